@@ -0,0 +1,124 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solana
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+)
+
+var ErrAccountDecoderNotFound = errors.New("account decoder not found")
+
+// AccountDecoder decodes the data of an account.
+type AccountDecoder func(data []byte) (interface{}, error)
+
+// AccountMatcher reports whether the data of an account has the layout
+// handled by an AccountDecoder; a nil AccountMatcher matches any data.
+type AccountMatcher func(data []byte) bool
+
+// AccountSizeMatcher matches the account data of exactly the provided size.
+func AccountSizeMatcher(size int) AccountMatcher {
+	return func(data []byte) bool {
+		return len(data) == size
+	}
+}
+
+// AccountDiscriminatorMatcher matches the account data
+// that starts with the provided discriminator.
+func AccountDiscriminatorMatcher(discriminator []byte) AccountMatcher {
+	discriminator = append([]byte(nil), discriminator...)
+	return func(data []byte) bool {
+		return bytes.HasPrefix(data, discriminator)
+	}
+}
+
+var accountDecoderRegistry = newAccountDecoderRegistry()
+
+type accountDecoderEntry struct {
+	matcher AccountMatcher
+	decoder AccountDecoder
+}
+
+type accountRegistry struct {
+	mu       *sync.RWMutex
+	decoders map[PublicKey][]accountDecoderEntry
+}
+
+func newAccountDecoderRegistry() *accountRegistry {
+	return &accountRegistry{
+		mu:       &sync.RWMutex{},
+		decoders: make(map[PublicKey][]accountDecoderEntry),
+	}
+}
+
+func (reg *accountRegistry) Has(owner PublicKey) bool {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	_, ok := reg.decoders[owner]
+	return ok
+}
+
+// Get returns the first decoder registered for the owner whose matcher matches the data.
+func (reg *accountRegistry) Get(owner PublicKey, data []byte) (AccountDecoder, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	for _, entry := range reg.decoders[owner] {
+		if entry.matcher == nil || entry.matcher(data) {
+			return entry.decoder, true
+		}
+	}
+	return nil, false
+}
+
+// Register adds the provided decoder for the provided owner;
+// returns false if the same decoder was already registered for the owner.
+func (reg *accountRegistry) Register(owner PublicKey, matcher AccountMatcher, decoder AccountDecoder) bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for _, entry := range reg.decoders[owner] {
+		if isSameFunction(entry.decoder, decoder) {
+			return false
+		}
+	}
+	reg.decoders[owner] = append(reg.decoders[owner], accountDecoderEntry{
+		matcher: matcher,
+		decoder: decoder,
+	})
+	return true
+}
+
+// RegisterAccountDecoder registers a decoder for the data of the accounts owned
+// by the provided program that match the provided matcher.
+// A program can register several decoders (e.g. one per account type);
+// they are tried in registration order. Registering the same decoder
+// twice for the same owner is a no-op.
+func RegisterAccountDecoder(owner PublicKey, matcher AccountMatcher, decoder AccountDecoder) {
+	accountDecoderRegistry.Register(owner, matcher, decoder)
+}
+
+// DecodeAccount decodes the data of an account owned by the provided program
+// with the matching registered decoder. The decoders of the programs under
+// programs/ are registered when their package is imported.
+func DecodeAccount(owner PublicKey, data []byte) (interface{}, error) {
+	decoder, found := accountDecoderRegistry.Get(owner, data)
+	if !found {
+		return nil, ErrAccountDecoderNotFound
+	}
+	return decoder(data)
+}
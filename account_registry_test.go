@@ -0,0 +1,50 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAccountDecoder(t *testing.T) {
+	owner := MustPublicKeyFromBase58("BPFLoader1111111111111111111111111111111111")
+
+	decodeSized := func(data []byte) (interface{}, error) {
+		return "sized", nil
+	}
+	decodeTagged := func(data []byte) (interface{}, error) {
+		return "tagged", nil
+	}
+	RegisterAccountDecoder(owner, AccountSizeMatcher(3), decodeSized)
+	RegisterAccountDecoder(owner, AccountDiscriminatorMatcher([]byte{7, 7}), decodeTagged)
+	// Registering the same decoder again is a no-op.
+	RegisterAccountDecoder(owner, nil, decodeSized)
+
+	got, err := DecodeAccount(owner, []byte{7, 7, 7})
+	require.NoError(t, err)
+	require.Equal(t, "sized", got)
+
+	got, err = DecodeAccount(owner, []byte{7, 7, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, "tagged", got)
+
+	_, err = DecodeAccount(owner, []byte{1, 2, 3, 4})
+	require.ErrorIs(t, err, ErrAccountDecoderNotFound)
+
+	_, err = DecodeAccount(NewWallet().PublicKey(), []byte{7, 7, 7})
+	require.ErrorIs(t, err, ErrAccountDecoderNotFound)
+}
@@ -0,0 +1,379 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var primitiveSizes = map[string]int{
+	TypeBool:   1,
+	TypeU8:     1,
+	TypeI8:     1,
+	TypeU16:    2,
+	TypeI16:    2,
+	TypeU32:    4,
+	TypeI32:    4,
+	TypeF32:    4,
+	TypeU64:    8,
+	TypeI64:    8,
+	TypeF64:    8,
+	TypeU128:   16,
+	TypeI128:   16,
+	TypeU256:   32,
+	TypeI256:   32,
+	TypePubkey: 32,
+}
+
+// maxDecodeDepth bounds the nesting of the decoded values,
+// guarding against (malicious) recursive types.
+const maxDecodeDepth = 64
+
+// genericScope maps the generics of a type definition to their arguments.
+type genericScope map[string]IDLGenericArg
+
+// Decode decodes a borsh-encoded value of the provided type.
+// The values are decoded as follows:
+//   - bool, u8...u64, i8...i64, f32, f64: the corresponding Go types;
+//   - u128, i128, u256, i256: *big.Int;
+//   - bytes: []byte; string: string; pubkey: solana.PublicKey;
+//   - option, coption: nil or the value;
+//   - vec, array, tuple structs: []interface{};
+//   - structs: *OrderedMap keyed by field name;
+//   - enums: *OrderedMap with the variant name as only key, and the
+//     variant fields (an empty *OrderedMap for unit variants) as value.
+func (idl *IDL) Decode(dec *bin.Decoder, typ IDLType) (interface{}, error) {
+	return idl.decode(dec, &typ, nil, 0)
+}
+
+// DecodeTypeDef decodes a borsh-encoded value of the
+// type definition with the provided name.
+func (idl *IDL) DecodeTypeDef(dec *bin.Decoder, name string) (interface{}, error) {
+	return idl.decode(dec, &IDLType{Defined: &IDLTypeDefined{Name: name}}, nil, 0)
+}
+
+func (idl *IDL) decode(dec *bin.Decoder, typ *IDLType, scope genericScope, depth int) (interface{}, error) {
+	if depth > maxDecodeDepth {
+		return nil, fmt.Errorf("max decoding depth exceeded")
+	}
+	switch {
+	case typ.Primitive != "":
+		return decodePrimitive(dec, typ.Primitive)
+	case typ.Option != nil:
+		has, err := dec.ReadOption()
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			return nil, nil
+		}
+		return idl.decode(dec, typ.Option, scope, depth+1)
+	case typ.COption != nil:
+		has, err := dec.ReadCOption()
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			// The space of fixed-size values is reserved.
+			if size, ok := idl.fixedSize(typ.COption, scope, depth+1); ok {
+				return nil, dec.SkipBytes(uint(size))
+			}
+			return nil, nil
+		}
+		return idl.decode(dec, typ.COption, scope, depth+1)
+	case typ.Vec != nil:
+		length, err := dec.ReadUint32(bin.LE)
+		if err != nil {
+			return nil, err
+		}
+		return idl.decodeSequence(dec, typ.Vec, int(length), scope, depth)
+	case typ.Array != nil:
+		length := typ.ArrayLen
+		if typ.ArrayLenGeneric != "" {
+			arg, ok := scope[typ.ArrayLenGeneric]
+			if !ok || arg.Kind != "const" {
+				return nil, fmt.Errorf("unknown const generic %s", typ.ArrayLenGeneric)
+			}
+			n, err := strconv.Atoi(arg.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid const generic %s: %w", typ.ArrayLenGeneric, err)
+			}
+			length = n
+		}
+		return idl.decodeSequence(dec, typ.Array, length, scope, depth)
+	case typ.Defined != nil:
+		return idl.decodeDefined(dec, typ.Defined, scope, depth+1)
+	case typ.Generic != "":
+		arg, ok := scope[typ.Generic]
+		if !ok || arg.Type == nil {
+			return nil, fmt.Errorf("unknown type generic %s", typ.Generic)
+		}
+		return idl.decode(dec, arg.Type, nil, depth+1)
+	default:
+		return nil, fmt.Errorf("invalid IDL type")
+	}
+}
+
+func (idl *IDL) decodeSequence(dec *bin.Decoder, elem *IDLType, length int, scope genericScope, depth int) (interface{}, error) {
+	if length < 0 || length > dec.Remaining() {
+		return nil, fmt.Errorf("length %d exceeds the remaining data", length)
+	}
+	out := make([]interface{}, length)
+	for i := range out {
+		value, err := idl.decode(dec, elem, scope, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		out[i] = value
+	}
+	return out, nil
+}
+
+func (idl *IDL) decodeDefined(dec *bin.Decoder, defined *IDLTypeDefined, scope genericScope, depth int) (interface{}, error) {
+	def, ok := idl.TypeDef(defined.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", defined.Name)
+	}
+	inner, err := resolveGenerics(def, defined.Generics, scope)
+	if err != nil {
+		return nil, err
+	}
+	switch def.Type.Kind {
+	case TypeDefKindStruct:
+		value, err := idl.decodeFields(dec, def.Type.Fields, inner, depth)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", def.Name, err)
+		}
+		return value, nil
+	case TypeDefKindEnum:
+		tag, err := dec.ReadUint8()
+		if err != nil {
+			return nil, err
+		}
+		if int(tag) >= len(def.Type.Variants) {
+			return nil, fmt.Errorf("%s: invalid variant %d", def.Name, tag)
+		}
+		variant := def.Type.Variants[tag]
+		fields, err := idl.decodeFields(dec, variant.Fields, inner, depth)
+		if err != nil {
+			return nil, fmt.Errorf("%s::%s: %w", def.Name, variant.Name, err)
+		}
+		out := NewOrderedMap()
+		out.Set(variant.Name, fields)
+		return out, nil
+	case TypeDefKindType:
+		if def.Type.Alias == nil {
+			return nil, fmt.Errorf("%s: alias type is not set", def.Name)
+		}
+		return idl.decode(dec, def.Type.Alias, inner, depth)
+	default:
+		return nil, fmt.Errorf("%s: unknown type kind %q", def.Name, def.Type.Kind)
+	}
+}
+
+// decodeFields decodes named fields into an *OrderedMap,
+// and tuple fields into a []interface{}.
+func (idl *IDL) decodeFields(dec *bin.Decoder, fields *IDLDefinedFields, scope genericScope, depth int) (interface{}, error) {
+	if fields != nil && fields.Tuple != nil {
+		out := make([]interface{}, len(fields.Tuple))
+		for i := range fields.Tuple {
+			value, err := idl.decode(dec, &fields.Tuple[i], scope, depth)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			out[i] = value
+		}
+		return out, nil
+	}
+	out := NewOrderedMap()
+	if fields == nil {
+		return out, nil
+	}
+	for i := range fields.Named {
+		field := &fields.Named[i]
+		value, err := idl.decode(dec, &field.Type, scope, depth)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+		out.Set(field.Name, value)
+	}
+	return out, nil
+}
+
+// resolveGenerics returns the scope of the provided type definition,
+// resolving the arguments that refer to generics of the enclosing scope.
+func resolveGenerics(def *IDLTypeDef, args []IDLGenericArg, outer genericScope) (genericScope, error) {
+	if len(def.Generics) == 0 {
+		return nil, nil
+	}
+	if len(args) != len(def.Generics) {
+		return nil, fmt.Errorf("%s: expected %d generic arguments, got %d", def.Name, len(def.Generics), len(args))
+	}
+	scope := make(genericScope, len(args))
+	for i, generic := range def.Generics {
+		arg := args[i]
+		if arg.Type != nil && arg.Type.Generic != "" {
+			resolved, ok := outer[arg.Type.Generic]
+			if !ok {
+				return nil, fmt.Errorf("unknown type generic %s", arg.Type.Generic)
+			}
+			arg = resolved
+		} else if arg.Kind == "const" {
+			if resolved, ok := outer[arg.Value]; ok {
+				arg = resolved
+			}
+		}
+		scope[generic.Name] = arg
+	}
+	return scope, nil
+}
+
+// fixedSize returns the encoded size of the values of the provided type,
+// if all of them have the same size.
+func (idl *IDL) fixedSize(typ *IDLType, scope genericScope, depth int) (int, bool) {
+	if depth > maxDecodeDepth {
+		return 0, false
+	}
+	switch {
+	case typ.Primitive != "":
+		size, ok := primitiveSizes[typ.Primitive]
+		return size, ok
+	case typ.COption != nil:
+		size, ok := idl.fixedSize(typ.COption, scope, depth+1)
+		return 4 + size, ok
+	case typ.Array != nil:
+		if typ.ArrayLenGeneric != "" {
+			return 0, false
+		}
+		size, ok := idl.fixedSize(typ.Array, scope, depth+1)
+		return size * typ.ArrayLen, ok
+	case typ.Defined != nil:
+		def, ok := idl.TypeDef(typ.Defined.Name)
+		if !ok || len(def.Generics) > 0 {
+			return 0, false
+		}
+		switch def.Type.Kind {
+		case TypeDefKindStruct:
+			return idl.fieldsFixedSize(def.Type.Fields, depth+1)
+		case TypeDefKindType:
+			if def.Type.Alias == nil {
+				return 0, false
+			}
+			return idl.fixedSize(def.Type.Alias, nil, depth+1)
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+}
+
+func (idl *IDL) fieldsFixedSize(fields *IDLDefinedFields, depth int) (int, bool) {
+	if fields == nil {
+		return 0, true
+	}
+	total := 0
+	types := fields.Tuple
+	for _, field := range fields.Named {
+		types = append(types, field.Type)
+	}
+	for i := range types {
+		size, ok := idl.fixedSize(&types[i], nil, depth)
+		if !ok {
+			return 0, false
+		}
+		total += size
+	}
+	return total, true
+}
+
+func decodePrimitive(dec *bin.Decoder, primitive string) (interface{}, error) {
+	switch primitive {
+	case TypeBool:
+		return dec.ReadBool()
+	case TypeU8:
+		return dec.ReadUint8()
+	case TypeI8:
+		return dec.ReadInt8()
+	case TypeU16:
+		return dec.ReadUint16(bin.LE)
+	case TypeI16:
+		return dec.ReadInt16(bin.LE)
+	case TypeU32:
+		return dec.ReadUint32(bin.LE)
+	case TypeI32:
+		return dec.ReadInt32(bin.LE)
+	case TypeF32:
+		return dec.ReadFloat32(bin.LE)
+	case TypeU64:
+		return dec.ReadUint64(bin.LE)
+	case TypeI64:
+		return dec.ReadInt64(bin.LE)
+	case TypeF64:
+		return dec.ReadFloat64(bin.LE)
+	case TypeU128, TypeU256, TypeI128, TypeI256:
+		buf, err := dec.ReadNBytes(primitiveSizes[primitive])
+		if err != nil {
+			return nil, err
+		}
+		return littleEndianBigInt(buf, primitive == TypeI128 || primitive == TypeI256), nil
+	case TypeBytes:
+		length, err := dec.ReadUint32(bin.LE)
+		if err != nil {
+			return nil, err
+		}
+		if int(length) > dec.Remaining() {
+			return nil, fmt.Errorf("length %d exceeds the remaining data", length)
+		}
+		return dec.ReadNBytes(int(length))
+	case TypeString:
+		length, err := dec.ReadUint32(bin.LE)
+		if err != nil {
+			return nil, err
+		}
+		if int(length) > dec.Remaining() {
+			return nil, fmt.Errorf("length %d exceeds the remaining data", length)
+		}
+		buf, err := dec.ReadNBytes(int(length))
+		if err != nil {
+			return nil, err
+		}
+		return string(buf), nil
+	case TypePubkey:
+		buf, err := dec.ReadNBytes(32)
+		if err != nil {
+			return nil, err
+		}
+		return solana.PublicKeyFromBytes(buf), nil
+	default:
+		return nil, fmt.Errorf("unknown primitive type %q", primitive)
+	}
+}
+
+func littleEndianBigInt(buf []byte, signed bool) *big.Int {
+	be := make([]byte, len(buf))
+	for i := range buf {
+		be[len(buf)-1-i] = buf[i]
+	}
+	out := new(big.Int).SetBytes(be)
+	if signed && len(be) > 0 && be[0]&0x80 != 0 {
+		out.Sub(out, new(big.Int).Lsh(big.NewInt(1), uint(len(buf)*8)))
+	}
+	return out
+}
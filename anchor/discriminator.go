@@ -0,0 +1,37 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	bin "github.com/gagliardetto/binary"
+)
+
+// InstructionDiscriminator returns the discriminator of the instruction
+// with the provided name: sha256("global:<snake_case name>")[:8].
+func InstructionDiscriminator(name string) Bytes {
+	return Bytes(bin.SighashInstruction(name))
+}
+
+// AccountDiscriminator returns the discriminator of the account
+// with the provided name: sha256("account:<PascalCase name>")[:8].
+func AccountDiscriminator(name string) Bytes {
+	return Bytes(bin.SighashAccount(name))
+}
+
+// EventDiscriminator returns the discriminator of the event
+// with the provided name: sha256("event:<Name>")[:8].
+func EventDiscriminator(name string) Bytes {
+	return Bytes(bin.Sighash("event", name))
+}
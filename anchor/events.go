@@ -0,0 +1,217 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programlog"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// EventIxTag prefixes the data of the self-invocations made by emit_cpi!
+// to emit an event (EVENT_IX_TAG_LE), followed by the event discriminator.
+var EventIxTag = []byte{228, 69, 165, 46, 81, 203, 154, 29}
+
+// EventDecoder decodes the data of an event, discriminator included,
+// returning its name and value; it returns ErrUnknownEvent (possibly
+// wrapped) if the data is not one of the events it decodes.
+type EventDecoder func(data []byte) (name string, event interface{}, err error)
+
+// Event is an event emitted by a program, either logged by emit!
+// ("Program data:") or through a self-invocation by emit_cpi!.
+type Event struct {
+	ProgramID solana.PublicKey
+	Name      string
+	// The decoded event: a *OrderedMap for the events decoded with an IDL,
+	// a pointer to the event struct for the ones registered with RegisterEvent.
+	Data interface{}
+	// Index of the top-level instruction during which the event was emitted.
+	InstructionIndex int
+	// Whether the event was emitted by emit_cpi!.
+	CPI bool
+	// The invocation that logged the event; nil for the events emitted by emit_cpi!.
+	Invocation *programlog.Invocation
+}
+
+// EventParser extracts the events of the programs it knows from the
+// logs and inner instructions of transactions.
+type EventParser struct {
+	decoders map[solana.PublicKey][]EventDecoder
+}
+
+func NewEventParser() *EventParser {
+	return &EventParser{
+		decoders: make(map[solana.PublicKey][]EventDecoder),
+	}
+}
+
+// RegisterDecoder registers a decoder of the events of the program;
+// the decoders of a program are tried in registration order.
+func (p *EventParser) RegisterDecoder(programID solana.PublicKey, decoder EventDecoder) {
+	p.decoders[programID] = append(p.decoders[programID], decoder)
+}
+
+// RegisterIDL registers the events of the IDL, emitted by the program at idl.Address.
+func (p *EventParser) RegisterIDL(idl *IDL) error {
+	if idl.Address.IsZero() {
+		return fmt.Errorf("IDL %q has no address", idl.Metadata.Name)
+	}
+	p.RegisterIDLWithProgramID(idl.Address, idl)
+	return nil
+}
+
+// RegisterIDLWithProgramID is like RegisterIDL, for a program deployed at
+// another address than the one of the IDL.
+func (p *EventParser) RegisterIDLWithProgramID(programID solana.PublicKey, idl *IDL) {
+	p.RegisterDecoder(programID, func(data []byte) (string, interface{}, error) {
+		event, err := idl.DecodeEvent(data)
+		if err != nil {
+			return "", nil, err
+		}
+		return event.Name, event.Data, nil
+	})
+}
+
+// RegisterEvent registers the event type T of the program, named after the
+// Rust struct of the event, which gives its discriminator; the events
+// are Borsh-decoded into a *T.
+func RegisterEvent[T any](p *EventParser, programID solana.PublicKey, name string) {
+	discriminator := EventDiscriminator(name)
+	p.RegisterDecoder(programID, func(data []byte) (string, interface{}, error) {
+		if !bytes.HasPrefix(data, discriminator) {
+			return "", nil, ErrUnknownEvent
+		}
+		event := new(T)
+		if err := bin.NewBorshDecoder(data[len(discriminator):]).Decode(event); err != nil {
+			return "", nil, fmt.Errorf("unable to decode event %s: %w", name, err)
+		}
+		return name, event, nil
+	})
+}
+
+// decode decodes an event of the program; it returns a nil event if the
+// program or the event is unknown.
+func (p *EventParser) decode(programID solana.PublicKey, data []byte) (*Event, error) {
+	for _, decoder := range p.decoders[programID] {
+		name, value, err := decoder(data)
+		if errors.Is(err, ErrUnknownEvent) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Event{
+			ProgramID: programID,
+			Name:      name,
+			Data:      value,
+		}, nil
+	}
+	return nil, nil
+}
+
+// ParseLogs extracts the events logged by emit! from the log messages of
+// a transaction (e.g. the Logs of a ws LogResult), ordered by instruction
+// and then depth-first, the events of an invocation preceding the ones of
+// its inner invocations.
+func (p *EventParser) ParseLogs(logs []string) ([]*Event, error) {
+	tree, err := programlog.Parse(logs)
+	if err != nil {
+		return nil, err
+	}
+	var out []*Event
+	for index, top := range tree.Invocations {
+		events, err := p.logEvents(index, top)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, events...)
+	}
+	return out, nil
+}
+
+// ParseTransaction extracts the events emitted by emit! and emit_cpi! during
+// the execution of the transaction. The events are ordered by instruction;
+// within an instruction, the logged events precede the ones emitted by emit_cpi!.
+func (p *EventParser) ParseTransaction(tx *solana.Transaction, meta *rpc.TransactionMeta) ([]*Event, error) {
+	tree, err := programlog.Parse(meta.LogMessages)
+	if err != nil {
+		return nil, err
+	}
+	keys := append(solana.PublicKeySlice{}, tx.Message.AccountKeys...)
+	keys = append(keys, meta.LoadedAddresses.Writable...)
+	keys = append(keys, meta.LoadedAddresses.ReadOnly...)
+
+	var out []*Event
+	for index := range tx.Message.Instructions {
+		if index < len(tree.Invocations) {
+			events, err := p.logEvents(index, tree.Invocations[index])
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, events...)
+		}
+		for _, inner := range meta.InnerInstructions {
+			if int(inner.Index) != index {
+				continue
+			}
+			for _, inst := range inner.Instructions {
+				if int(inst.ProgramIDIndex) >= len(keys) || !bytes.HasPrefix(inst.Data, EventIxTag) {
+					continue
+				}
+				event, err := p.decode(keys[inst.ProgramIDIndex], inst.Data[len(EventIxTag):])
+				if err != nil {
+					return nil, err
+				}
+				if event != nil {
+					event.InstructionIndex = index
+					event.CPI = true
+					out = append(out, event)
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// logEvents returns the events logged during the top-level invocation, depth-first:
+// the events of an invocation precede the ones of its inner invocations.
+func (p *EventParser) logEvents(index int, top *programlog.Invocation) ([]*Event, error) {
+	var out []*Event
+	var walkErr error
+	tree := &programlog.Tree{Invocations: []*programlog.Invocation{top}}
+	tree.Walk(func(inv *programlog.Invocation) bool {
+		for _, fields := range inv.Data {
+			for _, data := range fields {
+				event, err := p.decode(inv.ProgramID, data)
+				if err != nil {
+					walkErr = err
+					return false
+				}
+				if event != nil {
+					event.InstructionIndex = index
+					event.Invocation = inv
+					out = append(out, event)
+				}
+			}
+		}
+		return true
+	})
+	return out, walkErr
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"encoding/base64"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type incremented struct {
+	Count uint64
+	Delta *uint32 `bin:"coption"`
+}
+
+func incrementedEvent(t *testing.T, count uint64) []byte {
+	return encodeBorsh(t, func(enc *bin.Encoder) {
+		enc.WriteBytes(EventDiscriminator("Incremented"), false)
+		enc.WriteUint64(count, bin.LE)
+		enc.WriteUint32(1, bin.LE)
+		enc.WriteUint32(3, bin.LE)
+	})
+}
+
+func TestEventParser_ParseLogs(t *testing.T) {
+	idl := loadIDL(t, "counter.json")
+	other := solana.NewWallet().PublicKey()
+
+	parser := NewEventParser()
+	require.NoError(t, parser.RegisterIDL(idl))
+	RegisterEvent[incremented](parser, other, "Incremented")
+
+	data := func(count uint64) string {
+		return "Program data: " + base64.StdEncoding.EncodeToString(incrementedEvent(t, count))
+	}
+	events, err := parser.ParseLogs([]string{
+		"Program " + idl.Address.String() + " invoke [1]",
+		data(1),
+		"Program " + other.String() + " invoke [2]",
+		data(2),
+		"Program data: AAAAAAAAAAA=",
+		"Program " + other.String() + " success",
+		data(3),
+		"Program " + idl.Address.String() + " success",
+		"Program " + solana.SystemProgramID.String() + " invoke [1]",
+		data(4),
+		"Program " + solana.SystemProgramID.String() + " success",
+		"Program " + other.String() + " invoke [1]",
+		data(5),
+		"Program " + other.String() + " success",
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	for i, count := range []uint64{1, 3, 2, 5} {
+		require.Equal(t, "Incremented", events[i].Name)
+		require.False(t, events[i].CPI)
+		require.NotNil(t, events[i].Invocation)
+		require.Equal(t, events[i].ProgramID, events[i].Invocation.ProgramID)
+		switch data := events[i].Data.(type) {
+		case *OrderedMap:
+			require.Equal(t, idl.Address, events[i].ProgramID)
+			value, _ := data.Get("count")
+			require.EqualValues(t, count, value)
+		case *incremented:
+			require.Equal(t, other, events[i].ProgramID)
+			require.Equal(t, count, data.Count)
+			require.Equal(t, uint32(3), *data.Delta)
+		default:
+			t.Fatalf("unexpected event type %T", data)
+		}
+	}
+	require.Equal(t, []int{0, 0, 0, 2}, []int{events[0].InstructionIndex, events[1].InstructionIndex, events[2].InstructionIndex, events[3].InstructionIndex})
+
+	_, err = parser.ParseLogs([]string{
+		"Program " + other.String() + " invoke [1]",
+		"Program data: " + base64.StdEncoding.EncodeToString(EventDiscriminator("Incremented")),
+	})
+	require.Error(t, err)
+
+	require.Error(t, NewEventParser().RegisterIDL(&IDL{}))
+}
+
+func TestEventParser_ParseTransaction(t *testing.T) {
+	idl := loadIDL(t, "counter.json")
+	payer := solana.NewWallet().PublicKey()
+	authority := solana.NewWallet().PublicKey()
+
+	parser := NewEventParser()
+	require.NoError(t, parser.RegisterIDL(idl))
+
+	tx := &solana.Transaction{
+		Message: solana.Message{
+			AccountKeys: solana.PublicKeySlice{payer, idl.Address},
+			Instructions: []solana.CompiledInstruction{
+				{ProgramIDIndex: 1},
+				{ProgramIDIndex: 1},
+			},
+		},
+	}
+	cpi := func(count uint64) rpc.CompiledInstruction {
+		return rpc.CompiledInstruction{
+			ProgramIDIndex: 1,
+			Accounts:       []uint16{2},
+			Data:           append(append([]byte{}, EventIxTag...), incrementedEvent(t, count)...),
+			StackHeight:    2,
+		}
+	}
+	meta := &rpc.TransactionMeta{
+		LogMessages: []string{
+			"Program " + idl.Address.String() + " invoke [1]",
+			"Program " + idl.Address.String() + " invoke [2]",
+			"Program " + idl.Address.String() + " success",
+			"Program " + idl.Address.String() + " success",
+			"Program " + idl.Address.String() + " invoke [1]",
+			"Program data: " + base64.StdEncoding.EncodeToString(incrementedEvent(t, 2)),
+			"Program " + idl.Address.String() + " invoke [2]",
+			"Program " + idl.Address.String() + " success",
+			"Program " + idl.Address.String() + " success",
+		},
+		InnerInstructions: []rpc.InnerInstruction{
+			{Index: 0, Instructions: []rpc.CompiledInstruction{cpi(1)}},
+			{Index: 1, Instructions: []rpc.CompiledInstruction{cpi(3)}},
+		},
+		LoadedAddresses: rpc.LoadedAddresses{
+			ReadOnly: solana.PublicKeySlice{authority},
+		},
+	}
+
+	events, err := parser.ParseTransaction(tx, meta)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for i, expected := range []struct {
+		count uint64
+		index int
+		cpi   bool
+	}{{1, 0, true}, {2, 1, false}, {3, 1, true}} {
+		require.Equal(t, idl.Address, events[i].ProgramID)
+		require.Equal(t, expected.index, events[i].InstructionIndex)
+		require.Equal(t, expected.cpi, events[i].CPI)
+		require.Equal(t, expected.cpi, events[i].Invocation == nil)
+		value, _ := events[i].Data.(*OrderedMap).Get("count")
+		require.EqualValues(t, expected.count, value)
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anchor parses Anchor IDLs and decodes the instructions,
+// accounts and events of Anchor programs without generated code.
+package anchor
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// IDL is an Anchor IDL. Both the legacy IDLs and the ones following the
+// 0.30+ specification are parsed into this form, which mirrors the latter.
+type IDL struct {
+	Address      solana.PublicKey `json:"address"`
+	Metadata     IDLMetadata      `json:"metadata"`
+	Docs         []string         `json:"docs,omitempty"`
+	Instructions []IDLInstruction `json:"instructions"`
+	Accounts     []IDLAccount     `json:"accounts,omitempty"`
+	Events       []IDLEvent       `json:"events,omitempty"`
+	Errors       []IDLErrorCode   `json:"errors,omitempty"`
+	Types        []IDLTypeDef     `json:"types,omitempty"`
+	Constants    []IDLConst       `json:"constants,omitempty"`
+
+	indexOnce sync.Once
+	types     map[string]*IDLTypeDef
+}
+
+type IDLMetadata struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Spec        string `json:"spec"`
+	Description string `json:"description,omitempty"`
+}
+
+type IDLInstruction struct {
+	Name          string                  `json:"name"`
+	Docs          []string                `json:"docs,omitempty"`
+	Discriminator Bytes                   `json:"discriminator"`
+	Accounts      []IDLInstructionAccount `json:"accounts"`
+	Args          []IDLField              `json:"args"`
+	Returns       *IDLType                `json:"returns,omitempty"`
+}
+
+// IDLInstructionAccount is an account of an instruction,
+// or a group of accounts (composite accounts) if Accounts is set.
+type IDLInstructionAccount struct {
+	Name      string   `json:"name"`
+	Docs      []string `json:"docs,omitempty"`
+	Writable  bool     `json:"writable,omitempty"`
+	Signer    bool     `json:"signer,omitempty"`
+	Optional  bool     `json:"optional,omitempty"`
+	Address   string   `json:"address,omitempty"`
+	PDA       *IDLPDA  `json:"pda,omitempty"`
+	Relations []string `json:"relations,omitempty"`
+
+	Accounts []IDLInstructionAccount `json:"accounts,omitempty"`
+}
+
+type IDLPDA struct {
+	Seeds   []IDLSeed `json:"seeds"`
+	Program *IDLSeed  `json:"program,omitempty"`
+}
+
+// IDLSeed is a PDA seed: a constant (Value), or the value of
+// an instruction argument or account (Path).
+type IDLSeed struct {
+	Kind    string `json:"kind"` // "const", "arg" or "account"
+	Value   Bytes  `json:"value,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Account string `json:"account,omitempty"`
+}
+
+type IDLAccount struct {
+	Name          string `json:"name"`
+	Discriminator Bytes  `json:"discriminator"`
+}
+
+type IDLEvent struct {
+	Name          string `json:"name"`
+	Discriminator Bytes  `json:"discriminator"`
+}
+
+type IDLErrorCode struct {
+	Code uint32 `json:"code"`
+	Name string `json:"name"`
+	Msg  string `json:"msg,omitempty"`
+}
+
+type IDLConst struct {
+	Name  string   `json:"name"`
+	Docs  []string `json:"docs,omitempty"`
+	Type  IDLType  `json:"type"`
+	Value string   `json:"value"`
+}
+
+type IDLField struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs,omitempty"`
+	Type IDLType  `json:"type"`
+}
+
+type IDLTypeDef struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs,omitempty"`
+	// "borsh" (the default), "bytemuck", "bytemuckunsafe" or a custom one.
+	Serialization string              `json:"serialization,omitempty"`
+	Repr          *IDLRepr            `json:"repr,omitempty"`
+	Generics      []IDLTypeDefGeneric `json:"generics,omitempty"`
+	Type          IDLTypeDefTy        `json:"type"`
+}
+
+type IDLRepr struct {
+	Kind   string `json:"kind"` // "rust", "c" or "transparent"
+	Packed bool   `json:"packed,omitempty"`
+	Align  *int   `json:"align,omitempty"`
+}
+
+type IDLTypeDefGeneric struct {
+	Kind string `json:"kind"` // "type" or "const"
+	Name string `json:"name"`
+	// Type of the const generics.
+	Type string `json:"type,omitempty"`
+}
+
+const (
+	TypeDefKindStruct = "struct"
+	TypeDefKindEnum   = "enum"
+	TypeDefKindType   = "type"
+)
+
+type IDLTypeDefTy struct {
+	Kind string `json:"kind"`
+	// Set for the structs.
+	Fields *IDLDefinedFields `json:"fields,omitempty"`
+	// Set for the enums.
+	Variants []IDLEnumVariant `json:"variants,omitempty"`
+	// Set for the type aliases.
+	Alias *IDLType `json:"alias,omitempty"`
+}
+
+type IDLEnumVariant struct {
+	Name   string            `json:"name"`
+	Fields *IDLDefinedFields `json:"fields,omitempty"`
+}
+
+// ParseIDL parses an Anchor IDL, either legacy or following the 0.30+ specification.
+// The discriminators missing from a legacy IDL are computed.
+func ParseIDL(data []byte) (*IDL, error) {
+	var probe struct {
+		Metadata struct {
+			Spec string `json:"spec"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("unable to parse IDL: %w", err)
+	}
+	if probe.Metadata.Spec == "" {
+		return parseLegacyIDL(data)
+	}
+	idl := new(IDL)
+	if err := json.Unmarshal(data, idl); err != nil {
+		return nil, fmt.Errorf("unable to parse IDL: %w", err)
+	}
+	idl.fillDiscriminators()
+	return idl, nil
+}
+
+func (idl *IDL) fillDiscriminators() {
+	for i := range idl.Instructions {
+		if len(idl.Instructions[i].Discriminator) == 0 {
+			idl.Instructions[i].Discriminator = InstructionDiscriminator(idl.Instructions[i].Name)
+		}
+	}
+	for i := range idl.Accounts {
+		if len(idl.Accounts[i].Discriminator) == 0 {
+			idl.Accounts[i].Discriminator = AccountDiscriminator(idl.Accounts[i].Name)
+		}
+	}
+	for i := range idl.Events {
+		if len(idl.Events[i].Discriminator) == 0 {
+			idl.Events[i].Discriminator = EventDiscriminator(idl.Events[i].Name)
+		}
+	}
+}
+
+func (idl *IDL) index() {
+	idl.indexOnce.Do(func() {
+		idl.types = make(map[string]*IDLTypeDef, len(idl.Types))
+		for i := range idl.Types {
+			idl.types[idl.Types[i].Name] = &idl.Types[i]
+		}
+	})
+}
+
+// TypeDef returns the type definition with the provided name.
+func (idl *IDL) TypeDef(name string) (*IDLTypeDef, bool) {
+	idl.index()
+	def, ok := idl.types[name]
+	return def, ok
+}
+
+// Instruction returns the instruction whose discriminator prefixes the provided data.
+func (idl *IDL) Instruction(data []byte) (*IDLInstruction, bool) {
+	for i := range idl.Instructions {
+		if hasDiscriminator(data, idl.Instructions[i].Discriminator) {
+			return &idl.Instructions[i], true
+		}
+	}
+	return nil, false
+}
+
+// Account returns the account whose discriminator prefixes the provided data.
+func (idl *IDL) Account(data []byte) (*IDLAccount, bool) {
+	for i := range idl.Accounts {
+		if hasDiscriminator(data, idl.Accounts[i].Discriminator) {
+			return &idl.Accounts[i], true
+		}
+	}
+	return nil, false
+}
+
+// Event returns the event whose discriminator prefixes the provided data.
+func (idl *IDL) Event(data []byte) (*IDLEvent, bool) {
+	for i := range idl.Events {
+		if hasDiscriminator(data, idl.Events[i].Discriminator) {
+			return &idl.Events[i], true
+		}
+	}
+	return nil, false
+}
+
+// Error returns the error with the provided custom program error code.
+func (idl *IDL) Error(code uint32) (*IDLErrorCode, bool) {
+	for i := range idl.Errors {
+		if idl.Errors[i].Code == code {
+			return &idl.Errors[i], true
+		}
+	}
+	return nil, false
+}
+
+func hasDiscriminator(data []byte, discriminator Bytes) bool {
+	return len(discriminator) > 0 && bytes.HasPrefix(data, discriminator)
+}
+
+// FlattenAccounts returns the accounts of the instruction in the order
+// they are passed to it, expanding the composite accounts; the names of
+// the nested accounts are prefixed by the name of their group ("group.name").
+func (inst *IDLInstruction) FlattenAccounts() []IDLInstructionAccount {
+	return flattenAccounts("", inst.Accounts, nil)
+}
+
+func flattenAccounts(prefix string, accounts []IDLInstructionAccount, out []IDLInstructionAccount) []IDLInstructionAccount {
+	for _, account := range accounts {
+		if account.Accounts != nil {
+			out = flattenAccounts(prefix+account.Name+".", account.Accounts, out)
+			continue
+		}
+		account.Name = prefix + account.Name
+		out = append(out, account)
+	}
+	return out
+}
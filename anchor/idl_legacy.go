@@ -0,0 +1,301 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"encoding/binary"
+	stdjson "encoding/json"
+	"fmt"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// The IDLs produced before Anchor 0.30.
+type legacyIDL struct {
+	Version      string              `json:"version"`
+	Name         string              `json:"name"`
+	Docs         []string            `json:"docs"`
+	Instructions []legacyInstruction `json:"instructions"`
+	Accounts     []legacyTypeDef     `json:"accounts"`
+	Types        []legacyTypeDef     `json:"types"`
+	Events       []legacyEvent       `json:"events"`
+	Errors       []IDLErrorCode      `json:"errors"`
+	Constants    []IDLConst          `json:"constants"`
+	Metadata     struct {
+		Address string `json:"address"`
+	} `json:"metadata"`
+}
+
+type legacyInstruction struct {
+	Name     string          `json:"name"`
+	Docs     []string        `json:"docs"`
+	Accounts []legacyAccount `json:"accounts"`
+	Args     []IDLField      `json:"args"`
+	Returns  *IDLType        `json:"returns"`
+}
+
+type legacyAccount struct {
+	Name       string          `json:"name"`
+	Docs       []string        `json:"docs"`
+	IsMut      bool            `json:"isMut"`
+	IsSigner   bool            `json:"isSigner"`
+	IsOptional bool            `json:"isOptional"`
+	PDA        *legacyPDA      `json:"pda"`
+	Relations  []string        `json:"relations"`
+	Accounts   []legacyAccount `json:"accounts"`
+}
+
+type legacyPDA struct {
+	Seeds     []legacySeed `json:"seeds"`
+	ProgramID *legacySeed  `json:"programId"`
+}
+
+type legacySeed struct {
+	Kind    string             `json:"kind"`
+	Type    IDLType            `json:"type"`
+	Value   stdjson.RawMessage `json:"value"`
+	Path    string             `json:"path"`
+	Account string             `json:"account"`
+}
+
+type legacyTypeDef struct {
+	Name string       `json:"name"`
+	Docs []string     `json:"docs"`
+	Type IDLTypeDefTy `json:"type"`
+}
+
+type legacyEvent struct {
+	Name   string `json:"name"`
+	Fields []struct {
+		Name  string  `json:"name"`
+		Type  IDLType `json:"type"`
+		Index bool    `json:"index"`
+	} `json:"fields"`
+}
+
+// parseLegacyIDL converts a legacy IDL: the instruction, argument,
+// account and field names are converted to snake_case, the types of
+// the accounts and events are moved to the types, and the
+// discriminators are computed.
+func parseLegacyIDL(data []byte) (*IDL, error) {
+	var legacy legacyIDL
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, fmt.Errorf("unable to parse legacy IDL: %w", err)
+	}
+
+	idl := &IDL{
+		Metadata: IDLMetadata{
+			Name:    legacy.Name,
+			Version: legacy.Version,
+		},
+		Docs:      legacy.Docs,
+		Errors:    legacy.Errors,
+		Constants: legacy.Constants,
+	}
+	if legacy.Metadata.Address != "" {
+		address, err := solana.PublicKeyFromBase58(legacy.Metadata.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IDL address: %w", err)
+		}
+		idl.Address = address
+	}
+
+	for _, inst := range legacy.Instructions {
+		accounts, err := convertLegacyAccounts(inst.Accounts)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %s: %w", inst.Name, err)
+		}
+		name := bin.ToSnakeForSighash(inst.Name)
+		idl.Instructions = append(idl.Instructions, IDLInstruction{
+			Name:          name,
+			Docs:          inst.Docs,
+			Discriminator: InstructionDiscriminator(name),
+			Accounts:      accounts,
+			Args:          convertLegacyFields(inst.Args),
+			Returns:       inst.Returns,
+		})
+	}
+	for _, account := range legacy.Accounts {
+		idl.Accounts = append(idl.Accounts, IDLAccount{
+			Name:          account.Name,
+			Discriminator: AccountDiscriminator(account.Name),
+		})
+		idl.Types = append(idl.Types, convertLegacyTypeDef(account))
+	}
+	for _, event := range legacy.Events {
+		idl.Events = append(idl.Events, IDLEvent{
+			Name:          event.Name,
+			Discriminator: EventDiscriminator(event.Name),
+		})
+		fields := make([]IDLField, 0, len(event.Fields))
+		for _, field := range event.Fields {
+			fields = append(fields, IDLField{
+				Name: bin.ToSnakeForSighash(field.Name),
+				Type: field.Type,
+			})
+		}
+		idl.Types = append(idl.Types, IDLTypeDef{
+			Name: event.Name,
+			Type: IDLTypeDefTy{
+				Kind:   TypeDefKindStruct,
+				Fields: &IDLDefinedFields{Named: fields},
+			},
+		})
+	}
+	for _, def := range legacy.Types {
+		idl.Types = append(idl.Types, convertLegacyTypeDef(def))
+	}
+	return idl, nil
+}
+
+func convertLegacyAccounts(accounts []legacyAccount) ([]IDLInstructionAccount, error) {
+	out := make([]IDLInstructionAccount, 0, len(accounts))
+	for _, account := range accounts {
+		converted := IDLInstructionAccount{
+			Name:     bin.ToSnakeForSighash(account.Name),
+			Docs:     account.Docs,
+			Writable: account.IsMut,
+			Signer:   account.IsSigner,
+			Optional: account.IsOptional,
+		}
+		for _, relation := range account.Relations {
+			converted.Relations = append(converted.Relations, bin.ToSnakeForSighash(relation))
+		}
+		if account.Accounts != nil {
+			nested, err := convertLegacyAccounts(account.Accounts)
+			if err != nil {
+				return nil, err
+			}
+			converted.Accounts = nested
+		}
+		if account.PDA != nil {
+			pda := &IDLPDA{}
+			for _, seed := range account.PDA.Seeds {
+				converted, err := convertLegacySeed(seed)
+				if err != nil {
+					return nil, fmt.Errorf("account %s: %w", account.Name, err)
+				}
+				pda.Seeds = append(pda.Seeds, converted)
+			}
+			if account.PDA.ProgramID != nil {
+				program, err := convertLegacySeed(*account.PDA.ProgramID)
+				if err != nil {
+					return nil, fmt.Errorf("account %s: %w", account.Name, err)
+				}
+				pda.Program = &program
+			}
+			converted.PDA = pda
+		}
+		out = append(out, converted)
+	}
+	return out, nil
+}
+
+func convertLegacySeed(seed legacySeed) (IDLSeed, error) {
+	if seed.Kind != "const" {
+		return IDLSeed{
+			Kind:    seed.Kind,
+			Path:    snakeCasePath(seed.Path),
+			Account: seed.Account,
+		}, nil
+	}
+	value, err := legacyConstBytes(seed.Type, seed.Value)
+	if err != nil {
+		return IDLSeed{}, fmt.Errorf("invalid const seed %s: %w", seed.Value, err)
+	}
+	return IDLSeed{Kind: "const", Value: value}, nil
+}
+
+// legacyConstBytes returns the bytes of a constant seed of a legacy IDL,
+// which are given as a value of the seed type.
+func legacyConstBytes(typ IDLType, value stdjson.RawMessage) (Bytes, error) {
+	switch typ.Primitive {
+	case TypeString:
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, err
+		}
+		return Bytes(s), nil
+	case TypePubkey:
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, err
+		}
+		key, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return Bytes(key[:]), nil
+	case TypeU8, TypeI8, TypeU16, TypeI16, TypeU32, TypeI32, TypeU64, TypeI64:
+		var n int64
+		if err := json.Unmarshal(value, &n); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, uint64(n))
+		return Bytes(buf[:primitiveSizes[typ.Primitive]]), nil
+	}
+	var b Bytes
+	if err := json.Unmarshal(value, &b); err != nil {
+		return nil, fmt.Errorf("unsupported seed type %s", typ)
+	}
+	return b, nil
+}
+
+func snakeCasePath(path string) string {
+	parts := strings.Split(path, ".")
+	for i := range parts {
+		parts[i] = bin.ToSnakeForSighash(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
+
+func convertLegacyTypeDef(def legacyTypeDef) IDLTypeDef {
+	ty := def.Type
+	ty.Fields = convertLegacyDefinedFields(ty.Fields)
+	if ty.Variants != nil {
+		variants := make([]IDLEnumVariant, len(ty.Variants))
+		for i, variant := range ty.Variants {
+			variants[i] = IDLEnumVariant{
+				Name:   variant.Name,
+				Fields: convertLegacyDefinedFields(variant.Fields),
+			}
+		}
+		ty.Variants = variants
+	}
+	return IDLTypeDef{
+		Name: def.Name,
+		Docs: def.Docs,
+		Type: ty,
+	}
+}
+
+func convertLegacyDefinedFields(fields *IDLDefinedFields) *IDLDefinedFields {
+	if fields == nil || fields.Named == nil {
+		return fields
+	}
+	return &IDLDefinedFields{Named: convertLegacyFields(fields.Named)}
+}
+
+func convertLegacyFields(fields []IDLField) []IDLField {
+	out := make([]IDLField, len(fields))
+	for i, field := range fields {
+		field.Name = bin.ToSnakeForSighash(field.Name)
+		out[i] = field
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func loadIDL(t *testing.T, name string) *IDL {
+	data, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+	idl, err := ParseIDL(data)
+	require.NoError(t, err)
+	return idl
+}
+
+func TestParseIDL(t *testing.T) {
+	for _, name := range []string{"counter.json", "counter_legacy.json"} {
+		t.Run(name, func(t *testing.T) {
+			idl := loadIDL(t, name)
+			require.Equal(t, solana.MustPublicKeyFromBase58("Cntr1111111111111111111111111111111111111111"), idl.Address)
+			require.Equal(t, "counter", idl.Metadata.Name)
+
+			require.Len(t, idl.Instructions, 2)
+			require.Equal(t, "initialize", idl.Instructions[0].Name)
+			require.Equal(t, Bytes{175, 175, 109, 31, 13, 152, 155, 237}, idl.Instructions[0].Discriminator)
+			require.Equal(t, InstructionDiscriminator("increment"), idl.Instructions[1].Discriminator)
+			require.Equal(t, AccountDiscriminator("Counter"), idl.Accounts[0].Discriminator)
+			require.Equal(t, EventDiscriminator("Incremented"), idl.Events[0].Discriminator)
+
+			accounts := idl.Instructions[0].FlattenAccounts()
+			require.Len(t, accounts, 3)
+			require.Equal(t, "system.system_program", accounts[2].Name)
+			require.True(t, accounts[1].Writable)
+			require.True(t, accounts[1].Signer)
+			require.Equal(t, Bytes("counter"), accounts[0].PDA.Seeds[0].Value)
+			require.Equal(t, "authority", accounts[0].PDA.Seeds[1].Path)
+
+			mode, ok := idl.TypeDef("Mode")
+			require.True(t, ok)
+			require.Equal(t, TypeDefKindEnum, mode.Type.Kind)
+			require.Len(t, mode.Type.Variants[1].Fields.Named, 1)
+			require.Len(t, mode.Type.Variants[2].Fields.Tuple, 2)
+
+			errCode, ok := idl.Error(6000)
+			require.True(t, ok)
+			require.Equal(t, "Overflow", errCode.Name)
+		})
+	}
+}
+
+func TestIDLType_JSON(t *testing.T) {
+	for _, in := range []string{
+		`"pubkey"`,
+		`{"option":{"vec":"u8"}}`,
+		`{"array":[{"defined":{"name":"Foo"}},4]}`,
+		`{"array":["u8",{"generic":"N"}]}`,
+		`{"defined":{"name":"Ring","generics":[{"kind":"type","type":"u64"},{"kind":"const","value":"3"}]}}`,
+	} {
+		var typ IDLType
+		require.NoError(t, json.Unmarshal([]byte(in), &typ))
+		out, err := json.Marshal(typ)
+		require.NoError(t, err)
+		require.JSONEq(t, in, string(out))
+	}
+
+	var legacy IDLType
+	require.NoError(t, json.Unmarshal([]byte(`{"vec":{"defined":"Foo"}}`), &legacy))
+	require.Equal(t, "Foo", legacy.Vec.Defined.Name)
+	require.NoError(t, json.Unmarshal([]byte(`"publicKey"`), &legacy))
+	require.Equal(t, TypePubkey, legacy.Primitive)
+}
+
+func TestInstructionDiscriminator(t *testing.T) {
+	require.Equal(t, Bytes{175, 175, 109, 31, 13, 152, 155, 237}, InstructionDiscriminator("initialize"))
+	// The names are converted to snake_case, like in the legacy IDLs:
+	require.Equal(t, InstructionDiscriminator("initialize_mint2"), InstructionDiscriminator("initializeMint2"))
+	require.Equal(t, InstructionDiscriminator("create_ata"), InstructionDiscriminator("createATA"))
+}
+
+func encodeBorsh(t *testing.T, fn func(enc *bin.Encoder)) []byte {
+	buf := new(bytes.Buffer)
+	fn(bin.NewBorshEncoder(buf))
+	return buf.Bytes()
+}
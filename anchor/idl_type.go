@@ -0,0 +1,284 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+)
+
+// The primitive IDL types.
+const (
+	TypeBool   = "bool"
+	TypeU8     = "u8"
+	TypeI8     = "i8"
+	TypeU16    = "u16"
+	TypeI16    = "i16"
+	TypeU32    = "u32"
+	TypeI32    = "i32"
+	TypeF32    = "f32"
+	TypeU64    = "u64"
+	TypeI64    = "i64"
+	TypeF64    = "f64"
+	TypeU128   = "u128"
+	TypeI128   = "i128"
+	TypeU256   = "u256"
+	TypeI256   = "i256"
+	TypeBytes  = "bytes"
+	TypeString = "string"
+	TypePubkey = "pubkey"
+)
+
+// IDLType is a type expression; exactly one of its fields is set
+// (ArrayLen and ArrayLenGeneric go with Array).
+type IDLType struct {
+	// One of the Type* constants.
+	Primitive string
+
+	Option  *IDLType
+	COption *IDLType
+	Vec     *IDLType
+
+	Array *IDLType
+	// Length of Array, unless set by the const generic ArrayLenGeneric.
+	ArrayLen        int
+	ArrayLenGeneric string
+
+	Defined *IDLTypeDefined
+	// Name of a type generic of the enclosing type definition.
+	Generic string
+}
+
+type IDLTypeDefined struct {
+	Name     string          `json:"name"`
+	Generics []IDLGenericArg `json:"generics,omitempty"`
+}
+
+type IDLGenericArg struct {
+	Kind string `json:"kind"` // "type" or "const"
+	// Set for the type generics.
+	Type *IDLType `json:"type,omitempty"`
+	// Set for the const generics.
+	Value string `json:"value,omitempty"`
+}
+
+func (typ IDLType) String() string {
+	switch {
+	case typ.Primitive != "":
+		return typ.Primitive
+	case typ.Option != nil:
+		return "Option<" + typ.Option.String() + ">"
+	case typ.COption != nil:
+		return "COption<" + typ.COption.String() + ">"
+	case typ.Vec != nil:
+		return "Vec<" + typ.Vec.String() + ">"
+	case typ.Array != nil:
+		if typ.ArrayLenGeneric != "" {
+			return fmt.Sprintf("[%s; %s]", typ.Array, typ.ArrayLenGeneric)
+		}
+		return fmt.Sprintf("[%s; %d]", typ.Array, typ.ArrayLen)
+	case typ.Defined != nil:
+		return typ.Defined.Name
+	case typ.Generic != "":
+		return typ.Generic
+	default:
+		return "<invalid>"
+	}
+}
+
+func (typ IDLType) MarshalJSON() ([]byte, error) {
+	switch {
+	case typ.Primitive != "":
+		return json.Marshal(typ.Primitive)
+	case typ.Option != nil:
+		return json.Marshal(map[string]interface{}{"option": typ.Option})
+	case typ.COption != nil:
+		return json.Marshal(map[string]interface{}{"coption": typ.COption})
+	case typ.Vec != nil:
+		return json.Marshal(map[string]interface{}{"vec": typ.Vec})
+	case typ.Array != nil:
+		var length interface{} = typ.ArrayLen
+		if typ.ArrayLenGeneric != "" {
+			length = map[string]string{"generic": typ.ArrayLenGeneric}
+		}
+		return json.Marshal(map[string]interface{}{"array": []interface{}{typ.Array, length}})
+	case typ.Defined != nil:
+		return json.Marshal(map[string]interface{}{"defined": typ.Defined})
+	case typ.Generic != "":
+		return json.Marshal(map[string]string{"generic": typ.Generic})
+	default:
+		return nil, fmt.Errorf("invalid IDL type")
+	}
+}
+
+// UnmarshalJSON parses both the legacy type expressions
+// ("publicKey", {"defined": "Name"}) and the current ones.
+func (typ *IDLType) UnmarshalJSON(data []byte) error {
+	*typ = IDLType{}
+
+	var primitive string
+	if err := json.Unmarshal(data, &primitive); err == nil {
+		if primitive == "publicKey" {
+			primitive = TypePubkey
+		}
+		typ.Primitive = primitive
+		return nil
+	}
+
+	var obj map[string]stdjson.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid IDL type: %s", data)
+	}
+	if len(obj) != 1 {
+		return fmt.Errorf("invalid IDL type: %s", data)
+	}
+	for key, value := range obj {
+		switch key {
+		case "option":
+			typ.Option = new(IDLType)
+			return json.Unmarshal(value, typ.Option)
+		case "coption":
+			typ.COption = new(IDLType)
+			return json.Unmarshal(value, typ.COption)
+		case "vec":
+			typ.Vec = new(IDLType)
+			return json.Unmarshal(value, typ.Vec)
+		case "array":
+			var parts []stdjson.RawMessage
+			if err := json.Unmarshal(value, &parts); err != nil || len(parts) != 2 {
+				return fmt.Errorf("invalid IDL array type: %s", data)
+			}
+			typ.Array = new(IDLType)
+			if err := json.Unmarshal(parts[0], typ.Array); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(parts[1], &typ.ArrayLen); err == nil {
+				return nil
+			}
+			var generic struct {
+				Generic string `json:"generic"`
+			}
+			if err := json.Unmarshal(parts[1], &generic); err != nil || generic.Generic == "" {
+				return fmt.Errorf("invalid IDL array length: %s", parts[1])
+			}
+			typ.ArrayLenGeneric = generic.Generic
+			return nil
+		case "defined":
+			typ.Defined = new(IDLTypeDefined)
+			var name string
+			if err := json.Unmarshal(value, &name); err == nil {
+				typ.Defined.Name = name
+				return nil
+			}
+			return json.Unmarshal(value, typ.Defined)
+		case "generic":
+			return json.Unmarshal(value, &typ.Generic)
+		default:
+			return fmt.Errorf("unknown IDL type: %s", data)
+		}
+	}
+	return nil
+}
+
+// IDLDefinedFields are the fields of a struct or of an enum variant:
+// either named, or positional (tuple).
+type IDLDefinedFields struct {
+	Named []IDLField
+	Tuple []IDLType
+}
+
+func (fields IDLDefinedFields) MarshalJSON() ([]byte, error) {
+	if fields.Tuple != nil {
+		return json.Marshal(fields.Tuple)
+	}
+	if fields.Named == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(fields.Named)
+}
+
+func (fields *IDLDefinedFields) UnmarshalJSON(data []byte) error {
+	*fields = IDLDefinedFields{}
+
+	var items []stdjson.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("invalid IDL fields: %s", data)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	var probe map[string]stdjson.RawMessage
+	if json.Unmarshal(items[0], &probe) == nil {
+		_, hasName := probe["name"]
+		_, hasType := probe["type"]
+		if hasName && hasType {
+			return json.Unmarshal(data, &fields.Named)
+		}
+	}
+	return json.Unmarshal(data, &fields.Tuple)
+}
+
+// UnmarshalJSON also parses the legacy aliases ({"kind": "alias", "value": type}).
+func (ty *IDLTypeDefTy) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Kind     string            `json:"kind"`
+		Fields   *IDLDefinedFields `json:"fields"`
+		Variants []IDLEnumVariant  `json:"variants"`
+		Alias    *IDLType          `json:"alias"`
+		Value    *IDLType          `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*ty = IDLTypeDefTy{
+		Kind:     raw.Kind,
+		Fields:   raw.Fields,
+		Variants: raw.Variants,
+		Alias:    raw.Alias,
+	}
+	if raw.Kind == "alias" {
+		ty.Kind = TypeDefKindType
+		ty.Alias = raw.Value
+	}
+	return nil
+}
+
+// Bytes is a byte slice represented in JSON as an array of numbers,
+// like the discriminators and the constant seeds of the IDLs.
+type Bytes []byte
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	out := make([]int, len(b))
+	for i := range b {
+		out[i] = int(b[i])
+	}
+	return json.Marshal(out)
+}
+
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	var in []int
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("invalid byte array: %s", data)
+	}
+	out := make(Bytes, len(in))
+	for i, v := range in {
+		if v < 0 || v > 255 {
+			return fmt.Errorf("invalid byte array: %s", data)
+		}
+		out[i] = byte(v)
+	}
+	*b = out
+	return nil
+}
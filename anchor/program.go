@@ -0,0 +1,277 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/text"
+	"github.com/gagliardetto/solana-go/text/format"
+	"github.com/gagliardetto/treeout"
+)
+
+var (
+	ErrUnknownInstruction = errors.New("unknown instruction discriminator")
+	ErrUnknownAccount     = errors.New("unknown account discriminator")
+	ErrUnknownEvent       = errors.New("unknown event discriminator")
+)
+
+// DecodedInstruction is an instruction decoded with an IDL.
+type DecodedInstruction struct {
+	ProgramID   solana.PublicKey `json:"programId"`
+	ProgramName string           `json:"programName"`
+	Name        string           `json:"name"`
+	// Arguments of the instruction, keyed by name.
+	Args *OrderedMap `json:"args"`
+	// Accounts of the instruction, named after the IDL; the accounts
+	// beyond the ones declared by the IDL are named "remaining[i]".
+	Accounts []DecodedAccountMeta `json:"accounts"`
+}
+
+type DecodedAccountMeta struct {
+	Name       string           `json:"name"`
+	PublicKey  solana.PublicKey `json:"pubkey"`
+	IsWritable bool             `json:"isWritable"`
+	IsSigner   bool             `json:"isSigner"`
+}
+
+func (inst *DecodedInstruction) InstructionName() string {
+	return inst.Name
+}
+
+func (inst *DecodedInstruction) AccountNames() []string {
+	names := make([]string, len(inst.Accounts))
+	for i, account := range inst.Accounts {
+		names[i] = account.Name
+	}
+	return names
+}
+
+func (inst *DecodedInstruction) InstructionArgs() interface{} {
+	if inst.Args == nil {
+		return nil
+	}
+	return inst.Args
+}
+
+// DecodedAccount is the data of an account decoded with an IDL.
+type DecodedAccount struct {
+	Name string      `json:"name"`
+	Data *OrderedMap `json:"data"`
+}
+
+// DecodedEvent is an event decoded with an IDL.
+type DecodedEvent struct {
+	Name string      `json:"name"`
+	Data *OrderedMap `json:"data"`
+}
+
+// DecodeInstruction decodes the data of an instruction of the program,
+// naming the provided accounts (which may be nil) after the IDL.
+func (idl *IDL) DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*DecodedInstruction, error) {
+	return idl.decodeInstruction(idl.Address, accounts, data)
+}
+
+func (idl *IDL) decodeInstruction(programID solana.PublicKey, accounts []*solana.AccountMeta, data []byte) (*DecodedInstruction, error) {
+	inst, ok := idl.Instruction(data)
+	if !ok {
+		return nil, ErrUnknownInstruction
+	}
+	dec := bin.NewBorshDecoder(data[len(inst.Discriminator):])
+	args := NewOrderedMap()
+	for i := range inst.Args {
+		arg := &inst.Args[i]
+		value, err := idl.decode(dec, &arg.Type, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s argument %s: %w", inst.Name, arg.Name, err)
+		}
+		args.Set(arg.Name, value)
+	}
+
+	out := &DecodedInstruction{
+		ProgramID:   programID,
+		ProgramName: idl.Metadata.Name,
+		Name:        inst.Name,
+		Args:        args,
+	}
+	declared := inst.FlattenAccounts()
+	for i, meta := range accounts {
+		if meta == nil {
+			continue
+		}
+		name := fmt.Sprintf("remaining[%d]", i-len(declared))
+		if i < len(declared) {
+			name = declared[i].Name
+		}
+		out.Accounts = append(out.Accounts, DecodedAccountMeta{
+			Name:       name,
+			PublicKey:  meta.PublicKey,
+			IsWritable: meta.IsWritable,
+			IsSigner:   meta.IsSigner,
+		})
+	}
+	return out, nil
+}
+
+// DecodeAccount decodes the data of an account of the program.
+func (idl *IDL) DecodeAccount(data []byte) (*DecodedAccount, error) {
+	account, ok := idl.Account(data)
+	if !ok {
+		return nil, ErrUnknownAccount
+	}
+	value, err := idl.decodeStruct(account.Name, data[len(account.Discriminator):])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode account %s: %w", account.Name, err)
+	}
+	return &DecodedAccount{Name: account.Name, Data: value}, nil
+}
+
+// DecodeEvent decodes the data of an event emitted by the program,
+// discriminator included.
+func (idl *IDL) DecodeEvent(data []byte) (*DecodedEvent, error) {
+	event, ok := idl.Event(data)
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+	value, err := idl.decodeStruct(event.Name, data[len(event.Discriminator):])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode event %s: %w", event.Name, err)
+	}
+	return &DecodedEvent{Name: event.Name, Data: value}, nil
+}
+
+func (idl *IDL) decodeStruct(name string, data []byte) (*OrderedMap, error) {
+	value, err := idl.DecodeTypeDef(bin.NewBorshDecoder(data), name)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := value.(*OrderedMap)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct with named fields", name)
+	}
+	return out, nil
+}
+
+func (inst *DecodedInstruction) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(inst.ProgramName, inst.ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction(inst.Name)).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch treeout.Branches) {
+						for _, name := range inst.Args.Keys() {
+							value, _ := inst.Args.Get(name)
+							paramsBranch.Child(formatValue(name, value))
+						}
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch treeout.Branches) {
+						for _, account := range inst.Accounts {
+							accountsBranch.Child(format.Meta(account.Name, &solana.AccountMeta{
+								PublicKey:  account.PublicKey,
+								IsWritable: account.IsWritable,
+								IsSigner:   account.IsSigner,
+							}))
+						}
+					})
+				})
+		})
+}
+
+// formatValue formats a decoded value as JSON, which
+// is more readable than the spew dump of an *OrderedMap.
+func formatValue(name string, value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return format.Param(name, value)
+	}
+	return text.Shakespeare(name) + ": " + text.Lime(string(encoded))
+}
+
+var registry = struct {
+	mu   sync.RWMutex
+	idls map[solana.PublicKey]*IDL
+}{
+	idls: make(map[solana.PublicKey]*IDL),
+}
+
+// Register registers the IDL as the instruction decoder
+// (see solana.RegisterInstructionDecoder) and as an account decoder
+// (see solana.RegisterAccountDecoder) of the program at idl.Address,
+// so that solana.DecodeInstruction, solana.DecodeAccount and
+// Transaction.EncodeToTree decode the instructions and accounts of the program.
+func Register(idl *IDL) error {
+	if idl.Address.IsZero() {
+		return fmt.Errorf("IDL %q has no address", idl.Metadata.Name)
+	}
+	RegisterWithProgramID(idl.Address, idl)
+	return nil
+}
+
+// RegisterWithProgramID is like Register, for a program deployed at
+// another address than the one of the IDL.
+// Registering another IDL for the same program replaces the previous one.
+// Like solana.RegisterInstructionDecoder, it panics if the program
+// already has an instruction decoder that is not an IDL.
+func RegisterWithProgramID(programID solana.PublicKey, idl *IDL) {
+	registry.mu.Lock()
+	registry.idls[programID] = idl
+	registry.mu.Unlock()
+
+	// The decoders look up the IDL at every call, so that
+	// re-registering (a no-op for the same decoder) replaces it.
+	solana.RegisterInstructionDecoder(programID, func(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+		idl, ok := Lookup(programID)
+		if !ok {
+			return nil, solana.ErrInstructionDecoderNotFound
+		}
+		return idl.decodeInstruction(programID, accounts, data)
+	})
+	solana.RegisterAccountDecoder(
+		programID,
+		func(data []byte) bool {
+			idl, ok := Lookup(programID)
+			if !ok {
+				return false
+			}
+			_, ok = idl.Account(data)
+			return ok
+		},
+		func(data []byte) (interface{}, error) {
+			idl, ok := Lookup(programID)
+			if !ok {
+				return nil, solana.ErrAccountDecoderNotFound
+			}
+			return idl.DecodeAccount(data)
+		},
+	)
+}
+
+// Lookup returns the IDL registered for the provided program.
+func Lookup(programID solana.PublicKey) (*IDL, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	idl, ok := registry.idls[programID]
+	return idl, ok
+}
@@ -0,0 +1,181 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchor
+
+import (
+	"bytes"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/text"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDL_DecodeInstruction(t *testing.T) {
+	for _, name := range []string{"counter.json", "counter_legacy.json"} {
+		t.Run(name, func(t *testing.T) {
+			idl := loadIDL(t, name)
+
+			data := encodeBorsh(t, func(enc *bin.Encoder) {
+				enc.WriteBytes(InstructionDiscriminator("initialize"), false)
+				enc.WriteUint64(5, bin.LE)
+				enc.WriteOption(true)
+				enc.WriteUint32(2, bin.LE)
+				enc.WriteBytes([]byte("hi"), false)
+				enc.WriteUint8(1) // Mode::Step
+				enc.WriteUint8(3)
+			})
+			authority := solana.NewWallet().PublicKey()
+			inst, err := idl.DecodeInstruction([]*solana.AccountMeta{
+				solana.Meta(solana.NewWallet().PublicKey()).WRITE(),
+				solana.Meta(authority).WRITE().SIGNER(),
+				solana.Meta(solana.SystemProgramID),
+				solana.Meta(solana.SysVarRentPubkey),
+			}, data)
+			require.NoError(t, err)
+			require.Equal(t, "initialize", inst.Name)
+			args, err := json.Marshal(inst.Args)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"start":5,"label":"hi","mode":{"Step":{"size":3}}}`, string(args))
+			require.Len(t, inst.Accounts, 4)
+			require.Equal(t, DecodedAccountMeta{Name: "authority", PublicKey: authority, IsWritable: true, IsSigner: true}, inst.Accounts[1])
+			require.Equal(t, "system.system_program", inst.Accounts[2].Name)
+			require.Equal(t, "remaining[0]", inst.Accounts[3].Name)
+
+			data = encodeBorsh(t, func(enc *bin.Encoder) {
+				enc.WriteBytes(InstructionDiscriminator("increment"), false)
+				enc.WriteBytes([]byte{1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}, false)
+				enc.WriteUint32(2, bin.LE)
+				enc.WriteBytes([]byte{1, 2, 3, 4}, false)
+			})
+			inst, err = idl.DecodeInstruction(nil, data)
+			require.NoError(t, err)
+			args, err = json.Marshal(inst.Args)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"by":18446744073709551617,"tags":[[1,2],[3,4]]}`, string(args))
+
+			_, err = idl.DecodeInstruction(nil, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+			require.ErrorIs(t, err, ErrUnknownInstruction)
+
+			// Truncated data.
+			_, err = idl.DecodeInstruction(nil, data[:20])
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestIDL_DecodeAccountAndEvent(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+	account := encodeBorsh(t, func(enc *bin.Encoder) {
+		enc.WriteBytes(AccountDiscriminator("Counter"), false)
+		enc.WriteBytes(authority[:], false)
+		enc.WriteUint64(7, bin.LE)
+		enc.WriteUint8(2) // Mode::Range
+		enc.WriteUint32(1, bin.LE)
+		enc.WriteUint32(2, bin.LE)
+		enc.WriteInt16(-1, bin.LE)
+		enc.WriteInt16(2, bin.LE)
+	})
+	event := encodeBorsh(t, func(enc *bin.Encoder) {
+		enc.WriteBytes(EventDiscriminator("Incremented"), false)
+		enc.WriteUint64(8, bin.LE)
+		// COption::None still takes the space of the value.
+		enc.WriteUint32(0, bin.LE)
+		enc.WriteUint32(0, bin.LE)
+	})
+
+	for name, history := range map[string]string{
+		"counter.json":        `{"items":[-1,2]}`,
+		"counter_legacy.json": `[-1,2]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			idl := loadIDL(t, name)
+
+			decoded, err := idl.DecodeAccount(account)
+			require.NoError(t, err)
+			require.Equal(t, "Counter", decoded.Name)
+			out, err := json.Marshal(decoded.Data)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"authority":"`+authority.String()+`","count":7,"mode":{"Range":[1,2]},"history":`+history+`}`, string(out))
+			require.Equal(t, []string{"authority", "count", "mode", "history"}, decoded.Data.Keys())
+
+			decodedEvent, err := idl.DecodeEvent(event)
+			require.NoError(t, err)
+			out, err = json.Marshal(decodedEvent)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"name":"Incremented","data":{"count":8,"delta":null}}`, string(out))
+
+			_, err = idl.DecodeAccount(event)
+			require.ErrorIs(t, err, ErrUnknownAccount)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	idl := loadIDL(t, "counter.json")
+	programID := solana.NewWallet().PublicKey()
+	RegisterWithProgramID(programID, idl)
+	// Registering again, e.g. an updated IDL, does not panic.
+	RegisterWithProgramID(programID, loadIDL(t, "counter_legacy.json"))
+
+	got, ok := Lookup(programID)
+	require.True(t, ok)
+	require.Empty(t, got.Metadata.Spec)
+
+	payer := solana.NewWallet().PublicKey()
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			solana.NewInstruction(
+				programID,
+				solana.AccountMetaSlice{
+					solana.Meta(solana.NewWallet().PublicKey()).WRITE(),
+					solana.Meta(payer).SIGNER(),
+				},
+				encodeBorsh(t, func(enc *bin.Encoder) {
+					enc.WriteBytes(InstructionDiscriminator("increment"), false)
+					enc.WriteBytes(make([]byte, 16), false)
+					enc.WriteUint32(0, bin.LE)
+				}),
+			),
+		},
+		solana.Hash{},
+		solana.TransactionPayer(payer),
+	)
+	require.NoError(t, err)
+
+	_, err = solana.DecodeInstruction(programID, nil, []byte{1, 2, 3})
+	require.ErrorIs(t, err, ErrUnknownInstruction)
+
+	buf := new(bytes.Buffer)
+	_, err = tx.EncodeTree(text.NewTreeEncoder(buf, "tx"))
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "increment")
+	require.Contains(t, buf.String(), "authority")
+	require.Contains(t, buf.String(), programID.String())
+
+	account := encodeBorsh(t, func(enc *bin.Encoder) {
+		enc.WriteBytes(AccountDiscriminator("Counter"), false)
+		enc.WriteBytes(make([]byte, 32+8), false)
+		enc.WriteUint8(0)
+		enc.WriteBytes(make([]byte, 4), false)
+	})
+	decodedAccount, err := solana.DecodeAccount(programID, account)
+	require.NoError(t, err)
+	require.Equal(t, "Counter", decodedAccount.(*DecodedAccount).Name)
+
+	_, err = solana.DecodeAccount(programID, []byte{1, 2, 3})
+	require.ErrorIs(t, err, solana.ErrAccountDecoderNotFound)
+}
@@ -0,0 +1,545 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/anchor"
+	"github.com/mr-tron/base58"
+)
+
+// codamaNode is a node of a Codama IDL; it has the union of the fields
+// of the supported node kinds, the polymorphic ones left raw.
+type codamaNode struct {
+	Kind string   `json:"kind"`
+	Name string   `json:"name"`
+	Docs []string `json:"docs"`
+
+	// rootNode:
+	Program *codamaNode `json:"program"`
+
+	// programNode:
+	PublicKey    string       `json:"publicKey"`
+	Instructions []codamaNode `json:"instructions"`
+	Accounts     []codamaNode `json:"accounts"`
+	DefinedTypes []codamaNode `json:"definedTypes"`
+	Errors       []codamaNode `json:"errors"`
+	PDAs         []codamaNode `json:"pdas"`
+
+	// instructionNode:
+	Arguments []codamaNode `json:"arguments"`
+
+	// instructionAccountNode:
+	IsWritable   bool            `json:"isWritable"`
+	IsSigner     json.RawMessage `json:"isSigner"` // true, false or "either"
+	IsOptional   bool            `json:"isOptional"`
+	DefaultValue *codamaNode     `json:"defaultValue"`
+
+	// accountNode (a structTypeNode) and bytesValueNode (a string):
+	Data json.RawMessage `json:"data"`
+
+	// errorNode:
+	Code    uint32 `json:"code"`
+	Message string `json:"message"`
+
+	// pdaNode:
+	Seeds     []codamaNode `json:"seeds"`
+	ProgramID string       `json:"programId"`
+
+	// Type nodes:
+	Format   string          `json:"format"`
+	Endian   string          `json:"endian"`
+	Type     *codamaNode     `json:"type"`
+	Item     *codamaNode     `json:"item"`
+	Prefix   *codamaNode     `json:"prefix"`
+	Fixed    bool            `json:"fixed"`
+	Count    *codamaNode     `json:"count"`
+	Size     json.RawMessage `json:"size"`   // a number or a numberTypeNode
+	Number   json.RawMessage `json:"number"` // a number or a numberTypeNode
+	Fields   []codamaNode    `json:"fields"`
+	Variants []codamaNode    `json:"variants"`
+	Struct   *codamaNode     `json:"struct"`
+	Tuple    *codamaNode     `json:"tuple"`
+	Items    []codamaNode    `json:"items"`
+
+	// Value nodes:
+	Value    json.RawMessage `json:"value"` // a number or a value node
+	String   string          `json:"string"`
+	Encoding string          `json:"encoding"`
+}
+
+// isCodama tells whether the JSON document is a Codama IDL.
+func isCodama(data []byte) bool {
+	var probe struct {
+		Kind string `json:"kind"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Kind == "rootNode"
+}
+
+// parseCodama converts a Codama IDL to the Anchor IDL form, returning it
+// along with the PDAs it declares.
+func parseCodama(data []byte) (*anchor.IDL, []pdaDef, error) {
+	var root codamaNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("invalid Codama IDL: %w", err)
+	}
+	if root.Kind != "rootNode" || root.Program == nil {
+		return nil, nil, fmt.Errorf("invalid Codama IDL: no root program")
+	}
+	program := root.Program
+
+	idl := &anchor.IDL{
+		Metadata: anchor.IDLMetadata{
+			Name: bin.ToSnakeForSighash(program.Name),
+		},
+		Docs: program.Docs,
+	}
+	if program.PublicKey != "" {
+		address, err := solana.PublicKeyFromBase58(program.PublicKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid program address: %w", err)
+		}
+		idl.Address = address
+	}
+
+	for _, node := range program.DefinedTypes {
+		def, err := codamaTypeDef(node.Name, node.Docs, node.Type)
+		if err != nil {
+			return nil, nil, fmt.Errorf("type %s: %w", node.Name, err)
+		}
+		idl.Types = append(idl.Types, def)
+	}
+
+	for _, node := range program.Accounts {
+		var data codamaNode
+		if err := json.Unmarshal(node.Data, &data); err != nil || data.Kind != "structTypeNode" {
+			return nil, nil, fmt.Errorf("account %s: no struct data", node.Name)
+		}
+		discriminator, fields, err := codamaDiscriminator(data.Fields)
+		if err != nil {
+			return nil, nil, fmt.Errorf("account %s: %w", node.Name, err)
+		}
+		data.Fields = fields
+		def, err := codamaTypeDef(node.Name, node.Docs, &data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("account %s: %w", node.Name, err)
+		}
+		idl.Types = append(idl.Types, def)
+		idl.Accounts = append(idl.Accounts, anchor.IDLAccount{
+			Name:          def.Name,
+			Discriminator: discriminator,
+		})
+	}
+
+	for _, node := range program.Instructions {
+		inst, err := codamaInstruction(node)
+		if err != nil {
+			return nil, nil, fmt.Errorf("instruction %s: %w", node.Name, err)
+		}
+		idl.Instructions = append(idl.Instructions, inst)
+	}
+
+	for _, node := range program.Errors {
+		idl.Errors = append(idl.Errors, anchor.IDLErrorCode{
+			Code: node.Code,
+			Name: bin.ToSnakeForSighash(node.Name),
+			Msg:  node.Message,
+		})
+	}
+
+	pdas := []pdaDef{}
+	for _, node := range program.PDAs {
+		pda, ok, err := codamaPDA(node)
+		if err != nil {
+			return nil, nil, fmt.Errorf("pda %s: %w", node.Name, err)
+		}
+		if ok {
+			pdas = append(pdas, pda)
+		}
+	}
+	return idl, pdas, nil
+}
+
+// codamaDiscriminator extracts the "discriminator" field (or argument)
+// with a constant bytes value, returning the other fields.
+func codamaDiscriminator(fields []codamaNode) (anchor.Bytes, []codamaNode, error) {
+	var rest []codamaNode
+	var discriminator anchor.Bytes
+	for _, field := range fields {
+		if field.Name != "discriminator" {
+			rest = append(rest, field)
+			continue
+		}
+		if field.DefaultValue == nil || field.DefaultValue.Kind != "bytesValueNode" {
+			return nil, nil, fmt.Errorf("only the bytes discriminators are supported")
+		}
+		value, err := codamaBytes(field.DefaultValue)
+		if err != nil {
+			return nil, nil, err
+		}
+		discriminator = value
+	}
+	if discriminator == nil {
+		return nil, nil, fmt.Errorf("no discriminator")
+	}
+	return discriminator, rest, nil
+}
+
+func codamaInstruction(node codamaNode) (anchor.IDLInstruction, error) {
+	inst := anchor.IDLInstruction{
+		Name: bin.ToSnakeForSighash(node.Name),
+		Docs: node.Docs,
+	}
+	discriminator, args, err := codamaDiscriminator(node.Arguments)
+	if err != nil {
+		return inst, err
+	}
+	inst.Discriminator = discriminator
+	for _, arg := range args {
+		typ, err := codamaType(arg.Type)
+		if err != nil {
+			return inst, fmt.Errorf("argument %s: %w", arg.Name, err)
+		}
+		inst.Args = append(inst.Args, anchor.IDLField{
+			Name: bin.ToSnakeForSighash(arg.Name),
+			Docs: arg.Docs,
+			Type: *typ,
+		})
+	}
+	for _, acc := range node.Accounts {
+		account := anchor.IDLInstructionAccount{
+			Name:     bin.ToSnakeForSighash(acc.Name),
+			Docs:     acc.Docs,
+			Writable: acc.IsWritable,
+			Signer:   string(acc.IsSigner) == "true",
+			Optional: acc.IsOptional,
+		}
+		if acc.DefaultValue != nil && acc.DefaultValue.Kind == "publicKeyValueNode" {
+			account.Address = acc.DefaultValue.PublicKey
+		}
+		inst.Accounts = append(inst.Accounts, account)
+	}
+	return inst, nil
+}
+
+// codamaTypeDef converts a defined type (or account data); the structs,
+// enums and tuples are declared, the other types aliased.
+func codamaTypeDef(name string, docs []string, node *codamaNode) (anchor.IDLTypeDef, error) {
+	def := anchor.IDLTypeDef{
+		Name: goName(name),
+		Docs: docs,
+	}
+	if node == nil {
+		return def, fmt.Errorf("no type")
+	}
+	switch node.Kind {
+	case "structTypeNode", "tupleTypeNode":
+		fields, err := codamaFields(node)
+		if err != nil {
+			return def, err
+		}
+		def.Type = anchor.IDLTypeDefTy{Kind: anchor.TypeDefKindStruct, Fields: fields}
+	case "enumTypeNode":
+		if err := checkCodamaNumber(node.Size, "u8"); err != nil {
+			return def, fmt.Errorf("enum size: %w", err)
+		}
+		def.Type = anchor.IDLTypeDefTy{Kind: anchor.TypeDefKindEnum}
+		for _, variant := range node.Variants {
+			out := anchor.IDLEnumVariant{Name: goName(variant.Name)}
+			var err error
+			switch variant.Kind {
+			case "enumEmptyVariantTypeNode":
+			case "enumStructVariantTypeNode":
+				out.Fields, err = codamaFields(variant.Struct)
+			case "enumTupleVariantTypeNode":
+				out.Fields, err = codamaFields(variant.Tuple)
+			default:
+				err = fmt.Errorf("unsupported %s", variant.Kind)
+			}
+			if err != nil {
+				return def, fmt.Errorf("variant %s: %w", variant.Name, err)
+			}
+			def.Type.Variants = append(def.Type.Variants, out)
+		}
+	default:
+		typ, err := codamaType(node)
+		if err != nil {
+			return def, err
+		}
+		def.Type = anchor.IDLTypeDefTy{Kind: anchor.TypeDefKindType, Alias: typ}
+	}
+	return def, nil
+}
+
+func codamaFields(node *codamaNode) (*anchor.IDLDefinedFields, error) {
+	if node == nil {
+		return nil, fmt.Errorf("no fields")
+	}
+	fields := new(anchor.IDLDefinedFields)
+	switch node.Kind {
+	case "structTypeNode":
+		for _, field := range node.Fields {
+			typ, err := codamaType(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			fields.Named = append(fields.Named, anchor.IDLField{
+				Name: bin.ToSnakeForSighash(field.Name),
+				Docs: field.Docs,
+				Type: *typ,
+			})
+		}
+	case "tupleTypeNode":
+		for i := range node.Items {
+			typ, err := codamaType(&node.Items[i])
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			fields.Tuple = append(fields.Tuple, *typ)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported %s fields", node.Kind)
+	}
+	return fields, nil
+}
+
+// codamaType converts a type node; the structs, enums and tuples
+// are only supported as defined types.
+func codamaType(node *codamaNode) (*anchor.IDLType, error) {
+	if node == nil {
+		return nil, fmt.Errorf("no type")
+	}
+	switch node.Kind {
+	case "numberTypeNode":
+		if node.Endian != "" && node.Endian != "le" {
+			return nil, fmt.Errorf("unsupported %s endianness", node.Endian)
+		}
+		switch node.Format {
+		case anchor.TypeU8, anchor.TypeI8, anchor.TypeU16, anchor.TypeI16,
+			anchor.TypeU32, anchor.TypeI32, anchor.TypeU64, anchor.TypeI64,
+			anchor.TypeU128, anchor.TypeI128, anchor.TypeF32, anchor.TypeF64:
+			return &anchor.IDLType{Primitive: node.Format}, nil
+		}
+		return nil, fmt.Errorf("unsupported number format %q", node.Format)
+	case "booleanTypeNode":
+		if err := checkCodamaNumber(node.Size, "u8"); err != nil {
+			return nil, fmt.Errorf("boolean size: %w", err)
+		}
+		return &anchor.IDLType{Primitive: anchor.TypeBool}, nil
+	case "publicKeyTypeNode":
+		return &anchor.IDLType{Primitive: anchor.TypePubkey}, nil
+	case "amountTypeNode", "solAmountTypeNode", "dateTimeTypeNode":
+		var number codamaNode
+		if err := json.Unmarshal(node.Number, &number); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", node.Kind, err)
+		}
+		return codamaType(&number)
+	case "sizePrefixTypeNode":
+		if err := checkCodamaPrefix(node.Prefix, "u32"); err != nil {
+			return nil, err
+		}
+		switch {
+		case node.Type != nil && node.Type.Kind == "stringTypeNode":
+			return &anchor.IDLType{Primitive: anchor.TypeString}, nil
+		case node.Type != nil && node.Type.Kind == "bytesTypeNode":
+			return &anchor.IDLType{Primitive: anchor.TypeBytes}, nil
+		}
+		return nil, fmt.Errorf("unsupported size-prefixed type")
+	case "fixedSizeTypeNode":
+		if node.Type == nil || (node.Type.Kind != "bytesTypeNode" && node.Type.Kind != "stringTypeNode") {
+			return nil, fmt.Errorf("unsupported fixed-size type")
+		}
+		size, err := strconv.Atoi(string(node.Size))
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixed size %s", node.Size)
+		}
+		return &anchor.IDLType{Array: &anchor.IDLType{Primitive: anchor.TypeU8}, ArrayLen: size}, nil
+	case "optionTypeNode":
+		if node.Fixed {
+			return nil, fmt.Errorf("unsupported fixed option")
+		}
+		item, err := codamaType(node.Item)
+		if err != nil {
+			return nil, err
+		}
+		if node.Prefix != nil && node.Prefix.Format == "u32" {
+			return &anchor.IDLType{COption: item}, nil
+		}
+		if err := checkCodamaPrefix(node.Prefix, "u8"); err != nil {
+			return nil, err
+		}
+		return &anchor.IDLType{Option: item}, nil
+	case "arrayTypeNode":
+		item, err := codamaType(node.Item)
+		if err != nil {
+			return nil, err
+		}
+		if node.Count == nil {
+			return nil, fmt.Errorf("array without count")
+		}
+		switch node.Count.Kind {
+		case "fixedCountNode":
+			count, err := strconv.Atoi(string(node.Count.Value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid array count %s", node.Count.Value)
+			}
+			return &anchor.IDLType{Array: item, ArrayLen: count}, nil
+		case "prefixedCountNode":
+			if err := checkCodamaPrefix(node.Count.Prefix, "u32"); err != nil {
+				return nil, err
+			}
+			return &anchor.IDLType{Vec: item}, nil
+		}
+		return nil, fmt.Errorf("unsupported array count %s", node.Count.Kind)
+	case "definedTypeLinkNode":
+		return &anchor.IDLType{Defined: &anchor.IDLTypeDefined{Name: goName(node.Name)}}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", node.Kind)
+}
+
+func checkCodamaPrefix(prefix *codamaNode, format string) error {
+	if prefix == nil || prefix.Kind != "numberTypeNode" || prefix.Format != format {
+		return fmt.Errorf("only %s prefixes are supported", format)
+	}
+	return nil
+}
+
+// checkCodamaNumber checks the format of an optional numberTypeNode.
+func checkCodamaNumber(raw json.RawMessage, format string) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	var number codamaNode
+	if err := json.Unmarshal(raw, &number); err != nil {
+		return err
+	}
+	return checkCodamaPrefix(&number, format)
+}
+
+func codamaBytes(node *codamaNode) ([]byte, error) {
+	var data string
+	if err := json.Unmarshal(node.Data, &data); err != nil {
+		return nil, fmt.Errorf("invalid bytes value: %w", err)
+	}
+	switch node.Encoding {
+	case "base16":
+		return hex.DecodeString(data)
+	case "base58":
+		return base58.Decode(data)
+	case "base64":
+		return base64.StdEncoding.DecodeString(data)
+	case "utf8":
+		return []byte(data), nil
+	}
+	return nil, fmt.Errorf("unsupported bytes encoding %q", node.Encoding)
+}
+
+// codamaPDA converts a pdaNode, reporting whether its seeds are supported.
+func codamaPDA(node codamaNode) (pdaDef, bool, error) {
+	pda := pdaDef{
+		name: bin.ToSnakeForSighash(node.Name),
+		docs: node.Docs,
+	}
+	if node.ProgramID != "" {
+		program, err := solana.PublicKeyFromBase58(node.ProgramID)
+		if err != nil {
+			return pda, false, fmt.Errorf("invalid program: %w", err)
+		}
+		pda.program = program
+	}
+	for _, seed := range node.Seeds {
+		switch seed.Kind {
+		case "variablePdaSeedNode":
+			typ, err := codamaSeedType(seed.Type)
+			if err != nil {
+				return pda, false, fmt.Errorf("seed %s: %w", seed.Name, err)
+			}
+			if seedExpr(typ, "x") == "" {
+				return pda, false, nil
+			}
+			pda.seeds = append(pda.seeds, pdaSeed{name: bin.ToSnakeForSighash(seed.Name), typ: typ})
+		case "constantPdaSeedNode":
+			var value codamaNode
+			if err := json.Unmarshal(seed.Value, &value); err != nil {
+				return pda, false, fmt.Errorf("invalid constant seed: %w", err)
+			}
+			data, ok, err := codamaConstSeed(seed.Type, &value)
+			if err != nil || !ok {
+				return pda, false, err
+			}
+			pda.seeds = append(pda.seeds, pdaSeed{value: data})
+		default:
+			return pda, false, nil
+		}
+	}
+	return pda, true, nil
+}
+
+// codamaSeedType converts the type of a variable seed, which is
+// not size-prefixed.
+func codamaSeedType(node *codamaNode) (*anchor.IDLType, error) {
+	if node != nil {
+		switch node.Kind {
+		case "stringTypeNode":
+			return &anchor.IDLType{Primitive: anchor.TypeString}, nil
+		case "bytesTypeNode":
+			return &anchor.IDLType{Primitive: anchor.TypeBytes}, nil
+		}
+	}
+	return codamaType(node)
+}
+
+func codamaConstSeed(typ *codamaNode, value *codamaNode) ([]byte, bool, error) {
+	switch value.Kind {
+	case "stringValueNode":
+		return []byte(value.String), true, nil
+	case "bytesValueNode":
+		data, err := codamaBytes(value)
+		return data, err == nil, err
+	case "publicKeyValueNode":
+		key, err := solana.PublicKeyFromBase58(value.PublicKey)
+		if err != nil {
+			return nil, false, err
+		}
+		return key[:], true, nil
+	case "numberValueNode":
+		if typ == nil || typ.Kind != "numberTypeNode" {
+			return nil, false, nil
+		}
+		number, err := strconv.ParseUint(string(value.Number), 10, 64)
+		if err != nil {
+			return nil, false, nil
+		}
+		switch typ.Format {
+		case "u8":
+			return []byte{byte(number)}, true, nil
+		case "u16":
+			return binary.LittleEndian.AppendUint16(nil, uint16(number)), true, nil
+		case "u32":
+			return binary.LittleEndian.AppendUint32(nil, uint32(number)), true, nil
+		case "u64":
+			return binary.LittleEndian.AppendUint64(nil, number), true, nil
+		}
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go/anchor"
+)
+
+func (g *generator) genAccounts() error {
+	f := g.newFile("accounts.go")
+	bin, sol := f.use(pkgBinary), f.use(pkgSolana)
+
+	var defs []*anchor.IDLTypeDef
+	for _, account := range g.idl.Accounts {
+		if err := checkDiscriminator("account", account.Name, account.Discriminator); err != nil {
+			return err
+		}
+		def, ok := g.idl.TypeDef(account.Name)
+		if !ok {
+			return fmt.Errorf("account %s: no type definition", account.Name)
+		}
+		if err := checkSerialization(def); err != nil {
+			return err
+		}
+		if def.Type.Kind != anchor.TypeDefKindStruct || len(def.Generics) > 0 {
+			return fmt.Errorf("account %s: only the non-generic structs are supported", account.Name)
+		}
+		defs = append(defs, def)
+	}
+
+	if len(g.idl.Accounts) > 0 {
+		f.P("var (")
+		for _, account := range g.idl.Accounts {
+			f.P("%sDiscriminator = %s", goName(account.Name), byteArrayLiteral(account.Discriminator))
+		}
+		f.P(")")
+		f.P("")
+	}
+
+	var test *file
+	if len(defs) > 0 {
+		test = g.newFile("accounts_test.go")
+	}
+	for _, def := range defs {
+		f.use("fmt")
+		name := goName(def.Name)
+		fields := structFields(def.Type.Fields)
+		writeDocs(f, def.Docs)
+		if err := g.writeStruct(f, name, fields); err != nil {
+			return fmt.Errorf("account %s: %w", def.Name, err)
+		}
+		f.P("")
+		f.P("func (obj %s) MarshalWithEncoder(encoder *%s.Encoder) (err error) {", name, bin)
+		f.P("// Write account discriminator:")
+		f.P("err = encoder.WriteBytes(%sDiscriminator[:], false)", name)
+		f.P("if err != nil {\nreturn err\n}")
+		for _, field := range fields {
+			writeEncodeField(f, field.name, field.typ)
+		}
+		f.P("return nil")
+		f.P("}")
+		f.P("")
+		f.P("func (obj *%s) UnmarshalWithDecoder(decoder *%s.Decoder) (err error) {", name, bin)
+		f.P("// Read and check account discriminator:")
+		f.P("{")
+		f.P("discriminator, err := decoder.ReadTypeID()")
+		f.P("if err != nil {\nreturn err\n}")
+		f.P("if !discriminator.Equal(%sDiscriminator[:]) {", name)
+		f.P("return fmt.Errorf(")
+		f.P("\"wrong discriminator: wanted %%s, got %%s\",")
+		f.P("fmt.Sprint(%sDiscriminator[:]),", name)
+		f.P("fmt.Sprint(discriminator[:]))")
+		f.P("}")
+		f.P("}")
+		for _, field := range fields {
+			writeDecodeField(f, field.name, field.typ)
+		}
+		f.P("return nil")
+		f.P("}")
+		f.P("")
+		writeAccountTest(test, name)
+	}
+
+	f.P("func registerAccountDecoders(programID %s.PublicKey) {", sol)
+	for _, def := range defs {
+		name := goName(def.Name)
+		f.P("%s.RegisterAccountDecoder(programID, %s.AccountDiscriminatorMatcher(%sDiscriminator[:]), decode%sAccount)", sol, sol, name, name)
+	}
+	f.P("}")
+	for _, def := range defs {
+		name := goName(def.Name)
+		f.P("")
+		f.P("func decode%sAccount(data []byte) (interface{}, error) {", name)
+		f.P("out := new(%s)", name)
+		f.P("if err := %s.NewBorshDecoder(data).Decode(out); err != nil {", bin)
+		f.P("return nil, fmt.Errorf(\"unable to decode %s: %%w\", err)", def.Name)
+		f.P("}")
+		f.P("return out, nil")
+		f.P("}")
+	}
+	return nil
+}
+
+func writeAccountTest(f *file, name string) {
+	f.use("bytes")
+	f.use("testing")
+	require := f.use(pkgRequire)
+
+	f.P("func TestEncodeDecode_%s(t *testing.T) {", name)
+	f.P("fu := newFuzzer()")
+	f.P("params := new(%s)", name)
+	f.P("fu.Fuzz(params)")
+	f.P("buf := new(bytes.Buffer)")
+	f.P("err := encodeT(*params, buf)")
+	f.P("%s.NoError(t, err)", require)
+	f.P("%s.Equal(t, %sDiscriminator[:], buf.Bytes()[:8])", require, name)
+	f.P("//")
+	f.P("got := new(%s)", name)
+	f.P("err = decodeT(got, buf.Bytes())")
+	f.P("%s.NoError(t, err)", require)
+	f.P("%s.Equal(t, params, got)", require)
+	f.P("//")
+	f.P("decoded, err := decode%sAccount(buf.Bytes())", name)
+	f.P("%s.NoError(t, err)", require)
+	f.P("%s.Equal(t, params, decoded)", require)
+	f.P("}")
+	f.P("")
+}
+
+func (g *generator) genEvents() error {
+	if len(g.idl.Events) == 0 {
+		return nil
+	}
+	f := g.newFile("events.go")
+	bin := f.use(pkgBinary)
+	f.use("bytes")
+	f.use("fmt")
+
+	f.P("var (")
+	for _, event := range g.idl.Events {
+		if err := checkDiscriminator("event", event.Name, event.Discriminator); err != nil {
+			return err
+		}
+		if def, ok := g.idl.TypeDef(event.Name); !ok || len(def.Generics) > 0 {
+			return fmt.Errorf("event %s: no (non-generic) type definition", event.Name)
+		}
+		f.P("%sDiscriminator = %s", goName(event.Name), byteArrayLiteral(event.Discriminator))
+	}
+	f.P(")")
+	f.P("")
+	f.P("// DecodeEvent decodes an event emitted by the program (the base64-decoded")
+	f.P("// data of a \"Program data:\" log), returning a pointer to the event struct.")
+	f.P("func DecodeEvent(data []byte) (interface{}, error) {")
+	f.P("if len(data) < 8 {")
+	f.P("return nil, fmt.Errorf(\"event data too short: %%d bytes\", len(data))")
+	f.P("}")
+	f.P("var out interface{}")
+	f.P("switch {")
+	for _, event := range g.idl.Events {
+		name := goName(event.Name)
+		f.P("case bytes.Equal(data[:8], %sDiscriminator[:]):", name)
+		f.P("out = new(%s)", name)
+	}
+	f.P("default:")
+	f.P("return nil, fmt.Errorf(\"unknown event discriminator %%v\", data[:8])")
+	f.P("}")
+	f.P("if err := %s.NewBorshDecoder(data[8:]).Decode(out); err != nil {", bin)
+	f.P("return nil, fmt.Errorf(\"unable to decode event: %%w\", err)")
+	f.P("}")
+	f.P("return out, nil")
+	f.P("}")
+
+	t := g.newFile("events_test.go")
+	t.use("bytes")
+	t.use("testing")
+	require := t.use(pkgRequire)
+	for i, event := range g.idl.Events {
+		name := goName(event.Name)
+		if i > 0 {
+			t.P("")
+		}
+		t.P("func TestDecodeEvent_%s(t *testing.T) {", name)
+		t.P("fu := newFuzzer()")
+		t.P("params := new(%s)", name)
+		t.P("fu.Fuzz(params)")
+		t.P("buf := new(bytes.Buffer)")
+		t.P("buf.Write(%sDiscriminator[:])", name)
+		t.P("err := encodeT(*params, buf)")
+		t.P("%s.NoError(t, err)", require)
+		t.P("//")
+		t.P("got, err := DecodeEvent(buf.Bytes())")
+		t.P("%s.NoError(t, err)", require)
+		t.P("%s.Equal(t, params, got)", require)
+		t.P("}")
+	}
+	return nil
+}
+
+func (g *generator) genErrors() error {
+	if len(g.idl.Errors) == 0 {
+		return nil
+	}
+	f := g.newFile("errors.go")
+	f.use("fmt")
+
+	f.P("// ProgramError is a custom error of the program.")
+	f.P("type ProgramError struct {")
+	f.P("Code uint32")
+	f.P("Name string")
+	f.P("Msg  string")
+	f.P("}")
+	f.P("")
+	f.P("func (e *ProgramError) Error() string {")
+	f.P("return fmt.Sprintf(\"%%s (%%d): %%s\", e.Name, e.Code, e.Msg)")
+	f.P("}")
+	f.P("")
+	f.P("var (")
+	for _, e := range g.idl.Errors {
+		f.P("Err%s = &ProgramError{Code: %d, Name: %q, Msg: %q}", goName(e.Name), e.Code, goName(e.Name), e.Msg)
+	}
+	f.P(")")
+	f.P("")
+	f.P("var errorsByCode = map[uint32]*ProgramError{")
+	for _, e := range g.idl.Errors {
+		f.P("%d: Err%s,", e.Code, goName(e.Name))
+	}
+	f.P("}")
+	f.P("")
+	f.P("// ErrorFromCode returns the error of the program with the provided custom error code.")
+	f.P("func ErrorFromCode(code uint32) (*ProgramError, bool) {")
+	f.P("e, ok := errorsByCode[code]")
+	f.P("return e, ok")
+	f.P("}")
+	return nil
+}
@@ -0,0 +1,512 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go/anchor"
+)
+
+func (g *generator) genInstructions() error {
+	f := g.newFile("instructions.go")
+	bin, sol := f.use(pkgBinary), f.use(pkgSolana)
+	text, treeout := f.use(pkgText), f.use(pkgTreeout)
+	f.use("bytes")
+	f.use("fmt")
+
+	f.doc = g.idl.Docs
+	if g.idl.Address.IsZero() {
+		f.P("var ProgramID %s.PublicKey", sol)
+	} else {
+		f.P("var ProgramID %s.PublicKey = %s.MustPublicKeyFromBase58(%q)", sol, sol, g.idl.Address.String())
+	}
+	f.P("")
+	f.P("func SetProgramID(pubkey %s.PublicKey) {", sol)
+	f.P("ProgramID = pubkey")
+	f.P("%s.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)", sol)
+	f.P("registerAccountDecoders(ProgramID)")
+	f.P("}")
+	f.P("")
+	f.P("const ProgramName = %q", goName(g.idl.Metadata.Name))
+	f.P("")
+	f.P("func init() {")
+	f.P("if !ProgramID.IsZero() {")
+	f.P("%s.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)", sol)
+	f.P("registerAccountDecoders(ProgramID)")
+	f.P("}")
+	f.P("}")
+	f.P("")
+
+	if len(g.idl.Instructions) > 0 {
+		f.P("var (")
+		for i, inst := range g.idl.Instructions {
+			if err := checkDiscriminator("instruction", inst.Name, inst.Discriminator); err != nil {
+				return err
+			}
+			if i > 0 {
+				f.P("")
+			}
+			writeDocs(f, inst.Docs)
+			f.P("Instruction_%s = %s.TypeID(%s)", g.instructionTypes[inst.Name], bin, byteArrayLiteral(inst.Discriminator))
+		}
+		f.P(")")
+		f.P("")
+	}
+
+	f.P("// InstructionIDToName returns the name of the instruction given its ID.")
+	f.P("func InstructionIDToName(id %s.TypeID) string {", bin)
+	f.P("switch id {")
+	for _, inst := range g.idl.Instructions {
+		name := g.instructionTypes[inst.Name]
+		f.P("case Instruction_%s:", name)
+		f.P("return %q", name)
+	}
+	f.P("default:")
+	f.P("return \"\"")
+	f.P("}")
+	f.P("}")
+	f.P("")
+
+	f.P("type Instruction struct {")
+	f.P("%s.BaseVariant", bin)
+	f.P("}")
+	f.P("")
+	f.P("func (inst *Instruction) EncodeToTree(parent %s.Branches) {", treeout)
+	f.P("if enToTree, ok := inst.Impl.(%s.EncodableToTree); ok {", text)
+	f.P("enToTree.EncodeToTree(parent)")
+	f.P("} else {")
+	f.P("parent.Child(%s.Sdump(inst))", f.use(pkgSpew))
+	f.P("}")
+	f.P("}")
+	f.P("")
+	f.P("func (inst *Instruction) ProgramID() %s.PublicKey {", sol)
+	f.P("return ProgramID")
+	f.P("}")
+	f.P("")
+	f.P("func (inst *Instruction) Accounts() (out []*%s.AccountMeta) {", sol)
+	f.P("return inst.Impl.(%s.AccountsGettable).GetAccounts()", sol)
+	f.P("}")
+	f.P("")
+	f.P("func (inst *Instruction) Data() ([]byte, error) {")
+	f.P("buf := new(bytes.Buffer)")
+	f.P("if err := %s.NewBorshEncoder(buf).Encode(inst); err != nil {", bin)
+	f.P("return nil, fmt.Errorf(\"unable to encode instruction: %%w\", err)")
+	f.P("}")
+	f.P("return buf.Bytes(), nil")
+	f.P("}")
+	f.P("")
+	f.P("func (inst *Instruction) TextEncode(encoder *%s.Encoder, option *%s.Option) error {", text, text)
+	f.P("return encoder.Encode(inst.Impl, option)")
+	f.P("}")
+	f.P("")
+	f.P("func (inst *Instruction) UnmarshalWithDecoder(decoder *%s.Decoder) error {", bin)
+	f.P("typeID, err := decoder.ReadTypeID()")
+	f.P("if err != nil {")
+	f.P("return fmt.Errorf(\"unable to read variant type: %%w\", err)")
+	f.P("}")
+	f.P("var impl interface{}")
+	f.P("switch typeID {")
+	for _, inst := range g.idl.Instructions {
+		name := g.instructionTypes[inst.Name]
+		f.P("case Instruction_%s:", name)
+		f.P("impl = new(%s)", name)
+	}
+	f.P("default:")
+	f.P("return fmt.Errorf(\"unknown instruction discriminator %%v\", typeID.Bytes())")
+	f.P("}")
+	f.P("if err := decoder.Decode(impl); err != nil {")
+	f.P("return fmt.Errorf(\"unable to decode %%s: %%w\", InstructionIDToName(typeID), err)")
+	f.P("}")
+	f.P("inst.TypeID = typeID")
+	f.P("inst.Impl = impl")
+	f.P("return nil")
+	f.P("}")
+	f.P("")
+	f.P("func (inst Instruction) MarshalWithEncoder(encoder *%s.Encoder) error {", bin)
+	f.P("err := encoder.WriteBytes(inst.TypeID.Bytes(), false)")
+	f.P("if err != nil {")
+	f.P("return fmt.Errorf(\"unable to write variant type: %%w\", err)")
+	f.P("}")
+	f.P("return encoder.Encode(inst.Impl)")
+	f.P("}")
+	f.P("")
+	f.P("func registryDecodeInstruction(accounts []*%s.AccountMeta, data []byte) (interface{}, error) {", sol)
+	f.P("inst, err := DecodeInstruction(accounts, data)")
+	f.P("if err != nil {")
+	f.P("return nil, err")
+	f.P("}")
+	f.P("return inst, nil")
+	f.P("}")
+	f.P("")
+	f.P("func DecodeInstruction(accounts []*%s.AccountMeta, data []byte) (*Instruction, error) {", sol)
+	f.P("inst := new(Instruction)")
+	f.P("if err := %s.NewBorshDecoder(data).Decode(inst); err != nil {", bin)
+	f.P("return nil, fmt.Errorf(\"unable to decode instruction: %%w\", err)")
+	f.P("}")
+	f.P("if v, ok := inst.Impl.(%s.AccountsSettable); ok {", sol)
+	f.P("err := v.SetAccounts(accounts)")
+	f.P("if err != nil {")
+	f.P("return nil, fmt.Errorf(\"unable to set accounts for instruction: %%w\", err)")
+	f.P("}")
+	f.P("}")
+	f.P("return inst, nil")
+	f.P("}")
+
+	for i := range g.idl.Instructions {
+		if err := g.genInstruction(&g.idl.Instructions[i]); err != nil {
+			return fmt.Errorf("instruction %s: %w", g.idl.Instructions[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// instructionArg is an argument of an instruction, with its Go names.
+type instructionArg struct {
+	anchor.IDLField
+	field string
+	param string
+	typ   string
+	tag   string
+}
+
+func (g *generator) genInstruction(inst *anchor.IDLInstruction) error {
+	name := g.instructionTypes[inst.Name]
+	f := g.newFile(name + ".go")
+	bin, sol := f.use(pkgBinary), f.use(pkgSolana)
+	format, treeout := f.use(pkgFormat), f.use(pkgTreeout)
+
+	args := make([]instructionArg, len(inst.Args))
+	for i, arg := range inst.Args {
+		typ, tag, err := g.fieldType(f, &arg.Type)
+		if err != nil {
+			return fmt.Errorf("arg %s: %w", arg.Name, err)
+		}
+		if optionKind(&arg.Type) == "" {
+			typ = "*" + typ
+		}
+		args[i] = instructionArg{
+			IDLField: arg,
+			field:    goName(arg.Name),
+			param:    paramName(arg.Name),
+			typ:      typ,
+			tag:      tag,
+		}
+	}
+	accounts := inst.FlattenAccounts()
+
+	if len(inst.Docs) > 0 {
+		writeDocs(f, inst.Docs)
+	} else {
+		f.P("// %s is the `%s` instruction.", name, inst.Name)
+	}
+	f.P("type %s struct {", name)
+	for _, arg := range args {
+		writeDocs(f, arg.Docs)
+		f.P("%s %s %s", arg.field, arg.typ, arg.tag)
+	}
+	if len(args) > 0 {
+		f.P("")
+	}
+	for i, acc := range accounts {
+		if i > 0 {
+			f.P("//")
+		}
+		f.P("// [%d] = [%s] %s", i, accountFlags(acc), acc.Name)
+		for _, line := range acc.Docs {
+			f.P("// ··········· %s", strings.TrimSpace(line))
+		}
+	}
+	f.P("%s.AccountMetaSlice `bin:\"-\" borsh_skip:\"true\"`", sol)
+	f.P("}")
+	f.P("")
+
+	f.P("// New%sInstructionBuilder creates a new `%s` instruction builder.", name, name)
+	f.P("func New%sInstructionBuilder() *%s {", name, name)
+	f.P("nd := &%s{", name)
+	f.P("AccountMetaSlice: make(%s.AccountMetaSlice, %d),", sol, len(accounts))
+	f.P("}")
+	for i, acc := range accounts {
+		if acc.Address != "" {
+			f.P("nd.AccountMetaSlice[%d] = %s.Meta(%s.MustPublicKeyFromBase58(%q))%s", i, sol, sol, acc.Address, metaModifiers(acc))
+		}
+	}
+	f.P("return nd")
+	f.P("}")
+	f.P("")
+
+	for _, arg := range args {
+		value := arg.typ[1:]
+		f.P("// Set%s sets the %q parameter.", arg.field, arg.Name)
+		writeDocs(f, arg.Docs)
+		f.P("func (inst *%s) Set%s(%s %s) *%s {", name, arg.field, arg.param, value, name)
+		f.P("inst.%s = &%s", arg.field, arg.param)
+		f.P("return inst")
+		f.P("}")
+		f.P("")
+	}
+
+	for i, acc := range accounts {
+		accName := goName(acc.Name)
+		param := paramName(acc.Name)
+		f.P("// Set%sAccount sets the %q account.", accName, acc.Name)
+		writeDocs(f, acc.Docs)
+		f.P("func (inst *%s) Set%sAccount(%s %s.PublicKey) *%s {", name, accName, param, sol, name)
+		f.P("inst.AccountMetaSlice[%d] = %s.Meta(%s)%s", i, sol, param, metaModifiers(acc))
+		f.P("return inst")
+		f.P("}")
+		f.P("")
+		f.P("// Get%sAccount gets the %q account.", accName, acc.Name)
+		writeDocs(f, acc.Docs)
+		f.P("func (inst *%s) Get%sAccount() *%s.AccountMeta {", name, accName, sol)
+		f.P("return inst.AccountMetaSlice.Get(%d)", i)
+		f.P("}")
+		f.P("")
+	}
+
+	f.P("func (inst %s) Build() *Instruction {", name)
+	var optional []int
+	for i, acc := range accounts {
+		if acc.Optional {
+			optional = append(optional, i)
+		}
+	}
+	if len(optional) > 0 {
+		f.P("// The program ID stands for the optional accounts that are not set:")
+		for _, i := range optional {
+			f.P("if inst.AccountMetaSlice[%d] == nil {", i)
+			f.P("inst.AccountMetaSlice[%d] = %s.Meta(ProgramID)", i, sol)
+			f.P("}")
+		}
+	}
+	f.P("return &Instruction{BaseVariant: %s.BaseVariant{", bin)
+	f.P("Impl:   inst,")
+	f.P("TypeID: Instruction_%s,", name)
+	f.P("}}")
+	f.P("}")
+	f.P("")
+	f.P("// ValidateAndBuild validates the instruction parameters and accounts;")
+	f.P("// if there is a validation error, it returns the error.")
+	f.P("// Otherwise, it builds and returns the instruction.")
+	f.P("func (inst %s) ValidateAndBuild() (*Instruction, error) {", name)
+	f.P("if err := inst.Validate(); err != nil {")
+	f.P("return nil, err")
+	f.P("}")
+	f.P("return inst.Build(), nil")
+	f.P("}")
+	f.P("")
+
+	f.P("func (inst *%s) Validate() error {", name)
+	var required []instructionArg
+	for _, arg := range args {
+		if optionKind(&arg.Type) == "" {
+			required = append(required, arg)
+		}
+	}
+	if len(required) > 0 {
+		f.use("errors")
+		f.P("// Check whether all (required) parameters are set:")
+		f.P("{")
+		for _, arg := range required {
+			f.P("if inst.%s == nil {", arg.field)
+			f.P("return errors.New(%q)", arg.field+" parameter is not set")
+			f.P("}")
+		}
+		f.P("}")
+		f.P("")
+	}
+	if len(accounts) > len(optional) {
+		f.use("errors")
+		f.P("// Check whether all (required) accounts are set:")
+		f.P("{")
+		for i, acc := range accounts {
+			if acc.Optional {
+				continue
+			}
+			f.P("if inst.AccountMetaSlice[%d] == nil {", i)
+			f.P("return errors.New(%q)", "accounts."+goName(acc.Name)+" is not set")
+			f.P("}")
+		}
+		f.P("}")
+	}
+	f.P("return nil")
+	f.P("}")
+	f.P("")
+
+	f.P("func (inst *%s) EncodeToTree(parent %s.Branches) {", name, treeout)
+	f.P("parent.Child(%s.Program(ProgramName, ProgramID)).", format)
+	f.P("//")
+	f.P("ParentFunc(func(programBranch %s.Branches) {", treeout)
+	f.P("programBranch.Child(%s.Instruction(%q)).", format, name)
+	f.P("//")
+	f.P("ParentFunc(func(instructionBranch %s.Branches) {", treeout)
+	f.P("")
+	if len(args) > 0 {
+		labels := make([]string, len(args))
+		for i, arg := range args {
+			labels[i] = arg.field
+			if optionKind(&arg.Type) != "" {
+				labels[i] += " (OPT)"
+			}
+		}
+		labels = padNames(labels)
+		f.P("// Parameters of the instruction:")
+		f.P("instructionBranch.Child(\"Params\").ParentFunc(func(paramsBranch %s.Branches) {", treeout)
+		for i, arg := range args {
+			if optionKind(&arg.Type) != "" {
+				f.P("paramsBranch.Child(%s.Param(%q, inst.%s))", format, labels[i], arg.field)
+			} else {
+				f.P("paramsBranch.Child(%s.Param(%q, *inst.%s))", format, labels[i], arg.field)
+			}
+		}
+		f.P("})")
+		f.P("")
+	}
+	labels := make([]string, len(accounts))
+	for i, acc := range accounts {
+		labels[i] = acc.Name
+	}
+	labels = padNames(labels)
+	f.P("// Accounts of the instruction:")
+	f.P("instructionBranch.Child(\"Accounts\").ParentFunc(func(accountsBranch %s.Branches) {", treeout)
+	for i := range accounts {
+		f.P("accountsBranch.Child(%s.Meta(%q, inst.AccountMetaSlice.Get(%d)))", format, labels[i], i)
+	}
+	f.P("})")
+	f.P("})")
+	f.P("})")
+	f.P("}")
+	f.P("")
+
+	f.P("func (obj %s) MarshalWithEncoder(encoder *%s.Encoder) (err error) {", name, bin)
+	for _, arg := range args {
+		writeEncodeField(f, arg.field, &arg.Type)
+	}
+	f.P("return nil")
+	f.P("}")
+	f.P("")
+	f.P("func (obj *%s) UnmarshalWithDecoder(decoder *%s.Decoder) (err error) {", name, bin)
+	for _, arg := range args {
+		writeDecodeField(f, arg.field, &arg.Type)
+	}
+	f.P("return nil")
+	f.P("}")
+	f.P("")
+
+	f.P("// New%sInstruction declares a new %s instruction with the provided parameters and accounts.", name, name)
+	f.P("func New%sInstruction(", name)
+	if len(args) > 0 {
+		f.P("// Parameters:")
+		for _, arg := range args {
+			f.P("%s %s,", arg.param, arg.typ[1:])
+		}
+	}
+	var settable []anchor.IDLInstructionAccount
+	for _, acc := range accounts {
+		if acc.Address == "" {
+			settable = append(settable, acc)
+		}
+	}
+	if len(settable) > 0 {
+		f.P("// Accounts:")
+		for _, acc := range settable {
+			f.P("%s %s.PublicKey,", paramName(acc.Name), sol)
+		}
+	}
+	f.P(") *%s {", name)
+	calls := []string{fmt.Sprintf("New%sInstructionBuilder()", name)}
+	for _, arg := range args {
+		calls = append(calls, fmt.Sprintf("Set%s(%s)", arg.field, arg.param))
+	}
+	for _, acc := range settable {
+		calls = append(calls, fmt.Sprintf("Set%sAccount(%s)", goName(acc.Name), paramName(acc.Name)))
+	}
+	f.P("return %s", strings.Join(calls, ".\n"))
+	f.P("}")
+
+	return g.genInstructionTest(name)
+}
+
+func accountFlags(acc anchor.IDLInstructionAccount) string {
+	var flags []string
+	if acc.Writable {
+		flags = append(flags, "WRITE")
+	}
+	if acc.Signer {
+		flags = append(flags, "SIGNER")
+	}
+	if acc.Optional {
+		flags = append(flags, "OPTIONAL")
+	}
+	return strings.Join(flags, ", ")
+}
+
+func metaModifiers(acc anchor.IDLInstructionAccount) string {
+	var out string
+	if acc.Writable {
+		out += ".WRITE()"
+	}
+	if acc.Signer {
+		out += ".SIGNER()"
+	}
+	return out
+}
+
+func (g *generator) genInstructionTest(name string) error {
+	f := g.newFile(name + "_test.go")
+	f.use("bytes")
+	f.use("strconv")
+	f.use("testing")
+	bin, require := f.use(pkgBinary), f.use(pkgRequire)
+
+	f.P("func TestEncodeDecode_%s(t *testing.T) {", name)
+	f.P("fu := newFuzzer()")
+	f.P("for i := 0; i < 1; i++ {")
+	f.P("t.Run(%q+strconv.Itoa(i), func(t *testing.T) {", name)
+	f.P("{")
+	f.P("params := new(%s)", name)
+	f.P("fu.Fuzz(params)")
+	f.P("params.AccountMetaSlice = nil")
+	f.P("buf := new(bytes.Buffer)")
+	f.P("err := encodeT(*params, buf)")
+	f.P("%s.NoError(t, err)", require)
+	f.P("//")
+	f.P("got := new(%s)", name)
+	f.P("err = decodeT(got, buf.Bytes())")
+	f.P("got.AccountMetaSlice = nil")
+	f.P("%s.NoError(t, err)", require)
+	f.P("%s.Equal(t, params, got)", require)
+	f.P("}")
+	f.P("{")
+	f.P("params := new(%s)", name)
+	f.P("fu.Fuzz(params)")
+	f.P("params.AccountMetaSlice = nil")
+	f.P("inst := &Instruction{BaseVariant: %s.BaseVariant{", bin)
+	f.P("Impl:   *params,")
+	f.P("TypeID: Instruction_%s,", name)
+	f.P("}}")
+	f.P("data, err := inst.Data()")
+	f.P("%s.NoError(t, err)", require)
+	f.P("//")
+	f.P("got, err := DecodeInstruction(nil, data)")
+	f.P("%s.NoError(t, err)", require)
+	f.P("%s.Equal(t, params, got.Impl)", require)
+	f.P("}")
+	f.P("})")
+	f.P("}")
+	f.P("}")
+	return nil
+}
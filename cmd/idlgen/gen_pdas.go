@@ -0,0 +1,233 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/anchor"
+)
+
+// pdaDef is a program derived address with declared seeds.
+type pdaDef struct {
+	name  string
+	docs  []string
+	seeds []pdaSeed
+	// The program deriving the address; the ProgramID if zero.
+	program solana.PublicKey
+}
+
+// pdaSeed is either a constant seed (value) or a variable one (name and typ).
+type pdaSeed struct {
+	value []byte
+	name  string
+	typ   *anchor.IDLType
+}
+
+func (pda *pdaDef) equal(other *pdaDef) bool {
+	if pda.program != other.program || len(pda.seeds) != len(other.seeds) {
+		return false
+	}
+	for i := range pda.seeds {
+		a, b := pda.seeds[i], other.seeds[i]
+		if a.name != b.name || !bytes.Equal(a.value, b.value) {
+			return false
+		}
+		if a.typ != nil && b.typ != nil && a.typ.String() != b.typ.String() {
+			return false
+		}
+	}
+	return true
+}
+
+// collectPDAs collects the PDAs of the instruction accounts whose seeds can
+// all be computed from constants, arguments or other accounts; the seeds
+// reading the data of an account are not supported.
+// The PDAs are named after the account, or after the instruction and the
+// account if several instructions declare different seeds for the same name.
+func (g *generator) collectPDAs() []pdaDef {
+	var out []pdaDef
+	byName := make(map[string]int)
+	for i := range g.idl.Instructions {
+		inst := &g.idl.Instructions[i]
+		for _, acc := range inst.FlattenAccounts() {
+			if acc.PDA == nil {
+				continue
+			}
+			pda, ok := pdaFromSeeds(inst, acc)
+			if !ok {
+				continue
+			}
+			pda.name = acc.Name[strings.LastIndex(acc.Name, ".")+1:]
+			if j, exists := byName[pda.name]; exists {
+				if out[j].equal(&pda) {
+					continue
+				}
+				pda.name = inst.Name + "_" + pda.name
+				if _, exists := byName[pda.name]; exists {
+					continue
+				}
+			}
+			byName[pda.name] = len(out)
+			out = append(out, pda)
+		}
+	}
+	return out
+}
+
+func pdaFromSeeds(inst *anchor.IDLInstruction, acc anchor.IDLInstructionAccount) (pdaDef, bool) {
+	pda := pdaDef{docs: acc.Docs}
+	if program := acc.PDA.Program; program != nil {
+		if program.Kind != "const" || len(program.Value) != solana.PublicKeyLength {
+			return pda, false
+		}
+		pda.program = solana.PublicKeyFromBytes(program.Value)
+	}
+	for _, seed := range acc.PDA.Seeds {
+		switch seed.Kind {
+		case "const":
+			pda.seeds = append(pda.seeds, pdaSeed{value: seed.Value})
+		case "arg":
+			var typ *anchor.IDLType
+			for i := range inst.Args {
+				if inst.Args[i].Name == seed.Path {
+					typ = &inst.Args[i].Type
+				}
+			}
+			if typ == nil {
+				return pda, false
+			}
+			pda.seeds = append(pda.seeds, pdaSeed{name: seed.Path, typ: typ})
+		case "account":
+			if seed.Account != "" || strings.Contains(seed.Path, ".") {
+				return pda, false
+			}
+			pda.seeds = append(pda.seeds, pdaSeed{name: seed.Path, typ: &anchor.IDLType{Primitive: anchor.TypePubkey}})
+		default:
+			return pda, false
+		}
+	}
+	for _, seed := range pda.seeds {
+		if seed.typ != nil && seedExpr(seed.typ, "x") == "" {
+			return pda, false
+		}
+	}
+	return pda, true
+}
+
+// seedExpr returns the expression of the seed bytes of the variable v,
+// or "" if the type is not supported.
+func seedExpr(typ *anchor.IDLType, v string) string {
+	switch typ.Primitive {
+	case anchor.TypePubkey:
+		return v + "[:]"
+	case anchor.TypeString:
+		return "[]byte(" + v + ")"
+	case anchor.TypeBytes:
+		return v
+	case anchor.TypeU8:
+		return "[]byte{" + v + "}"
+	case anchor.TypeI8:
+		return "[]byte{byte(" + v + ")}"
+	case anchor.TypeU16, anchor.TypeU32, anchor.TypeU64:
+		return fmt.Sprintf("binary.LittleEndian.Append%s(nil, %s)", goName(strings.Replace(typ.Primitive, "u", "uint", 1)), v)
+	case anchor.TypeI16, anchor.TypeI32, anchor.TypeI64:
+		unsigned := strings.Replace(typ.Primitive, "i", "uint", 1)
+		return fmt.Sprintf("binary.LittleEndian.Append%s(nil, %s(%s))", goName(unsigned), unsigned, v)
+	}
+	if typ.Array != nil && typ.Array.Primitive == anchor.TypeU8 && typ.ArrayLenGeneric == "" {
+		return v + "[:]"
+	}
+	return ""
+}
+
+// constSeedExpr returns the expression of a constant seed.
+func constSeedExpr(value []byte) string {
+	printable := len(value) > 0
+	for _, b := range value {
+		if b < 0x20 || b > 0x7e {
+			printable = false
+		}
+	}
+	if printable {
+		return fmt.Sprintf("[]byte(%q)", value)
+	}
+	parts := make([]string, len(value))
+	for i, b := range value {
+		parts[i] = fmt.Sprint(b)
+	}
+	return "[]byte{" + strings.Join(parts, ", ") + "}"
+}
+
+func (g *generator) genPDAs() error {
+	if len(g.pdas) == 0 {
+		return nil
+	}
+	f := g.newFile("pdas.go")
+	sol := f.use(pkgSolana)
+	for i, pda := range g.pdas {
+		name := goName(pda.name)
+		var params []string
+		var seeds []string
+		taken := make(map[string]bool)
+		for _, seed := range pda.seeds {
+			if seed.typ == nil {
+				seeds = append(seeds, constSeedExpr(seed.value))
+				continue
+			}
+			param := paramName(seed.name)
+			typ, err := g.goType(f, seed.typ)
+			if err != nil {
+				return fmt.Errorf("pda %s: seed %s: %w", pda.name, seed.name, err)
+			}
+			expr := seedExpr(seed.typ, param)
+			if expr == "" {
+				return fmt.Errorf("pda %s: seed %s: unsupported type %s", pda.name, seed.name, seed.typ)
+			}
+			if strings.HasPrefix(expr, "binary.") {
+				f.use("encoding/binary")
+			}
+			if !taken[param] {
+				taken[param] = true
+				params = append(params, param+" "+typ)
+			}
+			seeds = append(seeds, expr)
+		}
+		program := "ProgramID"
+		if !pda.program.IsZero() {
+			program = fmt.Sprintf("%s.MustPublicKeyFromBase58(%q)", sol, pda.program.String())
+		}
+
+		if i > 0 {
+			f.P("")
+		}
+		f.P("// Find%sAddress finds the `%s` program derived address.", name, pda.name)
+		writeDocs(f, pda.docs)
+		f.P("func Find%sAddress(%s) (%s.PublicKey, uint8, error) {", name, strings.Join(params, ", "), sol)
+		f.P("return %s.FindProgramAddress(", sol)
+		f.P("[][]byte{")
+		for _, seed := range seeds {
+			f.P("%s,", seed)
+		}
+		f.P("},")
+		f.P("%s,", program)
+		f.P(")")
+		f.P("}")
+	}
+	return nil
+}
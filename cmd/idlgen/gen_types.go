@@ -0,0 +1,245 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go/anchor"
+)
+
+// structField is a field of a generated struct.
+type structField struct {
+	name string
+	docs []string
+	typ  *anchor.IDLType
+}
+
+// structFields returns the fields of a struct or enum variant;
+// the tuple fields are named V0, V1, ...
+func structFields(fields *anchor.IDLDefinedFields) []structField {
+	if fields == nil {
+		return nil
+	}
+	var out []structField
+	for i := range fields.Named {
+		out = append(out, structField{
+			name: goName(fields.Named[i].Name),
+			docs: fields.Named[i].Docs,
+			typ:  &fields.Named[i].Type,
+		})
+	}
+	for i := range fields.Tuple {
+		out = append(out, structField{
+			name: fmt.Sprintf("V%d", i),
+			typ:  &fields.Tuple[i],
+		})
+	}
+	return out
+}
+
+// writeStruct writes the declaration of a struct with the provided fields.
+func (g *generator) writeStruct(f *file, name string, fields []structField) error {
+	f.P("type %s struct {", name)
+	for _, field := range fields {
+		typ, tag, err := g.fieldType(f, field.typ)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.name, err)
+		}
+		writeDocs(f, field.docs)
+		f.P("%s %s %s", field.name, typ, tag)
+	}
+	f.P("}")
+	return nil
+}
+
+func checkSerialization(def *anchor.IDLTypeDef) error {
+	if def.Serialization != "" && def.Serialization != "borsh" {
+		return fmt.Errorf("type %s: unsupported %s serialization", def.Name, def.Serialization)
+	}
+	return nil
+}
+
+// isUnitEnum tells whether none of the variants of the enum has fields.
+func isUnitEnum(def *anchor.IDLTypeDef) bool {
+	for _, variant := range def.Type.Variants {
+		if variant.Fields != nil && (len(variant.Fields.Named) > 0 || len(variant.Fields.Tuple) > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) genTypes() error {
+	f := g.newFile("types.go")
+	for i := range g.idl.Types {
+		def := &g.idl.Types[i]
+		// The accounts are declared along with their discriminator,
+		// and the generic types can't be declared without being instantiated.
+		if g.accounts[def.Name] || len(def.Generics) > 0 {
+			continue
+		}
+		if err := checkSerialization(def); err != nil {
+			return err
+		}
+		name := goName(def.Name)
+		writeDocs(f, def.Docs)
+		switch def.Type.Kind {
+		case anchor.TypeDefKindStruct:
+			if err := g.writeStruct(f, name, structFields(def.Type.Fields)); err != nil {
+				return fmt.Errorf("type %s: %w", def.Name, err)
+			}
+		case anchor.TypeDefKindEnum:
+			if isUnitEnum(def) {
+				g.writeUnitEnum(f, name, def)
+			} else if err := g.writeComplexEnum(f, name, def); err != nil {
+				return fmt.Errorf("type %s: %w", def.Name, err)
+			}
+		case anchor.TypeDefKindType:
+			typ, err := g.goType(f, def.Type.Alias)
+			if err != nil {
+				return fmt.Errorf("type %s: %w", def.Name, err)
+			}
+			f.P("type %s %s", name, typ)
+		default:
+			return fmt.Errorf("type %s: unsupported kind %q", def.Name, def.Type.Kind)
+		}
+		f.P("")
+	}
+	return nil
+}
+
+func (g *generator) writeUnitEnum(f *file, name string, def *anchor.IDLTypeDef) {
+	f.P("type %s %s.BorshEnum", name, f.use(pkgBinary))
+	f.P("")
+	f.P("const (")
+	for i, variant := range def.Type.Variants {
+		if i == 0 {
+			f.P("%s%s %s = iota", name, goName(variant.Name), name)
+		} else {
+			f.P("%s%s", name, goName(variant.Name))
+		}
+	}
+	f.P(")")
+	f.P("")
+	f.P("func (value %s) String() string {", name)
+	f.P("switch value {")
+	for _, variant := range def.Type.Variants {
+		f.P("case %s%s:", name, goName(variant.Name))
+		f.P("return %q", goName(variant.Name))
+	}
+	f.P("default:")
+	f.P("return \"\"")
+	f.P("}")
+	f.P("}")
+}
+
+func (g *generator) writeComplexEnum(f *file, name string, def *anchor.IDLTypeDef) error {
+	bin := f.use(pkgBinary)
+	f.P("type %s struct {", name)
+	f.P("Enum %s.BorshEnum `borsh_enum:\"true\"`", bin)
+	for _, variant := range def.Type.Variants {
+		if len(structFields(variant.Fields)) == 0 {
+			f.P("%s %s.EmptyVariant", goName(variant.Name), bin)
+		} else {
+			f.P("%s %s%sFields", goName(variant.Name), name, goName(variant.Name))
+		}
+	}
+	f.P("}")
+	f.P("")
+	f.P("// The variants of %s, set in its Enum field.", name)
+	f.P("const (")
+	for i, variant := range def.Type.Variants {
+		if i == 0 {
+			f.P("%s%s %s.BorshEnum = iota", name, goName(variant.Name), bin)
+		} else {
+			f.P("%s%s", name, goName(variant.Name))
+		}
+	}
+	f.P(")")
+	for _, variant := range def.Type.Variants {
+		fields := structFields(variant.Fields)
+		if len(fields) == 0 {
+			continue
+		}
+		f.P("")
+		if err := g.writeStruct(f, name+goName(variant.Name)+"Fields", fields); err != nil {
+			return fmt.Errorf("variant %s: %w", variant.Name, err)
+		}
+	}
+	return nil
+}
+
+// fuzzFuncs returns the gofuzz functions generating valid enum values.
+func (g *generator) fuzzFuncs(f *file) []string {
+	fuzz := f.use(pkgGofuzz)
+	var out []string
+	for i := range g.idl.Types {
+		def := &g.idl.Types[i]
+		if def.Type.Kind != anchor.TypeDefKindEnum || len(def.Generics) > 0 {
+			continue
+		}
+		name := goName(def.Name)
+		if isUnitEnum(def) {
+			out = append(out, fmt.Sprintf("func(obj *%s, c %s.Continue) {\n*obj = %s(c.Intn(%d))\n}", name, fuzz, name, len(def.Type.Variants)))
+			continue
+		}
+		body := fmt.Sprintf("func(obj *%s, c %s.Continue) {\n*obj = %s{Enum: %s.BorshEnum(c.Intn(%d))}\nswitch obj.Enum {\n", name, fuzz, name, f.use(pkgBinary), len(def.Type.Variants))
+		for _, variant := range def.Type.Variants {
+			if len(structFields(variant.Fields)) == 0 {
+				continue
+			}
+			body += fmt.Sprintf("case %s%s:\nc.Fuzz(&obj.%s)\n", name, goName(variant.Name), goName(variant.Name))
+		}
+		body += "}\n}"
+		out = append(out, body)
+	}
+	return out
+}
+
+func (g *generator) genTestingUtils() error {
+	f := g.newFile("testing_utils.go")
+	bin := f.use(pkgBinary)
+	f.use("bytes")
+	f.use("fmt")
+	f.P("func encodeT(data interface{}, buf *bytes.Buffer) error {")
+	f.P("if err := %s.NewBorshEncoder(buf).Encode(data); err != nil {", bin)
+	f.P("return fmt.Errorf(\"unable to encode instruction: %%w\", err)")
+	f.P("}")
+	f.P("return nil")
+	f.P("}")
+	f.P("")
+	f.P("func decodeT(dst interface{}, data []byte) error {")
+	f.P("return %s.NewBorshDecoder(data).Decode(dst)", bin)
+	f.P("}")
+
+	t := g.newFile("fuzzer_test.go")
+	fuzz := t.use(pkgGofuzz)
+	funcs := g.fuzzFuncs(t)
+	t.P("// newFuzzer returns a fuzzer generating values that can be round-tripped;")
+	t.P("// the enums only take the values of their variants.")
+	t.P("func newFuzzer() *%s.Fuzzer {", fuzz)
+	if len(funcs) == 0 {
+		t.P("return %s.New().NilChance(0)", fuzz)
+	} else {
+		t.P("return %s.New().NilChance(0).Funcs(", fuzz)
+		for _, fn := range funcs {
+			t.P("%s,", fn)
+		}
+		t.P(")")
+	}
+	t.P("}")
+	return nil
+}
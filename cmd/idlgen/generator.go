@@ -0,0 +1,431 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go/anchor"
+)
+
+const (
+	pkgBinary  = "github.com/gagliardetto/binary"
+	pkgSolana  = "github.com/gagliardetto/solana-go"
+	pkgFormat  = "github.com/gagliardetto/solana-go/text/format"
+	pkgText    = "github.com/gagliardetto/solana-go/text"
+	pkgTreeout = "github.com/gagliardetto/treeout"
+	pkgSpew    = "github.com/davecgh/go-spew/spew"
+	pkgGofuzz  = "github.com/gagliardetto/gofuzz"
+	pkgRequire = "github.com/stretchr/testify/require"
+)
+
+// The aliases of the imported packages, following the hand-written program clients.
+var importAliases = map[string]string{
+	pkgBinary:  "ag_binary",
+	pkgSolana:  "ag_solanago",
+	pkgFormat:  "ag_format",
+	pkgText:    "ag_text",
+	pkgTreeout: "ag_treeout",
+	pkgSpew:    "ag_spew",
+	pkgGofuzz:  "ag_gofuzz",
+	pkgRequire: "ag_require",
+}
+
+// file accumulates the body of a generated file and the imports it uses.
+type file struct {
+	name    string
+	doc     []string
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+func newFile(name string) *file {
+	return &file{
+		name:    name,
+		imports: make(map[string]bool),
+	}
+}
+
+// P writes a line.
+func (f *file) P(format string, args ...interface{}) {
+	fmt.Fprintf(&f.body, format, args...)
+	f.body.WriteByte('\n')
+}
+
+// use imports the provided package, returning its alias (or name).
+func (f *file) use(path string) string {
+	f.imports[path] = true
+	if alias, ok := importAliases[path]; ok {
+		return alias
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func (f *file) render(pkg string) ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteString("// Code generated by idlgen. DO NOT EDIT.\n\n")
+	for _, line := range f.doc {
+		fmt.Fprintf(&out, "// %s\n", strings.TrimSpace(line))
+	}
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	if len(f.imports) > 0 {
+		var std, other []string
+		for path := range f.imports {
+			if strings.Contains(path, ".") {
+				other = append(other, path)
+			} else {
+				std = append(std, path)
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(other)
+		out.WriteString("import (\n")
+		for _, path := range std {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		if len(std) > 0 && len(other) > 0 {
+			out.WriteString("\n")
+		}
+		for _, path := range other {
+			if alias, ok := importAliases[path]; ok {
+				fmt.Fprintf(&out, "\t%s %q\n", alias, path)
+			} else {
+				fmt.Fprintf(&out, "\t%q\n", path)
+			}
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(f.body.Bytes())
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format %s: %w\n%s", f.name, err, out.Bytes())
+	}
+	return formatted, nil
+}
+
+type generator struct {
+	idl  *anchor.IDL
+	pkg  string
+	pdas []pdaDef
+
+	// Go names of the instruction structs, by instruction name.
+	instructionTypes map[string]string
+	// Names of the type definitions generated as accounts.
+	accounts map[string]bool
+
+	files []*file
+}
+
+// Generate generates the Go client of the program described by the IDL,
+// returning the generated files by name. If pdas is nil, the PDAs are
+// collected from the seeds of the instruction accounts.
+func Generate(idl *anchor.IDL, pkg string, pdas []pdaDef) (map[string][]byte, error) {
+	g := &generator{
+		idl:              idl,
+		pkg:              pkg,
+		pdas:             pdas,
+		instructionTypes: make(map[string]string),
+		accounts:         make(map[string]bool),
+	}
+	if pdas == nil {
+		g.pdas = g.collectPDAs()
+	}
+	for _, account := range idl.Accounts {
+		g.accounts[account.Name] = true
+	}
+	taken := make(map[string]bool)
+	for _, def := range idl.Types {
+		taken[goName(def.Name)] = true
+	}
+	for _, inst := range idl.Instructions {
+		name := goName(inst.Name)
+		if taken[name] || reservedNames[name] {
+			name += "Instruction"
+		}
+		g.instructionTypes[inst.Name] = name
+	}
+
+	steps := []func() error{
+		g.genInstructions,
+		g.genAccounts,
+		g.genTypes,
+		g.genEvents,
+		g.genErrors,
+		g.genPDAs,
+		g.genTestingUtils,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make(map[string][]byte, len(g.files))
+	for _, f := range g.files {
+		rendered, err := f.render(pkg)
+		if err != nil {
+			return nil, err
+		}
+		out[f.name] = rendered
+	}
+	return out, nil
+}
+
+func (g *generator) newFile(name string) *file {
+	f := newFile(name)
+	g.files = append(g.files, f)
+	return f
+}
+
+// The identifiers declared by the generated code.
+var reservedNames = map[string]bool{
+	"Instruction":         true,
+	"InstructionIDToName": true,
+	"DecodeInstruction":   true,
+	"DecodeEvent":         true,
+	"ProgramError":        true,
+	"ErrorFromCode":       true,
+	"ProgramID":           true,
+	"ProgramName":         true,
+	"SetProgramID":        true,
+}
+
+// goName returns the exported Go name of an IDL name.
+func goName(name string) string {
+	return bin.ToPascalCase(strings.ReplaceAll(name, ".", "_"))
+}
+
+// paramName returns the unexported Go name of an IDL name,
+// usable as a function parameter.
+func paramName(name string) string {
+	runes := []rune(goName(name))
+	// Lower the leading initialism, but its last letter if it starts a word ("URLPath" -> "urlPath"):
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	out := string(runes)
+	if token.IsKeyword(out) || out == "" {
+		out += "_"
+	}
+	return out
+}
+
+// goType returns the Go type of a (non-optional) IDL type.
+func (g *generator) goType(f *file, typ *anchor.IDLType) (string, error) {
+	switch {
+	case typ.Primitive != "":
+		switch typ.Primitive {
+		case anchor.TypeBool:
+			return "bool", nil
+		case anchor.TypeU8:
+			return "uint8", nil
+		case anchor.TypeI8:
+			return "int8", nil
+		case anchor.TypeU16:
+			return "uint16", nil
+		case anchor.TypeI16:
+			return "int16", nil
+		case anchor.TypeU32:
+			return "uint32", nil
+		case anchor.TypeI32:
+			return "int32", nil
+		case anchor.TypeF32:
+			return "float32", nil
+		case anchor.TypeU64:
+			return "uint64", nil
+		case anchor.TypeI64:
+			return "int64", nil
+		case anchor.TypeF64:
+			return "float64", nil
+		case anchor.TypeU128:
+			return f.use(pkgBinary) + ".Uint128", nil
+		case anchor.TypeI128:
+			return f.use(pkgBinary) + ".Int128", nil
+		case anchor.TypeBytes:
+			return "[]byte", nil
+		case anchor.TypeString:
+			return "string", nil
+		case anchor.TypePubkey:
+			return f.use(pkgSolana) + ".PublicKey", nil
+		default:
+			return "", fmt.Errorf("unsupported type %s", typ.Primitive)
+		}
+	case typ.Option != nil, typ.COption != nil:
+		return "", fmt.Errorf("unsupported nested optional type %s", typ)
+	case typ.Vec != nil:
+		elem, err := g.goType(f, typ.Vec)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case typ.Array != nil:
+		if typ.ArrayLenGeneric != "" {
+			return "", fmt.Errorf("unsupported generic array length %s", typ)
+		}
+		elem, err := g.goType(f, typ.Array)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("[%d]%s", typ.ArrayLen, elem), nil
+	case typ.Defined != nil:
+		if len(typ.Defined.Generics) > 0 {
+			return "", fmt.Errorf("unsupported generic type %s", typ.Defined.Name)
+		}
+		if _, ok := g.idl.TypeDef(typ.Defined.Name); !ok {
+			return "", fmt.Errorf("unknown type %s", typ.Defined.Name)
+		}
+		return goName(typ.Defined.Name), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+// fieldType returns the Go type and the struct tag of a field,
+// representing the optional values as pointers.
+func (g *generator) fieldType(f *file, typ *anchor.IDLType) (string, string, error) {
+	switch {
+	case typ.Option != nil:
+		inner, err := g.goType(f, typ.Option)
+		return "*" + inner, "`bin:\"optional\"`", err
+	case typ.COption != nil:
+		inner, err := g.goType(f, typ.COption)
+		return "*" + inner, "`bin:\"coption\"`", err
+	default:
+		inner, err := g.goType(f, typ)
+		return inner, "", err
+	}
+}
+
+// optionKind returns "option", "coption" or "" for the non-optional types.
+func optionKind(typ *anchor.IDLType) string {
+	switch {
+	case typ.Option != nil:
+		return "option"
+	case typ.COption != nil:
+		return "coption"
+	default:
+		return ""
+	}
+}
+
+func optionInner(typ *anchor.IDLType) *anchor.IDLType {
+	switch {
+	case typ.Option != nil:
+		return typ.Option
+	case typ.COption != nil:
+		return typ.COption
+	default:
+		return typ
+	}
+}
+
+// writeDocs writes the IDL docs as a comment.
+func writeDocs(f *file, docs []string) {
+	for _, line := range docs {
+		f.P("// %s", strings.TrimSpace(line))
+	}
+}
+
+// writeEncodeField writes the code serializing the field `obj.<name>`.
+func writeEncodeField(f *file, name string, typ *anchor.IDLType) {
+	switch kind := optionKind(typ); kind {
+	case "option", "coption":
+		write := "WriteOption"
+		if kind == "coption" {
+			write = "WriteCOption"
+		}
+		f.P("// Serialize `%s` (optional):", name)
+		f.P("{")
+		f.P("if obj.%s == nil {", name)
+		f.P("err = encoder.%s(false)", write)
+		f.P("if err != nil {\nreturn err\n}")
+		f.P("} else {")
+		f.P("err = encoder.%s(true)", write)
+		f.P("if err != nil {\nreturn err\n}")
+		f.P("err = encoder.Encode(obj.%s)", name)
+		f.P("if err != nil {\nreturn err\n}")
+		f.P("}")
+		f.P("}")
+	default:
+		f.P("// Serialize `%s`:", name)
+		f.P("err = encoder.Encode(obj.%s)", name)
+		f.P("if err != nil {\nreturn err\n}")
+	}
+}
+
+// writeDecodeField writes the code deserializing the field `obj.<name>`.
+func writeDecodeField(f *file, name string, typ *anchor.IDLType) {
+	switch kind := optionKind(typ); kind {
+	case "option", "coption":
+		read := "ReadOption"
+		if kind == "coption" {
+			read = "ReadCOption"
+		}
+		f.P("// Deserialize `%s` (optional):", name)
+		f.P("{")
+		f.P("ok, err := decoder.%s()", read)
+		f.P("if err != nil {\nreturn err\n}")
+		f.P("if ok {")
+		f.P("err = decoder.Decode(&obj.%s)", name)
+		f.P("if err != nil {\nreturn err\n}")
+		f.P("}")
+		f.P("}")
+	default:
+		f.P("// Deserialize `%s`:", name)
+		f.P("err = decoder.Decode(&obj.%s)", name)
+		f.P("if err != nil {\nreturn err\n}")
+	}
+}
+
+// byteArrayLiteral formats a discriminator as a [n]byte literal.
+func byteArrayLiteral(b []byte) string {
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = fmt.Sprint(b[i])
+	}
+	return fmt.Sprintf("[%d]byte{%s}", len(b), strings.Join(parts, ", "))
+}
+
+func checkDiscriminator(kind string, name string, discriminator []byte) error {
+	if len(discriminator) != 8 {
+		return fmt.Errorf("%s %s: only 8-byte discriminators are supported, got %d bytes", kind, name, len(discriminator))
+	}
+	return nil
+}
+
+// padNames right-aligns the names, like the hand-written EncodeToTree methods.
+func padNames(names []string) []string {
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = strings.Repeat(" ", width-len(name)) + name
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the generated package in testdata/vault")
+
+const goldenDir = "testdata/vault"
+
+func generateFile(t *testing.T, idlPath string) map[string][]byte {
+	t.Helper()
+	data, err := os.ReadFile(idlPath)
+	require.NoError(t, err)
+	idl, pdas, err := parseIDL(data)
+	require.NoError(t, err)
+	files, err := Generate(idl, "vault", pdas)
+	require.NoError(t, err)
+	return files
+}
+
+func TestGenerate_Anchor(t *testing.T) {
+	files := generateFile(t, "testdata/vault.json")
+
+	if *update {
+		require.NoError(t, os.RemoveAll(goldenDir))
+		require.NoError(t, run("testdata/vault.json", goldenDir, "vault"))
+	}
+
+	entries, err := os.ReadDir(goldenDir)
+	require.NoError(t, err)
+	var golden []string
+	for _, entry := range entries {
+		golden = append(golden, entry.Name())
+	}
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	require.Equal(t, golden, names)
+
+	for _, name := range names {
+		expected, err := os.ReadFile(filepath.Join(goldenDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(files[name]), name)
+	}
+}
+
+func TestGenerate_Codama(t *testing.T) {
+	files := generateFile(t, "testdata/vault.codama.json")
+
+	// Codama has no composite accounts nor events, and declares the PDAs apart:
+	// the rest matches the client generated from the Anchor IDL.
+	require.NotContains(t, files, "events.go")
+	for _, name := range []string{
+		"types.go",
+		"accounts.go",
+		"errors.go",
+		"pdas.go",
+		"Deposit.go",
+		"SetAuthority.go",
+		"Close.go",
+	} {
+		expected, err := os.ReadFile(filepath.Join(goldenDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(files[name]), name)
+	}
+	assert.Contains(t, string(files["Initialize.go"]), "func (inst *Initialize) SetSystemProgramAccount(")
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	data, err := os.ReadFile("../../anchor/testdata/counter.json")
+	require.NoError(t, err)
+	idl, pdas, err := parseIDL(data)
+	require.NoError(t, err)
+	_, err = Generate(idl, "counter", pdas)
+	require.EqualError(t, err, "account Counter: field History: unsupported generic type Ring")
+}
+
+// TestGenerate_Build builds and tests the generated package.
+func TestGenerate_Build(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+	for _, args := range [][]string{
+		{"vet", "./" + goldenDir},
+		{"test", "-count=1", "./" + goldenDir},
+	} {
+		out, err := exec.Command(goBin, args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestNames(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		goN   string
+		param string
+	}{
+		{"vault_id", "VaultId", "vaultId"},
+		{"system.system_program", "SystemSystemProgram", "systemSystemProgram"},
+		{"Mode", "Mode", "mode"},
+		{"type", "Type", "type_"},
+		{"initialize_mint2", "InitializeMint2", "initializeMint2"},
+		{"URLPath", "UrlPath", "urlPath"},
+	} {
+		assert.Equal(t, tt.goN, goName(tt.name), tt.name)
+		assert.Equal(t, tt.param, paramName(tt.name), tt.name)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command idlgen generates the Go client of a Solana program from its
+// Anchor IDL (legacy or 0.30+) or Codama IDL, in the style of the
+// packages in programs/: instruction builders, account structs with their
+// discriminators, types, events, error codes, PDA helpers and round-trip tests.
+//
+// Usage:
+//
+//	idlgen -idl path/to/idl.json -out path/to/package [-pkg name]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gagliardetto/solana-go/anchor"
+)
+
+func main() {
+	idlPath := flag.String("idl", "", "path of the Anchor or Codama IDL (JSON)")
+	outDir := flag.String("out", "", "directory of the generated package")
+	pkg := flag.String("pkg", "", "name of the generated package (default: the program name)")
+	flag.Parse()
+
+	if *idlPath == "" || *outDir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*idlPath, *outDir, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "idlgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(idlPath string, outDir string, pkg string) error {
+	data, err := os.ReadFile(idlPath)
+	if err != nil {
+		return err
+	}
+	idl, pdas, err := parseIDL(data)
+	if err != nil {
+		return err
+	}
+	if pkg == "" {
+		pkg = packageName(idl.Metadata.Name)
+	}
+	files, err := Generate(idl, pkg, pdas)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(outDir, name), files[name], 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseIDL parses an Anchor or Codama IDL; the PDAs are only returned for
+// the Codama IDLs, which declare them apart from the instructions.
+func parseIDL(data []byte) (*anchor.IDL, []pdaDef, error) {
+	if isCodama(data) {
+		return parseCodama(data)
+	}
+	idl, err := anchor.ParseIDL(data)
+	return idl, nil, err
+}
+
+// packageName returns a package name from the program name.
+func packageName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("_", "", "-", "", " ", "").Replace(name)
+	if name == "" {
+		return "program"
+	}
+	return name
+}
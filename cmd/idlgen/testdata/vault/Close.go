@@ -0,0 +1,135 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Close is the `close` instruction.
+type Close struct {
+	// [0] = [WRITE] vault
+	//
+	// [1] = [SIGNER] authority
+	//
+	// [2] = [WRITE] receiver
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewCloseInstructionBuilder creates a new `Close` instruction builder.
+func NewCloseInstructionBuilder() *Close {
+	nd := &Close{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 3),
+	}
+	return nd
+}
+
+// SetVaultAccount sets the "vault" account.
+func (inst *Close) SetVaultAccount(vault ag_solanago.PublicKey) *Close {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(vault).WRITE()
+	return inst
+}
+
+// GetVaultAccount gets the "vault" account.
+func (inst *Close) GetVaultAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+func (inst *Close) SetAuthorityAccount(authority ag_solanago.PublicKey) *Close {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(authority).SIGNER()
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+func (inst *Close) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetReceiverAccount sets the "receiver" account.
+func (inst *Close) SetReceiverAccount(receiver ag_solanago.PublicKey) *Close {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(receiver).WRITE()
+	return inst
+}
+
+// GetReceiverAccount gets the "receiver" account.
+func (inst *Close) GetReceiverAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+func (inst Close) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: Instruction_Close,
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Close) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Close) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Vault is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Receiver is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Close) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Close")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("    vault", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("authority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta(" receiver", inst.AccountMetaSlice.Get(2)))
+					})
+				})
+		})
+}
+
+func (obj Close) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+
+func (obj *Close) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewCloseInstruction declares a new Close instruction with the provided parameters and accounts.
+func NewCloseInstruction(
+	// Accounts:
+	vault ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	receiver ag_solanago.PublicKey,
+) *Close {
+	return NewCloseInstructionBuilder().
+		SetVaultAccount(vault).
+		SetAuthorityAccount(authority).
+		SetReceiverAccount(receiver)
+}
@@ -0,0 +1,282 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Deposit is the `deposit` instruction.
+type Deposit struct {
+	VaultId *uint64
+	Amounts *[]uint64
+	Tag     *[4]uint8
+	Weight  *ag_binary.Uint128
+	Delta   *int64
+	Memo    *[]byte `bin:"optional"`
+
+	// [0] = [WRITE] vault
+	//
+	// [1] = [WRITE, SIGNER] depositor
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewDepositInstructionBuilder creates a new `Deposit` instruction builder.
+func NewDepositInstructionBuilder() *Deposit {
+	nd := &Deposit{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 2),
+	}
+	return nd
+}
+
+// SetVaultId sets the "vault_id" parameter.
+func (inst *Deposit) SetVaultId(vaultId uint64) *Deposit {
+	inst.VaultId = &vaultId
+	return inst
+}
+
+// SetAmounts sets the "amounts" parameter.
+func (inst *Deposit) SetAmounts(amounts []uint64) *Deposit {
+	inst.Amounts = &amounts
+	return inst
+}
+
+// SetTag sets the "tag" parameter.
+func (inst *Deposit) SetTag(tag [4]uint8) *Deposit {
+	inst.Tag = &tag
+	return inst
+}
+
+// SetWeight sets the "weight" parameter.
+func (inst *Deposit) SetWeight(weight ag_binary.Uint128) *Deposit {
+	inst.Weight = &weight
+	return inst
+}
+
+// SetDelta sets the "delta" parameter.
+func (inst *Deposit) SetDelta(delta int64) *Deposit {
+	inst.Delta = &delta
+	return inst
+}
+
+// SetMemo sets the "memo" parameter.
+func (inst *Deposit) SetMemo(memo []byte) *Deposit {
+	inst.Memo = &memo
+	return inst
+}
+
+// SetVaultAccount sets the "vault" account.
+func (inst *Deposit) SetVaultAccount(vault ag_solanago.PublicKey) *Deposit {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(vault).WRITE()
+	return inst
+}
+
+// GetVaultAccount gets the "vault" account.
+func (inst *Deposit) GetVaultAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetDepositorAccount sets the "depositor" account.
+func (inst *Deposit) SetDepositorAccount(depositor ag_solanago.PublicKey) *Deposit {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(depositor).WRITE().SIGNER()
+	return inst
+}
+
+// GetDepositorAccount gets the "depositor" account.
+func (inst *Deposit) GetDepositorAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+func (inst Deposit) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: Instruction_Deposit,
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Deposit) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Deposit) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.VaultId == nil {
+			return errors.New("VaultId parameter is not set")
+		}
+		if inst.Amounts == nil {
+			return errors.New("Amounts parameter is not set")
+		}
+		if inst.Tag == nil {
+			return errors.New("Tag parameter is not set")
+		}
+		if inst.Weight == nil {
+			return errors.New("Weight parameter is not set")
+		}
+		if inst.Delta == nil {
+			return errors.New("Delta parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Vault is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Depositor is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Deposit) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Deposit")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("   VaultId", *inst.VaultId))
+						paramsBranch.Child(ag_format.Param("   Amounts", *inst.Amounts))
+						paramsBranch.Child(ag_format.Param("       Tag", *inst.Tag))
+						paramsBranch.Child(ag_format.Param("    Weight", *inst.Weight))
+						paramsBranch.Child(ag_format.Param("     Delta", *inst.Delta))
+						paramsBranch.Child(ag_format.Param("Memo (OPT)", inst.Memo))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("    vault", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("depositor", inst.AccountMetaSlice.Get(1)))
+					})
+				})
+		})
+}
+
+func (obj Deposit) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `VaultId`:
+	err = encoder.Encode(obj.VaultId)
+	if err != nil {
+		return err
+	}
+	// Serialize `Amounts`:
+	err = encoder.Encode(obj.Amounts)
+	if err != nil {
+		return err
+	}
+	// Serialize `Tag`:
+	err = encoder.Encode(obj.Tag)
+	if err != nil {
+		return err
+	}
+	// Serialize `Weight`:
+	err = encoder.Encode(obj.Weight)
+	if err != nil {
+		return err
+	}
+	// Serialize `Delta`:
+	err = encoder.Encode(obj.Delta)
+	if err != nil {
+		return err
+	}
+	// Serialize `Memo` (optional):
+	{
+		if obj.Memo == nil {
+			err = encoder.WriteOption(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteOption(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.Memo)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (obj *Deposit) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `VaultId`:
+	err = decoder.Decode(&obj.VaultId)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Amounts`:
+	err = decoder.Decode(&obj.Amounts)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Tag`:
+	err = decoder.Decode(&obj.Tag)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Weight`:
+	err = decoder.Decode(&obj.Weight)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Delta`:
+	err = decoder.Decode(&obj.Delta)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Memo` (optional):
+	{
+		ok, err := decoder.ReadOption()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.Memo)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewDepositInstruction declares a new Deposit instruction with the provided parameters and accounts.
+func NewDepositInstruction(
+	// Parameters:
+	vaultId uint64,
+	amounts []uint64,
+	tag [4]uint8,
+	weight ag_binary.Uint128,
+	delta int64,
+	memo []byte,
+	// Accounts:
+	vault ag_solanago.PublicKey,
+	depositor ag_solanago.PublicKey,
+) *Deposit {
+	return NewDepositInstructionBuilder().
+		SetVaultId(vaultId).
+		SetAmounts(amounts).
+		SetTag(tag).
+		SetWeight(weight).
+		SetDelta(delta).
+		SetMemo(memo).
+		SetVaultAccount(vault).
+		SetDepositorAccount(depositor)
+}
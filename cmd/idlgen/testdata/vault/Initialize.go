@@ -0,0 +1,319 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Creates a vault.
+type Initialize struct {
+	VaultId *uint64
+	Name    *string
+	FeeBps  *uint16 `bin:"optional"`
+	Mode    *Mode
+	Limits  *Limits
+
+	// [0] = [WRITE] vault
+	//
+	// [1] = [WRITE, SIGNER] authority
+	// ··········· Pays for the vault.
+	//
+	// [2] = [OPTIONAL] referrer
+	//
+	// [3] = [] system.config
+	//
+	// [4] = [] system.system_program
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeInstructionBuilder creates a new `Initialize` instruction builder.
+func NewInitializeInstructionBuilder() *Initialize {
+	nd := &Initialize{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 5),
+	}
+	nd.AccountMetaSlice[4] = ag_solanago.Meta(ag_solanago.MustPublicKeyFromBase58("11111111111111111111111111111111"))
+	return nd
+}
+
+// SetVaultId sets the "vault_id" parameter.
+func (inst *Initialize) SetVaultId(vaultId uint64) *Initialize {
+	inst.VaultId = &vaultId
+	return inst
+}
+
+// SetName sets the "name" parameter.
+func (inst *Initialize) SetName(name string) *Initialize {
+	inst.Name = &name
+	return inst
+}
+
+// SetFeeBps sets the "fee_bps" parameter.
+func (inst *Initialize) SetFeeBps(feeBps uint16) *Initialize {
+	inst.FeeBps = &feeBps
+	return inst
+}
+
+// SetMode sets the "mode" parameter.
+func (inst *Initialize) SetMode(mode Mode) *Initialize {
+	inst.Mode = &mode
+	return inst
+}
+
+// SetLimits sets the "limits" parameter.
+func (inst *Initialize) SetLimits(limits Limits) *Initialize {
+	inst.Limits = &limits
+	return inst
+}
+
+// SetVaultAccount sets the "vault" account.
+func (inst *Initialize) SetVaultAccount(vault ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(vault).WRITE()
+	return inst
+}
+
+// GetVaultAccount gets the "vault" account.
+func (inst *Initialize) GetVaultAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+// Pays for the vault.
+func (inst *Initialize) SetAuthorityAccount(authority ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(authority).WRITE().SIGNER()
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+// Pays for the vault.
+func (inst *Initialize) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetReferrerAccount sets the "referrer" account.
+func (inst *Initialize) SetReferrerAccount(referrer ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(referrer)
+	return inst
+}
+
+// GetReferrerAccount gets the "referrer" account.
+func (inst *Initialize) GetReferrerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetSystemConfigAccount sets the "system.config" account.
+func (inst *Initialize) SetSystemConfigAccount(systemConfig ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(systemConfig)
+	return inst
+}
+
+// GetSystemConfigAccount gets the "system.config" account.
+func (inst *Initialize) GetSystemConfigAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetSystemSystemProgramAccount sets the "system.system_program" account.
+func (inst *Initialize) SetSystemSystemProgramAccount(systemSystemProgram ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(systemSystemProgram)
+	return inst
+}
+
+// GetSystemSystemProgramAccount gets the "system.system_program" account.
+func (inst *Initialize) GetSystemSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+func (inst Initialize) Build() *Instruction {
+	// The program ID stands for the optional accounts that are not set:
+	if inst.AccountMetaSlice[2] == nil {
+		inst.AccountMetaSlice[2] = ag_solanago.Meta(ProgramID)
+	}
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: Instruction_Initialize,
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Initialize) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Initialize) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.VaultId == nil {
+			return errors.New("VaultId parameter is not set")
+		}
+		if inst.Name == nil {
+			return errors.New("Name parameter is not set")
+		}
+		if inst.Mode == nil {
+			return errors.New("Mode parameter is not set")
+		}
+		if inst.Limits == nil {
+			return errors.New("Limits parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Vault is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.SystemConfig is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.SystemSystemProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Initialize) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Initialize")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("     VaultId", *inst.VaultId))
+						paramsBranch.Child(ag_format.Param("        Name", *inst.Name))
+						paramsBranch.Child(ag_format.Param("FeeBps (OPT)", inst.FeeBps))
+						paramsBranch.Child(ag_format.Param("        Mode", *inst.Mode))
+						paramsBranch.Child(ag_format.Param("      Limits", *inst.Limits))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("                vault", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("            authority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("             referrer", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("        system.config", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("system.system_program", inst.AccountMetaSlice.Get(4)))
+					})
+				})
+		})
+}
+
+func (obj Initialize) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `VaultId`:
+	err = encoder.Encode(obj.VaultId)
+	if err != nil {
+		return err
+	}
+	// Serialize `Name`:
+	err = encoder.Encode(obj.Name)
+	if err != nil {
+		return err
+	}
+	// Serialize `FeeBps` (optional):
+	{
+		if obj.FeeBps == nil {
+			err = encoder.WriteOption(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteOption(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.FeeBps)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Serialize `Mode`:
+	err = encoder.Encode(obj.Mode)
+	if err != nil {
+		return err
+	}
+	// Serialize `Limits`:
+	err = encoder.Encode(obj.Limits)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Initialize) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `VaultId`:
+	err = decoder.Decode(&obj.VaultId)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Name`:
+	err = decoder.Decode(&obj.Name)
+	if err != nil {
+		return err
+	}
+	// Deserialize `FeeBps` (optional):
+	{
+		ok, err := decoder.ReadOption()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.FeeBps)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Deserialize `Mode`:
+	err = decoder.Decode(&obj.Mode)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Limits`:
+	err = decoder.Decode(&obj.Limits)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewInitializeInstruction declares a new Initialize instruction with the provided parameters and accounts.
+func NewInitializeInstruction(
+	// Parameters:
+	vaultId uint64,
+	name string,
+	feeBps uint16,
+	mode Mode,
+	limits Limits,
+	// Accounts:
+	vault ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	referrer ag_solanago.PublicKey,
+	systemConfig ag_solanago.PublicKey,
+) *Initialize {
+	return NewInitializeInstructionBuilder().
+		SetVaultId(vaultId).
+		SetName(name).
+		SetFeeBps(feeBps).
+		SetMode(mode).
+		SetLimits(limits).
+		SetVaultAccount(vault).
+		SetAuthorityAccount(authority).
+		SetReferrerAccount(referrer).
+		SetSystemConfigAccount(systemConfig)
+}
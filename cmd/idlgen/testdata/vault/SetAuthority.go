@@ -0,0 +1,190 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// SetAuthority is the `set_authority` instruction.
+type SetAuthority struct {
+	NewAuthority *ag_solanago.PublicKey `bin:"optional"`
+	Kind         *AuthorityKind
+
+	// [0] = [WRITE] vault
+	//
+	// [1] = [SIGNER] authority
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetAuthorityInstructionBuilder creates a new `SetAuthority` instruction builder.
+func NewSetAuthorityInstructionBuilder() *SetAuthority {
+	nd := &SetAuthority{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 2),
+	}
+	return nd
+}
+
+// SetNewAuthority sets the "new_authority" parameter.
+func (inst *SetAuthority) SetNewAuthority(newAuthority ag_solanago.PublicKey) *SetAuthority {
+	inst.NewAuthority = &newAuthority
+	return inst
+}
+
+// SetKind sets the "kind" parameter.
+func (inst *SetAuthority) SetKind(kind AuthorityKind) *SetAuthority {
+	inst.Kind = &kind
+	return inst
+}
+
+// SetVaultAccount sets the "vault" account.
+func (inst *SetAuthority) SetVaultAccount(vault ag_solanago.PublicKey) *SetAuthority {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(vault).WRITE()
+	return inst
+}
+
+// GetVaultAccount gets the "vault" account.
+func (inst *SetAuthority) GetVaultAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+func (inst *SetAuthority) SetAuthorityAccount(authority ag_solanago.PublicKey) *SetAuthority {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(authority).SIGNER()
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+func (inst *SetAuthority) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+func (inst SetAuthority) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: Instruction_SetAuthority,
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst SetAuthority) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetAuthority) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Kind == nil {
+			return errors.New("Kind parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Vault is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *SetAuthority) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("SetAuthority")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("NewAuthority (OPT)", inst.NewAuthority))
+						paramsBranch.Child(ag_format.Param("              Kind", *inst.Kind))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("    vault", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("authority", inst.AccountMetaSlice.Get(1)))
+					})
+				})
+		})
+}
+
+func (obj SetAuthority) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `NewAuthority` (optional):
+	{
+		if obj.NewAuthority == nil {
+			err = encoder.WriteOption(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteOption(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.NewAuthority)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Serialize `Kind`:
+	err = encoder.Encode(obj.Kind)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *SetAuthority) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `NewAuthority` (optional):
+	{
+		ok, err := decoder.ReadOption()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.NewAuthority)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Deserialize `Kind`:
+	err = decoder.Decode(&obj.Kind)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewSetAuthorityInstruction declares a new SetAuthority instruction with the provided parameters and accounts.
+func NewSetAuthorityInstruction(
+	// Parameters:
+	newAuthority ag_solanago.PublicKey,
+	kind AuthorityKind,
+	// Accounts:
+	vault ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+) *SetAuthority {
+	return NewSetAuthorityInstructionBuilder().
+		SetNewAuthority(newAuthority).
+		SetKind(kind).
+		SetVaultAccount(vault).
+		SetAuthorityAccount(authority)
+}
@@ -0,0 +1,49 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode_SetAuthority(t *testing.T) {
+	fu := newFuzzer()
+	for i := 0; i < 1; i++ {
+		t.Run("SetAuthority"+strconv.Itoa(i), func(t *testing.T) {
+			{
+				params := new(SetAuthority)
+				fu.Fuzz(params)
+				params.AccountMetaSlice = nil
+				buf := new(bytes.Buffer)
+				err := encodeT(*params, buf)
+				ag_require.NoError(t, err)
+				//
+				got := new(SetAuthority)
+				err = decodeT(got, buf.Bytes())
+				got.AccountMetaSlice = nil
+				ag_require.NoError(t, err)
+				ag_require.Equal(t, params, got)
+			}
+			{
+				params := new(SetAuthority)
+				fu.Fuzz(params)
+				params.AccountMetaSlice = nil
+				inst := &Instruction{BaseVariant: ag_binary.BaseVariant{
+					Impl:   *params,
+					TypeID: Instruction_SetAuthority,
+				}}
+				data, err := inst.Data()
+				ag_require.NoError(t, err)
+				//
+				got, err := DecodeInstruction(nil, data)
+				ag_require.NoError(t, err)
+				ag_require.Equal(t, params, got.Impl)
+			}
+		})
+	}
+}
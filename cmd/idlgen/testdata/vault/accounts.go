@@ -0,0 +1,247 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+var (
+	ConfigDiscriminator = [8]byte{155, 12, 170, 224, 30, 250, 204, 130}
+	VaultDiscriminator  = [8]byte{211, 8, 232, 43, 2, 152, 117, 119}
+)
+
+type Config struct {
+	Admin  ag_solanago.PublicKey
+	Paused bool
+	Ratio  Ratio
+}
+
+func (obj Config) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Write account discriminator:
+	err = encoder.WriteBytes(ConfigDiscriminator[:], false)
+	if err != nil {
+		return err
+	}
+	// Serialize `Admin`:
+	err = encoder.Encode(obj.Admin)
+	if err != nil {
+		return err
+	}
+	// Serialize `Paused`:
+	err = encoder.Encode(obj.Paused)
+	if err != nil {
+		return err
+	}
+	// Serialize `Ratio`:
+	err = encoder.Encode(obj.Ratio)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Config) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Read and check account discriminator:
+	{
+		discriminator, err := decoder.ReadTypeID()
+		if err != nil {
+			return err
+		}
+		if !discriminator.Equal(ConfigDiscriminator[:]) {
+			return fmt.Errorf(
+				"wrong discriminator: wanted %s, got %s",
+				fmt.Sprint(ConfigDiscriminator[:]),
+				fmt.Sprint(discriminator[:]))
+		}
+	}
+	// Deserialize `Admin`:
+	err = decoder.Decode(&obj.Admin)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Paused`:
+	err = decoder.Decode(&obj.Paused)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Ratio`:
+	err = decoder.Decode(&obj.Ratio)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type Vault struct {
+	Authority ag_solanago.PublicKey
+	VaultId   uint64
+	Name      string
+	FeeBps    *uint16 `bin:"optional"`
+	Mode      Mode
+	Limits    Limits
+	Total     ag_binary.Uint128
+	History   []Entry
+	Bump      uint8
+}
+
+func (obj Vault) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Write account discriminator:
+	err = encoder.WriteBytes(VaultDiscriminator[:], false)
+	if err != nil {
+		return err
+	}
+	// Serialize `Authority`:
+	err = encoder.Encode(obj.Authority)
+	if err != nil {
+		return err
+	}
+	// Serialize `VaultId`:
+	err = encoder.Encode(obj.VaultId)
+	if err != nil {
+		return err
+	}
+	// Serialize `Name`:
+	err = encoder.Encode(obj.Name)
+	if err != nil {
+		return err
+	}
+	// Serialize `FeeBps` (optional):
+	{
+		if obj.FeeBps == nil {
+			err = encoder.WriteOption(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteOption(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.FeeBps)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Serialize `Mode`:
+	err = encoder.Encode(obj.Mode)
+	if err != nil {
+		return err
+	}
+	// Serialize `Limits`:
+	err = encoder.Encode(obj.Limits)
+	if err != nil {
+		return err
+	}
+	// Serialize `Total`:
+	err = encoder.Encode(obj.Total)
+	if err != nil {
+		return err
+	}
+	// Serialize `History`:
+	err = encoder.Encode(obj.History)
+	if err != nil {
+		return err
+	}
+	// Serialize `Bump`:
+	err = encoder.Encode(obj.Bump)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (obj *Vault) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Read and check account discriminator:
+	{
+		discriminator, err := decoder.ReadTypeID()
+		if err != nil {
+			return err
+		}
+		if !discriminator.Equal(VaultDiscriminator[:]) {
+			return fmt.Errorf(
+				"wrong discriminator: wanted %s, got %s",
+				fmt.Sprint(VaultDiscriminator[:]),
+				fmt.Sprint(discriminator[:]))
+		}
+	}
+	// Deserialize `Authority`:
+	err = decoder.Decode(&obj.Authority)
+	if err != nil {
+		return err
+	}
+	// Deserialize `VaultId`:
+	err = decoder.Decode(&obj.VaultId)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Name`:
+	err = decoder.Decode(&obj.Name)
+	if err != nil {
+		return err
+	}
+	// Deserialize `FeeBps` (optional):
+	{
+		ok, err := decoder.ReadOption()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.FeeBps)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Deserialize `Mode`:
+	err = decoder.Decode(&obj.Mode)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Limits`:
+	err = decoder.Decode(&obj.Limits)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Total`:
+	err = decoder.Decode(&obj.Total)
+	if err != nil {
+		return err
+	}
+	// Deserialize `History`:
+	err = decoder.Decode(&obj.History)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Bump`:
+	err = decoder.Decode(&obj.Bump)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func registerAccountDecoders(programID ag_solanago.PublicKey) {
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountDiscriminatorMatcher(ConfigDiscriminator[:]), decodeConfigAccount)
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountDiscriminatorMatcher(VaultDiscriminator[:]), decodeVaultAccount)
+}
+
+func decodeConfigAccount(data []byte) (interface{}, error) {
+	out := new(Config)
+	if err := ag_binary.NewBorshDecoder(data).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode Config: %w", err)
+	}
+	return out, nil
+}
+
+func decodeVaultAccount(data []byte) (interface{}, error) {
+	out := new(Vault)
+	if err := ag_binary.NewBorshDecoder(data).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode Vault: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,34 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"fmt"
+)
+
+// ProgramError is a custom error of the program.
+type ProgramError struct {
+	Code uint32
+	Name string
+	Msg  string
+}
+
+func (e *ProgramError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Name, e.Code, e.Msg)
+}
+
+var (
+	ErrPaused   = &ProgramError{Code: 6000, Name: "Paused", Msg: "The vault is paused"}
+	ErrOverflow = &ProgramError{Code: 6001, Name: "Overflow", Msg: "Arithmetic overflow"}
+)
+
+var errorsByCode = map[uint32]*ProgramError{
+	6000: ErrPaused,
+	6001: ErrOverflow,
+}
+
+// ErrorFromCode returns the error of the program with the provided custom error code.
+func ErrorFromCode(code uint32) (*ProgramError, bool) {
+	e, ok := errorsByCode[code]
+	return e, ok
+}
@@ -0,0 +1,33 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+)
+
+var (
+	DepositedDiscriminator = [8]byte{111, 141, 26, 45, 161, 35, 100, 57}
+)
+
+// DecodeEvent decodes an event emitted by the program (the base64-decoded
+// data of a "Program data:" log), returning a pointer to the event struct.
+func DecodeEvent(data []byte) (interface{}, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("event data too short: %d bytes", len(data))
+	}
+	var out interface{}
+	switch {
+	case bytes.Equal(data[:8], DepositedDiscriminator[:]):
+		out = new(Deposited)
+	default:
+		return nil, fmt.Errorf("unknown event discriminator %v", data[:8])
+	}
+	if err := ag_binary.NewBorshDecoder(data[8:]).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode event: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,27 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	ag_binary "github.com/gagliardetto/binary"
+	ag_gofuzz "github.com/gagliardetto/gofuzz"
+)
+
+// newFuzzer returns a fuzzer generating values that can be round-tripped;
+// the enums only take the values of their variants.
+func newFuzzer() *ag_gofuzz.Fuzzer {
+	return ag_gofuzz.New().NilChance(0).Funcs(
+		func(obj *AuthorityKind, c ag_gofuzz.Continue) {
+			*obj = AuthorityKind(c.Intn(2))
+		},
+		func(obj *Mode, c ag_gofuzz.Continue) {
+			*obj = Mode{Enum: ag_binary.BorshEnum(c.Intn(3))}
+			switch obj.Enum {
+			case ModeFixed:
+				c.Fuzz(&obj.Fixed)
+			case ModeRange:
+				c.Fuzz(&obj.Range)
+			}
+		},
+	)
+}
@@ -0,0 +1,41 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	"encoding/binary"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+// FindVaultAddress finds the `vault` program derived address.
+func FindVaultAddress(vaultId uint64) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress(
+		[][]byte{
+			[]byte("vault"),
+			binary.LittleEndian.AppendUint64(nil, vaultId),
+		},
+		ProgramID,
+	)
+}
+
+// FindConfigAddress finds the `config` program derived address.
+func FindConfigAddress() (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress(
+		[][]byte{
+			[]byte("config"),
+		},
+		ProgramID,
+	)
+}
+
+// FindCloseVaultAddress finds the `close_vault` program derived address.
+func FindCloseVaultAddress(authority ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress(
+		[][]byte{
+			[]byte("vault"),
+			authority[:],
+		},
+		ProgramID,
+	)
+}
@@ -0,0 +1,69 @@
+// Code generated by idlgen. DO NOT EDIT.
+
+package vault
+
+import (
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+type AuthorityKind ag_binary.BorshEnum
+
+const (
+	AuthorityKindOwner AuthorityKind = iota
+	AuthorityKindDelegate
+)
+
+func (value AuthorityKind) String() string {
+	switch value {
+	case AuthorityKindOwner:
+		return "Owner"
+	case AuthorityKindDelegate:
+		return "Delegate"
+	default:
+		return ""
+	}
+}
+
+type Deposited struct {
+	Vault  ag_solanago.PublicKey
+	Amount uint64
+	Total  ag_binary.Uint128
+}
+
+// A timestamped deposit.
+type Entry struct {
+	V0 int64
+	V1 uint64
+}
+
+type Limits struct {
+	MaxDeposit uint64
+	MinDeposit *uint64 `bin:"optional"`
+	Whitelist  []ag_solanago.PublicKey
+}
+
+type Mode struct {
+	Enum  ag_binary.BorshEnum `borsh_enum:"true"`
+	Off   ag_binary.EmptyVariant
+	Fixed ModeFixedFields
+	Range ModeRangeFields
+}
+
+// The variants of Mode, set in its Enum field.
+const (
+	ModeOff ag_binary.BorshEnum = iota
+	ModeFixed
+	ModeRange
+)
+
+type ModeFixedFields struct {
+	Rate uint32
+}
+
+type ModeRangeFields struct {
+	V0 uint32
+	V1 uint32
+}
+
+type Ratio [2]uint16
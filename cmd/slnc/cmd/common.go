@@ -46,6 +46,21 @@ func getClient() *rpc.Client {
 	return api
 }
 
+// getWSURL returns the websocket endpoint, by default the one of the
+// JSON-RPC endpoint.
+func getWSURL() string {
+	if wsURL := viper.GetString("global-ws-url"); wsURL != "" {
+		return wsURL
+	}
+	wsURL := sanitizeAPIURL(viper.GetString("global-rpc-url"))
+	if strings.HasPrefix(wsURL, "https://") {
+		return "wss://" + strings.TrimPrefix(wsURL, "https://")
+	}
+	// The websocket port of a validator follows its RPC port.
+	wsURL = strings.Replace(wsURL, ":8899", ":8900", 1)
+	return "ws://" + strings.TrimPrefix(wsURL, "http://")
+}
+
 func sanitizeAPIURL(input string) string {
 	switch input {
 	case "devnet":
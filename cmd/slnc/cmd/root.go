@@ -54,6 +54,7 @@ func init() {
 	RootCmd.PersistentFlags().StringP("vault-file", "", "./solana-vault.json", "Wallet file that contains encrypted key material")
 	RootCmd.PersistentFlags().StringP("rpc-url", "u", defaultRPCURL, "API endpoint of eos.io blockchain node")
 	RootCmd.PersistentFlags().StringSliceP("http-header", "H", []string{}, "HTTP header to add to JSON-RPC requests")
+	RootCmd.PersistentFlags().StringP("ws-url", "", "", "Websocket endpoint of the node, by default the one of the API endpoint")
 	RootCmd.PersistentFlags().StringP("kms-gcp-keypath", "", "", "Path to the cryptoKeys within a keyRing on GCP")
 
 	RootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
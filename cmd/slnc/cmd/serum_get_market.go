@@ -26,8 +26,10 @@ import (
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/programs/serum"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
 	"github.com/ryanuber/columnize"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var serumGetMarketCmd = &cobra.Command{
@@ -43,6 +45,10 @@ var serumGetMarketCmd = &cobra.Command{
 		}
 
 		cli := getClient()
+		if viper.GetBool("serum-get-market-cmd-watch") {
+			return watchMarket(ctx, cli, marketAddr)
+		}
+
 		market, err := serum.FetchMarket(ctx, cli, marketAddr)
 		if err != nil {
 			return fmt.Errorf("fetch market: %w", err)
@@ -87,6 +93,70 @@ var serumGetMarketCmd = &cobra.Command{
 	},
 }
 
+// watchMarket prints the book of the market, then the changes of its levels.
+func watchMarket(ctx context.Context, cli *rpc.Client, marketAddr solana.PublicKey) error {
+	wsClient, err := ws.Connect(ctx, getWSURL())
+	if err != nil {
+		return fmt.Errorf("connecting to websocket: %w", err)
+	}
+	defer wsClient.Close()
+
+	first := true
+	return serum.WatchBook(ctx, cli, wsClient, marketAddr, rpc.CommitmentConfirmed, func(market *serum.MarketMeta, book *serum.Book, diff *serum.BookDiff) error {
+		if first {
+			first = false
+			asks, askSize := bookEntries(book.Asks, 20)
+			bids, bidSize := bookEntries(book.Bids, 20)
+			totalSize := new(big.Float).Add(askSize, bidSize)
+
+			output := []string{
+				"Price | Quantity | Depth",
+				"Asks",
+			}
+			output = append(output, outputOrderBook(asks, totalSize, true)...)
+			output = append(output, "------- | --------")
+			output = append(output, outputOrderBook(bids, totalSize, false)...)
+			output = append(output, "Bids")
+
+			fmt.Println(market.Name)
+			fmt.Println("Slot", diff.Slot)
+			fmt.Println(columnize.Format(output, nil))
+			fmt.Println("")
+			return nil
+		}
+
+		output := []string{}
+		for _, change := range diff.Levels {
+			side := "Bid"
+			if change.Side == serum.SideAsk {
+				side = "Ask"
+			}
+			output = append(output, fmt.Sprintf("%d | %s | %s | %s", diff.Slot, side, change.Price.String(), change.Quantity.String()))
+		}
+		if len(output) > 0 {
+			fmt.Println(columnize.Format(output, nil))
+		}
+		return nil
+	})
+}
+
+// bookEntries returns the first levels of a side of a book, and their total
+// size.
+func bookEntries(levels []*serum.BookLevel, limit int) (out []*orderBookEntry, totalSize *big.Float) {
+	totalSize = big.NewFloat(0)
+	for i, level := range levels {
+		if i == limit {
+			break
+		}
+		totalSize = new(big.Float).Add(totalSize, level.Quantity)
+		out = append(out, &orderBookEntry{
+			price:    level.Price,
+			quantity: level.Quantity,
+		})
+	}
+	return out, totalSize
+}
+
 type orderBookEntry struct {
 	price    *big.Float
 	quantity *big.Float
@@ -181,4 +251,6 @@ func outputOrderBook(entries []*orderBookEntry, totalSize *big.Float, reverse bo
 }
 func init() {
 	serumGetCmd.AddCommand(serumGetMarketCmd)
+
+	serumGetMarketCmd.Flags().BoolP("watch", "w", false, "Keep the orderbook live, printing the changes of its levels")
 }
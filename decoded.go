@@ -0,0 +1,186 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solana
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+)
+
+// DecodedTransaction is the machine-readable representation of a
+// transaction, with its instructions decoded through the registry.
+type DecodedTransaction struct {
+	Signatures      []Signature           `json:"signatures"`
+	RecentBlockhash Hash                  `json:"recentBlockhash"`
+	Instructions    []*DecodedInstruction `json:"instructions"`
+}
+
+// DecodedInstruction is the machine-readable representation of an
+// instruction decoded through the registry.
+type DecodedInstruction struct {
+	Program     PublicKey `json:"program"`
+	ProgramName string    `json:"programName,omitempty"`
+	Instruction string    `json:"instruction,omitempty"`
+	// The arguments of the instruction: its exported fields, in
+	// declaration order, unless it implements InstructionArgsGettable.
+	Args     interface{}           `json:"args,omitempty"`
+	Accounts []*DecodedAccountMeta `json:"accounts"`
+	// The data of the instructions that could not be decoded, and why.
+	Data  Base58 `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type DecodedAccountMeta struct {
+	// Empty if the instruction doesn't name the account.
+	Name     string    `json:"name,omitempty"`
+	Pubkey   PublicKey `json:"pubkey"`
+	Signer   bool      `json:"signer"`
+	Writable bool      `json:"writable"`
+}
+
+// Decoded decodes the instructions of the transaction through the registry.
+// The address table lookups of a v0 transaction must have been resolved
+// (see Message.ResolveLookups).
+func (tx *Transaction) Decoded() (*DecodedTransaction, error) {
+	out := &DecodedTransaction{
+		Signatures:      tx.Signatures,
+		RecentBlockhash: tx.Message.RecentBlockhash,
+		Instructions:    make([]*DecodedInstruction, len(tx.Message.Instructions)),
+	}
+	for i, inst := range tx.Message.Instructions {
+		programID, err := tx.Message.Program(inst.ProgramIDIndex)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %d: %w", i, err)
+		}
+		accounts, err := inst.ResolveInstructionAccounts(&tx.Message)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %d: %w", i, err)
+		}
+		out.Instructions[i] = NewDecodedInstruction(programID, accounts, inst.Data)
+	}
+	return out, nil
+}
+
+// NewDecodedInstruction decodes the instruction through the registry; if the
+// program has no registered decoder or the data cannot be decoded, the
+// instruction keeps its raw data, and the reason.
+func NewDecodedInstruction(programID PublicKey, accounts []*AccountMeta, data []byte) *DecodedInstruction {
+	out := &DecodedInstruction{
+		Program:  programID,
+		Accounts: make([]*DecodedAccountMeta, len(accounts)),
+	}
+	var names []string
+	decoded, err := DecodeInstruction(programID, accounts, data)
+	if err != nil {
+		out.Data = data
+		out.Error = err.Error()
+	} else {
+		if v, ok := decoded.(ProgramNameGettable); ok {
+			out.ProgramName = v.ProgramName()
+		}
+		if v, ok := decoded.(InstructionNameGettable); ok {
+			out.Instruction = v.InstructionName()
+		}
+		impl := variantImpl(decoded)
+		if v, ok := impl.(AccountNamesGettable); ok {
+			names = v.AccountNames()
+		}
+		out.Args = instructionArgs(impl)
+	}
+	for i, account := range accounts {
+		meta := &DecodedAccountMeta{
+			Pubkey:   account.PublicKey,
+			Signer:   account.IsSigner,
+			Writable: account.IsWritable,
+		}
+		if i < len(names) {
+			meta.Name = names[i]
+		}
+		out.Accounts[i] = meta
+	}
+	return out
+}
+
+var baseVariantType = reflect.TypeOf(bin.BaseVariant{})
+
+// variantImpl returns the implementation of a decoded instruction
+// embedding a bin.BaseVariant, or the decoded instruction itself.
+func variantImpl(decoded interface{}) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(decoded))
+	if v.Kind() == reflect.Struct {
+		if field := v.FieldByName("BaseVariant"); field.IsValid() && field.Type() == baseVariantType {
+			return field.Interface().(bin.BaseVariant).Impl
+		}
+	}
+	return decoded
+}
+
+func instructionArgs(impl interface{}) interface{} {
+	if v, ok := impl.(InstructionArgsGettable); ok {
+		return v.InstructionArgs()
+	}
+	v := reflect.Indirect(reflect.ValueOf(impl))
+	if v.Kind() != reflect.Struct {
+		return impl
+	}
+	var args orderedArgs
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		// Skip the accounts, which are not serialized.
+		if !field.IsExported() || strings.HasPrefix(field.Tag.Get("bin"), "-") {
+			continue
+		}
+		args = append(args, orderedArg{field.Name, v.Field(i).Interface()})
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+type orderedArg struct {
+	name  string
+	value interface{}
+}
+
+// orderedArgs marshals to a JSON object with the args in order.
+type orderedArgs []orderedArg
+
+func (args orderedArgs) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, arg := range args {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(arg.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(arg.value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal arg %s: %w", arg.name, err)
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solana
+
+import (
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/stretchr/testify/require"
+)
+
+type testDecodedInstruction struct {
+	bin.BaseVariant
+}
+
+func (inst *testDecodedInstruction) ProgramName() string     { return "Test" }
+func (inst *testDecodedInstruction) InstructionName() string { return "Pay" }
+
+type testPay struct {
+	Amount           uint64
+	Memo             *string
+	AccountMetaSlice `bin:"-"`
+}
+
+func (inst testPay) AccountNames() []string {
+	return []string{"from"}
+}
+
+func TestTransaction_Decoded(t *testing.T) {
+	programID := MustPublicKeyFromBase58("Test111111111111111111111111111111111111111")
+	unknownID := MustPublicKeyFromBase58("Unknown111111111111111111111111111111111111")
+	from := MustPublicKeyFromBase58("7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932")
+	to := MustPublicKeyFromBase58("DTUrFSqFh2kHWhaSQaqT1NXh3pRsa1sQP2XGvVGTXdCq")
+
+	RegisterInstructionDecoder(programID, func(accounts []*AccountMeta, data []byte) (interface{}, error) {
+		memo := "thanks"
+		return &testDecodedInstruction{BaseVariant: bin.BaseVariant{
+			Impl: &testPay{Amount: uint64(data[0]), Memo: &memo, AccountMetaSlice: accounts},
+		}}, nil
+	})
+
+	tx, err := NewTransaction(
+		[]Instruction{
+			NewInstruction(programID, AccountMetaSlice{Meta(from).WRITE().SIGNER(), Meta(to).WRITE()}, []byte{42}),
+			NewInstruction(unknownID, AccountMetaSlice{Meta(to)}, []byte{1, 2, 3}),
+		},
+		Hash{},
+		TransactionPayer(from),
+	)
+	require.NoError(t, err)
+
+	decoded, err := tx.Decoded()
+	require.NoError(t, err)
+	got, err := json.Marshal(decoded.Instructions)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{
+			"program": "Test111111111111111111111111111111111111111",
+			"programName": "Test",
+			"instruction": "Pay",
+			"args": {"Amount": 42, "Memo": "thanks"},
+			"accounts": [
+				{"name": "from", "pubkey": "7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932", "signer": true, "writable": true},
+				{"pubkey": "DTUrFSqFh2kHWhaSQaqT1NXh3pRsa1sQP2XGvVGTXdCq", "signer": false, "writable": true}
+			]
+		},
+		{
+			"program": "Unknown111111111111111111111111111111111111",
+			"accounts": [
+				{"pubkey": "DTUrFSqFh2kHWhaSQaqT1NXh3pRsa1sQP2XGvVGTXdCq", "signer": false, "writable": true}
+			],
+			"data": "Ldp",
+			"error": "instruction decoder not found"
+		}
+	]`, string(got))
+}
@@ -21,3 +21,27 @@ type AccountsSettable interface {
 type AccountsGettable interface {
 	GetAccounts() (accounts []*AccountMeta)
 }
+
+// ProgramNameGettable is implemented by the decoded instructions
+// of the programs with a name (e.g. "System").
+type ProgramNameGettable interface {
+	ProgramName() string
+}
+
+// InstructionNameGettable is implemented by the decoded instructions
+// that know the name of their variant (e.g. "Transfer").
+type InstructionNameGettable interface {
+	InstructionName() string
+}
+
+// AccountNamesGettable is implemented by the instructions that name
+// their accounts, in the order of GetAccounts.
+type AccountNamesGettable interface {
+	AccountNames() []string
+}
+
+// InstructionArgsGettable is implemented by the instructions that render
+// their arguments themselves, instead of their exported fields.
+type InstructionArgsGettable interface {
+	InstructionArgs() interface{}
+}
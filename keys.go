@@ -576,6 +576,7 @@ var nativeProgramIDs = PublicKeySlice{
 	StakeProgramID,
 	VoteProgramID,
 	Secp256k1ProgramID,
+	Ed25519ProgramID,
 	SystemProgramID,
 	SysVarClockPubkey,
 	SysVarEpochSchedulePubkey,
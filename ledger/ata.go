@@ -0,0 +1,90 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+// Approximate compute units consumed by the associated token account program,
+// not including the cross-program invocations.
+const associatedTokenAccountCost = 20000
+
+// processAssociatedTokenAccount executes the Create (empty data)
+// and CreateIdempotent (data [1]) instructions.
+func processAssociatedTokenAccount(e *executor, metas []*solana.AccountMeta, data []byte) error {
+	idempotent := false
+	switch {
+	case len(data) == 0 || len(data) == 1 && data[0] == 0:
+		e.programLog("Create")
+	case len(data) == 1 && data[0] == 1:
+		e.programLog("CreateIdempotent")
+		idempotent = true
+	default:
+		return errInvalidInstructionData
+	}
+	if err := requireAccounts(metas, 6); err != nil {
+		return err
+	}
+	payer, ata, wallet, mint, tokenProgram := metas[0], metas[1], metas[2], metas[3], metas[5]
+
+	address, _, err := solana.FindProgramAddress(
+		[][]byte{wallet.PublicKey[:], tokenProgram.PublicKey[:], mint.PublicKey[:]},
+		solana.SPLAssociatedTokenAccountProgramID,
+	)
+	if err != nil || !address.Equals(ata.PublicKey) {
+		e.programLog("Error: Associated address does not match seed derivation")
+		return errInvalidSeeds
+	}
+
+	acc := e.account(ata.PublicKey)
+	if idempotent && acc.Owner.Equals(tokenProgram.PublicKey) {
+		existing, ok := decodeTokenAccount(acc.Data)
+		if ok && existing.Owner.Equals(wallet.PublicKey) && existing.Mint.Equals(mint.PublicKey) {
+			return nil
+		}
+		return errIllegalOwner
+	}
+	if !acc.Owner.Equals(solana.SystemProgramID) {
+		return errIllegalOwner
+	}
+
+	// The associated token account signs for itself, as a PDA.
+	rent := MinimumBalanceForRentExemption(tokenAccountSize)
+	if acc.Lamports > 0 {
+		if acc.Lamports < rent {
+			err := e.invokeInstruction(system.NewTransferInstruction(rent-acc.Lamports, payer.PublicKey, ata.PublicKey).Build())
+			if err != nil {
+				return err
+			}
+		}
+		if err := e.invokeSigned(system.NewAllocateInstruction(tokenAccountSize, ata.PublicKey).Build(), ata.PublicKey); err != nil {
+			return err
+		}
+		if err := e.invokeSigned(system.NewAssignInstruction(tokenProgram.PublicKey, ata.PublicKey).Build(), ata.PublicKey); err != nil {
+			return err
+		}
+	} else {
+		create := system.NewCreateAccountInstruction(rent, tokenAccountSize, tokenProgram.PublicKey, payer.PublicKey, ata.PublicKey).Build()
+		if err := e.invokeSigned(create, ata.PublicKey); err != nil {
+			return err
+		}
+	}
+	e.programLog("Initialize the associated token account")
+	initialize := token.NewInitializeAccount3Instruction(wallet.PublicKey, ata.PublicKey, mint.PublicKey).Build()
+	return e.invokeInstruction(initialize)
+}
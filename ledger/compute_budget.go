@@ -0,0 +1,113 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"math"
+	"math/bits"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+)
+
+const (
+	defaultInstructionComputeUnitLimit = 200_000
+	maxComputeUnitLimit                = 1_400_000
+	minHeapFrameBytes                  = 32 * 1024
+	maxHeapFrameBytes                  = 256 * 1024
+)
+
+// computeBudget is the compute budget requested by a transaction.
+type computeBudget struct {
+	limit uint64
+	// Price of a compute unit, in micro-lamports.
+	microLamports uint64
+}
+
+// priorityFee returns the prioritization fee, in lamports.
+func (b *computeBudget) priorityFee() uint64 {
+	hi, lo := bits.Mul64(b.limit, b.microLamports)
+	lo, carry := bits.Add64(lo, 999_999, 0)
+	hi += carry
+	if hi >= 1_000_000 {
+		return math.MaxUint64
+	}
+	fee, _ := bits.Div64(hi, lo, 1_000_000)
+	return fee
+}
+
+// parseComputeBudget reads the compute budget instructions of the transaction;
+// the returned error is a transaction error.
+func parseComputeBudget(tx *solana.Transaction) (*computeBudget, interface{}) {
+	var limit, microLamports, heap *uint64
+	var others uint64
+	for index, inst := range tx.Message.Instructions {
+		programID, err := tx.Message.Program(inst.ProgramIDIndex)
+		if err != nil {
+			return nil, "InvalidAccountIndex"
+		}
+		if !programID.Equals(solana.ComputeBudget) {
+			others++
+			continue
+		}
+		invalid := map[string]interface{}{
+			"InstructionError": []interface{}{index, "InvalidInstructionData"},
+		}
+		duplicate := map[string]interface{}{"DuplicateInstruction": index}
+
+		decoded := new(computebudget.Instruction)
+		if err := bin.NewBinDecoder(inst.Data).Decode(decoded); err != nil {
+			return nil, invalid
+		}
+		switch impl := decoded.Impl.(type) {
+		case *computebudget.SetComputeUnitLimit:
+			if limit != nil {
+				return nil, duplicate
+			}
+			units := uint64(impl.Units)
+			limit = &units
+		case *computebudget.SetComputeUnitPrice:
+			if microLamports != nil {
+				return nil, duplicate
+			}
+			price := impl.MicroLamports
+			microLamports = &price
+		case *computebudget.RequestHeapFrame:
+			if heap != nil {
+				return nil, duplicate
+			}
+			size := uint64(impl.HeapSize)
+			if size < minHeapFrameBytes || size > maxHeapFrameBytes || size%1024 != 0 {
+				return nil, invalid
+			}
+			heap = &size
+		default:
+			return nil, invalid
+		}
+	}
+
+	budget := &computeBudget{limit: others * defaultInstructionComputeUnitLimit}
+	if limit != nil {
+		budget.limit = *limit
+	}
+	if budget.limit > maxComputeUnitLimit {
+		budget.limit = maxComputeUnitLimit
+	}
+	if microLamports != nil {
+		budget.microLamports = *microLamports
+	}
+	return budget, nil
+}
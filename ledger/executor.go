@@ -0,0 +1,485 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// programError is the error of a failed instruction;
+// value is in the JSON shape used by the RPC API (e.g. "InvalidAccountData" or {"Custom": 1}).
+type programError struct {
+	value       interface{}
+	description string
+}
+
+func (e *programError) Error() string {
+	return e.description
+}
+
+func newProgramError(value string, description string) *programError {
+	return &programError{value: value, description: description}
+}
+
+var (
+	errInvalidArgument             = newProgramError("InvalidArgument", "invalid program argument")
+	errInvalidInstructionData      = newProgramError("InvalidInstructionData", "invalid instruction data")
+	errInvalidAccountData          = newProgramError("InvalidAccountData", "invalid account data for instruction")
+	errInsufficientFunds           = newProgramError("InsufficientFunds", "insufficient funds for instruction")
+	errIncorrectProgramID          = newProgramError("IncorrectProgramId", "incorrect program id for instruction")
+	errMissingRequiredSignature    = newProgramError("MissingRequiredSignature", "missing required signature for instruction")
+	errUnbalancedInstruction       = newProgramError("UnbalancedInstruction", "sum of account balances before and after instruction do not match")
+	errModifiedProgramID           = newProgramError("ModifiedProgramId", "instruction illegally modified the program id of an account")
+	errExternalAccountLamportSpend = newProgramError("ExternalAccountLamportSpend", "instruction spent from the balance of an account it does not own")
+	errExternalAccountDataModified = newProgramError("ExternalAccountDataModified", "instruction modified data of an account it does not own")
+	errReadonlyLamportChange       = newProgramError("ReadonlyLamportChange", "instruction changed the balance of a read-only account")
+	errReadonlyDataModified        = newProgramError("ReadonlyDataModified", "instruction modified data of a read-only account")
+	errExecutableModified          = newProgramError("ExecutableModified", "instruction changed executable bit of an account")
+	errNotEnoughAccountKeys        = newProgramError("NotEnoughAccountKeys", "insufficient account keys for instruction")
+	errUnsupportedProgramID        = newProgramError("UnsupportedProgramId", "Unsupported program id")
+	errComputationalBudgetExceeded = newProgramError("ComputationalBudgetExceeded", "Computational budget exceeded")
+	errInvalidSeeds                = newProgramError("InvalidSeeds", "Provided seeds do not result in a valid address")
+	errIllegalOwner                = newProgramError("IllegalOwner", "Provided owner is not allowed")
+)
+
+// customError is a program-specific error, e.g. a TokenError.
+func customError(code uint32) *programError {
+	return &programError{
+		value:       map[string]interface{}{"Custom": code},
+		description: fmt.Sprintf("custom program error: 0x%x", code),
+	}
+}
+
+// Processor executes the instructions of a transaction against the provided accounts,
+// like the Ledger does, but without charging the fee and without verifying the signatures.
+// accounts must hold all the accounts referenced by the transaction (missing accounts can be nil)
+// and is modified in place; on failure, its content is undefined.
+//
+// Processor can be used as an rpctest.TransactionProcessor.
+func Processor(
+	tx *solana.Transaction,
+	accounts map[solana.PublicKey]*Account,
+) (logs []string, unitsConsumed uint64, txErr interface{}) {
+	keys, err := tx.Message.GetAllKeys()
+	if err != nil {
+		return nil, 0, "InvalidAccountIndex"
+	}
+	budget, txErr := parseComputeBudget(tx)
+	if txErr != nil {
+		return nil, 0, txErr
+	}
+	exec := newExecutor(tx, keys, accounts, budget, solana.Hash{}, defaultLamportsPerSignature)
+	exec.run()
+	return exec.logs, exec.consumed, exec.err
+}
+
+// accountState is a snapshot of an account, used to verify
+// the changes made by an instruction.
+type accountState struct {
+	lamports   uint64
+	owner      solana.PublicKey
+	data       []byte
+	executable bool
+}
+
+func snapshot(acc *Account) accountState {
+	if acc == nil {
+		return accountState{owner: solana.SystemProgramID}
+	}
+	return accountState{
+		lamports:   acc.Lamports,
+		owner:      acc.Owner,
+		data:       append([]byte(nil), acc.Data...),
+		executable: acc.Executable,
+	}
+}
+
+// frame is an instruction being executed.
+type frame struct {
+	programID solana.PublicKey
+	metas     []*solana.AccountMeta
+	pre       map[solana.PublicKey]accountState
+}
+
+type executor struct {
+	tx        *solana.Transaction
+	keys      solana.PublicKeySlice
+	accounts  map[solana.PublicKey]*Account
+	budget    *computeBudget
+	blockhash solana.Hash
+	// Stored in the nonce accounts.
+	lamportsPerSignature uint64
+
+	frames   []*frame
+	logs     []string
+	inner    []rpc.InnerInstruction
+	consumed uint64
+	err      interface{}
+}
+
+func newExecutor(
+	tx *solana.Transaction,
+	keys solana.PublicKeySlice,
+	accounts map[solana.PublicKey]*Account,
+	budget *computeBudget,
+	blockhash solana.Hash,
+	lamportsPerSignature uint64,
+) *executor {
+	return &executor{
+		tx:                   tx,
+		keys:                 keys,
+		accounts:             accounts,
+		budget:               budget,
+		blockhash:            blockhash,
+		lamportsPerSignature: lamportsPerSignature,
+		logs:                 make([]string, 0),
+		inner:                make([]rpc.InnerInstruction, 0),
+	}
+}
+
+// run executes all the instructions; it returns (and sets e.err to)
+// the transaction error, if any.
+func (e *executor) run() interface{} {
+	metas, err := e.tx.Message.AccountMetaList()
+	if err != nil {
+		e.err = "InvalidAccountIndex"
+		return e.err
+	}
+	pre := make(map[solana.PublicKey]accountState, len(e.keys))
+	for _, key := range e.keys {
+		pre[key] = snapshot(e.accounts[key])
+	}
+
+	for index, inst := range e.tx.Message.Instructions {
+		if int(inst.ProgramIDIndex) >= len(e.keys) {
+			e.err = "InvalidAccountIndex"
+			return e.err
+		}
+		instMetas := make([]*solana.AccountMeta, len(inst.Accounts))
+		for i, idx := range inst.Accounts {
+			if int(idx) >= len(metas) {
+				e.err = "InvalidAccountIndex"
+				return e.err
+			}
+			instMetas[i] = metas[idx]
+		}
+		e.inner = append(e.inner, rpc.InnerInstruction{
+			Index:        uint16(index),
+			Instructions: []rpc.CompiledInstruction{},
+		})
+		if err := e.invoke(e.keys[inst.ProgramIDIndex], instMetas, inst.Data); err != nil {
+			e.err = instructionError(index, err)
+			break
+		}
+	}
+	e.compactInner()
+	if e.err != nil {
+		return e.err
+	}
+
+	// Every writable account must end up either rent-exempt, closed,
+	// or no worse than it was (i.e. rent-paying accounts can't be created or grown).
+	for i, key := range e.keys {
+		if !metas[i].IsWritable {
+			continue
+		}
+		acc := e.accounts[key]
+		if acc == nil || acc.Lamports == 0 {
+			continue
+		}
+		if acc.Lamports >= MinimumBalanceForRentExemption(uint64(len(acc.Data))) {
+			continue
+		}
+		before := pre[key]
+		if before.lamports > 0 && len(before.data) == len(acc.Data) &&
+			before.lamports < MinimumBalanceForRentExemption(uint64(len(before.data))) {
+			continue
+		}
+		e.err = map[string]interface{}{
+			"InsufficientFundsForRent": map[string]interface{}{"account_index": i},
+		}
+		return e.err
+	}
+	return nil
+}
+
+func instructionError(index int, err error) interface{} {
+	var value interface{} = "GenericError"
+	if pErr, ok := err.(*programError); ok {
+		value = pErr.value
+	}
+	return map[string]interface{}{
+		"InstructionError": []interface{}{index, value},
+	}
+}
+
+// compactInner removes the entries without inner instructions.
+func (e *executor) compactInner() {
+	out := e.inner[:0]
+	for _, inner := range e.inner {
+		if len(inner.Instructions) > 0 {
+			out = append(out, inner)
+		}
+	}
+	e.inner = out
+}
+
+func (e *executor) log(format string, args ...interface{}) {
+	e.logs = append(e.logs, fmt.Sprintf(format, args...))
+}
+
+// programLog logs a message like the msg! macro of on-chain programs.
+func (e *executor) programLog(format string, args ...interface{}) {
+	e.log("Program log: "+format, args...)
+}
+
+// account returns the account at the provided address;
+// missing accounts are materialized as empty system accounts.
+func (e *executor) account(address solana.PublicKey) *Account {
+	acc, ok := e.accounts[address]
+	if !ok || acc == nil {
+		acc = &Account{Owner: solana.SystemProgramID}
+		e.accounts[address] = acc
+	}
+	return acc
+}
+
+// invoke executes an instruction; nested calls are cross-program invocations.
+func (e *executor) invoke(programID solana.PublicKey, metas []*solana.AccountMeta, data []byte) error {
+	depth := len(e.frames) + 1
+	if depth > 1 {
+		e.recordInner(programID, metas, data, depth)
+	}
+	if depth > 4 {
+		return newProgramError("CallDepth", "Cross-program invocation call depth too deep")
+	}
+
+	f := &frame{
+		programID: programID,
+		metas:     metas,
+		pre:       make(map[solana.PublicKey]accountState, len(metas)),
+	}
+	for _, meta := range metas {
+		f.pre[meta.PublicKey] = snapshot(e.accounts[meta.PublicKey])
+	}
+	e.frames = append(e.frames, f)
+	defer func() {
+		e.frames = e.frames[:len(e.frames)-1]
+	}()
+
+	e.log("Program %s invoke [%d]", programID, depth)
+	program, ok := programs[programID]
+	if !ok {
+		err := errUnsupportedProgramID
+		e.log("Program %s failed: %s", programID, err)
+		return err
+	}
+
+	remaining := e.budget.limit - e.consumed
+	startConsumed := e.consumed
+	err := e.consume(program.cost(data))
+	if err == nil {
+		err = program.process(e, metas, data)
+	}
+	if err == nil {
+		err = e.verify(f)
+	}
+	if !program.builtin {
+		e.log("Program %s consumed %d of %d compute units", programID, e.consumed-startConsumed, remaining)
+	}
+	if err != nil {
+		e.log("Program %s failed: %s", programID, err)
+		return err
+	}
+	e.log("Program %s success", programID)
+
+	if depth > 1 {
+		// The changes made by the callee are legit from the point of view of the caller.
+		parent := e.frames[len(e.frames)-2]
+		for _, meta := range metas {
+			if _, ok := parent.pre[meta.PublicKey]; ok {
+				parent.pre[meta.PublicKey] = snapshot(e.accounts[meta.PublicKey])
+			}
+		}
+	}
+	return nil
+}
+
+// invokeInstruction executes a cross-program invocation.
+func (e *executor) invokeInstruction(inst solana.Instruction) error {
+	data, err := inst.Data()
+	if err != nil {
+		return errInvalidInstructionData
+	}
+	return e.invoke(inst.ProgramID(), inst.Accounts(), data)
+}
+
+// invokeSigned is like invokeInstruction, with the program signing for the provided PDAs.
+func (e *executor) invokeSigned(inst solana.Instruction, signers ...solana.PublicKey) error {
+	metas := inst.Accounts()
+	for i, meta := range metas {
+		for _, signer := range signers {
+			if meta.PublicKey.Equals(signer) {
+				signed := *meta
+				signed.IsSigner = true
+				metas[i] = &signed
+			}
+		}
+	}
+	data, err := inst.Data()
+	if err != nil {
+		return errInvalidInstructionData
+	}
+	return e.invoke(inst.ProgramID(), metas, data)
+}
+
+func (e *executor) consume(units uint64) error {
+	if e.consumed+units > e.budget.limit {
+		e.consumed = e.budget.limit
+		return errComputationalBudgetExceeded
+	}
+	e.consumed += units
+	return nil
+}
+
+func (e *executor) recordInner(programID solana.PublicKey, metas []*solana.AccountMeta, data []byte, depth int) {
+	inst := rpc.CompiledInstruction{
+		ProgramIDIndex: uint16(indexOf(e.keys, programID)),
+		Accounts:       make([]uint16, len(metas)),
+		Data:           solana.Base58(append([]byte(nil), data...)),
+		StackHeight:    uint16(depth),
+	}
+	for i, meta := range metas {
+		inst.Accounts[i] = uint16(indexOf(e.keys, meta.PublicKey))
+	}
+	last := &e.inner[len(e.inner)-1]
+	last.Instructions = append(last.Instructions, inst)
+}
+
+// verify checks that the changes made by the instruction are allowed by the runtime.
+func (e *executor) verify(f *frame) error {
+	var preSum, postSum uint64
+	for key, before := range f.pre {
+		after := snapshot(e.accounts[key])
+		preSum += before.lamports
+		postSum += after.lamports
+
+		writable := false
+		for _, meta := range f.metas {
+			if meta.PublicKey.Equals(key) && meta.IsWritable {
+				writable = true
+			}
+		}
+		dataChanged := !bytes.Equal(before.data, after.data)
+		if !writable {
+			switch {
+			case before.lamports != after.lamports:
+				return errReadonlyLamportChange
+			case dataChanged:
+				return errReadonlyDataModified
+			case !before.owner.Equals(after.owner):
+				return errModifiedProgramID
+			}
+			continue
+		}
+		ownedByProgram := before.owner.Equals(f.programID)
+		switch {
+		case !before.owner.Equals(after.owner) && !ownedByProgram:
+			return errModifiedProgramID
+		case after.lamports < before.lamports && !ownedByProgram:
+			return errExternalAccountLamportSpend
+		case dataChanged && !ownedByProgram:
+			return errExternalAccountDataModified
+		case before.executable != after.executable:
+			return errExecutableModified
+		}
+	}
+	if preSum != postSum {
+		return errUnbalancedInstruction
+	}
+	return nil
+}
+
+// program is a natively executed program.
+type program struct {
+	process func(e *executor, metas []*solana.AccountMeta, data []byte) error
+	// cost returns the compute units consumed by the instruction,
+	// not including the cross-program invocations.
+	cost func(data []byte) uint64
+	// Builtin programs don't log the consumed compute units.
+	builtin bool
+}
+
+var programs map[solana.PublicKey]*program
+
+func init() {
+	programs = map[solana.PublicKey]*program{
+		solana.SystemProgramID: {
+			process: processSystem,
+			cost:    fixedCost(150),
+			builtin: true,
+		},
+		solana.ComputeBudget: {
+			process: func(*executor, []*solana.AccountMeta, []byte) error { return nil },
+			cost:    fixedCost(150),
+			builtin: true,
+		},
+		solana.TokenProgramID: {
+			process: processToken,
+			cost:    tokenCost,
+		},
+		solana.SPLAssociatedTokenAccountProgramID: {
+			process: processAssociatedTokenAccount,
+			cost:    fixedCost(associatedTokenAccountCost),
+		},
+		solana.MemoProgramID: {
+			process: processMemo,
+			cost:    memoCost,
+		},
+	}
+}
+
+func indexOf(keys solana.PublicKeySlice, key solana.PublicKey) int {
+	for i, k := range keys {
+		if k.Equals(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func fixedCost(units uint64) func([]byte) uint64 {
+	return func([]byte) uint64 { return units }
+}
+
+// requireAccounts checks that the instruction has at least n accounts.
+func requireAccounts(metas []*solana.AccountMeta, n int) error {
+	if len(metas) < n {
+		return errNotEnoughAccountKeys
+	}
+	return nil
+}
+
+// requireSigner checks that the account signed the transaction.
+func requireSigner(meta *solana.AccountMeta) error {
+	if !meta.IsSigner {
+		return errMissingRequiredSignature
+	}
+	return nil
+}
@@ -0,0 +1,430 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ledger implements a lightweight in-memory bank that executes
+// transactions against a map of accounts, without any RPC node or validator.
+//
+// The System, SPL Token, Associated Token Account, Compute Budget and Memo
+// programs are executed natively; instructions of any other program fail
+// with an UnsupportedProgramId error.
+//
+//	l := ledger.New(nil)
+//	l.SetBalance(payer.PublicKey(), solana.LAMPORTS_PER_SOL)
+//	meta, err := l.Process(tx)
+package ledger
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Account is an account held in the ledger.
+type Account struct {
+	Lamports   uint64
+	Owner      solana.PublicKey
+	Data       []byte
+	Executable bool
+	RentEpoch  uint64
+}
+
+// Clone returns a deep copy of the account.
+func (acc *Account) Clone() *Account {
+	if acc == nil {
+		return nil
+	}
+	out := *acc
+	out.Data = append([]byte(nil), acc.Data...)
+	return &out
+}
+
+const defaultLamportsPerSignature = 5000
+
+// Options configures a Ledger.
+type Options struct {
+	// Fee charged per signature.
+	// Defaults to 5000.
+	LamportsPerSignature uint64
+	// If true, the signatures of the transactions are not verified
+	// (the required signers must still be flagged as signers in the message).
+	SkipSignatureVerification bool
+	// If true, the recent blockhash of the transactions must be the ledger's blockhash
+	// (or the durable nonce stored in the nonce account of an AdvanceNonceAccount instruction).
+	CheckBlockhash bool
+}
+
+func (opts *Options) withDefaults() Options {
+	out := Options{}
+	if opts != nil {
+		out = *opts
+	}
+	if out.LamportsPerSignature == 0 {
+		out.LamportsPerSignature = defaultLamportsPerSignature
+	}
+	return out
+}
+
+// Ledger is an in-memory bank.
+// Ledger is safe for concurrent use.
+type Ledger struct {
+	opts Options
+
+	mu        sync.RWMutex
+	accounts  map[solana.PublicKey]*Account
+	blockhash solana.Hash
+	processed map[solana.Signature]struct{}
+}
+
+// New creates an empty Ledger.
+// The provided options can be nil.
+func New(opts *Options) *Ledger {
+	l := &Ledger{
+		opts:      opts.withDefaults(),
+		accounts:  make(map[solana.PublicKey]*Account),
+		processed: make(map[solana.Signature]struct{}),
+	}
+	l.blockhash = solana.HashFromBytes(sha256.New().Sum(nil))
+	return l
+}
+
+// SetAccount creates or replaces the account at the provided address;
+// a nil account deletes it.
+func (l *Ledger) SetAccount(address solana.PublicKey, account *Account) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if account == nil {
+		delete(l.accounts, address)
+		return
+	}
+	l.accounts[address] = account.Clone()
+}
+
+// SetBalance sets the lamports of the account at the provided address,
+// creating a system-owned account if it doesn't exist.
+func (l *Ledger) SetBalance(address solana.PublicKey, lamports uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	acc, ok := l.accounts[address]
+	if !ok {
+		acc = &Account{Owner: solana.SystemProgramID}
+		l.accounts[address] = acc
+	}
+	acc.Lamports = lamports
+}
+
+// GetAccount returns a copy of the account at the provided address, or nil.
+func (l *Ledger) GetAccount(address solana.PublicKey) *Account {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.accounts[address].Clone()
+}
+
+// Balance returns the lamports of the account at the provided address.
+func (l *Ledger) Balance(address solana.PublicKey) uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if acc, ok := l.accounts[address]; ok {
+		return acc.Lamports
+	}
+	return 0
+}
+
+// Blockhash returns the current blockhash of the ledger.
+func (l *Ledger) Blockhash() solana.Hash {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.blockhash
+}
+
+// AdvanceBlockhash replaces the blockhash of the ledger,
+// which is used as recent blockhash and as durable nonce value.
+func (l *Ledger) AdvanceBlockhash() solana.Hash {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sum := sha256.Sum256(l.blockhash[:])
+	l.blockhash = solana.HashFromBytes(sum[:])
+	return l.blockhash
+}
+
+// TransactionError is returned by Process and Simulate
+// when a transaction can't be included in the ledger at all
+// (i.e. not even the fee is charged).
+type TransactionError struct {
+	// Err is the error in the JSON shape used by the RPC API
+	// (e.g. "InsufficientFundsForFee").
+	Err interface{}
+}
+
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("transaction error: %v", e.Err)
+}
+
+// Process executes the transaction and commits its effects.
+//
+// If the transaction can't be included, a *TransactionError is returned
+// and nothing changes. Otherwise, the fee is charged and the returned meta
+// describes the execution: if meta.Err is not nil the instructions failed,
+// and all the changes except the fee have been discarded.
+func (l *Ledger) Process(tx *solana.Transaction) (*rpc.TransactionMeta, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	res, err := l.run(tx)
+	if err != nil {
+		return nil, err
+	}
+	for address, acc := range res.accounts {
+		if acc == nil || acc.Lamports == 0 {
+			// Accounts left without lamports are garbage-collected.
+			delete(l.accounts, address)
+			continue
+		}
+		l.accounts[address] = acc
+	}
+	l.processed[tx.Signatures[0]] = struct{}{}
+	return res.meta, nil
+}
+
+// Simulate is like Process, but it doesn't commit anything.
+// The returned accounts are the post-execution state of the accounts of the transaction.
+func (l *Ledger) Simulate(tx *solana.Transaction) (*rpc.TransactionMeta, map[solana.PublicKey]*Account, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	res, err := l.run(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.meta, res.accounts, nil
+}
+
+type runResult struct {
+	meta     *rpc.TransactionMeta
+	accounts map[solana.PublicKey]*Account
+}
+
+// run executes the transaction against a copy of its accounts.
+// Must be called with mu held.
+func (l *Ledger) run(tx *solana.Transaction) (*runResult, error) {
+	if len(tx.Signatures) == 0 {
+		return nil, &TransactionError{"MissingSignatureForFee"}
+	}
+	if _, ok := l.processed[tx.Signatures[0]]; ok {
+		return nil, &TransactionError{"AlreadyProcessed"}
+	}
+	if !l.opts.SkipSignatureVerification {
+		if len(tx.Signatures) != int(tx.Message.Header.NumRequiredSignatures) {
+			return nil, &TransactionError{"SignatureFailure"}
+		}
+		if err := tx.VerifySignatures(); err != nil {
+			return nil, &TransactionError{"SignatureFailure"}
+		}
+	}
+	loaded, err := l.resolveLookups(tx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := tx.Message.GetAllKeys()
+	if err != nil {
+		return nil, &TransactionError{"InvalidAccountIndex"}
+	}
+	if l.opts.CheckBlockhash && !l.isBlockhashValid(tx, keys) {
+		return nil, &TransactionError{"BlockhashNotFound"}
+	}
+	budget, txErr := parseComputeBudget(tx)
+	if txErr != nil {
+		return nil, &TransactionError{txErr}
+	}
+
+	accounts := make(map[solana.PublicKey]*Account, len(keys))
+	for _, key := range keys {
+		accounts[key] = l.accounts[key].Clone()
+	}
+
+	fee := l.opts.LamportsPerSignature*uint64(tx.Message.Header.NumRequiredSignatures) + budget.priorityFee()
+	payer := accounts[keys[0]]
+	if payer == nil {
+		return nil, &TransactionError{"AccountNotFound"}
+	}
+	if !payer.Owner.Equals(solana.SystemProgramID) {
+		return nil, &TransactionError{"InvalidAccountForFee"}
+	}
+	if payer.Lamports < fee {
+		return nil, &TransactionError{"InsufficientFundsForFee"}
+	}
+	if rest := payer.Lamports - fee; rest != 0 && rest < MinimumBalanceForRentExemption(uint64(len(payer.Data))) {
+		return nil, &TransactionError{"InsufficientFundsForFee"}
+	}
+
+	pre := snapshotBalances(keys, accounts)
+	preToken := l.tokenBalances(keys, accounts)
+	payer.Lamports -= fee
+
+	working := make(map[solana.PublicKey]*Account, len(accounts))
+	for key, acc := range accounts {
+		working[key] = acc.Clone()
+	}
+	exec := newExecutor(tx, keys, working, budget, l.blockhash, l.opts.LamportsPerSignature)
+	if exec.run() == nil {
+		accounts = working
+	}
+
+	meta := &rpc.TransactionMeta{
+		Err:                  exec.err,
+		Fee:                  fee,
+		PreBalances:          pre,
+		PostBalances:         snapshotBalances(keys, accounts),
+		InnerInstructions:    exec.inner,
+		PreTokenBalances:     preToken,
+		PostTokenBalances:    l.tokenBalances(keys, accounts),
+		LogMessages:          exec.logs,
+		Rewards:              []rpc.BlockReward{},
+		LoadedAddresses:      *loaded,
+		ComputeUnitsConsumed: &exec.consumed,
+	}
+	if exec.err == nil {
+		meta.Status = rpc.DeprecatedTransactionMetaStatus{"Ok": nil}
+	} else {
+		meta.Status = rpc.DeprecatedTransactionMetaStatus{"Err": exec.err}
+	}
+	return &runResult{meta: meta, accounts: accounts}, nil
+}
+
+// isBlockhashValid checks the recent blockhash of the transaction
+// against the ledger's blockhash, or against the durable nonce if the
+// first instruction advances a nonce account. Must be called with mu held.
+func (l *Ledger) isBlockhashValid(tx *solana.Transaction, keys solana.PublicKeySlice) bool {
+	if tx.Message.RecentBlockhash.Equals(l.blockhash) {
+		return true
+	}
+	if len(tx.Message.Instructions) == 0 {
+		return false
+	}
+	first := tx.Message.Instructions[0]
+	programID, err := tx.Message.Program(first.ProgramIDIndex)
+	if err != nil || !programID.Equals(solana.SystemProgramID) || len(first.Accounts) == 0 {
+		return false
+	}
+	if !isSystemAdvanceNonceAccount(first.Data) {
+		return false
+	}
+	nonce, ok := decodeNonce(l.accounts[keys[first.Accounts[0]]])
+	return ok && nonce.Nonce.Equals(solana.PublicKey(tx.Message.RecentBlockhash))
+}
+
+// resolveLookups loads the addresses referenced by the address table lookups
+// of a v0 transaction from the lookup table accounts held by the ledger.
+// Must be called with mu held.
+func (l *Ledger) resolveLookups(tx *solana.Transaction) (*rpc.LoadedAddresses, error) {
+	loaded := &rpc.LoadedAddresses{
+		Writable: solana.PublicKeySlice{},
+		ReadOnly: solana.PublicKeySlice{},
+	}
+	if !tx.Message.IsVersioned() || tx.Message.AddressTableLookups.NumLookups() == 0 {
+		return loaded, nil
+	}
+	tables := make(map[solana.PublicKey]solana.PublicKeySlice)
+	for _, lookup := range tx.Message.AddressTableLookups {
+		acc, ok := l.accounts[lookup.AccountKey]
+		if !ok {
+			return nil, &TransactionError{"AddressLookupTableNotFound"}
+		}
+		state, err := addresslookuptable.DecodeAddressLookupTableState(acc.Data)
+		if err != nil {
+			return nil, &TransactionError{"InvalidAddressLookupTableData"}
+		}
+		tables[lookup.AccountKey] = state.Addresses
+		for _, idx := range lookup.WritableIndexes {
+			if int(idx) >= len(state.Addresses) {
+				return nil, &TransactionError{"InvalidAddressLookupTableIndex"}
+			}
+			loaded.Writable = append(loaded.Writable, state.Addresses[idx])
+		}
+		for _, idx := range lookup.ReadonlyIndexes {
+			if int(idx) >= len(state.Addresses) {
+				return nil, &TransactionError{"InvalidAddressLookupTableIndex"}
+			}
+			loaded.ReadOnly = append(loaded.ReadOnly, state.Addresses[idx])
+		}
+	}
+	if err := tx.Message.SetAddressTables(tables); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
+
+func snapshotBalances(keys solana.PublicKeySlice, accounts map[solana.PublicKey]*Account) []uint64 {
+	out := make([]uint64, len(keys))
+	for i, key := range keys {
+		if acc := accounts[key]; acc != nil {
+			out[i] = acc.Lamports
+		}
+	}
+	return out
+}
+
+// tokenBalances returns the balances of the token accounts of the transaction.
+// The mints that are not part of the transaction are read from the ledger.
+// Must be called with mu held.
+func (l *Ledger) tokenBalances(keys solana.PublicKeySlice, accounts map[solana.PublicKey]*Account) []rpc.TokenBalance {
+	out := make([]rpc.TokenBalance, 0)
+	for i, key := range keys {
+		acc := accounts[key]
+		if acc == nil || !acc.Owner.Equals(solana.TokenProgramID) {
+			continue
+		}
+		tokenAccount, ok := decodeTokenAccount(acc.Data)
+		if !ok || tokenAccount.State == tokenUninitialized {
+			continue
+		}
+		var decimals uint8
+		if tokenAccount.Mint.Equals(solana.SolMint) {
+			decimals = nativeMintDecimals
+		} else {
+			mintAccount, ok := accounts[tokenAccount.Mint]
+			if !ok {
+				mintAccount = l.accounts[tokenAccount.Mint]
+			}
+			if mintAccount != nil {
+				if mint, ok := decodeMint(mintAccount.Data); ok {
+					decimals = mint.Decimals
+				}
+			}
+		}
+		owner := tokenAccount.Owner
+		programID := acc.Owner
+		out = append(out, rpc.TokenBalance{
+			AccountIndex:  uint16(i),
+			Owner:         &owner,
+			ProgramId:     &programID,
+			Mint:          tokenAccount.Mint,
+			UiTokenAmount: uiTokenAmount(tokenAccount.Amount, decimals),
+		})
+	}
+	return out
+}
+
+// MinimumBalanceForRentExemption returns the lamports needed
+// for an account of the provided data size to be rent-exempt,
+// with the default rent parameters.
+func MinimumBalanceForRentExemption(size uint64) uint64 {
+	const (
+		accountStorageOverhead = 128
+		lamportsPerByteYear    = 3480
+		exemptionThreshold     = 2
+	)
+	return (accountStorageOverhead + size) * lamportsPerByteYear * exemptionThreshold
+}
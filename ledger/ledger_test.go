@@ -0,0 +1,219 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"bytes"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+func newTx(t *testing.T, l *Ledger, payer solana.PrivateKey, signers []solana.PrivateKey, instructions ...solana.Instruction) *solana.Transaction {
+	tx, err := solana.NewTransaction(instructions, l.Blockhash(), solana.TransactionPayer(payer.PublicKey()))
+	require.NoError(t, err)
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		for _, signer := range append(signers, payer) {
+			if signer.PublicKey().Equals(key) {
+				return &signer
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestLedger_Transfer(t *testing.T) {
+	l := New(nil)
+	payer := solana.NewWallet().PrivateKey
+	recipient := solana.NewWallet().PublicKey()
+	l.SetBalance(payer.PublicKey(), solana.LAMPORTS_PER_SOL)
+
+	tx := newTx(t, l, payer, nil, system.NewTransferInstruction(1_000_000, payer.PublicKey(), recipient).Build())
+	meta, err := l.Process(tx)
+	require.NoError(t, err)
+	require.Nil(t, meta.Err)
+	require.Equal(t, uint64(5000), meta.Fee)
+	require.Equal(t, []uint64{solana.LAMPORTS_PER_SOL, 0, 0}, meta.PreBalances)
+	require.Equal(t, []uint64{solana.LAMPORTS_PER_SOL - 1_000_000 - 5000, 1_000_000, 0}, meta.PostBalances)
+	require.Equal(t, []string{
+		"Program 11111111111111111111111111111111 invoke [1]",
+		"Program 11111111111111111111111111111111 success",
+	}, meta.LogMessages)
+	require.Equal(t, uint64(1_000_000), l.Balance(recipient))
+
+	// Replays are rejected.
+	_, err = l.Process(tx)
+	require.Equal(t, &TransactionError{"AlreadyProcessed"}, err)
+
+	// Not enough lamports for the recipient to be rent-exempt:
+	// the fee is charged, but the transfer is rolled back.
+	other := solana.NewWallet().PublicKey()
+	tx = newTx(t, l, payer, nil, system.NewTransferInstruction(1, payer.PublicKey(), other).Build())
+	meta, err = l.Process(tx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"InsufficientFundsForRent": map[string]interface{}{"account_index": 1},
+	}, meta.Err)
+	require.Equal(t, solana.LAMPORTS_PER_SOL-1_000_000-10000, l.Balance(payer.PublicKey()))
+	require.Nil(t, l.GetAccount(other))
+
+	// Not enough lamports.
+	tx = newTx(t, l, payer, nil, system.NewTransferInstruction(solana.LAMPORTS_PER_SOL, payer.PublicKey(), other).Build())
+	meta, err = l.Process(tx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"InstructionError": []interface{}{0, map[string]interface{}{"Custom": uint32(1)}},
+	}, meta.Err)
+
+	// Unknown payer.
+	stranger := solana.NewWallet().PrivateKey
+	tx = newTx(t, l, stranger, nil, system.NewTransferInstruction(1, stranger.PublicKey(), other).Build())
+	_, err = l.Process(tx)
+	require.Equal(t, &TransactionError{"AccountNotFound"}, err)
+}
+
+func TestLedger_ReadonlyViolation(t *testing.T) {
+	l := New(nil)
+	payer := solana.NewWallet().PrivateKey
+	recipient := solana.NewWallet().PublicKey()
+	l.SetBalance(payer.PublicKey(), solana.LAMPORTS_PER_SOL)
+
+	transfer := system.NewTransferInstruction(solana.LAMPORTS_PER_SOL/2, payer.PublicKey(), recipient).Build()
+	data, err := transfer.Data()
+	require.NoError(t, err)
+	inst := solana.NewInstruction(solana.SystemProgramID, solana.AccountMetaSlice{
+		solana.Meta(payer.PublicKey()).WRITE().SIGNER(),
+		solana.Meta(recipient),
+	}, data)
+
+	meta, _, err := l.Simulate(newTx(t, l, payer, nil, inst))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"InstructionError": []interface{}{0, "ReadonlyLamportChange"},
+	}, meta.Err)
+}
+
+func TestLedger_Token(t *testing.T) {
+	l := New(nil)
+	payer := solana.NewWallet().PrivateKey
+	mint := solana.NewWallet().PrivateKey
+	alice := solana.NewWallet().PublicKey()
+	bob := solana.NewWallet().PublicKey()
+	l.SetBalance(payer.PublicKey(), 10*solana.LAMPORTS_PER_SOL)
+
+	aliceATA, _, err := solana.FindAssociatedTokenAddress(alice, mint.PublicKey())
+	require.NoError(t, err)
+	bobATA, _, err := solana.FindAssociatedTokenAddress(bob, mint.PublicKey())
+	require.NoError(t, err)
+
+	tx := newTx(t, l, payer, []solana.PrivateKey{mint},
+		system.NewCreateAccountInstruction(
+			MinimumBalanceForRentExemption(token.MINT_SIZE),
+			token.MINT_SIZE,
+			solana.TokenProgramID,
+			payer.PublicKey(),
+			mint.PublicKey(),
+		).Build(),
+		token.NewInitializeMint2Instruction(6, payer.PublicKey(), payer.PublicKey(), mint.PublicKey()).Build(),
+		associatedtokenaccount.NewCreateInstruction(payer.PublicKey(), alice, mint.PublicKey()).Build(),
+		associatedtokenaccount.NewCreateInstruction(payer.PublicKey(), bob, mint.PublicKey()).Build(),
+		token.NewMintToInstruction(1_500_000, mint.PublicKey(), aliceATA, payer.PublicKey(), nil).Build(),
+	)
+	meta, err := l.Process(tx)
+	require.NoError(t, err)
+	require.Nil(t, meta.Err, meta.LogMessages)
+	require.Len(t, meta.InnerInstructions, 2)
+	require.Equal(t, uint16(2), meta.InnerInstructions[0].Index)
+	require.Len(t, meta.InnerInstructions[0].Instructions, 2)
+	require.Equal(t, uint16(2), meta.InnerInstructions[0].Instructions[0].StackHeight)
+	require.Len(t, meta.PostTokenBalances, 2)
+
+	account, ok := decodeTokenAccount(l.GetAccount(aliceATA).Data)
+	require.True(t, ok)
+	require.Equal(t, alice, account.Owner)
+	require.Equal(t, uint64(1_500_000), account.Amount)
+
+	// Only the owner can transfer.
+	aliceKey := solana.NewWallet().PrivateKey
+	tx = newTx(t, l, payer, nil, token.NewTransferInstruction(1, aliceATA, bobATA, payer.PublicKey(), nil).Build())
+	meta, err = l.Process(tx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"InstructionError": []interface{}{0, map[string]interface{}{"Custom": uint32(4)}},
+	}, meta.Err)
+
+	// Hand alice's account over to a key we hold, then transfer.
+	l.SetAccount(aliceATA, func() *Account {
+		acc := l.GetAccount(aliceATA)
+		account.Owner = aliceKey.PublicKey()
+		buf := new(bytes.Buffer)
+		require.NoError(t, bin.NewBinEncoder(buf).Encode(account))
+		acc.Data = buf.Bytes()
+		return acc
+	}())
+	tx = newTx(t, l, payer, []solana.PrivateKey{aliceKey},
+		token.NewTransferInstruction(500_000, aliceATA, bobATA, aliceKey.PublicKey(), nil).Build(),
+	)
+	meta, err = l.Process(tx)
+	require.NoError(t, err)
+	require.Nil(t, meta.Err, meta.LogMessages)
+	require.Equal(t, "1.5", meta.PreTokenBalances[0].UiTokenAmount.UiAmountString)
+	require.Equal(t, "1", meta.PostTokenBalances[0].UiTokenAmount.UiAmountString)
+	require.Equal(t, "0.5", meta.PostTokenBalances[1].UiTokenAmount.UiAmountString)
+}
+
+func TestLedger_ComputeBudget(t *testing.T) {
+	l := New(nil)
+	payer := solana.NewWallet().PrivateKey
+	l.SetBalance(payer.PublicKey(), solana.LAMPORTS_PER_SOL)
+	recipient := solana.NewWallet().PublicKey()
+
+	tx := newTx(t, l, payer, nil,
+		computebudget.NewSetComputeUnitLimitInstruction(1000).Build(),
+		computebudget.NewSetComputeUnitPriceInstruction(1_000_001).Build(),
+		system.NewTransferInstruction(solana.LAMPORTS_PER_SOL/2, payer.PublicKey(), recipient).Build(),
+	)
+	meta, err := l.Process(tx)
+	require.NoError(t, err)
+	require.Nil(t, meta.Err)
+	require.Equal(t, uint64(5000+1001), meta.Fee)
+	require.Equal(t, uint64(450), *meta.ComputeUnitsConsumed)
+
+	tx = newTx(t, l, payer, nil,
+		computebudget.NewSetComputeUnitLimitInstruction(1000).Build(),
+		computebudget.NewSetComputeUnitLimitInstruction(2000).Build(),
+	)
+	_, err = l.Process(tx)
+	require.Equal(t, &TransactionError{map[string]interface{}{"DuplicateInstruction": 1}}, err)
+
+	tx = newTx(t, l, payer, nil,
+		computebudget.NewSetComputeUnitLimitInstruction(200).Build(),
+		system.NewTransferInstruction(1, payer.PublicKey(), recipient).Build(),
+	)
+	meta, err = l.Process(tx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"InstructionError": []interface{}{1, "ComputationalBudgetExceeded"},
+	}, meta.Err)
+}
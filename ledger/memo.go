@@ -0,0 +1,53 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"unicode/utf8"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func memoCost(data []byte) uint64 {
+	return 6000 + 20*uint64(len(data))
+}
+
+// processMemo executes the instructions of the SPL Memo program:
+// all the accounts must be signers, and the memo must be valid UTF-8.
+func processMemo(e *executor, metas []*solana.AccountMeta, data []byte) error {
+	for _, meta := range metas {
+		e.programLog("Signed by %s", meta.PublicKey)
+		if !meta.IsSigner {
+			return errMissingRequiredSignature
+		}
+	}
+	if !utf8.Valid(data) {
+		e.programLog("Invalid UTF-8, from byte %d", firstInvalidUTF8(data))
+		return errInvalidInstructionData
+	}
+	e.programLog("Memo (len %d): %q", len(data), string(data))
+	return nil
+}
+
+func firstInvalidUTF8(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return i
+		}
+		i += size
+	}
+	return len(data)
+}
@@ -0,0 +1,346 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// SystemError codes.
+var (
+	errSystemAccountAlreadyInUse        = customError(0)
+	errSystemResultWithNegativeLamports = customError(1)
+	errSystemInvalidAccountDataLength   = customError(3)
+	errSystemMaxSeedLengthExceeded      = customError(4)
+	errSystemAddressWithSeedMismatch    = customError(5)
+	errSystemNonceBlockhashNotExpired   = customError(7)
+)
+
+const (
+	// Maximum size of the data of an account.
+	maxPermittedDataLength = 10 * 1024 * 1024
+	// Maximum length of the seed of an address derived with CreateWithSeed.
+	maxSeedLength = 32
+
+	nonceAccountSize        = 80
+	nonceVersionCurrent     = 1
+	nonceStateUninitialized = 0
+	nonceStateInitialized   = 1
+)
+
+func isSystemAdvanceNonceAccount(data []byte) bool {
+	return len(data) >= 4 && binary.LittleEndian.Uint32(data) == system.Instruction_AdvanceNonceAccount
+}
+
+// durableNonce returns the nonce value stored by AdvanceNonceAccount for the provided blockhash.
+func durableNonce(blockhash solana.Hash) solana.PublicKey {
+	sum := sha256.Sum256(append([]byte("DURABLE_NONCE"), blockhash[:]...))
+	return solana.PublicKeyFromBytes(sum[:])
+}
+
+func decodeNonce(acc *Account) (system.NonceAccount, bool) {
+	var nonce system.NonceAccount
+	if acc == nil || !acc.Owner.Equals(solana.SystemProgramID) || len(acc.Data) != nonceAccountSize {
+		return nonce, false
+	}
+	if err := bin.NewBinDecoder(acc.Data).Decode(&nonce); err != nil {
+		return nonce, false
+	}
+	return nonce, nonce.State == nonceStateInitialized
+}
+
+func encodeNonce(acc *Account, nonce system.NonceAccount) error {
+	buf := new(bytes.Buffer)
+	if err := bin.NewBinEncoder(buf).Encode(nonce); err != nil {
+		return err
+	}
+	acc.Data = buf.Bytes()
+	return nil
+}
+
+// isSigner reports whether any of the accounts of the instruction is the provided signer.
+func isSigner(metas []*solana.AccountMeta, key solana.PublicKey) bool {
+	for _, meta := range metas {
+		if meta.IsSigner && meta.PublicKey.Equals(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func processSystem(e *executor, metas []*solana.AccountMeta, data []byte) error {
+	inst := new(system.Instruction)
+	if err := bin.NewBinDecoder(data).Decode(inst); err != nil {
+		return errInvalidInstructionData
+	}
+	switch impl := inst.Impl.(type) {
+	case *system.CreateAccount:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		if !metas[1].IsSigner {
+			return errMissingRequiredSignature
+		}
+		return e.systemCreate(metas[0], metas[1], *impl.Lamports, *impl.Space, *impl.Owner)
+	case *system.Assign:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.systemAssign(metas[0], *impl.Owner)
+	case *system.Transfer:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		return e.systemTransfer(metas[0], metas[1], *impl.Lamports)
+	case *system.CreateAccountWithSeed:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		if err := checkSeedAddress(metas[1].PublicKey, *impl.Base, *impl.Seed, *impl.Owner); err != nil {
+			return err
+		}
+		if !isSigner(metas, *impl.Base) {
+			return errMissingRequiredSignature
+		}
+		return e.systemCreate(metas[0], metas[1], *impl.Lamports, *impl.Space, *impl.Owner)
+	case *system.AdvanceNonceAccount:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.advanceNonce(metas)
+	case *system.WithdrawNonceAccount:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		return e.withdrawNonce(metas, *impl.Lamports)
+	case *system.InitializeNonceAccount:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.initializeNonce(metas[0], *impl.Authorized)
+	case *system.AuthorizeNonceAccount:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.authorizeNonce(metas, *impl.Authorized)
+	case *system.Allocate:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.systemAllocate(metas[0], *impl.Space, solana.SystemProgramID)
+	case *system.AllocateWithSeed:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		if err := checkSeedAddress(metas[0].PublicKey, *impl.Base, *impl.Seed, *impl.Owner); err != nil {
+			return err
+		}
+		if !isSigner(metas, *impl.Base) {
+			return errMissingRequiredSignature
+		}
+		return e.systemAllocateUnsigned(metas[0], *impl.Space, *impl.Owner)
+	case *system.AssignWithSeed:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		if err := checkSeedAddress(metas[0].PublicKey, *impl.Base, *impl.Seed, *impl.Owner); err != nil {
+			return err
+		}
+		if !isSigner(metas, *impl.Base) {
+			return errMissingRequiredSignature
+		}
+		e.account(metas[0].PublicKey).Owner = *impl.Owner
+		return nil
+	case *system.TransferWithSeed:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		base := metas[1].PublicKey
+		if err := checkSeedAddress(metas[0].PublicKey, base, *impl.FromSeed, *impl.FromOwner); err != nil {
+			return err
+		}
+		if !metas[1].IsSigner {
+			return errMissingRequiredSignature
+		}
+		return e.transferLamports(metas[0], metas[2], *impl.Lamports)
+	default:
+		return errInvalidInstructionData
+	}
+}
+
+func checkSeedAddress(address, base solana.PublicKey, seed string, owner solana.PublicKey) error {
+	if len(seed) > maxSeedLength {
+		return errSystemMaxSeedLengthExceeded
+	}
+	derived, err := solana.CreateWithSeed(base, seed, owner)
+	if err != nil || !derived.Equals(address) {
+		return errSystemAddressWithSeedMismatch
+	}
+	return nil
+}
+
+// systemCreate funds, allocates and assigns a new account;
+// the signature of the new account must have been checked by the caller.
+func (e *executor) systemCreate(from, to *solana.AccountMeta, lamports, space uint64, owner solana.PublicKey) error {
+	if e.account(to.PublicKey).Lamports > 0 {
+		e.log("Create Account: account %s already in use", to.PublicKey)
+		return errSystemAccountAlreadyInUse
+	}
+	if err := e.systemAllocateUnsigned(to, space, owner); err != nil {
+		return err
+	}
+	return e.systemTransfer(from, to, lamports)
+}
+
+// systemAllocate allocates the data of a system account and assigns it to owner;
+// the account must sign.
+func (e *executor) systemAllocate(meta *solana.AccountMeta, space uint64, owner solana.PublicKey) error {
+	if !meta.IsSigner {
+		return errMissingRequiredSignature
+	}
+	return e.systemAllocateUnsigned(meta, space, owner)
+}
+
+func (e *executor) systemAllocateUnsigned(meta *solana.AccountMeta, space uint64, owner solana.PublicKey) error {
+	acc := e.account(meta.PublicKey)
+	if len(acc.Data) != 0 || !acc.Owner.Equals(solana.SystemProgramID) {
+		e.log("Allocate: account %s already in use", meta.PublicKey)
+		return errSystemAccountAlreadyInUse
+	}
+	if space > maxPermittedDataLength {
+		return errSystemInvalidAccountDataLength
+	}
+	acc.Data = make([]byte, space)
+	acc.Owner = owner
+	return nil
+}
+
+func (e *executor) systemAssign(meta *solana.AccountMeta, owner solana.PublicKey) error {
+	acc := e.account(meta.PublicKey)
+	if acc.Owner.Equals(owner) {
+		return nil
+	}
+	if !meta.IsSigner {
+		return errMissingRequiredSignature
+	}
+	acc.Owner = owner
+	return nil
+}
+
+// systemTransfer moves lamports out of a system account, which must sign.
+func (e *executor) systemTransfer(from, to *solana.AccountMeta, lamports uint64) error {
+	if !from.IsSigner {
+		return errMissingRequiredSignature
+	}
+	return e.transferLamports(from, to, lamports)
+}
+
+func (e *executor) transferLamports(from, to *solana.AccountMeta, lamports uint64) error {
+	src := e.account(from.PublicKey)
+	if len(src.Data) != 0 {
+		e.log("Transfer: `from` must not carry data")
+		return errInvalidArgument
+	}
+	if src.Lamports < lamports {
+		e.log("Transfer: insufficient lamports %d, need %d", src.Lamports, lamports)
+		return errSystemResultWithNegativeLamports
+	}
+	src.Lamports -= lamports
+	e.account(to.PublicKey).Lamports += lamports
+	return nil
+}
+
+func (e *executor) advanceNonce(metas []*solana.AccountMeta) error {
+	acc := e.account(metas[0].PublicKey)
+	nonce, ok := decodeNonce(acc)
+	if !ok {
+		return errInvalidAccountData
+	}
+	if !isSigner(metas, nonce.AuthorizedPubkey) {
+		return errMissingRequiredSignature
+	}
+	next := durableNonce(e.blockhash)
+	if nonce.Nonce.Equals(next) {
+		e.log("Advance nonce account: nonce can only advance once per slot")
+		return errSystemNonceBlockhashNotExpired
+	}
+	nonce.Nonce = next
+	nonce.FeeCalculator.LamportsPerSignature = e.lamportsPerSignature
+	return encodeNonce(acc, nonce)
+}
+
+func (e *executor) withdrawNonce(metas []*solana.AccountMeta, lamports uint64) error {
+	acc := e.account(metas[0].PublicKey)
+	if nonce, ok := decodeNonce(acc); ok {
+		if !isSigner(metas, nonce.AuthorizedPubkey) {
+			return errMissingRequiredSignature
+		}
+		if lamports == acc.Lamports {
+			if nonce.Nonce.Equals(durableNonce(e.blockhash)) {
+				return errSystemNonceBlockhashNotExpired
+			}
+			nonce.State = nonceStateUninitialized
+			if err := encodeNonce(acc, nonce); err != nil {
+				return err
+			}
+		} else if lamports > acc.Lamports || acc.Lamports-lamports < MinimumBalanceForRentExemption(uint64(len(acc.Data))) {
+			return errInsufficientFunds
+		}
+	} else if !metas[0].IsSigner {
+		return errMissingRequiredSignature
+	}
+	if lamports > acc.Lamports {
+		return errInsufficientFunds
+	}
+	acc.Lamports -= lamports
+	e.account(metas[1].PublicKey).Lamports += lamports
+	return nil
+}
+
+func (e *executor) initializeNonce(meta *solana.AccountMeta, authority solana.PublicKey) error {
+	acc := e.account(meta.PublicKey)
+	if _, ok := decodeNonce(acc); ok || len(acc.Data) != nonceAccountSize {
+		return errInvalidAccountData
+	}
+	if acc.Lamports < MinimumBalanceForRentExemption(uint64(len(acc.Data))) {
+		return errInsufficientFunds
+	}
+	return encodeNonce(acc, system.NonceAccount{
+		Version:          nonceVersionCurrent,
+		State:            nonceStateInitialized,
+		AuthorizedPubkey: authority,
+		Nonce:            durableNonce(e.blockhash),
+		FeeCalculator:    system.FeeCalculator{LamportsPerSignature: e.lamportsPerSignature},
+	})
+}
+
+func (e *executor) authorizeNonce(metas []*solana.AccountMeta, authority solana.PublicKey) error {
+	acc := e.account(metas[0].PublicKey)
+	nonce, ok := decodeNonce(acc)
+	if !ok {
+		return errInvalidAccountData
+	}
+	if !isSigner(metas, nonce.AuthorizedPubkey) {
+		return errMissingRequiredSignature
+	}
+	nonce.AuthorizedPubkey = authority
+	return encodeNonce(acc, nonce)
+}
@@ -0,0 +1,807 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// TokenError codes.
+var (
+	errTokenNotRentExempt                  = customError(0)
+	errTokenInsufficientFunds              = customError(1)
+	errTokenInvalidMint                    = customError(2)
+	errTokenMintMismatch                   = customError(3)
+	errTokenOwnerMismatch                  = customError(4)
+	errTokenFixedSupply                    = customError(5)
+	errTokenAlreadyInUse                   = customError(6)
+	errTokenInvalidNumberOfProvidedSigners = customError(7)
+	errTokenInvalidNumberOfRequiredSigners = customError(8)
+	errTokenNativeNotMintable              = customError(10)
+	errTokenNonNativeHasBalance            = customError(11)
+	errTokenInvalidInstruction             = customError(12)
+	errTokenInvalidState                   = customError(13)
+	errTokenOverflow                       = customError(14)
+	errTokenAuthorityTypeNotSupported      = customError(15)
+	errTokenMintCannotFreeze               = customError(16)
+	errTokenAccountFrozen                  = customError(17)
+	errTokenMintDecimalsMismatch           = customError(18)
+	errTokenNonNativeNotSupported          = customError(19)
+
+	errUninitializedAccount = newProgramError("UninitializedAccount", "An account's data was too small")
+)
+
+const (
+	tokenAccountSize   = 165
+	tokenMultisigSize  = 355
+	tokenUninitialized = token.Uninitialized
+	nativeMintDecimals = 9
+)
+
+func decodeTokenAccount(data []byte) (*token.Account, bool) {
+	if len(data) != tokenAccountSize {
+		return nil, false
+	}
+	out := new(token.Account)
+	if err := bin.NewBinDecoder(data).Decode(out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func decodeMint(data []byte) (*token.Mint, bool) {
+	if len(data) != token.MINT_SIZE {
+		return nil, false
+	}
+	out := new(token.Mint)
+	if err := bin.NewBinDecoder(data).Decode(out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func decodeMultisig(data []byte) (*token.Multisig, bool) {
+	if len(data) != tokenMultisigSize {
+		return nil, false
+	}
+	out := new(token.Multisig)
+	if err := bin.NewBinDecoder(data).Decode(out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// uiTokenAmount renders a raw token amount with the provided decimals.
+func uiTokenAmount(amount uint64, decimals uint8) *rpc.UiTokenAmount {
+	str := strconv.FormatUint(amount, 10)
+	if decimals > 0 {
+		if len(str) <= int(decimals) {
+			str = strings.Repeat("0", int(decimals)-len(str)+1) + str
+		}
+		point := len(str) - int(decimals)
+		str = strings.TrimRight(str[:point]+"."+str[point:], "0")
+		str = strings.TrimSuffix(str, ".")
+	}
+	ui, _ := strconv.ParseFloat(str, 64)
+	return &rpc.UiTokenAmount{
+		Amount:         strconv.FormatUint(amount, 10),
+		Decimals:       decimals,
+		UiAmount:       &ui,
+		UiAmountString: str,
+	}
+}
+
+// Approximate compute units consumed by the token instructions.
+var tokenCosts = map[uint8]uint64{
+	token.Instruction_InitializeMint:     2967,
+	token.Instruction_InitializeMint2:    2827,
+	token.Instruction_InitializeAccount:  4527,
+	token.Instruction_InitializeAccount2: 4388,
+	token.Instruction_InitializeAccount3: 4241,
+	token.Instruction_Transfer:           4645,
+	token.Instruction_TransferChecked:    6200,
+	token.Instruction_MintTo:             4538,
+	token.Instruction_MintToChecked:      4477,
+	token.Instruction_Burn:               4753,
+	token.Instruction_BurnChecked:        4677,
+	token.Instruction_CloseAccount:       2916,
+}
+
+func tokenCost(data []byte) uint64 {
+	if len(data) > 0 {
+		if units, ok := tokenCosts[data[0]]; ok {
+			return units
+		}
+	}
+	return 3000
+}
+
+func processToken(e *executor, metas []*solana.AccountMeta, data []byte) error {
+	inst := new(token.Instruction)
+	if err := bin.NewBinDecoder(data).Decode(inst); err != nil {
+		return errTokenInvalidInstruction
+	}
+	e.programLog("Instruction: %s", token.InstructionIDToName(inst.TypeID.Uint8()))
+	switch impl := inst.Impl.(type) {
+	case *token.InitializeMint:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		return e.tokenInitializeMint(metas[0], *impl.Decimals, *impl.MintAuthority, impl.FreezeAuthority)
+	case *token.InitializeMint2:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.tokenInitializeMint(metas[0], *impl.Decimals, *impl.MintAuthority, impl.FreezeAuthority)
+	case *token.InitializeAccount:
+		if err := requireAccounts(metas, 4); err != nil {
+			return err
+		}
+		return e.tokenInitializeAccount(metas[0], metas[1], metas[2].PublicKey)
+	case *token.InitializeAccount2:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenInitializeAccount(metas[0], metas[1], *impl.Owner)
+	case *token.InitializeAccount3:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		return e.tokenInitializeAccount(metas[0], metas[1], *impl.Owner)
+	case *token.InitializeMultisig:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		return e.tokenInitializeMultisig(metas[0], metas[2:], *impl.M)
+	case *token.InitializeMultisig2:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.tokenInitializeMultisig(metas[0], metas[1:], *impl.M)
+	case *token.Transfer:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenTransfer(metas[0], nil, metas[1], metas[2], metas[3:], *impl.Amount, nil)
+	case *token.TransferChecked:
+		if err := requireAccounts(metas, 4); err != nil {
+			return err
+		}
+		return e.tokenTransfer(metas[0], metas[1], metas[2], metas[3], metas[4:], *impl.Amount, impl.Decimals)
+	case *token.Approve:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenApprove(metas[0], nil, metas[1], metas[2], metas[3:], *impl.Amount, nil)
+	case *token.ApproveChecked:
+		if err := requireAccounts(metas, 4); err != nil {
+			return err
+		}
+		return e.tokenApprove(metas[0], metas[1], metas[2], metas[3], metas[4:], *impl.Amount, impl.Decimals)
+	case *token.Revoke:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		return e.tokenRevoke(metas[0], metas[1], metas[2:])
+	case *token.SetAuthority:
+		if err := requireAccounts(metas, 2); err != nil {
+			return err
+		}
+		return e.tokenSetAuthority(metas[0], metas[1], metas[2:], *impl.AuthorityType, impl.NewAuthority)
+	case *token.MintTo:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenMintTo(metas[0], metas[1], metas[2], metas[3:], *impl.Amount, nil)
+	case *token.MintToChecked:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenMintTo(metas[0], metas[1], metas[2], metas[3:], *impl.Amount, impl.Decimals)
+	case *token.Burn:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenBurn(metas[0], metas[1], metas[2], metas[3:], *impl.Amount, nil)
+	case *token.BurnChecked:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenBurn(metas[0], metas[1], metas[2], metas[3:], *impl.Amount, impl.Decimals)
+	case *token.CloseAccount:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenCloseAccount(metas[0], metas[1], metas[2], metas[3:])
+	case *token.FreezeAccount:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenToggleFreeze(metas[0], metas[1], metas[2], metas[3:], true)
+	case *token.ThawAccount:
+		if err := requireAccounts(metas, 3); err != nil {
+			return err
+		}
+		return e.tokenToggleFreeze(metas[0], metas[1], metas[2], metas[3:], false)
+	case *token.SyncNative:
+		if err := requireAccounts(metas, 1); err != nil {
+			return err
+		}
+		return e.tokenSyncNative(metas[0])
+	default:
+		return errTokenInvalidInstruction
+	}
+}
+
+// tokenAccountData returns the data of an account owned by the token program.
+func (e *executor) tokenAccountData(meta *solana.AccountMeta) (*Account, error) {
+	acc := e.account(meta.PublicKey)
+	if !acc.Owner.Equals(solana.TokenProgramID) {
+		return nil, errIncorrectProgramID
+	}
+	return acc, nil
+}
+
+func (e *executor) loadTokenAccount(meta *solana.AccountMeta) (*token.Account, error) {
+	acc, err := e.tokenAccountData(meta)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := decodeTokenAccount(acc.Data)
+	if !ok {
+		return nil, errInvalidAccountData
+	}
+	if out.State == tokenUninitialized {
+		return nil, errUninitializedAccount
+	}
+	return out, nil
+}
+
+func (e *executor) loadMint(meta *solana.AccountMeta) (*token.Mint, error) {
+	if acc := e.accounts[meta.PublicKey]; meta.PublicKey.Equals(solana.SolMint) && (acc == nil || len(acc.Data) == 0) {
+		// The native mint is not required to be held in the ledger.
+		return &token.Mint{Decimals: nativeMintDecimals, IsInitialized: true}, nil
+	}
+	acc, err := e.tokenAccountData(meta)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := decodeMint(acc.Data)
+	if !ok {
+		return nil, errInvalidAccountData
+	}
+	if !out.IsInitialized {
+		return nil, errUninitializedAccount
+	}
+	return out, nil
+}
+
+func (e *executor) store(meta *solana.AccountMeta, value interface{}) error {
+	buf := new(bytes.Buffer)
+	if err := bin.NewBinEncoder(buf).Encode(value); err != nil {
+		return errInvalidAccountData
+	}
+	acc := e.account(meta.PublicKey)
+	if buf.Len() != len(acc.Data) {
+		return errInvalidAccountData
+	}
+	copy(acc.Data, buf.Bytes())
+	return nil
+}
+
+func (e *executor) isRentExempt(acc *Account) bool {
+	return acc.Lamports >= MinimumBalanceForRentExemption(uint64(len(acc.Data)))
+}
+
+// validateOwner checks that the authority is the expected owner and that it signed
+// the transaction; multisig authorities need M of their signers to be in signers.
+func (e *executor) validateOwner(expected solana.PublicKey, authority *solana.AccountMeta, signers []*solana.AccountMeta) error {
+	if !expected.Equals(authority.PublicKey) {
+		return errTokenOwnerMismatch
+	}
+	acc := e.accounts[authority.PublicKey]
+	if acc != nil && acc.Owner.Equals(solana.TokenProgramID) {
+		if multisig, ok := decodeMultisig(acc.Data); ok && multisig.IsInitialized {
+			matched := make([]bool, token.MAX_SIGNERS)
+			var count uint8
+			for _, signer := range signers {
+				for i, key := range multisig.Signers[:multisig.N] {
+					if key.Equals(signer.PublicKey) && !matched[i] {
+						if !signer.IsSigner {
+							return errMissingRequiredSignature
+						}
+						matched[i] = true
+						count++
+					}
+				}
+			}
+			if count < multisig.M {
+				return errMissingRequiredSignature
+			}
+			return nil
+		}
+	}
+	if !authority.IsSigner {
+		return errMissingRequiredSignature
+	}
+	return nil
+}
+
+func checkDecimals(mint *token.Mint, decimals *uint8) error {
+	if decimals != nil && *decimals != mint.Decimals {
+		return errTokenMintDecimalsMismatch
+	}
+	return nil
+}
+
+func (e *executor) tokenInitializeMint(meta *solana.AccountMeta, decimals uint8, mintAuthority solana.PublicKey, freezeAuthority *solana.PublicKey) error {
+	acc, err := e.tokenAccountData(meta)
+	if err != nil {
+		return err
+	}
+	mint, ok := decodeMint(acc.Data)
+	if !ok {
+		return errInvalidAccountData
+	}
+	if mint.IsInitialized {
+		return errTokenAlreadyInUse
+	}
+	if !e.isRentExempt(acc) {
+		return errTokenNotRentExempt
+	}
+	return e.store(meta, token.Mint{
+		MintAuthority:   &mintAuthority,
+		Decimals:        decimals,
+		IsInitialized:   true,
+		FreezeAuthority: freezeAuthority,
+	})
+}
+
+func (e *executor) tokenInitializeAccount(meta, mintMeta *solana.AccountMeta, owner solana.PublicKey) error {
+	acc, err := e.tokenAccountData(meta)
+	if err != nil {
+		return err
+	}
+	account, ok := decodeTokenAccount(acc.Data)
+	if !ok {
+		return errInvalidAccountData
+	}
+	if account.State != tokenUninitialized {
+		return errTokenAlreadyInUse
+	}
+	if !e.isRentExempt(acc) {
+		return errTokenNotRentExempt
+	}
+	out := token.Account{
+		Mint:  mintMeta.PublicKey,
+		Owner: owner,
+		State: token.Initialized,
+	}
+	if mintMeta.PublicKey.Equals(solana.SolMint) {
+		reserve := MinimumBalanceForRentExemption(uint64(len(acc.Data)))
+		out.IsNative = &reserve
+		out.Amount = acc.Lamports - reserve
+	} else if _, err := e.loadMint(mintMeta); err != nil {
+		return errTokenInvalidMint
+	}
+	return e.store(meta, &out)
+}
+
+func (e *executor) tokenInitializeMultisig(meta *solana.AccountMeta, signers []*solana.AccountMeta, m uint8) error {
+	acc, err := e.tokenAccountData(meta)
+	if err != nil {
+		return err
+	}
+	multisig, ok := decodeMultisig(acc.Data)
+	if !ok {
+		return errInvalidAccountData
+	}
+	if multisig.IsInitialized {
+		return errTokenAlreadyInUse
+	}
+	if !e.isRentExempt(acc) {
+		return errTokenNotRentExempt
+	}
+	if len(signers) < 1 || len(signers) > token.MAX_SIGNERS {
+		return errTokenInvalidNumberOfProvidedSigners
+	}
+	if m < 1 || int(m) > len(signers) {
+		return errTokenInvalidNumberOfRequiredSigners
+	}
+	out := token.Multisig{M: m, N: uint8(len(signers)), IsInitialized: true}
+	for i, signer := range signers {
+		out.Signers[i] = signer.PublicKey
+	}
+	return e.store(meta, out)
+}
+
+// spendAuthority checks that authority can spend amount from the account, either
+// as its delegate or as its owner, and decreases the delegated amount if needed.
+func (e *executor) spendAuthority(account *token.Account, authority *solana.AccountMeta, signers []*solana.AccountMeta, amount uint64) error {
+	if account.Delegate != nil && account.Delegate.Equals(authority.PublicKey) {
+		if err := e.validateOwner(*account.Delegate, authority, signers); err != nil {
+			return err
+		}
+		if account.DelegatedAmount < amount {
+			return errTokenInsufficientFunds
+		}
+		account.DelegatedAmount -= amount
+		if account.DelegatedAmount == 0 {
+			account.Delegate = nil
+		}
+		return nil
+	}
+	return e.validateOwner(account.Owner, authority, signers)
+}
+
+func (e *executor) tokenTransfer(
+	srcMeta, mintMeta, dstMeta, authority *solana.AccountMeta,
+	signers []*solana.AccountMeta,
+	amount uint64,
+	decimals *uint8,
+) error {
+	src, err := e.loadTokenAccount(srcMeta)
+	if err != nil {
+		return err
+	}
+	dst, err := e.loadTokenAccount(dstMeta)
+	if err != nil {
+		return err
+	}
+	if src.State == token.Frozen || dst.State == token.Frozen {
+		return errTokenAccountFrozen
+	}
+	if src.Amount < amount {
+		return errTokenInsufficientFunds
+	}
+	if !src.Mint.Equals(dst.Mint) {
+		return errTokenMintMismatch
+	}
+	if mintMeta != nil {
+		if !mintMeta.PublicKey.Equals(src.Mint) {
+			return errTokenMintMismatch
+		}
+		mint, err := e.loadMint(mintMeta)
+		if err != nil {
+			return err
+		}
+		if err := checkDecimals(mint, decimals); err != nil {
+			return err
+		}
+	}
+	if err := e.spendAuthority(src, authority, signers, amount); err != nil {
+		return err
+	}
+	if srcMeta.PublicKey.Equals(dstMeta.PublicKey) {
+		return nil
+	}
+	src.Amount -= amount
+	if dst.Amount+amount < dst.Amount {
+		return errTokenOverflow
+	}
+	dst.Amount += amount
+	if src.IsNative != nil {
+		srcAcc := e.account(srcMeta.PublicKey)
+		if srcAcc.Lamports < amount {
+			return errTokenInsufficientFunds
+		}
+		srcAcc.Lamports -= amount
+		e.account(dstMeta.PublicKey).Lamports += amount
+	}
+	if err := e.store(srcMeta, src); err != nil {
+		return err
+	}
+	return e.store(dstMeta, dst)
+}
+
+func (e *executor) tokenApprove(
+	srcMeta, mintMeta, delegate, owner *solana.AccountMeta,
+	signers []*solana.AccountMeta,
+	amount uint64,
+	decimals *uint8,
+) error {
+	src, err := e.loadTokenAccount(srcMeta)
+	if err != nil {
+		return err
+	}
+	if src.State == token.Frozen {
+		return errTokenAccountFrozen
+	}
+	if mintMeta != nil {
+		if !mintMeta.PublicKey.Equals(src.Mint) {
+			return errTokenMintMismatch
+		}
+		mint, err := e.loadMint(mintMeta)
+		if err != nil {
+			return err
+		}
+		if err := checkDecimals(mint, decimals); err != nil {
+			return err
+		}
+	}
+	if err := e.validateOwner(src.Owner, owner, signers); err != nil {
+		return err
+	}
+	key := delegate.PublicKey
+	src.Delegate = &key
+	src.DelegatedAmount = amount
+	return e.store(srcMeta, src)
+}
+
+func (e *executor) tokenRevoke(srcMeta, owner *solana.AccountMeta, signers []*solana.AccountMeta) error {
+	src, err := e.loadTokenAccount(srcMeta)
+	if err != nil {
+		return err
+	}
+	if src.State == token.Frozen {
+		return errTokenAccountFrozen
+	}
+	if err := e.validateOwner(src.Owner, owner, signers); err != nil {
+		return err
+	}
+	src.Delegate = nil
+	src.DelegatedAmount = 0
+	return e.store(srcMeta, src)
+}
+
+func (e *executor) tokenSetAuthority(
+	meta, authority *solana.AccountMeta,
+	signers []*solana.AccountMeta,
+	authorityType token.AuthorityType,
+	newAuthority *solana.PublicKey,
+) error {
+	acc, err := e.tokenAccountData(meta)
+	if err != nil {
+		return err
+	}
+	switch len(acc.Data) {
+	case tokenAccountSize:
+		account, err := e.loadTokenAccount(meta)
+		if err != nil {
+			return err
+		}
+		if account.State == token.Frozen {
+			return errTokenAccountFrozen
+		}
+		switch authorityType {
+		case token.AuthorityAccountOwner:
+			if err := e.validateOwner(account.Owner, authority, signers); err != nil {
+				return err
+			}
+			if newAuthority == nil {
+				return errTokenInvalidInstruction
+			}
+			account.Owner = *newAuthority
+			account.Delegate = nil
+			account.DelegatedAmount = 0
+			if account.IsNative != nil {
+				account.CloseAuthority = nil
+			}
+		case token.AuthorityCloseAccount:
+			current := account.Owner
+			if account.CloseAuthority != nil {
+				current = *account.CloseAuthority
+			}
+			if err := e.validateOwner(current, authority, signers); err != nil {
+				return err
+			}
+			account.CloseAuthority = newAuthority
+		default:
+			return errTokenAuthorityTypeNotSupported
+		}
+		return e.store(meta, account)
+	case token.MINT_SIZE:
+		mint, err := e.loadMint(meta)
+		if err != nil {
+			return err
+		}
+		switch authorityType {
+		case token.AuthorityMintTokens:
+			if mint.MintAuthority == nil {
+				return errTokenFixedSupply
+			}
+			if err := e.validateOwner(*mint.MintAuthority, authority, signers); err != nil {
+				return err
+			}
+			mint.MintAuthority = newAuthority
+		case token.AuthorityFreezeAccount:
+			if mint.FreezeAuthority == nil {
+				return errTokenMintCannotFreeze
+			}
+			if err := e.validateOwner(*mint.FreezeAuthority, authority, signers); err != nil {
+				return err
+			}
+			mint.FreezeAuthority = newAuthority
+		default:
+			return errTokenAuthorityTypeNotSupported
+		}
+		return e.store(meta, mint)
+	default:
+		return errInvalidArgument
+	}
+}
+
+func (e *executor) tokenMintTo(
+	mintMeta, dstMeta, authority *solana.AccountMeta,
+	signers []*solana.AccountMeta,
+	amount uint64,
+	decimals *uint8,
+) error {
+	dst, err := e.loadTokenAccount(dstMeta)
+	if err != nil {
+		return err
+	}
+	if dst.State == token.Frozen {
+		return errTokenAccountFrozen
+	}
+	if dst.IsNative != nil {
+		return errTokenNativeNotMintable
+	}
+	if !mintMeta.PublicKey.Equals(dst.Mint) {
+		return errTokenMintMismatch
+	}
+	mint, err := e.loadMint(mintMeta)
+	if err != nil {
+		return err
+	}
+	if err := checkDecimals(mint, decimals); err != nil {
+		return err
+	}
+	if mint.MintAuthority == nil {
+		return errTokenFixedSupply
+	}
+	if err := e.validateOwner(*mint.MintAuthority, authority, signers); err != nil {
+		return err
+	}
+	if dst.Amount+amount < dst.Amount || mint.Supply+amount < mint.Supply {
+		return errTokenOverflow
+	}
+	dst.Amount += amount
+	mint.Supply += amount
+	if err := e.store(dstMeta, dst); err != nil {
+		return err
+	}
+	return e.store(mintMeta, mint)
+}
+
+func (e *executor) tokenBurn(
+	srcMeta, mintMeta, authority *solana.AccountMeta,
+	signers []*solana.AccountMeta,
+	amount uint64,
+	decimals *uint8,
+) error {
+	src, err := e.loadTokenAccount(srcMeta)
+	if err != nil {
+		return err
+	}
+	if src.State == token.Frozen {
+		return errTokenAccountFrozen
+	}
+	if src.IsNative != nil {
+		return errTokenNonNativeNotSupported
+	}
+	if src.Amount < amount {
+		return errTokenInsufficientFunds
+	}
+	if !mintMeta.PublicKey.Equals(src.Mint) {
+		return errTokenMintMismatch
+	}
+	mint, err := e.loadMint(mintMeta)
+	if err != nil {
+		return err
+	}
+	if err := checkDecimals(mint, decimals); err != nil {
+		return err
+	}
+	if err := e.spendAuthority(src, authority, signers, amount); err != nil {
+		return err
+	}
+	src.Amount -= amount
+	mint.Supply -= amount
+	if err := e.store(srcMeta, src); err != nil {
+		return err
+	}
+	return e.store(mintMeta, mint)
+}
+
+func (e *executor) tokenCloseAccount(srcMeta, dstMeta, authority *solana.AccountMeta, signers []*solana.AccountMeta) error {
+	if srcMeta.PublicKey.Equals(dstMeta.PublicKey) {
+		return errInvalidAccountData
+	}
+	src, err := e.loadTokenAccount(srcMeta)
+	if err != nil {
+		return err
+	}
+	if src.IsNative == nil && src.Amount != 0 {
+		return errTokenNonNativeHasBalance
+	}
+	current := src.Owner
+	if src.CloseAuthority != nil {
+		current = *src.CloseAuthority
+	}
+	if err := e.validateOwner(current, authority, signers); err != nil {
+		return err
+	}
+	acc := e.account(srcMeta.PublicKey)
+	e.account(dstMeta.PublicKey).Lamports += acc.Lamports
+	acc.Lamports = 0
+	for i := range acc.Data {
+		acc.Data[i] = 0
+	}
+	return nil
+}
+
+func (e *executor) tokenToggleFreeze(
+	srcMeta, mintMeta, authority *solana.AccountMeta,
+	signers []*solana.AccountMeta,
+	freeze bool,
+) error {
+	src, err := e.loadTokenAccount(srcMeta)
+	if err != nil {
+		return err
+	}
+	if src.IsNative != nil {
+		return errTokenNonNativeNotSupported
+	}
+	if !mintMeta.PublicKey.Equals(src.Mint) {
+		return errTokenMintMismatch
+	}
+	if freeze && src.State != token.Initialized || !freeze && src.State != token.Frozen {
+		return errTokenInvalidState
+	}
+	mint, err := e.loadMint(mintMeta)
+	if err != nil {
+		return err
+	}
+	if mint.FreezeAuthority == nil {
+		return errTokenMintCannotFreeze
+	}
+	if err := e.validateOwner(*mint.FreezeAuthority, authority, signers); err != nil {
+		return err
+	}
+	if freeze {
+		src.State = token.Frozen
+	} else {
+		src.State = token.Initialized
+	}
+	return e.store(srcMeta, src)
+}
+
+func (e *executor) tokenSyncNative(meta *solana.AccountMeta) error {
+	account, err := e.loadTokenAccount(meta)
+	if err != nil {
+		return err
+	}
+	if account.IsNative == nil {
+		return errTokenNonNativeNotSupported
+	}
+	lamports := e.account(meta.PublicKey).Lamports
+	if lamports < *account.IsNative {
+		return errTokenInvalidState
+	}
+	amount := lamports - *account.IsNative
+	if amount < account.Amount {
+		return errTokenInvalidState
+	}
+	account.Amount = amount
+	return e.store(meta, account)
+}
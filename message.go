@@ -658,9 +658,10 @@ func (mx *Message) UnmarshalLegacy(decoder *bin.Decoder) (err error) {
 func (m Message) checkPreconditions() error {
 	// if this is versioned,
 	// and there are > 0 lookups,
-	// but the address table is empty,
+	// but the lookups haven't been resolved,
+	// and the address table is empty,
 	// then we can't build the account meta list:
-	if m.IsVersioned() && m.AddressTableLookups.NumLookups() > 0 && (m.addressTables == nil || len(m.addressTables) == 0) {
+	if m.IsVersioned() && m.AddressTableLookups.NumLookups() > 0 && !m.resolved && (m.addressTables == nil || len(m.addressTables) == 0) {
 		return fmt.Errorf("cannot build account meta list without address tables")
 	}
 
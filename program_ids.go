@@ -36,6 +36,9 @@ var (
 	// Verify secp256k1 public key recovery operations (ecrecover).
 	Secp256k1ProgramID = MustPublicKeyFromBase58("KeccakSecp256k11111111111111111111111111111")
 
+	// Verify ed25519 signatures.
+	Ed25519ProgramID = MustPublicKeyFromBase58("Ed25519SigVerify111111111111111111111111111")
+
 	FeatureProgramID = MustPublicKeyFromBase58("Feature111111111111111111111111111111111111")
 
 	ComputeBudget = MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
@@ -60,6 +63,10 @@ var (
 	// A lending protocol for the Token program on the Solana blockchain inspired by Aave and Compound.
 	TokenLendingProgramID = MustPublicKeyFromBase58("LendZqTs8gn5CTSJU1jWKhKuVpjJGom45nnwPb2AMTi")
 
+	// Pools the stake of its depositors, delegated to a set of validators,
+	// for pool tokens (liquid staking).
+	SPLStakePoolProgramID = MustPublicKeyFromBase58("SPoo1Ku8WFXoNDMHPsrGSTSG1Y47rzgn41SLUNakuHy")
+
 	// This program defines the convention and provides the mechanism for mapping
 	// the user's wallet address to the associated token accounts they hold.
 	SPLAssociatedTokenAccountProgramID = MustPublicKeyFromBase58("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
@@ -80,3 +87,15 @@ var (
 )
 
 var TokenMetadataProgramID = MustPublicKeyFromBase58("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+
+var (
+	// Creates and maintains the concurrent merkle trees of compressed accounts.
+	SPLAccountCompressionProgramID = MustPublicKeyFromBase58("cmtDvXumGCrqC1Age74AVPhSRVXJMd8PJS91L8KbNCK")
+
+	// Logs its instruction data; used by the compression programs to index
+	// the changes of their trees through the instruction data of the transactions.
+	SPLNoopProgramID = MustPublicKeyFromBase58("noopb9bkMVfRPU8AsbpTUg8AQkHtKwMYZiFUjNRtMmV")
+
+	// Mints and manages compressed NFTs, stored as the leaves of concurrent merkle trees.
+	BubblegumProgramID = MustPublicKeyFromBase58("BGUMAp9Gq7iTEuizy4pqaxsTyUCBK68MDfK752saRPUY")
+)
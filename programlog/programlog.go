@@ -0,0 +1,318 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package programlog parses the log messages of a transaction (the
+// LogMessages of rpc.TransactionMeta, or the Logs of a ws LogResult)
+// into the tree of the program invocations, with their logs, data,
+// compute units, return data and failure.
+//
+//	tree, err := programlog.Parse(meta.LogMessages)
+//	if failed := tree.Failed(); failed != nil {
+//		fmt.Println(failed.ProgramID, failed.FailureReason)
+//	}
+package programlog
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/treeout"
+)
+
+// Tree is the tree of the program invocations of a transaction.
+type Tree struct {
+	// The top-level invocations, one per executed instruction.
+	Invocations []*Invocation
+	// Whether the logs were truncated by the runtime ("Log truncated"):
+	// the invocations still running at that point are incomplete.
+	Truncated bool
+}
+
+// Invocation is the invocation of a program by an instruction, or by
+// another program (cross-program invocation).
+type Invocation struct {
+	ProgramID solana.PublicKey
+	// 1 for the top-level invocations, 2 for the programs they invoke, ...
+	Depth  int
+	Parent *Invocation `json:"-"`
+	// The messages of the "Program log:" lines, and the lines in no known
+	// format (e.g. the messages of the builtin programs), in order.
+	Logs []string
+	// The fields of the "Program data:" lines (sol_log_data).
+	Data [][][]byte
+	// The invocations made by the program, in order.
+	Invocations []*Invocation
+
+	// Compute units consumed by the invocation, including the ones
+	// consumed by its invocations, and compute units that were available
+	// to it; both are zero if not logged (e.g. by the builtin programs).
+	ComputeUnitsConsumed  uint64
+	ComputeUnitsAvailable uint64
+
+	// Set by "Program return:".
+	ReturnData []byte
+
+	Success bool
+	// Set by "Program <id> failed: <reason>".
+	FailureReason string
+	// Whether the logs of the invocation were truncated.
+	Truncated bool
+}
+
+// Completed tells whether the invocation succeeded or failed, i.e. whether
+// its logs are complete.
+func (inv *Invocation) Completed() bool {
+	return inv.Success || inv.FailureReason != ""
+}
+
+// SelfComputeUnits returns the compute units consumed by the program
+// itself, i.e. without the ones consumed by its invocations.
+func (inv *Invocation) SelfComputeUnits() uint64 {
+	consumed := inv.ComputeUnitsConsumed
+	for _, child := range inv.Invocations {
+		if child.ComputeUnitsConsumed > consumed {
+			return 0
+		}
+		consumed -= child.ComputeUnitsConsumed
+	}
+	return consumed
+}
+
+// Walk calls fn for the invocations in execution order (depth-first),
+// until fn returns false.
+func (tree *Tree) Walk(fn func(inv *Invocation) bool) {
+	var walk func(invocations []*Invocation) bool
+	walk = func(invocations []*Invocation) bool {
+		for _, inv := range invocations {
+			if !fn(inv) || !walk(inv.Invocations) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(tree.Invocations)
+}
+
+// Failed returns the invocation at the origin of the failure of the
+// transaction, i.e. the deepest failed invocation, or nil.
+func (tree *Tree) Failed() *Invocation {
+	var failed *Invocation
+	tree.Walk(func(inv *Invocation) bool {
+		if inv.FailureReason != "" && (failed == nil || inv.Depth > failed.Depth) {
+			failed = inv
+		}
+		return true
+	})
+	return failed
+}
+
+// ComputeUnitsByProgram returns the compute units consumed by each
+// program itself (see Invocation.SelfComputeUnits), over all its invocations.
+func (tree *Tree) ComputeUnitsByProgram() map[solana.PublicKey]uint64 {
+	out := make(map[solana.PublicKey]uint64)
+	tree.Walk(func(inv *Invocation) bool {
+		out[inv.ProgramID] += inv.SelfComputeUnits()
+		return true
+	})
+	return out
+}
+
+const (
+	prefixProgram = "Program "
+	prefixLog     = "Program log: "
+	prefixData    = "Program data: "
+	prefixReturn  = "Program return: "
+	logTruncated  = "Log truncated"
+)
+
+// Parse parses the log messages of a transaction. It fails on messages
+// inconsistent with the invocation stack, which the runtime doesn't produce.
+func Parse(logs []string) (*Tree, error) {
+	tree := new(Tree)
+	var stack []*Invocation
+	current := func() *Invocation {
+		if len(stack) == 0 {
+			return nil
+		}
+		return stack[len(stack)-1]
+	}
+
+	for i, line := range logs {
+		if line == logTruncated {
+			tree.Truncated = true
+			for _, inv := range stack {
+				inv.Truncated = true
+			}
+			break
+		}
+		inv := current()
+
+		switch {
+		case strings.HasPrefix(line, prefixLog):
+			if inv == nil {
+				return nil, fmt.Errorf("line %d: log outside of any invocation: %q", i, line)
+			}
+			inv.Logs = append(inv.Logs, strings.TrimPrefix(line, prefixLog))
+			continue
+		case strings.HasPrefix(line, prefixData):
+			if inv == nil {
+				return nil, fmt.Errorf("line %d: data outside of any invocation: %q", i, line)
+			}
+			var fields [][]byte
+			for _, field := range strings.Fields(strings.TrimPrefix(line, prefixData)) {
+				data, err := base64.StdEncoding.DecodeString(field)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid data: %w", i, err)
+				}
+				fields = append(fields, data)
+			}
+			inv.Data = append(inv.Data, fields)
+			continue
+		case strings.HasPrefix(line, prefixReturn):
+			parts := strings.Fields(strings.TrimPrefix(line, prefixReturn))
+			if len(parts) == 0 || len(parts) > 2 {
+				return nil, fmt.Errorf("line %d: invalid return data: %q", i, line)
+			}
+			programID, err := solana.PublicKeyFromBase58(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid return data program: %w", i, err)
+			}
+			if inv == nil || !inv.ProgramID.Equals(programID) {
+				return nil, fmt.Errorf("line %d: return data of %s outside of its invocation", i, programID)
+			}
+			inv.ReturnData = []byte{}
+			if len(parts) == 2 {
+				if inv.ReturnData, err = base64.StdEncoding.DecodeString(parts[1]); err != nil {
+					return nil, fmt.Errorf("line %d: invalid return data: %w", i, err)
+				}
+			}
+			continue
+		}
+
+		programID, rest, ok := parseProgramLine(line)
+		if !ok {
+			// A message in no known format, e.g. logged by a builtin program:
+			if inv == nil {
+				return nil, fmt.Errorf("line %d: log outside of any invocation: %q", i, line)
+			}
+			inv.Logs = append(inv.Logs, line)
+			continue
+		}
+
+		if strings.HasPrefix(rest, "invoke [") && strings.HasSuffix(rest, "]") {
+			depth, err := strconv.Atoi(rest[len("invoke [") : len(rest)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid invocation depth: %q", i, line)
+			}
+			if depth != len(stack)+1 {
+				return nil, fmt.Errorf("line %d: invocation at depth %d, expected %d", i, depth, len(stack)+1)
+			}
+			child := &Invocation{
+				ProgramID: programID,
+				Depth:     depth,
+				Parent:    inv,
+			}
+			if inv == nil {
+				tree.Invocations = append(tree.Invocations, child)
+			} else {
+				inv.Invocations = append(inv.Invocations, child)
+			}
+			stack = append(stack, child)
+			continue
+		}
+
+		if inv == nil || !inv.ProgramID.Equals(programID) {
+			return nil, fmt.Errorf("line %d: %q outside of the invocation of %s", i, line, programID)
+		}
+		switch {
+		case rest == "success":
+			inv.Success = true
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(rest, "failed: "):
+			inv.FailureReason = strings.TrimPrefix(rest, "failed: ")
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(rest, "consumed "):
+			var consumed, available uint64
+			if _, err := fmt.Sscanf(rest, "consumed %d of %d compute units", &consumed, &available); err != nil {
+				return nil, fmt.Errorf("line %d: invalid compute units: %q", i, line)
+			}
+			inv.ComputeUnitsConsumed = consumed
+			inv.ComputeUnitsAvailable = available
+		default:
+			inv.Logs = append(inv.Logs, line)
+		}
+	}
+	return tree, nil
+}
+
+// parseProgramLine parses a "Program <id> <rest>" line.
+func parseProgramLine(line string) (solana.PublicKey, string, bool) {
+	if !strings.HasPrefix(line, prefixProgram) {
+		return solana.PublicKey{}, "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(line, prefixProgram), " ", 2)
+	if len(parts) != 2 {
+		return solana.PublicKey{}, "", false
+	}
+	programID, err := solana.PublicKeyFromBase58(parts[0])
+	if err != nil {
+		return solana.PublicKey{}, "", false
+	}
+	return programID, parts[1], true
+}
+
+// EncodeToTree renders the invocations, for CU profiling and debugging.
+func (tree *Tree) EncodeToTree(parent treeout.Branches) {
+	for _, inv := range tree.Invocations {
+		inv.EncodeToTree(parent)
+	}
+	if tree.Truncated {
+		parent.Child("(log truncated)")
+	}
+}
+
+func (inv *Invocation) EncodeToTree(parent treeout.Branches) {
+	status := "incomplete"
+	switch {
+	case inv.Success:
+		status = "success"
+	case inv.FailureReason != "":
+		status = "failed: " + inv.FailureReason
+	}
+	label := fmt.Sprintf("%s [%s]", inv.ProgramID, status)
+	if inv.ComputeUnitsAvailable > 0 {
+		label += fmt.Sprintf(" %d CU (self %d) of %d", inv.ComputeUnitsConsumed, inv.SelfComputeUnits(), inv.ComputeUnitsAvailable)
+	}
+	parent.Child(label).ParentFunc(func(branch treeout.Branches) {
+		for _, log := range inv.Logs {
+			branch.Child("log: " + log)
+		}
+		for _, fields := range inv.Data {
+			encoded := make([]string, len(fields))
+			for i := range fields {
+				encoded[i] = base64.StdEncoding.EncodeToString(fields[i])
+			}
+			branch.Child("data: " + strings.Join(encoded, " "))
+		}
+		if inv.ReturnData != nil {
+			branch.Child("return: " + base64.StdEncoding.EncodeToString(inv.ReturnData))
+		}
+		for _, child := range inv.Invocations {
+			child.EncodeToTree(branch)
+		}
+	})
+}
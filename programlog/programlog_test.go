@@ -0,0 +1,184 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package programlog
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/treeout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	computeBudget = solana.ComputeBudget
+	swapProgram   = solana.MustPublicKeyFromBase58("JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4")
+	tokenProgram  = solana.TokenProgramID
+)
+
+func TestParse(t *testing.T) {
+	logs := []string{
+		"Program ComputeBudget111111111111111111111111111111 invoke [1]",
+		"Program ComputeBudget111111111111111111111111111111 success",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [1]",
+		"Program log: Instruction: Route",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program log: Instruction: Transfer",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 4645 of 180000 compute units",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+		"Program data: AQID BAU=",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program log: Instruction: Transfer",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 4736 of 170000 compute units",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+		"Program return: JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 6AMAAAAAAAA=",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 consumed 25000 of 199850 compute units",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 success",
+	}
+	tree, err := Parse(logs)
+	require.NoError(t, err)
+	require.False(t, tree.Truncated)
+	require.Len(t, tree.Invocations, 2)
+
+	budget := tree.Invocations[0]
+	assert.Equal(t, computeBudget, budget.ProgramID)
+	assert.Equal(t, 1, budget.Depth)
+	assert.True(t, budget.Success)
+	assert.Zero(t, budget.ComputeUnitsConsumed)
+
+	swap := tree.Invocations[1]
+	assert.Equal(t, swapProgram, swap.ProgramID)
+	assert.Nil(t, swap.Parent)
+	assert.True(t, swap.Success)
+	assert.True(t, swap.Completed())
+	assert.Equal(t, []string{"Instruction: Route"}, swap.Logs)
+	assert.Equal(t, [][][]byte{{{1, 2, 3}, {4, 5}}}, swap.Data)
+	assert.Equal(t, []byte{0xe8, 3, 0, 0, 0, 0, 0, 0}, swap.ReturnData)
+	assert.Equal(t, uint64(25000), swap.ComputeUnitsConsumed)
+	assert.Equal(t, uint64(199850), swap.ComputeUnitsAvailable)
+	assert.Equal(t, uint64(25000-4645-4736), swap.SelfComputeUnits())
+
+	require.Len(t, swap.Invocations, 2)
+	for _, transfer := range swap.Invocations {
+		assert.Equal(t, tokenProgram, transfer.ProgramID)
+		assert.Equal(t, 2, transfer.Depth)
+		assert.Equal(t, swap, transfer.Parent)
+		assert.Equal(t, []string{"Instruction: Transfer"}, transfer.Logs)
+		assert.True(t, transfer.Success)
+	}
+	assert.Equal(t, uint64(4736), swap.Invocations[1].ComputeUnitsConsumed)
+	assert.Nil(t, tree.Failed())
+
+	var order []solana.PublicKey
+	tree.Walk(func(inv *Invocation) bool {
+		order = append(order, inv.ProgramID)
+		return true
+	})
+	assert.Equal(t, []solana.PublicKey{computeBudget, swapProgram, tokenProgram, tokenProgram}, order)
+
+	assert.Equal(t, map[solana.PublicKey]uint64{
+		computeBudget: 0,
+		swapProgram:   25000 - 4645 - 4736,
+		tokenProgram:  4645 + 4736,
+	}, tree.ComputeUnitsByProgram())
+
+	tr := treeout.New("logs")
+	tree.EncodeToTree(tr)
+	assert.Contains(t, tr.String(), "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA [success] 4645 CU (self 4645) of 180000")
+}
+
+func TestParse_Failure(t *testing.T) {
+	tree, err := Parse([]string{
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [1]",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program log: Instruction: Transfer",
+		"Program log: Error: insufficient funds",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 4381 of 195000 compute units",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA failed: custom program error: 0x1",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 consumed 9000 of 200000 compute units",
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 failed: custom program error: 0x1",
+	})
+	require.NoError(t, err)
+
+	failed := tree.Failed()
+	require.NotNil(t, failed)
+	assert.Equal(t, tokenProgram, failed.ProgramID)
+	assert.Equal(t, "custom program error: 0x1", failed.FailureReason)
+	assert.Equal(t, []string{"Instruction: Transfer", "Error: insufficient funds"}, failed.Logs)
+	assert.False(t, tree.Invocations[0].Success)
+	assert.True(t, tree.Invocations[0].Completed())
+}
+
+func TestParse_BuiltinMessages(t *testing.T) {
+	tree, err := Parse([]string{
+		"Program 11111111111111111111111111111111 invoke [1]",
+		"Transfer: insufficient lamports 100, need 200",
+		"Program 11111111111111111111111111111111 failed: custom program error: 0x1",
+	})
+	require.NoError(t, err)
+	require.Len(t, tree.Invocations, 1)
+	assert.Equal(t, []string{"Transfer: insufficient lamports 100, need 200"}, tree.Invocations[0].Logs)
+}
+
+func TestParse_Truncated(t *testing.T) {
+	tree, err := Parse([]string{
+		"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [1]",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program log: Instruction: Transfer",
+		"Log truncated",
+	})
+	require.NoError(t, err)
+	require.True(t, tree.Truncated)
+
+	swap := tree.Invocations[0]
+	assert.True(t, swap.Truncated)
+	assert.False(t, swap.Completed())
+	require.Len(t, swap.Invocations, 2)
+	assert.False(t, swap.Invocations[0].Truncated)
+	assert.True(t, swap.Invocations[1].Truncated)
+	assert.Nil(t, tree.Failed())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, logs := range map[string][]string{
+		"log outside of invocation": {
+			"Program log: hello",
+		},
+		"unexpected depth": {
+			"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [2]",
+		},
+		"success of another program": {
+			"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [1]",
+			"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+		},
+		"invalid data": {
+			"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [1]",
+			"Program data: !!!",
+		},
+		"invalid compute units": {
+			"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 invoke [1]",
+			"Program JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4 consumed many compute units",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(logs)
+			require.Error(t, err)
+		})
+	}
+}
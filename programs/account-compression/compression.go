@@ -0,0 +1,83 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package accountcompression decodes the concurrent merkle tree accounts of
+// the SPL Account Compression program, and verifies merkle proofs against
+// them locally.
+package accountcompression
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var ProgramID solana.PublicKey = solana.SPLAccountCompressionProgramID
+
+func SetProgramID(pubkey solana.PublicKey) {
+	ProgramID = pubkey
+	registerAccountDecoders(ProgramID)
+}
+
+const ProgramName = "AccountCompression"
+
+func init() {
+	registerAccountDecoders(ProgramID)
+}
+
+// CompressionAccountType is the first byte of the accounts of the program.
+type CompressionAccountType uint8
+
+const (
+	CompressionAccountTypeUninitialized CompressionAccountType = iota
+	CompressionAccountTypeConcurrentMerkleTree
+)
+
+func (typ CompressionAccountType) String() string {
+	switch typ {
+	case CompressionAccountTypeUninitialized:
+		return "Uninitialized"
+	case CompressionAccountTypeConcurrentMerkleTree:
+		return "ConcurrentMerkleTree"
+	default:
+		return fmt.Sprintf("CompressionAccountType(%d)", uint8(typ))
+	}
+}
+
+func registerAccountDecoders(programID solana.PublicKey) {
+	solana.RegisterAccountDecoder(
+		programID,
+		solana.AccountDiscriminatorMatcher([]byte{byte(CompressionAccountTypeConcurrentMerkleTree)}),
+		registryDecodeAccount,
+	)
+}
+
+func registryDecodeAccount(data []byte) (interface{}, error) {
+	tree, err := DecodeMerkleTreeAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// DecodeMerkleTreeAccount decodes the data of a concurrent merkle tree
+// account: its header, its tree and its canopy.
+func DecodeMerkleTreeAccount(data []byte) (*MerkleTreeAccount, error) {
+	account := new(MerkleTreeAccount)
+	if err := bin.NewBinDecoder(data).Decode(account); err != nil {
+		return nil, fmt.Errorf("unable to decode merkle tree account: %w", err)
+	}
+	return account, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountcompression
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/rpctest"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTree returns the levels of the tree of the leaves, from the leaves
+// up to the root.
+func buildTree(leaves []solana.Hash) [][]solana.Hash {
+	levels := [][]solana.Hash{leaves}
+	for len(levels[len(levels)-1]) > 1 {
+		below := levels[len(levels)-1]
+		level := make([]solana.Hash, len(below)/2)
+		for i := range level {
+			level[i] = HashNodes(below[2*i], below[2*i+1])
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+func proofOf(levels [][]solana.Hash, index uint32) []solana.PublicKey {
+	var proof []solana.PublicKey
+	for _, level := range levels[:len(levels)-1] {
+		proof = append(proof, solana.PublicKey(level[index^1]))
+		index >>= 1
+	}
+	return proof
+}
+
+// encodeTreeAccount encodes a tree account of the levels, with its root as
+// the only change log, and the top levels of the tree as its canopy.
+func encodeTreeAccount(t *testing.T, levels [][]solana.Hash, maxBufferSize uint32, canopyDepth int) []byte {
+	maxDepth := len(levels) - 1
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewBinEncoder(buf).Encode(ConcurrentMerkleTreeHeader{
+		AccountType:   CompressionAccountTypeConcurrentMerkleTree,
+		MaxBufferSize: maxBufferSize,
+		MaxDepth:      uint32(maxDepth),
+		Authority:     solana.SysVarClockPubkey,
+		CreationSlot:  42,
+	}))
+	require.Equal(t, CONCURRENT_MERKLE_TREE_HEADER_SIZE_V1, buf.Len())
+
+	var data []byte
+	data = binary.LittleEndian.AppendUint64(data, 1) // sequence number
+	data = binary.LittleEndian.AppendUint64(data, 0) // active index
+	data = binary.LittleEndian.AppendUint64(data, 1) // buffer size
+	for i := 0; i < int(maxBufferSize); i++ {
+		var root solana.Hash
+		if i == 0 {
+			root = levels[maxDepth][0]
+		}
+		data = append(data, root[:]...)
+		data = append(data, make([]byte, 32*maxDepth+8)...)
+	}
+	leaves := len(levels[0])
+	for _, node := range proofOf(levels, uint32(leaves-1)) {
+		data = append(data, node[:]...)
+	}
+	data = append(data, levels[0][leaves-1][:]...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(leaves))
+	data = append(data, 0, 0, 0, 0)
+	for depth := 1; depth <= canopyDepth; depth++ {
+		for _, node := range levels[maxDepth-depth] {
+			data = append(data, node[:]...)
+		}
+	}
+	return append(buf.Bytes(), data...)
+}
+
+func testLeaves(count int) []solana.Hash {
+	leaves := make([]solana.Hash, count)
+	for i := range leaves {
+		leaves[i][0] = byte(i + 1)
+	}
+	return leaves
+}
+
+func TestDecodeMerkleTreeAccount(t *testing.T) {
+	levels := buildTree(testLeaves(8))
+	data := encodeTreeAccount(t, levels, 2, 2)
+	require.Equal(t, MerkleTreeAccountSize(3, 2, 2), len(data))
+
+	decoded, err := solana.DecodeAccount(ProgramID, data)
+	require.NoError(t, err)
+	tree := decoded.(*MerkleTreeAccount)
+	require.Equal(t, uint32(3), tree.Header.MaxDepth)
+	require.Equal(t, uint32(2), tree.Header.MaxBufferSize)
+	require.Equal(t, solana.SysVarClockPubkey, tree.Header.Authority)
+	require.Equal(t, uint64(42), tree.Header.CreationSlot)
+	require.Equal(t, levels[3][0], tree.Root())
+	require.Equal(t, uint32(8), tree.NumLeaves())
+	require.Len(t, tree.Canopy, 6)
+	require.Equal(t, uint32(2), tree.CanopyDepth())
+
+	_, err = DecodeMerkleTreeAccount(data[:len(data)-32])
+	require.Error(t, err)
+}
+
+func TestVerifyProof(t *testing.T) {
+	levels := buildTree(testLeaves(8))
+	root := levels[3][0]
+	for index := uint32(0); index < 8; index++ {
+		proof := proofOf(levels, index)
+		require.NoError(t, VerifyProof(root, levels[0][index], proof, index))
+		require.ErrorIs(t, VerifyProof(root, levels[0][index], proof, index^1), ErrInvalidProof)
+	}
+	require.Error(t, VerifyProof(root, levels[0][0], proofOf(levels, 0), 8))
+
+	require.Equal(t, HashNodes(solana.Hash{}, solana.Hash{}), EmptyNode(1))
+	require.Equal(t, HashNodes(EmptyNode(1), EmptyNode(1)), EmptyNode(2))
+}
+
+func TestTruncateAndFillProof(t *testing.T) {
+	leaves := testLeaves(8)
+	// The right half of the tree is empty.
+	for i := 4; i < 8; i++ {
+		leaves[i] = solana.Hash{}
+	}
+	levels := buildTree(leaves)
+	data := encodeTreeAccount(t, levels, 1, 2)
+	// The canopy keeps zero for the nodes of the empty subtrees.
+	copy(data[len(data)-32*2:], make([]byte, 32*2))
+	copy(data[len(data)-32*5:], make([]byte, 32))
+
+	tree, err := DecodeMerkleTreeAccount(data)
+	require.NoError(t, err)
+
+	proof := proofOf(levels, 2)
+	truncated := tree.TruncateProof(proof)
+	require.Equal(t, proof[:1], truncated)
+	require.Empty(t, TruncateProof(proof, 3))
+
+	filled, err := tree.FillProofFromCanopy(truncated, 2)
+	require.NoError(t, err)
+	require.Equal(t, proof, filled)
+	require.NoError(t, tree.VerifyLeaf(leaves[2], truncated, 2))
+	require.ErrorIs(t, tree.VerifyLeaf(leaves[3], truncated, 2), ErrInvalidProof)
+
+	_, err = tree.FillProofFromCanopy(truncated, 8)
+	require.Error(t, err)
+
+	// The proof doesn't reach the canopy.
+	_, err = tree.FillProofFromCanopy(nil, 2)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "too short")
+	err = tree.VerifyLeaf(leaves[2], nil, 2)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrInvalidProof)
+}
+
+func TestFetchMerkleTree(t *testing.T) {
+	levels := buildTree(testLeaves(4))
+	address := solana.NewWallet().PublicKey()
+
+	srv := rpctest.NewServer(nil)
+	defer srv.Close()
+	srv.SetAccount(address, &rpctest.Account{
+		Lamports: 1,
+		Owner:    ProgramID,
+		Data:     encodeTreeAccount(t, levels, 1, 0),
+	})
+
+	tree, err := FetchMerkleTree(context.Background(), rpc.New(srv.URL()), address)
+	require.NoError(t, err)
+	require.Equal(t, levels[2][0], tree.Root())
+	require.Zero(t, tree.CanopyDepth())
+}
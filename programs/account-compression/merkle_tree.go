@@ -0,0 +1,304 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountcompression
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// CONCURRENT_MERKLE_TREE_HEADER_SIZE_V1 is the size of the header of a
+// concurrent merkle tree account, in front of its tree.
+const CONCURRENT_MERKLE_TREE_HEADER_SIZE_V1 = 56
+
+// The versions of the header of a concurrent merkle tree account.
+const (
+	HeaderVersionV1 uint8 = 0
+)
+
+// ConcurrentMerkleTreeHeader is the header of a concurrent merkle tree
+// account, which holds the parameters of its tree.
+type ConcurrentMerkleTreeHeader struct {
+	AccountType CompressionAccountType
+	Version     uint8
+
+	// The number of changes that can happen to the tree concurrently, i.e.
+	// within the same slot, and still be applied.
+	MaxBufferSize uint32
+
+	// The depth of the tree; it holds at most 2^MaxDepth leaves.
+	MaxDepth uint32
+
+	// The authority that can modify the tree.
+	Authority solana.PublicKey
+
+	// The slot at which the tree was initialized.
+	CreationSlot uint64
+
+	// Whether the tree was initialized with a batch of leaves.
+	IsBatchInitialized bool
+}
+
+func (header ConcurrentMerkleTreeHeader) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint8(uint8(header.AccountType)); err != nil {
+		return err
+	}
+	if err := encoder.WriteUint8(header.Version); err != nil {
+		return err
+	}
+	if err := encoder.WriteUint32(header.MaxBufferSize, bin.LE); err != nil {
+		return err
+	}
+	if err := encoder.WriteUint32(header.MaxDepth, bin.LE); err != nil {
+		return err
+	}
+	if err := encoder.WriteBytes(header.Authority[:], false); err != nil {
+		return err
+	}
+	if err := encoder.WriteUint64(header.CreationSlot, bin.LE); err != nil {
+		return err
+	}
+	if err := encoder.WriteBool(header.IsBatchInitialized); err != nil {
+		return err
+	}
+	return encoder.WriteBytes(make([]byte, 5), false)
+}
+
+func (header *ConcurrentMerkleTreeHeader) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	accountType, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	header.AccountType = CompressionAccountType(accountType)
+	if header.AccountType != CompressionAccountTypeConcurrentMerkleTree {
+		return fmt.Errorf("not a concurrent merkle tree account: %s", header.AccountType)
+	}
+	header.Version, err = decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	if header.Version != HeaderVersionV1 {
+		return fmt.Errorf("unsupported header version: %d", header.Version)
+	}
+	header.MaxBufferSize, err = decoder.ReadUint32(bin.LE)
+	if err != nil {
+		return err
+	}
+	header.MaxDepth, err = decoder.ReadUint32(bin.LE)
+	if err != nil {
+		return err
+	}
+	if err = decoder.Decode(&header.Authority); err != nil {
+		return err
+	}
+	header.CreationSlot, err = decoder.ReadUint64(bin.LE)
+	if err != nil {
+		return err
+	}
+	header.IsBatchInitialized, err = decoder.ReadBool()
+	if err != nil {
+		return err
+	}
+	return decoder.SkipBytes(5)
+}
+
+// ChangeLog is a change to the tree: the root after the change, and the
+// path of the changed leaf, from the leaf up.
+type ChangeLog struct {
+	Root  solana.Hash
+	Path  []solana.Hash
+	Index uint32
+}
+
+// Path is the proof of a leaf of the tree.
+type Path struct {
+	Proof []solana.Hash
+	Leaf  solana.Hash
+	Index uint32
+}
+
+// ConcurrentMerkleTree is the tree of a concurrent merkle tree account. It
+// keeps the last MaxBufferSize changes, so that the proofs made against
+// one of their roots can still be applied.
+type ConcurrentMerkleTree struct {
+	// The number of changes applied to the tree.
+	SequenceNumber uint64
+
+	// The index of the latest change in ChangeLogs.
+	ActiveIndex uint64
+
+	// The number of changes in ChangeLogs.
+	BufferSize uint64
+
+	// The ring buffer of the latest changes.
+	ChangeLogs []ChangeLog
+
+	// The proof of the rightmost leaf; its index is the number of leaves
+	// appended to the tree.
+	RightmostProof Path
+}
+
+// MerkleTreeAccountSize returns the size of a concurrent merkle tree account
+// of the provided depth, buffer size and canopy depth.
+func MerkleTreeAccountSize(maxDepth, maxBufferSize, canopyDepth uint32) int {
+	return CONCURRENT_MERKLE_TREE_HEADER_SIZE_V1 +
+		merkleTreeSize(int(maxDepth), int(maxBufferSize)) +
+		canopySize(int(canopyDepth))
+}
+
+func merkleTreeSize(maxDepth, maxBufferSize int) int {
+	// A change log and a path both hold maxDepth nodes, a node, an u32 index
+	// and an u32 padding.
+	pathSize := 32*maxDepth + 32 + 8
+	return 24 + maxBufferSize*pathSize + pathSize
+}
+
+func canopySize(canopyDepth int) int {
+	return ((1 << (canopyDepth + 1)) - 2) * 32
+}
+
+// MerkleTreeAccount is a decoded concurrent merkle tree account.
+type MerkleTreeAccount struct {
+	Header ConcurrentMerkleTreeHeader
+	Tree   ConcurrentMerkleTree
+
+	// The upper nodes of the tree, cached on chain so that the proofs don't
+	// need to include them; level by level from the top, without the root.
+	Canopy []solana.Hash
+}
+
+func (account *MerkleTreeAccount) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if err = decoder.Decode(&account.Header); err != nil {
+		return err
+	}
+	maxDepth := int(account.Header.MaxDepth)
+	maxBufferSize := int(account.Header.MaxBufferSize)
+	if maxDepth == 0 || maxDepth > 32 || maxBufferSize == 0 {
+		return fmt.Errorf("invalid tree parameters: max depth %d, max buffer size %d", maxDepth, maxBufferSize)
+	}
+	if decoder.Remaining() < merkleTreeSize(maxDepth, maxBufferSize) {
+		return fmt.Errorf("tree data too short: %d bytes", decoder.Remaining())
+	}
+
+	tree := &account.Tree
+	if tree.SequenceNumber, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if tree.ActiveIndex, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if tree.BufferSize, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if tree.ActiveIndex >= uint64(maxBufferSize) {
+		return fmt.Errorf("invalid active index: %d", tree.ActiveIndex)
+	}
+	tree.ChangeLogs = make([]ChangeLog, maxBufferSize)
+	for i := range tree.ChangeLogs {
+		changeLog := &tree.ChangeLogs[i]
+		if changeLog.Root, err = readNode(decoder); err != nil {
+			return err
+		}
+		if changeLog.Path, err = readNodes(decoder, maxDepth); err != nil {
+			return err
+		}
+		if changeLog.Index, err = readIndex(decoder); err != nil {
+			return err
+		}
+	}
+	if tree.RightmostProof.Proof, err = readNodes(decoder, maxDepth); err != nil {
+		return err
+	}
+	if tree.RightmostProof.Leaf, err = readNode(decoder); err != nil {
+		return err
+	}
+	if tree.RightmostProof.Index, err = readIndex(decoder); err != nil {
+		return err
+	}
+
+	if decoder.Remaining()%32 != 0 {
+		return fmt.Errorf("invalid canopy size: %d bytes", decoder.Remaining())
+	}
+	if account.Canopy, err = readNodes(decoder, decoder.Remaining()/32); err != nil {
+		return err
+	}
+	if _, err = canopyDepth(len(account.Canopy), maxDepth); err != nil {
+		return err
+	}
+	return nil
+}
+
+func readNode(decoder *bin.Decoder) (node solana.Hash, err error) {
+	err = decoder.Decode(&node)
+	return
+}
+
+func readNodes(decoder *bin.Decoder, count int) ([]solana.Hash, error) {
+	nodes := make([]solana.Hash, count)
+	for i := range nodes {
+		node, err := readNode(decoder)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+// readIndex reads an u32 index, followed by its u32 padding.
+func readIndex(decoder *bin.Decoder) (uint32, error) {
+	index, err := decoder.ReadUint32(bin.LE)
+	if err != nil {
+		return 0, err
+	}
+	return index, decoder.SkipBytes(4)
+}
+
+var errInvalidCanopy = errors.New("invalid canopy size")
+
+// canopyDepth returns the depth of a canopy of the provided number of
+// nodes, which must be 2^(depth+1)-2.
+func canopyDepth(nodes int, maxDepth int) (int, error) {
+	total := nodes + 2
+	if total&(total-1) != 0 {
+		return 0, errInvalidCanopy
+	}
+	depth := bits.TrailingZeros(uint(total)) - 1
+	if depth > maxDepth {
+		return 0, errInvalidCanopy
+	}
+	return depth, nil
+}
+
+// Root returns the current root of the tree.
+func (account *MerkleTreeAccount) Root() solana.Hash {
+	return account.Tree.ChangeLogs[account.Tree.ActiveIndex].Root
+}
+
+// NumLeaves returns the number of leaves appended to the tree.
+func (account *MerkleTreeAccount) NumLeaves() uint32 {
+	return account.Tree.RightmostProof.Index
+}
+
+// CanopyDepth returns the number of levels of the tree, under its root,
+// cached in the canopy.
+func (account *MerkleTreeAccount) CanopyDepth() uint32 {
+	depth, _ := canopyDepth(len(account.Canopy), int(account.Header.MaxDepth))
+	return uint32(depth)
+}
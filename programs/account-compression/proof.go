@@ -0,0 +1,134 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountcompression
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrInvalidProof is returned when a proof doesn't lead to the expected root.
+var ErrInvalidProof = errors.New("invalid proof")
+
+// HashNodes returns the parent of two sibling nodes of a tree: the
+// Keccak-256 hash of the left node followed by the right node.
+func HashNodes(left, right solana.Hash) solana.Hash {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(left[:])
+	hash.Write(right[:])
+	var parent solana.Hash
+	copy(parent[:], hash.Sum(nil))
+	return parent
+}
+
+// EmptyNode returns the node of an empty subtree of the provided level, the
+// leaves being at level zero.
+func EmptyNode(level uint32) solana.Hash {
+	var node solana.Hash
+	for i := uint32(0); i < level; i++ {
+		node = HashNodes(node, node)
+	}
+	return node
+}
+
+// ComputeRoot returns the root of the tree of the leaf at the provided index,
+// given its proof: the siblings of its path, from the leaf up.
+func ComputeRoot(leaf solana.Hash, proof []solana.PublicKey, index uint32) solana.Hash {
+	node := leaf
+	for level, sibling := range proof {
+		if (index>>level)&1 == 0 {
+			node = HashNodes(node, solana.Hash(sibling))
+		} else {
+			node = HashNodes(solana.Hash(sibling), node)
+		}
+	}
+	return node
+}
+
+// VerifyProof verifies that the proof of the leaf at the provided index
+// leads to the root; the proof must be complete, from the leaf up to the
+// children of the root.
+func VerifyProof(root solana.Hash, leaf solana.Hash, proof []solana.PublicKey, index uint32) error {
+	if len(proof) < 32 && uint64(index) >= 1<<len(proof) {
+		return fmt.Errorf("leaf index %d out of range for a proof of %d nodes", index, len(proof))
+	}
+	if ComputeRoot(leaf, proof, index) != root {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// TruncateProof returns the proof without the nodes cached in a canopy of
+// the provided depth, which the program doesn't need; those are the last
+// ones of the proof.
+func TruncateProof(proof []solana.PublicKey, canopyDepth uint32) []solana.PublicKey {
+	if int(canopyDepth) >= len(proof) {
+		return proof[:0]
+	}
+	return proof[:len(proof)-int(canopyDepth)]
+}
+
+// TruncateProof returns the proof without the nodes cached in the canopy of
+// the tree; these are the proof accounts to pass to the instructions that
+// modify a leaf.
+func (account *MerkleTreeAccount) TruncateProof(proof []solana.PublicKey) []solana.PublicKey {
+	return TruncateProof(proof, account.CanopyDepth())
+}
+
+// FillProofFromCanopy completes a truncated proof of the leaf at the
+// provided index with the nodes cached in the canopy, like the program does;
+// the proof must reach the canopy.
+func (account *MerkleTreeAccount) FillProofFromCanopy(proof []solana.PublicKey, index uint32) ([]solana.PublicKey, error) {
+	maxDepth := int(account.Header.MaxDepth)
+	if len(proof) > maxDepth {
+		return nil, fmt.Errorf("proof of %d nodes is longer than the depth of the tree", len(proof))
+	}
+	if canopyDepth := int(account.CanopyDepth()); len(proof)+canopyDepth < maxDepth {
+		return nil, fmt.Errorf("proof of %d nodes is too short for a tree of depth %d with a canopy of depth %d", len(proof), maxDepth, canopyDepth)
+	}
+	if uint64(index) >= 1<<maxDepth {
+		return nil, fmt.Errorf("leaf index %d out of range", index)
+	}
+
+	// The nodes are numbered from the root, at 1, and the canopy holds
+	// the nodes from 2.
+	var inferred []solana.PublicKey
+	for nodeIndex := (uint64(1)<<maxDepth + uint64(index)) >> len(proof); nodeIndex > 1; nodeIndex >>= 1 {
+		node := account.Canopy[int(nodeIndex^1)-2]
+		if node.IsZero() {
+			node = EmptyNode(uint32(len(proof) + len(inferred)))
+		}
+		inferred = append(inferred, solana.PublicKey(node))
+	}
+
+	filled := make([]solana.PublicKey, 0, maxDepth)
+	filled = append(filled, proof...)
+	return append(filled, inferred...), nil
+}
+
+// VerifyLeaf verifies the proof of the leaf at the provided index against
+// the current root of the tree; the proof can be truncated to the canopy.
+// A proof made against an older root, which the program can still apply,
+// fails the verification.
+func (account *MerkleTreeAccount) VerifyLeaf(leaf solana.Hash, proof []solana.PublicKey, index uint32) error {
+	filled, err := account.FillProofFromCanopy(proof, index)
+	if err != nil {
+		return err
+	}
+	return VerifyProof(account.Root(), leaf, filled, index)
+}
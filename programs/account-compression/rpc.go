@@ -0,0 +1,35 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountcompression
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// FetchMerkleTree fetches the concurrent merkle tree account at the address.
+func FetchMerkleTree(ctx context.Context, rpcCli *rpc.Client, address solana.PublicKey) (*MerkleTreeAccount, error) {
+	resp, err := rpcCli.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get merkle tree account: %w", err)
+	}
+	if !resp.Value.Owner.Equals(ProgramID) {
+		return nil, fmt.Errorf("account %s is not owned by the account compression program", address)
+	}
+	return DecodeMerkleTreeAccount(resp.GetBinary())
+}
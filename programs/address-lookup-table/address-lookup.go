@@ -16,6 +16,23 @@ const (
 	LOOKUP_TABLE_MAX_ADDRESSES = 256
 )
 
+func init() {
+	// Only the initialized lookup tables (type index 1) are decoded.
+	solana.RegisterAccountDecoder(
+		solana.AddressLookupTableProgramID,
+		solana.AccountDiscriminatorMatcher([]byte{1, 0, 0, 0}),
+		registryDecodeAccount,
+	)
+}
+
+func registryDecodeAccount(data []byte) (interface{}, error) {
+	state, err := DecodeAddressLookupTableState(data)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
 // DecodeAddressLookupTableState decodes the given account bytes into a AddressLookupTableState.
 func DecodeAddressLookupTableState(data []byte) (*AddressLookupTableState, error) {
 	decoder := bin.NewBinDecoder(data)
@@ -0,0 +1,52 @@
+package addresslookuptable
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ResolveLookups fetches the address lookup tables used by the message that
+// haven't been set yet (see solana.Message.SetAddressTables), and resolves
+// its lookups.
+//
+// The transactions returned by GetTransaction and GetBlock are resolved with
+// the addresses loaded during their execution; this is the fallback for the
+// ones without a meta, like the transactions received over a websocket or
+// built locally. Note that the tables are read at their current state.
+func ResolveLookups(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	message *solana.Message,
+) error {
+	if message.IsResolved() || message.NumLookups() == 0 {
+		return nil
+	}
+	tables := message.GetAddressTables()
+	fetched := make(map[solana.PublicKey]solana.PublicKeySlice)
+	for _, tableID := range message.GetAddressTableLookups().GetTableIDs() {
+		if _, ok := tables[tableID]; ok {
+			continue
+		}
+		if _, ok := fetched[tableID]; ok {
+			continue
+		}
+		state, err := GetAddressLookupTable(ctx, rpcClient, tableID)
+		if err != nil {
+			return fmt.Errorf("unable to get address lookup table %s: %w", tableID, err)
+		}
+		fetched[tableID] = state.Addresses
+	}
+	if tables == nil {
+		if err := message.SetAddressTables(fetched); err != nil {
+			return err
+		}
+	} else {
+		for tableID, addresses := range fetched {
+			tables[tableID] = addresses
+		}
+	}
+	return message.ResolveLookups()
+}
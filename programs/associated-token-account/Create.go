@@ -73,6 +73,11 @@ func (inst *Create) SetMint(mint solana.PublicKey) *Create {
 	return inst
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Create) AccountNames() []string {
+	return []string{"payer", "associatedTokenAccount", "wallet", "tokenMint", "systemProgram", "tokenProgram", "rentSysvar"}
+}
+
 func (inst Create) Build() *Instruction {
 
 	// Find the associatedTokenAddress;
@@ -62,6 +62,15 @@ func (inst *Instruction) ProgramID() solana.PublicKey {
 	return ProgramID
 }
 
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
 func (inst *Instruction) Accounts() (out []*solana.AccountMeta) {
 	return inst.Impl.(solana.AccountsGettable).GetAccounts()
 }
@@ -0,0 +1,325 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bubblegum
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Mints a compressed NFT, as a new leaf of the tree.
+type MintV1 struct {
+	Metadata *MetadataArgs
+
+	// [0] = [WRITE] treeAuthority
+	// ··········· The tree config, at FindTreeConfigAddress(merkleTree).
+	//
+	// [1] = [] leafOwner
+	// ··········· The owner of the new NFT.
+	//
+	// [2] = [] leafDelegate
+	// ··········· The delegate of the new NFT; usually its owner.
+	//
+	// [3] = [WRITE] merkleTree
+	// ··········· The merkle tree of the leaf.
+	//
+	// [4] = [SIGNER] payer
+	// ··········· The payer.
+	//
+	// [5] = [SIGNER] treeDelegate
+	// ··········· The creator or the delegate of the tree; any signer for public trees.
+	//
+	// [6] = [] logWrapper
+	// ··········· The noop program, which logs the changes of the tree.
+	//
+	// [7] = [] compressionProgram
+	// ··········· The account compression program.
+	//
+	// [8] = [] systemProgram
+	// ··········· The system program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewMintV1InstructionBuilder creates a new `MintV1` instruction builder.
+func NewMintV1InstructionBuilder() *MintV1 {
+	nd := &MintV1{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 9),
+	}
+	nd.AccountMetaSlice[6] = ag_solanago.Meta(ag_solanago.SPLNoopProgramID)
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.SPLAccountCompressionProgramID)
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	return nd
+}
+
+// SetMetadata sets the "metadata" parameter.
+func (inst *MintV1) SetMetadata(metadata MetadataArgs) *MintV1 {
+	inst.Metadata = &metadata
+	return inst
+}
+
+// SetTreeAuthorityAccount sets the "treeAuthority" account.
+// The tree config, at FindTreeConfigAddress(merkleTree).
+func (inst *MintV1) SetTreeAuthorityAccount(treeAuthority ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(treeAuthority).WRITE()
+	return inst
+}
+
+// GetTreeAuthorityAccount gets the "treeAuthority" account.
+// The tree config, at FindTreeConfigAddress(merkleTree).
+func (inst *MintV1) GetTreeAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetLeafOwnerAccount sets the "leafOwner" account.
+// The owner of the new NFT.
+func (inst *MintV1) SetLeafOwnerAccount(leafOwner ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(leafOwner)
+	return inst
+}
+
+// GetLeafOwnerAccount gets the "leafOwner" account.
+// The owner of the new NFT.
+func (inst *MintV1) GetLeafOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetLeafDelegateAccount sets the "leafDelegate" account.
+// The delegate of the new NFT; usually its owner.
+func (inst *MintV1) SetLeafDelegateAccount(leafDelegate ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(leafDelegate)
+	return inst
+}
+
+// GetLeafDelegateAccount gets the "leafDelegate" account.
+// The delegate of the new NFT; usually its owner.
+func (inst *MintV1) GetLeafDelegateAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetMerkleTreeAccount sets the "merkleTree" account.
+// The merkle tree of the leaf.
+func (inst *MintV1) SetMerkleTreeAccount(merkleTree ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(merkleTree).WRITE()
+	return inst
+}
+
+// GetMerkleTreeAccount gets the "merkleTree" account.
+// The merkle tree of the leaf.
+func (inst *MintV1) GetMerkleTreeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetPayerAccount sets the "payer" account.
+// The payer.
+func (inst *MintV1) SetPayerAccount(payer ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(payer).SIGNER()
+	return inst
+}
+
+// GetPayerAccount gets the "payer" account.
+// The payer.
+func (inst *MintV1) GetPayerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetTreeDelegateAccount sets the "treeDelegate" account.
+// The creator or the delegate of the tree; any signer for public trees.
+func (inst *MintV1) SetTreeDelegateAccount(treeDelegate ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(treeDelegate).SIGNER()
+	return inst
+}
+
+// GetTreeDelegateAccount gets the "treeDelegate" account.
+// The creator or the delegate of the tree; any signer for public trees.
+func (inst *MintV1) GetTreeDelegateAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetLogWrapperAccount sets the "logWrapper" account.
+// The noop program, which logs the changes of the tree.
+func (inst *MintV1) SetLogWrapperAccount(logWrapper ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(logWrapper)
+	return inst
+}
+
+// GetLogWrapperAccount gets the "logWrapper" account.
+// The noop program, which logs the changes of the tree.
+func (inst *MintV1) GetLogWrapperAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetCompressionProgramAccount sets the "compressionProgram" account.
+// The account compression program.
+func (inst *MintV1) SetCompressionProgramAccount(compressionProgram ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(compressionProgram)
+	return inst
+}
+
+// GetCompressionProgramAccount gets the "compressionProgram" account.
+// The account compression program.
+func (inst *MintV1) GetCompressionProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *MintV1) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *MintV1 {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *MintV1) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst MintV1) AccountNames() []string {
+	return []string{"treeAuthority", "leafOwner", "leafDelegate", "merkleTree", "payer", "treeDelegate", "logWrapper", "compressionProgram", "systemProgram"}
+}
+
+func (inst MintV1) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: Instruction_MintV1,
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst MintV1) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *MintV1) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Metadata == nil {
+			return errors.New("Metadata parameter is not set")
+		}
+		if err := inst.Metadata.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.TreeAuthority is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.LeafOwner is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.LeafDelegate is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.MerkleTree is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Payer is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.TreeDelegate is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.LogWrapper is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.CompressionProgram is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *MintV1) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("MintV1")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("Metadata", inst.Metadata))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("     treeAuthority", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("         leafOwner", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("      leafDelegate", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("        merkleTree", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("             payer", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("      treeDelegate", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("        logWrapper", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("compressionProgram", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("     systemProgram", inst.AccountMetaSlice.Get(8)))
+					})
+				})
+		})
+}
+
+func (obj MintV1) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Metadata` param:
+	err = encoder.Encode(obj.Metadata)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *MintV1) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Metadata`:
+	err = decoder.Decode(&obj.Metadata)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewMintV1Instruction declares a new MintV1 instruction with the provided parameters and accounts.
+func NewMintV1Instruction(
+	// Parameters:
+	metadata MetadataArgs,
+	// Accounts:
+	treeAuthority ag_solanago.PublicKey,
+	leafOwner ag_solanago.PublicKey,
+	leafDelegate ag_solanago.PublicKey,
+	merkleTree ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+	treeDelegate ag_solanago.PublicKey,
+) *MintV1 {
+	return NewMintV1InstructionBuilder().
+		SetMetadata(metadata).
+		SetTreeAuthorityAccount(treeAuthority).
+		SetLeafOwnerAccount(leafOwner).
+		SetLeafDelegateAccount(leafDelegate).
+		SetMerkleTreeAccount(merkleTree).
+		SetPayerAccount(payer).
+		SetTreeDelegateAccount(treeDelegate)
+}
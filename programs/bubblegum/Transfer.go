@@ -0,0 +1,448 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bubblegum
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Transfers a compressed NFT to a new owner.
+type Transfer struct {
+	// The root of the tree the proof was made against.
+	Root *ag_solanago.Hash
+	// The hash of the metadata of the leaf.
+	DataHash *ag_solanago.Hash
+	// The hash of the creators of the leaf.
+	CreatorHash *ag_solanago.Hash
+	// The nonce of the leaf, from which its asset ID is derived.
+	Nonce *uint64
+	// The index of the leaf in the tree.
+	Index *uint32
+
+	// [0] = [] treeAuthority
+	// ··········· The tree config, at FindTreeConfigAddress(merkleTree).
+	//
+	// [1] = [] leafOwner
+	// ··········· The owner of the NFT; it signs, unless the delegate does.
+	//
+	// [2] = [] leafDelegate
+	// ··········· The delegate of the NFT; it signs, unless the owner does.
+	//
+	// [3] = [] newLeafOwner
+	// ··········· The new owner of the NFT.
+	//
+	// [4] = [WRITE] merkleTree
+	// ··········· The merkle tree of the leaf.
+	//
+	// [5] = [] logWrapper
+	// ··········· The noop program, which logs the changes of the tree.
+	//
+	// [6] = [] compressionProgram
+	// ··········· The account compression program.
+	//
+	// [7] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [8...] = [] proof
+	// ··········· The nodes of the proof of the leaf, without the ones in the canopy of the tree.
+	Accounts ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+	Proof    ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+func (obj *Transfer) SetAccounts(accounts []*ag_solanago.AccountMeta) error {
+	obj.Accounts, obj.Proof = ag_solanago.AccountMetaSlice(accounts).SplitFrom(8)
+	return nil
+}
+
+func (slice Transfer) GetAccounts() (accounts []*ag_solanago.AccountMeta) {
+	accounts = append(accounts, slice.Accounts...)
+	accounts = append(accounts, slice.Proof...)
+	return
+}
+
+// NewTransferInstructionBuilder creates a new `Transfer` instruction builder.
+func NewTransferInstructionBuilder() *Transfer {
+	nd := &Transfer{
+		Accounts: make(ag_solanago.AccountMetaSlice, 8),
+		Proof:    make(ag_solanago.AccountMetaSlice, 0),
+	}
+	nd.Accounts[5] = ag_solanago.Meta(ag_solanago.SPLNoopProgramID)
+	nd.Accounts[6] = ag_solanago.Meta(ag_solanago.SPLAccountCompressionProgramID)
+	nd.Accounts[7] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	return nd
+}
+
+// SetRoot sets the "root" parameter.
+// The root of the tree the proof was made against.
+func (inst *Transfer) SetRoot(root ag_solanago.Hash) *Transfer {
+	inst.Root = &root
+	return inst
+}
+
+// SetDataHash sets the "dataHash" parameter.
+// The hash of the metadata of the leaf.
+func (inst *Transfer) SetDataHash(dataHash ag_solanago.Hash) *Transfer {
+	inst.DataHash = &dataHash
+	return inst
+}
+
+// SetCreatorHash sets the "creatorHash" parameter.
+// The hash of the creators of the leaf.
+func (inst *Transfer) SetCreatorHash(creatorHash ag_solanago.Hash) *Transfer {
+	inst.CreatorHash = &creatorHash
+	return inst
+}
+
+// SetNonce sets the "nonce" parameter.
+// The nonce of the leaf, from which its asset ID is derived.
+func (inst *Transfer) SetNonce(nonce uint64) *Transfer {
+	inst.Nonce = &nonce
+	return inst
+}
+
+// SetIndex sets the "index" parameter.
+// The index of the leaf in the tree.
+func (inst *Transfer) SetIndex(index uint32) *Transfer {
+	inst.Index = &index
+	return inst
+}
+
+// SetTreeAuthorityAccount sets the "treeAuthority" account.
+// The tree config, at FindTreeConfigAddress(merkleTree).
+func (inst *Transfer) SetTreeAuthorityAccount(treeAuthority ag_solanago.PublicKey) *Transfer {
+	inst.Accounts[0] = ag_solanago.Meta(treeAuthority)
+	return inst
+}
+
+// GetTreeAuthorityAccount gets the "treeAuthority" account.
+// The tree config, at FindTreeConfigAddress(merkleTree).
+func (inst *Transfer) GetTreeAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(0)
+}
+
+// SetLeafOwnerAccount sets the "leafOwner" account.
+// The owner of the NFT; it signs, unless the delegate does.
+func (inst *Transfer) SetLeafOwnerAccount(leafOwner ag_solanago.PublicKey, isSigner bool) *Transfer {
+	inst.Accounts[1] = ag_solanago.Meta(leafOwner)
+	if isSigner {
+		inst.Accounts[1].SIGNER()
+	}
+	return inst
+}
+
+// GetLeafOwnerAccount gets the "leafOwner" account.
+// The owner of the NFT; it signs, unless the delegate does.
+func (inst *Transfer) GetLeafOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(1)
+}
+
+// SetLeafDelegateAccount sets the "leafDelegate" account.
+// The delegate of the NFT; it signs, unless the owner does.
+func (inst *Transfer) SetLeafDelegateAccount(leafDelegate ag_solanago.PublicKey, isSigner bool) *Transfer {
+	inst.Accounts[2] = ag_solanago.Meta(leafDelegate)
+	if isSigner {
+		inst.Accounts[2].SIGNER()
+	}
+	return inst
+}
+
+// GetLeafDelegateAccount gets the "leafDelegate" account.
+// The delegate of the NFT; it signs, unless the owner does.
+func (inst *Transfer) GetLeafDelegateAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(2)
+}
+
+// SetNewLeafOwnerAccount sets the "newLeafOwner" account.
+// The new owner of the NFT.
+func (inst *Transfer) SetNewLeafOwnerAccount(newLeafOwner ag_solanago.PublicKey) *Transfer {
+	inst.Accounts[3] = ag_solanago.Meta(newLeafOwner)
+	return inst
+}
+
+// GetNewLeafOwnerAccount gets the "newLeafOwner" account.
+// The new owner of the NFT.
+func (inst *Transfer) GetNewLeafOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(3)
+}
+
+// SetMerkleTreeAccount sets the "merkleTree" account.
+// The merkle tree of the leaf.
+func (inst *Transfer) SetMerkleTreeAccount(merkleTree ag_solanago.PublicKey) *Transfer {
+	inst.Accounts[4] = ag_solanago.Meta(merkleTree).WRITE()
+	return inst
+}
+
+// GetMerkleTreeAccount gets the "merkleTree" account.
+// The merkle tree of the leaf.
+func (inst *Transfer) GetMerkleTreeAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(4)
+}
+
+// SetLogWrapperAccount sets the "logWrapper" account.
+// The noop program, which logs the changes of the tree.
+func (inst *Transfer) SetLogWrapperAccount(logWrapper ag_solanago.PublicKey) *Transfer {
+	inst.Accounts[5] = ag_solanago.Meta(logWrapper)
+	return inst
+}
+
+// GetLogWrapperAccount gets the "logWrapper" account.
+// The noop program, which logs the changes of the tree.
+func (inst *Transfer) GetLogWrapperAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(5)
+}
+
+// SetCompressionProgramAccount sets the "compressionProgram" account.
+// The account compression program.
+func (inst *Transfer) SetCompressionProgramAccount(compressionProgram ag_solanago.PublicKey) *Transfer {
+	inst.Accounts[6] = ag_solanago.Meta(compressionProgram)
+	return inst
+}
+
+// GetCompressionProgramAccount gets the "compressionProgram" account.
+// The account compression program.
+func (inst *Transfer) GetCompressionProgramAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(6)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *Transfer) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *Transfer {
+	inst.Accounts[7] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *Transfer) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(7)
+}
+
+// SetProof sets the nodes of the proof of the leaf, as the remaining accounts;
+// see accountcompression.TruncateProof for the nodes to leave out.
+func (inst *Transfer) SetProof(proof ...ag_solanago.PublicKey) *Transfer {
+	inst.Proof = make(ag_solanago.AccountMetaSlice, len(proof))
+	for i, node := range proof {
+		inst.Proof[i] = ag_solanago.Meta(node)
+	}
+	return inst
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Transfer) AccountNames() []string {
+	return appendProofNames([]string{"treeAuthority", "leafOwner", "leafDelegate", "newLeafOwner", "merkleTree", "logWrapper", "compressionProgram", "systemProgram"}, inst.Proof)
+}
+
+func (inst Transfer) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: Instruction_Transfer,
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Transfer) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Transfer) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Root == nil {
+			return errors.New("Root parameter is not set")
+		}
+		if inst.DataHash == nil {
+			return errors.New("DataHash parameter is not set")
+		}
+		if inst.CreatorHash == nil {
+			return errors.New("CreatorHash parameter is not set")
+		}
+		if inst.Nonce == nil {
+			return errors.New("Nonce parameter is not set")
+		}
+		if inst.Index == nil {
+			return errors.New("Index parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.Accounts[0] == nil {
+			return errors.New("accounts.TreeAuthority is not set")
+		}
+		if inst.Accounts[1] == nil {
+			return errors.New("accounts.LeafOwner is not set")
+		}
+		if inst.Accounts[2] == nil {
+			return errors.New("accounts.LeafDelegate is not set")
+		}
+		if inst.Accounts[3] == nil {
+			return errors.New("accounts.NewLeafOwner is not set")
+		}
+		if inst.Accounts[4] == nil {
+			return errors.New("accounts.MerkleTree is not set")
+		}
+		if inst.Accounts[5] == nil {
+			return errors.New("accounts.LogWrapper is not set")
+		}
+		if inst.Accounts[6] == nil {
+			return errors.New("accounts.CompressionProgram is not set")
+		}
+		if inst.Accounts[7] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Transfer) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Transfer")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("       Root", inst.Root))
+						paramsBranch.Child(ag_format.Param("   DataHash", inst.DataHash))
+						paramsBranch.Child(ag_format.Param("CreatorHash", inst.CreatorHash))
+						paramsBranch.Child(ag_format.Param("      Nonce", inst.Nonce))
+						paramsBranch.Child(ag_format.Param("      Index", inst.Index))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("     treeAuthority", inst.Accounts.Get(0)))
+						accountsBranch.Child(ag_format.Meta("         leafOwner", inst.Accounts.Get(1)))
+						accountsBranch.Child(ag_format.Meta("      leafDelegate", inst.Accounts.Get(2)))
+						accountsBranch.Child(ag_format.Meta("      newLeafOwner", inst.Accounts.Get(3)))
+						accountsBranch.Child(ag_format.Meta("        merkleTree", inst.Accounts.Get(4)))
+						accountsBranch.Child(ag_format.Meta("        logWrapper", inst.Accounts.Get(5)))
+						accountsBranch.Child(ag_format.Meta("compressionProgram", inst.Accounts.Get(6)))
+						accountsBranch.Child(ag_format.Meta("     systemProgram", inst.Accounts.Get(7)))
+
+						proofBranch := accountsBranch.Child(fmt.Sprintf("proof[len=%v]", len(inst.Proof)))
+						for i, v := range inst.Proof {
+							if len(inst.Proof) > 9 && i < 10 {
+								proofBranch.Child(ag_format.Meta(fmt.Sprintf(" [%v]", i), v))
+							} else {
+								proofBranch.Child(ag_format.Meta(fmt.Sprintf("[%v]", i), v))
+							}
+						}
+					})
+				})
+		})
+}
+
+func (obj Transfer) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Root` param:
+	err = encoder.Encode(obj.Root)
+	if err != nil {
+		return err
+	}
+	// Serialize `DataHash` param:
+	err = encoder.Encode(obj.DataHash)
+	if err != nil {
+		return err
+	}
+	// Serialize `CreatorHash` param:
+	err = encoder.Encode(obj.CreatorHash)
+	if err != nil {
+		return err
+	}
+	// Serialize `Nonce` param:
+	err = encoder.Encode(obj.Nonce)
+	if err != nil {
+		return err
+	}
+	// Serialize `Index` param:
+	err = encoder.Encode(obj.Index)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *Transfer) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Root`:
+	err = decoder.Decode(&obj.Root)
+	if err != nil {
+		return err
+	}
+	// Deserialize `DataHash`:
+	err = decoder.Decode(&obj.DataHash)
+	if err != nil {
+		return err
+	}
+	// Deserialize `CreatorHash`:
+	err = decoder.Decode(&obj.CreatorHash)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Nonce`:
+	err = decoder.Decode(&obj.Nonce)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Index`:
+	err = decoder.Decode(&obj.Index)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewTransferInstruction declares a new Transfer instruction with the provided parameters and accounts.
+func NewTransferInstruction(
+	// Parameters:
+	root ag_solanago.Hash,
+	dataHash ag_solanago.Hash,
+	creatorHash ag_solanago.Hash,
+	nonce uint64,
+	index uint32,
+	// Accounts:
+	treeAuthority ag_solanago.PublicKey,
+	leafOwner ag_solanago.PublicKey,
+	isLeafOwnerSigner bool,
+	leafDelegate ag_solanago.PublicKey,
+	isLeafDelegateSigner bool,
+	newLeafOwner ag_solanago.PublicKey,
+	merkleTree ag_solanago.PublicKey,
+	proof []ag_solanago.PublicKey,
+) *Transfer {
+	return NewTransferInstructionBuilder().
+		SetRoot(root).
+		SetDataHash(dataHash).
+		SetCreatorHash(creatorHash).
+		SetNonce(nonce).
+		SetIndex(index).
+		SetTreeAuthorityAccount(treeAuthority).
+		SetLeafOwnerAccount(leafOwner, isLeafOwnerSigner).
+		SetLeafDelegateAccount(leafDelegate, isLeafDelegateSigner).
+		SetNewLeafOwnerAccount(newLeafOwner).
+		SetMerkleTreeAccount(merkleTree).
+		SetProof(proof...)
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bubblegum
+
+import (
+	"encoding/binary"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+const (
+	ASSET_PREFIX          = "asset"
+	COLLECTION_CPI_PREFIX = "collection_cpi"
+)
+
+// FindTreeConfigAddress returns the address of the tree config of the
+// merkle tree, which is the tree authority of the instructions.
+func FindTreeConfigAddress(merkleTree ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{merkleTree[:]}, ProgramID)
+}
+
+// FindAssetID returns the ID of the compressed NFT minted with the nonce in
+// the merkle tree; it is the ID under which the DAS methods serve it.
+func FindAssetID(merkleTree ag_solanago.PublicKey, nonce uint64) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress(
+		[][]byte{[]byte(ASSET_PREFIX), merkleTree[:], binary.LittleEndian.AppendUint64(nil, nonce)},
+		ProgramID,
+	)
+}
+
+// FindBubblegumSignerAddress returns the address with which the program
+// signs its calls to the token metadata program.
+func FindBubblegumSignerAddress() (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{[]byte(COLLECTION_CPI_PREFIX)}, ProgramID)
+}
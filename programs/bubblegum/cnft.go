@@ -0,0 +1,171 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bubblegum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	accountcompression "github.com/gagliardetto/solana-go/programs/account-compression"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// AssetLeaf is the leaf of a compressed NFT, with its proof, as the
+// instructions that modify it take them.
+type AssetLeaf struct {
+	MerkleTree  ag_solanago.PublicKey
+	Owner       ag_solanago.PublicKey
+	Delegate    ag_solanago.PublicKey
+	Root        ag_solanago.Hash
+	DataHash    ag_solanago.Hash
+	CreatorHash ag_solanago.Hash
+	Nonce       uint64
+	Index       uint32
+
+	// The nodes of the proof, without the ones in the canopy of the tree.
+	Proof []ag_solanago.PublicKey
+}
+
+// NewAssetLeaf returns the leaf of a compressed NFT from the results of the
+// getAsset and getAssetProof DAS methods, and the canopy depth of its tree.
+func NewAssetLeaf(asset *rpc.Asset, proof *rpc.AssetProof, canopyDepth uint32) (*AssetLeaf, error) {
+	if asset.Compression == nil || !asset.Compression.Compressed {
+		return nil, fmt.Errorf("asset %s is not compressed", asset.ID)
+	}
+	if asset.Ownership == nil {
+		return nil, fmt.Errorf("asset %s has no ownership", asset.ID)
+	}
+	owner, err := ag_solanago.PublicKeyFromBase58(asset.Ownership.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner: %w", err)
+	}
+	delegate := owner
+	if asset.Ownership.Delegate != nil {
+		delegate = *asset.Ownership.Delegate
+	}
+	dataHash, err := ag_solanago.HashFromBase58(asset.Compression.DataHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data hash: %w", err)
+	}
+	creatorHash, err := ag_solanago.HashFromBase58(asset.Compression.CreatorHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator hash: %w", err)
+	}
+
+	// The node index counts the nodes from the root, at 1.
+	if len(proof.Proof) >= 32 || proof.NodeIndex < 1<<len(proof.Proof) {
+		return nil, errors.New("node index out of range of the proof")
+	}
+	return &AssetLeaf{
+		MerkleTree:  proof.TreeID,
+		Owner:       owner,
+		Delegate:    delegate,
+		Root:        proof.Root,
+		DataHash:    dataHash,
+		CreatorHash: creatorHash,
+		Nonce:       asset.Compression.LeafID,
+		Index:       uint32(proof.NodeIndex - 1<<len(proof.Proof)),
+		Proof:       accountcompression.TruncateProof(proof.Proof, canopyDepth),
+	}, nil
+}
+
+// FetchAssetLeaf fetches the compressed NFT and its proof from the DAS
+// methods, and the canopy of its tree from the cluster.
+func FetchAssetLeaf(ctx context.Context, rpcCli *rpc.Client, assetID ag_solanago.PublicKey) (*AssetLeaf, error) {
+	asset, err := rpcCli.GetAsset(ctx, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get asset: %w", err)
+	}
+	proof, err := rpcCli.GetAssetProof(ctx, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get asset proof: %w", err)
+	}
+	tree, err := accountcompression.FetchMerkleTree(ctx, rpcCli, proof.TreeID)
+	if err != nil {
+		return nil, err
+	}
+	return NewAssetLeaf(asset, proof, tree.CanopyDepth())
+}
+
+// NewTransferCompressedNFTInstruction returns the instruction transferring
+// the compressed NFT to the new owner, signed by its owner.
+func NewTransferCompressedNFTInstruction(leaf *AssetLeaf, newOwner ag_solanago.PublicKey) (*Transfer, error) {
+	treeConfig, _, err := FindTreeConfigAddress(leaf.MerkleTree)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransferInstruction(
+		leaf.Root,
+		leaf.DataHash,
+		leaf.CreatorHash,
+		leaf.Nonce,
+		leaf.Index,
+		treeConfig,
+		leaf.Owner,
+		true,
+		leaf.Delegate,
+		false,
+		newOwner,
+		leaf.MerkleTree,
+		leaf.Proof,
+	), nil
+}
+
+// NewBurnCompressedNFTInstruction returns the instruction burning the
+// compressed NFT, signed by its owner.
+func NewBurnCompressedNFTInstruction(leaf *AssetLeaf) (*Burn, error) {
+	treeConfig, _, err := FindTreeConfigAddress(leaf.MerkleTree)
+	if err != nil {
+		return nil, err
+	}
+	return NewBurnInstruction(
+		leaf.Root,
+		leaf.DataHash,
+		leaf.CreatorHash,
+		leaf.Nonce,
+		leaf.Index,
+		treeConfig,
+		leaf.Owner,
+		true,
+		leaf.Delegate,
+		false,
+		leaf.MerkleTree,
+		leaf.Proof,
+	), nil
+}
+
+// NewDelegateCompressedNFTInstruction returns the instruction setting the
+// delegate of the compressed NFT, signed by its owner.
+func NewDelegateCompressedNFTInstruction(leaf *AssetLeaf, newDelegate ag_solanago.PublicKey) (*Delegate, error) {
+	treeConfig, _, err := FindTreeConfigAddress(leaf.MerkleTree)
+	if err != nil {
+		return nil, err
+	}
+	return NewDelegateInstruction(
+		leaf.Root,
+		leaf.DataHash,
+		leaf.CreatorHash,
+		leaf.Nonce,
+		leaf.Index,
+		treeConfig,
+		leaf.Owner,
+		leaf.Delegate,
+		newDelegate,
+		leaf.MerkleTree,
+		leaf.Proof,
+	), nil
+}
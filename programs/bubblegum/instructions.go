@@ -0,0 +1,196 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bubblegum builds the instructions of the Metaplex Bubblegum
+// program, which mints and manages compressed NFTs: the leaves of the
+// concurrent merkle trees of the SPL Account Compression program.
+package bubblegum
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_spew "github.com/davecgh/go-spew/spew"
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_text "github.com/gagliardetto/solana-go/text"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+var ProgramID ag_solanago.PublicKey = ag_solanago.BubblegumProgramID
+
+func SetProgramID(pubkey ag_solanago.PublicKey) {
+	ProgramID = pubkey
+	ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
+}
+
+const ProgramName = "Bubblegum"
+
+func init() {
+	if !ProgramID.IsZero() {
+		ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+		registerAccountDecoders(ProgramID)
+	}
+}
+
+// The IDs of the supported instructions: the anchor sighashes of their names.
+var (
+	Instruction_CreateTree = ag_binary.TypeID([8]byte{165, 83, 136, 142, 89, 202, 47, 220})
+
+	Instruction_MintV1 = ag_binary.TypeID([8]byte{145, 98, 192, 118, 184, 147, 118, 104})
+
+	Instruction_MintToCollectionV1 = ag_binary.TypeID([8]byte{153, 18, 178, 47, 197, 158, 86, 15})
+
+	Instruction_Transfer = ag_binary.TypeID([8]byte{163, 52, 200, 231, 140, 3, 69, 186})
+
+	Instruction_Burn = ag_binary.TypeID([8]byte{116, 110, 29, 56, 107, 219, 42, 93})
+
+	Instruction_Delegate = ag_binary.TypeID([8]byte{90, 147, 75, 178, 85, 88, 4, 137})
+)
+
+// InstructionIDToName returns the name of the instruction given its ID.
+func InstructionIDToName(id ag_binary.TypeID) string {
+	switch id {
+	case Instruction_CreateTree:
+		return "CreateTree"
+	case Instruction_MintV1:
+		return "MintV1"
+	case Instruction_MintToCollectionV1:
+		return "MintToCollectionV1"
+	case Instruction_Transfer:
+		return "Transfer"
+	case Instruction_Burn:
+		return "Burn"
+	case Instruction_Delegate:
+		return "Delegate"
+	default:
+		return ""
+	}
+}
+
+type Instruction struct {
+	ag_binary.BaseVariant
+}
+
+func (inst *Instruction) EncodeToTree(parent ag_treeout.Branches) {
+	if enToTree, ok := inst.Impl.(ag_text.EncodableToTree); ok {
+		enToTree.EncodeToTree(parent)
+	} else {
+		parent.Child(ag_spew.Sdump(inst))
+	}
+}
+
+var InstructionImplDef = ag_binary.NewVariantDefinition(
+	ag_binary.AnchorTypeIDEncoding,
+	[]ag_binary.VariantType{
+		{
+			"create_tree", (*CreateTree)(nil),
+		},
+		{
+			"mint_v1", (*MintV1)(nil),
+		},
+		{
+			"mint_to_collection_v1", (*MintToCollectionV1)(nil),
+		},
+		{
+			"transfer", (*Transfer)(nil),
+		},
+		{
+			"burn", (*Burn)(nil),
+		},
+		{
+			"delegate", (*Delegate)(nil),
+		},
+	},
+)
+
+func (inst *Instruction) ProgramID() ag_solanago.PublicKey {
+	return ProgramID
+}
+
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	return InstructionIDToName(inst.TypeID)
+}
+
+func (inst *Instruction) Accounts() (out []*ag_solanago.AccountMeta) {
+	return inst.Impl.(ag_solanago.AccountsGettable).GetAccounts()
+}
+
+// Data returns the borsh-encoded data of the instruction.
+func (inst *Instruction) Data() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := ag_binary.NewBorshEncoder(buf).Encode(inst); err != nil {
+		return nil, fmt.Errorf("unable to encode instruction: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (inst *Instruction) TextEncode(encoder *ag_text.Encoder, option *ag_text.Option) error {
+	return encoder.Encode(inst.Impl, option)
+}
+
+func (inst *Instruction) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	return inst.BaseVariant.UnmarshalBinaryVariant(decoder, InstructionImplDef)
+}
+
+func (inst Instruction) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	err := encoder.WriteBytes(inst.TypeID.Bytes(), false)
+	if err != nil {
+		return fmt.Errorf("unable to write variant type: %w", err)
+	}
+	return encoder.Encode(inst.Impl)
+}
+
+func registryDecodeInstruction(accounts []*ag_solanago.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func DecodeInstruction(accounts []*ag_solanago.AccountMeta, data []byte) (*Instruction, error) {
+	inst := new(Instruction)
+	if err := ag_binary.NewBorshDecoder(data).Decode(inst); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction: %w", err)
+	}
+	if v, ok := inst.Impl.(ag_solanago.AccountsSettable); ok {
+		err := v.SetAccounts(accounts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set accounts for instruction: %w", err)
+		}
+	}
+	return inst, nil
+}
+
+// optionalAccount returns the account, or the program ID that the program
+// takes in place of the omitted optional accounts.
+func optionalAccount(account *ag_solanago.AccountMeta) *ag_solanago.AccountMeta {
+	if account == nil {
+		return ag_solanago.Meta(ProgramID)
+	}
+	return account
+}
+
+func appendProofNames(names []string, proof ag_solanago.AccountMetaSlice) []string {
+	for i := range proof {
+		names = append(names, fmt.Sprintf("proof[%d]", i))
+	}
+	return names
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bubblegum
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	accountcompression "github.com/gagliardetto/solana-go/programs/account-compression"
+	tokenmetadata "github.com/gagliardetto/solana-go/programs/token-metadata"
+	"github.com/gagliardetto/solana-go/rpc"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func appendString(data []byte, s string) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(s)))
+	return append(data, s...)
+}
+
+func TestMintV1(t *testing.T) {
+	tree := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	owner := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	treeConfig, _, err := FindTreeConfigAddress(tree)
+	ag_require.NoError(t, err)
+
+	metadata := MetadataArgs{
+		Name:                 "Compressed",
+		Symbol:               "CNFT",
+		Uri:                  "https://example.com",
+		SellerFeeBasisPoints: 500,
+		IsMutable:            true,
+		Creators:             []tokenmetadata.Creator{{Address: owner, Share: 100}},
+	}
+	inst, err := NewMintV1Instruction(metadata, treeConfig, owner, owner, tree, owner, owner).ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := []byte{145, 98, 192, 118, 184, 147, 118, 104}
+	expected = appendString(expected, "Compressed")
+	expected = appendString(expected, "CNFT")
+	expected = appendString(expected, "https://example.com")
+	expected = binary.LittleEndian.AppendUint16(expected, 500)
+	// Primary sale not happened, mutable, no edition nonce, token
+	// standard, collection nor uses, original token program.
+	expected = append(expected, 0, 1, 0, 0, 0, 0, 0)
+	expected = binary.LittleEndian.AppendUint32(expected, 1)
+	expected = append(expected, owner[:]...)
+	expected = append(expected, 0, 100)
+
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+	ag_require.Equal(t, []*ag_solanago.AccountMeta{
+		ag_solanago.Meta(treeConfig).WRITE(),
+		ag_solanago.Meta(owner),
+		ag_solanago.Meta(owner),
+		ag_solanago.Meta(tree).WRITE(),
+		ag_solanago.Meta(owner).SIGNER(),
+		ag_solanago.Meta(owner).SIGNER(),
+		ag_solanago.Meta(ag_solanago.SPLNoopProgramID),
+		ag_solanago.Meta(ag_solanago.SPLAccountCompressionProgramID),
+		ag_solanago.Meta(ag_solanago.SystemProgramID),
+	}, inst.Accounts())
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "MintV1", decoded.InstructionName())
+	ag_require.Equal(t, metadata, *decoded.Impl.(*MintV1).Metadata)
+
+	metadata.Creators[0].Share = 50
+	_, err = NewMintV1Instruction(metadata, treeConfig, owner, owner, tree, owner, owner).ValidateAndBuild()
+	ag_require.Error(t, err)
+}
+
+func TestTransferWithProof(t *testing.T) {
+	tree := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	owner := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	newOwner := ag_solanago.SysVarClockPubkey
+	proof := []ag_solanago.PublicKey{ag_solanago.SysVarRentPubkey, ag_solanago.SysVarEpochSchedulePubkey}
+
+	var root, dataHash, creatorHash ag_solanago.Hash
+	root[0], dataHash[0], creatorHash[0] = 1, 2, 3
+	inst, err := NewTransferInstruction(root, dataHash, creatorHash, 7, 7, tree, owner, true, owner, false, newOwner, tree, proof).ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := []byte{163, 52, 200, 231, 140, 3, 69, 186}
+	expected = append(expected, root[:]...)
+	expected = append(expected, dataHash[:]...)
+	expected = append(expected, creatorHash[:]...)
+	expected = binary.LittleEndian.AppendUint64(expected, 7)
+	expected = binary.LittleEndian.AppendUint32(expected, 7)
+
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+
+	accounts := inst.Accounts()
+	ag_require.Len(t, accounts, 10)
+	ag_require.Equal(t, ag_solanago.Meta(owner).SIGNER(), accounts[1])
+	ag_require.Equal(t, ag_solanago.Meta(owner), accounts[2])
+	ag_require.Equal(t, ag_solanago.Meta(proof[0]), accounts[8])
+	ag_require.Equal(t, ag_solanago.Meta(proof[1]), accounts[9])
+	ag_require.Equal(t, "proof[1]", inst.Impl.(Transfer).AccountNames()[9])
+
+	decoded, err := DecodeInstruction(accounts, data)
+	ag_require.NoError(t, err)
+	transfer := decoded.Impl.(*Transfer)
+	ag_require.Equal(t, root, *transfer.Root)
+	ag_require.Equal(t, uint32(7), *transfer.Index)
+	ag_require.Equal(t, ag_solanago.AccountMetaSlice{ag_solanago.Meta(proof[0]), ag_solanago.Meta(proof[1])}, transfer.Proof)
+}
+
+func TestTreeConfig(t *testing.T) {
+	creator := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	data := append([]byte{122, 245, 175, 248, 171, 34, 0, 207}, creator[:]...)
+	data = append(data, creator[:]...)
+	data = binary.LittleEndian.AppendUint64(data, 1<<14)
+	data = binary.LittleEndian.AppendUint64(data, 3)
+	data = append(data, 1, 1)
+
+	decoded, err := ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, &TreeConfig{
+		TreeCreator:       creator,
+		TreeDelegate:      creator,
+		TotalMintCapacity: 1 << 14,
+		NumMinted:         3,
+		IsPublic:          true,
+		IsDecompressible:  DecompressibleStateDisabled,
+	}, decoded)
+}
+
+func TestAssetLeaf(t *testing.T) {
+	tree := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	owner := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	newOwner := ag_solanago.SysVarClockPubkey
+	assetID, _, err := FindAssetID(tree, 1)
+	ag_require.NoError(t, err)
+
+	metadata := &MetadataArgs{Name: "Compressed", Creators: []tokenmetadata.Creator{{Address: owner, Verified: true, Share: 100}}}
+	dataHash, err := HashMetadata(metadata)
+	ag_require.NoError(t, err)
+	creatorHash := HashCreators(metadata.Creators)
+
+	// A tree of depth 2 with the NFT at index 1.
+	leaf := LeafSchema{ID: assetID, Owner: owner, Delegate: owner, Nonce: 1, DataHash: dataHash, CreatorHash: creatorHash}.Hash()
+	empty := accountcompression.EmptyNode(0)
+	left := accountcompression.HashNodes(empty, leaf)
+	root := accountcompression.HashNodes(left, accountcompression.EmptyNode(1))
+	proof := []ag_solanago.PublicKey{ag_solanago.PublicKey(empty), ag_solanago.PublicKey(accountcompression.EmptyNode(1))}
+	ag_require.NoError(t, accountcompression.VerifyProof(root, leaf, proof, 1))
+
+	assetLeaf, err := NewAssetLeaf(
+		&rpc.Asset{
+			ID: assetID,
+			Compression: &rpc.AssetCompression{
+				Compressed:  true,
+				DataHash:    dataHash.String(),
+				CreatorHash: creatorHash.String(),
+				Tree:        tree.String(),
+				LeafID:      1,
+			},
+			Ownership: &rpc.AssetOwnership{Owner: owner.String()},
+		},
+		&rpc.AssetProof{Root: root, Proof: proof, NodeIndex: 5, Leaf: leaf, TreeID: tree},
+		1,
+	)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, &AssetLeaf{
+		MerkleTree:  tree,
+		Owner:       owner,
+		Delegate:    owner,
+		Root:        root,
+		DataHash:    dataHash,
+		CreatorHash: creatorHash,
+		Nonce:       1,
+		Index:       1,
+		Proof:       proof[:1],
+	}, assetLeaf)
+
+	transfer, err := NewTransferCompressedNFTInstruction(assetLeaf, newOwner)
+	ag_require.NoError(t, err)
+	ag_require.NoError(t, transfer.Validate())
+	treeConfig, _, err := FindTreeConfigAddress(tree)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, treeConfig, transfer.GetTreeAuthorityAccount().PublicKey)
+	ag_require.Equal(t, newOwner, transfer.GetNewLeafOwnerAccount().PublicKey)
+	ag_require.Len(t, transfer.Proof, 1)
+}
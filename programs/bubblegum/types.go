@@ -0,0 +1,208 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bubblegum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	tokenmetadata "github.com/gagliardetto/solana-go/programs/token-metadata"
+	"golang.org/x/crypto/sha3"
+)
+
+type TokenProgramVersion ag_binary.BorshEnum
+
+const (
+	TokenProgramVersionOriginal TokenProgramVersion = iota
+	TokenProgramVersionToken2022
+)
+
+// MetadataArgs is the metadata of a compressed NFT; only its hash, and the
+// hash of its creators, are stored in the leaf.
+type MetadataArgs struct {
+	Name                 string
+	Symbol               string
+	Uri                  string
+	SellerFeeBasisPoints uint16
+	PrimarySaleHappened  bool
+	IsMutable            bool
+	EditionNonce         *uint8                       `bin:"optional"`
+	TokenStandard        *tokenmetadata.TokenStandard `bin:"optional"`
+
+	// The collection of the NFT; it must be unverified for MintV1, and
+	// MintToCollectionV1 verifies it.
+	Collection          *tokenmetadata.Collection `bin:"optional"`
+	Uses                *tokenmetadata.Uses       `bin:"optional"`
+	TokenProgramVersion TokenProgramVersion
+	Creators            []tokenmetadata.Creator
+}
+
+func (args MetadataArgs) validate() error {
+	if len(args.Name) > tokenmetadata.MAX_NAME_LENGTH {
+		return fmt.Errorf("name is longer than %v bytes", tokenmetadata.MAX_NAME_LENGTH)
+	}
+	if len(args.Symbol) > tokenmetadata.MAX_SYMBOL_LENGTH {
+		return fmt.Errorf("symbol is longer than %v bytes", tokenmetadata.MAX_SYMBOL_LENGTH)
+	}
+	if len(args.Uri) > tokenmetadata.MAX_URI_LENGTH {
+		return fmt.Errorf("uri is longer than %v bytes", tokenmetadata.MAX_URI_LENGTH)
+	}
+	if len(args.Creators) > tokenmetadata.MAX_CREATOR_LIMIT {
+		return fmt.Errorf("more than %v creators", tokenmetadata.MAX_CREATOR_LIMIT)
+	}
+	if len(args.Creators) > 0 {
+		total := 0
+		for _, creator := range args.Creators {
+			total += int(creator.Share)
+		}
+		if total != 100 {
+			return errors.New("the shares of the creators must add up to 100")
+		}
+	}
+	return nil
+}
+
+func keccak(data ...[]byte) (out ag_solanago.Hash) {
+	hash := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		hash.Write(d)
+	}
+	copy(out[:], hash.Sum(nil))
+	return
+}
+
+// HashMetadata returns the data hash of the leaf of the metadata.
+func HashMetadata(args *MetadataArgs) (ag_solanago.Hash, error) {
+	buf := new(bytes.Buffer)
+	if err := ag_binary.NewBorshEncoder(buf).Encode(args); err != nil {
+		return ag_solanago.Hash{}, fmt.Errorf("unable to encode metadata: %w", err)
+	}
+	argsHash := keccak(buf.Bytes())
+	return keccak(argsHash[:], binary.LittleEndian.AppendUint16(nil, args.SellerFeeBasisPoints)), nil
+}
+
+// HashCreators returns the creator hash of the leaf of the creators.
+func HashCreators(creators []tokenmetadata.Creator) ag_solanago.Hash {
+	data := make([][]byte, 0, len(creators))
+	for _, creator := range creators {
+		verified := byte(0)
+		if creator.Verified {
+			verified = 1
+		}
+		data = append(data, append(creator.Address.Bytes(), verified, creator.Share))
+	}
+	return keccak(data...)
+}
+
+// LEAF_SCHEMA_VERSION_V1 is the version of the leaves minted by the program.
+const LEAF_SCHEMA_VERSION_V1 = 1
+
+// LeafSchema is the content of a leaf of the tree, which holds its hash.
+type LeafSchema struct {
+	// The asset ID of the NFT, at FindAssetID(merkleTree, Nonce).
+	ID          ag_solanago.PublicKey
+	Owner       ag_solanago.PublicKey
+	Delegate    ag_solanago.PublicKey
+	Nonce       uint64
+	DataHash    ag_solanago.Hash
+	CreatorHash ag_solanago.Hash
+}
+
+// Hash returns the leaf node of the tree for the leaf.
+func (leaf LeafSchema) Hash() ag_solanago.Hash {
+	return keccak(
+		[]byte{LEAF_SCHEMA_VERSION_V1},
+		leaf.ID[:],
+		leaf.Owner[:],
+		leaf.Delegate[:],
+		binary.LittleEndian.AppendUint64(nil, leaf.Nonce),
+		leaf.DataHash[:],
+		leaf.CreatorHash[:],
+	)
+}
+
+type DecompressibleState ag_binary.BorshEnum
+
+const (
+	DecompressibleStateEnabled DecompressibleState = iota
+	DecompressibleStateDisabled
+)
+
+// TreeConfigDiscriminator is the anchor discriminator of the tree config accounts.
+var TreeConfigDiscriminator = [8]byte{122, 245, 175, 248, 171, 34, 0, 207}
+
+// TreeConfig is the account of the program for a merkle tree, at
+// FindTreeConfigAddress(merkleTree).
+type TreeConfig struct {
+	TreeCreator  ag_solanago.PublicKey
+	TreeDelegate ag_solanago.PublicKey
+
+	// The number of leaves of the tree: 2^MaxDepth.
+	TotalMintCapacity uint64
+	NumMinted         uint64
+
+	// Whether anyone can mint to the tree.
+	IsPublic         bool
+	IsDecompressible DecompressibleState
+}
+
+func (config TreeConfig) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if err := encoder.WriteBytes(TreeConfigDiscriminator[:], false); err != nil {
+		return err
+	}
+	type noMethods TreeConfig
+	return encoder.Encode(noMethods(config))
+}
+
+func (config *TreeConfig) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	discriminator, err := decoder.ReadNBytes(8)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(discriminator, TreeConfigDiscriminator[:]) {
+		return fmt.Errorf("wrong discriminator: %v", discriminator)
+	}
+	// The newer versions of the program append fields, which are ignored.
+	type noMethods TreeConfig
+	return decoder.Decode((*noMethods)(config))
+}
+
+func DecodeTreeConfig(data []byte) (*TreeConfig, error) {
+	config := new(TreeConfig)
+	if err := ag_binary.NewBorshDecoder(data).Decode(config); err != nil {
+		return nil, fmt.Errorf("unable to decode tree config: %w", err)
+	}
+	return config, nil
+}
+
+func registerAccountDecoders(programID ag_solanago.PublicKey) {
+	ag_solanago.RegisterAccountDecoder(
+		programID,
+		ag_solanago.AccountDiscriminatorMatcher(TreeConfigDiscriminator[:]),
+		decodeTreeConfigAccount,
+	)
+}
+
+func decodeTreeConfigAccount(data []byte) (interface{}, error) {
+	out, err := DecodeTreeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -109,6 +109,15 @@ func (inst *Instruction) ProgramID() ag_solanago.PublicKey {
 	return ProgramID
 }
 
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
 func (inst *Instruction) Accounts() (out []*ag_solanago.AccountMeta) {
 	return inst.Impl.(ag_solanago.AccountsGettable).GetAccounts()
 }
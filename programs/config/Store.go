@@ -0,0 +1,225 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/text/format"
+	"github.com/gagliardetto/treeout"
+)
+
+// Store stores the keys and the data of a config account. The config account
+// must sign when it is not among the signer keys and was just created; the
+// signer keys must sign, and so must the previous ones for an update.
+type Store struct {
+	Keys ConfigKeys
+	// The data of the config; its layout depends on the config.
+	Data []byte
+
+	// [0] = [WRITE] ConfigAccount
+	// ··········· The config account; a signer if isConfigSigner.
+	//
+	// [1...] = [SIGNER] signers
+	// ··········· The signer keys, other than the config account.
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Store) AccountNames() []string {
+	names := []string{"configAccount"}
+	for i := 1; i < len(inst.AccountMetaSlice); i++ {
+		names = append(names, fmt.Sprintf("signers[%d]", i-1))
+	}
+	return names
+}
+
+func (inst *Store) SetAccounts(accounts []*solana.AccountMeta) error {
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// NewStoreInstructionBuilder creates a new `Store` instruction builder.
+func NewStoreInstructionBuilder() *Store {
+	return &Store{
+		AccountMetaSlice: make(solana.AccountMetaSlice, 1),
+	}
+}
+
+// NewStoreInstruction declares a new Store instruction with the provided parameters and accounts.
+func NewStoreInstruction(
+	// Params:
+	keys ConfigKeys,
+	data []byte,
+	// Accounts:
+	configAccount solana.PublicKey,
+	isConfigSigner bool,
+) *Store {
+	return NewStoreInstructionBuilder().
+		SetKeys(keys).
+		SetData(data).
+		SetConfigAccount(configAccount, isConfigSigner)
+}
+
+// NewValidatorInfoStoreInstruction publishes the info of a validator, like
+// `solana validator-info publish`, to its config (see FindValidatorInfoAddress),
+// which must have been created with the identity as base. The identity signs.
+func NewValidatorInfoStoreInstruction(identity solana.PublicKey, info ValidatorInfo) (*Store, error) {
+	configAccount, err := FindValidatorInfoAddress(identity)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > MAX_VALIDATOR_INFO {
+		return nil, fmt.Errorf("validator info is %d bytes, the maximum is %d", len(raw), MAX_VALIDATOR_INFO)
+	}
+	data := new(bytes.Buffer)
+	if err := bin.NewBinEncoder(data).WriteRustString(string(raw)); err != nil {
+		return nil, err
+	}
+	return NewStoreInstruction(
+		ConfigKeys{
+			{Pubkey: ValidatorInfoKey, Signer: false},
+			{Pubkey: identity, Signer: true},
+		},
+		data.Bytes(),
+		configAccount,
+		false,
+	), nil
+}
+
+// SetKeys sets the keys of the config.
+func (inst *Store) SetKeys(keys ConfigKeys) *Store {
+	inst.Keys = keys
+	return inst.setSigners()
+}
+
+// AddKey adds a key to the config.
+func (inst *Store) AddKey(pubkey solana.PublicKey, signer bool) *Store {
+	inst.Keys = append(inst.Keys, ConfigKey{Pubkey: pubkey, Signer: signer})
+	return inst.setSigners()
+}
+
+// SetData sets the data of the config.
+func (inst *Store) SetData(data []byte) *Store {
+	inst.Data = data
+	return inst
+}
+
+// SetConfigAccount sets the config account, a signer if isConfigSigner
+// (e.g. when storing the config for the first time).
+func (inst *Store) SetConfigAccount(configAccount solana.PublicKey, isConfigSigner bool) *Store {
+	inst.AccountMetaSlice[0] = solana.Meta(configAccount).WRITE()
+	if isConfigSigner {
+		inst.AccountMetaSlice[0].SIGNER()
+	}
+	return inst.setSigners()
+}
+
+func (inst *Store) GetConfigAccount() *solana.AccountMeta {
+	return inst.AccountMetaSlice[0]
+}
+
+// setSigners sets the accounts following the config account:
+// the signer keys, other than the config account.
+func (inst *Store) setSigners() *Store {
+	inst.AccountMetaSlice = inst.AccountMetaSlice[:1]
+	for _, signer := range inst.Keys.Signers() {
+		if config := inst.AccountMetaSlice[0]; config != nil && config.PublicKey.Equals(signer) {
+			continue
+		}
+		inst.AccountMetaSlice = append(inst.AccountMetaSlice, solana.Meta(signer).SIGNER())
+	}
+	return inst
+}
+
+func (inst Store) Build() *Instruction {
+	return &Instruction{BaseVariant: bin.BaseVariant{
+		Impl:   inst,
+		TypeID: bin.NoTypeIDDefaultID,
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Store) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Store) Validate() error {
+	if inst.AccountMetaSlice[0] == nil {
+		return errors.New("accounts.ConfigAccount is not set")
+	}
+	if len(inst.Keys) > 0xffff {
+		return fmt.Errorf("too many keys: %d", len(inst.Keys))
+	}
+	return nil
+}
+
+func (inst *Store) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Store")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=2]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(fmt.Sprintf("Keys[len=%v]", len(inst.Keys))).ParentFunc(func(keysBranch treeout.Branches) {
+							for i, key := range inst.Keys {
+								keysBranch.Child(format.Param(fmt.Sprintf("[%v]", i), fmt.Sprintf("%s (signer: %v)", key.Pubkey, key.Signer)))
+							}
+						})
+						paramsBranch.Child(format.Param("Data", bin.FormatByteSlice(inst.Data)))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%v]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("configAccount", inst.AccountMetaSlice.Get(0)))
+						for i := 1; i < len(inst.AccountMetaSlice); i++ {
+							accountsBranch.Child(format.Meta(fmt.Sprintf("   signers[%v]", i-1), inst.AccountMetaSlice.Get(i)))
+						}
+					})
+				})
+		})
+}
+
+func (inst Store) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := inst.Keys.MarshalWithEncoder(encoder); err != nil {
+		return err
+	}
+	return encoder.WriteBytes(inst.Data, false)
+}
+
+func (inst *Store) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if err = inst.Keys.UnmarshalWithDecoder(decoder); err != nil {
+		return err
+	}
+	inst.Data, err = decoder.ReadNBytes(decoder.Remaining())
+	return err
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidatorInfoStoreInstruction(t *testing.T) {
+	identity := solana.MustPublicKeyFromBase58("7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932")
+	configAccount, err := FindValidatorInfoAddress(identity)
+	require.NoError(t, err)
+
+	store, err := NewValidatorInfoStoreInstruction(identity, ValidatorInfo{Name: "Validator", Website: "https://example.com"})
+	require.NoError(t, err)
+	inst, err := store.ValidateAndBuild()
+	require.NoError(t, err)
+
+	require.Equal(t, []*solana.AccountMeta{
+		solana.Meta(configAccount).WRITE(),
+		solana.Meta(identity).SIGNER(),
+	}, inst.Accounts())
+
+	// The data of the instruction is the data of the account.
+	data, err := inst.Data()
+	require.NoError(t, err)
+	info, err := DecodeValidatorInfoAccount(data)
+	require.NoError(t, err)
+	require.Equal(t, identity, info.Identity)
+	require.Equal(t, `{"name":"Validator","website":"https://example.com"}`, info.RawInfo)
+
+	decoded, err := solana.DecodeInstruction(ProgramID, inst.Accounts(), data)
+	require.NoError(t, err)
+	require.Equal(t, store.Keys, decoded.(*Instruction).Impl.(*Store).Keys)
+	require.Equal(t, store.Data, decoded.(*Instruction).Impl.(*Store).Data)
+	require.Equal(t, []string{"configAccount", "signers[0]"}, decoded.(*Instruction).Impl.(*Store).AccountNames())
+
+	_, err = NewValidatorInfoStoreInstruction(identity, ValidatorInfo{Details: string(make([]byte, MAX_VALIDATOR_INFO))})
+	require.Error(t, err)
+}
+
+func TestStore_signers(t *testing.T) {
+	configAccount := solana.NewWallet().PublicKey()
+	owner := solana.NewWallet().PublicKey()
+	other := solana.NewWallet().PublicKey()
+
+	// The config account is not repeated among the signers.
+	inst := NewStoreInstructionBuilder().
+		SetConfigAccount(configAccount, true).
+		AddKey(configAccount, true).
+		AddKey(other, false).
+		AddKey(owner, true).
+		SetData([]byte{1, 2, 3}).
+		Build()
+	require.Equal(t, []*solana.AccountMeta{
+		solana.Meta(configAccount).WRITE().SIGNER(),
+		solana.Meta(owner).SIGNER(),
+	}, inst.Accounts())
+
+	_, err := NewStoreInstructionBuilder().ValidateAndBuild()
+	require.Error(t, err)
+}
@@ -0,0 +1,250 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	// ValidatorInfoKey is the first key of the validator info configs.
+	ValidatorInfoKey = solana.MustPublicKeyFromBase58("Va1idator1nfo111111111111111111111111111111")
+)
+
+const (
+	// MAX_VALIDATOR_INFO is the maximum size of the JSON info of a validator.
+	MAX_VALIDATOR_INFO = 576
+	// VALIDATOR_INFO_SEED is the seed of the address of the validator info
+	// config, derived from the validator identity.
+	VALIDATOR_INFO_SEED = "validator-info"
+
+	// The offset of the first key in the data of a config account.
+	validatorInfoKeyOffset = 1
+)
+
+// ConfigKey is a key of a config; the signers must sign its updates.
+type ConfigKey struct {
+	Pubkey solana.PublicKey
+	Signer bool
+}
+
+// ConfigKeys is the list of keys at the start of the data of a config
+// account, with a compact-u16 length.
+type ConfigKeys []ConfigKey
+
+func (keys ConfigKeys) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteCompactU16(len(keys)); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := encoder.WriteBytes(key.Pubkey[:], false); err != nil {
+			return err
+		}
+		if err := encoder.WriteBool(key.Signer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (keys *ConfigKeys) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	count, err := decoder.ReadCompactU16()
+	if err != nil {
+		return fmt.Errorf("unable to read the number of keys: %w", err)
+	}
+	if count > decoder.Remaining()/(solana.PublicKeyLength+1) {
+		return fmt.Errorf("%d keys exceed the remaining data", count)
+	}
+	*keys = make(ConfigKeys, count)
+	for i := range *keys {
+		key := &(*keys)[i]
+		b, err := decoder.ReadNBytes(solana.PublicKeyLength)
+		if err != nil {
+			return err
+		}
+		key.Pubkey = solana.PublicKeyFromBytes(b)
+		if key.Signer, err = decoder.ReadBool(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Signers returns the keys that must sign the updates of the config.
+func (keys ConfigKeys) Signers() (out []solana.PublicKey) {
+	for _, key := range keys {
+		if key.Signer {
+			out = append(out, key.Pubkey)
+		}
+	}
+	return out
+}
+
+// ConfigAccount is the data of a config account: its keys, followed
+// by the data of the config, whose layout depends on the config.
+type ConfigAccount struct {
+	Keys ConfigKeys
+	Data []byte
+}
+
+func (acc ConfigAccount) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := acc.Keys.MarshalWithEncoder(encoder); err != nil {
+		return err
+	}
+	return encoder.WriteBytes(acc.Data, false)
+}
+
+func (acc *ConfigAccount) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if err = acc.Keys.UnmarshalWithDecoder(decoder); err != nil {
+		return err
+	}
+	acc.Data, err = decoder.ReadNBytes(decoder.Remaining())
+	return err
+}
+
+func DecodeConfigAccount(data []byte) (*ConfigAccount, error) {
+	acc := new(ConfigAccount)
+	if err := bin.NewBinDecoder(data).Decode(acc); err != nil {
+		return nil, fmt.Errorf("unable to decode config account: %w", err)
+	}
+	return acc, nil
+}
+
+// IsValidatorInfo reports whether the config is the info of a validator.
+func (acc *ConfigAccount) IsValidatorInfo() bool {
+	return len(acc.Keys) > 0 && acc.Keys[0].Pubkey.Equals(ValidatorInfoKey)
+}
+
+// ValidatorInfo is the info published by a validator
+// (see `solana validator-info publish`).
+type ValidatorInfo struct {
+	Name            string `json:"name"`
+	Website         string `json:"website,omitempty"`
+	KeybaseUsername string `json:"keybaseUsername,omitempty"`
+	Details         string `json:"details,omitempty"`
+	IconURL         string `json:"iconUrl,omitempty"`
+}
+
+// ValidatorInfoAccount is the data of a validator info config account.
+type ValidatorInfoAccount struct {
+	Keys ConfigKeys
+	// The identity of the validator, which signs the updates.
+	Identity solana.PublicKey
+	// The info as published: a JSON object, which Info is parsed from.
+	RawInfo string
+	Info    ValidatorInfo
+}
+
+// ValidatorInfo decodes the info of a validator info config.
+func (acc *ConfigAccount) ValidatorInfo() (*ValidatorInfoAccount, error) {
+	if !acc.IsValidatorInfo() {
+		return nil, fmt.Errorf("not a validator info config")
+	}
+	out := &ValidatorInfoAccount{Keys: acc.Keys}
+	for _, key := range acc.Keys[1:] {
+		if key.Signer {
+			out.Identity = key.Pubkey
+			break
+		}
+	}
+	var err error
+	if out.RawInfo, err = bin.NewBinDecoder(acc.Data).ReadRustString(); err != nil {
+		return nil, fmt.Errorf("unable to read validator info: %w", err)
+	}
+	if err := json.Unmarshal([]byte(out.RawInfo), &out.Info); err != nil {
+		return nil, fmt.Errorf("unable to parse validator info: %w", err)
+	}
+	return out, nil
+}
+
+func DecodeValidatorInfoAccount(data []byte) (*ValidatorInfoAccount, error) {
+	acc, err := DecodeConfigAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	return acc.ValidatorInfo()
+}
+
+// FindValidatorInfoAddress returns the address of the validator info config
+// of a validator, as created by `solana validator-info publish`.
+func FindValidatorInfoAddress(identity solana.PublicKey) (solana.PublicKey, error) {
+	return solana.CreateWithSeed(identity, VALIDATOR_INFO_SEED, ProgramID)
+}
+
+// StakeConfig is the data of the stake config (at solana.SysVarStakeConfigPubkey).
+type StakeConfig struct {
+	// The rate at which stake is activated and deactivated per epoch.
+	WarmupCooldownRate float64
+	// The percentage of stake slashed, unused.
+	SlashPenalty uint8
+}
+
+// StakeConfig decodes the data of the stake config.
+func (acc *ConfigAccount) StakeConfig() (*StakeConfig, error) {
+	out := new(StakeConfig)
+	decoder := bin.NewBinDecoder(acc.Data)
+	var err error
+	if out.WarmupCooldownRate, err = decoder.ReadFloat64(bin.LE); err != nil {
+		return nil, fmt.Errorf("unable to decode stake config: %w", err)
+	}
+	if out.SlashPenalty, err = decoder.ReadUint8(); err != nil {
+		return nil, fmt.Errorf("unable to decode stake config: %w", err)
+	}
+	return out, nil
+}
+
+func DecodeStakeConfig(data []byte) (*StakeConfig, error) {
+	acc, err := DecodeConfigAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	return acc.StakeConfig()
+}
+
+func registerAccountDecoders(programID solana.PublicKey) {
+	solana.RegisterAccountDecoder(programID, isValidatorInfo, registryDecodeValidatorInfo)
+	solana.RegisterAccountDecoder(programID, nil, registryDecodeAccount)
+}
+
+// isValidatorInfo matches the configs whose first key (after a
+// single-byte length) is ValidatorInfoKey.
+func isValidatorInfo(data []byte) bool {
+	return len(data) > validatorInfoKeyOffset+solana.PublicKeyLength &&
+		data[0] > 0 && data[0] < 0x80 &&
+		bytes.Equal(data[validatorInfoKeyOffset:validatorInfoKeyOffset+solana.PublicKeyLength], ValidatorInfoKey[:])
+}
+
+func registryDecodeValidatorInfo(data []byte) (interface{}, error) {
+	acc, err := DecodeValidatorInfoAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// registryDecodeAccount decodes the other configs generically; the
+// layout of their data depends on the config (see e.g. StakeConfig).
+func registryDecodeAccount(data []byte) (interface{}, error) {
+	acc, err := DecodeConfigAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
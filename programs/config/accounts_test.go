@@ -0,0 +1,83 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeValidatorInfoAccount(t *testing.T) {
+	identity := solana.MustPublicKeyFromBase58("7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932")
+	info := `{"name":"Validator","website":"https://example.com","keybaseUsername":"val","details":"Hello"}`
+
+	data := []byte{2}
+	data = append(data, ValidatorInfoKey[:]...)
+	data = append(data, 0)
+	data = append(data, identity[:]...)
+	data = append(data, 1)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(info)))
+	data = append(data, info...)
+
+	decoded, err := solana.DecodeAccount(ProgramID, data)
+	require.NoError(t, err)
+	require.Equal(t, &ValidatorInfoAccount{
+		Keys: ConfigKeys{
+			{Pubkey: ValidatorInfoKey, Signer: false},
+			{Pubkey: identity, Signer: true},
+		},
+		Identity: identity,
+		RawInfo:  info,
+		Info: ValidatorInfo{
+			Name:            "Validator",
+			Website:         "https://example.com",
+			KeybaseUsername: "val",
+			Details:         "Hello",
+		},
+	}, decoded)
+
+	acc, err := DecodeConfigAccount(data)
+	require.NoError(t, err)
+	require.True(t, acc.IsValidatorInfo())
+	encoded, err := bin.MarshalBin(acc)
+	require.NoError(t, err)
+	require.Equal(t, data, encoded)
+
+	_, err = DecodeConfigAccount([]byte{3, 1, 2})
+	require.Error(t, err)
+}
+
+func TestDecodeStakeConfig(t *testing.T) {
+	// No keys, a warmup/cooldown rate of 0.25 and a slash penalty of 12.
+	data := []byte{0}
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(0.25))
+	data = append(data, 12)
+
+	config, err := DecodeStakeConfig(data)
+	require.NoError(t, err)
+	require.Equal(t, &StakeConfig{WarmupCooldownRate: 0.25, SlashPenalty: 12}, config)
+
+	decoded, err := solana.DecodeAccount(ProgramID, data)
+	require.NoError(t, err)
+	require.Equal(t, &ConfigAccount{Keys: ConfigKeys{}, Data: data[1:]}, decoded)
+
+	_, err = DecodeStakeConfig(data[:5])
+	require.Error(t, err)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// FetchValidatorInfos fetches the infos published by the validators, like
+// `solana validator-info get`; the configs whose info cannot be parsed
+// are skipped.
+func FetchValidatorInfos(ctx context.Context, rpcCli *rpc.Client) (out []*ValidatorInfoAccount, err error) {
+	resp, err := rpcCli.GetProgramAccountsWithOpts(
+		ctx,
+		ProgramID,
+		&rpc.GetProgramAccountsOpts{
+			Filters: []rpc.RPCFilter{
+				rpc.NewMemcmpFilterPublicKey(validatorInfoKeyOffset, ValidatorInfoKey),
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, keyedAcct := range resp {
+		info, err := DecodeValidatorInfoAccount(keyedAcct.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+// FetchStakeConfig fetches the stake config.
+func FetchStakeConfig(ctx context.Context, rpcCli *rpc.Client) (*StakeConfig, error) {
+	resp, err := rpcCli.GetAccountInfo(ctx, solana.SysVarStakeConfigPubkey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get stake config account: %w", err)
+	}
+	return DecodeStakeConfig(resp.GetBinary())
+}
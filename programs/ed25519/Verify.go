@@ -0,0 +1,146 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Verify builds an instruction verifying one or more signatures, whose data
+// is either carried by the instruction or located in other instructions
+// of the transaction.
+type Verify struct {
+	signatures []verifySignature
+}
+
+type verifySignature struct {
+	// Set for the signatures located in other instructions.
+	offsets *SignatureOffsets
+
+	publicKey solana.PublicKey
+	signature solana.Signature
+	message   []byte
+}
+
+// NewVerifyInstructionBuilder creates a new `Verify` instruction builder.
+func NewVerifyInstructionBuilder() *Verify {
+	return &Verify{}
+}
+
+// NewVerifyInstruction verifies a signature of the message by the public key,
+// all carried by the instruction.
+func NewVerifyInstruction(
+	publicKey solana.PublicKey,
+	signature solana.Signature,
+	message []byte,
+) *Verify {
+	return NewVerifyInstructionBuilder().AddSignature(publicKey, signature, message)
+}
+
+// NewSignInstruction signs the message with the private key,
+// and verifies the signature.
+func NewSignInstruction(privateKey solana.PrivateKey, message []byte) (*Verify, error) {
+	signature, err := privateKey.Sign(message)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifyInstruction(privateKey.PublicKey(), signature, message), nil
+}
+
+// AddSignature adds a signature to verify, carried by the instruction
+// with its public key and message.
+func (inst *Verify) AddSignature(
+	publicKey solana.PublicKey,
+	signature solana.Signature,
+	message []byte,
+) *Verify {
+	inst.signatures = append(inst.signatures, verifySignature{
+		publicKey: publicKey,
+		signature: signature,
+		message:   message,
+	})
+	return inst
+}
+
+// AddSignatureOffsets adds a signature to verify, located by the offsets
+// (e.g. in the data of another instruction of the transaction).
+func (inst *Verify) AddSignatureOffsets(offsets SignatureOffsets) *Verify {
+	inst.signatures = append(inst.signatures, verifySignature{offsets: &offsets})
+	return inst
+}
+
+func (inst Verify) Build() *Instruction {
+	out := &Instruction{
+		Offsets: make([]SignatureOffsets, len(inst.signatures)),
+		Payload: []byte{},
+	}
+	start := SignatureOffsetsStart + SignatureOffsetsSize*len(inst.signatures)
+	for i, sig := range inst.signatures {
+		if sig.offsets != nil {
+			out.Offsets[i] = *sig.offsets
+			continue
+		}
+		// The public key, the signature, then the message,
+		// like the native instruction builder.
+		offset := start + len(out.Payload)
+		out.Offsets[i] = SignatureOffsets{
+			PublicKeyOffset:           uint16(offset),
+			PublicKeyInstructionIndex: CurrentInstructionIndex,
+			SignatureOffset:           uint16(offset + PublicKeySize),
+			SignatureInstructionIndex: CurrentInstructionIndex,
+			MessageDataOffset:         uint16(offset + PublicKeySize + SignatureSize),
+			MessageDataSize:           uint16(len(sig.message)),
+			MessageInstructionIndex:   CurrentInstructionIndex,
+		}
+		out.Payload = append(out.Payload, sig.publicKey[:]...)
+		out.Payload = append(out.Payload, sig.signature[:]...)
+		out.Payload = append(out.Payload, sig.message...)
+	}
+	return out
+}
+
+// ValidateAndBuild validates the instruction parameters.
+// If there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Verify) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Verify) Validate() error {
+	if len(inst.signatures) == 0 {
+		return errors.New("no signature to verify")
+	}
+	if len(inst.signatures) > math.MaxUint8 {
+		return fmt.Errorf("too many signatures: %d", len(inst.signatures))
+	}
+	// The offsets must fit the data of the instruction.
+	size := SignatureOffsetsStart + SignatureOffsetsSize*len(inst.signatures)
+	for _, sig := range inst.signatures {
+		if sig.offsets == nil {
+			size += PublicKeySize + SignatureSize + len(sig.message)
+		}
+	}
+	if size > math.MaxUint16 {
+		return fmt.Errorf("instruction data too large: %d bytes", size)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ed25519 builds, decodes and verifies the instructions of the
+// Ed25519SigVerify precompile, which fails the transaction unless all the
+// signatures its instructions point to are valid.
+package ed25519
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+var ProgramID solana.PublicKey = solana.Ed25519ProgramID
+
+func SetProgramID(pubkey solana.PublicKey) {
+	ProgramID = pubkey
+	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+}
+
+const ProgramName = "Ed25519SigVerify"
+
+func init() {
+	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+}
+
+const (
+	PublicKeySize = 32
+	SignatureSize = 64
+
+	// The offsets start after the number of signatures and a padding byte.
+	SignatureOffsetsStart = 2
+	SignatureOffsetsSize  = 14
+	// The data of the first signature starts after the offsets
+	// of a single signature.
+	DataStart = SignatureOffsetsStart + SignatureOffsetsSize
+
+	// CurrentInstructionIndex is the instruction index pointing
+	// to the data of the verifying instruction itself.
+	CurrentInstructionIndex uint16 = 0xffff
+)
+
+// SignatureOffsets locates the signature, the public key and the message
+// of a signature to verify, each in the data of the instruction at the
+// given index of the transaction (or of the verifying instruction, with
+// CurrentInstructionIndex).
+type SignatureOffsets struct {
+	SignatureOffset           uint16
+	SignatureInstructionIndex uint16
+	PublicKeyOffset           uint16
+	PublicKeyInstructionIndex uint16
+	MessageDataOffset         uint16
+	MessageDataSize           uint16
+	MessageInstructionIndex   uint16
+}
+
+func (offsets SignatureOffsets) MarshalWithEncoder(encoder *bin.Encoder) error {
+	for _, v := range []uint16{
+		offsets.SignatureOffset,
+		offsets.SignatureInstructionIndex,
+		offsets.PublicKeyOffset,
+		offsets.PublicKeyInstructionIndex,
+		offsets.MessageDataOffset,
+		offsets.MessageDataSize,
+		offsets.MessageInstructionIndex,
+	} {
+		if err := encoder.WriteUint16(v, binary.LittleEndian); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (offsets *SignatureOffsets) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	for _, v := range []*uint16{
+		&offsets.SignatureOffset,
+		&offsets.SignatureInstructionIndex,
+		&offsets.PublicKeyOffset,
+		&offsets.PublicKeyInstructionIndex,
+		&offsets.MessageDataOffset,
+		&offsets.MessageDataSize,
+		&offsets.MessageInstructionIndex,
+	} {
+		if *v, err = decoder.ReadUint16(binary.LittleEndian); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Instruction is an instruction of the precompile: the offsets of the
+// signatures to verify, followed by the data they point to, if any.
+type Instruction struct {
+	Offsets []SignatureOffsets
+	// The data following the offsets; the offsets are relative
+	// to the start of the instruction data, not of the payload.
+	Payload []byte
+}
+
+func (inst *Instruction) ProgramID() solana.PublicKey {
+	return ProgramID
+}
+
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	return "Verify"
+}
+
+func (inst *Instruction) Accounts() (out []*solana.AccountMeta) {
+	return nil
+}
+
+func (inst *Instruction) Data() ([]byte, error) {
+	return bin.MarshalBin(inst)
+}
+
+func (inst *Instruction) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Verify")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Params[len=%v]", len(inst.Offsets)+1)).ParentFunc(func(paramsBranch treeout.Branches) {
+						for i, offsets := range inst.Offsets {
+							paramsBranch.Child(format.Param(fmt.Sprintf("Offsets[%v]", i), offsets))
+						}
+						paramsBranch.Child(format.Param("Payload", bin.FormatByteSlice(inst.Payload)))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts[len=0]").ParentFunc(func(accountsBranch treeout.Branches) {})
+				})
+		})
+}
+
+func (inst Instruction) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if len(inst.Offsets) > 0xff {
+		return fmt.Errorf("too many signatures: %d", len(inst.Offsets))
+	}
+	// The number of signatures, and the padding.
+	if err := encoder.WriteBytes([]byte{uint8(len(inst.Offsets)), 0}, false); err != nil {
+		return err
+	}
+	for i := range inst.Offsets {
+		if err := inst.Offsets[i].MarshalWithEncoder(encoder); err != nil {
+			return fmt.Errorf("unable to write offsets %d: %w", i, err)
+		}
+	}
+	return encoder.WriteBytes(inst.Payload, false)
+}
+
+func (inst *Instruction) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	header, err := decoder.ReadNBytes(SignatureOffsetsStart)
+	if err != nil {
+		return fmt.Errorf("unable to read the number of signatures: %w", err)
+	}
+	inst.Offsets = make([]SignatureOffsets, header[0])
+	for i := range inst.Offsets {
+		if err := inst.Offsets[i].UnmarshalWithDecoder(decoder); err != nil {
+			return fmt.Errorf("unable to read offsets %d: %w", i, err)
+		}
+	}
+	inst.Payload, err = decoder.ReadNBytes(decoder.Remaining())
+	return err
+}
+
+func registryDecodeInstruction(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// DecodeInstruction decodes the offsets and the payload of an instruction;
+// it doesn't verify the signatures (see VerifyInstruction).
+func DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*Instruction, error) {
+	inst := new(Instruction)
+	if err := bin.NewBinDecoder(data).Decode(inst); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction: %w", err)
+	}
+	return inst, nil
+}
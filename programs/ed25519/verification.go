@@ -0,0 +1,129 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// The errors of the precompile.
+var (
+	ErrInvalidPublicKey           = errors.New("invalid public key")
+	ErrInvalidSignature           = errors.New("invalid signature")
+	ErrInvalidDataOffsets         = errors.New("invalid data offsets")
+	ErrInvalidInstructionDataSize = errors.New("invalid instruction data size")
+)
+
+// VerifyInstruction verifies the signatures of the instruction data like the
+// precompile, with instructionDatas the data of all the instructions of the
+// transaction. It fails with one of the errors of the precompile.
+func VerifyInstruction(data []byte, instructionDatas [][]byte) error {
+	if len(data) < SignatureOffsetsStart {
+		return ErrInvalidInstructionDataSize
+	}
+	count := int(data[0])
+	if count == 0 && len(data) > SignatureOffsetsStart {
+		return ErrInvalidInstructionDataSize
+	}
+	if len(data) < SignatureOffsetsStart+count*SignatureOffsetsSize {
+		return ErrInvalidInstructionDataSize
+	}
+	for i := 0; i < count; i++ {
+		start := SignatureOffsetsStart + i*SignatureOffsetsSize
+		var offsets SignatureOffsets
+		if err := bin.NewBinDecoder(data[start : start+SignatureOffsetsSize]).Decode(&offsets); err != nil {
+			return ErrInvalidInstructionDataSize
+		}
+		if err := verifySignatureOffsets(offsets, data, instructionDatas); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func verifySignatureOffsets(offsets SignatureOffsets, data []byte, instructionDatas [][]byte) error {
+	signature, err := dataSlice(data, instructionDatas, offsets.SignatureInstructionIndex, offsets.SignatureOffset, SignatureSize)
+	if err != nil {
+		return err
+	}
+	publicKey, err := dataSlice(data, instructionDatas, offsets.PublicKeyInstructionIndex, offsets.PublicKeyOffset, PublicKeySize)
+	if err != nil {
+		return err
+	}
+	a, err := new(edwards25519.Point).SetBytes(publicKey)
+	if err != nil {
+		return ErrInvalidPublicKey
+	}
+	message, err := dataSlice(data, instructionDatas, offsets.MessageInstructionIndex, offsets.MessageDataOffset, int(offsets.MessageDataSize))
+	if err != nil {
+		return err
+	}
+	// Like verify_strict, reject the public keys and the R of small order.
+	r, err := new(edwards25519.Point).SetBytes(signature[:32])
+	if err != nil || isSmallOrder(a) || isSmallOrder(r) {
+		return ErrInvalidSignature
+	}
+	if !ed25519.Verify(publicKey, message, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func isSmallOrder(point *edwards25519.Point) bool {
+	return new(edwards25519.Point).MultByCofactor(point).Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+func dataSlice(data []byte, instructionDatas [][]byte, instructionIndex uint16, offset uint16, size int) ([]byte, error) {
+	instruction := data
+	if instructionIndex != CurrentInstructionIndex {
+		if int(instructionIndex) >= len(instructionDatas) {
+			return nil, ErrInvalidDataOffsets
+		}
+		instruction = instructionDatas[instructionIndex]
+	}
+	end := int(offset) + size
+	if end > len(instruction) {
+		return nil, ErrInvalidDataOffsets
+	}
+	return instruction[offset:end], nil
+}
+
+// VerifyTransaction verifies, like the precompile, the signatures of all the
+// instructions of the transaction for the program.
+func VerifyTransaction(tx *solana.Transaction) error {
+	instructionDatas := make([][]byte, len(tx.Message.Instructions))
+	for i, inst := range tx.Message.Instructions {
+		instructionDatas[i] = inst.Data
+	}
+	for i, inst := range tx.Message.Instructions {
+		programID, err := tx.Message.Program(inst.ProgramIDIndex)
+		if err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+		if !programID.Equals(ProgramID) {
+			continue
+		}
+		if err := VerifyInstruction(inst.Data, instructionDatas); err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ed25519
+
+import (
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyInstruction(t *testing.T) {
+	privateKey := solana.NewWallet().PrivateKey
+	message := []byte("hello")
+
+	builder, err := NewSignInstruction(privateKey, message)
+	require.NoError(t, err)
+	inst, err := builder.ValidateAndBuild()
+	require.NoError(t, err)
+	data, err := inst.Data()
+	require.NoError(t, err)
+
+	require.Equal(t, DataStart+PublicKeySize+SignatureSize+len(message), len(data))
+	require.Equal(t, []byte{
+		1, 0, // one signature, padding
+		48, 0, 0xff, 0xff, // signature
+		16, 0, 0xff, 0xff, // public key
+		112, 0, 5, 0, 0xff, 0xff, // message
+	}, data[:DataStart])
+	require.Equal(t, privateKey.PublicKey().Bytes(), data[16:48])
+	require.Equal(t, message, data[112:])
+	require.NoError(t, VerifyInstruction(data, nil))
+
+	decoded, err := solana.DecodeInstruction(ProgramID, nil, data)
+	require.NoError(t, err)
+	require.Equal(t, inst, decoded)
+
+	t.Run("tampered message", func(t *testing.T) {
+		tampered := append([]byte{}, data...)
+		tampered[len(tampered)-1] ^= 1
+		require.ErrorIs(t, VerifyInstruction(tampered, nil), ErrInvalidSignature)
+	})
+	t.Run("invalid public key", func(t *testing.T) {
+		tampered := append([]byte{}, data...)
+		// Not the y coordinate of a point of the curve.
+		copy(tampered[16:48], make([]byte, 32))
+		tampered[16] = 2
+		require.ErrorIs(t, VerifyInstruction(tampered, nil), ErrInvalidPublicKey)
+	})
+	t.Run("small order public key", func(t *testing.T) {
+		tampered := append([]byte{}, data...)
+		copy(tampered[16:48], make([]byte, 32))
+		tampered[16] = 1 // the identity
+		require.ErrorIs(t, VerifyInstruction(tampered, nil), ErrInvalidSignature)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		require.ErrorIs(t, VerifyInstruction(data[:1], nil), ErrInvalidInstructionDataSize)
+		require.ErrorIs(t, VerifyInstruction(data[:DataStart-1], nil), ErrInvalidInstructionDataSize)
+		require.ErrorIs(t, VerifyInstruction(data[:len(data)-1], nil), ErrInvalidDataOffsets)
+		require.ErrorIs(t, VerifyInstruction([]byte{0, 0, 1}, nil), ErrInvalidInstructionDataSize)
+		require.NoError(t, VerifyInstruction([]byte{0, 0}, nil))
+	})
+}
+
+func TestVerifyTransaction(t *testing.T) {
+	privateKey := solana.NewWallet().PrivateKey
+	other := solana.NewWallet().PrivateKey
+	attestation := []byte("price=42")
+	signature, err := privateKey.Sign(attestation)
+	require.NoError(t, err)
+	otherSignature, err := other.Sign([]byte("inline"))
+	require.NoError(t, err)
+
+	// The attestation lives in the data of the first instruction.
+	oracleData := append(append(append([]byte{}, privateKey.PublicKey().Bytes()...), signature[:]...), attestation...)
+	oracleProgramID := solana.NewWallet().PublicKey()
+	verify := NewVerifyInstruction(other.PublicKey(), otherSignature, []byte("inline")).
+		AddSignatureOffsets(SignatureOffsets{
+			PublicKeyOffset:           0,
+			PublicKeyInstructionIndex: 0,
+			SignatureOffset:           PublicKeySize,
+			SignatureInstructionIndex: 0,
+			MessageDataOffset:         PublicKeySize + SignatureSize,
+			MessageDataSize:           uint16(len(attestation)),
+			MessageInstructionIndex:   0,
+		})
+	inst, err := verify.ValidateAndBuild()
+	require.NoError(t, err)
+
+	build := func(oracleData []byte) *solana.Transaction {
+		tx, err := solana.NewTransaction(
+			[]solana.Instruction{
+				solana.NewInstruction(oracleProgramID, solana.AccountMetaSlice{}, oracleData),
+				inst,
+			},
+			solana.Hash{},
+			solana.TransactionPayer(privateKey.PublicKey()),
+		)
+		require.NoError(t, err)
+		return tx
+	}
+	require.NoError(t, VerifyTransaction(build(oracleData)))
+
+	tampered := append([]byte{}, oracleData...)
+	tampered[len(tampered)-1] ^= 1
+	require.ErrorIs(t, VerifyTransaction(build(tampered)), ErrInvalidSignature)
+	require.ErrorIs(t, VerifyTransaction(build(oracleData[:10])), ErrInvalidDataOffsets)
+}
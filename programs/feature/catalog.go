@@ -0,0 +1,107 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Catalog maps the IDs of known features to their descriptions.
+type Catalog struct {
+	mu           sync.RWMutex
+	descriptions map[solana.PublicKey]string
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{
+		descriptions: make(map[solana.PublicKey]string),
+	}
+}
+
+// DefaultCatalog is the catalog of RegisterFeature and of the statuses
+// without a catalog. It holds the runtime features known to agave; register
+// newer ones, or load them with LoadJSON from
+// `solana feature status --display-all --output json`.
+var DefaultCatalog = NewCatalog()
+
+func init() {
+	for _, entry := range knownFeatures {
+		DefaultCatalog.Register(entry.ID, entry.Description)
+	}
+}
+
+// RegisterFeature adds a feature to the default catalog.
+func RegisterFeature(id solana.PublicKey, description string) {
+	DefaultCatalog.Register(id, description)
+}
+
+// Register adds a feature, replacing its description if it is known.
+func (c *Catalog) Register(id solana.PublicKey, description string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.descriptions[id] = description
+}
+
+// Description returns the description of the feature, if it is known.
+func (c *Catalog) Description(id solana.PublicKey) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	description, ok := c.descriptions[id]
+	return description, ok
+}
+
+// IDs returns the IDs of the known features, sorted.
+func (c *Catalog) IDs() []solana.PublicKey {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(solana.PublicKeySlice, 0, len(c.descriptions))
+	for id := range c.descriptions {
+		out = append(out, id)
+	}
+	out.Sort()
+	return out
+}
+
+type catalogEntry struct {
+	ID          solana.PublicKey `json:"id"`
+	Description string           `json:"description"`
+}
+
+// LoadJSON registers the features of a JSON list of {"id", "description"}
+// objects, or of an object with such a list as "features", like the
+// output of `solana feature status --display-all --output json`.
+func (c *Catalog) LoadJSON(data []byte) error {
+	var entries []catalogEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var wrapper struct {
+			Features []catalogEntry `json:"features"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return fmt.Errorf("unable to parse feature catalog: %w", err)
+		}
+		entries = wrapper.Features
+	}
+	for _, entry := range entries {
+		if entry.ID.IsZero() {
+			return fmt.Errorf("feature without id: %q", entry.Description)
+		}
+		c.Register(entry.ID, entry.Description)
+	}
+	return nil
+}
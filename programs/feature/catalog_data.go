@@ -0,0 +1,229 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// knownFeatures are the runtime features of the FEATURE_NAMES of agave's
+// feature-set crate, in the order they were added.
+var knownFeatures = []catalogEntry{
+	{solana.MPK("GaBtBJvmS4Arjj5W1NmFcyvPjsHN38UGYDq2MDwbs9Qu"), "deprecate unused rewards sysvar"},
+	{solana.MPK("4RWNif6C2WCNiKVW7otP4G7dkmkHGyKQWRpuZ1pxKU5m"), "pico inflation"},
+	{solana.MPK("DT4n6ABDqs6w4bnfwrXT9rsprcPf6cdDga1egctaPkLC"), "full inflation on devnet and testnet"},
+	{solana.MPK("E5JiFDQCwyC6QfT9REFyMpfK2mHcmv1GUDySU1Ue7TYv"), "spl-token multisig fix"},
+	{solana.MPK("4kpdyrcj5jS47CZb2oJGfVxjYbsMm2Kx97gFyZrxxwXz"), "no overflow rent distribution"},
+	{solana.MPK("GTUMCZ8LTNxVfxdrw7ZsDFTxXb7TutYkzJnFwinpE6dg"), "filter stake_delegation_accounts #14062"},
+	{solana.MPK("D4jsDcXaqdW8tDAWn8H4R25Cdns2YwLneujSL1zvjW6R"), "require custodian to authorize withdrawer change for locked stake"},
+	{solana.MPK("BL99GYhdjjcv6ys22C9wPgn2aTVERDbPHHo4NbS3hgp7"), "spl-token self-transfer fix"},
+	{solana.MPK("GvDsGDkH5gyzwpDhxNixx8vtx1kwYHH13RiNAPw27zXb"), "warp timestamp again, adjust bounding to 25% fast 80% slow #15204"},
+	{solana.MPK("3ccR6QpxGYsAbWyfevEtBNGfWV4xBffxRj2tD6A9i39F"), "check initialized Vote data"},
+	{solana.MPK("6RvdSWHh8oh72Dp7wMTS2DBkf3fRPtChfNrAo3cZZoXJ"), "secp256k1_recover syscall"},
+	{solana.MPK("BrTR9hzw4WBGFP65AJMbpAo64DcA3U6jdPSga9fMV5cS"), "perform all checks for transfers of 0 lamports"},
+	{solana.MPK("HTW2pSyErTj4BV6KBM9NZ9VBUJVxt7sacNWcf76wtzb3"), "blake3 syscall"},
+	{solana.MPK("8kEuAshXLsgkUEdcFVLqrjCGGHVWFW99ZZpxvAzzMtBp"), "dedupe config program signers"},
+	{solana.MPK("EVW9B5xD9FFK7vw1SBARwMA4s5eRo5eKJdKpsBikzKBz"), "prohibit extra transaction signatures"},
+	{solana.MPK("BcWknVcgvonN8sL4HE4XFuEVgfcee5MwxWPAgP6ZV89X"), "vote/state program checked instructions #18345"},
+	{solana.MPK("BKCPBQQBZqggVnFso5nQ8rQ4RwwogYwjuUt9biBjxwNF"), "collect rent from accounts owned by sysvars"},
+	{solana.MPK("DhsYfRjxfnh2g7HKJYSzT79r74Afa1wbHkAgHndrA1oy"), "upgrade libsecp256k1 to v0.5.0"},
+	{solana.MPK("5ekBxc8itEnPv4NzGJtr8BVVQLNMQuLMNQQj7pHoLNZ9"), "transaction wide compute cap"},
+	{solana.MPK("FToKNBYyiF4ky9s8WsmLBXHCht17Ek7RXaLZGHzzQhJ1"), "spl-token set_authority fix"},
+	{solana.MPK("21AWDosvp3pBamFW91KB35pNoaoZVTM7ess8nr2nt53B"), "merge NonceError into SystemError"},
+	{solana.MPK("JAN1trEUEtZjgXYzNBYHU9DYd7GnThhXfFP7SzPXkPsG"), "disable fees sysvar"},
+	{solana.MPK("meRgp4ArRPhD3KtCY9c5yAf2med7mBLsjKTPeVUHqBL"), "allow merging active stakes with unmatched credits_observed #18985"},
+	{solana.MPK("zk1snxsc6Fh3wsGNbbHAJNHiJoYgF29mMnTSusGx5EJ"), "enable Zk Token proof program and syscalls"},
+	{solana.MPK("7rcw5UtqgDTBBv2EcynNfYckgdAaH1MAsCjKgXMkN7Ri"), "enable curve25519 syscalls"},
+	{solana.MPK("3KZZ6Ks1885aGBQ45fwRcPXVBCtzUvxhUTkwKMR41Tca"), "enable versioned transaction message processing"},
+	{solana.MPK("8aXvSuopd1PUj7UhehfXJRg6619RHp8ZvwTyyJHdUYsj"), "fail libsecp256k1_verify if count appears wrong"},
+	{solana.MPK("H3kBSaKdeiUsyHmeHqjJYNc27jesXZ6zWj3zWkowQbkV"), "fix owner for instructions sysvar"},
+	{solana.MPK("SAdVFw3RZvzbo6DvySbSdBnHN4gkzSTH9dSxesyKKPj"), "Enable advancing credits observed for activation epoch #19309"},
+	{solana.MPK("BUS12ciZ5gCoFafUHWW8qaFMMtwFQGVxjsDheWLdqBE2"), "Auto rewind stake's credits_observed if (accidental) vote recreation is detected #22546"},
+	{solana.MPK("3E3jV7v9VcdJL8iYZUMax9DiDno8j7EWUVbhm9RtShj2"), "demote program write locks to readonly, except when upgradeable loader present #19593 #20265"},
+	{solana.MPK("6ppMXNYLhVd7GcsZ5uV11wQEW7spppiMVfqQv5SXhDpX"), "enable builtin ed25519 signature verify program"},
+	{solana.MPK("DwScAzPUjuv65TMbDnFY7AgwmotzWy3xpEJMXM3hZFaB"), "enable sol_{set,get}_return_data syscall"},
+	{solana.MPK("EBeznQDjcPG8491sFsKZYBi5S5jTVXMpAKNDJMQPS2kq"), "reduce required payer balance for program deploys"},
+	{solana.MPK("6uaHcKPGUy4J7emLBgUTeufhJdiwhngW6a1R9B7c2ob9"), "enable sol_log_data syscall"},
+	{solana.MPK("HFpdDDNQjvcXnXKec697HDDsyk6tFoWS2o8fkxuhQZpL"), "remove delegations from stakes cache when inactive"},
+	{solana.MPK("75m6ysz33AfLA5DDEzWM1obBrnPQRSsdVQ2nRmc8Vuu1"), "support account data reallocation"},
+	{solana.MPK("4ApgRX3ud6p7LNMJmsuaAcZY5HWctGPr5obAsjB3A54d"), "prevent calling precompiles as programs"},
+	{solana.MPK("265hPS8k8xJ37ot82KEgjRunsUp5w4n4Q4VwwiN9i9ps"), "optimize epoch boundary updates"},
+	{solana.MPK("HTTgmruMYRZEntyL3EdCDdnS6e4D5wRq1FA7kQsb66qq"), "remove support for the native loader"},
+	{solana.MPK("C5fh68nJ7uyKAuYZg2x9sEQ5YrVf3dkW6oojNBSc3Jvo"), "send votes to the tpu vote port"},
+	{solana.MPK("CCu4boMmfLuqcmfTLPHQiUo22ZdUsXjgzPAURYaWt1Bw"), "Requestable heap frame size"},
+	{solana.MPK("2jXx2yDmGysmBKfKYNgLj2DQyAQv6mMk2BPh4eSbyB4H"), "deprecate fee calculator"},
+	{solana.MPK("4d5AKtxoh93Dwm1vHXUU3iRATuMndx1c431KgT2td52r"), "Add compute_budget_program"},
+	{solana.MPK("BiCU7M5w8ZCMykVSyhZ7Q3m2SWoR2qrEQ86ERcDX77ME"), "nonce must be writable"},
+	{solana.MPK("Ftok2jhqAqxUWEiCVRrfRs9DPppWP8cgTB7NQNKL88mS"), "spl-token v3.3.0 release"},
+	{solana.MPK("E8MkiWZNNPGU6n55jkGzyj8ghUmjCHRmDFdYYFYHxWhQ"), "leave nonce as is on success"},
+	{solana.MPK("9kdtFSrXHQg3hKkbXkQ6trJ3Ja1xpJ22CTFSNAciEwmL"), "fail instructions which have native_loader as program_id directly"},
+	{solana.MPK("36PRUK2Dz6HWYdG9SpjeAsF5F3KxnFCakA2BZMbtMhSb"), "use correct check for nonoverlapping regions in memcpy syscall"},
+	{solana.MPK("7txXZZD6Um59YoLMF7XUNimbMjsqsWhc7g2EniiTrmp1"), "fail vote withdraw instructions which leave the account non-rent-exempt"},
+	{solana.MPK("EMX9Q7TVFAmQ9V1CggAkhMzhXSg8ECp7fHrWQX2G1chf"), "evict invalid stakes cache entries on epoch boundaries"},
+	{solana.MPK("Ff8b1fBeB86q8cjq47ZhsQLgv5EkHu3G1C99zjUfAzrq"), "enable direct vote state update"},
+	{solana.MPK("capRxUrBjNkkCpjrJxPGfPaWijB7q3JoDfsWXAnt46r"), "cap the accounts data len"},
+	{solana.MPK("CBkDroRDqm8HwHe6ak9cguPjUomrASEkfmxEaZ5CNNxz"), "enforce max number of locked accounts per transaction"},
+	{solana.MPK("BkFDxiJQWZXGTZaJQxH7wVEHkAmwCgSEVkrvswFfRJPD"), "require all new transaction accounts with data to be rent-exempt"},
+	{solana.MPK("3gtZPqvPpsbXZVCx6hceMfWxtsmrjMzmg8C7PLKSxS2d"), "filter vote slots older than the slot hashes history"},
+	{solana.MPK("2h63t332mGCCsWK2nqqqHhN4U9ayyqhLVFvczznHDoTZ"), "update syscall base costs"},
+	{solana.MPK("437r62HoAdUb63amq3D7ENnBLDhHT2xY8eFkLJYVKK4x"), "enable the deactivate delinquent stake instruction #23932"},
+	{solana.MPK("AVZS3ZsN4gi6Rkx2QUibYuSJG3S6QHib7xCYhG6vGJxU"), "vote account withdraw authority may change the authorized voter #22521"},
+	{solana.MPK("FaTa4SpiaSNH44PGC4z8bnGVTkSRYaWvrBs3KTu8XQQq"), "SPL Associated Token Account Program release version 1.0.4, tied to token 3.3.0 #22648"},
+	{solana.MPK("ALBk3EWdeAg2WAGf6GPDUf1nynyNqCdEVmgouG7rpuCj"), "fail vote account withdraw to 0 unless account earned 0 credits in last completed epoch"},
+	{solana.MPK("CFK1hRCNy8JJuAAY8Pb2GjLFNdCThS2qwZNe3izzBMgn"), "add add_get_processed_sibling_instruction_syscall"},
+	{solana.MPK("Vo5siZ442SaZBKPXNocthiXysNviW4UYPwRFggmbgAp"), "fixes Bank::transaction_count to include all committed transactions, not just successful ones"},
+	{solana.MPK("3XgNukcZWf9o3HdA3fpJbm94XFc4qpvTXc8h1wxYwiPi"), "disable ldabs* and ldind* SBF instructions"},
+	{solana.MPK("4yuaYAj2jGMGTh1sSmi4G2eFscsDq8qjugJXZoBN6YEa"), "disable reporting of unresolved SBF symbols at runtime"},
+	{solana.MPK("3aJdcZqxoLpSBxgeYGjPwaYS1zzcByxUDqJkbzWAH1Zb"), "move the CPI stack overflow check to the end of push"},
+	{solana.MPK("HyrbKftCdJ5CrUfEti6x26Cj7rZLNe32weugk7tLcWb8"), "syscalls use saturated math"},
+	{solana.MPK("nWBqjr3gpETbiaVj3CBJ3HFC5TMdnJDGt21hnvSTvVZ"), "check physical overlapping regions"},
+	{solana.MPK("7g9EUwj4j7CS21Yx1wvgWLjSZeh5aPq8x9kpoPwXM8n8"), "limit secp256k1 recovery id"},
+	{solana.MPK("GmC19j9qLn2RFk5NduX6QXaDhVpGncVVBzyM8e9WMz2F"), "check size when translating slices"},
+	{solana.MPK("FQnc7U4koHqWgRvFaBJjZnV8VPg6L6wWK33yJeDp4yvV"), "stake split instruction uses rent sysvar"},
+	{solana.MPK("St8k9dVXP97xT6faW24YmRSYConLbhsMJA4TJTBLmMT"), "add GetMinimumDelegation instruction to stake program"},
+	{solana.MPK("8199Q2gMD2kwgfopK5qqVWuDbegLgpuFUFHCcUJQDN8b"), "error on bpf function hash collisions"},
+	{solana.MPK("3NKRSwpySNwD3TvP5pHnRmkAQRsdkXWRr1WaQh8p4PWX"), "Reject bpf callx r10 instructions"},
+	{solana.MPK("4Di3y24QFLt5QEUPZtbnjyfQKfm6ZMTfa6Dw1psfoMKU"), "drop redundant turbine path"},
+	{solana.MPK("7GUcYgq4tVtaqNCKT3dho9r4665Qp5TxCZ27Qgjx3829"), "Executables incur CPI data costs"},
+	{solana.MPK("6iyggb5MTcsvdcugX7bEKbHV8c6jdLbpHwkncrgLMhfo"), "stop adding hashes for skipped slots to recent blockhashes"},
+	{solana.MPK("28s7i3htzhahXQKqmS2ExzbEoUypg9krwvtK2M9UWXh9"), "update rewards from cached accounts"},
+	{solana.MPK("Ftok4njE8b7tDffYkC5bAbCaQv5sL6jispYrprzatUwN"), "SPL Token Program version 3.4.0 release #24740"},
+	{solana.MPK("FaTa17gVKoqbh38HcfiQonPsAaQViyDCCSg71AubYZw8"), "SPL Associated Token Account Program version 1.1.0 release #24741"},
+	{solana.MPK("J2QdYx8crLbTVK8nur1jeLsmc3krDbfjoxoea2V1Uy5Q"), "Default max tx-wide compute units calculated per-instruction"},
+	{solana.MPK("sTKz343FM8mqtyGvYWvbLpTThw3ixRM4Xk8QvZ985mw"), "Allow zero-lamport undelegated amount for initialized stakes #24670"},
+	{solana.MPK("8FdwgyHFEjhAdjWfV2vfqk7wA1g9X3fQpKH7SBpEv3kC"), "require static program ids in versioned transactions"},
+	{solana.MPK("9onWzzvCzNC2jfhxxeqRgs5q7nFAAKpCUvkj6T6GJK9i"), "Raise minimum stake delegation to 1.0 SOL #24357"},
+	{solana.MPK("G6ANXD6ptCSyNd9znZm7j4dEczAJCfx7Cy43oBx3rKHJ"), "stakes must be at least the minimum delegation to earn rewards"},
+	{solana.MPK("98std1NSHqXi9WYvFShfVepRdCoq1qvsp8fsR2XZtG8g"), "add compute budget ix for setting a compute unit price"},
+	{solana.MPK("79HWsX9rpnnJBPcdNURVqygpMAfxdrAirzAGAVmf92im"), "disable new deployments of deprecated sol_alloc_free_ syscall"},
+	{solana.MPK("2R72wpcQ7qV7aTJWUumdn8u5wmmTyXbK7qzEy7YSAgyY"), "include account index in rent tx error #25190"},
+	{solana.MPK("Ds87KVeqhbv7Jw8W6avsS1mqz3Mw5J3pRTpPoDQ2QdiJ"), "add shred-type to shred seed #25556"},
+	{solana.MPK("3BX6SBeEBibHaVQXywdkcgyUk6evfYZkHdztXiDtEpFS"), "warp timestamp again, adjust bounding to 150% slow #25666"},
+	{solana.MPK("Gea3ZkK2N4pHuVZVxWcnAtS6UEDdyumdYt4pFcKjA3ar"), "separate durable nonce and blockhash domains #25744"},
+	{solana.MPK("4EJQtF2pkRyawwcTVfQutzq4Sa5hRhibF6QAK1QXhtEX"), "enable durable nonce #25744"},
+	{solana.MPK("CveezY6FDLVBToHDcvJRmtMouqzsmj4UXYh5ths5G5Uv"), "Calculate vote credits for VoteStateUpdate per vote dequeue to match credit awards for Vote instruction"},
+	{solana.MPK("DpJREPyuMZ5nDfU6H3WTqSqUFSXAfw8u7xqmWtEwJDcP"), "quick bail on panic"},
+	{solana.MPK("HxrEu1gXuH7iD3Puua1ohd5n4iUKJyFNtNxk9DVJkvgr"), "nonce must be authorized"},
+	{solana.MPK("3u3Er5Vc2jVcwz4xr2GJeSAXT3fAj6ADHZ4BJMZiScFd"), "durable nonces must be advanceable"},
+	{solana.MPK("6tRxEYKuy2L5nnv5bgn7iT28MxUbYxp5h7F3Ncf1exrT"), "An instruction you can use to change a vote accounts authority when the current authority is a derived key #25860"},
+	{solana.MPK("HH3MUYReL2BvqqA3oEcAa7txju5GY6G4nxJ51zvsEjEZ"), "preserve rent epoch for rent exempt accounts #26479"},
+	{solana.MPK("8Zs9W7D9MpSEtUWSQdGniZk2cNmV22y6FLJwCx53asme"), "enable bpf upgradeable loader ExtendProgram instruction #25234"},
+	{solana.MPK("7Vced912WrRnfjaiKRiNBcbuFw7RrnLv3E3z95Y4GTNc"), "enable early verification of account modifications #25899"},
+	{solana.MPK("CGB2jM8pwZkeeiXQ66kBMyBR6Np61mggL7XUsmLjVcrw"), "skip rewriting rent exempt accounts during rent collection #26491"},
+	{solana.MPK("812kqX67odAp5NFwM8D2N24cku7WTm9CHUTFUXaDkWPn"), "prevent crediting rent paying accounts #26606"},
+	{solana.MPK("9k5ijzTbYPtjzu8wj2ErH9v45xecHzQ1x4PMYMMxFgdM"), "enforce max number of accounts per bpf program instruction #26628"},
+	{solana.MPK("GDH5TVdbTPUpRnXaRyQqiKUa7uZAbZ28Q2N9bhbKoMLm"), "loosen cpi size restrictions #26641"},
+	{solana.MPK("8sKQrMQoUHtQSUP83SPG4ta2JDjSAiWs7t5aJ9uEd6To"), "use default units per instruction in fee calculation #26785"},
+	{solana.MPK("86HpNqzutEZwLcPxS6EHDcMNYWk6ikhteg9un7Y2PBKE"), "Compact vote state updates to lower block size"},
+	{solana.MPK("25vqsfjk7Nv1prsQJmA4Xu1bN61s8LXCBGUPp8Rfy1UF"), "only hash accounts in incremental snapshot during incremental snapshot creation #26799"},
+	{solana.MPK("B9cdB55u4jQsDNsdTK525yE9dmSc5Ga7YBaBrDFvEhM9"), "disable setting is_executable and_rent_epoch in CPI #26987"},
+	{solana.MPK("CpkdQmspsaZZ8FVAouQTtTWZkc8eeQ7V3uj7dWz543rZ"), "on bank load account, do not try to fix up rent_epoch #28541"},
+	{solana.MPK("SVn36yVApPLYsa8koK3qUcy14zXDnqkNYWyUh1f4oK1"), "ignore slot when calculating an account hash #28420"},
+	{solana.MPK("5wAGiy15X1Jb2hkHnPDCM8oB9V42VNA9ftNVFK84dEgv"), "set rent epoch to Epoch::MAX for rent-exempt accounts #28683"},
+	{solana.MPK("FKAcEvNgSY79RpqsPNUV5gDyumopH4cEHqUxyfm8b8Ap"), "relax authority signer check for lookup table creation #27205"},
+	{solana.MPK("EYVpEP7uzH1CoXzbD6PubjqCPRNGmFVNbUMtvVP7h53T"), "stop the search in get_processed_sibling_instruction when the parent instruction is reached #27289"},
+	{solana.MPK("G74BkWBzmsByZ1kxHy44H3wjwp5hp7JbrGRuDpco22tY"), "fix root in vote state updates #27361"},
+	{solana.MPK("9gxu85LYRAcZL38We8MYJ4A9AwgBBPtVBAqebMcT1241"), "cap accounts data allocations per transaction #27375"},
+	{solana.MPK("5GpmAKxaGsWWbPp4bNXFLJxZVvG92ctxf7jQnzTQjF3n"), "enable epoch accounts hash calculation #27539"},
+	{solana.MPK("EfhYd3SafzGT472tYQDUc4dPd2xdEfKs5fwkowUgVt4W"), "remove support for RequestUnitsDeprecated instruction #27500"},
+	{solana.MPK("DTVTkmw3JSofd8CJVJte8PXEbxNQ2yZijvVr3pe2APPj"), "on accounts hash calculation, do not try to rehash accounts #28934"},
+	{solana.MPK("9LZdXeKGeBV6hRLdxS1rHbHoEUsKqesCC2ZAPTPKJAbK"), "increase tx account lock limit to 128 #27241"},
+	{solana.MPK("GQALDaC48fEhZGWRj9iL5Q889emJKcj3aCvHF7VCbbF4"), "limit max instruction trace length #27939"},
+	{solana.MPK("3uRVPBpyEJRo1emLCrq38eLRFGcu6uKSpUXqGvU8T7SZ"), "check syscall outputs do_not overlap #28600"},
+	{solana.MPK("5x3825XS7M2A3Ekbn5VGGkvFoAg5qrRWkTrY4bARP1GL"), "enable bpf upgradeable loader SetAuthorityChecked instruction #28424"},
+	{solana.MPK("A16q37opZdQMCbe5qJ6xpBB9usykfv8jZaMkxvZQi4GJ"), "add alt_bn128 syscalls #27961"},
+	{solana.MPK("J4HFT8usBxpcF63y46t1upYobJgChmKyZPm5uTBRg25Z"), "enable program redeployment cooldown #29135"},
+	{solana.MPK("noRuG2kzACwgaY7TVmLRnUNPLKNVQE1fb7X55YWBehp"), "enable commission updates only allowed in first half of epoch #29362"},
+	{solana.MPK("74CoWuBmt3rUVUrCb2JiSTvh6nXyBWUsK4SaMj3CtE3T"), "cpi ignore serialized_len_ptr #29592"},
+	{solana.MPK("3uFHb9oKdGfgZGJK9EHaAXN4USvnQtAFC13Fh5gGFS5B"), "Update desired hashes per tick on epoch boundary"},
+	{solana.MPK("EBq48m8irRKuE7ZnMTLvLg2UuGSqhe8s8oMqnmja1fJw"), "add big_mod_exp syscall #28503"},
+	{solana.MPK("DdLwVYuvDz26JohmgSbA7mjpJFgX5zP2dkp8qsF2C33V"), "cap transaction accounts data size up to a limit #27839"},
+	{solana.MPK("A8xyMHZovGXFkorFqEmVH2PKGLiBip5JD7jt4zsUWo4H"), "Remove congestion_multiplier from calculating fees #29881"},
+	{solana.MPK("Hr1nUA9b7NJ6eChS26o7Vi8gYYDDwWD3YeBfzJkTbU86"), "Enable transaction to request heap frame using compute budget instruction #30076"},
+	{solana.MPK("Fab5oP3DmsLYCiQZXdjyqT3ukFFPrsmqhXU4WU1AWVVF"), "prevent recipients of rent rewards from ending in rent-paying state #30151"},
+	{solana.MPK("GmuBvtFb2aHfSfMXpuFeWZGHyDeCLPS79s48fmCWCfM5"), "delay visibility of program upgrades #30085"},
+	{solana.MPK("2ry7ygxiYURULZCrypHhveanvP5tzZ4toRwVp89oCNSj"), "apply cost tracker to blocks during replay #29595"},
+	{solana.MPK("G6vbf1UBok8MWb8m25ex86aoQHeKTzDKzuZADHkShqm6"), "add compute budget instruction for setting account data size per transaction #30366"},
+	{solana.MPK("Cdkc8PPTeTNUPoZEfCY5AyetUrEdkZtNPMgz58nqyaHD"), "switch to new ELF parser #30497"},
+	{solana.MPK("CE2et8pqgyQMP2mQRg3CgvX8nJBKUArMu3wfiQiQKY1y"), "round up heap size when calculating heap cost #30679"},
+	{solana.MPK("2HmTkCj9tXuPE4ueHzdD7jPeMf9JGCoZh5AsyoATiWEe"), "stop incorrectly throwing IncorrectProgramId in bpf_loader #30747"},
+	{solana.MPK("EaQpmC6GtRssaZ3PCUM5YksGqUdMLeZ46BQXYtHYakDS"), "include transaction loaded accounts data size in base fee calculation #30657"},
+	{solana.MPK("8pgXCMNXC8qyEFypuwpXyRxLXZdpM4Qo72gJ6k87A6wL"), "Native program should consume compute units #30620"},
+	{solana.MPK("5ZCcFAzJ1zsFKe1KSZa9K92jhx7gkcKj97ci2DBo1vwj"), "Simplify checks performed for writable upgradeable program accounts #30559"},
+	{solana.MPK("16FMCmgLzCNNz6eTwGanbyN2ZxvTBSLuQ6DZhgeMshg"), "Stop truncating strings in syscalls #31029"},
+	{solana.MPK("Bj2jmUsM2iRhfdLLDSTkhM5UQRQvQHm57HSmPibPtEyu"), "Return InsufficientDelegation instead of InsufficientFunds or InsufficientStake where applicable #31206"},
+	{solana.MPK("7axKe5BTYBDD87ftzWbk5DfzWMGyRvqmWTduuo22Yaqy"), "replace Lockout with LandedVote (including vote latency) in vote state #31264"},
+	{solana.MPK("5Pecy6ie6XGm22pc9d4P9W5c31BugcFBuy6hsP2zkETv"), "checked arithmetic in fee validation #31273"},
+	{solana.MPK("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR"), "enable new sysvar last_restart_slot"},
+	{solana.MPK("GwtDQBghCTBgmX2cpEGNPxTEBUTQRaDMGTr5qychdGMj"), "reduce stake warmup cooldown from 25% to 9%"},
+	{solana.MPK("BTWmtJC8U5ZLMbBUUA1k6As62sYjPEjAiNAT55xYGdJU"), "revise turbine epoch stakes"},
+	{solana.MPK("FL9RsQA6TVUoh5xJQ9d936RHSebA1NLQqe3Zv9sXZRpr"), "Enable Poseidon syscall"},
+	{solana.MPK("tvcF6b1TRz353zKuhBjinZkKzjmihXmBAHJdjNYw1sQ"), "use timeliness of votes in determining credits to award"},
+	{solana.MPK("5TuppMutoyzhUSfuYdhgzD47F92GL1g89KpCZQKqedxP"), "enable the remaining_compute_units syscall"},
+	{solana.MPK("D2aip4BBr8NPWtU9vLrwrBvbuaQ8w1zV38zFLxx4pfBV"), "Require stake split destination account to be rent exempt"},
+	{solana.MPK("Ffswd3egL3tccB6Rv3XY6oqfdzn913vUcjCSnpvCKpfx"), "better error codes for tx lamport check #33353"},
+	{solana.MPK("prpFrMtgNmzaNzkPJg9o753fVvbHKqNrNTm76foJ2wm"), "validate fee collector account #33888"},
+	{solana.MPK("CJzY83ggJHqPGDq8VisV3U91jDJLuEaALZooBrXtnnLU"), "Disable rent fees collection #33945"},
+	{solana.MPK("zkNLP7EQALfC1TYeB3biDU7akDckj8iPkvh9y2Mt2K3"), "enable Zk Token proof program transfer with fee"},
+	{solana.MPK("GV49KKQdBNaiv2pgqhS2Dy3GWYJGXMTVYbYkdk91orRy"), "drops legacy shreds #34328"},
+	{solana.MPK("decoMktMcnmiq6t3u7g5BfgcQu91nKZr6RvMYf9z1Jb"), "Allow commission decrease at any time in epoch #33843"},
+	{solana.MPK("8U4skmMVnF6k2kMvrWbQuRUT3qQSiTYpSjqmhmgfthZu"), "add new unwritable reserved accounts #34899"},
+	{solana.MPK("6YsBCejwK96GZCkJ6mkZ4b68oP63z2PLoQmWjC7ggTqZ"), "consume duplicate proofs from blockstore in consensus #34372"},
+	{solana.MPK("dupPajaLy2SSn8ko42aZz4mHANDNrLe8Nw8VQgFecLa"), "generate duplicate proofs for index and erasure conflicts #34360"},
+	{solana.MPK("mrkPjRg79B2oK2ZLgd7S3AfEJaX9B6gAF3H9aEykRUS"), "generate duplicate proofs for merkle root conflicts #34270"},
+	{solana.MPK("7WeS1vfPRgeeoXArLh7879YcB9mgE9ktjPDtajXeWfXn"), "disable the deprecated BPF loader instructions #35164"},
+	{solana.MPK("zkiTNuzBKxrCLMKehzuQeKZyLtX2yvFcEKMML8nExU8"), "Enable zk token proof program to read proof from accounts instead of instruction data #34750"},
+	{solana.MPK("wLckV1a64ngtcKPRGU4S4grVTestXjmNjxBjaKZrAcn"), "cost model uses number of requested write locks #34819"},
+	{solana.MPK("FNKCMBzYUdjhHyPdsKG2LSmdzH8TCHXn3ytj8RNBS4nG"), "enable gossip duplicate proof ingestion #32963"},
+	{solana.MPK("chaie9S2zVfuxJKNRGkyTDokLwWxx6kD2ZLsqQHaDD8"), "generate duplicate proofs for chained merkle root conflicts"},
+	{solana.MPK("7uZBkJXJ1HkuP6R3MJfZs7mLwymBcDbKdqbF51ZWLier"), "Enable chained Merkle shreds #34916"},
+	{solana.MPK("BtVN7YjDzNE6Dk7kTT7YTDgMNUZTNgiSJgsdzAeTg2jF"), "Removing unwanted rounding in fee calculation #34982"},
+	{solana.MPK("tSynMCspg4xFiCj1v3TDb4c7crMR5tSBhLz4sF7rrNA"), "Enable tower sync vote instruction"},
+	{solana.MPK("6Uf8S75PVh91MYgPQSHnjRAPQq6an5BDv9vomrCwDqLe"), "Deprecate unused legacy vote tx plumbing"},
+	{solana.MPK("3opE3EzAKnUftUDURkzMgwpNgimBAypW1mNDYH4x4Zg7"), "Reward full priority fee to validators #34731"},
+	{solana.MPK("CLCoTADvV64PSrnR6QXty6Fwrt9Xc6EdxSJE4wLRePjq"), "Enable syscall for fetching Sysvar bytes #615"},
+	{solana.MPK("FuS3FPfJDKSNot99ECLXtp3rueq36hMNStJkPJwWodLh"), "Abort when elliptic curve syscalls invoked on invalid curve id SIMD-0137"},
+	{solana.MPK("4eohviozzEeivk1y9UbrnekbAFMDQyJz5JjA9Y6gyvky"), "Migrate Feature Gate program to Core BPF (programify) #1003"},
+	{solana.MPK("ffecLRhhakKSGhMuc6Fz2Lnfq4uT9q3iu9ZsNaPLxPc"), "vote only full fec sets"},
+	{solana.MPK("2Fr57nzzkLYXW695UdDxDeR5fhnZWSttZeZYemrnpGFV"), "Migrate Config program to Core BPF #1378"},
+	{solana.MPK("7mScTYkJXsbdrcwTQRs7oeCSXoJm4WjzBsRyf8bCU3Np"), "Enable syscall: sol_get_epoch_stake #884"},
+	{solana.MPK("C97eKZygrkU4JxJsZdjgbUY7iQR7rKTr4NyDWo2E5pRm"), "Migrate Address Lookup Table program to Core BPF #1651"},
+	{solana.MPK("zkhiy5oLowR7HY4zogXjCjeMXyruLqBwSWH21qcFtnv"), "Enable the zk elgamal proof program"},
+	{solana.MPK("BZ5g4hRbu5hLQQBdPyo2z9icGyJ8Khiyj3QS6dhWijTb"), "Verify retransmitter signature #1840"},
+	{solana.MPK("7bTK6Jis8Xpfrs8ZoUfiMDPazTcdPcTWheZFJTA5Z6X4"), "Enable MoveStake and MoveLamports stake program instructions #1610"},
+	{solana.MPK("ed9tNscbWLYBooxWA7FE2B5KHWs8A6sxfY8EzezEcoo"), "Use strict verification in ed25519 precompile SIMD-0152"},
+	{solana.MPK("RfEcA95xnhuwooVAhUUksEJLZBF7xKCLuqrJoqk4Zph"), "vote only on retransmitter signed fec sets"},
+	{solana.MPK("9ypxGLzkMxi89eDerRKXWDXe44UY2z4hBig4mDhNq5Dp"), "SIMD-0159: Move precompile verification into SVM"},
+	{solana.MPK("PaymEPK2oqwT9TXAVfadjztH2H6KfLEB9Hhd5Q5frvP"), "Enable fees for some additional transaction failures SIMD-0082"},
+	{solana.MPK("depVvnQ2UysGrhwdiwU42tCadZL8GcBb1i2GYhMopQv"), "Deprecate legacy vote instructions"},
+	{solana.MPK("FfgtauHUWKeXTzjXkua9Px4tNGBFHKZ9WaigM5VbbzFx"), "Remove checks of accounts is_executable flag SIMD-0162"},
+	{solana.MPK("HcW8ZjBezYYgvcbxNJwqv1t484Y2556qJsfNDWvJGZRH"), "Allow 32 bit values in the CPI caller-address check SIMD-0164"},
+	{solana.MPK("EQUMpNFr7Nacb1sva56xn1aLfBxppEoSBH8RRVdkcD1x"), "Disable account loader special case #3513"},
+	{solana.MPK("sryYyFwxzJop1Bh9XpyiVWjZP4nfHExTC8dAgZGRpnH"), "Enable secp256r1 precompile SIMD-0075"},
+	{solana.MPK("LtHaSHHsUge7EWTPVrmpuexKz6uVHZXZL6cgJa7W7Zn"), "enables accounts lattice hash SIMD-0215"},
+	{solana.MPK("LTsNAP8h1voEVVToMNBNqoiNQex4aqfUrbFhRH3mSQ2"), "snapshots use lattice-based accounts hash SIMD-0220"},
+	{solana.MPK("LTdLt9Ycbyoipz5fLysCi1NnDnASsZfmJLJXts5ZxZz"), "remove accounts delta hash SIMD-0223"},
+	{solana.MPK("6M4oQ6eXneVhtLoiAr4yRYQY43eVLjrKbiDZDJc892yk"), "Migrate Stake program to Core BPF SIMD-0196 #3655"},
+	{solana.MPK("B7H2caeia4ZFcpE3QcgMqbiWiBtWrdBRBSJ1DY6Ktxbq"), "Deplete compute meter for vm errors SIMD-0182 #3993"},
+	{solana.MPK("C9oAhLxDBm3ssWtJx1yBGzPY55r2rArHmN1pbQn6HogH"), "Reserve minimal CUs for builtin instructions SIMD-170 #2562"},
+	{solana.MPK("5oMCU3JPaFLr8Zr4ct7yFA7jdk6Mw1RmB8K4u9ZbS42z"), "Raise block limit to 50M SIMD-0207"},
+	{solana.MPK("5KLGJSASDVxKPjLCDWNtnABLpZjsQSrYZ8HKwcEdAMC8"), "drops unchained Merkle shreds #2149"},
+	{solana.MPK("ENTRYnPAoT5Swwx73YDGzMp3XnNH1kxacyvLosRHza1i"), "Allow intrabatch account locks SIMD-0083"},
+	{solana.MPK("2B2SBNbUcr438LtGXNcJNBP2GBSxjx81F945SdSkUSfC"), "Disable partitioned rent collection SIMD-0175 #4562"},
+	{solana.MPK("5JsG4NWH8Jbrqdd8uL6BNwnyZK3dQSoieRXG5vmofj9y"), "Enable vote address leader schedule SIMD-0180 #4573"},
+	{solana.MPK("7VVhpg5oAjAmnmz1zCcSHb2Z9ecZB2FQqpnEwReka9Zm"), "Require static nonce account SIMD-0242 #5067"},
+	{solana.MPK("6oMCUgfY6BzZ6jwB681J6ju5Bh6CjVXbd7NeWYqiXBSu"), "Raise block limit to 60M SIMD-0256"},
+	{solana.MPK("RENtePQcDLrAbxAsP3k8dwVcnNYQ466hi2uKvALjnXx"), "SIMD-0267: Sets rent_epoch to a constant in the VM"},
+}
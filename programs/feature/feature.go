@@ -0,0 +1,96 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package feature decodes the accounts of the Feature program, which gate
+// the runtime features of a cluster, and queries their activation status.
+package feature
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var ProgramID solana.PublicKey = solana.FeatureProgramID
+
+func SetProgramID(pubkey solana.PublicKey) {
+	ProgramID = pubkey
+	registerAccountDecoders(ProgramID)
+}
+
+const ProgramName = "Feature"
+
+func init() {
+	registerAccountDecoders(ProgramID)
+}
+
+// FEATURE_SIZE is the size of the data of a feature account.
+const FEATURE_SIZE = 9
+
+// Feature is the data of a feature account, at the ID of the feature.
+// A feature is activated by creating its account, owned by the program,
+// with an unset ActivatedAt; the runtime sets it at the start of the
+// next epoch.
+type Feature struct {
+	// The slot at which the feature was activated, if it was.
+	ActivatedAt *uint64
+}
+
+func (feature Feature) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteOption(feature.ActivatedAt != nil); err != nil {
+		return err
+	}
+	if feature.ActivatedAt == nil {
+		return nil
+	}
+	return encoder.WriteUint64(*feature.ActivatedAt, bin.LE)
+}
+
+func (feature *Feature) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	isSet, err := decoder.ReadOption()
+	if err != nil {
+		return err
+	}
+	if !isSet {
+		feature.ActivatedAt = nil
+		return nil
+	}
+	slot, err := decoder.ReadUint64(bin.LE)
+	if err != nil {
+		return err
+	}
+	feature.ActivatedAt = &slot
+	return nil
+}
+
+func DecodeFeature(data []byte) (*Feature, error) {
+	feature := new(Feature)
+	if err := bin.NewBinDecoder(data).Decode(feature); err != nil {
+		return nil, fmt.Errorf("unable to decode feature: %w", err)
+	}
+	return feature, nil
+}
+
+func registerAccountDecoders(programID solana.PublicKey) {
+	solana.RegisterAccountDecoder(programID, solana.AccountSizeMatcher(FEATURE_SIZE), registryDecodeAccount)
+}
+
+func registryDecodeAccount(data []byte) (interface{}, error) {
+	feature, err := DecodeFeature(data)
+	if err != nil {
+		return nil, err
+	}
+	return feature, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"context"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/rpctest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeFeature(t *testing.T) {
+	decoded, err := solana.DecodeAccount(ProgramID, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, &Feature{}, decoded)
+
+	data := []byte{1, 0x40, 0xe2, 0x01, 0, 0, 0, 0, 0}
+	feature, err := DecodeFeature(data)
+	require.NoError(t, err)
+	require.Equal(t, uint64(123456), *feature.ActivatedAt)
+
+	encoded, err := bin.MarshalBin(feature)
+	require.NoError(t, err)
+	require.Equal(t, data, encoded)
+
+	_, err = DecodeFeature([]byte{1, 0})
+	require.Error(t, err)
+}
+
+func TestCatalog_LoadJSON(t *testing.T) {
+	first := solana.MPK("7txXZZD6Um59YoLMF7XUNimbMjsqsWhc7g2EniiTrmp1")
+	second := solana.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+
+	catalog := NewCatalog()
+	require.NoError(t, catalog.LoadJSON([]byte(`[{"id":"7txXZZD6Um59YoLMF7XUNimbMjsqsWhc7g2EniiTrmp1","description":"first"}]`)))
+	require.NoError(t, catalog.LoadJSON([]byte(`{"features":[{"id":"2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc","description":"second","status":"active"}]}`)))
+	require.Equal(t, []solana.PublicKey{second, first}, catalog.IDs())
+
+	description, ok := catalog.Description(first)
+	require.True(t, ok)
+	require.Equal(t, "first", description)
+
+	require.Error(t, catalog.LoadJSON([]byte(`[{"description":"no id"}]`)))
+	require.Error(t, catalog.LoadJSON([]byte(`"features"`)))
+}
+
+func TestDefaultCatalog(t *testing.T) {
+	description, ok := DefaultCatalog.Description(solana.MPK("3KZZ6Ks1885aGBQ45fwRcPXVBCtzUvxhUTkwKMR41Tca"))
+	require.True(t, ok)
+	require.Equal(t, "enable versioned transaction message processing", description)
+
+	description, ok = DefaultCatalog.Description(solana.MPK("4d5AKtxoh93Dwm1vHXUU3iRATuMndx1c431KgT2td52r"))
+	require.True(t, ok)
+	require.Equal(t, "Add compute_budget_program", description)
+
+	require.Len(t, DefaultCatalog.IDs(), len(knownFeatures))
+}
+
+func TestFetchStatuses(t *testing.T) {
+	srv := rpctest.NewServer(nil)
+	defer srv.Close()
+
+	active := solana.MPK("7txXZZD6Um59YoLMF7XUNimbMjsqsWhc7g2EniiTrmp1")
+	pending := solana.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	inactive := solana.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	unowned := solana.MPK("3or4uF7ZyuQW5GGmcmdXDJasNiSZUURF2az1UrRPYQTg")
+
+	slot := uint64(42)
+	for id, feature := range map[solana.PublicKey]Feature{active: {ActivatedAt: &slot}, pending: {}} {
+		data, err := bin.MarshalBin(feature)
+		require.NoError(t, err)
+		srv.SetAccount(id, &rpctest.Account{Lamports: 1, Owner: ProgramID, Data: data})
+	}
+	srv.SetAccount(unowned, &rpctest.Account{Lamports: 1, Owner: solana.SystemProgramID, Data: make([]byte, FEATURE_SIZE)})
+
+	catalog := NewCatalog()
+	catalog.Register(active, "active feature")
+
+	client := rpc.New(srv.URL())
+	statuses, err := FetchStatusesWithCatalog(context.Background(), client, catalog, active, pending, inactive, unowned)
+	require.NoError(t, err)
+	require.Equal(t, []*Status{
+		{ID: active, Description: "active feature", State: StateActive, ActivatedAt: &slot},
+		{ID: pending, State: StatePending},
+		{ID: inactive, State: StateInactive},
+		{ID: unowned, State: StateInactive},
+	}, statuses)
+
+	// Without IDs, the features of the catalog.
+	statuses, err = FetchStatusesWithCatalog(context.Background(), client, catalog)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, active, statuses[0].ID)
+	_, err = FetchStatusesWithCatalog(context.Background(), client, NewCatalog())
+	require.ErrorIs(t, err, ErrNoFeatures)
+
+	isActive, err := IsActive(context.Background(), client, pending)
+	require.NoError(t, err)
+	require.False(t, isActive)
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// The maximum number of accounts of a getMultipleAccounts request.
+const maxAccountsPerRequest = 100
+
+type State int
+
+const (
+	// The feature account doesn't exist.
+	StateInactive State = iota
+	// The feature account exists; the feature activates at the next epoch.
+	StatePending
+	// The feature is activated.
+	StateActive
+)
+
+func (state State) String() string {
+	switch state {
+	case StateInactive:
+		return "inactive"
+	case StatePending:
+		return "pending"
+	case StateActive:
+		return "active"
+	default:
+		return fmt.Sprintf("State(%d)", int(state))
+	}
+}
+
+// Status is the activation status of a feature on a cluster.
+type Status struct {
+	ID solana.PublicKey
+	// Empty if the feature is not in the catalog.
+	Description string
+	State       State
+	// Set for the active features.
+	ActivatedAt *uint64
+}
+
+// ErrNoFeatures is returned when the status of all the features of
+// an empty catalog is requested.
+var ErrNoFeatures = errors.New("no feature ids and no feature in the catalog")
+
+// FetchStatuses fetches the status of the features, in order, with the
+// descriptions of the default catalog; without IDs, it fetches the status
+// of all the features of the catalog.
+func FetchStatuses(ctx context.Context, rpcCli *rpc.Client, ids ...solana.PublicKey) ([]*Status, error) {
+	return FetchStatusesWithCatalog(ctx, rpcCli, DefaultCatalog, ids...)
+}
+
+// FetchStatusesWithCatalog is FetchStatuses with the descriptions of the catalog.
+func FetchStatusesWithCatalog(ctx context.Context, rpcCli *rpc.Client, catalog *Catalog, ids ...solana.PublicKey) ([]*Status, error) {
+	if len(ids) == 0 {
+		ids = catalog.IDs()
+		if len(ids) == 0 {
+			return nil, ErrNoFeatures
+		}
+	}
+	out := make([]*Status, 0, len(ids))
+	for _, chunk := range solana.PublicKeySlice(ids).Split(maxAccountsPerRequest) {
+		resp, err := rpcCli.GetMultipleAccounts(ctx, chunk...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get feature accounts: %w", err)
+		}
+		if len(resp.Value) != len(chunk) {
+			return nil, fmt.Errorf("got %d feature accounts, expected %d", len(resp.Value), len(chunk))
+		}
+		for i, acc := range resp.Value {
+			status, err := newStatus(chunk[i], acc)
+			if err != nil {
+				return nil, err
+			}
+			status.Description, _ = catalog.Description(status.ID)
+			out = append(out, status)
+		}
+	}
+	return out, nil
+}
+
+func newStatus(id solana.PublicKey, acc *rpc.Account) (*Status, error) {
+	status := &Status{ID: id}
+	// A feature account not yet owned by the program is not a request
+	// to activate it.
+	if acc == nil || !acc.Owner.Equals(ProgramID) {
+		return status, nil
+	}
+	feature, err := DecodeFeature(acc.Data.GetBinary())
+	if err != nil {
+		return nil, fmt.Errorf("feature %s: %w", id, err)
+	}
+	status.State = StatePending
+	if feature.ActivatedAt != nil {
+		status.State = StateActive
+		status.ActivatedAt = feature.ActivatedAt
+	}
+	return status, nil
+}
+
+// IsActive reports whether the feature is activated on the cluster.
+func IsActive(ctx context.Context, rpcCli *rpc.Client, id solana.PublicKey) (bool, error) {
+	statuses, err := FetchStatuses(ctx, rpcCli, id)
+	if err != nil {
+		return false, err
+	}
+	return statuses[0].State == StateActive, nil
+}
@@ -56,6 +56,20 @@ func (inst *Create) GetSigner() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[0]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Create) AccountNames() []string {
+	names := make([]string, len(inst.AccountMetaSlice))
+	for i := range names {
+		names[i] = fmt.Sprintf("signers[%d]", i)
+	}
+	return names
+}
+
+// InstructionArgs renders the message as text.
+func (inst Create) InstructionArgs() interface{} {
+	return map[string]string{"Message": string(inst.Message)}
+}
+
 func (inst Create) Build() *MemoInstruction {
 
 	return &MemoInstruction{BaseVariant: ag_binary.BaseVariant{
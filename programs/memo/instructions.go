@@ -60,6 +60,15 @@ func (inst *MemoInstruction) ProgramID() ag_solanago.PublicKey {
 	return ProgramID
 }
 
+func (inst *MemoInstruction) ProgramName() string {
+	return "Memo"
+}
+
+func (inst *MemoInstruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
 func (inst *MemoInstruction) Accounts() (out []*ag_solanago.AccountMeta) {
 	return inst.Impl.(ag_solanago.AccountsGettable).GetAccounts()
 }
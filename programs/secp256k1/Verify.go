@@ -0,0 +1,148 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secp256k1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Verify builds an instruction verifying one or more signatures, whose data
+// is either carried by the instruction or located in other instructions
+// of the transaction.
+type Verify struct {
+	// The index of the instruction in the transaction, which the
+	// offsets of the data carried by the instruction refer to.
+	instructionIndex uint8
+	signatures       []verifySignature
+}
+
+type verifySignature struct {
+	// Set for the signatures located in other instructions.
+	offsets *SignatureOffsets
+
+	ethAddress EthAddress
+	signature  Signature
+	message    []byte
+}
+
+// NewVerifyInstructionBuilder creates a new `Verify` instruction builder.
+func NewVerifyInstructionBuilder() *Verify {
+	return &Verify{}
+}
+
+// NewVerifyInstruction verifies a signature of the message by the
+// Ethereum address, all carried by the instruction.
+func NewVerifyInstruction(
+	ethAddress EthAddress,
+	signature Signature,
+	message []byte,
+) *Verify {
+	return NewVerifyInstructionBuilder().AddSignature(ethAddress, signature, message)
+}
+
+// SetInstructionIndex sets the index of the instruction in the
+// transaction (0 by default).
+func (inst *Verify) SetInstructionIndex(index uint8) *Verify {
+	inst.instructionIndex = index
+	return inst
+}
+
+// AddSignature adds a signature to verify, carried by the instruction
+// with its Ethereum address and message.
+func (inst *Verify) AddSignature(
+	ethAddress EthAddress,
+	signature Signature,
+	message []byte,
+) *Verify {
+	inst.signatures = append(inst.signatures, verifySignature{
+		ethAddress: ethAddress,
+		signature:  signature,
+		message:    message,
+	})
+	return inst
+}
+
+// AddSignatureOffsets adds a signature to verify, located by the offsets
+// (e.g. in the data of another instruction of the transaction).
+func (inst *Verify) AddSignatureOffsets(offsets SignatureOffsets) *Verify {
+	inst.signatures = append(inst.signatures, verifySignature{offsets: &offsets})
+	return inst
+}
+
+func (inst Verify) Build() *Instruction {
+	out := &Instruction{
+		Offsets: make([]SignatureOffsets, len(inst.signatures)),
+		Payload: []byte{},
+	}
+	start := SignatureOffsetsStart + SignatureOffsetsSize*len(inst.signatures)
+	for i, sig := range inst.signatures {
+		if sig.offsets != nil {
+			out.Offsets[i] = *sig.offsets
+			continue
+		}
+		// The address, the signature and its recovery id, then the
+		// message, like the native instruction builder.
+		offset := start + len(out.Payload)
+		out.Offsets[i] = SignatureOffsets{
+			EthAddressOffset:           uint16(offset),
+			EthAddressInstructionIndex: inst.instructionIndex,
+			SignatureOffset:            uint16(offset + EthAddressSize),
+			SignatureInstructionIndex:  inst.instructionIndex,
+			MessageDataOffset:          uint16(offset + EthAddressSize + len(sig.signature)),
+			MessageDataSize:            uint16(len(sig.message)),
+			MessageInstructionIndex:    inst.instructionIndex,
+		}
+		out.Payload = append(out.Payload, sig.ethAddress[:]...)
+		out.Payload = append(out.Payload, sig.signature[:]...)
+		out.Payload = append(out.Payload, sig.message...)
+	}
+	return out
+}
+
+// ValidateAndBuild validates the instruction parameters.
+// If there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Verify) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Verify) Validate() error {
+	if len(inst.signatures) == 0 {
+		return errors.New("no signature to verify")
+	}
+	if len(inst.signatures) > math.MaxUint8 {
+		return fmt.Errorf("too many signatures: %d", len(inst.signatures))
+	}
+	// The offsets must fit the data of the instruction.
+	size := SignatureOffsetsStart + SignatureOffsetsSize*len(inst.signatures)
+	for i, sig := range inst.signatures {
+		if sig.offsets != nil {
+			continue
+		}
+		if sig.signature.RecoveryID() > 3 {
+			return fmt.Errorf("signature %d: recovery id must be between 0 and 3, not %d", i, sig.signature.RecoveryID())
+		}
+		size += EthAddressSize + len(sig.signature) + len(sig.message)
+	}
+	if size > math.MaxUint16 {
+		return fmt.Errorf("instruction data too large: %d bytes", size)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secp256k1 builds, decodes and verifies the instructions of the
+// Secp256k1 precompile, which fails the transaction unless the Ethereum
+// addresses recovered from all the signatures its instructions point to
+// match the expected ones.
+package secp256k1
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+var ProgramID solana.PublicKey = solana.Secp256k1ProgramID
+
+func SetProgramID(pubkey solana.PublicKey) {
+	ProgramID = pubkey
+	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+}
+
+const ProgramName = "Secp256k1"
+
+func init() {
+	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+}
+
+const (
+	EthAddressSize = 20
+	// The signature is followed by its recovery id.
+	SignatureSize = 64
+
+	// The offsets start after the number of signatures.
+	SignatureOffsetsStart = 1
+	SignatureOffsetsSize  = 11
+	// The data of the first signature starts after the offsets
+	// of a single signature.
+	DataStart = SignatureOffsetsStart + SignatureOffsetsSize
+)
+
+// SignatureOffsets locates the signature (with its recovery id), the
+// Ethereum address and the message of a signature to verify, each in the
+// data of the instruction at the given index of the transaction.
+type SignatureOffsets struct {
+	SignatureOffset            uint16
+	SignatureInstructionIndex  uint8
+	EthAddressOffset           uint16
+	EthAddressInstructionIndex uint8
+	MessageDataOffset          uint16
+	MessageDataSize            uint16
+	MessageInstructionIndex    uint8
+}
+
+func (offsets SignatureOffsets) MarshalWithEncoder(encoder *bin.Encoder) (err error) {
+	if err = encoder.WriteUint16(offsets.SignatureOffset, binary.LittleEndian); err != nil {
+		return err
+	}
+	if err = encoder.WriteUint8(offsets.SignatureInstructionIndex); err != nil {
+		return err
+	}
+	if err = encoder.WriteUint16(offsets.EthAddressOffset, binary.LittleEndian); err != nil {
+		return err
+	}
+	if err = encoder.WriteUint8(offsets.EthAddressInstructionIndex); err != nil {
+		return err
+	}
+	if err = encoder.WriteUint16(offsets.MessageDataOffset, binary.LittleEndian); err != nil {
+		return err
+	}
+	if err = encoder.WriteUint16(offsets.MessageDataSize, binary.LittleEndian); err != nil {
+		return err
+	}
+	return encoder.WriteUint8(offsets.MessageInstructionIndex)
+}
+
+func (offsets *SignatureOffsets) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if offsets.SignatureOffset, err = decoder.ReadUint16(binary.LittleEndian); err != nil {
+		return err
+	}
+	if offsets.SignatureInstructionIndex, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	if offsets.EthAddressOffset, err = decoder.ReadUint16(binary.LittleEndian); err != nil {
+		return err
+	}
+	if offsets.EthAddressInstructionIndex, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	if offsets.MessageDataOffset, err = decoder.ReadUint16(binary.LittleEndian); err != nil {
+		return err
+	}
+	if offsets.MessageDataSize, err = decoder.ReadUint16(binary.LittleEndian); err != nil {
+		return err
+	}
+	offsets.MessageInstructionIndex, err = decoder.ReadUint8()
+	return err
+}
+
+// Instruction is an instruction of the precompile: the offsets of the
+// signatures to verify, followed by the data they point to, if any.
+type Instruction struct {
+	Offsets []SignatureOffsets
+	// The data following the offsets; the offsets are relative
+	// to the start of the instruction data, not of the payload.
+	Payload []byte
+}
+
+func (inst *Instruction) ProgramID() solana.PublicKey {
+	return ProgramID
+}
+
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	return "Verify"
+}
+
+func (inst *Instruction) Accounts() (out []*solana.AccountMeta) {
+	return nil
+}
+
+func (inst *Instruction) Data() ([]byte, error) {
+	return bin.MarshalBin(inst)
+}
+
+func (inst *Instruction) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Verify")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Params[len=%v]", len(inst.Offsets)+1)).ParentFunc(func(paramsBranch treeout.Branches) {
+						for i, offsets := range inst.Offsets {
+							paramsBranch.Child(format.Param(fmt.Sprintf("Offsets[%v]", i), offsets))
+						}
+						paramsBranch.Child(format.Param("Payload", bin.FormatByteSlice(inst.Payload)))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts[len=0]").ParentFunc(func(accountsBranch treeout.Branches) {})
+				})
+		})
+}
+
+func (inst Instruction) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if len(inst.Offsets) > 0xff {
+		return fmt.Errorf("too many signatures: %d", len(inst.Offsets))
+	}
+	if err := encoder.WriteUint8(uint8(len(inst.Offsets))); err != nil {
+		return err
+	}
+	for i := range inst.Offsets {
+		if err := inst.Offsets[i].MarshalWithEncoder(encoder); err != nil {
+			return fmt.Errorf("unable to write offsets %d: %w", i, err)
+		}
+	}
+	return encoder.WriteBytes(inst.Payload, false)
+}
+
+func (inst *Instruction) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	count, err := decoder.ReadUint8()
+	if err != nil {
+		return fmt.Errorf("unable to read the number of signatures: %w", err)
+	}
+	inst.Offsets = make([]SignatureOffsets, count)
+	for i := range inst.Offsets {
+		if err := inst.Offsets[i].UnmarshalWithDecoder(decoder); err != nil {
+			return fmt.Errorf("unable to read offsets %d: %w", i, err)
+		}
+	}
+	inst.Payload, err = decoder.ReadNBytes(decoder.Remaining())
+	return err
+}
+
+func registryDecodeInstruction(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// DecodeInstruction decodes the offsets and the payload of an instruction;
+// it doesn't verify the signatures (see VerifyInstruction).
+func DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (*Instruction, error) {
+	inst := new(Instruction)
+	if err := bin.NewBinDecoder(data).Decode(inst); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction: %w", err)
+	}
+	return inst, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secp256k1
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// EthAddress is an Ethereum address: the last 20 bytes of the Keccak-256
+// hash of an uncompressed public key.
+type EthAddress [EthAddressSize]byte
+
+// EthAddressFromHex parses an address, with or without its 0x prefix.
+func EthAddressFromHex(in string) (out EthAddress, err error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(in, "0x"), "0X"))
+	if err != nil {
+		return out, fmt.Errorf("invalid eth address %q: %w", in, err)
+	}
+	if len(b) != EthAddressSize {
+		return out, fmt.Errorf("invalid eth address %q: %d bytes", in, len(b))
+	}
+	copy(out[:], b)
+	return out, nil
+}
+
+func (a EthAddress) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// EthAddressFromPublicKey returns the address of an uncompressed public key,
+// in 64 bytes (x and y) or 65 bytes (with the 0x04 prefix).
+func EthAddressFromPublicKey(publicKey []byte) (out EthAddress, err error) {
+	if len(publicKey) == 65 && publicKey[0] == 0x04 {
+		publicKey = publicKey[1:]
+	}
+	if len(publicKey) != 64 {
+		return out, fmt.Errorf("invalid uncompressed public key: %d bytes", len(publicKey))
+	}
+	copy(out[:], keccak256(publicKey)[12:])
+	return out, nil
+}
+
+// Signature is a recoverable signature: r, s and the recovery id,
+// from 0 to 3 (not 27 or 28 like the v of Ethereum transactions).
+type Signature [SignatureSize + 1]byte
+
+func (sig Signature) RecoveryID() uint8 {
+	return sig[SignatureSize]
+}
+
+// RecoverEthAddress recovers the address of the signer of the Keccak-256
+// hash of the message, like the precompile.
+func RecoverEthAddress(message []byte, signature Signature) (out EthAddress, err error) {
+	if signature.RecoveryID() > 3 {
+		return out, ErrInvalidRecoveryId
+	}
+	publicKey, err := recoverPublicKey(keccak256(message), signature[:SignatureSize], signature.RecoveryID())
+	if err != nil {
+		return out, err
+	}
+	return EthAddressFromPublicKey(publicKey)
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+var errRecoverPublicKey = errors.New("unable to recover public key")
+
+// recoverPublicKey recovers the uncompressed public key (without prefix)
+// from a signature (r and s) of the hash, and the recovery id telling
+// which of the points with the x coordinate r (or r + n) was used.
+func recoverPublicKey(hash []byte, signature []byte, recoveryID uint8) ([]byte, error) {
+	// Compact signatures are prefixed with 27 plus the recovery id
+	// (for uncompressed public keys).
+	compact := make([]byte, 0, SignatureSize+1)
+	compact = append(compact, 27+recoveryID)
+	compact = append(compact, signature[:SignatureSize]...)
+	publicKey, _, err := ecdsa.RecoverCompact(compact, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errRecoverPublicKey, err)
+	}
+	return publicKey.SerializeUncompressed()[1:], nil
+}
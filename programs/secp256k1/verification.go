@@ -0,0 +1,125 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secp256k1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// The errors of the precompile.
+var (
+	ErrInvalidSignature           = errors.New("invalid signature")
+	ErrInvalidRecoveryId          = errors.New("invalid recovery id")
+	ErrInvalidDataOffsets         = errors.New("invalid data offsets")
+	ErrInvalidInstructionDataSize = errors.New("invalid instruction data size")
+)
+
+// VerifyInstruction verifies the signatures of the instruction data like the
+// precompile, with instructionDatas the data of all the instructions of the
+// transaction (including the verifying one, which the offsets refer to by
+// its index). It fails with one of the errors of the precompile.
+func VerifyInstruction(data []byte, instructionDatas [][]byte) error {
+	if len(data) < SignatureOffsetsStart {
+		return ErrInvalidInstructionDataSize
+	}
+	count := int(data[0])
+	if count == 0 && len(data) > SignatureOffsetsStart {
+		return ErrInvalidInstructionDataSize
+	}
+	if len(data) < SignatureOffsetsStart+count*SignatureOffsetsSize {
+		return ErrInvalidInstructionDataSize
+	}
+	for i := 0; i < count; i++ {
+		start := SignatureOffsetsStart + i*SignatureOffsetsSize
+		var offsets SignatureOffsets
+		if err := bin.NewBinDecoder(data[start : start+SignatureOffsetsSize]).Decode(&offsets); err != nil {
+			return ErrInvalidInstructionDataSize
+		}
+		if err := verifySignatureOffsets(offsets, instructionDatas); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func verifySignatureOffsets(offsets SignatureOffsets, instructionDatas [][]byte) error {
+	if int(offsets.SignatureInstructionIndex) >= len(instructionDatas) {
+		return ErrInvalidInstructionDataSize
+	}
+	instruction := instructionDatas[offsets.SignatureInstructionIndex]
+	// The signature is followed by its recovery id.
+	end := int(offsets.SignatureOffset) + SignatureSize
+	if end >= len(instruction) {
+		return ErrInvalidSignature
+	}
+	var signature Signature
+	copy(signature[:], instruction[offsets.SignatureOffset:end+1])
+	if signature.RecoveryID() > 3 {
+		return ErrInvalidRecoveryId
+	}
+
+	ethAddress, err := dataSlice(instructionDatas, offsets.EthAddressInstructionIndex, offsets.EthAddressOffset, EthAddressSize)
+	if err != nil {
+		return err
+	}
+	message, err := dataSlice(instructionDatas, offsets.MessageInstructionIndex, offsets.MessageDataOffset, int(offsets.MessageDataSize))
+	if err != nil {
+		return err
+	}
+	recovered, err := RecoverEthAddress(message, signature)
+	if err != nil || !bytes.Equal(recovered[:], ethAddress) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func dataSlice(instructionDatas [][]byte, instructionIndex uint8, offset uint16, size int) ([]byte, error) {
+	if int(instructionIndex) >= len(instructionDatas) {
+		return nil, ErrInvalidDataOffsets
+	}
+	instruction := instructionDatas[instructionIndex]
+	end := int(offset) + size
+	if end > len(instruction) {
+		return nil, ErrInvalidSignature
+	}
+	return instruction[offset:end], nil
+}
+
+// VerifyTransaction verifies, like the precompile, the signatures of all the
+// instructions of the transaction for the program.
+func VerifyTransaction(tx *solana.Transaction) error {
+	instructionDatas := make([][]byte, len(tx.Message.Instructions))
+	for i, inst := range tx.Message.Instructions {
+		instructionDatas[i] = inst.Data
+	}
+	for i, inst := range tx.Message.Instructions {
+		programID, err := tx.Message.Program(inst.ProgramIDIndex)
+		if err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+		if !programID.Equals(ProgramID) {
+			continue
+		}
+		if err := VerifyInstruction(inst.Data, instructionDatas); err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+	}
+	return nil
+}
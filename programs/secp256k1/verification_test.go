@@ -0,0 +1,184 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secp256k1
+
+import (
+	"encoding/hex"
+	"testing"
+
+	dcrsecp256k1 "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func testPrivateKey(n uint32) *dcrsecp256k1.PrivateKey {
+	var key dcrsecp256k1.ModNScalar
+	key.SetInt(n)
+	return dcrsecp256k1.NewPrivateKey(&key)
+}
+
+// testSign signs the Keccak-256 hash of the message with the private key.
+func testSign(t *testing.T, privateKey *dcrsecp256k1.PrivateKey, message []byte) Signature {
+	compact := ecdsa.SignCompact(privateKey, keccak256(message), false)
+	// The compact signature starts with 27 plus the recovery id.
+	var out Signature
+	copy(out[:SignatureSize], compact[1:])
+	out[SignatureSize] = compact[0] - 27
+	return out
+}
+
+func testEthAddress(t *testing.T, privateKey *dcrsecp256k1.PrivateKey) EthAddress {
+	address, err := EthAddressFromPublicKey(privateKey.PubKey().SerializeUncompressed())
+	require.NoError(t, err)
+	return address
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	out, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return out
+}
+
+// Known answers computed by go-ethereum (crypto.Ecrecover and the
+// ecrecover precompile), which use libsecp256k1.
+func TestRecoverPublicKey_KnownAnswers(t *testing.T) {
+	t.Run("go-ethereum signature test", func(t *testing.T) {
+		hash := mustHex(t, "ce0677bb30baa8cf067c88db9811f4333d131bf8bcf12fe7065d211dce971008")
+		signature := mustHex(t, "90f27b8b488db00b00606796d2987f6a5f59ae62ea05effe84fef5b8b0e549984a691139ad57a3f0b906637673aa2f63d1f55cb1a69199d4009eea23ceaddc93")
+		publicKey, err := recoverPublicKey(hash, signature, 1)
+		require.NoError(t, err)
+		require.Equal(t, mustHex(t, "e32df42865e97135acfb65f3bae71bdc86f4d49150ad6a440b6f15878109880a0a2b2667f7e725ceea70c673093bf67663e0312623c8e091b13cf2c0f11ef652"), publicKey)
+	})
+	t.Run("ecrecover precompile", func(t *testing.T) {
+		hash := mustHex(t, "38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e")
+		signature := mustHex(t, "38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e789d1dd423d25f0772d2748d60f7e4b81bb14d086eba8e8e8efb6dcff8a4ae02")
+		publicKey, err := recoverPublicKey(hash, signature, 0)
+		require.NoError(t, err)
+		address, err := EthAddressFromPublicKey(publicKey)
+		require.NoError(t, err)
+		require.Equal(t, "0xceaccac640adf55b2028469bd36ba501f28b699d", address.String())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := recoverPublicKey(make([]byte, 32), make([]byte, SignatureSize), 0)
+		require.ErrorIs(t, err, errRecoverPublicKey)
+	})
+}
+
+func TestEthAddressFromPublicKey(t *testing.T) {
+	require.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", testEthAddress(t, testPrivateKey(1)).String())
+	require.Equal(t, "0x2b5ad5c4795c026514f8317c7a215e218dccd6cf", testEthAddress(t, testPrivateKey(2)).String())
+
+	address, err := EthAddressFromHex("0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF")
+	require.NoError(t, err)
+	require.Equal(t, testEthAddress(t, testPrivateKey(2)), address)
+}
+
+func TestVerifyInstruction(t *testing.T) {
+	privateKey := testPrivateKey(0xc0ffee)
+	address := testEthAddress(t, privateKey)
+	message := []byte("hello")
+	signature := testSign(t, privateKey, message)
+
+	recovered, err := RecoverEthAddress(message, signature)
+	require.NoError(t, err)
+	require.Equal(t, address, recovered)
+
+	inst, err := NewVerifyInstruction(address, signature, message).ValidateAndBuild()
+	require.NoError(t, err)
+	data, err := inst.Data()
+	require.NoError(t, err)
+	require.Equal(t, DataStart+EthAddressSize+SignatureSize+1+len(message), len(data))
+	require.Equal(t, []byte{
+		1,        // one signature
+		32, 0, 0, // signature
+		12, 0, 0, // address
+		97, 0, 5, 0, 0, // message
+	}, data[:DataStart])
+	require.NoError(t, VerifyInstruction(data, [][]byte{data}))
+
+	decoded, err := solana.DecodeInstruction(ProgramID, nil, data)
+	require.NoError(t, err)
+	require.Equal(t, inst, decoded)
+
+	tamper := func(i int) []byte {
+		tampered := append([]byte{}, data...)
+		tampered[i] ^= 1
+		return tampered
+	}
+	t.Run("tampered message", func(t *testing.T) {
+		tampered := tamper(len(data) - 1)
+		require.ErrorIs(t, VerifyInstruction(tampered, [][]byte{tampered}), ErrInvalidSignature)
+	})
+	t.Run("other address", func(t *testing.T) {
+		tampered := tamper(DataStart)
+		require.ErrorIs(t, VerifyInstruction(tampered, [][]byte{tampered}), ErrInvalidSignature)
+	})
+	t.Run("invalid recovery id", func(t *testing.T) {
+		tampered := append([]byte{}, data...)
+		tampered[DataStart+EthAddressSize+SignatureSize] = 27
+		require.ErrorIs(t, VerifyInstruction(tampered, [][]byte{tampered}), ErrInvalidRecoveryId)
+	})
+	t.Run("offsets", func(t *testing.T) {
+		require.ErrorIs(t, VerifyInstruction(data, nil), ErrInvalidInstructionDataSize)
+		require.ErrorIs(t, VerifyInstruction(data[:DataStart-1], nil), ErrInvalidInstructionDataSize)
+		require.ErrorIs(t, VerifyInstruction(data, [][]byte{data[:len(data)-1]}), ErrInvalidSignature)
+		require.ErrorIs(t, VerifyInstruction([]byte{}, nil), ErrInvalidInstructionDataSize)
+		require.NoError(t, VerifyInstruction([]byte{0}, nil))
+	})
+}
+
+func TestVerifyTransaction(t *testing.T) {
+	oracle := testPrivateKey(0xbeef)
+	signer := testPrivateKey(0xcafe)
+	attestation := []byte("price=42")
+	attestationSignature := testSign(t, oracle, attestation)
+	signature := testSign(t, signer, []byte("inline"))
+
+	// The attestation lives in the data of the first instruction.
+	oracleAddress := testEthAddress(t, oracle)
+	oracleData := append(append(append([]byte{}, oracleAddress[:]...), attestationSignature[:]...), attestation...)
+	inst, err := NewVerifyInstruction(testEthAddress(t, signer), signature, []byte("inline")).
+		SetInstructionIndex(1).
+		AddSignatureOffsets(SignatureOffsets{
+			EthAddressOffset:           0,
+			EthAddressInstructionIndex: 0,
+			SignatureOffset:            EthAddressSize,
+			SignatureInstructionIndex:  0,
+			MessageDataOffset:          EthAddressSize + SignatureSize + 1,
+			MessageDataSize:            uint16(len(attestation)),
+			MessageInstructionIndex:    0,
+		}).
+		ValidateAndBuild()
+	require.NoError(t, err)
+
+	build := func(oracleData []byte) *solana.Transaction {
+		tx, err := solana.NewTransaction(
+			[]solana.Instruction{
+				solana.NewInstruction(solana.NewWallet().PublicKey(), solana.AccountMetaSlice{}, oracleData),
+				inst,
+			},
+			solana.Hash{},
+			solana.TransactionPayer(solana.NewWallet().PublicKey()),
+		)
+		require.NoError(t, err)
+		return tx
+	}
+	require.NoError(t, VerifyTransaction(build(oracleData)))
+
+	tampered := append([]byte{}, oracleData...)
+	tampered[len(tampered)-1] ^= 1
+	require.ErrorIs(t, VerifyTransaction(build(tampered)), ErrInvalidSignature)
+}
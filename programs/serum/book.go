@@ -0,0 +1,380 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// BookOrder is an order of the L3 book of a market.
+type BookOrder struct {
+	OrderID OrderID
+	Side    Side
+
+	// The open orders account of the order, and the slot of the order in it.
+	Owner     solana.PublicKey
+	OwnerSlot uint8
+
+	ClientOrderID uint64
+	PriceLots     uint64
+	QuantityLots  uint64
+}
+
+// BookLevel is a price level of the L2 book of a market: the orders at a
+// price, aggregated.
+type BookLevel struct {
+	PriceLots    uint64
+	QuantityLots uint64
+	Orders       int
+
+	// The price and quantity in UI units.
+	Price    *big.Float
+	Quantity *big.Float
+}
+
+// Book is a snapshot of the book of a market, with the best levels and
+// orders first.
+type Book struct {
+	Bids []*BookLevel
+	Asks []*BookLevel
+
+	BidOrders []*BookOrder
+	AskOrders []*BookOrder
+}
+
+// Levels returns the levels of a side of the book.
+func (b *Book) Levels(side Side) []*BookLevel {
+	if side == SideBid {
+		return b.Bids
+	}
+	return b.Asks
+}
+
+// Orders returns the orders of a side of the book.
+func (b *Book) Orders(side Side) []*BookOrder {
+	if side == SideBid {
+		return b.BidOrders
+	}
+	return b.AskOrders
+}
+
+// BookLevelChange is the change of a level of the L2 book.
+type BookLevelChange struct {
+	Side      Side
+	PriceLots uint64
+
+	// The new quantity of the level; zero if the level was removed.
+	QuantityLots uint64
+
+	// The price and the new quantity in UI units.
+	Price    *big.Float
+	Quantity *big.Float
+}
+
+// BookDiff is the changes of the book of a market between two snapshots.
+type BookDiff struct {
+	// The slot of the new snapshot.
+	Slot uint64
+
+	// The changed levels of the L2 book, bids first, by price in the order of
+	// the book.
+	Levels []*BookLevelChange
+
+	// The changes of the L3 book: the orders added, removed (filled or
+	// cancelled), and partially filled, with their new quantity.
+	Added   []*BookOrder
+	Removed []*BookOrder
+	Updated []*BookOrder
+}
+
+// IsEmpty returns whether the book didn't change.
+func (d *BookDiff) IsEmpty() bool {
+	return len(d.Levels) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Updated) == 0
+}
+
+// bookSide builds the levels and orders of a side of the book from its
+// slab, in the order of the book.
+func (m *MarketMeta) bookSide(slab *Orderbook, side Side) (levels []*BookLevel, orders []*BookOrder) {
+	slab.Items(side == SideBid, func(node *SlabLeafNode) error {
+		order := &BookOrder{
+			OrderID:       OrderID(node.Key),
+			Side:          side,
+			Owner:         node.Owner,
+			OwnerSlot:     node.OwnerSlot,
+			ClientOrderID: uint64(node.ClientOrderId),
+			PriceLots:     OrderID(node.Key).Price(),
+			QuantityLots:  uint64(node.Quantity),
+		}
+		orders = append(orders, order)
+		if len(levels) > 0 && levels[len(levels)-1].PriceLots == order.PriceLots {
+			levels[len(levels)-1].QuantityLots += order.QuantityLots
+			levels[len(levels)-1].Orders++
+		} else {
+			levels = append(levels, &BookLevel{PriceLots: order.PriceLots, QuantityLots: order.QuantityLots, Orders: 1})
+		}
+		return nil
+	})
+	for _, level := range levels {
+		level.Price = m.PriceLotsToNumber(new(big.Int).SetUint64(level.PriceLots))
+		level.Quantity = m.BaseSizeLotsToNumber(new(big.Int).SetUint64(level.QuantityLots))
+	}
+	return levels, orders
+}
+
+// DiffBooks returns the changes from the previous book to the next one; a nil
+// previous book is empty.
+func (m *MarketMeta) DiffBooks(prev, next *Book) *BookDiff {
+	if prev == nil {
+		prev = &Book{}
+	}
+	diff := &BookDiff{}
+	for _, side := range []Side{SideBid, SideAsk} {
+		diff.Levels = append(diff.Levels, diffLevels(side, prev.Levels(side), next.Levels(side))...)
+
+		oldOrders := make(map[OrderID]*BookOrder, len(prev.Orders(side)))
+		for _, order := range prev.Orders(side) {
+			oldOrders[order.OrderID] = order
+		}
+		for _, order := range next.Orders(side) {
+			oldOrder, ok := oldOrders[order.OrderID]
+			switch {
+			case !ok:
+				diff.Added = append(diff.Added, order)
+			case oldOrder.QuantityLots != order.QuantityLots:
+				diff.Updated = append(diff.Updated, order)
+			}
+			delete(oldOrders, order.OrderID)
+		}
+		for _, order := range prev.Orders(side) {
+			if _, ok := oldOrders[order.OrderID]; ok {
+				diff.Removed = append(diff.Removed, order)
+			}
+		}
+	}
+	return diff
+}
+
+func diffLevels(side Side, prev, next []*BookLevel) (out []*BookLevelChange) {
+	// Merges the levels, both in the order of the book.
+	better := func(a, b uint64) bool {
+		if side == SideBid {
+			return a > b
+		}
+		return a < b
+	}
+	removed := func(level *BookLevel) *BookLevelChange {
+		return &BookLevelChange{
+			Side:      side,
+			PriceLots: level.PriceLots,
+			Price:     level.Price,
+			Quantity:  new(big.Float),
+		}
+	}
+	changed := func(level *BookLevel) *BookLevelChange {
+		return &BookLevelChange{
+			Side:         side,
+			PriceLots:    level.PriceLots,
+			QuantityLots: level.QuantityLots,
+			Price:        level.Price,
+			Quantity:     level.Quantity,
+		}
+	}
+	i, j := 0, 0
+	for i < len(prev) || j < len(next) {
+		switch {
+		case j == len(next) || (i < len(prev) && better(prev[i].PriceLots, next[j].PriceLots)):
+			out = append(out, removed(prev[i]))
+			i++
+		case i == len(prev) || better(next[j].PriceLots, prev[i].PriceLots):
+			out = append(out, changed(next[j]))
+			j++
+		default:
+			if prev[i].QuantityLots != next[j].QuantityLots {
+				out = append(out, changed(next[j]))
+			}
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// BookManager maintains the book of a market from the data of its bids and
+// asks accounts.
+type BookManager struct {
+	Market *MarketMeta
+
+	mu   sync.Mutex
+	book *Book
+	// The slots of the bids and asks of the book.
+	slots [2]uint64
+}
+
+// NewBookManager returns the manager of the book of the market, with an
+// empty book.
+func NewBookManager(market *MarketMeta) *BookManager {
+	return &BookManager{
+		Market: market,
+		book:   &Book{},
+	}
+}
+
+// Book returns the latest snapshot of the book; snapshots are not modified.
+func (bm *BookManager) Book() *Book {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	return bm.book
+}
+
+// Update replaces a side of the book with the data of its slab account at the
+// slot, and returns the changes of the book; data older than the side of the
+// book changes nothing.
+func (bm *BookManager) Update(side Side, slot uint64, data []byte) (*BookDiff, error) {
+	slab := &Orderbook{}
+	if err := bin.NewBinDecoder(data).Decode(slab); err != nil {
+		return nil, fmt.Errorf("decoding orderbook: %w", err)
+	}
+	wantFlag := AccountFlagBids
+	if side == SideAsk {
+		wantFlag = AccountFlagAsks
+	}
+	if !slab.AccountFlags.Is(wantFlag) {
+		return nil, fmt.Errorf("unexpected orderbook account: %s", slab.AccountFlags.String())
+	}
+	levels, orders := bm.Market.bookSide(slab, side)
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	if slot < bm.slots[side] {
+		return &BookDiff{Slot: slot}, nil
+	}
+	bm.slots[side] = slot
+	book := *bm.book
+	if side == SideBid {
+		book.Bids, book.BidOrders = levels, orders
+	} else {
+		book.Asks, book.AskOrders = levels, orders
+	}
+	diff := bm.Market.DiffBooks(bm.book, &book)
+	diff.Slot = slot
+	bm.book = &book
+	return diff, nil
+}
+
+// WatchBook fetches the market at the address and its book, then subscribes
+// to its bids and asks accounts to maintain the book. It calls handle with
+// the book and its changes: first with the whole fetched book, then at each
+// change, until the context is done or handle returns an error.
+func WatchBook(
+	ctx context.Context,
+	rpcCli *rpc.Client,
+	wsClient *ws.Client,
+	marketAddr solana.PublicKey,
+	commitment rpc.CommitmentType,
+	handle func(market *MarketMeta, book *Book, diff *BookDiff) error,
+) error {
+	market, err := FetchMarket(ctx, rpcCli, marketAddr)
+	if err != nil {
+		return fmt.Errorf("fetch market: %w", err)
+	}
+	bm := NewBookManager(market)
+
+	type update struct {
+		side Side
+		res  *ws.AccountResult
+		err  error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	updates := make(chan update)
+	for _, side := range []Side{SideBid, SideAsk} {
+		address := market.MarketV2.Bids
+		if side == SideAsk {
+			address = market.MarketV2.Asks
+		}
+		// Subscribes before fetching, to miss no change.
+		sub, err := wsClient.AccountSubscribe(address, commitment)
+		if err != nil {
+			return fmt.Errorf("unable to subscribe to orderbook %q: %w", address, err)
+		}
+		defer sub.Unsubscribe()
+		go func(side Side, sub *ws.AccountSubscription) {
+			for {
+				res, err := sub.Recv(ctx)
+				select {
+				case updates <- update{side: side, res: res, err: err}:
+				case <-ctx.Done():
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}(side, sub)
+	}
+
+	var slot uint64
+	for _, side := range []Side{SideBid, SideAsk} {
+		address := market.MarketV2.Bids
+		if side == SideAsk {
+			address = market.MarketV2.Asks
+		}
+		out, err := rpcCli.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{Commitment: commitment})
+		if err != nil {
+			return fmt.Errorf("unable to get orderbook %q: %w", address, err)
+		}
+		if _, err := bm.Update(side, out.Context.Slot, out.Value.Data.GetBinary()); err != nil {
+			return err
+		}
+		if out.Context.Slot > slot {
+			slot = out.Context.Slot
+		}
+	}
+	// The whole book, as changes from an empty book.
+	diff := market.DiffBooks(nil, bm.Book())
+	diff.Slot = slot
+	if err := handle(market, bm.Book(), diff); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case u := <-updates:
+			if u.err != nil {
+				return fmt.Errorf("received error from orderbook subscription: %w", u.err)
+			}
+			diff, err := bm.Update(u.side, u.res.Context.Slot, u.res.Value.Data.GetBinary())
+			if err != nil {
+				return err
+			}
+			if diff.IsEmpty() {
+				continue
+			}
+			if err := handle(market, bm.Book(), diff); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serum
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/rpctest"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+	"github.com/stretchr/testify/require"
+)
+
+type testLeaf struct {
+	price, seq, quantity uint64
+}
+
+// encodeSlab encodes the slab of the leaves, sorted by key, in a tree whose
+// inner nodes each have the next leaf as greater child.
+func encodeSlab(t *testing.T, flag AccountFlag, leaves []testLeaf) []byte {
+	ob := &Orderbook{AccountFlags: AccountFlagInitialized | flag, LeafCount: uint32(len(leaves))}
+	for _, leaf := range leaves {
+		ob.Nodes = append(ob.Nodes, &Slab{BaseVariant: bin.BaseVariant{
+			TypeID: bin.TypeIDFromUint32(2, bin.LE),
+			Impl: &SlabLeafNode{
+				OwnerSlot:     uint8(leaf.seq),
+				Key:           bin.Uint128{Hi: leaf.price, Lo: leaf.seq},
+				Quantity:      bin.Uint64(leaf.quantity),
+				ClientOrderId: bin.Uint64(leaf.seq),
+			},
+		}})
+	}
+	root := uint32(0)
+	for i := 1; i < len(leaves); i++ {
+		ob.Nodes = append(ob.Nodes, &Slab{BaseVariant: bin.BaseVariant{
+			TypeID: bin.TypeIDFromUint32(1, bin.LE),
+			Impl:   &SlabInnerNode{Children: [2]uint32{root, uint32(i)}},
+		}})
+		root = uint32(len(ob.Nodes) - 1)
+	}
+	ob.Root = root
+	ob.BumpIndex = uint32(len(ob.Nodes))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewBinEncoder(buf).Encode(ob))
+	return buf.Bytes()
+}
+
+func newTestMarket() *MarketMeta {
+	market := &MarketMeta{}
+	market.MarketV2.BaseLotSize = 100
+	market.MarketV2.QuoteLotSize = 10
+	market.BaseMint.Decimals = 2
+	market.QuoteMint.Decimals = 1
+	return market
+}
+
+var (
+	testBids = []testLeaf{{price: 9, seq: 3, quantity: 4}, {price: 10, seq: 2, quantity: 3}, {price: 10, seq: 1, quantity: 5}}
+	testAsks = []testLeaf{{price: 11, seq: 4, quantity: 2}}
+	// Order 1 was partially filled, order 2 filled and order 5 added.
+	testBidsNext = []testLeaf{{price: 8, seq: 5, quantity: 7}, {price: 9, seq: 3, quantity: 4}, {price: 10, seq: 1, quantity: 1}}
+)
+
+func levelPrices(changes []*BookLevelChange) (out [][3]uint64) {
+	for _, change := range changes {
+		out = append(out, [3]uint64{uint64(change.Side), change.PriceLots, change.QuantityLots})
+	}
+	return out
+}
+
+func orderSeqs(orders []*BookOrder) (out []uint64) {
+	for _, order := range orders {
+		out = append(out, order.ClientOrderID)
+	}
+	return out
+}
+
+func TestBookManager(t *testing.T) {
+	bm := NewBookManager(newTestMarket())
+
+	diff, err := bm.Update(SideBid, 5, encodeSlab(t, AccountFlagBids, testBids))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), diff.Slot)
+	require.Equal(t, [][3]uint64{{SideBid, 10, 8}, {SideBid, 9, 4}}, levelPrices(diff.Levels))
+	require.Equal(t, []uint64{1, 2, 3}, orderSeqs(diff.Added))
+
+	_, err = bm.Update(SideAsk, 5, encodeSlab(t, AccountFlagBids, testAsks))
+	require.Error(t, err)
+	diff, err = bm.Update(SideAsk, 5, encodeSlab(t, AccountFlagAsks, testAsks))
+	require.NoError(t, err)
+	require.Equal(t, [][3]uint64{{SideAsk, 11, 2}}, levelPrices(diff.Levels))
+
+	book := bm.Book()
+	require.Len(t, book.Bids, 2)
+	require.Equal(t, 2, book.Bids[0].Orders)
+	// 10 lots of 10 quote units of 0.1, per lot of 100 base units of 0.01.
+	price, _ := book.Bids[0].Price.Float64()
+	require.Equal(t, 10.0, price)
+	quantity, _ := book.Bids[0].Quantity.Float64()
+	require.Equal(t, 8.0, quantity)
+
+	diff, err = bm.Update(SideBid, 6, encodeSlab(t, AccountFlagBids, testBidsNext))
+	require.NoError(t, err)
+	require.Equal(t, [][3]uint64{{SideBid, 10, 1}, {SideBid, 8, 7}}, levelPrices(diff.Levels))
+	require.Equal(t, []uint64{5}, orderSeqs(diff.Added))
+	require.Equal(t, []uint64{1}, orderSeqs(diff.Updated))
+	require.Equal(t, []uint64{2}, orderSeqs(diff.Removed))
+	// The previous snapshot is unchanged.
+	require.Len(t, book.BidOrders, 3)
+
+	// Older data changes nothing.
+	diff, err = bm.Update(SideBid, 5, encodeSlab(t, AccountFlagBids, testBids))
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+
+	diff, err = bm.Update(SideBid, 7, encodeSlab(t, AccountFlagBids, nil))
+	require.NoError(t, err)
+	require.Equal(t, [][3]uint64{{SideBid, 10, 0}, {SideBid, 9, 0}, {SideBid, 8, 0}}, levelPrices(diff.Levels))
+	require.Len(t, diff.Removed, 3)
+}
+
+func TestWatchBook(t *testing.T) {
+	srv := rpctest.NewServer(nil)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wsClient, err := ws.Connect(ctx, srv.WSURL())
+	require.NoError(t, err)
+	defer wsClient.Close()
+
+	encode := func(v interface{}) []byte {
+		buf := new(bytes.Buffer)
+		require.NoError(t, bin.NewBinEncoder(buf).Encode(v))
+		return buf.Bytes()
+	}
+	testMarket := newTestMarket()
+	marketAddr := solana.NewWallet().PublicKey()
+	market := &testMarket.MarketV2
+	market.AccountFlags = AccountFlagInitialized | AccountFlagMarket
+	market.BaseMint = solana.NewWallet().PublicKey()
+	market.QuoteMint = solana.NewWallet().PublicKey()
+	market.Bids = solana.NewWallet().PublicKey()
+	market.Asks = solana.NewWallet().PublicKey()
+	marketData := encode(market)
+	require.Len(t, marketData, 388)
+	srv.SetAccount(marketAddr, &rpctest.Account{Lamports: 1, Owner: DEXProgramIDV3, Data: marketData})
+	for mint, decimals := range map[solana.PublicKey]uint8{market.BaseMint: 2, market.QuoteMint: 1} {
+		data := encode(&token.Mint{Decimals: decimals, IsInitialized: true})
+		srv.SetAccount(mint, &rpctest.Account{Lamports: 1, Owner: solana.TokenProgramID, Data: data})
+	}
+	srv.SetAccount(market.Bids, &rpctest.Account{Lamports: 1, Owner: DEXProgramIDV3, Data: encodeSlab(t, AccountFlagBids, testBids)})
+	srv.SetAccount(market.Asks, &rpctest.Account{Lamports: 1, Owner: DEXProgramIDV3, Data: encodeSlab(t, AccountFlagAsks, testAsks)})
+
+	var updating int32
+	errDone := errors.New("done")
+	var diffs []*BookDiff
+	err = WatchBook(ctx, rpc.New(srv.URL()), wsClient, marketAddr, rpc.CommitmentProcessed, func(market *MarketMeta, book *Book, diff *BookDiff) error {
+		require.Equal(t, uint8(2), market.BaseMint.Decimals)
+		diffs = append(diffs, diff)
+		if len(diffs) == 2 {
+			require.Len(t, book.Bids, 3)
+			return errDone
+		}
+		if atomic.CompareAndSwapInt32(&updating, 0, 1) {
+			// Update the bids until the book changes, as the subscriptions
+			// are asynchronous.
+			go func() {
+				data := encodeSlab(t, AccountFlagBids, testBidsNext)
+				for ctx.Err() == nil {
+					srv.SetAccount(market.MarketV2.Bids, &rpctest.Account{Lamports: 1, Owner: DEXProgramIDV3, Data: data})
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, errDone)
+	require.Equal(t, [][3]uint64{{SideBid, 10, 8}, {SideBid, 9, 4}, {SideAsk, 11, 2}}, levelPrices(diffs[0].Levels))
+	require.Equal(t, [][3]uint64{{SideBid, 10, 1}, {SideBid, 8, 7}}, levelPrices(diffs[1].Levels))
+}
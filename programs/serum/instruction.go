@@ -20,6 +20,8 @@ package serum
 import (
 	"encoding/binary"
 	"fmt"
+	"reflect"
+	"unicode"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -81,6 +83,16 @@ type Instruction struct {
 
 var _ bin.EncoderDecoder = &Instruction{}
 
+func (i *Instruction) ProgramName() string {
+	return "Serum"
+}
+
+// InstructionName returns the variant name in PascalCase (e.g. "NewOrderV3").
+func (i *Instruction) InstructionName() string {
+	_, name, _ := i.Obtain(InstructionDefVariant)
+	return bin.ToPascalCase(name)
+}
+
 func (i *Instruction) TextEncode(encoder *text.Encoder, option *text.Option) error {
 	return encoder.Encode(i.Impl, option)
 }
@@ -614,3 +626,104 @@ func (i *InstructionSendTake) SetAccounts(accounts []*solana.AccountMeta) error
 	}
 	return nil
 }
+
+// AccountNames returns the names of the accounts, in order. The accounts 1
+// to 4 (the queues and the order book) are not kept by SetAccounts.
+func (i InstructionInitializeMarket) AccountNames() []string {
+	return []string{"market", "requestQueue", "eventQueue", "bids", "asks", "splCoinToken", "splPriceToken", "coinMint", "priceMint"}
+}
+
+func (i InstructionNewOrder) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionMatchOrder) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionConsumeEvents) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionCancelOrder) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionSettleFunds) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionCancelOrderByClientId) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionDisableMarketAccounts) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionSweepFees) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionNewOrderV2) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionNewOrderV3) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionCancelOrderV2) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionCancelOrderByClientIdV2) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+func (i InstructionSendTake) AccountNames() []string {
+	return accountNames(i.Accounts)
+}
+
+// accountNames names the accounts after the fields of an accounts struct,
+// in order: slices are expanded as name[i], and the names stop at the first
+// optional account that is not set.
+func accountNames(accounts interface{}) (names []string) {
+	v := reflect.ValueOf(accounts)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	v = v.Elem()
+	for f := 0; f < v.NumField(); f++ {
+		name := lowerCamel(v.Type().Field(f).Name)
+		field := v.Field(f)
+		switch field.Kind() {
+		case reflect.Slice:
+			for idx := 0; idx < field.Len(); idx++ {
+				names = append(names, fmt.Sprintf("%s[%d]", name, idx))
+			}
+		case reflect.Ptr:
+			if field.IsNil() {
+				return names
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// lowerCamel lowers the leading capitals of a field name, keeping the last
+// one of an acronym followed by a word (e.g. SPLTokenProgram is splTokenProgram).
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for idx := range runes {
+		if !unicode.IsUpper(runes[idx]) {
+			break
+		}
+		if idx > 0 && idx+1 < len(runes) && unicode.IsLower(runes[idx+1]) {
+			break
+		}
+		runes[idx] = unicode.ToLower(runes[idx])
+	}
+	return string(runes)
+}
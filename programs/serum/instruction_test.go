@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -87,3 +88,24 @@ func TestDecodeInstruction(t *testing.T) {
 		})
 	}
 }
+
+func TestInstruction_AccountNames(t *testing.T) {
+	meta := func() *solana.AccountMeta { return solana.Meta(solana.NewWallet().PublicKey()) }
+
+	consume := InstructionConsumeEvents{Accounts: &ConsumeEventsAccounts{
+		OpenOrders:        []*solana.AccountMeta{meta(), meta()},
+		Market:            meta(),
+		EventQueue:        meta(),
+		CoinFeeReceivable: meta(),
+		PCFeeReceivable:   meta(),
+	}}
+	assert.Equal(t, []string{"openOrders[0]", "openOrders[1]", "market", "eventQueue", "coinFeeReceivable", "pcFeeReceivable"}, consume.AccountNames())
+
+	settle := InstructionSettleFunds{Accounts: &SettleFundsAccounts{
+		Market: meta(), OpenOrders: meta(), Owner: meta(), CoinVault: meta(), PCVault: meta(),
+		CoinWallet: meta(), PCWallet: meta(), Signer: meta(), SPLTokenProgram: meta(),
+	}}
+	assert.Equal(t, []string{"market", "openOrders", "owner", "coinVault", "pcVault", "coinWallet", "pcWallet", "signer", "splTokenProgram"}, settle.AccountNames())
+
+	assert.Equal(t, "NewOrderV3", (&Instruction{BaseVariant: bin.BaseVariant{TypeID: bin.TypeIDFromUint32(10, binary.LittleEndian)}}).InstructionName())
+}
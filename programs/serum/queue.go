@@ -18,6 +18,8 @@
 package serum
 
 import (
+	"fmt"
+	"math"
 	"strings"
 
 	bin "github.com/gagliardetto/binary"
@@ -218,10 +220,18 @@ func (q *EventQueue) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
 	if err = decoder.Decode(&q.SeqNum); err != nil {
 		return err
 	}
+	if !q.AccountFlags.Is(AccountFlagEventQueue) {
+		return fmt.Errorf("not an event queue account: %s", q.AccountFlags.String())
+	}
 
 	ringbufStartByte := decoder.Position()
-	ringbufByteSize := uint(decoder.Remaining() - 7)
-	ringbugLength := ringbufByteSize / EVENT_BYTE_SIZE
+	ringbugLength, err := eventQueueCapacity(decoder.Remaining())
+	if err != nil {
+		return err
+	}
+	if uint(q.Count) > ringbugLength {
+		return fmt.Errorf("event queue count %d exceeds its capacity %d", q.Count, ringbugLength)
+	}
 
 	q.Events = make([]*Event, q.Count)
 
@@ -240,6 +250,104 @@ func (q *EventQueue) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
 	return nil
 }
 
+// FirstSeqNum returns the sequence number of the first event of the queue,
+// the oldest event not consumed yet.
+func (q *EventQueue) FirstSeqNum() uint64 {
+	return uint64(q.SeqNum) - uint64(q.Count)
+}
+
+// eventQueueCapacity returns the number of events of the ring buffer of an
+// event queue, given the size of the data after its header.
+func eventQueueCapacity(remaining int) (uint, error) {
+	if remaining < 7 {
+		return 0, fmt.Errorf("event queue data too short")
+	}
+	capacity := uint(remaining-7) / EVENT_BYTE_SIZE
+	if capacity == 0 {
+		return 0, fmt.Errorf("event queue has no room for events")
+	}
+	return capacity, nil
+}
+
+// EventBatch is the events appended to an event queue from a sequence
+// number.
+type EventBatch struct {
+	// The sequence number of the first event of the batch.
+	SeqNum uint64
+
+	Events []*Event
+
+	// The number of events appended from the sequence number that were
+	// already overwritten in the ring buffer, before the first event of the
+	// batch.
+	Missed uint64
+}
+
+// NextSeqNum returns the sequence number following the last event of the
+// batch.
+func (b *EventBatch) NextSeqNum() uint64 {
+	return b.SeqNum + uint64(len(b.Events))
+}
+
+// DecodeEventsSince decodes the events appended to the event queue of the
+// data from the sequence number fromSeqNum, included, whether or not they
+// have been consumed since. The ring buffer only keeps the latest events, as
+// many as its capacity: older events are counted as missed.
+//
+// Sequence numbers wrap around; a queue whose sequence number is behind
+// fromSeqNum (e.g. from a lagging RPC node) has no new events.
+func DecodeEventsSince(data []byte, fromSeqNum uint64) (*EventBatch, error) {
+	decoder := bin.NewBinDecoder(data)
+	if err := decoder.SkipBytes(5); err != nil {
+		return nil, err
+	}
+	var header struct {
+		AccountFlags AccountFlag
+		Head         bin.Uint64
+		Count        bin.Uint64
+		SeqNum       bin.Uint64
+	}
+	if err := decoder.Decode(&header); err != nil {
+		return nil, fmt.Errorf("unable to decode event queue header: %w", err)
+	}
+	if !header.AccountFlags.Is(AccountFlagEventQueue) {
+		return nil, fmt.Errorf("not an event queue account: %s", header.AccountFlags.String())
+	}
+	ringbufStartByte := decoder.Position()
+	capacity, err := eventQueueCapacity(decoder.Remaining())
+	if err != nil {
+		return nil, err
+	}
+
+	seqNum := uint64(header.SeqNum)
+	newCount := seqNum - fromSeqNum
+	if newCount > math.MaxInt64 {
+		// The queue is behind fromSeqNum.
+		return &EventBatch{SeqNum: fromSeqNum}, nil
+	}
+	batch := &EventBatch{SeqNum: fromSeqNum}
+	if newCount > uint64(capacity) {
+		batch.Missed = newCount - uint64(capacity)
+		batch.SeqNum += batch.Missed
+		newCount = uint64(capacity)
+	}
+
+	// The next event is written at head+count, so the event of sequence
+	// number seqNum-i is i slots before it.
+	end := uint64(header.Head) + uint64(header.Count)
+	batch.Events = make([]*Event, newCount)
+	for i := uint64(0); i < newCount; i++ {
+		itemIndex := uint((end + uint64(capacity) - newCount + i) % uint64(capacity))
+		if err := decoder.SetPosition(ringbufStartByte + itemIndex*EVENT_BYTE_SIZE); err != nil {
+			return nil, err
+		}
+		if err := decoder.Decode(&batch.Events[i]); err != nil {
+			return nil, fmt.Errorf("unable to decode event %d: %w", batch.SeqNum+i, err)
+		}
+	}
+	return batch, nil
+}
+
 // TODO: fill up later
 func (q EventQueue) MarshalWithEncoder(encoder *bin.Encoder) error {
 	return nil
@@ -336,4 +444,22 @@ func (e *Event) Filled() bool {
 	return Has(uint8(e.Flag), uint8(EventFlagFill))
 }
 
+// FillQuantities returns the native quantities of base and quote tokens
+// exchanged by a fill event, with the quote quantity before the fees or
+// rebates.
+func (e *Event) FillQuantities() (base, quote uint64) {
+	if e.Flag.IsBid() {
+		// Bought base, paying quote.
+		if e.Flag.IsMaker() {
+			return e.NativeQtyReleased, e.NativeQtyPaid + e.NativeFeeOrRebate
+		}
+		return e.NativeQtyReleased, e.NativeQtyPaid - e.NativeFeeOrRebate
+	}
+	// Sold base, for quote.
+	if e.Flag.IsMaker() {
+		return e.NativeQtyPaid, e.NativeQtyReleased - e.NativeFeeOrRebate
+	}
+	return e.NativeQtyPaid, e.NativeQtyReleased + e.NativeFeeOrRebate
+}
+
 func Has(b, flag uint8) bool { return b&flag == flag }
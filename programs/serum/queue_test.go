@@ -18,8 +18,11 @@
 package serum
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
+	"math"
 	"os"
 	"strings"
 	"testing"
@@ -172,3 +175,96 @@ func TestDecoder_EventQueue_DiffManual(t *testing.T) {
 		}
 	}))
 }
+
+// encodeEventQueue encodes an event queue of the capacity, with the events
+// of sequence numbers from seqNum-len(events) at their slot of the ring.
+func encodeEventQueue(t *testing.T, capacity int, head, count, seqNum uint64, events []*Event) []byte {
+	buf := new(bytes.Buffer)
+	enc := bin.NewBinEncoder(buf)
+	require.NoError(t, enc.WriteBytes([]byte("serum"), false))
+	require.NoError(t, enc.Encode(AccountFlagInitialized|AccountFlagEventQueue))
+	for _, v := range []uint64{head, count, seqNum} {
+		require.NoError(t, enc.WriteUint64(v, binary.LittleEndian))
+	}
+	ring := make([]byte, capacity*int(EVENT_BYTE_SIZE))
+	end := head + count
+	for i, event := range events {
+		eventBuf := new(bytes.Buffer)
+		require.NoError(t, bin.NewBinEncoder(eventBuf).Encode(event))
+		require.Equal(t, int(EVENT_BYTE_SIZE), eventBuf.Len())
+		slot := (end + uint64(capacity) - uint64(len(events)) + uint64(i)) % uint64(capacity)
+		copy(ring[slot*uint64(EVENT_BYTE_SIZE):], eventBuf.Bytes())
+	}
+	require.NoError(t, enc.WriteBytes(ring, false))
+	require.NoError(t, enc.WriteBytes([]byte("padding"), false))
+	return buf.Bytes()
+}
+
+func TestDecodeEventsSince(t *testing.T) {
+	var events []*Event
+	for seq := uint64(6); seq < 10; seq++ {
+		events = append(events, &Event{Flag: EventFlagFill, OwnerSlot: uint8(seq), ClientOrderID: seq})
+	}
+	// The next event goes to slot 2: the ring holds events 8 and 9 in slots
+	// 0 and 1, and events 6 and 7 in slots 2 and 3.
+	data := encodeEventQueue(t, 4, 1, 1, 10, events)
+
+	clientOrderIDs := func(batch *EventBatch) (out []uint64) {
+		for _, event := range batch.Events {
+			out = append(out, event.ClientOrderID)
+		}
+		return out
+	}
+
+	batch, err := DecodeEventsSince(data, 8)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), batch.SeqNum)
+	assert.Equal(t, []uint64{8, 9}, clientOrderIDs(batch))
+	assert.Equal(t, uint64(10), batch.NextSeqNum())
+
+	batch, err = DecodeEventsSince(data, 7)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{7, 8, 9}, clientOrderIDs(batch))
+	assert.Equal(t, uint8(7), batch.Events[0].OwnerSlot)
+
+	batch, err = DecodeEventsSince(data, 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), batch.Missed)
+	assert.Equal(t, uint64(6), batch.SeqNum)
+	assert.Equal(t, []uint64{6, 7, 8, 9}, clientOrderIDs(batch))
+
+	for _, seqNum := range []uint64{10, 11} {
+		batch, err = DecodeEventsSince(data, seqNum)
+		require.NoError(t, err)
+		assert.Empty(t, batch.Events)
+		assert.Zero(t, batch.Missed)
+	}
+
+	// Only event 9 wasn't consumed.
+	queue := &EventQueue{}
+	require.NoError(t, queue.Decode(data))
+	require.Len(t, queue.Events, 1)
+	assert.Equal(t, uint64(9), queue.FirstSeqNum())
+	assert.Equal(t, uint64(9), queue.Events[0].ClientOrderID)
+
+	// The sequence number wraps around.
+	data = encodeEventQueue(t, 4, 3, 2, 1, events[1:])
+	batch, err = DecodeEventsSince(data, math.MaxUint64-1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{7, 8, 9}, clientOrderIDs(batch))
+
+	_, err = DecodeEventsSince(data[:40], 0)
+	assert.Error(t, err)
+}
+
+func TestEvent_FillQuantities(t *testing.T) {
+	event := &Event{Flag: EventFlagFill | EventFlagBid, NativeQtyReleased: 100, NativeQtyPaid: 1004, NativeFeeOrRebate: 4}
+	base, quote := event.FillQuantities()
+	assert.Equal(t, uint64(100), base)
+	assert.Equal(t, uint64(1000), quote)
+
+	event = &Event{Flag: EventFlagFill | EventFlagMaker, NativeQtyReleased: 1001, NativeQtyPaid: 100, NativeFeeOrRebate: 1}
+	base, quote = event.FillQuantities()
+	assert.Equal(t, uint64(100), base)
+	assert.Equal(t, uint64(1000), quote)
+}
@@ -20,14 +20,13 @@ package serum
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
 	rice "github.com/GeertJohan/go.rice"
-	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/gagliardetto/solana-go/rpc/ws"
-	"go.uber.org/zap"
 )
 
 //go:generate rice embed-go
@@ -101,29 +100,113 @@ func FetchMarket(ctx context.Context, rpcCli *rpc.Client, marketAddr solana.Publ
 	return meta, nil
 }
 
-func StreamOpenOrders(ctx context.Context, client *ws.Client) error {
-	sub, err := client.ProgramSubscribe(DEXProgramIDV2, rpc.CommitmentSingleGossip)
+// FetchEventQueue fetches the event queue account at the address.
+func FetchEventQueue(ctx context.Context, rpcCli *rpc.Client, eventQueueAddr solana.PublicKey) (*EventQueue, error) {
+	acctInfo, err := rpcCli.GetAccountInfo(ctx, eventQueueAddr)
 	if err != nil {
-		return fmt.Errorf("unable to subscribe to programID %q: %w", DEXProgramIDV2, err)
+		return nil, fmt.Errorf("unable to get event queue account: %w", err)
+	}
+
+	queue := &EventQueue{}
+	if err := queue.Decode(acctInfo.Value.Data.GetBinary()); err != nil {
+		return nil, fmt.Errorf("decoding event queue: %w", err)
+	}
+	return queue, nil
+}
+
+// StreamEvents subscribes to the event queue account at the address, and
+// calls handle with the events appended to the queue at each update, until
+// the context is done or handle returns an error.
+//
+// The stream starts with the event of sequence number fromSeqNum, e.g. the
+// SeqNum of a fetched queue for the events appended after it; if fromSeqNum
+// is nil, it starts with the events not consumed yet at the first update. Events are emitted once,
+// by sequence number, whether or not the crank consumed them between two
+// updates; the batches count the events lost if the queue wrapped around
+// between two updates.
+func StreamEvents(
+	ctx context.Context,
+	client *ws.Client,
+	eventQueueAddr solana.PublicKey,
+	commitment rpc.CommitmentType,
+	fromSeqNum *uint64,
+	handle func(slot uint64, batch *EventBatch) error,
+) error {
+	sub, err := client.AccountSubscribe(eventQueueAddr, commitment)
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to event queue %q: %w", eventQueueAddr, err)
+	}
+	defer sub.Unsubscribe()
+
+	var nextSeqNum uint64
+	started := false
+	if fromSeqNum != nil {
+		nextSeqNum = *fromSeqNum
+		started = true
 	}
-	count := 0
 	for {
-		d, err := sub.Recv(ctx)
+		res, err := sub.Recv(ctx)
 		if err != nil {
-			return fmt.Errorf("received error from programID subscription: %w", err)
+			return fmt.Errorf("received error from event queue subscription: %w", err)
+		}
+		data := res.Value.Data.GetBinary()
+
+		if !started {
+			queue := &EventQueue{}
+			if err := queue.Decode(data); err != nil {
+				return fmt.Errorf("decoding event queue: %w", err)
+			}
+			nextSeqNum = queue.FirstSeqNum()
+			started = true
 		}
-		res := d
 
-		var f *AccountFlag
-		err = bin.NewBinDecoder(res.Value.Account.Data.GetBinary()).Decode(&f)
+		batch, err := DecodeEventsSince(data, nextSeqNum)
 		if err != nil {
-			fmt.Println("***********************************", err)
-			zlog.Debug("unable to decoce account flag for account... skipping",
-				zap.Stringer("account_address", res.Value.Pubkey),
-			)
+			return fmt.Errorf("decoding event queue: %w", err)
+		}
+		if len(batch.Events) == 0 && batch.Missed == 0 {
 			continue
 		}
-		fmt.Printf("%d - %s\n", count, f.String())
-		count++
+		if err := handle(res.Context.Slot, batch); err != nil {
+			return err
+		}
+		nextSeqNum = batch.NextSeqNum()
 	}
 }
+
+// ErrEventsMissed is returned by StreamOpenOrders when events were
+// overwritten in the event queue before they could be streamed.
+var ErrEventsMissed = errors.New("events were overwritten before being streamed")
+
+// StreamOpenOrders streams the events of the event queue at eventQueueAddr
+// (see StreamEvents) that concern the open orders account at openOrdersAddr,
+// calling handle with each of them and its sequence number, until the context
+// is done or handle returns an error.
+//
+// As the events of a missed range can't be checked, the stream stops
+// with ErrEventsMissed if the queue wrapped around between two updates;
+// the events can be streamed again from the SeqNum of a fetched queue.
+func StreamOpenOrders(
+	ctx context.Context,
+	client *ws.Client,
+	eventQueueAddr solana.PublicKey,
+	openOrdersAddr solana.PublicKey,
+	commitment rpc.CommitmentType,
+	fromSeqNum *uint64,
+	handle func(slot uint64, seqNum uint64, event *Event) error,
+) error {
+	return StreamEvents(ctx, client, eventQueueAddr, commitment, fromSeqNum, func(slot uint64, batch *EventBatch) error {
+		if batch.Missed > 0 {
+			return fmt.Errorf("%d events before sequence number %d: %w", batch.Missed, batch.SeqNum, ErrEventsMissed)
+		}
+		for i, event := range batch.Events {
+			if !event.Owner.Equals(openOrdersAddr) {
+				continue
+			}
+			if err := handle(slot, batch.SeqNum+uint64(i), event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -19,13 +19,17 @@ package serum
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gagliardetto/solana-go/rpc/ws"
 
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/rpctest"
 
 	"github.com/stretchr/testify/require"
 
@@ -58,14 +62,105 @@ func TestFetchMarket(t *testing.T) {
 }
 
 func TestStreamOpenOrders(t *testing.T) {
-	rpcURL := os.Getenv("RPC_URL")
-	if rpcURL == "" {
-		t.Skip("Setup 'RPC_URL' to run test i.e. 'wss://api.mainnet-beta.solana.com'")
-		return
-	}
-	client, err := ws.Connect(context.Background(), rpcURL)
+	srv := rpctest.NewServer(nil)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := ws.Connect(ctx, srv.WSURL())
 	require.NoError(t, err)
+	defer client.Close()
+
+	openOrders := solana.NewWallet().PublicKey()
+	other := solana.NewWallet().PublicKey()
+	newEvents := func(from, to uint64) (out []*Event) {
+		for seq := from; seq < to; seq++ {
+			owner := other
+			if seq%2 == 0 {
+				owner = openOrders
+			}
+			out = append(out, &Event{Flag: EventFlagFill, Owner: owner, ClientOrderID: seq})
+		}
+		return out
+	}
+	eventQueue := solana.NewWallet().PublicKey()
+	updates := [][]byte{
+		encodeEventQueue(t, 4, 0, 4, 4, newEvents(0, 4)),
+		// Events 4 and 5 are overwritten before being streamed.
+		encodeEventQueue(t, 4, 2, 4, 10, newEvents(6, 10)),
+	}
 
-	err = StreamOpenOrders(context.Background(), client)
+	var update int32
+	go func() {
+		for ctx.Err() == nil {
+			data := updates[atomic.LoadInt32(&update)]
+			srv.SetAccount(eventQueue, &rpctest.Account{Lamports: 1, Owner: DEXProgramIDV3, Data: data})
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	var seqNums, clientOrderIDs []uint64
+	fromSeqNum := uint64(0)
+	err = StreamOpenOrders(ctx, client, eventQueue, openOrders, rpc.CommitmentProcessed, &fromSeqNum, func(slot uint64, seqNum uint64, event *Event) error {
+		seqNums = append(seqNums, seqNum)
+		clientOrderIDs = append(clientOrderIDs, event.ClientOrderID)
+		atomic.StoreInt32(&update, 1)
+		return nil
+	})
+	require.ErrorIs(t, err, ErrEventsMissed)
+	require.Equal(t, []uint64{0, 2}, seqNums)
+	require.Equal(t, []uint64{0, 2}, clientOrderIDs)
+}
+
+func TestStreamEvents(t *testing.T) {
+	srv := rpctest.NewServer(nil)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := ws.Connect(ctx, srv.WSURL())
 	require.NoError(t, err)
+	defer client.Close()
+
+	var events []*Event
+	for seq := uint64(6); seq < 12; seq++ {
+		events = append(events, &Event{Flag: EventFlagFill, ClientOrderID: seq})
+	}
+	eventQueue := solana.NewWallet().PublicKey()
+	updates := [][]byte{
+		encodeEventQueue(t, 4, 1, 1, 10, events[:4]),
+		// Events 10 and 11 overwrite events 6 and 7, which were consumed.
+		encodeEventQueue(t, 4, 2, 2, 12, events[2:]),
+	}
+
+	var update int32
+	go func() {
+		// Update the account until the stream gets the events of the update,
+		// as the stream subscribes asynchronously.
+		for ctx.Err() == nil {
+			data := updates[atomic.LoadInt32(&update)]
+			srv.SetAccount(eventQueue, &rpctest.Account{Lamports: 1, Owner: DEXProgramIDV3, Data: data})
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	errDone := errors.New("done")
+	var batches []*EventBatch
+	fromSeqNum := uint64(7)
+	err = StreamEvents(ctx, client, eventQueue, rpc.CommitmentProcessed, &fromSeqNum, func(slot uint64, batch *EventBatch) error {
+		batches = append(batches, batch)
+		if len(batches) == len(updates) {
+			return errDone
+		}
+		atomic.StoreInt32(&update, int32(len(batches)))
+		return nil
+	})
+	require.ErrorIs(t, err, errDone)
+
+	var clientOrderIDs []uint64
+	for _, batch := range batches {
+		require.Zero(t, batch.Missed)
+		for _, event := range batch.Events {
+			clientOrderIDs = append(clientOrderIDs, event.ClientOrderID)
+		}
+	}
+	require.Equal(t, []uint64{7, 8, 9, 10, 11}, clientOrderIDs)
 }
@@ -0,0 +1,410 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Adds a validator to the pool, with a new stake account funded by the
+// reserve and delegated to its vote account.
+type AddValidatorToPool struct {
+	// The seed of the validator stake account; zero for none.
+	ValidatorSeed *uint32
+
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] staker
+	// ··········· The staker of the stake pool.
+	//
+	// [2] = [WRITE] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [3] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [4] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [5] = [WRITE] validatorStake
+	// ··········· The stake account of the validator, at FindValidatorStakeAddress.
+	//
+	// [6] = [] validatorVote
+	// ··········· The vote account of the validator.
+	//
+	// [7] = [] rent
+	// ··········· The rent sysvar.
+	//
+	// [8] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [9] = [] stakeHistory
+	// ··········· The stake history sysvar.
+	//
+	// [10] = [] stakeConfig
+	// ··········· The stake config account.
+	//
+	// [11] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [12] = [] stakeProgram
+	// ··········· The stake program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewAddValidatorToPoolInstructionBuilder creates a new `AddValidatorToPool` instruction builder.
+func NewAddValidatorToPoolInstructionBuilder() *AddValidatorToPool {
+	nd := &AddValidatorToPool{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 13),
+	}
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.SysVarRentPubkey)
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.SysVarStakeHistoryPubkey)
+	nd.AccountMetaSlice[10] = ag_solanago.Meta(ag_solanago.SysVarStakeConfigPubkey)
+	nd.AccountMetaSlice[11] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	nd.AccountMetaSlice[12] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	return nd
+}
+
+// SetValidatorSeed sets the "validatorSeed" parameter.
+// The seed of the validator stake account; zero for none.
+func (inst *AddValidatorToPool) SetValidatorSeed(validatorSeed uint32) *AddValidatorToPool {
+	inst.ValidatorSeed = &validatorSeed
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *AddValidatorToPool) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *AddValidatorToPool) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetStakerAccount sets the "staker" account.
+// The staker of the stake pool.
+func (inst *AddValidatorToPool) SetStakerAccount(staker ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(staker).SIGNER()
+	return inst
+}
+
+// GetStakerAccount gets the "staker" account.
+// The staker of the stake pool.
+func (inst *AddValidatorToPool) GetStakerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *AddValidatorToPool) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(reserveStake).WRITE()
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *AddValidatorToPool) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *AddValidatorToPool) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *AddValidatorToPool) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *AddValidatorToPool) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *AddValidatorToPool) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetValidatorStakeAccount sets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *AddValidatorToPool) SetValidatorStakeAccount(validatorStake ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(validatorStake).WRITE()
+	return inst
+}
+
+// GetValidatorStakeAccount gets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *AddValidatorToPool) GetValidatorStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetValidatorVoteAccount sets the "validatorVote" account.
+// The vote account of the validator.
+func (inst *AddValidatorToPool) SetValidatorVoteAccount(validatorVote ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(validatorVote)
+	return inst
+}
+
+// GetValidatorVoteAccount gets the "validatorVote" account.
+// The vote account of the validator.
+func (inst *AddValidatorToPool) GetValidatorVoteAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetRentAccount sets the "rent" account.
+// The rent sysvar.
+func (inst *AddValidatorToPool) SetRentAccount(rent ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(rent)
+	return inst
+}
+
+// GetRentAccount gets the "rent" account.
+// The rent sysvar.
+func (inst *AddValidatorToPool) GetRentAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *AddValidatorToPool) SetClockAccount(clock ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *AddValidatorToPool) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetStakeHistoryAccount sets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *AddValidatorToPool) SetStakeHistoryAccount(stakeHistory ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(stakeHistory)
+	return inst
+}
+
+// GetStakeHistoryAccount gets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *AddValidatorToPool) GetStakeHistoryAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetStakeConfigAccount sets the "stakeConfig" account.
+// The stake config account.
+func (inst *AddValidatorToPool) SetStakeConfigAccount(stakeConfig ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(stakeConfig)
+	return inst
+}
+
+// GetStakeConfigAccount gets the "stakeConfig" account.
+// The stake config account.
+func (inst *AddValidatorToPool) GetStakeConfigAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *AddValidatorToPool) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[11] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *AddValidatorToPool) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(11)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *AddValidatorToPool) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *AddValidatorToPool {
+	inst.AccountMetaSlice[12] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *AddValidatorToPool) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(12)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst AddValidatorToPool) AccountNames() []string {
+	return []string{"stakePool", "staker", "reserveStake", "withdrawAuthority", "validatorList", "validatorStake", "validatorVote", "rent", "clock", "stakeHistory", "stakeConfig", "systemProgram", "stakeProgram"}
+}
+
+func (inst AddValidatorToPool) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_AddValidatorToPool),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst AddValidatorToPool) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *AddValidatorToPool) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.ValidatorSeed == nil {
+			return errors.New("ValidatorSeed parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Staker is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.ValidatorStake is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.ValidatorVote is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.Rent is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.StakeHistory is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.StakeConfig is not set")
+		}
+		if inst.AccountMetaSlice[11] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+		if inst.AccountMetaSlice[12] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *AddValidatorToPool) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("AddValidatorToPool")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("ValidatorSeed", inst.ValidatorSeed))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("           staker", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("     reserveStake", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("withdrawAuthority", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("    validatorList", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("   validatorStake", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("    validatorVote", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("             rent", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("            clock", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("     stakeHistory", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("      stakeConfig", inst.AccountMetaSlice.Get(10)))
+						accountsBranch.Child(ag_format.Meta("    systemProgram", inst.AccountMetaSlice.Get(11)))
+						accountsBranch.Child(ag_format.Meta("     stakeProgram", inst.AccountMetaSlice.Get(12)))
+					})
+				})
+		})
+}
+
+func (obj AddValidatorToPool) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `ValidatorSeed` param:
+	err = encoder.Encode(obj.ValidatorSeed)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *AddValidatorToPool) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `ValidatorSeed`:
+	err = decoder.Decode(&obj.ValidatorSeed)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewAddValidatorToPoolInstruction declares a new AddValidatorToPool instruction with the provided parameters and accounts.
+func NewAddValidatorToPoolInstruction(
+	// Parameters:
+	validatorSeed uint32,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	staker ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	validatorStake ag_solanago.PublicKey,
+	validatorVote ag_solanago.PublicKey,
+) *AddValidatorToPool {
+	return NewAddValidatorToPoolInstructionBuilder().
+		SetValidatorSeed(validatorSeed).
+		SetStakePoolAccount(stakePool).
+		SetStakerAccount(staker).
+		SetReserveStakeAccount(reserveStake).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetValidatorListAccount(validatorList).
+		SetValidatorStakeAccount(validatorStake).
+		SetValidatorVoteAccount(validatorVote)
+}
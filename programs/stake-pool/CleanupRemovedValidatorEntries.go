@@ -0,0 +1,142 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Removes the validators ready for removal from the validator list.
+type CleanupRemovedValidatorEntries struct {
+	// [0] = [] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewCleanupRemovedValidatorEntriesInstructionBuilder creates a new `CleanupRemovedValidatorEntries` instruction builder.
+func NewCleanupRemovedValidatorEntriesInstructionBuilder() *CleanupRemovedValidatorEntries {
+	nd := &CleanupRemovedValidatorEntries{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 2),
+	}
+	return nd
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *CleanupRemovedValidatorEntries) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *CleanupRemovedValidatorEntries {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool)
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *CleanupRemovedValidatorEntries) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *CleanupRemovedValidatorEntries) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *CleanupRemovedValidatorEntries {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *CleanupRemovedValidatorEntries) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst CleanupRemovedValidatorEntries) AccountNames() []string {
+	return []string{"stakePool", "validatorList"}
+}
+
+func (inst CleanupRemovedValidatorEntries) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_CleanupRemovedValidatorEntries),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst CleanupRemovedValidatorEntries) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *CleanupRemovedValidatorEntries) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *CleanupRemovedValidatorEntries) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("CleanupRemovedValidatorEntries")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("    stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("validatorList", inst.AccountMetaSlice.Get(1)))
+					})
+				})
+		})
+}
+
+func (obj CleanupRemovedValidatorEntries) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *CleanupRemovedValidatorEntries) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewCleanupRemovedValidatorEntriesInstruction declares a new CleanupRemovedValidatorEntries instruction with the provided parameters and accounts.
+func NewCleanupRemovedValidatorEntriesInstruction(
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+) *CleanupRemovedValidatorEntries {
+	return NewCleanupRemovedValidatorEntriesInstructionBuilder().
+		SetStakePoolAccount(stakePool).
+		SetValidatorListAccount(validatorList)
+}
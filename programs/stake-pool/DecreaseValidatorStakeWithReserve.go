@@ -0,0 +1,393 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Moves stake of a validator to its transient stake account, deactivated,
+// with the rent of the transient account funded by the reserve.
+type DecreaseValidatorStakeWithReserve struct {
+	// The lamports to move from the validator.
+	Lamports *uint64
+	// The seed of the transient stake account.
+	TransientStakeSeed *uint64
+
+	// [0] = [] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] staker
+	// ··········· The staker of the stake pool.
+	//
+	// [2] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [3] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [4] = [WRITE] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [5] = [WRITE] validatorStake
+	// ··········· The stake account of the validator, at FindValidatorStakeAddress.
+	//
+	// [6] = [WRITE] transientStake
+	// ··········· The transient stake account of the validator, at FindTransientStakeAddress.
+	//
+	// [7] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [8] = [] stakeHistory
+	// ··········· The stake history sysvar.
+	//
+	// [9] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [10] = [] stakeProgram
+	// ··········· The stake program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewDecreaseValidatorStakeWithReserveInstructionBuilder creates a new `DecreaseValidatorStakeWithReserve` instruction builder.
+func NewDecreaseValidatorStakeWithReserveInstructionBuilder() *DecreaseValidatorStakeWithReserve {
+	nd := &DecreaseValidatorStakeWithReserve{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 11),
+	}
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SysVarStakeHistoryPubkey)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	nd.AccountMetaSlice[10] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	return nd
+}
+
+// SetLamports sets the "lamports" parameter.
+// The lamports to move from the validator.
+func (inst *DecreaseValidatorStakeWithReserve) SetLamports(lamports uint64) *DecreaseValidatorStakeWithReserve {
+	inst.Lamports = &lamports
+	return inst
+}
+
+// SetTransientStakeSeed sets the "transientStakeSeed" parameter.
+// The seed of the transient stake account.
+func (inst *DecreaseValidatorStakeWithReserve) SetTransientStakeSeed(transientStakeSeed uint64) *DecreaseValidatorStakeWithReserve {
+	inst.TransientStakeSeed = &transientStakeSeed
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool)
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetStakerAccount sets the "staker" account.
+// The staker of the stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) SetStakerAccount(staker ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(staker).SIGNER()
+	return inst
+}
+
+// GetStakerAccount gets the "staker" account.
+// The staker of the stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) GetStakerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *DecreaseValidatorStakeWithReserve) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *DecreaseValidatorStakeWithReserve) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(reserveStake).WRITE()
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *DecreaseValidatorStakeWithReserve) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetValidatorStakeAccount sets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *DecreaseValidatorStakeWithReserve) SetValidatorStakeAccount(validatorStake ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(validatorStake).WRITE()
+	return inst
+}
+
+// GetValidatorStakeAccount gets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *DecreaseValidatorStakeWithReserve) GetValidatorStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetTransientStakeAccount sets the "transientStake" account.
+// The transient stake account of the validator, at FindTransientStakeAddress.
+func (inst *DecreaseValidatorStakeWithReserve) SetTransientStakeAccount(transientStake ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(transientStake).WRITE()
+	return inst
+}
+
+// GetTransientStakeAccount gets the "transientStake" account.
+// The transient stake account of the validator, at FindTransientStakeAddress.
+func (inst *DecreaseValidatorStakeWithReserve) GetTransientStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *DecreaseValidatorStakeWithReserve) SetClockAccount(clock ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *DecreaseValidatorStakeWithReserve) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetStakeHistoryAccount sets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *DecreaseValidatorStakeWithReserve) SetStakeHistoryAccount(stakeHistory ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(stakeHistory)
+	return inst
+}
+
+// GetStakeHistoryAccount gets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *DecreaseValidatorStakeWithReserve) GetStakeHistoryAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *DecreaseValidatorStakeWithReserve) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *DecreaseValidatorStakeWithReserve) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *DecreaseValidatorStakeWithReserve) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *DecreaseValidatorStakeWithReserve {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *DecreaseValidatorStakeWithReserve) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst DecreaseValidatorStakeWithReserve) AccountNames() []string {
+	return []string{"stakePool", "staker", "withdrawAuthority", "validatorList", "reserveStake", "validatorStake", "transientStake", "clock", "stakeHistory", "systemProgram", "stakeProgram"}
+}
+
+func (inst DecreaseValidatorStakeWithReserve) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_DecreaseValidatorStakeWithReserve),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst DecreaseValidatorStakeWithReserve) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *DecreaseValidatorStakeWithReserve) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Lamports == nil {
+			return errors.New("Lamports parameter is not set")
+		}
+		if inst.TransientStakeSeed == nil {
+			return errors.New("TransientStakeSeed parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Staker is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.ValidatorStake is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.TransientStake is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.StakeHistory is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *DecreaseValidatorStakeWithReserve) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("DecreaseValidatorStakeWithReserve")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("          Lamports", inst.Lamports))
+						paramsBranch.Child(ag_format.Param("TransientStakeSeed", inst.TransientStakeSeed))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("           staker", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("withdrawAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("    validatorList", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("     reserveStake", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("   validatorStake", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("   transientStake", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("            clock", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("     stakeHistory", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("    systemProgram", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("     stakeProgram", inst.AccountMetaSlice.Get(10)))
+					})
+				})
+		})
+}
+
+func (obj DecreaseValidatorStakeWithReserve) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Lamports` param:
+	err = encoder.Encode(obj.Lamports)
+	if err != nil {
+		return err
+	}
+	// Serialize `TransientStakeSeed` param:
+	err = encoder.Encode(obj.TransientStakeSeed)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *DecreaseValidatorStakeWithReserve) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Lamports`:
+	err = decoder.Decode(&obj.Lamports)
+	if err != nil {
+		return err
+	}
+	// Deserialize `TransientStakeSeed`:
+	err = decoder.Decode(&obj.TransientStakeSeed)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewDecreaseValidatorStakeWithReserveInstruction declares a new DecreaseValidatorStakeWithReserve instruction with the provided parameters and accounts.
+func NewDecreaseValidatorStakeWithReserveInstruction(
+	// Parameters:
+	lamports uint64,
+	transientStakeSeed uint64,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	staker ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	validatorStake ag_solanago.PublicKey,
+	transientStake ag_solanago.PublicKey,
+) *DecreaseValidatorStakeWithReserve {
+	return NewDecreaseValidatorStakeWithReserveInstructionBuilder().
+		SetLamports(lamports).
+		SetTransientStakeSeed(transientStakeSeed).
+		SetStakePoolAccount(stakePool).
+		SetStakerAccount(staker).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetValidatorListAccount(validatorList).
+		SetReserveStakeAccount(reserveStake).
+		SetValidatorStakeAccount(validatorStake).
+		SetTransientStakeAccount(transientStake)
+}
@@ -0,0 +1,364 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Deposits lamports into the reserve of a stake pool, for pool tokens.
+type DepositSol struct {
+	// The amount of lamports to deposit.
+	Lamports *uint64
+
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [2] = [WRITE] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [3] = [WRITE, SIGNER] lamportsFrom
+	// ··········· The system account of the deposited lamports.
+	//
+	// [4] = [WRITE] destinationPoolTokens
+	// ··········· The pool token account receiving the pool tokens.
+	//
+	// [5] = [WRITE] managerFee
+	// ··········· The pool token account of the manager receiving the fees.
+	//
+	// [6] = [WRITE] referralPoolTokens
+	// ··········· The pool token account of the referrer receiving the referral fee.
+	//
+	// [7] = [WRITE] poolMint
+	// ··········· The pool token mint.
+	//
+	// [8] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [9] = [] tokenProgram
+	// ··········· The token program of the pool mint.
+	//
+	// [10] = [SIGNER] solDepositAuthority
+	// ··········· The SOL deposit authority, if the stake pool has one.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewDepositSolInstructionBuilder creates a new `DepositSol` instruction builder.
+func NewDepositSolInstructionBuilder() *DepositSol {
+	nd := &DepositSol{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 11),
+	}
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetLamports sets the "lamports" parameter.
+// The amount of lamports to deposit.
+func (inst *DepositSol) SetLamports(lamports uint64) *DepositSol {
+	inst.Lamports = &lamports
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *DepositSol) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *DepositSol) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *DepositSol) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *DepositSol) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *DepositSol) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(reserveStake).WRITE()
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *DepositSol) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetLamportsFromAccount sets the "lamportsFrom" account.
+// The system account of the deposited lamports.
+func (inst *DepositSol) SetLamportsFromAccount(lamportsFrom ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(lamportsFrom).WRITE().SIGNER()
+	return inst
+}
+
+// GetLamportsFromAccount gets the "lamportsFrom" account.
+// The system account of the deposited lamports.
+func (inst *DepositSol) GetLamportsFromAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetDestinationPoolTokensAccount sets the "destinationPoolTokens" account.
+// The pool token account receiving the pool tokens.
+func (inst *DepositSol) SetDestinationPoolTokensAccount(destinationPoolTokens ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(destinationPoolTokens).WRITE()
+	return inst
+}
+
+// GetDestinationPoolTokensAccount gets the "destinationPoolTokens" account.
+// The pool token account receiving the pool tokens.
+func (inst *DepositSol) GetDestinationPoolTokensAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetManagerFeeAccount sets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *DepositSol) SetManagerFeeAccount(managerFee ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(managerFee).WRITE()
+	return inst
+}
+
+// GetManagerFeeAccount gets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *DepositSol) GetManagerFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetReferralPoolTokensAccount sets the "referralPoolTokens" account.
+// The pool token account of the referrer receiving the referral fee.
+func (inst *DepositSol) SetReferralPoolTokensAccount(referralPoolTokens ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(referralPoolTokens).WRITE()
+	return inst
+}
+
+// GetReferralPoolTokensAccount gets the "referralPoolTokens" account.
+// The pool token account of the referrer receiving the referral fee.
+func (inst *DepositSol) GetReferralPoolTokensAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The pool token mint.
+func (inst *DepositSol) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The pool token mint.
+func (inst *DepositSol) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *DepositSol) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *DepositSol) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *DepositSol) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *DepositSol) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetSolDepositAuthorityAccount sets the "solDepositAuthority" account.
+// The SOL deposit authority, if the stake pool has one.
+func (inst *DepositSol) SetSolDepositAuthorityAccount(solDepositAuthority ag_solanago.PublicKey) *DepositSol {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(solDepositAuthority).SIGNER()
+	return inst
+}
+
+// GetSolDepositAuthorityAccount gets the "solDepositAuthority" account.
+// The SOL deposit authority, if the stake pool has one.
+func (inst *DepositSol) GetSolDepositAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst DepositSol) AccountNames() []string {
+	return []string{"stakePool", "withdrawAuthority", "reserveStake", "lamportsFrom", "destinationPoolTokens", "managerFee", "referralPoolTokens", "poolMint", "systemProgram", "tokenProgram", "solDepositAuthority"}
+}
+
+func (inst DepositSol) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_DepositSol),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst DepositSol) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *DepositSol) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Lamports == nil {
+			return errors.New("Lamports parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.LamportsFrom is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.DestinationPoolTokens is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.ManagerFee is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.ReferralPoolTokens is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *DepositSol) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("DepositSol")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("Lamports", inst.Lamports))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("            stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("    withdrawAuthority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("         reserveStake", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("         lamportsFrom", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("destinationPoolTokens", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("           managerFee", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("   referralPoolTokens", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("             poolMint", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("        systemProgram", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("         tokenProgram", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("  solDepositAuthority", inst.AccountMetaSlice.Get(10)))
+					})
+				})
+		})
+}
+
+func (obj DepositSol) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Lamports` param:
+	err = encoder.Encode(obj.Lamports)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *DepositSol) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Lamports`:
+	err = decoder.Decode(&obj.Lamports)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewDepositSolInstruction declares a new DepositSol instruction with the provided parameters and accounts.
+func NewDepositSolInstruction(
+	// Parameters:
+	lamports uint64,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	lamportsFrom ag_solanago.PublicKey,
+	destinationPoolTokens ag_solanago.PublicKey,
+	managerFee ag_solanago.PublicKey,
+	referralPoolTokens ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+) *DepositSol {
+	return NewDepositSolInstructionBuilder().
+		SetLamports(lamports).
+		SetStakePoolAccount(stakePool).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetReserveStakeAccount(reserveStake).
+		SetLamportsFromAccount(lamportsFrom).
+		SetDestinationPoolTokensAccount(destinationPoolTokens).
+		SetManagerFeeAccount(managerFee).
+		SetReferralPoolTokensAccount(referralPoolTokens).
+		SetPoolMintAccount(poolMint)
+}
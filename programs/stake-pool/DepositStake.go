@@ -0,0 +1,430 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Deposits an active stake account delegated to a validator of the pool, for
+// pool tokens; the staker and withdrawer of the stake account must first be
+// set to the deposit authority (see NewDepositStakeInstructions).
+type DepositStake struct {
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [2] = [] depositAuthority
+	// ··········· The deposit authority of the stake pool, a signer if it is not the default FindDepositAuthorityAddress(stakePool).
+	//
+	// [3] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [4] = [WRITE] depositStake
+	// ··········· The stake account to deposit.
+	//
+	// [5] = [WRITE] validatorStake
+	// ··········· The stake account of the validator, at FindValidatorStakeAddress.
+	//
+	// [6] = [WRITE] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [7] = [WRITE] destinationPoolTokens
+	// ··········· The pool token account receiving the pool tokens.
+	//
+	// [8] = [WRITE] managerFee
+	// ··········· The pool token account of the manager receiving the fees.
+	//
+	// [9] = [WRITE] referralPoolTokens
+	// ··········· The pool token account of the referrer receiving the referral fee.
+	//
+	// [10] = [WRITE] poolMint
+	// ··········· The pool token mint.
+	//
+	// [11] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [12] = [] stakeHistory
+	// ··········· The stake history sysvar.
+	//
+	// [13] = [] tokenProgram
+	// ··········· The token program of the pool mint.
+	//
+	// [14] = [] stakeProgram
+	// ··········· The stake program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewDepositStakeInstructionBuilder creates a new `DepositStake` instruction builder.
+func NewDepositStakeInstructionBuilder() *DepositStake {
+	nd := &DepositStake{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 15),
+	}
+	nd.AccountMetaSlice[11] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[12] = ag_solanago.Meta(ag_solanago.SysVarStakeHistoryPubkey)
+	nd.AccountMetaSlice[13] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	nd.AccountMetaSlice[14] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	return nd
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *DepositStake) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *DepositStake) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *DepositStake) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *DepositStake) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetDepositAuthorityAccount sets the "depositAuthority" account.
+// The deposit authority of the stake pool, a signer if it is not the default FindDepositAuthorityAddress(stakePool).
+func (inst *DepositStake) SetDepositAuthorityAccount(depositAuthority ag_solanago.PublicKey, isSigner bool) *DepositStake {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(depositAuthority)
+	if isSigner {
+		inst.AccountMetaSlice[2].SIGNER()
+	}
+	return inst
+}
+
+// GetDepositAuthorityAccount gets the "depositAuthority" account.
+// The deposit authority of the stake pool, a signer if it is not the default FindDepositAuthorityAddress(stakePool).
+func (inst *DepositStake) GetDepositAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *DepositStake) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *DepositStake) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetDepositStakeAccount sets the "depositStake" account.
+// The stake account to deposit.
+func (inst *DepositStake) SetDepositStakeAccount(depositStake ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(depositStake).WRITE()
+	return inst
+}
+
+// GetDepositStakeAccount gets the "depositStake" account.
+// The stake account to deposit.
+func (inst *DepositStake) GetDepositStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetValidatorStakeAccount sets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *DepositStake) SetValidatorStakeAccount(validatorStake ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(validatorStake).WRITE()
+	return inst
+}
+
+// GetValidatorStakeAccount gets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *DepositStake) GetValidatorStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *DepositStake) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(reserveStake).WRITE()
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *DepositStake) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetDestinationPoolTokensAccount sets the "destinationPoolTokens" account.
+// The pool token account receiving the pool tokens.
+func (inst *DepositStake) SetDestinationPoolTokensAccount(destinationPoolTokens ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(destinationPoolTokens).WRITE()
+	return inst
+}
+
+// GetDestinationPoolTokensAccount gets the "destinationPoolTokens" account.
+// The pool token account receiving the pool tokens.
+func (inst *DepositStake) GetDestinationPoolTokensAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetManagerFeeAccount sets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *DepositStake) SetManagerFeeAccount(managerFee ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(managerFee).WRITE()
+	return inst
+}
+
+// GetManagerFeeAccount gets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *DepositStake) GetManagerFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetReferralPoolTokensAccount sets the "referralPoolTokens" account.
+// The pool token account of the referrer receiving the referral fee.
+func (inst *DepositStake) SetReferralPoolTokensAccount(referralPoolTokens ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(referralPoolTokens).WRITE()
+	return inst
+}
+
+// GetReferralPoolTokensAccount gets the "referralPoolTokens" account.
+// The pool token account of the referrer receiving the referral fee.
+func (inst *DepositStake) GetReferralPoolTokensAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The pool token mint.
+func (inst *DepositStake) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The pool token mint.
+func (inst *DepositStake) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *DepositStake) SetClockAccount(clock ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[11] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *DepositStake) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(11)
+}
+
+// SetStakeHistoryAccount sets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *DepositStake) SetStakeHistoryAccount(stakeHistory ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[12] = ag_solanago.Meta(stakeHistory)
+	return inst
+}
+
+// GetStakeHistoryAccount gets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *DepositStake) GetStakeHistoryAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(12)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *DepositStake) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[13] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *DepositStake) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(13)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *DepositStake) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *DepositStake {
+	inst.AccountMetaSlice[14] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *DepositStake) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(14)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst DepositStake) AccountNames() []string {
+	return []string{"stakePool", "validatorList", "depositAuthority", "withdrawAuthority", "depositStake", "validatorStake", "reserveStake", "destinationPoolTokens", "managerFee", "referralPoolTokens", "poolMint", "clock", "stakeHistory", "tokenProgram", "stakeProgram"}
+}
+
+func (inst DepositStake) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_DepositStake),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst DepositStake) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *DepositStake) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.DepositAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.DepositStake is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.ValidatorStake is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.DestinationPoolTokens is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.ManagerFee is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.ReferralPoolTokens is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[11] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[12] == nil {
+			return errors.New("accounts.StakeHistory is not set")
+		}
+		if inst.AccountMetaSlice[13] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+		if inst.AccountMetaSlice[14] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *DepositStake) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("DepositStake")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("            stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("        validatorList", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("     depositAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("    withdrawAuthority", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("         depositStake", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("       validatorStake", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("         reserveStake", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("destinationPoolTokens", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("           managerFee", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("   referralPoolTokens", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("             poolMint", inst.AccountMetaSlice.Get(10)))
+						accountsBranch.Child(ag_format.Meta("                clock", inst.AccountMetaSlice.Get(11)))
+						accountsBranch.Child(ag_format.Meta("         stakeHistory", inst.AccountMetaSlice.Get(12)))
+						accountsBranch.Child(ag_format.Meta("         tokenProgram", inst.AccountMetaSlice.Get(13)))
+						accountsBranch.Child(ag_format.Meta("         stakeProgram", inst.AccountMetaSlice.Get(14)))
+					})
+				})
+		})
+}
+
+func (obj DepositStake) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *DepositStake) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewDepositStakeInstruction declares a new DepositStake instruction with the provided parameters and accounts.
+func NewDepositStakeInstruction(
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	depositAuthority ag_solanago.PublicKey,
+	isDepositAuthoritySigner bool,
+	withdrawAuthority ag_solanago.PublicKey,
+	depositStake ag_solanago.PublicKey,
+	validatorStake ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	destinationPoolTokens ag_solanago.PublicKey,
+	managerFee ag_solanago.PublicKey,
+	referralPoolTokens ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+) *DepositStake {
+	return NewDepositStakeInstructionBuilder().
+		SetStakePoolAccount(stakePool).
+		SetValidatorListAccount(validatorList).
+		SetDepositAuthorityAccount(depositAuthority, isDepositAuthoritySigner).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetDepositStakeAccount(depositStake).
+		SetValidatorStakeAccount(validatorStake).
+		SetReserveStakeAccount(reserveStake).
+		SetDestinationPoolTokensAccount(destinationPoolTokens).
+		SetManagerFeeAccount(managerFee).
+		SetReferralPoolTokensAccount(referralPoolTokens).
+		SetPoolMintAccount(poolMint)
+}
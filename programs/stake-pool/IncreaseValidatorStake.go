@@ -0,0 +1,457 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Moves lamports of the reserve to the transient stake account of a
+// validator, delegated to it.
+type IncreaseValidatorStake struct {
+	// The lamports to move to the validator.
+	Lamports *uint64
+	// The seed of the transient stake account.
+	TransientStakeSeed *uint64
+
+	// [0] = [] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] staker
+	// ··········· The staker of the stake pool.
+	//
+	// [2] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [3] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [4] = [WRITE] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [5] = [WRITE] transientStake
+	// ··········· The transient stake account of the validator, at FindTransientStakeAddress.
+	//
+	// [6] = [] validatorStake
+	// ··········· The stake account of the validator, at FindValidatorStakeAddress.
+	//
+	// [7] = [] validatorVote
+	// ··········· The vote account of the validator.
+	//
+	// [8] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [9] = [] rent
+	// ··········· The rent sysvar.
+	//
+	// [10] = [] stakeHistory
+	// ··········· The stake history sysvar.
+	//
+	// [11] = [] stakeConfig
+	// ··········· The stake config account.
+	//
+	// [12] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [13] = [] stakeProgram
+	// ··········· The stake program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewIncreaseValidatorStakeInstructionBuilder creates a new `IncreaseValidatorStake` instruction builder.
+func NewIncreaseValidatorStakeInstructionBuilder() *IncreaseValidatorStake {
+	nd := &IncreaseValidatorStake{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 14),
+	}
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.SysVarRentPubkey)
+	nd.AccountMetaSlice[10] = ag_solanago.Meta(ag_solanago.SysVarStakeHistoryPubkey)
+	nd.AccountMetaSlice[11] = ag_solanago.Meta(ag_solanago.SysVarStakeConfigPubkey)
+	nd.AccountMetaSlice[12] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	nd.AccountMetaSlice[13] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	return nd
+}
+
+// SetLamports sets the "lamports" parameter.
+// The lamports to move to the validator.
+func (inst *IncreaseValidatorStake) SetLamports(lamports uint64) *IncreaseValidatorStake {
+	inst.Lamports = &lamports
+	return inst
+}
+
+// SetTransientStakeSeed sets the "transientStakeSeed" parameter.
+// The seed of the transient stake account.
+func (inst *IncreaseValidatorStake) SetTransientStakeSeed(transientStakeSeed uint64) *IncreaseValidatorStake {
+	inst.TransientStakeSeed = &transientStakeSeed
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *IncreaseValidatorStake) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool)
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *IncreaseValidatorStake) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetStakerAccount sets the "staker" account.
+// The staker of the stake pool.
+func (inst *IncreaseValidatorStake) SetStakerAccount(staker ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(staker).SIGNER()
+	return inst
+}
+
+// GetStakerAccount gets the "staker" account.
+// The staker of the stake pool.
+func (inst *IncreaseValidatorStake) GetStakerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *IncreaseValidatorStake) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *IncreaseValidatorStake) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *IncreaseValidatorStake) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *IncreaseValidatorStake) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *IncreaseValidatorStake) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(reserveStake).WRITE()
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *IncreaseValidatorStake) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetTransientStakeAccount sets the "transientStake" account.
+// The transient stake account of the validator, at FindTransientStakeAddress.
+func (inst *IncreaseValidatorStake) SetTransientStakeAccount(transientStake ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(transientStake).WRITE()
+	return inst
+}
+
+// GetTransientStakeAccount gets the "transientStake" account.
+// The transient stake account of the validator, at FindTransientStakeAddress.
+func (inst *IncreaseValidatorStake) GetTransientStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetValidatorStakeAccount sets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *IncreaseValidatorStake) SetValidatorStakeAccount(validatorStake ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(validatorStake)
+	return inst
+}
+
+// GetValidatorStakeAccount gets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *IncreaseValidatorStake) GetValidatorStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetValidatorVoteAccount sets the "validatorVote" account.
+// The vote account of the validator.
+func (inst *IncreaseValidatorStake) SetValidatorVoteAccount(validatorVote ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(validatorVote)
+	return inst
+}
+
+// GetValidatorVoteAccount gets the "validatorVote" account.
+// The vote account of the validator.
+func (inst *IncreaseValidatorStake) GetValidatorVoteAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *IncreaseValidatorStake) SetClockAccount(clock ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *IncreaseValidatorStake) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetRentAccount sets the "rent" account.
+// The rent sysvar.
+func (inst *IncreaseValidatorStake) SetRentAccount(rent ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(rent)
+	return inst
+}
+
+// GetRentAccount gets the "rent" account.
+// The rent sysvar.
+func (inst *IncreaseValidatorStake) GetRentAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetStakeHistoryAccount sets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *IncreaseValidatorStake) SetStakeHistoryAccount(stakeHistory ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(stakeHistory)
+	return inst
+}
+
+// GetStakeHistoryAccount gets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *IncreaseValidatorStake) GetStakeHistoryAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// SetStakeConfigAccount sets the "stakeConfig" account.
+// The stake config account.
+func (inst *IncreaseValidatorStake) SetStakeConfigAccount(stakeConfig ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[11] = ag_solanago.Meta(stakeConfig)
+	return inst
+}
+
+// GetStakeConfigAccount gets the "stakeConfig" account.
+// The stake config account.
+func (inst *IncreaseValidatorStake) GetStakeConfigAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(11)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *IncreaseValidatorStake) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[12] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *IncreaseValidatorStake) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(12)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *IncreaseValidatorStake) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *IncreaseValidatorStake {
+	inst.AccountMetaSlice[13] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *IncreaseValidatorStake) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(13)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst IncreaseValidatorStake) AccountNames() []string {
+	return []string{"stakePool", "staker", "withdrawAuthority", "validatorList", "reserveStake", "transientStake", "validatorStake", "validatorVote", "clock", "rent", "stakeHistory", "stakeConfig", "systemProgram", "stakeProgram"}
+}
+
+func (inst IncreaseValidatorStake) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_IncreaseValidatorStake),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst IncreaseValidatorStake) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *IncreaseValidatorStake) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Lamports == nil {
+			return errors.New("Lamports parameter is not set")
+		}
+		if inst.TransientStakeSeed == nil {
+			return errors.New("TransientStakeSeed parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Staker is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.TransientStake is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.ValidatorStake is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.ValidatorVote is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.Rent is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.StakeHistory is not set")
+		}
+		if inst.AccountMetaSlice[11] == nil {
+			return errors.New("accounts.StakeConfig is not set")
+		}
+		if inst.AccountMetaSlice[12] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+		if inst.AccountMetaSlice[13] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *IncreaseValidatorStake) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("IncreaseValidatorStake")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("          Lamports", inst.Lamports))
+						paramsBranch.Child(ag_format.Param("TransientStakeSeed", inst.TransientStakeSeed))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("           staker", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("withdrawAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("    validatorList", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("     reserveStake", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("   transientStake", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("   validatorStake", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("    validatorVote", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("            clock", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("             rent", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("     stakeHistory", inst.AccountMetaSlice.Get(10)))
+						accountsBranch.Child(ag_format.Meta("      stakeConfig", inst.AccountMetaSlice.Get(11)))
+						accountsBranch.Child(ag_format.Meta("    systemProgram", inst.AccountMetaSlice.Get(12)))
+						accountsBranch.Child(ag_format.Meta("     stakeProgram", inst.AccountMetaSlice.Get(13)))
+					})
+				})
+		})
+}
+
+func (obj IncreaseValidatorStake) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Lamports` param:
+	err = encoder.Encode(obj.Lamports)
+	if err != nil {
+		return err
+	}
+	// Serialize `TransientStakeSeed` param:
+	err = encoder.Encode(obj.TransientStakeSeed)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *IncreaseValidatorStake) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Lamports`:
+	err = decoder.Decode(&obj.Lamports)
+	if err != nil {
+		return err
+	}
+	// Deserialize `TransientStakeSeed`:
+	err = decoder.Decode(&obj.TransientStakeSeed)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewIncreaseValidatorStakeInstruction declares a new IncreaseValidatorStake instruction with the provided parameters and accounts.
+func NewIncreaseValidatorStakeInstruction(
+	// Parameters:
+	lamports uint64,
+	transientStakeSeed uint64,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	staker ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	transientStake ag_solanago.PublicKey,
+	validatorStake ag_solanago.PublicKey,
+	validatorVote ag_solanago.PublicKey,
+) *IncreaseValidatorStake {
+	return NewIncreaseValidatorStakeInstructionBuilder().
+		SetLamports(lamports).
+		SetTransientStakeSeed(transientStakeSeed).
+		SetStakePoolAccount(stakePool).
+		SetStakerAccount(staker).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetValidatorListAccount(validatorList).
+		SetReserveStakeAccount(reserveStake).
+		SetTransientStakeAccount(transientStake).
+		SetValidatorStakeAccount(validatorStake).
+		SetValidatorVoteAccount(validatorVote)
+}
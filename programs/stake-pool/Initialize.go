@@ -0,0 +1,451 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Initializes a stake pool, with its validator list and reserve stake account.
+type Initialize struct {
+	// The epoch fee, on the rewards of the pool.
+	Fee *Fee
+	// The fee on the withdrawals of stake.
+	WithdrawalFee *Fee
+	// The fee on the deposits of stake.
+	DepositFee *Fee
+	// The percentage of the deposit fees that goes to the referrers.
+	ReferralFee *uint8
+	// The maximum number of validators of the pool.
+	MaxValidators *uint32
+
+	// [0] = [WRITE, SIGNER] stakePool
+	// ··········· The new stake pool account, owned by the program.
+	//
+	// [1] = [SIGNER] manager
+	// ··········· The manager of the stake pool.
+	//
+	// [2] = [] staker
+	// ··········· The staker of the stake pool.
+	//
+	// [3] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [4] = [WRITE] validatorList
+	// ··········· The new validator list account, owned by the program.
+	//
+	// [5] = [] reserveStake
+	// ··········· The reserve stake account, initialized with the withdraw authority as staker and withdrawer.
+	//
+	// [6] = [] poolMint
+	// ··········· The pool token mint, with the withdraw authority as mint authority and no supply.
+	//
+	// [7] = [WRITE] managerFee
+	// ··········· The pool token account of the manager receiving the fees.
+	//
+	// [8] = [] tokenProgram
+	// ··········· The token program of the pool mint.
+	//
+	// [9] = [] depositAuthority
+	// ··········· The optional authority of the deposits of stake, instead of FindDepositAuthorityAddress(stakePool).
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeInstructionBuilder creates a new `Initialize` instruction builder.
+func NewInitializeInstructionBuilder() *Initialize {
+	nd := &Initialize{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 10),
+	}
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetFee sets the "fee" parameter.
+// The epoch fee, on the rewards of the pool.
+func (inst *Initialize) SetFee(fee Fee) *Initialize {
+	inst.Fee = &fee
+	return inst
+}
+
+// SetWithdrawalFee sets the "withdrawalFee" parameter.
+// The fee on the withdrawals of stake.
+func (inst *Initialize) SetWithdrawalFee(withdrawalFee Fee) *Initialize {
+	inst.WithdrawalFee = &withdrawalFee
+	return inst
+}
+
+// SetDepositFee sets the "depositFee" parameter.
+// The fee on the deposits of stake.
+func (inst *Initialize) SetDepositFee(depositFee Fee) *Initialize {
+	inst.DepositFee = &depositFee
+	return inst
+}
+
+// SetReferralFee sets the "referralFee" parameter.
+// The percentage of the deposit fees that goes to the referrers.
+func (inst *Initialize) SetReferralFee(referralFee uint8) *Initialize {
+	inst.ReferralFee = &referralFee
+	return inst
+}
+
+// SetMaxValidators sets the "maxValidators" parameter.
+// The maximum number of validators of the pool.
+func (inst *Initialize) SetMaxValidators(maxValidators uint32) *Initialize {
+	inst.MaxValidators = &maxValidators
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The new stake pool account, owned by the program.
+func (inst *Initialize) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE().SIGNER()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The new stake pool account, owned by the program.
+func (inst *Initialize) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetManagerAccount sets the "manager" account.
+// The manager of the stake pool.
+func (inst *Initialize) SetManagerAccount(manager ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(manager).SIGNER()
+	return inst
+}
+
+// GetManagerAccount gets the "manager" account.
+// The manager of the stake pool.
+func (inst *Initialize) GetManagerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetStakerAccount sets the "staker" account.
+// The staker of the stake pool.
+func (inst *Initialize) SetStakerAccount(staker ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(staker)
+	return inst
+}
+
+// GetStakerAccount gets the "staker" account.
+// The staker of the stake pool.
+func (inst *Initialize) GetStakerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *Initialize) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *Initialize) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The new validator list account, owned by the program.
+func (inst *Initialize) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The new validator list account, owned by the program.
+func (inst *Initialize) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account, initialized with the withdraw authority as staker and withdrawer.
+func (inst *Initialize) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(reserveStake)
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account, initialized with the withdraw authority as staker and withdrawer.
+func (inst *Initialize) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The pool token mint, with the withdraw authority as mint authority and no supply.
+func (inst *Initialize) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(poolMint)
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The pool token mint, with the withdraw authority as mint authority and no supply.
+func (inst *Initialize) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetManagerFeeAccount sets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *Initialize) SetManagerFeeAccount(managerFee ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(managerFee).WRITE()
+	return inst
+}
+
+// GetManagerFeeAccount gets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *Initialize) GetManagerFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *Initialize) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *Initialize) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetDepositAuthorityAccount sets the "depositAuthority" account.
+// The optional authority of the deposits of stake, instead of FindDepositAuthorityAddress(stakePool).
+func (inst *Initialize) SetDepositAuthorityAccount(depositAuthority ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(depositAuthority)
+	return inst
+}
+
+// GetDepositAuthorityAccount gets the "depositAuthority" account.
+// The optional authority of the deposits of stake, instead of FindDepositAuthorityAddress(stakePool).
+func (inst *Initialize) GetDepositAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Initialize) AccountNames() []string {
+	return []string{"stakePool", "manager", "staker", "withdrawAuthority", "validatorList", "reserveStake", "poolMint", "managerFee", "tokenProgram", "depositAuthority"}
+}
+
+func (inst Initialize) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_Initialize),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Initialize) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Initialize) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Fee == nil {
+			return errors.New("Fee parameter is not set")
+		}
+		if inst.WithdrawalFee == nil {
+			return errors.New("WithdrawalFee parameter is not set")
+		}
+		if inst.DepositFee == nil {
+			return errors.New("DepositFee parameter is not set")
+		}
+		if inst.ReferralFee == nil {
+			return errors.New("ReferralFee parameter is not set")
+		}
+		if inst.MaxValidators == nil {
+			return errors.New("MaxValidators parameter is not set")
+		}
+		for _, fee := range []*Fee{inst.Fee, inst.WithdrawalFee, inst.DepositFee} {
+			if err := fee.validate(); err != nil {
+				return err
+			}
+		}
+		if *inst.ReferralFee > 100 {
+			return errors.New("referral fee must be a percentage")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Manager is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Staker is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.ManagerFee is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Initialize) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Initialize")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("          Fee", inst.Fee))
+						paramsBranch.Child(ag_format.Param("WithdrawalFee", inst.WithdrawalFee))
+						paramsBranch.Child(ag_format.Param("   DepositFee", inst.DepositFee))
+						paramsBranch.Child(ag_format.Param("  ReferralFee", inst.ReferralFee))
+						paramsBranch.Child(ag_format.Param("MaxValidators", inst.MaxValidators))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("          manager", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("           staker", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("withdrawAuthority", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("    validatorList", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("     reserveStake", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("         poolMint", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("       managerFee", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("     tokenProgram", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta(" depositAuthority", inst.AccountMetaSlice.Get(9)))
+					})
+				})
+		})
+}
+
+func (obj Initialize) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Fee` param:
+	err = encoder.Encode(obj.Fee)
+	if err != nil {
+		return err
+	}
+	// Serialize `WithdrawalFee` param:
+	err = encoder.Encode(obj.WithdrawalFee)
+	if err != nil {
+		return err
+	}
+	// Serialize `DepositFee` param:
+	err = encoder.Encode(obj.DepositFee)
+	if err != nil {
+		return err
+	}
+	// Serialize `ReferralFee` param:
+	err = encoder.Encode(obj.ReferralFee)
+	if err != nil {
+		return err
+	}
+	// Serialize `MaxValidators` param:
+	err = encoder.Encode(obj.MaxValidators)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *Initialize) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Fee`:
+	err = decoder.Decode(&obj.Fee)
+	if err != nil {
+		return err
+	}
+	// Deserialize `WithdrawalFee`:
+	err = decoder.Decode(&obj.WithdrawalFee)
+	if err != nil {
+		return err
+	}
+	// Deserialize `DepositFee`:
+	err = decoder.Decode(&obj.DepositFee)
+	if err != nil {
+		return err
+	}
+	// Deserialize `ReferralFee`:
+	err = decoder.Decode(&obj.ReferralFee)
+	if err != nil {
+		return err
+	}
+	// Deserialize `MaxValidators`:
+	err = decoder.Decode(&obj.MaxValidators)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewInitializeInstruction declares a new Initialize instruction with the provided parameters and accounts.
+func NewInitializeInstruction(
+	// Parameters:
+	fee Fee,
+	withdrawalFee Fee,
+	depositFee Fee,
+	referralFee uint8,
+	maxValidators uint32,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	manager ag_solanago.PublicKey,
+	staker ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+	managerFee ag_solanago.PublicKey,
+) *Initialize {
+	return NewInitializeInstructionBuilder().
+		SetFee(fee).
+		SetWithdrawalFee(withdrawalFee).
+		SetDepositFee(depositFee).
+		SetReferralFee(referralFee).
+		SetMaxValidators(maxValidators).
+		SetStakePoolAccount(stakePool).
+		SetManagerAccount(manager).
+		SetStakerAccount(staker).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetValidatorListAccount(validatorList).
+		SetReserveStakeAccount(reserveStake).
+		SetPoolMintAccount(poolMint).
+		SetManagerFeeAccount(managerFee)
+}
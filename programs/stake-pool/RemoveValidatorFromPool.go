@@ -0,0 +1,272 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Removes a validator from the pool, deactivating its stake accounts.
+type RemoveValidatorFromPool struct {
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] staker
+	// ··········· The staker of the stake pool.
+	//
+	// [2] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [3] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [4] = [WRITE] validatorStake
+	// ··········· The stake account of the validator, at FindValidatorStakeAddress.
+	//
+	// [5] = [WRITE] transientStake
+	// ··········· The transient stake account of the validator, at FindTransientStakeAddress.
+	//
+	// [6] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [7] = [] stakeProgram
+	// ··········· The stake program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewRemoveValidatorFromPoolInstructionBuilder creates a new `RemoveValidatorFromPool` instruction builder.
+func NewRemoveValidatorFromPoolInstructionBuilder() *RemoveValidatorFromPool {
+	nd := &RemoveValidatorFromPool{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 8),
+	}
+	nd.AccountMetaSlice[6] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	return nd
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *RemoveValidatorFromPool) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *RemoveValidatorFromPool) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetStakerAccount sets the "staker" account.
+// The staker of the stake pool.
+func (inst *RemoveValidatorFromPool) SetStakerAccount(staker ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(staker).SIGNER()
+	return inst
+}
+
+// GetStakerAccount gets the "staker" account.
+// The staker of the stake pool.
+func (inst *RemoveValidatorFromPool) GetStakerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *RemoveValidatorFromPool) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *RemoveValidatorFromPool) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *RemoveValidatorFromPool) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *RemoveValidatorFromPool) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetValidatorStakeAccount sets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *RemoveValidatorFromPool) SetValidatorStakeAccount(validatorStake ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(validatorStake).WRITE()
+	return inst
+}
+
+// GetValidatorStakeAccount gets the "validatorStake" account.
+// The stake account of the validator, at FindValidatorStakeAddress.
+func (inst *RemoveValidatorFromPool) GetValidatorStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetTransientStakeAccount sets the "transientStake" account.
+// The transient stake account of the validator, at FindTransientStakeAddress.
+func (inst *RemoveValidatorFromPool) SetTransientStakeAccount(transientStake ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(transientStake).WRITE()
+	return inst
+}
+
+// GetTransientStakeAccount gets the "transientStake" account.
+// The transient stake account of the validator, at FindTransientStakeAddress.
+func (inst *RemoveValidatorFromPool) GetTransientStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *RemoveValidatorFromPool) SetClockAccount(clock ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *RemoveValidatorFromPool) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *RemoveValidatorFromPool) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *RemoveValidatorFromPool {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *RemoveValidatorFromPool) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst RemoveValidatorFromPool) AccountNames() []string {
+	return []string{"stakePool", "staker", "withdrawAuthority", "validatorList", "validatorStake", "transientStake", "clock", "stakeProgram"}
+}
+
+func (inst RemoveValidatorFromPool) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_RemoveValidatorFromPool),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst RemoveValidatorFromPool) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *RemoveValidatorFromPool) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Staker is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ValidatorStake is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.TransientStake is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *RemoveValidatorFromPool) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("RemoveValidatorFromPool")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("           staker", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("withdrawAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("    validatorList", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("   validatorStake", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("   transientStake", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("            clock", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("     stakeProgram", inst.AccountMetaSlice.Get(7)))
+					})
+				})
+		})
+}
+
+func (obj RemoveValidatorFromPool) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *RemoveValidatorFromPool) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewRemoveValidatorFromPoolInstruction declares a new RemoveValidatorFromPool instruction with the provided parameters and accounts.
+func NewRemoveValidatorFromPoolInstruction(
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	staker ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	validatorStake ag_solanago.PublicKey,
+	transientStake ag_solanago.PublicKey,
+) *RemoveValidatorFromPool {
+	return NewRemoveValidatorFromPoolInstructionBuilder().
+		SetStakePoolAccount(stakePool).
+		SetStakerAccount(staker).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetValidatorListAccount(validatorList).
+		SetValidatorStakeAccount(validatorStake).
+		SetTransientStakeAccount(transientStake)
+}
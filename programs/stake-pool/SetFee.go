@@ -0,0 +1,177 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Sets one of the fees of a stake pool; the new fees of the epoch and of the
+// withdrawals apply after two epoch boundaries.
+type SetFee struct {
+	// The fee to set.
+	Fee *FeeType
+
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] manager
+	// ··········· The manager of the stake pool.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetFeeInstructionBuilder creates a new `SetFee` instruction builder.
+func NewSetFeeInstructionBuilder() *SetFee {
+	nd := &SetFee{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 2),
+	}
+	return nd
+}
+
+// SetFee sets the "fee" parameter.
+// The fee to set.
+func (inst *SetFee) SetFee(fee FeeType) *SetFee {
+	inst.Fee = &fee
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *SetFee) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *SetFee {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *SetFee) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetManagerAccount sets the "manager" account.
+// The manager of the stake pool.
+func (inst *SetFee) SetManagerAccount(manager ag_solanago.PublicKey) *SetFee {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(manager).SIGNER()
+	return inst
+}
+
+// GetManagerAccount gets the "manager" account.
+// The manager of the stake pool.
+func (inst *SetFee) GetManagerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst SetFee) AccountNames() []string {
+	return []string{"stakePool", "manager"}
+}
+
+func (inst SetFee) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_SetFee),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst SetFee) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetFee) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Fee == nil {
+			return errors.New("Fee parameter is not set")
+		}
+		if err := inst.Fee.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Manager is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *SetFee) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("SetFee")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("Fee", inst.Fee))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("  manager", inst.AccountMetaSlice.Get(1)))
+					})
+				})
+		})
+}
+
+func (obj SetFee) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Fee` param:
+	err = encoder.Encode(obj.Fee)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *SetFee) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Fee`:
+	err = decoder.Decode(&obj.Fee)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewSetFeeInstruction declares a new SetFee instruction with the provided parameters and accounts.
+func NewSetFeeInstruction(
+	// Parameters:
+	fee FeeType,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	manager ag_solanago.PublicKey,
+) *SetFee {
+	return NewSetFeeInstructionBuilder().
+		SetFee(fee).
+		SetStakePoolAccount(stakePool).
+		SetManagerAccount(manager)
+}
@@ -0,0 +1,191 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Sets or unsets the authority of a type of deposits or withdrawals of a
+// stake pool.
+type SetFundingAuthority struct {
+	// The type of deposits or withdrawals.
+	FundingType *FundingType
+
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] manager
+	// ··········· The manager of the stake pool.
+	//
+	// [2] = [] newAuthority
+	// ··········· The new authority; none to unset.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetFundingAuthorityInstructionBuilder creates a new `SetFundingAuthority` instruction builder.
+func NewSetFundingAuthorityInstructionBuilder() *SetFundingAuthority {
+	nd := &SetFundingAuthority{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 3),
+	}
+	return nd
+}
+
+// SetFundingType sets the "fundingType" parameter.
+// The type of deposits or withdrawals.
+func (inst *SetFundingAuthority) SetFundingType(fundingType FundingType) *SetFundingAuthority {
+	inst.FundingType = &fundingType
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *SetFundingAuthority) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *SetFundingAuthority {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *SetFundingAuthority) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetManagerAccount sets the "manager" account.
+// The manager of the stake pool.
+func (inst *SetFundingAuthority) SetManagerAccount(manager ag_solanago.PublicKey) *SetFundingAuthority {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(manager).SIGNER()
+	return inst
+}
+
+// GetManagerAccount gets the "manager" account.
+// The manager of the stake pool.
+func (inst *SetFundingAuthority) GetManagerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetNewAuthorityAccount sets the "newAuthority" account.
+// The new authority; none to unset.
+func (inst *SetFundingAuthority) SetNewAuthorityAccount(newAuthority ag_solanago.PublicKey) *SetFundingAuthority {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(newAuthority)
+	return inst
+}
+
+// GetNewAuthorityAccount gets the "newAuthority" account.
+// The new authority; none to unset.
+func (inst *SetFundingAuthority) GetNewAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst SetFundingAuthority) AccountNames() []string {
+	return []string{"stakePool", "manager", "newAuthority"}
+}
+
+func (inst SetFundingAuthority) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_SetFundingAuthority),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst SetFundingAuthority) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetFundingAuthority) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.FundingType == nil {
+			return errors.New("FundingType parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Manager is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *SetFundingAuthority) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("SetFundingAuthority")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("FundingType", inst.FundingType))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("   stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("     manager", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("newAuthority", inst.AccountMetaSlice.Get(2)))
+					})
+				})
+		})
+}
+
+func (obj SetFundingAuthority) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `FundingType` param:
+	err = encoder.Encode(obj.FundingType)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *SetFundingAuthority) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `FundingType`:
+	err = decoder.Decode(&obj.FundingType)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewSetFundingAuthorityInstruction declares a new SetFundingAuthority instruction with the provided parameters and accounts.
+func NewSetFundingAuthorityInstruction(
+	// Parameters:
+	fundingType FundingType,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	manager ag_solanago.PublicKey,
+) *SetFundingAuthority {
+	return NewSetFundingAuthorityInstructionBuilder().
+		SetFundingType(fundingType).
+		SetStakePoolAccount(stakePool).
+		SetManagerAccount(manager)
+}
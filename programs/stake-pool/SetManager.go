@@ -0,0 +1,186 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Sets the manager of a stake pool, and the pool token account of its fees.
+type SetManager struct {
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] manager
+	// ··········· The manager of the stake pool.
+	//
+	// [2] = [SIGNER] newManager
+	// ··········· The new manager.
+	//
+	// [3] = [] newManagerFee
+	// ··········· The pool token account of the new manager receiving the fees.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetManagerInstructionBuilder creates a new `SetManager` instruction builder.
+func NewSetManagerInstructionBuilder() *SetManager {
+	nd := &SetManager{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 4),
+	}
+	return nd
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *SetManager) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *SetManager {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *SetManager) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetManagerAccount sets the "manager" account.
+// The manager of the stake pool.
+func (inst *SetManager) SetManagerAccount(manager ag_solanago.PublicKey) *SetManager {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(manager).SIGNER()
+	return inst
+}
+
+// GetManagerAccount gets the "manager" account.
+// The manager of the stake pool.
+func (inst *SetManager) GetManagerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetNewManagerAccount sets the "newManager" account.
+// The new manager.
+func (inst *SetManager) SetNewManagerAccount(newManager ag_solanago.PublicKey) *SetManager {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(newManager).SIGNER()
+	return inst
+}
+
+// GetNewManagerAccount gets the "newManager" account.
+// The new manager.
+func (inst *SetManager) GetNewManagerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetNewManagerFeeAccount sets the "newManagerFee" account.
+// The pool token account of the new manager receiving the fees.
+func (inst *SetManager) SetNewManagerFeeAccount(newManagerFee ag_solanago.PublicKey) *SetManager {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(newManagerFee)
+	return inst
+}
+
+// GetNewManagerFeeAccount gets the "newManagerFee" account.
+// The pool token account of the new manager receiving the fees.
+func (inst *SetManager) GetNewManagerFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst SetManager) AccountNames() []string {
+	return []string{"stakePool", "manager", "newManager", "newManagerFee"}
+}
+
+func (inst SetManager) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_SetManager),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst SetManager) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetManager) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Manager is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.NewManager is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.NewManagerFee is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *SetManager) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("SetManager")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("    stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("      manager", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("   newManager", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("newManagerFee", inst.AccountMetaSlice.Get(3)))
+					})
+				})
+		})
+}
+
+func (obj SetManager) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *SetManager) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewSetManagerInstruction declares a new SetManager instruction with the provided parameters and accounts.
+func NewSetManagerInstruction(
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	manager ag_solanago.PublicKey,
+	newManager ag_solanago.PublicKey,
+	newManagerFee ag_solanago.PublicKey,
+) *SetManager {
+	return NewSetManagerInstructionBuilder().
+		SetStakePoolAccount(stakePool).
+		SetManagerAccount(manager).
+		SetNewManagerAccount(newManager).
+		SetNewManagerFeeAccount(newManagerFee)
+}
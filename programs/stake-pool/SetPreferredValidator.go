@@ -0,0 +1,236 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Sets or unsets the validator that the deposits or the withdrawals must use.
+type SetPreferredValidator struct {
+	// The preferred validator to set.
+	ValidatorType *PreferredValidatorType
+	// The vote account of the validator; none to unset.
+	ValidatorVoteAddress *ag_solanago.PublicKey `bin:"optional"`
+
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] staker
+	// ··········· The staker of the stake pool.
+	//
+	// [2] = [] validatorList
+	// ··········· The validator list of the stake pool.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetPreferredValidatorInstructionBuilder creates a new `SetPreferredValidator` instruction builder.
+func NewSetPreferredValidatorInstructionBuilder() *SetPreferredValidator {
+	nd := &SetPreferredValidator{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 3),
+	}
+	return nd
+}
+
+// SetValidatorType sets the "validatorType" parameter.
+// The preferred validator to set.
+func (inst *SetPreferredValidator) SetValidatorType(validatorType PreferredValidatorType) *SetPreferredValidator {
+	inst.ValidatorType = &validatorType
+	return inst
+}
+
+// SetValidatorVoteAddress sets the "validatorVoteAddress" parameter.
+// The vote account of the validator; none to unset.
+func (inst *SetPreferredValidator) SetValidatorVoteAddress(validatorVoteAddress ag_solanago.PublicKey) *SetPreferredValidator {
+	inst.ValidatorVoteAddress = &validatorVoteAddress
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *SetPreferredValidator) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *SetPreferredValidator {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *SetPreferredValidator) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetStakerAccount sets the "staker" account.
+// The staker of the stake pool.
+func (inst *SetPreferredValidator) SetStakerAccount(staker ag_solanago.PublicKey) *SetPreferredValidator {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(staker).SIGNER()
+	return inst
+}
+
+// GetStakerAccount gets the "staker" account.
+// The staker of the stake pool.
+func (inst *SetPreferredValidator) GetStakerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *SetPreferredValidator) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *SetPreferredValidator {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(validatorList)
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *SetPreferredValidator) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst SetPreferredValidator) AccountNames() []string {
+	return []string{"stakePool", "staker", "validatorList"}
+}
+
+func (inst SetPreferredValidator) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_SetPreferredValidator),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst SetPreferredValidator) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetPreferredValidator) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.ValidatorType == nil {
+			return errors.New("ValidatorType parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Staker is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *SetPreferredValidator) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("SetPreferredValidator")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("       ValidatorType", inst.ValidatorType))
+						paramsBranch.Child(ag_format.Param("ValidatorVoteAddress", inst.ValidatorVoteAddress))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("    stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("       staker", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("validatorList", inst.AccountMetaSlice.Get(2)))
+					})
+				})
+		})
+}
+
+func (obj SetPreferredValidator) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `ValidatorType` param:
+	err = encoder.Encode(obj.ValidatorType)
+	if err != nil {
+		return err
+	}
+	// Serialize `ValidatorVoteAddress` param (optional):
+	{
+		if obj.ValidatorVoteAddress == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.ValidatorVoteAddress)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+func (obj *SetPreferredValidator) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `ValidatorType`:
+	err = decoder.Decode(&obj.ValidatorType)
+	if err != nil {
+		return err
+	}
+	// Deserialize `ValidatorVoteAddress` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.ValidatorVoteAddress)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewSetPreferredValidatorInstruction declares a new SetPreferredValidator instruction with the provided parameters and accounts.
+func NewSetPreferredValidatorInstruction(
+	// Parameters:
+	validatorType PreferredValidatorType,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	staker ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+) *SetPreferredValidator {
+	return NewSetPreferredValidatorInstructionBuilder().
+		SetValidatorType(validatorType).
+		SetStakePoolAccount(stakePool).
+		SetStakerAccount(staker).
+		SetValidatorListAccount(validatorList)
+}
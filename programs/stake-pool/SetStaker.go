@@ -0,0 +1,164 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Sets the staker of a stake pool.
+type SetStaker struct {
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [SIGNER] signer
+	// ··········· The manager or the current staker of the stake pool.
+	//
+	// [2] = [] newStaker
+	// ··········· The new staker.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetStakerInstructionBuilder creates a new `SetStaker` instruction builder.
+func NewSetStakerInstructionBuilder() *SetStaker {
+	nd := &SetStaker{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 3),
+	}
+	return nd
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *SetStaker) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *SetStaker {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *SetStaker) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetSignerAccount sets the "signer" account.
+// The manager or the current staker of the stake pool.
+func (inst *SetStaker) SetSignerAccount(signer ag_solanago.PublicKey) *SetStaker {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(signer).SIGNER()
+	return inst
+}
+
+// GetSignerAccount gets the "signer" account.
+// The manager or the current staker of the stake pool.
+func (inst *SetStaker) GetSignerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetNewStakerAccount sets the "newStaker" account.
+// The new staker.
+func (inst *SetStaker) SetNewStakerAccount(newStaker ag_solanago.PublicKey) *SetStaker {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(newStaker)
+	return inst
+}
+
+// GetNewStakerAccount gets the "newStaker" account.
+// The new staker.
+func (inst *SetStaker) GetNewStakerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst SetStaker) AccountNames() []string {
+	return []string{"stakePool", "signer", "newStaker"}
+}
+
+func (inst SetStaker) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_SetStaker),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst SetStaker) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetStaker) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Signer is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.NewStaker is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *SetStaker) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("SetStaker")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("   signer", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("newStaker", inst.AccountMetaSlice.Get(2)))
+					})
+				})
+		})
+}
+
+func (obj SetStaker) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *SetStaker) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewSetStakerInstruction declares a new SetStaker instruction with the provided parameters and accounts.
+func NewSetStakerInstruction(
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	signer ag_solanago.PublicKey,
+	newStaker ag_solanago.PublicKey,
+) *SetStaker {
+	return NewSetStakerInstructionBuilder().
+		SetStakePoolAccount(stakePool).
+		SetSignerAccount(signer).
+		SetNewStakerAccount(newStaker)
+}
@@ -0,0 +1,252 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Updates the total lamports of the pool from the validator list and the
+// reserve, and mints the epoch fee to the manager.
+type UpdateStakePoolBalance struct {
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [2] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [3] = [] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [4] = [WRITE] managerFee
+	// ··········· The pool token account of the manager receiving the fees.
+	//
+	// [5] = [WRITE] poolMint
+	// ··········· The pool token mint.
+	//
+	// [6] = [] tokenProgram
+	// ··········· The token program of the pool mint.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewUpdateStakePoolBalanceInstructionBuilder creates a new `UpdateStakePoolBalance` instruction builder.
+func NewUpdateStakePoolBalanceInstructionBuilder() *UpdateStakePoolBalance {
+	nd := &UpdateStakePoolBalance{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 7),
+	}
+	nd.AccountMetaSlice[6] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *UpdateStakePoolBalance) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *UpdateStakePoolBalance {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *UpdateStakePoolBalance) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *UpdateStakePoolBalance) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *UpdateStakePoolBalance {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *UpdateStakePoolBalance) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *UpdateStakePoolBalance) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *UpdateStakePoolBalance {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *UpdateStakePoolBalance) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *UpdateStakePoolBalance) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *UpdateStakePoolBalance {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(reserveStake)
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *UpdateStakePoolBalance) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetManagerFeeAccount sets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *UpdateStakePoolBalance) SetManagerFeeAccount(managerFee ag_solanago.PublicKey) *UpdateStakePoolBalance {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(managerFee).WRITE()
+	return inst
+}
+
+// GetManagerFeeAccount gets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *UpdateStakePoolBalance) GetManagerFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The pool token mint.
+func (inst *UpdateStakePoolBalance) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *UpdateStakePoolBalance {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The pool token mint.
+func (inst *UpdateStakePoolBalance) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *UpdateStakePoolBalance) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *UpdateStakePoolBalance {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *UpdateStakePoolBalance) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst UpdateStakePoolBalance) AccountNames() []string {
+	return []string{"stakePool", "withdrawAuthority", "validatorList", "reserveStake", "managerFee", "poolMint", "tokenProgram"}
+}
+
+func (inst UpdateStakePoolBalance) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_UpdateStakePoolBalance),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst UpdateStakePoolBalance) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *UpdateStakePoolBalance) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ManagerFee is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *UpdateStakePoolBalance) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("UpdateStakePoolBalance")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("withdrawAuthority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("    validatorList", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("     reserveStake", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("       managerFee", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("         poolMint", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("     tokenProgram", inst.AccountMetaSlice.Get(6)))
+					})
+				})
+		})
+}
+
+func (obj UpdateStakePoolBalance) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *UpdateStakePoolBalance) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewUpdateStakePoolBalanceInstruction declares a new UpdateStakePoolBalance instruction with the provided parameters and accounts.
+func NewUpdateStakePoolBalanceInstruction(
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	managerFee ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+) *UpdateStakePoolBalance {
+	return NewUpdateStakePoolBalanceInstructionBuilder().
+		SetStakePoolAccount(stakePool).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetValidatorListAccount(validatorList).
+		SetReserveStakeAccount(reserveStake).
+		SetManagerFeeAccount(managerFee).
+		SetPoolMintAccount(poolMint)
+}
@@ -0,0 +1,344 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Updates the balances of validators of the validator list, merging their
+// transient stake accounts when possible; the first instruction of the
+// update of a pool at each epoch.
+type UpdateValidatorListBalance struct {
+	// The index of the first validator of the stake accounts in the list.
+	StartIndex *uint32
+	// Whether to skip the merges of the transient stake accounts.
+	NoMerge *bool
+
+	// [0] = [] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [2] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [3] = [WRITE] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [4] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [5] = [] stakeHistory
+	// ··········· The stake history sysvar.
+	//
+	// [6] = [] stakeProgram
+	// ··········· The stake program.
+	//
+	// [7...] = [WRITE] stakeAccounts
+	// ··········· The validator and transient stake accounts of each validator, from the start index.
+	Accounts      ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+	StakeAccounts ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+func (obj *UpdateValidatorListBalance) SetAccounts(accounts []*ag_solanago.AccountMeta) error {
+	obj.Accounts, obj.StakeAccounts = ag_solanago.AccountMetaSlice(accounts).SplitFrom(7)
+	return nil
+}
+
+func (slice UpdateValidatorListBalance) GetAccounts() (accounts []*ag_solanago.AccountMeta) {
+	accounts = append(accounts, slice.Accounts...)
+	accounts = append(accounts, slice.StakeAccounts...)
+	return
+}
+
+// NewUpdateValidatorListBalanceInstructionBuilder creates a new `UpdateValidatorListBalance` instruction builder.
+func NewUpdateValidatorListBalanceInstructionBuilder() *UpdateValidatorListBalance {
+	nd := &UpdateValidatorListBalance{
+		Accounts:      make(ag_solanago.AccountMetaSlice, 7),
+		StakeAccounts: make(ag_solanago.AccountMetaSlice, 0),
+	}
+	nd.Accounts[4] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.Accounts[5] = ag_solanago.Meta(ag_solanago.SysVarStakeHistoryPubkey)
+	nd.Accounts[6] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	return nd
+}
+
+// SetStartIndex sets the "startIndex" parameter.
+// The index of the first validator of the stake accounts in the list.
+func (inst *UpdateValidatorListBalance) SetStartIndex(startIndex uint32) *UpdateValidatorListBalance {
+	inst.StartIndex = &startIndex
+	return inst
+}
+
+// SetNoMerge sets the "noMerge" parameter.
+// Whether to skip the merges of the transient stake accounts.
+func (inst *UpdateValidatorListBalance) SetNoMerge(noMerge bool) *UpdateValidatorListBalance {
+	inst.NoMerge = &noMerge
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *UpdateValidatorListBalance) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.Accounts[0] = ag_solanago.Meta(stakePool)
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *UpdateValidatorListBalance) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(0)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *UpdateValidatorListBalance) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.Accounts[1] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *UpdateValidatorListBalance) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(1)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *UpdateValidatorListBalance) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.Accounts[2] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *UpdateValidatorListBalance) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(2)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *UpdateValidatorListBalance) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.Accounts[3] = ag_solanago.Meta(reserveStake).WRITE()
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *UpdateValidatorListBalance) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(3)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *UpdateValidatorListBalance) SetClockAccount(clock ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.Accounts[4] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *UpdateValidatorListBalance) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(4)
+}
+
+// SetStakeHistoryAccount sets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *UpdateValidatorListBalance) SetStakeHistoryAccount(stakeHistory ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.Accounts[5] = ag_solanago.Meta(stakeHistory)
+	return inst
+}
+
+// GetStakeHistoryAccount gets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *UpdateValidatorListBalance) GetStakeHistoryAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(5)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *UpdateValidatorListBalance) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.Accounts[6] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *UpdateValidatorListBalance) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(6)
+}
+
+// SetStakeAccounts sets the validator and transient stake accounts of each validator, from the start index, as the remaining accounts.
+func (inst *UpdateValidatorListBalance) SetStakeAccounts(stakeAccounts ...ag_solanago.PublicKey) *UpdateValidatorListBalance {
+	inst.StakeAccounts = make(ag_solanago.AccountMetaSlice, len(stakeAccounts))
+	for i, account := range stakeAccounts {
+		inst.StakeAccounts[i] = ag_solanago.Meta(account).WRITE()
+	}
+	return inst
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst UpdateValidatorListBalance) AccountNames() []string {
+	return appendAccountNames([]string{"stakePool", "withdrawAuthority", "validatorList", "reserveStake", "clock", "stakeHistory", "stakeProgram"}, "stakeAccounts", inst.StakeAccounts)
+}
+
+func (inst UpdateValidatorListBalance) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_UpdateValidatorListBalance),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst UpdateValidatorListBalance) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *UpdateValidatorListBalance) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.StartIndex == nil {
+			return errors.New("StartIndex parameter is not set")
+		}
+		if inst.NoMerge == nil {
+			return errors.New("NoMerge parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.Accounts[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.Accounts[1] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.Accounts[2] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.Accounts[3] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.Accounts[4] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.Accounts[5] == nil {
+			return errors.New("accounts.StakeHistory is not set")
+		}
+		if inst.Accounts[6] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *UpdateValidatorListBalance) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("UpdateValidatorListBalance")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("StartIndex", inst.StartIndex))
+						paramsBranch.Child(ag_format.Param("   NoMerge", inst.NoMerge))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        stakePool", inst.Accounts.Get(0)))
+						accountsBranch.Child(ag_format.Meta("withdrawAuthority", inst.Accounts.Get(1)))
+						accountsBranch.Child(ag_format.Meta("    validatorList", inst.Accounts.Get(2)))
+						accountsBranch.Child(ag_format.Meta("     reserveStake", inst.Accounts.Get(3)))
+						accountsBranch.Child(ag_format.Meta("            clock", inst.Accounts.Get(4)))
+						accountsBranch.Child(ag_format.Meta("     stakeHistory", inst.Accounts.Get(5)))
+						accountsBranch.Child(ag_format.Meta("     stakeProgram", inst.Accounts.Get(6)))
+
+						restBranch := accountsBranch.Child(fmt.Sprintf("stakeAccounts[len=%v]", len(inst.StakeAccounts)))
+						for i, v := range inst.StakeAccounts {
+							if len(inst.StakeAccounts) > 9 && i < 10 {
+								restBranch.Child(ag_format.Meta(fmt.Sprintf(" [%v]", i), v))
+							} else {
+								restBranch.Child(ag_format.Meta(fmt.Sprintf("[%v]", i), v))
+							}
+						}
+					})
+				})
+		})
+}
+
+func (obj UpdateValidatorListBalance) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `StartIndex` param:
+	err = encoder.Encode(obj.StartIndex)
+	if err != nil {
+		return err
+	}
+	// Serialize `NoMerge` param:
+	err = encoder.Encode(obj.NoMerge)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *UpdateValidatorListBalance) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `StartIndex`:
+	err = decoder.Decode(&obj.StartIndex)
+	if err != nil {
+		return err
+	}
+	// Deserialize `NoMerge`:
+	err = decoder.Decode(&obj.NoMerge)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewUpdateValidatorListBalanceInstruction declares a new UpdateValidatorListBalance instruction with the provided parameters and accounts.
+func NewUpdateValidatorListBalanceInstruction(
+	// Parameters:
+	startIndex uint32,
+	noMerge bool,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	stakeAccounts []ag_solanago.PublicKey,
+) *UpdateValidatorListBalance {
+	return NewUpdateValidatorListBalanceInstructionBuilder().
+		SetStartIndex(startIndex).
+		SetNoMerge(noMerge).
+		SetStakePoolAccount(stakePool).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetValidatorListAccount(validatorList).
+		SetReserveStakeAccount(reserveStake).
+		SetStakeAccounts(stakeAccounts...)
+}
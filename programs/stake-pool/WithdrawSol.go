@@ -0,0 +1,406 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Burns pool tokens, for lamports of the reserve of a stake pool.
+type WithdrawSol struct {
+	// The amount of pool tokens to burn.
+	PoolTokens *uint64
+
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [2] = [SIGNER] userTransferAuthority
+	// ··········· The owner or the delegate of the pool token account.
+	//
+	// [3] = [WRITE] burnFromPoolTokens
+	// ··········· The pool token account of the burned pool tokens.
+	//
+	// [4] = [WRITE] reserveStake
+	// ··········· The reserve stake account of the stake pool.
+	//
+	// [5] = [WRITE] destinationLamports
+	// ··········· The system account receiving the lamports.
+	//
+	// [6] = [WRITE] managerFee
+	// ··········· The pool token account of the manager receiving the fees.
+	//
+	// [7] = [WRITE] poolMint
+	// ··········· The pool token mint.
+	//
+	// [8] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [9] = [] stakeHistory
+	// ··········· The stake history sysvar.
+	//
+	// [10] = [] stakeProgram
+	// ··········· The stake program.
+	//
+	// [11] = [] tokenProgram
+	// ··········· The token program of the pool mint.
+	//
+	// [12] = [SIGNER] solWithdrawAuthority
+	// ··········· The SOL withdraw authority, if the stake pool has one.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewWithdrawSolInstructionBuilder creates a new `WithdrawSol` instruction builder.
+func NewWithdrawSolInstructionBuilder() *WithdrawSol {
+	nd := &WithdrawSol{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 13),
+	}
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.SysVarStakeHistoryPubkey)
+	nd.AccountMetaSlice[10] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	nd.AccountMetaSlice[11] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetPoolTokens sets the "poolTokens" parameter.
+// The amount of pool tokens to burn.
+func (inst *WithdrawSol) SetPoolTokens(poolTokens uint64) *WithdrawSol {
+	inst.PoolTokens = &poolTokens
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *WithdrawSol) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *WithdrawSol) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *WithdrawSol) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *WithdrawSol) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetUserTransferAuthorityAccount sets the "userTransferAuthority" account.
+// The owner or the delegate of the pool token account.
+func (inst *WithdrawSol) SetUserTransferAuthorityAccount(userTransferAuthority ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(userTransferAuthority).SIGNER()
+	return inst
+}
+
+// GetUserTransferAuthorityAccount gets the "userTransferAuthority" account.
+// The owner or the delegate of the pool token account.
+func (inst *WithdrawSol) GetUserTransferAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetBurnFromPoolTokensAccount sets the "burnFromPoolTokens" account.
+// The pool token account of the burned pool tokens.
+func (inst *WithdrawSol) SetBurnFromPoolTokensAccount(burnFromPoolTokens ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(burnFromPoolTokens).WRITE()
+	return inst
+}
+
+// GetBurnFromPoolTokensAccount gets the "burnFromPoolTokens" account.
+// The pool token account of the burned pool tokens.
+func (inst *WithdrawSol) GetBurnFromPoolTokensAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetReserveStakeAccount sets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *WithdrawSol) SetReserveStakeAccount(reserveStake ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(reserveStake).WRITE()
+	return inst
+}
+
+// GetReserveStakeAccount gets the "reserveStake" account.
+// The reserve stake account of the stake pool.
+func (inst *WithdrawSol) GetReserveStakeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetDestinationLamportsAccount sets the "destinationLamports" account.
+// The system account receiving the lamports.
+func (inst *WithdrawSol) SetDestinationLamportsAccount(destinationLamports ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(destinationLamports).WRITE()
+	return inst
+}
+
+// GetDestinationLamportsAccount gets the "destinationLamports" account.
+// The system account receiving the lamports.
+func (inst *WithdrawSol) GetDestinationLamportsAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetManagerFeeAccount sets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *WithdrawSol) SetManagerFeeAccount(managerFee ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(managerFee).WRITE()
+	return inst
+}
+
+// GetManagerFeeAccount gets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *WithdrawSol) GetManagerFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The pool token mint.
+func (inst *WithdrawSol) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The pool token mint.
+func (inst *WithdrawSol) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *WithdrawSol) SetClockAccount(clock ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *WithdrawSol) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetStakeHistoryAccount sets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *WithdrawSol) SetStakeHistoryAccount(stakeHistory ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(stakeHistory)
+	return inst
+}
+
+// GetStakeHistoryAccount gets the "stakeHistory" account.
+// The stake history sysvar.
+func (inst *WithdrawSol) GetStakeHistoryAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *WithdrawSol) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *WithdrawSol) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *WithdrawSol) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[11] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *WithdrawSol) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(11)
+}
+
+// SetSolWithdrawAuthorityAccount sets the "solWithdrawAuthority" account.
+// The SOL withdraw authority, if the stake pool has one.
+func (inst *WithdrawSol) SetSolWithdrawAuthorityAccount(solWithdrawAuthority ag_solanago.PublicKey) *WithdrawSol {
+	inst.AccountMetaSlice[12] = ag_solanago.Meta(solWithdrawAuthority).SIGNER()
+	return inst
+}
+
+// GetSolWithdrawAuthorityAccount gets the "solWithdrawAuthority" account.
+// The SOL withdraw authority, if the stake pool has one.
+func (inst *WithdrawSol) GetSolWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(12)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst WithdrawSol) AccountNames() []string {
+	return []string{"stakePool", "withdrawAuthority", "userTransferAuthority", "burnFromPoolTokens", "reserveStake", "destinationLamports", "managerFee", "poolMint", "clock", "stakeHistory", "stakeProgram", "tokenProgram", "solWithdrawAuthority"}
+}
+
+func (inst WithdrawSol) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_WithdrawSol),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst WithdrawSol) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *WithdrawSol) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.PoolTokens == nil {
+			return errors.New("PoolTokens parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.UserTransferAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.BurnFromPoolTokens is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ReserveStake is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.DestinationLamports is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.ManagerFee is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.StakeHistory is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+		if inst.AccountMetaSlice[11] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *WithdrawSol) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("WithdrawSol")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("PoolTokens", inst.PoolTokens))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("            stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("    withdrawAuthority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("userTransferAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("   burnFromPoolTokens", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("         reserveStake", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("  destinationLamports", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("           managerFee", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("             poolMint", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("                clock", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("         stakeHistory", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("         stakeProgram", inst.AccountMetaSlice.Get(10)))
+						accountsBranch.Child(ag_format.Meta("         tokenProgram", inst.AccountMetaSlice.Get(11)))
+						accountsBranch.Child(ag_format.Meta(" solWithdrawAuthority", inst.AccountMetaSlice.Get(12)))
+					})
+				})
+		})
+}
+
+func (obj WithdrawSol) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `PoolTokens` param:
+	err = encoder.Encode(obj.PoolTokens)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *WithdrawSol) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `PoolTokens`:
+	err = decoder.Decode(&obj.PoolTokens)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewWithdrawSolInstruction declares a new WithdrawSol instruction with the provided parameters and accounts.
+func NewWithdrawSolInstruction(
+	// Parameters:
+	poolTokens uint64,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	userTransferAuthority ag_solanago.PublicKey,
+	burnFromPoolTokens ag_solanago.PublicKey,
+	reserveStake ag_solanago.PublicKey,
+	destinationLamports ag_solanago.PublicKey,
+	managerFee ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+) *WithdrawSol {
+	return NewWithdrawSolInstructionBuilder().
+		SetPoolTokens(poolTokens).
+		SetStakePoolAccount(stakePool).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetUserTransferAuthorityAccount(userTransferAuthority).
+		SetBurnFromPoolTokensAccount(burnFromPoolTokens).
+		SetReserveStakeAccount(reserveStake).
+		SetDestinationLamportsAccount(destinationLamports).
+		SetManagerFeeAccount(managerFee).
+		SetPoolMintAccount(poolMint)
+}
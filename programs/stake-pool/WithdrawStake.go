@@ -0,0 +1,413 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Burns pool tokens, for a stake account split from a validator stake account
+// or from the reserve.
+type WithdrawStake struct {
+	// The amount of pool tokens to burn.
+	PoolTokens *uint64
+
+	// [0] = [WRITE] stakePool
+	// ··········· The stake pool.
+	//
+	// [1] = [WRITE] validatorList
+	// ··········· The validator list of the stake pool.
+	//
+	// [2] = [] withdrawAuthority
+	// ··········· The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+	//
+	// [3] = [WRITE] stakeSplitFrom
+	// ··········· The validator stake account, or the reserve, to split the stake from.
+	//
+	// [4] = [WRITE] stakeSplitTo
+	// ··········· The uninitialized stake account receiving the stake.
+	//
+	// [5] = [] userStakeAuthority
+	// ··········· The staker and withdrawer of the new stake account.
+	//
+	// [6] = [SIGNER] userTransferAuthority
+	// ··········· The owner or the delegate of the pool token account.
+	//
+	// [7] = [WRITE] burnFromPoolTokens
+	// ··········· The pool token account of the burned pool tokens.
+	//
+	// [8] = [WRITE] managerFee
+	// ··········· The pool token account of the manager receiving the fees.
+	//
+	// [9] = [WRITE] poolMint
+	// ··········· The pool token mint.
+	//
+	// [10] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [11] = [] tokenProgram
+	// ··········· The token program of the pool mint.
+	//
+	// [12] = [] stakeProgram
+	// ··········· The stake program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewWithdrawStakeInstructionBuilder creates a new `WithdrawStake` instruction builder.
+func NewWithdrawStakeInstructionBuilder() *WithdrawStake {
+	nd := &WithdrawStake{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 13),
+	}
+	nd.AccountMetaSlice[10] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[11] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	nd.AccountMetaSlice[12] = ag_solanago.Meta(ag_solanago.StakeProgramID)
+	return nd
+}
+
+// SetPoolTokens sets the "poolTokens" parameter.
+// The amount of pool tokens to burn.
+func (inst *WithdrawStake) SetPoolTokens(poolTokens uint64) *WithdrawStake {
+	inst.PoolTokens = &poolTokens
+	return inst
+}
+
+// SetStakePoolAccount sets the "stakePool" account.
+// The stake pool.
+func (inst *WithdrawStake) SetStakePoolAccount(stakePool ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(stakePool).WRITE()
+	return inst
+}
+
+// GetStakePoolAccount gets the "stakePool" account.
+// The stake pool.
+func (inst *WithdrawStake) GetStakePoolAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetValidatorListAccount sets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *WithdrawStake) SetValidatorListAccount(validatorList ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(validatorList).WRITE()
+	return inst
+}
+
+// GetValidatorListAccount gets the "validatorList" account.
+// The validator list of the stake pool.
+func (inst *WithdrawStake) GetValidatorListAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetWithdrawAuthorityAccount sets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *WithdrawStake) SetWithdrawAuthorityAccount(withdrawAuthority ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(withdrawAuthority)
+	return inst
+}
+
+// GetWithdrawAuthorityAccount gets the "withdrawAuthority" account.
+// The withdraw authority of the stake pool, at FindWithdrawAuthorityAddress(stakePool).
+func (inst *WithdrawStake) GetWithdrawAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetStakeSplitFromAccount sets the "stakeSplitFrom" account.
+// The validator stake account, or the reserve, to split the stake from.
+func (inst *WithdrawStake) SetStakeSplitFromAccount(stakeSplitFrom ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(stakeSplitFrom).WRITE()
+	return inst
+}
+
+// GetStakeSplitFromAccount gets the "stakeSplitFrom" account.
+// The validator stake account, or the reserve, to split the stake from.
+func (inst *WithdrawStake) GetStakeSplitFromAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetStakeSplitToAccount sets the "stakeSplitTo" account.
+// The uninitialized stake account receiving the stake.
+func (inst *WithdrawStake) SetStakeSplitToAccount(stakeSplitTo ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(stakeSplitTo).WRITE()
+	return inst
+}
+
+// GetStakeSplitToAccount gets the "stakeSplitTo" account.
+// The uninitialized stake account receiving the stake.
+func (inst *WithdrawStake) GetStakeSplitToAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetUserStakeAuthorityAccount sets the "userStakeAuthority" account.
+// The staker and withdrawer of the new stake account.
+func (inst *WithdrawStake) SetUserStakeAuthorityAccount(userStakeAuthority ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(userStakeAuthority)
+	return inst
+}
+
+// GetUserStakeAuthorityAccount gets the "userStakeAuthority" account.
+// The staker and withdrawer of the new stake account.
+func (inst *WithdrawStake) GetUserStakeAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetUserTransferAuthorityAccount sets the "userTransferAuthority" account.
+// The owner or the delegate of the pool token account.
+func (inst *WithdrawStake) SetUserTransferAuthorityAccount(userTransferAuthority ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(userTransferAuthority).SIGNER()
+	return inst
+}
+
+// GetUserTransferAuthorityAccount gets the "userTransferAuthority" account.
+// The owner or the delegate of the pool token account.
+func (inst *WithdrawStake) GetUserTransferAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetBurnFromPoolTokensAccount sets the "burnFromPoolTokens" account.
+// The pool token account of the burned pool tokens.
+func (inst *WithdrawStake) SetBurnFromPoolTokensAccount(burnFromPoolTokens ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(burnFromPoolTokens).WRITE()
+	return inst
+}
+
+// GetBurnFromPoolTokensAccount gets the "burnFromPoolTokens" account.
+// The pool token account of the burned pool tokens.
+func (inst *WithdrawStake) GetBurnFromPoolTokensAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetManagerFeeAccount sets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *WithdrawStake) SetManagerFeeAccount(managerFee ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(managerFee).WRITE()
+	return inst
+}
+
+// GetManagerFeeAccount gets the "managerFee" account.
+// The pool token account of the manager receiving the fees.
+func (inst *WithdrawStake) GetManagerFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The pool token mint.
+func (inst *WithdrawStake) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The pool token mint.
+func (inst *WithdrawStake) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *WithdrawStake) SetClockAccount(clock ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *WithdrawStake) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *WithdrawStake) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[11] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program of the pool mint.
+func (inst *WithdrawStake) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(11)
+}
+
+// SetStakeProgramAccount sets the "stakeProgram" account.
+// The stake program.
+func (inst *WithdrawStake) SetStakeProgramAccount(stakeProgram ag_solanago.PublicKey) *WithdrawStake {
+	inst.AccountMetaSlice[12] = ag_solanago.Meta(stakeProgram)
+	return inst
+}
+
+// GetStakeProgramAccount gets the "stakeProgram" account.
+// The stake program.
+func (inst *WithdrawStake) GetStakeProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(12)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst WithdrawStake) AccountNames() []string {
+	return []string{"stakePool", "validatorList", "withdrawAuthority", "stakeSplitFrom", "stakeSplitTo", "userStakeAuthority", "userTransferAuthority", "burnFromPoolTokens", "managerFee", "poolMint", "clock", "tokenProgram", "stakeProgram"}
+}
+
+func (inst WithdrawStake) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_WithdrawStake),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst WithdrawStake) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *WithdrawStake) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.PoolTokens == nil {
+			return errors.New("PoolTokens parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.StakePool is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.ValidatorList is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.WithdrawAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.StakeSplitFrom is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.StakeSplitTo is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.UserStakeAuthority is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.UserTransferAuthority is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.BurnFromPoolTokens is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.ManagerFee is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[11] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+		if inst.AccountMetaSlice[12] == nil {
+			return errors.New("accounts.StakeProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *WithdrawStake) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("WithdrawStake")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("PoolTokens", inst.PoolTokens))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("            stakePool", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("        validatorList", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("    withdrawAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("       stakeSplitFrom", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("         stakeSplitTo", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("   userStakeAuthority", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("userTransferAuthority", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("   burnFromPoolTokens", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("           managerFee", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("             poolMint", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("                clock", inst.AccountMetaSlice.Get(10)))
+						accountsBranch.Child(ag_format.Meta("         tokenProgram", inst.AccountMetaSlice.Get(11)))
+						accountsBranch.Child(ag_format.Meta("         stakeProgram", inst.AccountMetaSlice.Get(12)))
+					})
+				})
+		})
+}
+
+func (obj WithdrawStake) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `PoolTokens` param:
+	err = encoder.Encode(obj.PoolTokens)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *WithdrawStake) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `PoolTokens`:
+	err = decoder.Decode(&obj.PoolTokens)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewWithdrawStakeInstruction declares a new WithdrawStake instruction with the provided parameters and accounts.
+func NewWithdrawStakeInstruction(
+	// Parameters:
+	poolTokens uint64,
+	// Accounts:
+	stakePool ag_solanago.PublicKey,
+	validatorList ag_solanago.PublicKey,
+	withdrawAuthority ag_solanago.PublicKey,
+	stakeSplitFrom ag_solanago.PublicKey,
+	stakeSplitTo ag_solanago.PublicKey,
+	userStakeAuthority ag_solanago.PublicKey,
+	userTransferAuthority ag_solanago.PublicKey,
+	burnFromPoolTokens ag_solanago.PublicKey,
+	managerFee ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+) *WithdrawStake {
+	return NewWithdrawStakeInstructionBuilder().
+		SetPoolTokens(poolTokens).
+		SetStakePoolAccount(stakePool).
+		SetValidatorListAccount(validatorList).
+		SetWithdrawAuthorityAccount(withdrawAuthority).
+		SetStakeSplitFromAccount(stakeSplitFrom).
+		SetStakeSplitToAccount(stakeSplitTo).
+		SetUserStakeAuthorityAccount(userStakeAuthority).
+		SetUserTransferAuthorityAccount(userTransferAuthority).
+		SetBurnFromPoolTokensAccount(burnFromPoolTokens).
+		SetManagerFeeAccount(managerFee).
+		SetPoolMintAccount(poolMint)
+}
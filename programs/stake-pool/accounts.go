@@ -0,0 +1,262 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+)
+
+// StakePool is the state of a stake pool account.
+type StakePool struct {
+	AccountType AccountType
+
+	// The manager of the pool, who sets its fees and authorities.
+	Manager ag_solanago.PublicKey
+
+	// The staker of the pool, who adds and removes validators and moves
+	// stake between them.
+	Staker ag_solanago.PublicKey
+
+	// The authority of the deposits of stake: FindDepositAuthorityAddress of
+	// the pool, unless the manager set another one.
+	StakeDepositAuthority ag_solanago.PublicKey
+
+	// The bump seed of the withdraw authority of the pool.
+	StakeWithdrawBumpSeed uint8
+
+	ValidatorList ag_solanago.PublicKey
+	ReserveStake  ag_solanago.PublicKey
+	PoolMint      ag_solanago.PublicKey
+
+	// The pool token account receiving the fees of the manager.
+	ManagerFeeAccount ag_solanago.PublicKey
+
+	// The token program of the pool mint.
+	TokenProgramID ag_solanago.PublicKey
+
+	// The lamports of the pool, at the last update.
+	TotalLamports uint64
+
+	// The supply of the pool mint, at the last update.
+	PoolTokenSupply uint64
+
+	LastUpdateEpoch uint64
+
+	// The lockup of the stake accounts of the pool.
+	Lockup stake.Lockup
+
+	// The fee on the rewards of the pool, at each epoch.
+	EpochFee     Fee
+	NextEpochFee FutureEpochFee
+
+	PreferredDepositValidatorVoteAddress  *ag_solanago.PublicKey `bin:"optional"`
+	PreferredWithdrawValidatorVoteAddress *ag_solanago.PublicKey `bin:"optional"`
+
+	StakeDepositFee        Fee
+	StakeWithdrawalFee     Fee
+	NextStakeWithdrawalFee FutureEpochFee
+
+	// The percentage of the stake deposit fees that goes to the referrers.
+	StakeReferralFee uint8
+
+	// The authority of the deposits of SOL, if any.
+	SolDepositAuthority *ag_solanago.PublicKey `bin:"optional"`
+	SolDepositFee       Fee
+
+	// The percentage of the SOL deposit fees that goes to the referrers.
+	SolReferralFee uint8
+
+	// The authority of the withdrawals of SOL, if any.
+	SolWithdrawAuthority     *ag_solanago.PublicKey `bin:"optional"`
+	SolWithdrawalFee         Fee
+	NextSolWithdrawalFee     FutureEpochFee
+	LastEpochPoolTokenSupply uint64
+	LastEpochTotalLamports   uint64
+}
+
+// WithdrawAuthority returns the withdraw authority of the stake pool at the
+// address.
+func (pool *StakePool) WithdrawAuthority(address ag_solanago.PublicKey) (ag_solanago.PublicKey, error) {
+	return ag_solanago.CreateProgramAddress([][]byte{address[:], []byte(withdrawAuthoritySeed), {pool.StakeWithdrawBumpSeed}}, ProgramID)
+}
+
+// PoolTokensForDeposit returns the pool tokens worth the lamports, before
+// the deposit fees; the first deposits get one pool token per lamport.
+func (pool *StakePool) PoolTokensForDeposit(lamports uint64) (uint64, bool) {
+	if pool.TotalLamports == 0 || pool.PoolTokenSupply == 0 {
+		return lamports, true
+	}
+	return mulDiv(lamports, pool.PoolTokenSupply, pool.TotalLamports)
+}
+
+// LamportsForWithdrawal returns the lamports worth the pool tokens, before
+// the withdrawal fees.
+func (pool *StakePool) LamportsForWithdrawal(poolTokens uint64) (uint64, bool) {
+	if pool.PoolTokenSupply == 0 {
+		return 0, false
+	}
+	return mulDiv(poolTokens, pool.TotalLamports, pool.PoolTokenSupply)
+}
+
+// mulDiv returns a*b/c, rounded down; it fails if the quotient overflows.
+func mulDiv(a, b, c uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= c {
+		return 0, false
+	}
+	quo, _ := bits.Div64(hi, lo, c)
+	return quo, true
+}
+
+// ValidatorList is the state of the validator list account of a stake pool.
+type ValidatorList struct {
+	AccountType AccountType
+
+	// The maximum number of validators, the capacity of the account.
+	MaxValidators uint32
+
+	Validators []ValidatorStakeInfo
+}
+
+// VALIDATOR_STAKE_INFO_LEN is the size of the validators of the validator
+// list.
+const VALIDATOR_STAKE_INFO_LEN = 73
+
+// ValidatorStakeInfo is a validator of a stake pool, with the balances of its
+// stake accounts at the last update.
+type ValidatorStakeInfo struct {
+	// The lamports of the validator stake account.
+	ActiveStakeLamports uint64
+
+	// The lamports of the transient stake account.
+	TransientStakeLamports uint64
+
+	LastUpdateEpoch uint64
+
+	// The seed of the transient stake account.
+	TransientSeedSuffix uint64
+
+	Unused uint32
+
+	// The seed of the validator stake account; zero for none.
+	ValidatorSeedSuffix uint32
+
+	Status StakeStatus
+
+	VoteAccountAddress ag_solanago.PublicKey
+}
+
+// StakeLamports returns the lamports of the stake accounts of the validator.
+func (info *ValidatorStakeInfo) StakeLamports() uint64 {
+	return info.ActiveStakeLamports + info.TransientStakeLamports
+}
+
+// StakeAddress returns the validator stake account of the validator in the
+// stake pool.
+func (info *ValidatorStakeInfo) StakeAddress(stakePool ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return FindValidatorStakeAddress(info.VoteAccountAddress, stakePool, info.ValidatorSeedSuffix)
+}
+
+// TransientStakeAddress returns the transient stake account of the
+// validator in the stake pool.
+func (info *ValidatorStakeInfo) TransientStakeAddress(stakePool ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return FindTransientStakeAddress(info.VoteAccountAddress, stakePool, info.TransientSeedSuffix)
+}
+
+// Find returns the validator of the vote account, or nil.
+func (list *ValidatorList) Find(voteAccount ag_solanago.PublicKey) *ValidatorStakeInfo {
+	for i := range list.Validators {
+		if list.Validators[i].VoteAccountAddress.Equals(voteAccount) {
+			return &list.Validators[i]
+		}
+	}
+	return nil
+}
+
+// StakeAccounts returns the validator and transient stake accounts of the
+// validators, from the start index, for UpdateValidatorListBalance.
+func (list *ValidatorList) StakeAccounts(stakePool ag_solanago.PublicKey, start, count int) ([]ag_solanago.PublicKey, error) {
+	if start > len(list.Validators) {
+		start = len(list.Validators)
+	}
+	end := start + count
+	if end > len(list.Validators) {
+		end = len(list.Validators)
+	}
+	out := make([]ag_solanago.PublicKey, 0, 2*(end-start))
+	for i := start; i < end; i++ {
+		validatorStake, _, err := list.Validators[i].StakeAddress(stakePool)
+		if err != nil {
+			return nil, err
+		}
+		transientStake, _, err := list.Validators[i].TransientStakeAddress(stakePool)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, validatorStake, transientStake)
+	}
+	return out, nil
+}
+
+// DecodeStakePool decodes the data of a stake pool account.
+func DecodeStakePool(data []byte) (*StakePool, error) {
+	if len(data) == 0 || AccountType(data[0]) != AccountTypeStakePool {
+		return nil, errors.New("unable to decode stake pool: not a stake pool account")
+	}
+	pool := new(StakePool)
+	if err := ag_binary.NewBorshDecoder(data).Decode(pool); err != nil {
+		return nil, fmt.Errorf("unable to decode stake pool: %w", err)
+	}
+	return pool, nil
+}
+
+// DecodeValidatorList decodes the data of a validator list account.
+func DecodeValidatorList(data []byte) (*ValidatorList, error) {
+	if len(data) == 0 || AccountType(data[0]) != AccountTypeValidatorList {
+		return nil, errors.New("unable to decode validator list: not a validator list account")
+	}
+	list := new(ValidatorList)
+	if err := ag_binary.NewBorshDecoder(data).Decode(list); err != nil {
+		return nil, fmt.Errorf("unable to decode validator list: %w", err)
+	}
+	return list, nil
+}
+
+func registerAccountDecoders(programID ag_solanago.PublicKey) {
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountDiscriminatorMatcher([]byte{byte(AccountTypeStakePool)}), decodeStakePoolAccount)
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountDiscriminatorMatcher([]byte{byte(AccountTypeValidatorList)}), decodeValidatorListAccount)
+}
+
+func decodeStakePoolAccount(data []byte) (interface{}, error) {
+	out, err := DecodeStakePool(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeValidatorListAccount(data []byte) (interface{}, error) {
+	out, err := DecodeValidatorList(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
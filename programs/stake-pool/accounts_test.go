@@ -0,0 +1,215 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"bytes"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func encode(t *testing.T, v interface{}, size int) []byte {
+	buf := new(bytes.Buffer)
+	ag_require.NoError(t, ag_binary.NewBorshEncoder(buf).Encode(v))
+	ag_require.LessOrEqual(t, buf.Len(), size)
+	return append(buf.Bytes(), make([]byte, size-buf.Len())...)
+}
+
+func TestDecodeStakePool(t *testing.T) {
+	unixTimestamp, epoch, custodian := int64(0), uint64(0), ag_solanago.PublicKey{}
+	preferred := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	pool := &StakePool{
+		AccountType:                           AccountTypeStakePool,
+		Manager:                               ag_solanago.SysVarRentPubkey,
+		Staker:                                ag_solanago.SysVarClockPubkey,
+		StakeWithdrawBumpSeed:                 255,
+		TokenProgramID:                        ag_solanago.TokenProgramID,
+		TotalLamports:                         2_000_000_000,
+		PoolTokenSupply:                       1_000_000_000,
+		LastUpdateEpoch:                       500,
+		Lockup:                                stake.Lockup{UnixTimestamp: &unixTimestamp, Epoch: &epoch, Custodian: &custodian},
+		EpochFee:                              NewFee(5, 100),
+		NextEpochFee:                          FutureEpochFee{Kind: FutureEpochTwo, Fee: NewFee(3, 100)},
+		PreferredWithdrawValidatorVoteAddress: &preferred,
+		StakeWithdrawalFee:                    NewFee(1, 1000),
+		StakeReferralFee:                      50,
+		SolDepositFee:                         NewFee(1, 200),
+	}
+	// The pool accounts are allocated larger than their data.
+	data := encode(t, pool, 1000)
+	decoded, err := DecodeStakePool(data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, pool, decoded)
+	ag_require.Nil(t, decoded.PreferredDepositValidatorVoteAddress)
+	fee, ok := decoded.NextEpochFee.Get()
+	ag_require.True(t, ok)
+	ag_require.Equal(t, NewFee(3, 100), fee)
+
+	account, err := ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	ag_require.IsType(t, &StakePool{}, account)
+
+	poolTokens, ok := decoded.PoolTokensForDeposit(1_000)
+	ag_require.True(t, ok)
+	ag_require.Equal(t, uint64(500), poolTokens)
+	lamports, ok := decoded.LamportsForWithdrawal(poolTokens)
+	ag_require.True(t, ok)
+	ag_require.Equal(t, uint64(1_000), lamports)
+
+	_, err = DecodeStakePool(data[:100])
+	ag_require.Error(t, err)
+	_, err = DecodeValidatorList(data)
+	ag_require.Error(t, err)
+}
+
+func TestDecodeStakePool_Layout(t *testing.T) {
+	key := func(b byte) ag_solanago.PublicKey {
+		var k ag_solanago.PublicKey
+		for i := range k {
+			k[i] = b
+		}
+		return k
+	}
+	data := make([]byte, 611)
+	putU64 := func(offset int, v uint64) { ag_binary.LE.PutUint64(data[offset:], v) }
+	putKey := func(offset int, k ag_solanago.PublicKey) { copy(data[offset:], k[:]) }
+	putFee := func(offset int, denominator, numerator uint64) {
+		putU64(offset, denominator)
+		putU64(offset+8, numerator)
+	}
+
+	// The layout of spl-stake-pool's StakePool, with the offsets of the
+	// fields that follow the first optional field for these values.
+	data[0] = byte(AccountTypeStakePool)
+	putKey(1, key(1))   // manager
+	putKey(33, key(2))  // staker
+	putKey(65, key(3))  // stake_deposit_authority
+	data[97] = 254      // stake_withdraw_bump_seed
+	putKey(98, key(4))  // validator_list
+	putKey(130, key(5)) // reserve_stake
+	putKey(162, key(6)) // pool_mint
+	putKey(194, key(7)) // manager_fee_account
+	putKey(226, ag_solanago.TokenProgramID)
+	putU64(258, 2_000_000_000) // total_lamports
+	putU64(266, 1_900_000_000) // pool_token_supply
+	putU64(274, 500)           // last_update_epoch
+	putU64(282, 7)             // lockup.unix_timestamp
+	putU64(290, 8)             // lockup.epoch
+	putKey(298, key(9))        // lockup.custodian
+	putFee(330, 100, 5)        // epoch_fee
+	data[346] = 2              // next_epoch_fee: Two
+	putFee(347, 100, 3)
+	data[363] = 0 // preferred_deposit_validator_vote_address: None
+	data[364] = 1 // preferred_withdraw_validator_vote_address: Some
+	putKey(365, key(10))
+	putFee(397, 1000, 2) // stake_deposit_fee
+	putFee(413, 1000, 1) // stake_withdrawal_fee
+	data[429] = 0        // next_stake_withdrawal_fee: None
+	data[430] = 50       // stake_referral_fee
+	data[431] = 1        // sol_deposit_authority: Some
+	putKey(432, key(11))
+	putFee(464, 200, 1) // sol_deposit_fee
+	data[480] = 25      // sol_referral_fee
+	data[481] = 0       // sol_withdraw_authority: None
+	putFee(482, 300, 1) // sol_withdrawal_fee
+	data[498] = 1       // next_sol_withdrawal_fee: One
+	putFee(499, 10, 1)
+	putU64(515, 1_800_000_000) // last_epoch_pool_token_supply
+	putU64(523, 1_950_000_000) // last_epoch_total_lamports
+
+	pool, err := DecodeStakePool(data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, key(1), pool.Manager)
+	ag_require.Equal(t, key(2), pool.Staker)
+	ag_require.Equal(t, key(3), pool.StakeDepositAuthority)
+	ag_require.Equal(t, uint8(254), pool.StakeWithdrawBumpSeed)
+	ag_require.Equal(t, key(4), pool.ValidatorList)
+	ag_require.Equal(t, key(5), pool.ReserveStake)
+	ag_require.Equal(t, key(6), pool.PoolMint)
+	ag_require.Equal(t, key(7), pool.ManagerFeeAccount)
+	ag_require.Equal(t, ag_solanago.TokenProgramID, pool.TokenProgramID)
+	ag_require.Equal(t, uint64(2_000_000_000), pool.TotalLamports)
+	ag_require.Equal(t, uint64(1_900_000_000), pool.PoolTokenSupply)
+	ag_require.Equal(t, uint64(500), pool.LastUpdateEpoch)
+	ag_require.Equal(t, int64(7), *pool.Lockup.UnixTimestamp)
+	ag_require.Equal(t, uint64(8), *pool.Lockup.Epoch)
+	ag_require.Equal(t, key(9), *pool.Lockup.Custodian)
+	ag_require.Equal(t, NewFee(5, 100), pool.EpochFee)
+	ag_require.Equal(t, FutureEpochFee{Kind: FutureEpochTwo, Fee: NewFee(3, 100)}, pool.NextEpochFee)
+	ag_require.Nil(t, pool.PreferredDepositValidatorVoteAddress)
+	ag_require.Equal(t, key(10), *pool.PreferredWithdrawValidatorVoteAddress)
+	ag_require.Equal(t, NewFee(2, 1000), pool.StakeDepositFee)
+	ag_require.Equal(t, NewFee(1, 1000), pool.StakeWithdrawalFee)
+	ag_require.Equal(t, FutureEpochNone, pool.NextStakeWithdrawalFee.Kind)
+	ag_require.Equal(t, uint8(50), pool.StakeReferralFee)
+	ag_require.Equal(t, key(11), *pool.SolDepositAuthority)
+	ag_require.Equal(t, NewFee(1, 200), pool.SolDepositFee)
+	ag_require.Equal(t, uint8(25), pool.SolReferralFee)
+	ag_require.Nil(t, pool.SolWithdrawAuthority)
+	ag_require.Equal(t, NewFee(1, 300), pool.SolWithdrawalFee)
+	ag_require.Equal(t, FutureEpochFee{Kind: FutureEpochOne, Fee: NewFee(1, 10)}, pool.NextSolWithdrawalFee)
+	ag_require.Equal(t, uint64(1_800_000_000), pool.LastEpochPoolTokenSupply)
+	ag_require.Equal(t, uint64(1_950_000_000), pool.LastEpochTotalLamports)
+
+	// Encoding the decoded pool gives back the layout.
+	ag_require.Equal(t, data, encode(t, pool, len(data)))
+}
+
+func TestDecodeValidatorList(t *testing.T) {
+	vote := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	list := &ValidatorList{
+		AccountType:   AccountTypeValidatorList,
+		MaxValidators: 3,
+		Validators: []ValidatorStakeInfo{
+			{ActiveStakeLamports: 10, TransientStakeLamports: 5, LastUpdateEpoch: 500, VoteAccountAddress: ag_solanago.SysVarRentPubkey},
+			{ActiveStakeLamports: 20, TransientSeedSuffix: 1, ValidatorSeedSuffix: 2, Status: StakeStatusDeactivatingAll, VoteAccountAddress: vote},
+		},
+	}
+	size := 1 + 4 + 4 + 3*VALIDATOR_STAKE_INFO_LEN
+	data := encode(t, list, size)
+	ag_require.Equal(t, byte(StakeStatusDeactivatingAll), data[9+2*VALIDATOR_STAKE_INFO_LEN-33])
+
+	decoded, err := DecodeValidatorList(data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, list, decoded)
+	ag_require.Equal(t, uint64(15), decoded.Validators[0].StakeLamports())
+	ag_require.Equal(t, &decoded.Validators[1], decoded.Find(vote))
+	ag_require.Nil(t, decoded.Find(ag_solanago.SysVarClockPubkey))
+
+	account, err := ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	ag_require.IsType(t, &ValidatorList{}, account)
+}
+
+func TestFindValidatorStakeAddress(t *testing.T) {
+	stakePool := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	vote := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+
+	unseeded, _, err := ag_solanago.FindProgramAddress([][]byte{vote[:], stakePool[:]}, ProgramID)
+	ag_require.NoError(t, err)
+	address, _, err := FindValidatorStakeAddress(vote, stakePool, 0)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, unseeded, address)
+
+	seeded, _, err := ag_solanago.FindProgramAddress([][]byte{vote[:], stakePool[:], {7, 0, 0, 0}}, ProgramID)
+	ag_require.NoError(t, err)
+	address, _, err = FindValidatorStakeAddress(vote, stakePool, 7)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, seeded, address)
+}
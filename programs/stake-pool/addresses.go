@@ -0,0 +1,65 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"encoding/binary"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+// The seeds of the program addresses.
+const (
+	withdrawAuthoritySeed = "withdraw"
+	depositAuthoritySeed  = "deposit"
+	transientStakeSeed    = "transient"
+	ephemeralStakeSeed    = "ephemeral"
+)
+
+// FindWithdrawAuthorityAddress returns the withdraw authority of the stake
+// pool, the staker and withdrawer of its stake accounts and the mint
+// authority of its pool mint.
+func FindWithdrawAuthorityAddress(stakePool ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{stakePool[:], []byte(withdrawAuthoritySeed)}, ProgramID)
+}
+
+// FindDepositAuthorityAddress returns the default deposit authority of the
+// stake pool, which the deposited stake accounts must be authorized to.
+func FindDepositAuthorityAddress(stakePool ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{stakePool[:], []byte(depositAuthoritySeed)}, ProgramID)
+}
+
+// FindValidatorStakeAddress returns the stake account of the validator of
+// the vote account in the stake pool; a zero seed is no seed.
+func FindValidatorStakeAddress(voteAccount, stakePool ag_solanago.PublicKey, seed uint32) (ag_solanago.PublicKey, uint8, error) {
+	seeds := [][]byte{voteAccount[:], stakePool[:]}
+	if seed != 0 {
+		seeds = append(seeds, binary.LittleEndian.AppendUint32(nil, seed))
+	}
+	return ag_solanago.FindProgramAddress(seeds, ProgramID)
+}
+
+// FindTransientStakeAddress returns the transient stake account of the
+// validator of the vote account in the stake pool, which holds the stake
+// being activated or deactivated.
+func FindTransientStakeAddress(voteAccount, stakePool ag_solanago.PublicKey, seed uint64) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{[]byte(transientStakeSeed), voteAccount[:], stakePool[:], binary.LittleEndian.AppendUint64(nil, seed)}, ProgramID)
+}
+
+// FindEphemeralStakeAddress returns the ephemeral stake account of the stake
+// pool, used to move stake between validators.
+func FindEphemeralStakeAddress(stakePool ag_solanago.PublicKey, seed uint64) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{[]byte(ephemeralStakeSeed), stakePool[:], binary.LittleEndian.AppendUint64(nil, seed)}, ProgramID)
+}
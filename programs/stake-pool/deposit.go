@@ -0,0 +1,73 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	ag_solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+)
+
+// NewDepositStakeInstructions returns the instructions depositing a stake
+// account into the stake pool at the address: the authorization of the
+// deposit authority of the pool as staker and withdrawer of the stake
+// account, then the deposit.
+//
+// The stake account must be active and delegated to a validator of the pool,
+// whose validator stake account is validatorStake; the current authority of
+// the stake account must sign, and so must the deposit authority of the pool
+// if the manager set one.
+func NewDepositStakeInstructions(
+	stakePoolAddress ag_solanago.PublicKey,
+	stakePool *StakePool,
+	depositStake ag_solanago.PublicKey,
+	depositStakeAuthority ag_solanago.PublicKey,
+	validatorStake ag_solanago.PublicKey,
+	destinationPoolTokens ag_solanago.PublicKey,
+	referralPoolTokens ag_solanago.PublicKey,
+) ([]ag_solanago.Instruction, error) {
+	withdrawAuthority, err := stakePool.WithdrawAuthority(stakePoolAddress)
+	if err != nil {
+		return nil, err
+	}
+	defaultDepositAuthority, _, err := FindDepositAuthorityAddress(stakePoolAddress)
+	if err != nil {
+		return nil, err
+	}
+	depositAuthority := stakePool.StakeDepositAuthority
+	isDepositAuthoritySigner := !depositAuthority.Equals(defaultDepositAuthority)
+
+	deposit, err := NewDepositStakeInstruction(
+		stakePoolAddress,
+		stakePool.ValidatorList,
+		depositAuthority,
+		isDepositAuthoritySigner,
+		withdrawAuthority,
+		depositStake,
+		validatorStake,
+		stakePool.ReserveStake,
+		destinationPoolTokens,
+		stakePool.ManagerFeeAccount,
+		referralPoolTokens,
+		stakePool.PoolMint,
+	).SetTokenProgramAccount(stakePool.TokenProgramID).ValidateAndBuild()
+	if err != nil {
+		return nil, err
+	}
+	return []ag_solanago.Instruction{
+		stake.NewAuthorizeInstruction(depositAuthority, stake.StakeAuthorizeStaker, depositStake, depositStakeAuthority).Build(),
+		stake.NewAuthorizeInstruction(depositAuthority, stake.StakeAuthorizeWithdrawer, depositStake, depositStakeAuthority).Build(),
+		deposit,
+	}, nil
+}
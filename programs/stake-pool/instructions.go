@@ -0,0 +1,321 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stakepool builds the instructions of the SPL Stake Pool program,
+// decodes its stake pool and validator list accounts, and derives the
+// addresses of the pool authorities and of its validator stake accounts.
+package stakepool
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_spew "github.com/davecgh/go-spew/spew"
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_text "github.com/gagliardetto/solana-go/text"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+var ProgramID ag_solanago.PublicKey = ag_solanago.SPLStakePoolProgramID
+
+func SetProgramID(pubkey ag_solanago.PublicKey) {
+	ProgramID = pubkey
+	ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
+}
+
+const ProgramName = "StakePool"
+
+func init() {
+	if !ProgramID.IsZero() {
+		ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+		registerAccountDecoders(ProgramID)
+	}
+}
+
+const (
+	// Initializes a stake pool.
+	Instruction_Initialize uint8 = iota
+
+	// Adds a validator to a stake pool.
+	Instruction_AddValidatorToPool
+
+	// Removes a validator from a stake pool.
+	Instruction_RemoveValidatorFromPool
+
+	// Moves stake of a validator to its transient stake account (deprecated).
+	Instruction_DecreaseValidatorStake
+
+	// Moves lamports of the reserve to the transient stake account of a validator.
+	Instruction_IncreaseValidatorStake
+
+	// Sets the preferred deposit or withdraw validator of a stake pool.
+	Instruction_SetPreferredValidator
+
+	// Updates the balances of validators of a stake pool.
+	Instruction_UpdateValidatorListBalance
+
+	// Updates the total lamports of a stake pool.
+	Instruction_UpdateStakePoolBalance
+
+	// Removes the removed validators from the validator list.
+	Instruction_CleanupRemovedValidatorEntries
+
+	// Deposits a stake account into a stake pool.
+	Instruction_DepositStake
+
+	// Withdraws a stake account from a stake pool.
+	Instruction_WithdrawStake
+
+	// Sets the manager of a stake pool.
+	Instruction_SetManager
+
+	// Sets a fee of a stake pool.
+	Instruction_SetFee
+
+	// Sets the staker of a stake pool.
+	Instruction_SetStaker
+
+	// Deposits lamports into a stake pool.
+	Instruction_DepositSol
+
+	// Sets a deposit or withdraw authority of a stake pool.
+	Instruction_SetFundingAuthority
+
+	// Withdraws lamports from a stake pool.
+	Instruction_WithdrawSol
+
+	// Creates the token metadata of the pool mint.
+	Instruction_CreateTokenMetadata
+
+	// Updates the token metadata of the pool mint.
+	Instruction_UpdateTokenMetadata
+
+	// Moves lamports of the reserve to an additional transient stake account of a validator.
+	Instruction_IncreaseAdditionalValidatorStake
+
+	// Moves stake of a validator to an additional transient stake account.
+	Instruction_DecreaseAdditionalValidatorStake
+
+	// Moves stake of a validator to its transient stake account, with the rent funded by the reserve.
+	Instruction_DecreaseValidatorStakeWithReserve
+)
+
+// InstructionIDToName returns the name of the instruction given its ID.
+func InstructionIDToName(id uint8) string {
+	switch id {
+	case Instruction_Initialize:
+		return "Initialize"
+	case Instruction_AddValidatorToPool:
+		return "AddValidatorToPool"
+	case Instruction_RemoveValidatorFromPool:
+		return "RemoveValidatorFromPool"
+	case Instruction_DecreaseValidatorStake:
+		return "DecreaseValidatorStake"
+	case Instruction_IncreaseValidatorStake:
+		return "IncreaseValidatorStake"
+	case Instruction_SetPreferredValidator:
+		return "SetPreferredValidator"
+	case Instruction_UpdateValidatorListBalance:
+		return "UpdateValidatorListBalance"
+	case Instruction_UpdateStakePoolBalance:
+		return "UpdateStakePoolBalance"
+	case Instruction_CleanupRemovedValidatorEntries:
+		return "CleanupRemovedValidatorEntries"
+	case Instruction_DepositStake:
+		return "DepositStake"
+	case Instruction_WithdrawStake:
+		return "WithdrawStake"
+	case Instruction_SetManager:
+		return "SetManager"
+	case Instruction_SetFee:
+		return "SetFee"
+	case Instruction_SetStaker:
+		return "SetStaker"
+	case Instruction_DepositSol:
+		return "DepositSol"
+	case Instruction_SetFundingAuthority:
+		return "SetFundingAuthority"
+	case Instruction_WithdrawSol:
+		return "WithdrawSol"
+	case Instruction_CreateTokenMetadata:
+		return "CreateTokenMetadata"
+	case Instruction_UpdateTokenMetadata:
+		return "UpdateTokenMetadata"
+	case Instruction_IncreaseAdditionalValidatorStake:
+		return "IncreaseAdditionalValidatorStake"
+	case Instruction_DecreaseAdditionalValidatorStake:
+		return "DecreaseAdditionalValidatorStake"
+	case Instruction_DecreaseValidatorStakeWithReserve:
+		return "DecreaseValidatorStakeWithReserve"
+	default:
+		return ""
+	}
+}
+
+type Instruction struct {
+	ag_binary.BaseVariant
+}
+
+func (inst *Instruction) EncodeToTree(parent ag_treeout.Branches) {
+	if enToTree, ok := inst.Impl.(ag_text.EncodableToTree); ok {
+		enToTree.EncodeToTree(parent)
+	} else {
+		parent.Child(ag_spew.Sdump(inst))
+	}
+}
+
+var InstructionImplDef = ag_binary.NewVariantDefinition(
+	ag_binary.Uint8TypeIDEncoding,
+	[]ag_binary.VariantType{
+		{
+			"Initialize", (*Initialize)(nil),
+		},
+		{
+			"AddValidatorToPool", (*AddValidatorToPool)(nil),
+		},
+		{
+			"RemoveValidatorFromPool", (*RemoveValidatorFromPool)(nil),
+		},
+		{
+			"DecreaseValidatorStake", nil,
+		},
+		{
+			"IncreaseValidatorStake", (*IncreaseValidatorStake)(nil),
+		},
+		{
+			"SetPreferredValidator", (*SetPreferredValidator)(nil),
+		},
+		{
+			"UpdateValidatorListBalance", (*UpdateValidatorListBalance)(nil),
+		},
+		{
+			"UpdateStakePoolBalance", (*UpdateStakePoolBalance)(nil),
+		},
+		{
+			"CleanupRemovedValidatorEntries", (*CleanupRemovedValidatorEntries)(nil),
+		},
+		{
+			"DepositStake", (*DepositStake)(nil),
+		},
+		{
+			"WithdrawStake", (*WithdrawStake)(nil),
+		},
+		{
+			"SetManager", (*SetManager)(nil),
+		},
+		{
+			"SetFee", (*SetFee)(nil),
+		},
+		{
+			"SetStaker", (*SetStaker)(nil),
+		},
+		{
+			"DepositSol", (*DepositSol)(nil),
+		},
+		{
+			"SetFundingAuthority", (*SetFundingAuthority)(nil),
+		},
+		{
+			"WithdrawSol", (*WithdrawSol)(nil),
+		},
+		{
+			"CreateTokenMetadata", nil,
+		},
+		{
+			"UpdateTokenMetadata", nil,
+		},
+		{
+			"IncreaseAdditionalValidatorStake", nil,
+		},
+		{
+			"DecreaseAdditionalValidatorStake", nil,
+		},
+		{
+			"DecreaseValidatorStakeWithReserve", (*DecreaseValidatorStakeWithReserve)(nil),
+		},
+	},
+)
+
+func (inst *Instruction) ProgramID() ag_solanago.PublicKey {
+	return ProgramID
+}
+
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
+func (inst *Instruction) Accounts() (out []*ag_solanago.AccountMeta) {
+	return inst.Impl.(ag_solanago.AccountsGettable).GetAccounts()
+}
+
+func (inst *Instruction) Data() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := ag_binary.NewBorshEncoder(buf).Encode(inst); err != nil {
+		return nil, fmt.Errorf("unable to encode instruction: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (inst *Instruction) TextEncode(encoder *ag_text.Encoder, option *ag_text.Option) error {
+	return encoder.Encode(inst.Impl, option)
+}
+
+func (inst *Instruction) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	return inst.BaseVariant.UnmarshalBinaryVariant(decoder, InstructionImplDef)
+}
+
+func (inst Instruction) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	err := encoder.WriteUint8(inst.TypeID.Uint8())
+	if err != nil {
+		return fmt.Errorf("unable to write variant type: %w", err)
+	}
+	return encoder.Encode(inst.Impl)
+}
+
+func registryDecodeInstruction(accounts []*ag_solanago.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func DecodeInstruction(accounts []*ag_solanago.AccountMeta, data []byte) (*Instruction, error) {
+	inst := new(Instruction)
+	if err := ag_binary.NewBorshDecoder(data).Decode(inst); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction: %w", err)
+	}
+	if v, ok := inst.Impl.(ag_solanago.AccountsSettable); ok {
+		err := v.SetAccounts(accounts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set accounts for instruction: %w", err)
+		}
+	}
+	return inst, nil
+}
+
+// appendAccountNames appends the names of the remaining accounts.
+func appendAccountNames(names []string, name string, accounts ag_solanago.AccountMetaSlice) []string {
+	for i := range accounts {
+		names = append(names, fmt.Sprintf("%s[%d]", name, i))
+	}
+	return names
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func TestSetFee(t *testing.T) {
+	stakePool := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	manager := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+
+	inst, err := NewSetFeeInstruction(NewFeeType(FeeKindEpoch, NewFee(3, 100)), stakePool, manager).ValidateAndBuild()
+	ag_require.NoError(t, err)
+	expected := []byte{Instruction_SetFee, byte(FeeKindEpoch)}
+	expected = binary.LittleEndian.AppendUint64(expected, 100)
+	expected = binary.LittleEndian.AppendUint64(expected, 3)
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "SetFee", decoded.InstructionName())
+	ag_require.Equal(t, NewFeeType(FeeKindEpoch, NewFee(3, 100)), *decoded.Impl.(*SetFee).Fee)
+
+	inst, err = NewSetFeeInstruction(NewReferralFeeType(FeeKindSolReferral, 50), stakePool, manager).ValidateAndBuild()
+	ag_require.NoError(t, err)
+	data, err = inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []byte{Instruction_SetFee, byte(FeeKindSolReferral), 50}, data)
+
+	_, err = NewSetFeeInstruction(NewFeeType(FeeKindSolDeposit, NewFee(2, 1)), stakePool, manager).ValidateAndBuild()
+	ag_require.Error(t, err)
+	_, err = NewSetFeeInstruction(NewReferralFeeType(FeeKindStakeReferral, 101), stakePool, manager).ValidateAndBuild()
+	ag_require.Error(t, err)
+}
+
+func TestSetPreferredValidator(t *testing.T) {
+	stakePool := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	staker := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	validatorList := ag_solanago.SysVarRentPubkey
+	vote := ag_solanago.SysVarClockPubkey
+
+	builder := NewSetPreferredValidatorInstruction(PreferredValidatorTypeWithdraw, stakePool, staker, validatorList)
+	data, err := builder.Build().Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []byte{Instruction_SetPreferredValidator, 1, 0}, data)
+
+	data, err = builder.SetValidatorVoteAddress(vote).Build().Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, append([]byte{Instruction_SetPreferredValidator, 1, 1}, vote[:]...), data)
+
+	decoded, err := DecodeInstruction(builder.GetAccounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, vote, *decoded.Impl.(*SetPreferredValidator).ValidatorVoteAddress)
+}
+
+func TestUpdateValidatorListBalance(t *testing.T) {
+	stakePool := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	vote := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	list := &ValidatorList{
+		AccountType:   AccountTypeValidatorList,
+		MaxValidators: 10,
+		Validators:    []ValidatorStakeInfo{{VoteAccountAddress: vote, ValidatorSeedSuffix: 1, TransientSeedSuffix: 2}},
+	}
+	stakeAccounts, err := list.StakeAccounts(stakePool, 0, 5)
+	ag_require.NoError(t, err)
+	validatorStake, _, err := FindValidatorStakeAddress(vote, stakePool, 1)
+	ag_require.NoError(t, err)
+	transientStake, _, err := FindTransientStakeAddress(vote, stakePool, 2)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []ag_solanago.PublicKey{validatorStake, transientStake}, stakeAccounts)
+
+	inst, err := NewUpdateValidatorListBalanceInstruction(
+		3,
+		true,
+		stakePool,
+		ag_solanago.SysVarRentPubkey,
+		ag_solanago.SysVarFeesPubkey,
+		ag_solanago.SysVarRecentBlockHashesPubkey,
+		stakeAccounts,
+	).ValidateAndBuild()
+	ag_require.NoError(t, err)
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []byte{Instruction_UpdateValidatorListBalance, 3, 0, 0, 0, 1}, data)
+
+	accounts := inst.Accounts()
+	ag_require.Len(t, accounts, 9)
+	ag_require.Equal(t, ag_solanago.StakeProgramID, accounts[6].PublicKey)
+	ag_require.Equal(t, validatorStake, accounts[7].PublicKey)
+	ag_require.True(t, accounts[8].IsWritable)
+
+	decoded, err := DecodeInstruction(accounts, data)
+	ag_require.NoError(t, err)
+	ag_require.Len(t, decoded.Impl.(*UpdateValidatorListBalance).StakeAccounts, 2)
+}
+
+func TestNewDepositStakeInstructions(t *testing.T) {
+	stakePoolAddress := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	_, bumpSeed, err := FindWithdrawAuthorityAddress(stakePoolAddress)
+	ag_require.NoError(t, err)
+	depositAuthority, _, err := FindDepositAuthorityAddress(stakePoolAddress)
+	ag_require.NoError(t, err)
+	pool := &StakePool{
+		AccountType:           AccountTypeStakePool,
+		StakeDepositAuthority: depositAuthority,
+		StakeWithdrawBumpSeed: bumpSeed,
+		ValidatorList:         ag_solanago.SysVarRentPubkey,
+		ReserveStake:          ag_solanago.SysVarFeesPubkey,
+		PoolMint:              ag_solanago.SysVarSlotHashesPubkey,
+		ManagerFeeAccount:     ag_solanago.SysVarInstructionsPubkey,
+		TokenProgramID:        ag_solanago.Token2022ProgramID,
+	}
+	depositStake := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	owner := ag_solanago.MPK("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+
+	insts, err := NewDepositStakeInstructions(stakePoolAddress, pool, depositStake, owner, ag_solanago.SysVarClockPubkey, owner, owner)
+	ag_require.NoError(t, err)
+	ag_require.Len(t, insts, 3)
+
+	for i, stakeAuthorize := range []stake.StakeAuthorize{stake.StakeAuthorizeStaker, stake.StakeAuthorizeWithdrawer} {
+		ag_require.Equal(t, ag_solanago.StakeProgramID, insts[i].ProgramID())
+		data, err := insts[i].Data()
+		ag_require.NoError(t, err)
+		expected := binary.LittleEndian.AppendUint32(nil, 1)
+		expected = append(expected, depositAuthority[:]...)
+		expected = binary.LittleEndian.AppendUint32(expected, uint32(stakeAuthorize))
+		ag_require.Equal(t, expected, data)
+	}
+
+	ag_require.Equal(t, ProgramID, insts[2].ProgramID())
+	data, err := insts[2].Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []byte{Instruction_DepositStake}, data)
+	accounts := insts[2].Accounts()
+	ag_require.Len(t, accounts, 15)
+	ag_require.Equal(t, depositAuthority, accounts[2].PublicKey)
+	ag_require.False(t, accounts[2].IsSigner)
+	withdrawAuthority, err := pool.WithdrawAuthority(stakePoolAddress)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, withdrawAuthority, accounts[3].PublicKey)
+	ag_require.Equal(t, ag_solanago.Token2022ProgramID, accounts[13].PublicKey)
+
+	pool.StakeDepositAuthority = owner
+	insts, err = NewDepositStakeInstructions(stakePoolAddress, pool, depositStake, owner, ag_solanago.SysVarClockPubkey, owner, owner)
+	ag_require.NoError(t, err)
+	ag_require.True(t, insts[2].Accounts()[2].IsSigner)
+}
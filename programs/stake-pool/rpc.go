@@ -0,0 +1,52 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"context"
+	"fmt"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func getAccountData(ctx context.Context, rpcCli *rpc.Client, address ag_solanago.PublicKey, name string) ([]byte, error) {
+	resp, err := rpcCli.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %s account: %w", name, err)
+	}
+	if !resp.Value.Owner.Equals(ProgramID) {
+		return nil, fmt.Errorf("account %s is not owned by the stake pool program", address)
+	}
+	return resp.GetBinary(), nil
+}
+
+// FetchStakePool fetches the stake pool account at the address.
+func FetchStakePool(ctx context.Context, rpcCli *rpc.Client, address ag_solanago.PublicKey) (*StakePool, error) {
+	data, err := getAccountData(ctx, rpcCli, address, "stake pool")
+	if err != nil {
+		return nil, err
+	}
+	return DecodeStakePool(data)
+}
+
+// FetchValidatorList fetches the validator list account at the address.
+func FetchValidatorList(ctx context.Context, rpcCli *rpc.Client, address ag_solanago.PublicKey) (*ValidatorList, error) {
+	data, err := getAccountData(ctx, rpcCli, address, "validator list")
+	if err != nil {
+		return nil, err
+	}
+	return DecodeValidatorList(data)
+}
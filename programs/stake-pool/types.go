@@ -0,0 +1,338 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stakepool
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+)
+
+// Fee is a fraction, of the pool tokens or of the rewards; a zero
+// denominator is a zero fee.
+type Fee struct {
+	Denominator uint64
+	Numerator   uint64
+}
+
+// NewFee returns the fee of numerator/denominator.
+func NewFee(numerator, denominator uint64) Fee {
+	return Fee{Denominator: denominator, Numerator: numerator}
+}
+
+// IsZero returns whether the fee is zero.
+func (fee Fee) IsZero() bool {
+	return fee.Denominator == 0 || fee.Numerator == 0
+}
+
+func (fee Fee) String() string {
+	return fmt.Sprintf("%d/%d", fee.Numerator, fee.Denominator)
+}
+
+func (fee *Fee) validate() error {
+	if fee.Numerator > fee.Denominator {
+		return fmt.Errorf("fee %s must not be greater than one", fee)
+	}
+	return nil
+}
+
+type FutureEpochKind uint8
+
+const (
+	// No change of the fee is planned.
+	FutureEpochNone FutureEpochKind = iota
+
+	// The fee changes at the next epoch boundary.
+	FutureEpochOne
+
+	// The fee changes at the second epoch boundary.
+	FutureEpochTwo
+)
+
+func (kind FutureEpochKind) String() string {
+	switch kind {
+	case FutureEpochNone:
+		return "None"
+	case FutureEpochOne:
+		return "One"
+	case FutureEpochTwo:
+		return "Two"
+	default:
+		return fmt.Sprintf("FutureEpochKind(%d)", uint8(kind))
+	}
+}
+
+// FutureEpochFee is a fee set by the manager, applied after the epoch
+// boundaries of its kind.
+type FutureEpochFee struct {
+	Kind FutureEpochKind
+	Fee  Fee
+}
+
+// Get returns the planned fee, if any.
+func (fee FutureEpochFee) Get() (Fee, bool) {
+	return fee.Fee, fee.Kind != FutureEpochNone
+}
+
+func (fee FutureEpochFee) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if err := encoder.WriteUint8(uint8(fee.Kind)); err != nil {
+		return err
+	}
+	if fee.Kind == FutureEpochNone {
+		return nil
+	}
+	return encoder.Encode(fee.Fee)
+}
+
+func (fee *FutureEpochFee) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	kind, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	fee.Kind = FutureEpochKind(kind)
+	switch fee.Kind {
+	case FutureEpochNone:
+		fee.Fee = Fee{}
+		return nil
+	case FutureEpochOne, FutureEpochTwo:
+		return decoder.Decode(&fee.Fee)
+	default:
+		return fmt.Errorf("invalid future epoch kind %d", kind)
+	}
+}
+
+type FeeKind uint8
+
+const (
+	// The percentage of the SOL deposit fees that goes to the referrers.
+	FeeKindSolReferral FeeKind = iota
+
+	// The percentage of the stake deposit fees that goes to the referrers.
+	FeeKindStakeReferral
+
+	// The fee on the rewards of the pool, at each epoch.
+	FeeKindEpoch
+
+	// The fee on the withdrawals of stake.
+	FeeKindStakeWithdrawal
+
+	// The fee on the deposits of SOL.
+	FeeKindSolDeposit
+
+	// The fee on the deposits of stake.
+	FeeKindStakeDeposit
+
+	// The fee on the withdrawals of SOL.
+	FeeKindSolWithdrawal
+)
+
+func (kind FeeKind) String() string {
+	switch kind {
+	case FeeKindSolReferral:
+		return "SolReferral"
+	case FeeKindStakeReferral:
+		return "StakeReferral"
+	case FeeKindEpoch:
+		return "Epoch"
+	case FeeKindStakeWithdrawal:
+		return "StakeWithdrawal"
+	case FeeKindSolDeposit:
+		return "SolDeposit"
+	case FeeKindStakeDeposit:
+		return "StakeDeposit"
+	case FeeKindSolWithdrawal:
+		return "SolWithdrawal"
+	default:
+		return fmt.Sprintf("FeeKind(%d)", uint8(kind))
+	}
+}
+
+// IsReferral returns whether the fees of the kind are percentages of the
+// deposit fees instead of fractions.
+func (kind FeeKind) IsReferral() bool {
+	return kind == FeeKindSolReferral || kind == FeeKindStakeReferral
+}
+
+// FeeType is a fee of a stake pool, the parameter of SetFee.
+type FeeType struct {
+	Kind FeeKind
+
+	// The fee, for the kinds other than the referrals.
+	Fee Fee
+
+	// The percentage, for the referral kinds.
+	ReferralFee uint8
+}
+
+// NewFeeType returns a fee of a kind other than the referrals.
+func NewFeeType(kind FeeKind, fee Fee) FeeType {
+	return FeeType{Kind: kind, Fee: fee}
+}
+
+// NewReferralFeeType returns a referral fee of a percentage.
+func NewReferralFeeType(kind FeeKind, percentage uint8) FeeType {
+	return FeeType{Kind: kind, ReferralFee: percentage}
+}
+
+func (typ FeeType) String() string {
+	if typ.Kind.IsReferral() {
+		return fmt.Sprintf("%s(%d%%)", typ.Kind, typ.ReferralFee)
+	}
+	return fmt.Sprintf("%s(%s)", typ.Kind, typ.Fee)
+}
+
+func (typ *FeeType) validate() error {
+	switch {
+	case typ.Kind > FeeKindSolWithdrawal:
+		return fmt.Errorf("invalid fee kind %d", uint8(typ.Kind))
+	case typ.Kind.IsReferral():
+		if typ.ReferralFee > 100 {
+			return errors.New("referral fee must be a percentage")
+		}
+		return nil
+	default:
+		return typ.Fee.validate()
+	}
+}
+
+func (typ FeeType) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if err := encoder.WriteUint8(uint8(typ.Kind)); err != nil {
+		return err
+	}
+	if typ.Kind.IsReferral() {
+		return encoder.WriteUint8(typ.ReferralFee)
+	}
+	return encoder.Encode(typ.Fee)
+}
+
+func (typ *FeeType) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	kind, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	*typ = FeeType{Kind: FeeKind(kind)}
+	switch {
+	case typ.Kind > FeeKindSolWithdrawal:
+		return fmt.Errorf("invalid fee kind %d", kind)
+	case typ.Kind.IsReferral():
+		typ.ReferralFee, err = decoder.ReadUint8()
+		return err
+	default:
+		return decoder.Decode(&typ.Fee)
+	}
+}
+
+// FundingType is a type of deposits or withdrawals that can require the
+// signature of an authority.
+type FundingType uint8
+
+const (
+	FundingTypeStakeDeposit FundingType = iota
+	FundingTypeSolDeposit
+	FundingTypeSolWithdraw
+)
+
+func (typ FundingType) String() string {
+	switch typ {
+	case FundingTypeStakeDeposit:
+		return "StakeDeposit"
+	case FundingTypeSolDeposit:
+		return "SolDeposit"
+	case FundingTypeSolWithdraw:
+		return "SolWithdraw"
+	default:
+		return fmt.Sprintf("FundingType(%d)", uint8(typ))
+	}
+}
+
+// PreferredValidatorType is the operation that a preferred validator applies
+// to.
+type PreferredValidatorType uint8
+
+const (
+	PreferredValidatorTypeDeposit PreferredValidatorType = iota
+	PreferredValidatorTypeWithdraw
+)
+
+func (typ PreferredValidatorType) String() string {
+	switch typ {
+	case PreferredValidatorTypeDeposit:
+		return "Deposit"
+	case PreferredValidatorTypeWithdraw:
+		return "Withdraw"
+	default:
+		return fmt.Sprintf("PreferredValidatorType(%d)", uint8(typ))
+	}
+}
+
+// StakeStatus is the status of a validator in the validator list.
+type StakeStatus uint8
+
+const (
+	// The validator stake account is active.
+	StakeStatusActive StakeStatus = iota
+
+	// Only the transient stake account remains, deactivating.
+	StakeStatusDeactivatingTransient
+
+	// No stake account remains; the validator can be cleaned up.
+	StakeStatusReadyForRemoval
+
+	// The validator stake account is deactivating.
+	StakeStatusDeactivatingValidator
+
+	// The validator and transient stake accounts are deactivating.
+	StakeStatusDeactivatingAll
+)
+
+func (status StakeStatus) String() string {
+	switch status {
+	case StakeStatusActive:
+		return "Active"
+	case StakeStatusDeactivatingTransient:
+		return "DeactivatingTransient"
+	case StakeStatusReadyForRemoval:
+		return "ReadyForRemoval"
+	case StakeStatusDeactivatingValidator:
+		return "DeactivatingValidator"
+	case StakeStatusDeactivatingAll:
+		return "DeactivatingAll"
+	default:
+		return fmt.Sprintf("StakeStatus(%d)", uint8(status))
+	}
+}
+
+// AccountType is the first byte of the accounts of the program.
+type AccountType uint8
+
+const (
+	AccountTypeUninitialized AccountType = iota
+	AccountTypeStakePool
+	AccountTypeValidatorList
+)
+
+func (typ AccountType) String() string {
+	switch typ {
+	case AccountTypeUninitialized:
+		return "Uninitialized"
+	case AccountTypeStakePool:
+		return "StakePool"
+	case AccountTypeValidatorList:
+		return "ValidatorList"
+	default:
+		return fmt.Sprintf("AccountType(%d)", uint8(typ))
+	}
+}
@@ -37,6 +37,11 @@ type Deactivate struct {
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Deactivate) AccountNames() []string {
+	return []string{"stakeAccount", "clockSysvar", "stakeAuthority"}
+}
+
 func (inst *Deactivate) Validate() error {
 
 	// Check whether all accounts are set:
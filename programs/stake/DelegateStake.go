@@ -45,6 +45,11 @@ type DelegateStake struct {
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst DelegateStake) AccountNames() []string {
+	return []string{"stakeAccount", "voteAccount", "clockSysvar", "stakeHistorySysvar", "stakeConfigAccount", "stakeAuthority"}
+}
+
 func (inst *DelegateStake) Validate() error {
 	// Check whether all accounts are set:
 	for accIndex, acc := range inst.AccountMetaSlice {
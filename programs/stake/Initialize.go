@@ -40,6 +40,11 @@ type Initialize struct {
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Initialize) AccountNames() []string {
+	return []string{"stakeAccount", "rentSysvar"}
+}
+
 func (inst *Initialize) UnmarshalWithDecoder(dec *bin.Decoder) error {
 	{
 		err := dec.Decode(&inst.Authorized)
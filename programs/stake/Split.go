@@ -39,6 +39,11 @@ type Split struct {
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Split) AccountNames() []string {
+	return []string{"stakeAccount", "newStakeAccount", "stakeAuthority"}
+}
+
 func (inst *Split) Validate() error {
 	{
 		if inst.Lamports == nil {
@@ -48,6 +48,11 @@ type Withdraw struct {
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Withdraw) AccountNames() []string {
+	return []string{"stakeAccount", "recipientAccount", "clockSysvar", "stakeHistorySysvar", "withdrawAuthority", "lockupAuthority"}
+}
+
 func (inst *Withdraw) Validate() error {
 	{
 		if inst.Lamports == nil {
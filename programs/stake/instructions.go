@@ -32,12 +32,14 @@ var ProgramID solana.PublicKey = solana.StakeProgramID
 func SetProgramID(pubkey solana.PublicKey) {
 	ProgramID = pubkey
 	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
 }
 
 const ProgramName = "Stake"
 
 func init() {
 	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
 }
 
 const (
@@ -74,7 +76,7 @@ var InstructionImplDef = bin.NewVariantDefinition(
 			"Initialize", (*Initialize)(nil),
 		},
 		{
-			"Authorize", nil,
+			"Authorize", (*Authorize)(nil),
 		},
 		{
 			"DelegateStake", (*DelegateStake)(nil),
@@ -95,6 +97,15 @@ func (inst *Instruction) ProgramID() solana.PublicKey {
 	return ProgramID
 }
 
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
 func (inst *Instruction) Accounts() (out []*solana.AccountMeta) {
 	return inst.Impl.(solana.AccountsGettable).GetAccounts()
 }
@@ -0,0 +1,165 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stake
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// STAKE_STATE_SIZE is the size of the data of a stake account,
+// including the trailing padding.
+const STAKE_STATE_SIZE = 200
+
+type StakeStateType uint32
+
+const (
+	StakeStateUninitialized StakeStateType = iota
+	StakeStateInitialized
+	StakeStateStake
+	StakeStateRewardsPool
+)
+
+func (typ StakeStateType) String() string {
+	switch typ {
+	case StakeStateUninitialized:
+		return "Uninitialized"
+	case StakeStateInitialized:
+		return "Initialized"
+	case StakeStateStake:
+		return "Stake"
+	case StakeStateRewardsPool:
+		return "RewardsPool"
+	default:
+		return fmt.Sprintf("StakeStateType(%d)", uint32(typ))
+	}
+}
+
+// StakeState is the data of a stake account.
+type StakeState struct {
+	Type StakeStateType
+	// Set for the Initialized and Stake states.
+	Meta *Meta
+	// Set for the Stake state.
+	Stake *Stake
+	// Set for the Stake state.
+	StakeFlags uint8
+}
+
+type Meta struct {
+	RentExemptReserve uint64
+	Authorized        Authorized
+	Lockup            Lockup
+}
+
+type Stake struct {
+	Delegation      Delegation
+	CreditsObserved uint64
+}
+
+type Delegation struct {
+	// Vote account the stake is delegated to.
+	VoterPubkey solana.PublicKey
+	// Activated stake amount.
+	Stake uint64
+	// Epoch at which this stake was activated.
+	ActivationEpoch uint64
+	// Epoch the stake was deactivated; math.MaxUint64 if not deactivated.
+	DeactivationEpoch uint64
+	// Deprecated.
+	WarmupCooldownRate float64
+}
+
+func DecodeStakeState(data []byte) (*StakeState, error) {
+	state := new(StakeState)
+	if err := bin.NewBinDecoder(data).Decode(state); err != nil {
+		return nil, fmt.Errorf("unable to decode stake state: %w", err)
+	}
+	return state, nil
+}
+
+func (state *StakeState) UnmarshalWithDecoder(dec *bin.Decoder) error {
+	typ, err := dec.ReadUint32(bin.LE)
+	if err != nil {
+		return err
+	}
+	state.Type = StakeStateType(typ)
+	switch state.Type {
+	case StakeStateUninitialized, StakeStateRewardsPool:
+		return nil
+	case StakeStateInitialized, StakeStateStake:
+	default:
+		return fmt.Errorf("unknown stake state type: %d", typ)
+	}
+
+	state.Meta = new(Meta)
+	if err := dec.Decode(state.Meta); err != nil {
+		return fmt.Errorf("unable to decode meta: %w", err)
+	}
+	if state.Type == StakeStateInitialized {
+		return nil
+	}
+	state.Stake = new(Stake)
+	if err := dec.Decode(state.Stake); err != nil {
+		return fmt.Errorf("unable to decode stake: %w", err)
+	}
+	// The stake flags occupy what used to be padding.
+	if dec.Remaining() > 0 {
+		state.StakeFlags, err = dec.ReadUint8()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (state StakeState) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint32(uint32(state.Type), bin.LE); err != nil {
+		return err
+	}
+	if state.Type != StakeStateInitialized && state.Type != StakeStateStake {
+		return nil
+	}
+	if state.Meta == nil {
+		return fmt.Errorf("meta is not set")
+	}
+	if err := encoder.Encode(state.Meta); err != nil {
+		return err
+	}
+	if state.Type == StakeStateInitialized {
+		return nil
+	}
+	if state.Stake == nil {
+		return fmt.Errorf("stake is not set")
+	}
+	if err := encoder.Encode(state.Stake); err != nil {
+		return err
+	}
+	return encoder.WriteUint8(state.StakeFlags)
+}
+
+func registerAccountDecoders(programID solana.PublicKey) {
+	solana.RegisterAccountDecoder(programID, solana.AccountSizeMatcher(STAKE_STATE_SIZE), registryDecodeAccount)
+}
+
+func registryDecodeAccount(data []byte) (interface{}, error) {
+	state, err := DecodeStakeState(data)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stake
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakeState_Decode(t *testing.T) {
+	staker := solana.NewWallet().PublicKey()
+	withdrawer := solana.NewWallet().PublicKey()
+	custodian := solana.PublicKey{}
+	unixTimestamp := int64(0)
+	epoch := uint64(0)
+	state := StakeState{
+		Type: StakeStateStake,
+		Meta: &Meta{
+			RentExemptReserve: 2282880,
+			Authorized:        Authorized{Staker: &staker, Withdrawer: &withdrawer},
+			Lockup:            Lockup{UnixTimestamp: &unixTimestamp, Epoch: &epoch, Custodian: &custodian},
+		},
+		Stake: &Stake{
+			Delegation: Delegation{
+				VoterPubkey:        solana.NewWallet().PublicKey(),
+				Stake:              1000000000,
+				ActivationEpoch:    500,
+				DeactivationEpoch:  math.MaxUint64,
+				WarmupCooldownRate: 0.25,
+			},
+			CreditsObserved: 42,
+		},
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewBinEncoder(buf).Encode(state))
+	// Stake accounts carry 3 bytes of trailing padding.
+	require.Len(t, buf.Bytes(), STAKE_STATE_SIZE-3)
+	data := append(buf.Bytes(), 0, 0, 0)
+
+	decoded, err := solana.DecodeAccount(ProgramID, data)
+	require.NoError(t, err)
+	require.Equal(t, &state, decoded)
+
+	// Initialized accounts are zero-padded to the same size.
+	data = make([]byte, STAKE_STATE_SIZE)
+	data[0] = byte(StakeStateInitialized)
+	got, err := DecodeStakeState(data)
+	require.NoError(t, err)
+	require.Equal(t, StakeStateInitialized, got.Type)
+	require.NotNil(t, got.Meta)
+	require.Nil(t, got.Stake)
+}
@@ -77,6 +77,11 @@ func (inst *AdvanceNonceAccount) GetNonceAuthorityAccount() *ag_solanago.Account
 	return inst.AccountMetaSlice[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst AdvanceNonceAccount) AccountNames() []string {
+	return []string{"nonceAccount", "recentBlockhashesSysvar", "nonceAuthorityAccount"}
+}
+
 func (inst AdvanceNonceAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
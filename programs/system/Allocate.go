@@ -59,6 +59,11 @@ func (inst *Allocate) GetNewAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[0]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Allocate) AccountNames() []string {
+	return []string{"newAccount"}
+}
+
 func (inst Allocate) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
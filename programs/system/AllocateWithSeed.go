@@ -99,6 +99,11 @@ func (inst *AllocateWithSeed) GetBaseAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst AllocateWithSeed) AccountNames() []string {
+	return []string{"allocatedAccount", "baseAccount"}
+}
+
 func (inst AllocateWithSeed) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -59,6 +59,11 @@ func (inst *Assign) GetAssignedAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[0]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Assign) AccountNames() []string {
+	return []string{"assignedAccount"}
+}
+
 func (inst Assign) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -90,6 +90,11 @@ func (inst *AssignWithSeed) GetBaseAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst AssignWithSeed) AccountNames() []string {
+	return []string{"assignedAccount", "baseAccount"}
+}
+
 func (inst AssignWithSeed) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
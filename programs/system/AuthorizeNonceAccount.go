@@ -72,6 +72,11 @@ func (inst *AuthorizeNonceAccount) GetNonceAuthorityAccount() *ag_solanago.Accou
 	return inst.AccountMetaSlice[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst AuthorizeNonceAccount) AccountNames() []string {
+	return []string{"nonceAccount", "nonceAuthorityAccount"}
+}
+
 func (inst AuthorizeNonceAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -90,6 +90,11 @@ func (inst *CreateAccount) GetNewAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst CreateAccount) AccountNames() []string {
+	return []string{"fundingAccount", "newAccount"}
+}
+
 func (inst CreateAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
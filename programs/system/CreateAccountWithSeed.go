@@ -121,6 +121,11 @@ func (inst *CreateAccountWithSeed) GetBaseAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst CreateAccountWithSeed) AccountNames() []string {
+	return []string{"fundingAccount", "createdAccount", "baseAccount"}
+}
+
 func (inst CreateAccountWithSeed) Build() *Instruction {
 	{
 		if !inst.Base.Equals(inst.GetFundingAccount().PublicKey) {
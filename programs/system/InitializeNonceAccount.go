@@ -89,6 +89,11 @@ func (inst *InitializeNonceAccount) GetSysVarRentPubkeyAccount() *ag_solanago.Ac
 	return inst.AccountMetaSlice[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeNonceAccount) AccountNames() []string {
+	return []string{"nonceAccount", "recentBlockhashesSysvar", "rentSysvar"}
+}
+
 func (inst InitializeNonceAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
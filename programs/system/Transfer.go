@@ -72,6 +72,11 @@ func (inst *Transfer) GetRecipientAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Transfer) AccountNames() []string {
+	return []string{"fundingAccount", "recipientAccount"}
+}
+
 func (inst Transfer) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -103,6 +103,11 @@ func (inst *TransferWithSeed) GetRecipientAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst TransferWithSeed) AccountNames() []string {
+	return []string{"fundingAccount", "baseForFundingAccount", "recipientAccount"}
+}
+
 func (inst TransferWithSeed) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -113,6 +113,11 @@ func (inst *WithdrawNonceAccount) GetNonceAuthorityAccount() *ag_solanago.Accoun
 	return inst.AccountMetaSlice[4]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst WithdrawNonceAccount) AccountNames() []string {
+	return []string{"nonceAccount", "recipientAccount", "recentBlockhashesSysvar", "rentSysvar", "nonceAuthorityAccount"}
+}
+
 func (inst WithdrawNonceAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
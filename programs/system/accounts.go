@@ -16,11 +16,15 @@ package system
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 )
 
+// NONCE_ACCOUNT_SIZE is the size of the data of a nonce account.
+const NONCE_ACCOUNT_SIZE = 80
+
 type NonceAccount struct {
 	Version          uint32
 	State            uint32
@@ -91,3 +95,15 @@ func (obj *FeeCalculator) UnmarshalWithDecoder(decoder *bin.Decoder) (err error)
 	obj.LamportsPerSignature, err = decoder.ReadUint64(binary.LittleEndian)
 	return err
 }
+
+func registerAccountDecoders(programID solana.PublicKey) {
+	solana.RegisterAccountDecoder(programID, solana.AccountSizeMatcher(NONCE_ACCOUNT_SIZE), decodeNonceAccount)
+}
+
+func decodeNonceAccount(data []byte) (interface{}, error) {
+	out := new(NonceAccount)
+	if err := bin.NewBinDecoder(data).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode nonce account: %w", err)
+	}
+	return out, nil
+}
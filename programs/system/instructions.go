@@ -34,12 +34,14 @@ var ProgramID ag_solanago.PublicKey = ag_solanago.SystemProgramID
 func SetProgramID(pubkey ag_solanago.PublicKey) {
 	ProgramID = pubkey
 	ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
 }
 
 const ProgramName = "System"
 
 func init() {
 	ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
 }
 
 const (
@@ -170,6 +172,15 @@ func (inst *Instruction) ProgramID() ag_solanago.PublicKey {
 	return ProgramID
 }
 
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
 func (inst *Instruction) Accounts() (out []*ag_solanago.AccountMeta) {
 	return inst.Impl.(ag_solanago.AccountsGettable).GetAccounts()
 }
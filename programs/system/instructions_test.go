@@ -0,0 +1,47 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"encoding/json"
+	"testing"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func TestInstruction_Decoded(t *testing.T) {
+	from := ag_solanago.MustPublicKeyFromBase58("7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932")
+	to := ag_solanago.MustPublicKeyFromBase58("DTUrFSqFh2kHWhaSQaqT1NXh3pRsa1sQP2XGvVGTXdCq")
+
+	tx, err := ag_solanago.NewTransaction(
+		[]ag_solanago.Instruction{NewTransferInstruction(1000, from, to).Build()},
+		ag_solanago.Hash{},
+		ag_solanago.TransactionPayer(from),
+	)
+	ag_require.NoError(t, err)
+
+	decoded, err := tx.Decoded()
+	ag_require.NoError(t, err)
+	got, err := json.Marshal(decoded.Instructions[0])
+	ag_require.NoError(t, err)
+	ag_require.Equal(t,
+		`{"program":"11111111111111111111111111111111","programName":"System","instruction":"Transfer",`+
+			`"args":{"Lamports":1000},"accounts":[`+
+			`{"name":"fundingAccount","pubkey":"7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932","signer":true,"writable":true},`+
+			`{"name":"recipientAccount","pubkey":"DTUrFSqFh2kHWhaSQaqT1NXh3pRsa1sQP2XGvVGTXdCq","signer":false,"writable":true}]}`,
+		string(got),
+	)
+}
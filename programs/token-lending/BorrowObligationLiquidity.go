@@ -0,0 +1,364 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Borrows liquidity from a reserve, against the deposits of an obligation.
+type BorrowObligationLiquidity struct {
+	// The amount of liquidity to borrow; the maximum for MaxUint64.
+	LiquidityAmount *uint64
+
+	// [0] = [WRITE] sourceLiquidity
+	// ··········· The liquidity supply of the borrow reserve.
+	//
+	// [1] = [WRITE] destinationLiquidity
+	// ··········· The user token account receiving the liquidity.
+	//
+	// [2] = [WRITE] borrowReserve
+	// ··········· The borrow reserve, refreshed.
+	//
+	// [3] = [WRITE] borrowReserveLiquidityFeeReceiver
+	// ··········· The fee receiver of the borrow reserve.
+	//
+	// [4] = [WRITE] obligation
+	// ··········· The obligation, refreshed.
+	//
+	// [5] = [] lendingMarket
+	// ··········· The lending market.
+	//
+	// [6] = [] lendingMarketAuthority
+	// ··········· The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+	//
+	// [7] = [SIGNER] obligationOwner
+	// ··········· The owner of the obligation.
+	//
+	// [8] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [9] = [] tokenProgram
+	// ··········· The token program.
+	//
+	// [10] = [WRITE] hostFeeReceiver
+	// ··········· The optional token account receiving the host share of the borrow fee.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewBorrowObligationLiquidityInstructionBuilder creates a new `BorrowObligationLiquidity` instruction builder.
+func NewBorrowObligationLiquidityInstructionBuilder() *BorrowObligationLiquidity {
+	nd := &BorrowObligationLiquidity{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 11),
+	}
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetLiquidityAmount sets the "liquidityAmount" parameter.
+// The amount of liquidity to borrow; the maximum for MaxUint64.
+func (inst *BorrowObligationLiquidity) SetLiquidityAmount(liquidityAmount uint64) *BorrowObligationLiquidity {
+	inst.LiquidityAmount = &liquidityAmount
+	return inst
+}
+
+// SetSourceLiquidityAccount sets the "sourceLiquidity" account.
+// The liquidity supply of the borrow reserve.
+func (inst *BorrowObligationLiquidity) SetSourceLiquidityAccount(sourceLiquidity ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(sourceLiquidity).WRITE()
+	return inst
+}
+
+// GetSourceLiquidityAccount gets the "sourceLiquidity" account.
+// The liquidity supply of the borrow reserve.
+func (inst *BorrowObligationLiquidity) GetSourceLiquidityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetDestinationLiquidityAccount sets the "destinationLiquidity" account.
+// The user token account receiving the liquidity.
+func (inst *BorrowObligationLiquidity) SetDestinationLiquidityAccount(destinationLiquidity ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(destinationLiquidity).WRITE()
+	return inst
+}
+
+// GetDestinationLiquidityAccount gets the "destinationLiquidity" account.
+// The user token account receiving the liquidity.
+func (inst *BorrowObligationLiquidity) GetDestinationLiquidityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetBorrowReserveAccount sets the "borrowReserve" account.
+// The borrow reserve, refreshed.
+func (inst *BorrowObligationLiquidity) SetBorrowReserveAccount(borrowReserve ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(borrowReserve).WRITE()
+	return inst
+}
+
+// GetBorrowReserveAccount gets the "borrowReserve" account.
+// The borrow reserve, refreshed.
+func (inst *BorrowObligationLiquidity) GetBorrowReserveAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetBorrowReserveLiquidityFeeReceiverAccount sets the "borrowReserveLiquidityFeeReceiver" account.
+// The fee receiver of the borrow reserve.
+func (inst *BorrowObligationLiquidity) SetBorrowReserveLiquidityFeeReceiverAccount(borrowReserveLiquidityFeeReceiver ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(borrowReserveLiquidityFeeReceiver).WRITE()
+	return inst
+}
+
+// GetBorrowReserveLiquidityFeeReceiverAccount gets the "borrowReserveLiquidityFeeReceiver" account.
+// The fee receiver of the borrow reserve.
+func (inst *BorrowObligationLiquidity) GetBorrowReserveLiquidityFeeReceiverAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetObligationAccount sets the "obligation" account.
+// The obligation, refreshed.
+func (inst *BorrowObligationLiquidity) SetObligationAccount(obligation ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(obligation).WRITE()
+	return inst
+}
+
+// GetObligationAccount gets the "obligation" account.
+// The obligation, refreshed.
+func (inst *BorrowObligationLiquidity) GetObligationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetLendingMarketAccount sets the "lendingMarket" account.
+// The lending market.
+func (inst *BorrowObligationLiquidity) SetLendingMarketAccount(lendingMarket ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(lendingMarket)
+	return inst
+}
+
+// GetLendingMarketAccount gets the "lendingMarket" account.
+// The lending market.
+func (inst *BorrowObligationLiquidity) GetLendingMarketAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetLendingMarketAuthorityAccount sets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *BorrowObligationLiquidity) SetLendingMarketAuthorityAccount(lendingMarketAuthority ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(lendingMarketAuthority)
+	return inst
+}
+
+// GetLendingMarketAuthorityAccount gets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *BorrowObligationLiquidity) GetLendingMarketAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetObligationOwnerAccount sets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *BorrowObligationLiquidity) SetObligationOwnerAccount(obligationOwner ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(obligationOwner).SIGNER()
+	return inst
+}
+
+// GetObligationOwnerAccount gets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *BorrowObligationLiquidity) GetObligationOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *BorrowObligationLiquidity) SetClockAccount(clock ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *BorrowObligationLiquidity) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *BorrowObligationLiquidity) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *BorrowObligationLiquidity) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetHostFeeReceiverAccount sets the "hostFeeReceiver" account.
+// The optional token account receiving the host share of the borrow fee.
+func (inst *BorrowObligationLiquidity) SetHostFeeReceiverAccount(hostFeeReceiver ag_solanago.PublicKey) *BorrowObligationLiquidity {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(hostFeeReceiver).WRITE()
+	return inst
+}
+
+// GetHostFeeReceiverAccount gets the "hostFeeReceiver" account.
+// The optional token account receiving the host share of the borrow fee.
+func (inst *BorrowObligationLiquidity) GetHostFeeReceiverAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst BorrowObligationLiquidity) AccountNames() []string {
+	return []string{"sourceLiquidity", "destinationLiquidity", "borrowReserve", "borrowReserveLiquidityFeeReceiver", "obligation", "lendingMarket", "lendingMarketAuthority", "obligationOwner", "clock", "tokenProgram", "hostFeeReceiver"}
+}
+
+func (inst BorrowObligationLiquidity) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_BorrowObligationLiquidity),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst BorrowObligationLiquidity) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *BorrowObligationLiquidity) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.LiquidityAmount == nil {
+			return errors.New("LiquidityAmount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.SourceLiquidity is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.DestinationLiquidity is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.BorrowReserve is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.BorrowReserveLiquidityFeeReceiver is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Obligation is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.LendingMarket is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.LendingMarketAuthority is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.ObligationOwner is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *BorrowObligationLiquidity) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("BorrowObligationLiquidity")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("LiquidityAmount", inst.LiquidityAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("                  sourceLiquidity", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("             destinationLiquidity", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("                    borrowReserve", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("borrowReserveLiquidityFeeReceiver", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("                       obligation", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("                    lendingMarket", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("           lendingMarketAuthority", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("                  obligationOwner", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("                            clock", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("                     tokenProgram", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("                  hostFeeReceiver", inst.AccountMetaSlice.Get(10)))
+					})
+				})
+		})
+}
+
+func (obj BorrowObligationLiquidity) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `LiquidityAmount` param:
+	err = encoder.Encode(obj.LiquidityAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *BorrowObligationLiquidity) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `LiquidityAmount`:
+	err = decoder.Decode(&obj.LiquidityAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewBorrowObligationLiquidityInstruction declares a new BorrowObligationLiquidity instruction with the provided parameters and accounts.
+func NewBorrowObligationLiquidityInstruction(
+	// Parameters:
+	liquidityAmount uint64,
+	// Accounts:
+	sourceLiquidity ag_solanago.PublicKey,
+	destinationLiquidity ag_solanago.PublicKey,
+	borrowReserve ag_solanago.PublicKey,
+	borrowReserveLiquidityFeeReceiver ag_solanago.PublicKey,
+	obligation ag_solanago.PublicKey,
+	lendingMarket ag_solanago.PublicKey,
+	lendingMarketAuthority ag_solanago.PublicKey,
+	obligationOwner ag_solanago.PublicKey,
+) *BorrowObligationLiquidity {
+	return NewBorrowObligationLiquidityInstructionBuilder().
+		SetLiquidityAmount(liquidityAmount).
+		SetSourceLiquidityAccount(sourceLiquidity).
+		SetDestinationLiquidityAccount(destinationLiquidity).
+		SetBorrowReserveAccount(borrowReserve).
+		SetBorrowReserveLiquidityFeeReceiverAccount(borrowReserveLiquidityFeeReceiver).
+		SetObligationAccount(obligation).
+		SetLendingMarketAccount(lendingMarket).
+		SetLendingMarketAuthorityAccount(lendingMarketAuthority).
+		SetObligationOwnerAccount(obligationOwner)
+}
@@ -0,0 +1,325 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Deposits collateral tokens into an obligation.
+type DepositObligationCollateral struct {
+	// The amount of collateral tokens to deposit.
+	CollateralAmount *uint64
+
+	// [0] = [WRITE] sourceCollateral
+	// ··········· The user token account of the collateral tokens.
+	//
+	// [1] = [WRITE] destinationCollateral
+	// ··········· The collateral supply of the deposit reserve.
+	//
+	// [2] = [] depositReserve
+	// ··········· The deposit reserve, refreshed.
+	//
+	// [3] = [WRITE] obligation
+	// ··········· The obligation.
+	//
+	// [4] = [] lendingMarket
+	// ··········· The lending market.
+	//
+	// [5] = [SIGNER] obligationOwner
+	// ··········· The owner of the obligation.
+	//
+	// [6] = [SIGNER] userTransferAuthority
+	// ··········· The owner or the delegate of the user token account it debits.
+	//
+	// [7] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [8] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewDepositObligationCollateralInstructionBuilder creates a new `DepositObligationCollateral` instruction builder.
+func NewDepositObligationCollateralInstructionBuilder() *DepositObligationCollateral {
+	nd := &DepositObligationCollateral{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 9),
+	}
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetCollateralAmount sets the "collateralAmount" parameter.
+// The amount of collateral tokens to deposit.
+func (inst *DepositObligationCollateral) SetCollateralAmount(collateralAmount uint64) *DepositObligationCollateral {
+	inst.CollateralAmount = &collateralAmount
+	return inst
+}
+
+// SetSourceCollateralAccount sets the "sourceCollateral" account.
+// The user token account of the collateral tokens.
+func (inst *DepositObligationCollateral) SetSourceCollateralAccount(sourceCollateral ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(sourceCollateral).WRITE()
+	return inst
+}
+
+// GetSourceCollateralAccount gets the "sourceCollateral" account.
+// The user token account of the collateral tokens.
+func (inst *DepositObligationCollateral) GetSourceCollateralAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetDestinationCollateralAccount sets the "destinationCollateral" account.
+// The collateral supply of the deposit reserve.
+func (inst *DepositObligationCollateral) SetDestinationCollateralAccount(destinationCollateral ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(destinationCollateral).WRITE()
+	return inst
+}
+
+// GetDestinationCollateralAccount gets the "destinationCollateral" account.
+// The collateral supply of the deposit reserve.
+func (inst *DepositObligationCollateral) GetDestinationCollateralAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetDepositReserveAccount sets the "depositReserve" account.
+// The deposit reserve, refreshed.
+func (inst *DepositObligationCollateral) SetDepositReserveAccount(depositReserve ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(depositReserve)
+	return inst
+}
+
+// GetDepositReserveAccount gets the "depositReserve" account.
+// The deposit reserve, refreshed.
+func (inst *DepositObligationCollateral) GetDepositReserveAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetObligationAccount sets the "obligation" account.
+// The obligation.
+func (inst *DepositObligationCollateral) SetObligationAccount(obligation ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(obligation).WRITE()
+	return inst
+}
+
+// GetObligationAccount gets the "obligation" account.
+// The obligation.
+func (inst *DepositObligationCollateral) GetObligationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetLendingMarketAccount sets the "lendingMarket" account.
+// The lending market.
+func (inst *DepositObligationCollateral) SetLendingMarketAccount(lendingMarket ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(lendingMarket)
+	return inst
+}
+
+// GetLendingMarketAccount gets the "lendingMarket" account.
+// The lending market.
+func (inst *DepositObligationCollateral) GetLendingMarketAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetObligationOwnerAccount sets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *DepositObligationCollateral) SetObligationOwnerAccount(obligationOwner ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(obligationOwner).SIGNER()
+	return inst
+}
+
+// GetObligationOwnerAccount gets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *DepositObligationCollateral) GetObligationOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetUserTransferAuthorityAccount sets the "userTransferAuthority" account.
+// The owner or the delegate of the user token account it debits.
+func (inst *DepositObligationCollateral) SetUserTransferAuthorityAccount(userTransferAuthority ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(userTransferAuthority).SIGNER()
+	return inst
+}
+
+// GetUserTransferAuthorityAccount gets the "userTransferAuthority" account.
+// The owner or the delegate of the user token account it debits.
+func (inst *DepositObligationCollateral) GetUserTransferAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *DepositObligationCollateral) SetClockAccount(clock ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *DepositObligationCollateral) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *DepositObligationCollateral) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *DepositObligationCollateral {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *DepositObligationCollateral) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst DepositObligationCollateral) AccountNames() []string {
+	return []string{"sourceCollateral", "destinationCollateral", "depositReserve", "obligation", "lendingMarket", "obligationOwner", "userTransferAuthority", "clock", "tokenProgram"}
+}
+
+func (inst DepositObligationCollateral) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_DepositObligationCollateral),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst DepositObligationCollateral) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *DepositObligationCollateral) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.CollateralAmount == nil {
+			return errors.New("CollateralAmount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.SourceCollateral is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.DestinationCollateral is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.DepositReserve is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.Obligation is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.LendingMarket is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.ObligationOwner is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.UserTransferAuthority is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *DepositObligationCollateral) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("DepositObligationCollateral")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("CollateralAmount", inst.CollateralAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("     sourceCollateral", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("destinationCollateral", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("       depositReserve", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("           obligation", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("        lendingMarket", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("      obligationOwner", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("userTransferAuthority", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("                clock", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("         tokenProgram", inst.AccountMetaSlice.Get(8)))
+					})
+				})
+		})
+}
+
+func (obj DepositObligationCollateral) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `CollateralAmount` param:
+	err = encoder.Encode(obj.CollateralAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *DepositObligationCollateral) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `CollateralAmount`:
+	err = decoder.Decode(&obj.CollateralAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewDepositObligationCollateralInstruction declares a new DepositObligationCollateral instruction with the provided parameters and accounts.
+func NewDepositObligationCollateralInstruction(
+	// Parameters:
+	collateralAmount uint64,
+	// Accounts:
+	sourceCollateral ag_solanago.PublicKey,
+	destinationCollateral ag_solanago.PublicKey,
+	depositReserve ag_solanago.PublicKey,
+	obligation ag_solanago.PublicKey,
+	lendingMarket ag_solanago.PublicKey,
+	obligationOwner ag_solanago.PublicKey,
+	userTransferAuthority ag_solanago.PublicKey,
+) *DepositObligationCollateral {
+	return NewDepositObligationCollateralInstructionBuilder().
+		SetCollateralAmount(collateralAmount).
+		SetSourceCollateralAccount(sourceCollateral).
+		SetDestinationCollateralAccount(destinationCollateral).
+		SetDepositReserveAccount(depositReserve).
+		SetObligationAccount(obligation).
+		SetLendingMarketAccount(lendingMarket).
+		SetObligationOwnerAccount(obligationOwner).
+		SetUserTransferAuthorityAccount(userTransferAuthority)
+}
@@ -0,0 +1,347 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Deposits liquidity into a reserve, for collateral tokens.
+type DepositReserveLiquidity struct {
+	// The amount of liquidity to deposit.
+	LiquidityAmount *uint64
+
+	// [0] = [WRITE] sourceLiquidity
+	// ··········· The user token account of the liquidity.
+	//
+	// [1] = [WRITE] destinationCollateral
+	// ··········· The user token account receiving the collateral tokens.
+	//
+	// [2] = [WRITE] reserve
+	// ··········· The reserve, refreshed.
+	//
+	// [3] = [WRITE] reserveLiquiditySupply
+	// ··········· The liquidity supply of the reserve.
+	//
+	// [4] = [WRITE] reserveCollateralMint
+	// ··········· The collateral mint of the reserve.
+	//
+	// [5] = [] lendingMarket
+	// ··········· The lending market.
+	//
+	// [6] = [] lendingMarketAuthority
+	// ··········· The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+	//
+	// [7] = [SIGNER] userTransferAuthority
+	// ··········· The owner or the delegate of the user token account it debits.
+	//
+	// [8] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [9] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewDepositReserveLiquidityInstructionBuilder creates a new `DepositReserveLiquidity` instruction builder.
+func NewDepositReserveLiquidityInstructionBuilder() *DepositReserveLiquidity {
+	nd := &DepositReserveLiquidity{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 10),
+	}
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetLiquidityAmount sets the "liquidityAmount" parameter.
+// The amount of liquidity to deposit.
+func (inst *DepositReserveLiquidity) SetLiquidityAmount(liquidityAmount uint64) *DepositReserveLiquidity {
+	inst.LiquidityAmount = &liquidityAmount
+	return inst
+}
+
+// SetSourceLiquidityAccount sets the "sourceLiquidity" account.
+// The user token account of the liquidity.
+func (inst *DepositReserveLiquidity) SetSourceLiquidityAccount(sourceLiquidity ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(sourceLiquidity).WRITE()
+	return inst
+}
+
+// GetSourceLiquidityAccount gets the "sourceLiquidity" account.
+// The user token account of the liquidity.
+func (inst *DepositReserveLiquidity) GetSourceLiquidityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetDestinationCollateralAccount sets the "destinationCollateral" account.
+// The user token account receiving the collateral tokens.
+func (inst *DepositReserveLiquidity) SetDestinationCollateralAccount(destinationCollateral ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(destinationCollateral).WRITE()
+	return inst
+}
+
+// GetDestinationCollateralAccount gets the "destinationCollateral" account.
+// The user token account receiving the collateral tokens.
+func (inst *DepositReserveLiquidity) GetDestinationCollateralAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetReserveAccount sets the "reserve" account.
+// The reserve, refreshed.
+func (inst *DepositReserveLiquidity) SetReserveAccount(reserve ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(reserve).WRITE()
+	return inst
+}
+
+// GetReserveAccount gets the "reserve" account.
+// The reserve, refreshed.
+func (inst *DepositReserveLiquidity) GetReserveAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetReserveLiquiditySupplyAccount sets the "reserveLiquiditySupply" account.
+// The liquidity supply of the reserve.
+func (inst *DepositReserveLiquidity) SetReserveLiquiditySupplyAccount(reserveLiquiditySupply ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(reserveLiquiditySupply).WRITE()
+	return inst
+}
+
+// GetReserveLiquiditySupplyAccount gets the "reserveLiquiditySupply" account.
+// The liquidity supply of the reserve.
+func (inst *DepositReserveLiquidity) GetReserveLiquiditySupplyAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetReserveCollateralMintAccount sets the "reserveCollateralMint" account.
+// The collateral mint of the reserve.
+func (inst *DepositReserveLiquidity) SetReserveCollateralMintAccount(reserveCollateralMint ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(reserveCollateralMint).WRITE()
+	return inst
+}
+
+// GetReserveCollateralMintAccount gets the "reserveCollateralMint" account.
+// The collateral mint of the reserve.
+func (inst *DepositReserveLiquidity) GetReserveCollateralMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetLendingMarketAccount sets the "lendingMarket" account.
+// The lending market.
+func (inst *DepositReserveLiquidity) SetLendingMarketAccount(lendingMarket ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(lendingMarket)
+	return inst
+}
+
+// GetLendingMarketAccount gets the "lendingMarket" account.
+// The lending market.
+func (inst *DepositReserveLiquidity) GetLendingMarketAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetLendingMarketAuthorityAccount sets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *DepositReserveLiquidity) SetLendingMarketAuthorityAccount(lendingMarketAuthority ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(lendingMarketAuthority)
+	return inst
+}
+
+// GetLendingMarketAuthorityAccount gets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *DepositReserveLiquidity) GetLendingMarketAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetUserTransferAuthorityAccount sets the "userTransferAuthority" account.
+// The owner or the delegate of the user token account it debits.
+func (inst *DepositReserveLiquidity) SetUserTransferAuthorityAccount(userTransferAuthority ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(userTransferAuthority).SIGNER()
+	return inst
+}
+
+// GetUserTransferAuthorityAccount gets the "userTransferAuthority" account.
+// The owner or the delegate of the user token account it debits.
+func (inst *DepositReserveLiquidity) GetUserTransferAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *DepositReserveLiquidity) SetClockAccount(clock ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *DepositReserveLiquidity) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *DepositReserveLiquidity) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *DepositReserveLiquidity {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *DepositReserveLiquidity) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst DepositReserveLiquidity) AccountNames() []string {
+	return []string{"sourceLiquidity", "destinationCollateral", "reserve", "reserveLiquiditySupply", "reserveCollateralMint", "lendingMarket", "lendingMarketAuthority", "userTransferAuthority", "clock", "tokenProgram"}
+}
+
+func (inst DepositReserveLiquidity) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_DepositReserveLiquidity),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst DepositReserveLiquidity) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *DepositReserveLiquidity) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.LiquidityAmount == nil {
+			return errors.New("LiquidityAmount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.SourceLiquidity is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.DestinationCollateral is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Reserve is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.ReserveLiquiditySupply is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ReserveCollateralMint is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.LendingMarket is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.LendingMarketAuthority is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.UserTransferAuthority is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *DepositReserveLiquidity) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("DepositReserveLiquidity")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("LiquidityAmount", inst.LiquidityAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("       sourceLiquidity", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta(" destinationCollateral", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("               reserve", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("reserveLiquiditySupply", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta(" reserveCollateralMint", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("         lendingMarket", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("lendingMarketAuthority", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta(" userTransferAuthority", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("                 clock", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("          tokenProgram", inst.AccountMetaSlice.Get(9)))
+					})
+				})
+		})
+}
+
+func (obj DepositReserveLiquidity) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `LiquidityAmount` param:
+	err = encoder.Encode(obj.LiquidityAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *DepositReserveLiquidity) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `LiquidityAmount`:
+	err = decoder.Decode(&obj.LiquidityAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewDepositReserveLiquidityInstruction declares a new DepositReserveLiquidity instruction with the provided parameters and accounts.
+func NewDepositReserveLiquidityInstruction(
+	// Parameters:
+	liquidityAmount uint64,
+	// Accounts:
+	sourceLiquidity ag_solanago.PublicKey,
+	destinationCollateral ag_solanago.PublicKey,
+	reserve ag_solanago.PublicKey,
+	reserveLiquiditySupply ag_solanago.PublicKey,
+	reserveCollateralMint ag_solanago.PublicKey,
+	lendingMarket ag_solanago.PublicKey,
+	lendingMarketAuthority ag_solanago.PublicKey,
+	userTransferAuthority ag_solanago.PublicKey,
+) *DepositReserveLiquidity {
+	return NewDepositReserveLiquidityInstructionBuilder().
+		SetLiquidityAmount(liquidityAmount).
+		SetSourceLiquidityAccount(sourceLiquidity).
+		SetDestinationCollateralAccount(destinationCollateral).
+		SetReserveAccount(reserve).
+		SetReserveLiquiditySupplyAccount(reserveLiquiditySupply).
+		SetReserveCollateralMintAccount(reserveCollateralMint).
+		SetLendingMarketAccount(lendingMarket).
+		SetLendingMarketAuthorityAccount(lendingMarketAuthority).
+		SetUserTransferAuthorityAccount(userTransferAuthority)
+}
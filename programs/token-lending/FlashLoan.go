@@ -0,0 +1,361 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Lends liquidity of a reserve to a receiver program for the duration of the
+// instruction; the receiver must return it with the flash loan fee.
+type FlashLoan struct {
+	// The amount of liquidity to borrow.
+	Amount *uint64
+
+	// [0] = [WRITE] sourceLiquidity
+	// ··········· The liquidity supply of the reserve.
+	//
+	// [1] = [WRITE] destinationLiquidity
+	// ··········· The token account receiving the loan, returned by the receiver program.
+	//
+	// [2] = [WRITE] reserve
+	// ··········· The reserve.
+	//
+	// [3] = [WRITE] flashLoanFeeReceiver
+	// ··········· The fee receiver of the reserve.
+	//
+	// [4] = [WRITE] hostFeeReceiver
+	// ··········· The token account receiving the host share of the flash loan fee.
+	//
+	// [5] = [] lendingMarket
+	// ··········· The lending market.
+	//
+	// [6] = [] lendingMarketAuthority
+	// ··········· The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+	//
+	// [7] = [] tokenProgram
+	// ··········· The token program.
+	//
+	// [8] = [] flashLoanReceiverProgram
+	// ··········· The flash loan receiver program, invoked with the ReceiveFlashLoan instruction.
+	//
+	// [9...] = [] receiverAccounts
+	// ··········· The accounts passed to the flash loan receiver program.
+	Accounts         ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+	ReceiverAccounts ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+func (obj *FlashLoan) SetAccounts(accounts []*ag_solanago.AccountMeta) error {
+	obj.Accounts, obj.ReceiverAccounts = ag_solanago.AccountMetaSlice(accounts).SplitFrom(9)
+	return nil
+}
+
+func (slice FlashLoan) GetAccounts() (accounts []*ag_solanago.AccountMeta) {
+	accounts = append(accounts, slice.Accounts...)
+	accounts = append(accounts, slice.ReceiverAccounts...)
+	return
+}
+
+// NewFlashLoanInstructionBuilder creates a new `FlashLoan` instruction builder.
+func NewFlashLoanInstructionBuilder() *FlashLoan {
+	nd := &FlashLoan{
+		Accounts:         make(ag_solanago.AccountMetaSlice, 9),
+		ReceiverAccounts: make(ag_solanago.AccountMetaSlice, 0),
+	}
+	nd.Accounts[7] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetAmount sets the "amount" parameter.
+// The amount of liquidity to borrow.
+func (inst *FlashLoan) SetAmount(amount uint64) *FlashLoan {
+	inst.Amount = &amount
+	return inst
+}
+
+// SetSourceLiquidityAccount sets the "sourceLiquidity" account.
+// The liquidity supply of the reserve.
+func (inst *FlashLoan) SetSourceLiquidityAccount(sourceLiquidity ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[0] = ag_solanago.Meta(sourceLiquidity).WRITE()
+	return inst
+}
+
+// GetSourceLiquidityAccount gets the "sourceLiquidity" account.
+// The liquidity supply of the reserve.
+func (inst *FlashLoan) GetSourceLiquidityAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(0)
+}
+
+// SetDestinationLiquidityAccount sets the "destinationLiquidity" account.
+// The token account receiving the loan, returned by the receiver program.
+func (inst *FlashLoan) SetDestinationLiquidityAccount(destinationLiquidity ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[1] = ag_solanago.Meta(destinationLiquidity).WRITE()
+	return inst
+}
+
+// GetDestinationLiquidityAccount gets the "destinationLiquidity" account.
+// The token account receiving the loan, returned by the receiver program.
+func (inst *FlashLoan) GetDestinationLiquidityAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(1)
+}
+
+// SetReserveAccount sets the "reserve" account.
+// The reserve.
+func (inst *FlashLoan) SetReserveAccount(reserve ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[2] = ag_solanago.Meta(reserve).WRITE()
+	return inst
+}
+
+// GetReserveAccount gets the "reserve" account.
+// The reserve.
+func (inst *FlashLoan) GetReserveAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(2)
+}
+
+// SetFlashLoanFeeReceiverAccount sets the "flashLoanFeeReceiver" account.
+// The fee receiver of the reserve.
+func (inst *FlashLoan) SetFlashLoanFeeReceiverAccount(flashLoanFeeReceiver ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[3] = ag_solanago.Meta(flashLoanFeeReceiver).WRITE()
+	return inst
+}
+
+// GetFlashLoanFeeReceiverAccount gets the "flashLoanFeeReceiver" account.
+// The fee receiver of the reserve.
+func (inst *FlashLoan) GetFlashLoanFeeReceiverAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(3)
+}
+
+// SetHostFeeReceiverAccount sets the "hostFeeReceiver" account.
+// The token account receiving the host share of the flash loan fee.
+func (inst *FlashLoan) SetHostFeeReceiverAccount(hostFeeReceiver ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[4] = ag_solanago.Meta(hostFeeReceiver).WRITE()
+	return inst
+}
+
+// GetHostFeeReceiverAccount gets the "hostFeeReceiver" account.
+// The token account receiving the host share of the flash loan fee.
+func (inst *FlashLoan) GetHostFeeReceiverAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(4)
+}
+
+// SetLendingMarketAccount sets the "lendingMarket" account.
+// The lending market.
+func (inst *FlashLoan) SetLendingMarketAccount(lendingMarket ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[5] = ag_solanago.Meta(lendingMarket)
+	return inst
+}
+
+// GetLendingMarketAccount gets the "lendingMarket" account.
+// The lending market.
+func (inst *FlashLoan) GetLendingMarketAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(5)
+}
+
+// SetLendingMarketAuthorityAccount sets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *FlashLoan) SetLendingMarketAuthorityAccount(lendingMarketAuthority ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[6] = ag_solanago.Meta(lendingMarketAuthority)
+	return inst
+}
+
+// GetLendingMarketAuthorityAccount gets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *FlashLoan) GetLendingMarketAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(6)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *FlashLoan) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[7] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *FlashLoan) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(7)
+}
+
+// SetFlashLoanReceiverProgramAccount sets the "flashLoanReceiverProgram" account.
+// The flash loan receiver program, invoked with the ReceiveFlashLoan instruction.
+func (inst *FlashLoan) SetFlashLoanReceiverProgramAccount(flashLoanReceiverProgram ag_solanago.PublicKey) *FlashLoan {
+	inst.Accounts[8] = ag_solanago.Meta(flashLoanReceiverProgram)
+	return inst
+}
+
+// GetFlashLoanReceiverProgramAccount gets the "flashLoanReceiverProgram" account.
+// The flash loan receiver program, invoked with the ReceiveFlashLoan instruction.
+func (inst *FlashLoan) GetFlashLoanReceiverProgramAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(8)
+}
+
+// SetReceiverAccounts sets the accounts passed to the flash loan receiver program, as the remaining accounts.
+func (inst *FlashLoan) SetReceiverAccounts(receiverAccounts ...*ag_solanago.AccountMeta) *FlashLoan {
+	inst.ReceiverAccounts = receiverAccounts
+	return inst
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst FlashLoan) AccountNames() []string {
+	return appendAccountNames([]string{"sourceLiquidity", "destinationLiquidity", "reserve", "flashLoanFeeReceiver", "hostFeeReceiver", "lendingMarket", "lendingMarketAuthority", "tokenProgram", "flashLoanReceiverProgram"}, "receiverAccounts", inst.ReceiverAccounts)
+}
+
+func (inst FlashLoan) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_FlashLoan),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst FlashLoan) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *FlashLoan) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Amount == nil {
+			return errors.New("Amount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.Accounts[0] == nil {
+			return errors.New("accounts.SourceLiquidity is not set")
+		}
+		if inst.Accounts[1] == nil {
+			return errors.New("accounts.DestinationLiquidity is not set")
+		}
+		if inst.Accounts[2] == nil {
+			return errors.New("accounts.Reserve is not set")
+		}
+		if inst.Accounts[3] == nil {
+			return errors.New("accounts.FlashLoanFeeReceiver is not set")
+		}
+		if inst.Accounts[4] == nil {
+			return errors.New("accounts.HostFeeReceiver is not set")
+		}
+		if inst.Accounts[5] == nil {
+			return errors.New("accounts.LendingMarket is not set")
+		}
+		if inst.Accounts[6] == nil {
+			return errors.New("accounts.LendingMarketAuthority is not set")
+		}
+		if inst.Accounts[7] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+		if inst.Accounts[8] == nil {
+			return errors.New("accounts.FlashLoanReceiverProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *FlashLoan) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("FlashLoan")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("Amount", inst.Amount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("         sourceLiquidity", inst.Accounts.Get(0)))
+						accountsBranch.Child(ag_format.Meta("    destinationLiquidity", inst.Accounts.Get(1)))
+						accountsBranch.Child(ag_format.Meta("                 reserve", inst.Accounts.Get(2)))
+						accountsBranch.Child(ag_format.Meta("    flashLoanFeeReceiver", inst.Accounts.Get(3)))
+						accountsBranch.Child(ag_format.Meta("         hostFeeReceiver", inst.Accounts.Get(4)))
+						accountsBranch.Child(ag_format.Meta("           lendingMarket", inst.Accounts.Get(5)))
+						accountsBranch.Child(ag_format.Meta("  lendingMarketAuthority", inst.Accounts.Get(6)))
+						accountsBranch.Child(ag_format.Meta("            tokenProgram", inst.Accounts.Get(7)))
+						accountsBranch.Child(ag_format.Meta("flashLoanReceiverProgram", inst.Accounts.Get(8)))
+
+						restBranch := accountsBranch.Child(fmt.Sprintf("receiverAccounts[len=%v]", len(inst.ReceiverAccounts)))
+						for i, v := range inst.ReceiverAccounts {
+							if len(inst.ReceiverAccounts) > 9 && i < 10 {
+								restBranch.Child(ag_format.Meta(fmt.Sprintf(" [%v]", i), v))
+							} else {
+								restBranch.Child(ag_format.Meta(fmt.Sprintf("[%v]", i), v))
+							}
+						}
+					})
+				})
+		})
+}
+
+func (obj FlashLoan) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Amount` param:
+	err = encoder.Encode(obj.Amount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *FlashLoan) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Amount`:
+	err = decoder.Decode(&obj.Amount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewFlashLoanInstruction declares a new FlashLoan instruction with the provided parameters and accounts.
+func NewFlashLoanInstruction(
+	// Parameters:
+	amount uint64,
+	// Accounts:
+	sourceLiquidity ag_solanago.PublicKey,
+	destinationLiquidity ag_solanago.PublicKey,
+	reserve ag_solanago.PublicKey,
+	flashLoanFeeReceiver ag_solanago.PublicKey,
+	hostFeeReceiver ag_solanago.PublicKey,
+	lendingMarket ag_solanago.PublicKey,
+	lendingMarketAuthority ag_solanago.PublicKey,
+	flashLoanReceiverProgram ag_solanago.PublicKey,
+	receiverAccounts []*ag_solanago.AccountMeta,
+) *FlashLoan {
+	return NewFlashLoanInstructionBuilder().
+		SetAmount(amount).
+		SetSourceLiquidityAccount(sourceLiquidity).
+		SetDestinationLiquidityAccount(destinationLiquidity).
+		SetReserveAccount(reserve).
+		SetFlashLoanFeeReceiverAccount(flashLoanFeeReceiver).
+		SetHostFeeReceiverAccount(hostFeeReceiver).
+		SetLendingMarketAccount(lendingMarket).
+		SetLendingMarketAuthorityAccount(lendingMarketAuthority).
+		SetFlashLoanReceiverProgramAccount(flashLoanReceiverProgram).
+		SetReceiverAccounts(receiverAccounts...)
+}
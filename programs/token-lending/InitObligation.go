@@ -0,0 +1,228 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Initializes an obligation, holding the collateral deposits and the
+// borrows of its owner in a lending market.
+type InitObligation struct {
+	// [0] = [WRITE] obligation
+	// ··········· The new obligation account, allocated with OBLIGATION_LEN bytes and owned by the program.
+	//
+	// [1] = [] lendingMarket
+	// ··········· The lending market.
+	//
+	// [2] = [SIGNER] obligationOwner
+	// ··········· The owner of the obligation.
+	//
+	// [3] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [4] = [] rent
+	// ··········· The rent sysvar.
+	//
+	// [5] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitObligationInstructionBuilder creates a new `InitObligation` instruction builder.
+func NewInitObligationInstructionBuilder() *InitObligation {
+	nd := &InitObligation{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 6),
+	}
+	nd.AccountMetaSlice[3] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[4] = ag_solanago.Meta(ag_solanago.SysVarRentPubkey)
+	nd.AccountMetaSlice[5] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetObligationAccount sets the "obligation" account.
+// The new obligation account, allocated with OBLIGATION_LEN bytes and owned by the program.
+func (inst *InitObligation) SetObligationAccount(obligation ag_solanago.PublicKey) *InitObligation {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(obligation).WRITE()
+	return inst
+}
+
+// GetObligationAccount gets the "obligation" account.
+// The new obligation account, allocated with OBLIGATION_LEN bytes and owned by the program.
+func (inst *InitObligation) GetObligationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetLendingMarketAccount sets the "lendingMarket" account.
+// The lending market.
+func (inst *InitObligation) SetLendingMarketAccount(lendingMarket ag_solanago.PublicKey) *InitObligation {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(lendingMarket)
+	return inst
+}
+
+// GetLendingMarketAccount gets the "lendingMarket" account.
+// The lending market.
+func (inst *InitObligation) GetLendingMarketAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetObligationOwnerAccount sets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *InitObligation) SetObligationOwnerAccount(obligationOwner ag_solanago.PublicKey) *InitObligation {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(obligationOwner).SIGNER()
+	return inst
+}
+
+// GetObligationOwnerAccount gets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *InitObligation) GetObligationOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *InitObligation) SetClockAccount(clock ag_solanago.PublicKey) *InitObligation {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *InitObligation) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetRentAccount sets the "rent" account.
+// The rent sysvar.
+func (inst *InitObligation) SetRentAccount(rent ag_solanago.PublicKey) *InitObligation {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(rent)
+	return inst
+}
+
+// GetRentAccount gets the "rent" account.
+// The rent sysvar.
+func (inst *InitObligation) GetRentAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *InitObligation) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *InitObligation {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *InitObligation) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst InitObligation) AccountNames() []string {
+	return []string{"obligation", "lendingMarket", "obligationOwner", "clock", "rent", "tokenProgram"}
+}
+
+func (inst InitObligation) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_InitObligation),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst InitObligation) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitObligation) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Obligation is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.LendingMarket is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.ObligationOwner is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Rent is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *InitObligation) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("InitObligation")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("     obligation", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("  lendingMarket", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("obligationOwner", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("          clock", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("           rent", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("   tokenProgram", inst.AccountMetaSlice.Get(5)))
+					})
+				})
+		})
+}
+
+func (obj InitObligation) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *InitObligation) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewInitObligationInstruction declares a new InitObligation instruction with the provided parameters and accounts.
+func NewInitObligationInstruction(
+	// Accounts:
+	obligation ag_solanago.PublicKey,
+	lendingMarket ag_solanago.PublicKey,
+	obligationOwner ag_solanago.PublicKey,
+) *InitObligation {
+	return NewInitObligationInstructionBuilder().
+		SetObligationAccount(obligation).
+		SetLendingMarketAccount(lendingMarket).
+		SetObligationOwnerAccount(obligationOwner)
+}
@@ -0,0 +1,347 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Redeems collateral tokens of a reserve, for liquidity.
+type RedeemReserveCollateral struct {
+	// The amount of collateral tokens to redeem.
+	CollateralAmount *uint64
+
+	// [0] = [WRITE] sourceCollateral
+	// ··········· The user token account of the collateral tokens.
+	//
+	// [1] = [WRITE] destinationLiquidity
+	// ··········· The user token account receiving the liquidity.
+	//
+	// [2] = [WRITE] reserve
+	// ··········· The reserve, refreshed.
+	//
+	// [3] = [WRITE] reserveCollateralMint
+	// ··········· The collateral mint of the reserve.
+	//
+	// [4] = [WRITE] reserveLiquiditySupply
+	// ··········· The liquidity supply of the reserve.
+	//
+	// [5] = [] lendingMarket
+	// ··········· The lending market.
+	//
+	// [6] = [] lendingMarketAuthority
+	// ··········· The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+	//
+	// [7] = [SIGNER] userTransferAuthority
+	// ··········· The owner or the delegate of the user token account it debits.
+	//
+	// [8] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [9] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewRedeemReserveCollateralInstructionBuilder creates a new `RedeemReserveCollateral` instruction builder.
+func NewRedeemReserveCollateralInstructionBuilder() *RedeemReserveCollateral {
+	nd := &RedeemReserveCollateral{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 10),
+	}
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetCollateralAmount sets the "collateralAmount" parameter.
+// The amount of collateral tokens to redeem.
+func (inst *RedeemReserveCollateral) SetCollateralAmount(collateralAmount uint64) *RedeemReserveCollateral {
+	inst.CollateralAmount = &collateralAmount
+	return inst
+}
+
+// SetSourceCollateralAccount sets the "sourceCollateral" account.
+// The user token account of the collateral tokens.
+func (inst *RedeemReserveCollateral) SetSourceCollateralAccount(sourceCollateral ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(sourceCollateral).WRITE()
+	return inst
+}
+
+// GetSourceCollateralAccount gets the "sourceCollateral" account.
+// The user token account of the collateral tokens.
+func (inst *RedeemReserveCollateral) GetSourceCollateralAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetDestinationLiquidityAccount sets the "destinationLiquidity" account.
+// The user token account receiving the liquidity.
+func (inst *RedeemReserveCollateral) SetDestinationLiquidityAccount(destinationLiquidity ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(destinationLiquidity).WRITE()
+	return inst
+}
+
+// GetDestinationLiquidityAccount gets the "destinationLiquidity" account.
+// The user token account receiving the liquidity.
+func (inst *RedeemReserveCollateral) GetDestinationLiquidityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetReserveAccount sets the "reserve" account.
+// The reserve, refreshed.
+func (inst *RedeemReserveCollateral) SetReserveAccount(reserve ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(reserve).WRITE()
+	return inst
+}
+
+// GetReserveAccount gets the "reserve" account.
+// The reserve, refreshed.
+func (inst *RedeemReserveCollateral) GetReserveAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetReserveCollateralMintAccount sets the "reserveCollateralMint" account.
+// The collateral mint of the reserve.
+func (inst *RedeemReserveCollateral) SetReserveCollateralMintAccount(reserveCollateralMint ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(reserveCollateralMint).WRITE()
+	return inst
+}
+
+// GetReserveCollateralMintAccount gets the "reserveCollateralMint" account.
+// The collateral mint of the reserve.
+func (inst *RedeemReserveCollateral) GetReserveCollateralMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetReserveLiquiditySupplyAccount sets the "reserveLiquiditySupply" account.
+// The liquidity supply of the reserve.
+func (inst *RedeemReserveCollateral) SetReserveLiquiditySupplyAccount(reserveLiquiditySupply ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(reserveLiquiditySupply).WRITE()
+	return inst
+}
+
+// GetReserveLiquiditySupplyAccount gets the "reserveLiquiditySupply" account.
+// The liquidity supply of the reserve.
+func (inst *RedeemReserveCollateral) GetReserveLiquiditySupplyAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetLendingMarketAccount sets the "lendingMarket" account.
+// The lending market.
+func (inst *RedeemReserveCollateral) SetLendingMarketAccount(lendingMarket ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(lendingMarket)
+	return inst
+}
+
+// GetLendingMarketAccount gets the "lendingMarket" account.
+// The lending market.
+func (inst *RedeemReserveCollateral) GetLendingMarketAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetLendingMarketAuthorityAccount sets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *RedeemReserveCollateral) SetLendingMarketAuthorityAccount(lendingMarketAuthority ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(lendingMarketAuthority)
+	return inst
+}
+
+// GetLendingMarketAuthorityAccount gets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *RedeemReserveCollateral) GetLendingMarketAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetUserTransferAuthorityAccount sets the "userTransferAuthority" account.
+// The owner or the delegate of the user token account it debits.
+func (inst *RedeemReserveCollateral) SetUserTransferAuthorityAccount(userTransferAuthority ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(userTransferAuthority).SIGNER()
+	return inst
+}
+
+// GetUserTransferAuthorityAccount gets the "userTransferAuthority" account.
+// The owner or the delegate of the user token account it debits.
+func (inst *RedeemReserveCollateral) GetUserTransferAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *RedeemReserveCollateral) SetClockAccount(clock ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *RedeemReserveCollateral) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *RedeemReserveCollateral) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *RedeemReserveCollateral {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *RedeemReserveCollateral) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst RedeemReserveCollateral) AccountNames() []string {
+	return []string{"sourceCollateral", "destinationLiquidity", "reserve", "reserveCollateralMint", "reserveLiquiditySupply", "lendingMarket", "lendingMarketAuthority", "userTransferAuthority", "clock", "tokenProgram"}
+}
+
+func (inst RedeemReserveCollateral) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_RedeemReserveCollateral),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst RedeemReserveCollateral) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *RedeemReserveCollateral) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.CollateralAmount == nil {
+			return errors.New("CollateralAmount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.SourceCollateral is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.DestinationLiquidity is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Reserve is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.ReserveCollateralMint is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.ReserveLiquiditySupply is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.LendingMarket is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.LendingMarketAuthority is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.UserTransferAuthority is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *RedeemReserveCollateral) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("RedeemReserveCollateral")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("CollateralAmount", inst.CollateralAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("      sourceCollateral", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("  destinationLiquidity", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("               reserve", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta(" reserveCollateralMint", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("reserveLiquiditySupply", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("         lendingMarket", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("lendingMarketAuthority", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta(" userTransferAuthority", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("                 clock", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("          tokenProgram", inst.AccountMetaSlice.Get(9)))
+					})
+				})
+		})
+}
+
+func (obj RedeemReserveCollateral) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `CollateralAmount` param:
+	err = encoder.Encode(obj.CollateralAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *RedeemReserveCollateral) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `CollateralAmount`:
+	err = decoder.Decode(&obj.CollateralAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewRedeemReserveCollateralInstruction declares a new RedeemReserveCollateral instruction with the provided parameters and accounts.
+func NewRedeemReserveCollateralInstruction(
+	// Parameters:
+	collateralAmount uint64,
+	// Accounts:
+	sourceCollateral ag_solanago.PublicKey,
+	destinationLiquidity ag_solanago.PublicKey,
+	reserve ag_solanago.PublicKey,
+	reserveCollateralMint ag_solanago.PublicKey,
+	reserveLiquiditySupply ag_solanago.PublicKey,
+	lendingMarket ag_solanago.PublicKey,
+	lendingMarketAuthority ag_solanago.PublicKey,
+	userTransferAuthority ag_solanago.PublicKey,
+) *RedeemReserveCollateral {
+	return NewRedeemReserveCollateralInstructionBuilder().
+		SetCollateralAmount(collateralAmount).
+		SetSourceCollateralAccount(sourceCollateral).
+		SetDestinationLiquidityAccount(destinationLiquidity).
+		SetReserveAccount(reserve).
+		SetReserveCollateralMintAccount(reserveCollateralMint).
+		SetReserveLiquiditySupplyAccount(reserveLiquiditySupply).
+		SetLendingMarketAccount(lendingMarket).
+		SetLendingMarketAuthorityAccount(lendingMarketAuthority).
+		SetUserTransferAuthorityAccount(userTransferAuthority)
+}
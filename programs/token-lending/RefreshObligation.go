@@ -0,0 +1,179 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Updates the values of the deposits and borrows of an obligation, from
+// their reserves refreshed in the same slot.
+type RefreshObligation struct {
+	// [0] = [WRITE] obligation
+	// ··········· The obligation.
+	//
+	// [1] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [2...] = [] reserves
+	// ··········· The reserves of the deposits, then of the borrows, in the order of the obligation.
+	Accounts ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+	Reserves ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+func (obj *RefreshObligation) SetAccounts(accounts []*ag_solanago.AccountMeta) error {
+	obj.Accounts, obj.Reserves = ag_solanago.AccountMetaSlice(accounts).SplitFrom(2)
+	return nil
+}
+
+func (slice RefreshObligation) GetAccounts() (accounts []*ag_solanago.AccountMeta) {
+	accounts = append(accounts, slice.Accounts...)
+	accounts = append(accounts, slice.Reserves...)
+	return
+}
+
+// NewRefreshObligationInstructionBuilder creates a new `RefreshObligation` instruction builder.
+func NewRefreshObligationInstructionBuilder() *RefreshObligation {
+	nd := &RefreshObligation{
+		Accounts: make(ag_solanago.AccountMetaSlice, 2),
+		Reserves: make(ag_solanago.AccountMetaSlice, 0),
+	}
+	nd.Accounts[1] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	return nd
+}
+
+// SetObligationAccount sets the "obligation" account.
+// The obligation.
+func (inst *RefreshObligation) SetObligationAccount(obligation ag_solanago.PublicKey) *RefreshObligation {
+	inst.Accounts[0] = ag_solanago.Meta(obligation).WRITE()
+	return inst
+}
+
+// GetObligationAccount gets the "obligation" account.
+// The obligation.
+func (inst *RefreshObligation) GetObligationAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(0)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *RefreshObligation) SetClockAccount(clock ag_solanago.PublicKey) *RefreshObligation {
+	inst.Accounts[1] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *RefreshObligation) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.Accounts.Get(1)
+}
+
+// SetReserves sets the reserves of the deposits, then of the borrows, of the obligation, as the remaining accounts.
+func (inst *RefreshObligation) SetReserves(reserves ...ag_solanago.PublicKey) *RefreshObligation {
+	inst.Reserves = make(ag_solanago.AccountMetaSlice, len(reserves))
+	for i, account := range reserves {
+		inst.Reserves[i] = ag_solanago.Meta(account)
+	}
+	return inst
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst RefreshObligation) AccountNames() []string {
+	return appendAccountNames([]string{"obligation", "clock"}, "reserves", inst.Reserves)
+}
+
+func (inst RefreshObligation) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_RefreshObligation),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst RefreshObligation) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *RefreshObligation) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.Accounts[0] == nil {
+			return errors.New("accounts.Obligation is not set")
+		}
+		if inst.Accounts[1] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *RefreshObligation) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("RefreshObligation")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("obligation", inst.Accounts.Get(0)))
+						accountsBranch.Child(ag_format.Meta("     clock", inst.Accounts.Get(1)))
+
+						restBranch := accountsBranch.Child(fmt.Sprintf("reserves[len=%v]", len(inst.Reserves)))
+						for i, v := range inst.Reserves {
+							if len(inst.Reserves) > 9 && i < 10 {
+								restBranch.Child(ag_format.Meta(fmt.Sprintf(" [%v]", i), v))
+							} else {
+								restBranch.Child(ag_format.Meta(fmt.Sprintf("[%v]", i), v))
+							}
+						}
+					})
+				})
+		})
+}
+
+func (obj RefreshObligation) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *RefreshObligation) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewRefreshObligationInstruction declares a new RefreshObligation instruction with the provided parameters and accounts.
+func NewRefreshObligationInstruction(
+	// Accounts:
+	obligation ag_solanago.PublicKey,
+	reserves []ag_solanago.PublicKey,
+) *RefreshObligation {
+	return NewRefreshObligationInstructionBuilder().
+		SetObligationAccount(obligation).
+		SetReserves(reserves...)
+}
@@ -0,0 +1,326 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Withdraws collateral tokens from an obligation, as long as the remaining
+// deposits cover its borrows.
+type WithdrawObligationCollateral struct {
+	// The amount of collateral tokens to withdraw; the maximum for MaxUint64.
+	CollateralAmount *uint64
+
+	// [0] = [WRITE] sourceCollateral
+	// ··········· The collateral supply of the withdraw reserve.
+	//
+	// [1] = [WRITE] destinationCollateral
+	// ··········· The user token account receiving the collateral tokens.
+	//
+	// [2] = [] withdrawReserve
+	// ··········· The withdraw reserve, refreshed.
+	//
+	// [3] = [WRITE] obligation
+	// ··········· The obligation, refreshed.
+	//
+	// [4] = [] lendingMarket
+	// ··········· The lending market.
+	//
+	// [5] = [] lendingMarketAuthority
+	// ··········· The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+	//
+	// [6] = [SIGNER] obligationOwner
+	// ··········· The owner of the obligation.
+	//
+	// [7] = [] clock
+	// ··········· The clock sysvar.
+	//
+	// [8] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewWithdrawObligationCollateralInstructionBuilder creates a new `WithdrawObligationCollateral` instruction builder.
+func NewWithdrawObligationCollateralInstructionBuilder() *WithdrawObligationCollateral {
+	nd := &WithdrawObligationCollateral{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 9),
+	}
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetCollateralAmount sets the "collateralAmount" parameter.
+// The amount of collateral tokens to withdraw; the maximum for MaxUint64.
+func (inst *WithdrawObligationCollateral) SetCollateralAmount(collateralAmount uint64) *WithdrawObligationCollateral {
+	inst.CollateralAmount = &collateralAmount
+	return inst
+}
+
+// SetSourceCollateralAccount sets the "sourceCollateral" account.
+// The collateral supply of the withdraw reserve.
+func (inst *WithdrawObligationCollateral) SetSourceCollateralAccount(sourceCollateral ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(sourceCollateral).WRITE()
+	return inst
+}
+
+// GetSourceCollateralAccount gets the "sourceCollateral" account.
+// The collateral supply of the withdraw reserve.
+func (inst *WithdrawObligationCollateral) GetSourceCollateralAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetDestinationCollateralAccount sets the "destinationCollateral" account.
+// The user token account receiving the collateral tokens.
+func (inst *WithdrawObligationCollateral) SetDestinationCollateralAccount(destinationCollateral ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(destinationCollateral).WRITE()
+	return inst
+}
+
+// GetDestinationCollateralAccount gets the "destinationCollateral" account.
+// The user token account receiving the collateral tokens.
+func (inst *WithdrawObligationCollateral) GetDestinationCollateralAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetWithdrawReserveAccount sets the "withdrawReserve" account.
+// The withdraw reserve, refreshed.
+func (inst *WithdrawObligationCollateral) SetWithdrawReserveAccount(withdrawReserve ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(withdrawReserve)
+	return inst
+}
+
+// GetWithdrawReserveAccount gets the "withdrawReserve" account.
+// The withdraw reserve, refreshed.
+func (inst *WithdrawObligationCollateral) GetWithdrawReserveAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetObligationAccount sets the "obligation" account.
+// The obligation, refreshed.
+func (inst *WithdrawObligationCollateral) SetObligationAccount(obligation ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(obligation).WRITE()
+	return inst
+}
+
+// GetObligationAccount gets the "obligation" account.
+// The obligation, refreshed.
+func (inst *WithdrawObligationCollateral) GetObligationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetLendingMarketAccount sets the "lendingMarket" account.
+// The lending market.
+func (inst *WithdrawObligationCollateral) SetLendingMarketAccount(lendingMarket ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(lendingMarket)
+	return inst
+}
+
+// GetLendingMarketAccount gets the "lendingMarket" account.
+// The lending market.
+func (inst *WithdrawObligationCollateral) GetLendingMarketAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetLendingMarketAuthorityAccount sets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *WithdrawObligationCollateral) SetLendingMarketAuthorityAccount(lendingMarketAuthority ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(lendingMarketAuthority)
+	return inst
+}
+
+// GetLendingMarketAuthorityAccount gets the "lendingMarketAuthority" account.
+// The authority of the lending market, at FindLendingMarketAuthorityAddress(lendingMarket).
+func (inst *WithdrawObligationCollateral) GetLendingMarketAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetObligationOwnerAccount sets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *WithdrawObligationCollateral) SetObligationOwnerAccount(obligationOwner ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(obligationOwner).SIGNER()
+	return inst
+}
+
+// GetObligationOwnerAccount gets the "obligationOwner" account.
+// The owner of the obligation.
+func (inst *WithdrawObligationCollateral) GetObligationOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetClockAccount sets the "clock" account.
+// The clock sysvar.
+func (inst *WithdrawObligationCollateral) SetClockAccount(clock ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(clock)
+	return inst
+}
+
+// GetClockAccount gets the "clock" account.
+// The clock sysvar.
+func (inst *WithdrawObligationCollateral) GetClockAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *WithdrawObligationCollateral) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *WithdrawObligationCollateral {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *WithdrawObligationCollateral) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst WithdrawObligationCollateral) AccountNames() []string {
+	return []string{"sourceCollateral", "destinationCollateral", "withdrawReserve", "obligation", "lendingMarket", "lendingMarketAuthority", "obligationOwner", "clock", "tokenProgram"}
+}
+
+func (inst WithdrawObligationCollateral) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_WithdrawObligationCollateral),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst WithdrawObligationCollateral) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *WithdrawObligationCollateral) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.CollateralAmount == nil {
+			return errors.New("CollateralAmount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.SourceCollateral is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.DestinationCollateral is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.WithdrawReserve is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.Obligation is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.LendingMarket is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.LendingMarketAuthority is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.ObligationOwner is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.Clock is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *WithdrawObligationCollateral) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("WithdrawObligationCollateral")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("CollateralAmount", inst.CollateralAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("      sourceCollateral", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta(" destinationCollateral", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("       withdrawReserve", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("            obligation", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("         lendingMarket", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("lendingMarketAuthority", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("       obligationOwner", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("                 clock", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("          tokenProgram", inst.AccountMetaSlice.Get(8)))
+					})
+				})
+		})
+}
+
+func (obj WithdrawObligationCollateral) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `CollateralAmount` param:
+	err = encoder.Encode(obj.CollateralAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *WithdrawObligationCollateral) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `CollateralAmount`:
+	err = decoder.Decode(&obj.CollateralAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewWithdrawObligationCollateralInstruction declares a new WithdrawObligationCollateral instruction with the provided parameters and accounts.
+func NewWithdrawObligationCollateralInstruction(
+	// Parameters:
+	collateralAmount uint64,
+	// Accounts:
+	sourceCollateral ag_solanago.PublicKey,
+	destinationCollateral ag_solanago.PublicKey,
+	withdrawReserve ag_solanago.PublicKey,
+	obligation ag_solanago.PublicKey,
+	lendingMarket ag_solanago.PublicKey,
+	lendingMarketAuthority ag_solanago.PublicKey,
+	obligationOwner ag_solanago.PublicKey,
+) *WithdrawObligationCollateral {
+	return NewWithdrawObligationCollateralInstructionBuilder().
+		SetCollateralAmount(collateralAmount).
+		SetSourceCollateralAccount(sourceCollateral).
+		SetDestinationCollateralAccount(destinationCollateral).
+		SetWithdrawReserveAccount(withdrawReserve).
+		SetObligationAccount(obligation).
+		SetLendingMarketAccount(lendingMarket).
+		SetLendingMarketAuthorityAccount(lendingMarketAuthority).
+		SetObligationOwnerAccount(obligationOwner)
+}
@@ -0,0 +1,345 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+// PROGRAM_VERSION is the version of the state of the accounts; zero is
+// uninitialized.
+const PROGRAM_VERSION = 1
+
+// The sizes of the data of the accounts.
+const (
+	LENDING_MARKET_LEN = 258
+	RESERVE_LEN        = 571
+	OBLIGATION_LEN     = 916
+)
+
+// MAX_OBLIGATION_RESERVES is the maximum number of deposits and borrows of an
+// obligation, together.
+const MAX_OBLIGATION_RESERVES = 10
+
+// The sizes of the deposits and borrows of the obligations.
+const (
+	OBLIGATION_COLLATERAL_LEN = 56
+	OBLIGATION_LIQUIDITY_LEN  = 80
+
+	// The size of the deposits and borrows: one deposit and the remaining
+	// borrows.
+	OBLIGATION_DATA_LEN = OBLIGATION_COLLATERAL_LEN + (MAX_OBLIGATION_RESERVES-1)*OBLIGATION_LIQUIDITY_LEN
+)
+
+// LendingMarket is the state of a lending market account.
+type LendingMarket struct {
+	Version uint8
+
+	// The bump seed of the authority of the lending market.
+	BumpSeed uint8
+
+	// The owner of the lending market, who can add reserves.
+	Owner ag_solanago.PublicKey
+
+	// The currency of the prices of the market.
+	QuoteCurrency [32]uint8
+
+	TokenProgramID  ag_solanago.PublicKey
+	OracleProgramID ag_solanago.PublicKey
+}
+
+// Authority returns the authority of the lending market at the address.
+func (market *LendingMarket) Authority(address ag_solanago.PublicKey) (ag_solanago.PublicKey, error) {
+	return ag_solanago.CreateProgramAddress([][]byte{address[:], {market.BumpSeed}}, ProgramID)
+}
+
+// FindLendingMarketAuthorityAddress returns the authority of the lending
+// market at the address, which owns the supplies and mints of its reserves.
+func FindLendingMarketAuthorityAddress(lendingMarket ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{lendingMarket[:]}, ProgramID)
+}
+
+// Reserve is the state of a reserve account: the liquidity of a token in
+// a lending market, and the collateral token of its depositors.
+type Reserve struct {
+	Version       uint8
+	LastUpdate    LastUpdate
+	LendingMarket ag_solanago.PublicKey
+	Liquidity     ReserveLiquidity
+	Collateral    ReserveCollateral
+	Config        ReserveConfig
+}
+
+// ReserveLiquidity is the liquidity of a reserve.
+type ReserveLiquidity struct {
+	MintPubkey   ag_solanago.PublicKey
+	MintDecimals uint8
+
+	// The token account holding the liquidity.
+	SupplyPubkey ag_solanago.PublicKey
+
+	// The token account receiving the fees.
+	FeeReceiver ag_solanago.PublicKey
+
+	// The oracle price account of the liquidity.
+	OraclePubkey ag_solanago.PublicKey
+
+	// The liquidity in the supply.
+	AvailableAmount uint64
+
+	// The borrowed liquidity, with the interest accrued at the last refresh.
+	BorrowedAmountWads Decimal
+
+	// The product of the interest rates since the creation of the reserve.
+	CumulativeBorrowRateWads Decimal
+
+	// The price of one token of liquidity, in the quote currency of the
+	// lending market.
+	MarketPrice Decimal
+}
+
+// ReserveCollateral is the collateral token of a reserve.
+type ReserveCollateral struct {
+	MintPubkey      ag_solanago.PublicKey
+	MintTotalSupply uint64
+
+	// The token account holding the collateral deposited in obligations.
+	SupplyPubkey ag_solanago.PublicKey
+}
+
+// Obligation is the state of an obligation account: the collateral
+// deposited by its owner, and the liquidity borrowed against it, with their
+// values at the last refresh.
+type Obligation struct {
+	Version       uint8
+	LastUpdate    LastUpdate
+	LendingMarket ag_solanago.PublicKey
+	Owner         ag_solanago.PublicKey
+
+	// The value of the deposits.
+	DepositedValue Decimal
+
+	// The value of the borrows.
+	BorrowedValue Decimal
+
+	// The value that can be borrowed: the value of the deposits weighted by
+	// the loan to value ratios of their reserves.
+	AllowedBorrowValue Decimal
+
+	// The value of the borrows over which the obligation can be liquidated:
+	// the value of the deposits weighted by the liquidation thresholds of
+	// their reserves.
+	UnhealthyBorrowValue Decimal
+
+	Deposits []ObligationCollateral
+	Borrows  []ObligationLiquidity
+}
+
+// ObligationCollateral is a deposit of collateral of an obligation.
+type ObligationCollateral struct {
+	DepositReserve  ag_solanago.PublicKey
+	DepositedAmount uint64
+	MarketValue     Decimal
+}
+
+// ObligationLiquidity is a borrow of liquidity of an obligation.
+type ObligationLiquidity struct {
+	BorrowReserve ag_solanago.PublicKey
+
+	// The cumulative borrow rate of the reserve at the last refresh.
+	CumulativeBorrowRateWads Decimal
+
+	// The borrowed liquidity, with the interest accrued at the last refresh.
+	BorrowedAmountWads Decimal
+
+	MarketValue Decimal
+}
+
+func (obligation Obligation) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if len(obligation.Deposits)+len(obligation.Borrows) > MAX_OBLIGATION_RESERVES {
+		return fmt.Errorf("too many deposits and borrows: %d", len(obligation.Deposits)+len(obligation.Borrows))
+	}
+	for _, v := range []interface{}{
+		obligation.Version,
+		obligation.LastUpdate,
+		obligation.LendingMarket,
+		obligation.Owner,
+		obligation.DepositedValue,
+		obligation.BorrowedValue,
+		obligation.AllowedBorrowValue,
+		obligation.UnhealthyBorrowValue,
+		uint8(len(obligation.Deposits)),
+		uint8(len(obligation.Borrows)),
+	} {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+	for _, deposit := range obligation.Deposits {
+		if err := encoder.Encode(deposit); err != nil {
+			return err
+		}
+	}
+	for _, borrow := range obligation.Borrows {
+		if err := encoder.Encode(borrow); err != nil {
+			return err
+		}
+	}
+	padding := OBLIGATION_DATA_LEN - len(obligation.Deposits)*OBLIGATION_COLLATERAL_LEN - len(obligation.Borrows)*OBLIGATION_LIQUIDITY_LEN
+	if padding < 0 {
+		return errors.New("too many borrows")
+	}
+	return encoder.WriteBytes(make([]byte, padding), false)
+}
+
+func (obligation *Obligation) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	for _, v := range []interface{}{
+		&obligation.Version,
+		&obligation.LastUpdate,
+		&obligation.LendingMarket,
+		&obligation.Owner,
+		&obligation.DepositedValue,
+		&obligation.BorrowedValue,
+		&obligation.AllowedBorrowValue,
+		&obligation.UnhealthyBorrowValue,
+	} {
+		if err = decoder.Decode(v); err != nil {
+			return err
+		}
+	}
+	depositsLen, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	borrowsLen, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	if int(depositsLen)+int(borrowsLen) > MAX_OBLIGATION_RESERVES {
+		return fmt.Errorf("too many deposits and borrows: %d", int(depositsLen)+int(borrowsLen))
+	}
+	obligation.Deposits = make([]ObligationCollateral, depositsLen)
+	for i := range obligation.Deposits {
+		if err = decoder.Decode(&obligation.Deposits[i]); err != nil {
+			return err
+		}
+	}
+	obligation.Borrows = make([]ObligationLiquidity, borrowsLen)
+	for i := range obligation.Borrows {
+		if err = decoder.Decode(&obligation.Borrows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reserves returns the reserves of the deposits, then of the borrows, of the
+// obligation: the remaining accounts of RefreshObligation.
+func (obligation *Obligation) Reserves() []ag_solanago.PublicKey {
+	reserves := make([]ag_solanago.PublicKey, 0, len(obligation.Deposits)+len(obligation.Borrows))
+	for _, deposit := range obligation.Deposits {
+		reserves = append(reserves, deposit.DepositReserve)
+	}
+	for _, borrow := range obligation.Borrows {
+		reserves = append(reserves, borrow.BorrowReserve)
+	}
+	return reserves
+}
+
+func checkVersion(version uint8) error {
+	if version > PROGRAM_VERSION {
+		return fmt.Errorf("unsupported version %d", version)
+	}
+	return nil
+}
+
+// DecodeLendingMarket decodes the data of a lending market account.
+func DecodeLendingMarket(data []byte) (*LendingMarket, error) {
+	if len(data) < LENDING_MARKET_LEN {
+		return nil, errors.New("unable to decode lending market: data too short")
+	}
+	market := new(LendingMarket)
+	if err := ag_binary.NewBinDecoder(data).Decode(market); err != nil {
+		return nil, fmt.Errorf("unable to decode lending market: %w", err)
+	}
+	if err := checkVersion(market.Version); err != nil {
+		return nil, fmt.Errorf("unable to decode lending market: %w", err)
+	}
+	return market, nil
+}
+
+// DecodeReserve decodes the data of a reserve account.
+func DecodeReserve(data []byte) (*Reserve, error) {
+	if len(data) < RESERVE_LEN {
+		return nil, errors.New("unable to decode reserve: data too short")
+	}
+	reserve := new(Reserve)
+	if err := ag_binary.NewBinDecoder(data).Decode(reserve); err != nil {
+		return nil, fmt.Errorf("unable to decode reserve: %w", err)
+	}
+	if err := checkVersion(reserve.Version); err != nil {
+		return nil, fmt.Errorf("unable to decode reserve: %w", err)
+	}
+	return reserve, nil
+}
+
+// DecodeObligation decodes the data of an obligation account.
+func DecodeObligation(data []byte) (*Obligation, error) {
+	if len(data) < OBLIGATION_LEN {
+		return nil, errors.New("unable to decode obligation: data too short")
+	}
+	obligation := new(Obligation)
+	if err := ag_binary.NewBinDecoder(data).Decode(obligation); err != nil {
+		return nil, fmt.Errorf("unable to decode obligation: %w", err)
+	}
+	if err := checkVersion(obligation.Version); err != nil {
+		return nil, fmt.Errorf("unable to decode obligation: %w", err)
+	}
+	return obligation, nil
+}
+
+func registerAccountDecoders(programID ag_solanago.PublicKey) {
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountSizeMatcher(LENDING_MARKET_LEN), decodeLendingMarketAccount)
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountSizeMatcher(RESERVE_LEN), decodeReserveAccount)
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountSizeMatcher(OBLIGATION_LEN), decodeObligationAccount)
+}
+
+func decodeLendingMarketAccount(data []byte) (interface{}, error) {
+	out, err := DecodeLendingMarket(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeReserveAccount(data []byte) (interface{}, error) {
+	out, err := DecodeReserve(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeObligationAccount(data []byte) (interface{}, error) {
+	out, err := DecodeObligation(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
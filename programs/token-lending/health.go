@@ -0,0 +1,260 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+// ErrNegativeInterestRate is returned when the cumulative borrow rate of a
+// reserve is lower than the one of a borrow.
+var ErrNegativeInterestRate = errors.New("interest rate is negative")
+
+// SLOTS_PER_YEAR is the number of slots per year of the borrow rates:
+// ticks per second / ticks per slot * seconds per day * 365, in integers.
+const SLOTS_PER_YEAR = 160 / 64 * 86_400 * 365
+
+// INITIAL_COLLATERAL_RATIO is the number of collateral tokens per token of
+// liquidity of empty reserves.
+const INITIAL_COLLATERAL_RATIO = 1
+
+// LIQUIDATION_CLOSE_FACTOR is the percentage of the value of the borrows
+// that a liquidation can repay.
+const LIQUIDATION_CLOSE_FACTOR = 50
+
+// totalLiquidity returns the available and borrowed liquidity, scaled.
+func (reserve *Reserve) totalLiquidity() *big.Int {
+	available := new(big.Int).Mul(u128(reserve.Liquidity.AvailableAmount), wad)
+	return scaledAdd(available, reserve.Liquidity.BorrowedAmountWads.Scaled(), 192)
+}
+
+func (reserve *Reserve) utilizationRate() *big.Int {
+	total := reserve.totalLiquidity()
+	if total.Sign() == 0 {
+		return new(big.Int)
+	}
+	return checkBits(scaledDiv(reserve.Liquidity.BorrowedAmountWads.Scaled(), total, 192), 128)
+}
+
+// UtilizationRate returns the share of the liquidity of the reserve that is
+// borrowed.
+func (reserve *Reserve) UtilizationRate() (rate Rate, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	return rateFromScaled(reserve.utilizationRate()), nil
+}
+
+// The borrow rate goes linearly from the min to the optimal borrow rate up to
+// the optimal utilization rate, then to the max borrow rate.
+func (reserve *Reserve) currentBorrowRate() *big.Int {
+	config := reserve.Config
+	utilizationRate := reserve.utilizationRate()
+	optimalUtilizationRate := percentRate(config.OptimalUtilizationRate)
+	if utilizationRate.Cmp(optimalUtilizationRate) < 0 || config.OptimalUtilizationRate == 100 {
+		normalizedRate := scaledDiv(utilizationRate, optimalUtilizationRate, 128)
+		rateRange := percentRate(config.OptimalBorrowRate - config.MinBorrowRate)
+		return scaledAdd(scaledMul(normalizedRate, rateRange, 128), percentRate(config.MinBorrowRate), 128)
+	}
+	normalizedRate := scaledDiv(
+		scaledSub(utilizationRate, optimalUtilizationRate, 128),
+		percentRate(100-config.OptimalUtilizationRate),
+		128,
+	)
+	rateRange := percentRate(config.MaxBorrowRate - config.OptimalBorrowRate)
+	return scaledAdd(scaledMul(normalizedRate, rateRange, 128), percentRate(config.OptimalBorrowRate), 128)
+}
+
+// CurrentBorrowRate returns the yearly borrow rate of the reserve, at its
+// current utilization rate.
+func (reserve *Reserve) CurrentBorrowRate() (rate Rate, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	return rateFromScaled(reserve.currentBorrowRate()), nil
+}
+
+func (reserve *Reserve) collateralExchangeRate() *big.Int {
+	total := reserve.totalLiquidity()
+	if reserve.Collateral.MintTotalSupply == 0 || total.Sign() == 0 {
+		return new(big.Int).Mul(big.NewInt(INITIAL_COLLATERAL_RATIO), wad)
+	}
+	supply := new(big.Int).Mul(u128(reserve.Collateral.MintTotalSupply), wad)
+	return checkBits(scaledDiv(supply, total, 192), 128)
+}
+
+// CollateralExchangeRate returns the number of collateral tokens per token
+// of liquidity of the reserve.
+func (reserve *Reserve) CollateralExchangeRate() (rate Rate, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	return rateFromScaled(reserve.collateralExchangeRate()), nil
+}
+
+func (reserve *Reserve) collateralToLiquidity(collateralAmount uint64) *big.Int {
+	collateral := new(big.Int).Mul(u128(collateralAmount), wad)
+	return scaledDiv(collateral, reserve.collateralExchangeRate(), 192)
+}
+
+// CollateralToLiquidity returns the liquidity that the collateral tokens
+// redeem, rounded down.
+func (reserve *Reserve) CollateralToLiquidity(collateralAmount uint64) (liquidityAmount uint64, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	return toU64(new(big.Int).Quo(reserve.collateralToLiquidity(collateralAmount), wad))
+}
+
+// LiquidityToCollateral returns the collateral tokens that a deposit of the
+// liquidity mints, rounded down.
+func (reserve *Reserve) LiquidityToCollateral(liquidityAmount uint64) (collateralAmount uint64, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	collateral := checkBits(new(big.Int).Mul(reserve.collateralExchangeRate(), u128(liquidityAmount)), 128)
+	return toU64(new(big.Int).Quo(collateral, wad))
+}
+
+func (reserve *Reserve) marketValue(liquidityAmount *big.Int) *big.Int {
+	value := scaledMul(reserve.Liquidity.MarketPrice.Scaled(), liquidityAmount, 192)
+	decimals := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(reserve.Liquidity.MintDecimals)), nil)
+	return value.Quo(value, checkBits(decimals, 64))
+}
+
+// MarketValue returns the value of an amount of liquidity of the reserve, in
+// the quote currency of the lending market.
+func (reserve *Reserve) MarketValue(liquidityAmount Decimal) (value Decimal, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	return decimalFromScaled(reserve.marketValue(liquidityAmount.Scaled())), nil
+}
+
+// Refresh does what RefreshReserve does at the slot, with the market price
+// of the oracle of the reserve: it accrues the interest of the borrowed
+// liquidity since the last update, compounded per slot.
+func (reserve *Reserve) Refresh(slot uint64, marketPrice Decimal) (err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	if slot < reserve.LastUpdate.Slot {
+		return ErrMathOverflow
+	}
+	cumulativeBorrowRate := reserve.Liquidity.CumulativeBorrowRateWads
+	borrowedAmount := reserve.Liquidity.BorrowedAmountWads
+	if slotsElapsed := slot - reserve.LastUpdate.Slot; slotsElapsed > 0 {
+		slotInterestRate := new(big.Int).Quo(reserve.currentBorrowRate(), big.NewInt(SLOTS_PER_YEAR))
+		compoundedInterestRate := ratePow(scaledAdd(wad, slotInterestRate, 128), slotsElapsed)
+		cumulativeBorrowRate = decimalFromScaled(scaledMul(cumulativeBorrowRate.Scaled(), compoundedInterestRate, 192))
+		borrowedAmount = decimalFromScaled(scaledMul(borrowedAmount.Scaled(), compoundedInterestRate, 192))
+	}
+	reserve.Liquidity.CumulativeBorrowRateWads = cumulativeBorrowRate
+	reserve.Liquidity.BorrowedAmountWads = borrowedAmount
+	reserve.Liquidity.MarketPrice = marketPrice
+	reserve.LastUpdate = LastUpdate{Slot: slot}
+	return nil
+}
+
+// Refresh does what RefreshObligation does, with the reserves of the
+// obligation by address: it accrues the interest of the borrows, and updates
+// the values of the deposits and borrows at the market prices of the
+// reserves. The program requires the reserves to be refreshed in the same
+// slot; see Reserve.Refresh.
+func (obligation *Obligation) Refresh(reserves map[ag_solanago.PublicKey]*Reserve) (err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	deposits := make([]ObligationCollateral, len(obligation.Deposits))
+	borrows := make([]ObligationLiquidity, len(obligation.Borrows))
+	depositedValue, borrowedValue := new(big.Int), new(big.Int)
+	allowedBorrowValue, unhealthyBorrowValue := new(big.Int), new(big.Int)
+
+	for i, collateral := range obligation.Deposits {
+		reserve, ok := reserves[collateral.DepositReserve]
+		if !ok {
+			return fmt.Errorf("deposit reserve %s not provided", collateral.DepositReserve)
+		}
+		marketValue := reserve.marketValue(reserve.collateralToLiquidity(collateral.DepositedAmount))
+		collateral.MarketValue = decimalFromScaled(marketValue)
+		deposits[i] = collateral
+
+		depositedValue = scaledAdd(depositedValue, marketValue, 192)
+		allowedBorrowValue = scaledAdd(allowedBorrowValue, scaledMul(marketValue, percentRate(reserve.Config.LoanToValueRatio), 192), 192)
+		unhealthyBorrowValue = scaledAdd(unhealthyBorrowValue, scaledMul(marketValue, percentRate(reserve.Config.LiquidationThreshold), 192), 192)
+	}
+	for i, liquidity := range obligation.Borrows {
+		reserve, ok := reserves[liquidity.BorrowReserve]
+		if !ok {
+			return fmt.Errorf("borrow reserve %s not provided", liquidity.BorrowReserve)
+		}
+		switch reserve.Liquidity.CumulativeBorrowRateWads.Cmp(liquidity.CumulativeBorrowRateWads) {
+		case -1:
+			return ErrNegativeInterestRate
+		case 1:
+			compoundedInterestRate := checkBits(scaledDiv(reserve.Liquidity.CumulativeBorrowRateWads.Scaled(), liquidity.CumulativeBorrowRateWads.Scaled(), 192), 128)
+			liquidity.BorrowedAmountWads = decimalFromScaled(scaledMul(liquidity.BorrowedAmountWads.Scaled(), compoundedInterestRate, 192))
+			liquidity.CumulativeBorrowRateWads = reserve.Liquidity.CumulativeBorrowRateWads
+		}
+		marketValue := reserve.marketValue(liquidity.BorrowedAmountWads.Scaled())
+		liquidity.MarketValue = decimalFromScaled(marketValue)
+		borrows[i] = liquidity
+
+		borrowedValue = scaledAdd(borrowedValue, marketValue, 192)
+	}
+
+	obligation.Deposits = deposits
+	obligation.Borrows = borrows
+	obligation.DepositedValue = decimalFromScaled(depositedValue)
+	obligation.BorrowedValue = decimalFromScaled(borrowedValue)
+	obligation.AllowedBorrowValue = decimalFromScaled(allowedBorrowValue)
+	obligation.UnhealthyBorrowValue = decimalFromScaled(unhealthyBorrowValue)
+	return nil
+}
+
+// LoanToValue returns the value of the borrows over the value of the
+// deposits of the obligation.
+func (obligation *Obligation) LoanToValue() (rate Rate, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	return rateFromScaled(scaledDiv(obligation.BorrowedValue.Scaled(), obligation.DepositedValue.Scaled(), 192)), nil
+}
+
+// HealthFactor returns the unhealthy borrow value over the borrowed value of
+// the obligation: it can be liquidated at 1 or less, and it is +Inf without
+// borrows.
+func (obligation *Obligation) HealthFactor() float64 {
+	if obligation.BorrowedValue.IsZero() {
+		return math.Inf(1)
+	}
+	f, _ := new(big.Rat).SetFrac(obligation.UnhealthyBorrowValue.Scaled(), obligation.BorrowedValue.Scaled()).Float64()
+	return f
+}
+
+// IsUnhealthy returns whether the obligation can be liquidated: it has
+// borrows, and their value reaches the unhealthy borrow value.
+func (obligation *Obligation) IsUnhealthy() bool {
+	return len(obligation.Borrows) > 0 && obligation.BorrowedValue.Cmp(obligation.UnhealthyBorrowValue) >= 0
+}
+
+// RemainingBorrowValue returns the value that the obligation can still
+// borrow, zero if the value of its borrows exceeds the allowed value.
+func (obligation *Obligation) RemainingBorrowValue() Decimal {
+	if obligation.BorrowedValue.Cmp(obligation.AllowedBorrowValue) >= 0 {
+		return Decimal{}
+	}
+	return decimalFromScaled(new(big.Int).Sub(obligation.AllowedBorrowValue.Scaled(), obligation.BorrowedValue.Scaled()))
+}
+
+// MaxLiquidationAmount returns the amount of a borrow of the obligation that
+// a liquidation can repay: LIQUIDATION_CLOSE_FACTOR percent of the value of
+// the borrows, up to the whole borrow.
+func (obligation *Obligation) MaxLiquidationAmount(liquidity ObligationLiquidity) (amount Decimal, err error) {
+	defer recoverAs(&err, ErrMathOverflow)
+	maxLiquidationValue := minBig(
+		scaledMul(obligation.BorrowedValue.Scaled(), percentRate(LIQUIDATION_CLOSE_FACTOR), 192),
+		liquidity.MarketValue.Scaled(),
+	)
+	maxLiquidationPct := scaledDiv(maxLiquidationValue, liquidity.MarketValue.Scaled(), 192)
+	return decimalFromScaled(scaledMul(liquidity.BorrowedAmountWads.Scaled(), maxLiquidationPct, 192)), nil
+}
@@ -0,0 +1,350 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func decimal(t *testing.T, scaled string) Decimal {
+	v, ok := new(big.Int).SetString(scaled, 10)
+	ag_require.True(t, ok)
+	d, err := NewDecimalFromScaled(v)
+	ag_require.NoError(t, err)
+	return d
+}
+
+func encode(t *testing.T, v interface{}, size int) []byte {
+	buf := new(bytes.Buffer)
+	ag_require.NoError(t, ag_binary.NewBinEncoder(buf).Encode(v))
+	ag_require.LessOrEqual(t, buf.Len(), size)
+	return append(buf.Bytes(), make([]byte, size-buf.Len())...)
+}
+
+var (
+	depositReserveAddress = ag_solanago.SysVarRentPubkey
+	borrowReserveAddress  = ag_solanago.SysVarFeesPubkey
+)
+
+// The deposit reserve: 600 tokens available and 400 borrowed at 2, as many
+// collateral tokens.
+func depositReserve() *Reserve {
+	return &Reserve{
+		Version: PROGRAM_VERSION,
+		Liquidity: ReserveLiquidity{
+			MintDecimals:             6,
+			AvailableAmount:          600_000_000,
+			BorrowedAmountWads:       NewDecimal(400_000_000),
+			CumulativeBorrowRateWads: NewDecimal(1),
+			MarketPrice:              NewDecimal(2),
+		},
+		Collateral: ReserveCollateral{MintTotalSupply: 1_000_000_000},
+		Config: ReserveConfig{
+			OptimalUtilizationRate: 80,
+			LoanToValueRatio:       50,
+			LiquidationThreshold:   60,
+			OptimalBorrowRate:      8,
+			MaxBorrowRate:          20,
+		},
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	ag_require.Equal(t, "1.1", decimal(t, "1100000000000000000").String())
+	ag_require.Equal(t, "0.000000000000000001", decimal(t, "1").String())
+	ag_require.Equal(t, "400000000", NewDecimal(400_000_000).String())
+	ag_require.Equal(t, "0", Decimal{}.String())
+	ag_require.Equal(t, "0.5", RateFromPercent(50).String())
+
+	d := decimal(t, "1500000000000000000")
+	floor, err := d.Floor()
+	ag_require.NoError(t, err)
+	ceil, err := d.Ceil()
+	ag_require.NoError(t, err)
+	round, err := d.Round()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []uint64{1, 2, 2}, []uint64{floor, ceil, round})
+
+	_, err = NewDecimalFromScaled(new(big.Int).Lsh(big.NewInt(1), 128))
+	ag_require.ErrorIs(t, err, ErrMathOverflow)
+}
+
+func TestDecodeAccounts(t *testing.T) {
+	market := &LendingMarket{
+		Version:         PROGRAM_VERSION,
+		BumpSeed:        255,
+		Owner:           ag_solanago.SysVarClockPubkey,
+		TokenProgramID:  ag_solanago.TokenProgramID,
+		OracleProgramID: ag_solanago.SysVarRentPubkey,
+	}
+	copy(market.QuoteCurrency[:], "USD")
+	decoded, err := ag_solanago.DecodeAccount(ProgramID, encode(t, market, LENDING_MARKET_LEN))
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, market, decoded)
+
+	reserve := depositReserve()
+	decoded, err = ag_solanago.DecodeAccount(ProgramID, encode(t, reserve, RESERVE_LEN))
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, reserve, decoded)
+
+	obligation := &Obligation{
+		Version:  PROGRAM_VERSION,
+		Owner:    ag_solanago.SysVarClockPubkey,
+		Deposits: []ObligationCollateral{{DepositReserve: depositReserveAddress, DepositedAmount: 100_000_000}},
+		Borrows: []ObligationLiquidity{{
+			BorrowReserve:            borrowReserveAddress,
+			CumulativeBorrowRateWads: NewDecimal(1),
+			BorrowedAmountWads:       NewDecimal(100_000_000),
+		}},
+	}
+	data := encode(t, obligation, OBLIGATION_LEN)
+	ag_require.Equal(t, uint8(1), data[138])
+	ag_require.Equal(t, uint8(1), data[139])
+	decoded, err = ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, obligation, decoded)
+	ag_require.Equal(t, []ag_solanago.PublicKey{depositReserveAddress, borrowReserveAddress}, obligation.Reserves())
+
+	data[0] = 2
+	_, err = DecodeObligation(data)
+	ag_require.Error(t, err)
+}
+
+// fixture is the data of an account, assembled at the offsets of the
+// on-chain layout.
+type fixture []byte
+
+func (f fixture) u64(offset int, v uint64) {
+	ag_binary.LE.PutUint64(f[offset:], v)
+}
+
+func (f fixture) u128(t *testing.T, offset int, scaled string) {
+	v, ok := new(big.Int).SetString(scaled, 10)
+	ag_require.True(t, ok)
+	be := v.FillBytes(make([]byte, 16))
+	for i := range be {
+		f[offset+i] = be[15-i]
+	}
+}
+
+func (f fixture) key(offset int, k ag_solanago.PublicKey) {
+	copy(f[offset:], k[:])
+}
+
+func TestDecodeReserve_Layout(t *testing.T) {
+	market := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	liquidityMint := ag_solanago.MPK("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	supply := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	feeReceiver := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	oracle := ag_solanago.MPK("3or4uF7ZyuQW5GGmcmdXDJasNiSZUURF2az1UrRPYQTg")
+	collateralMint := ag_solanago.MPK("9WWfC3y4uCNofr2qEFHSVUXkCxW99JiYkMWmSZvVt8j3")
+	collateralSupply := ag_solanago.SysVarClockPubkey
+
+	data := make(fixture, RESERVE_LEN)
+	data[0] = PROGRAM_VERSION
+	data.u64(1, 123_456) // last_update.slot
+	data[9] = 1          // last_update.stale
+	data.key(10, market)
+	data.key(42, liquidityMint)
+	data[74] = 6 // liquidity.mint_decimals
+	data.key(75, supply)
+	data.key(107, feeReceiver)
+	data.key(139, oracle)
+	data.u64(171, 600_000_000)                       // liquidity.available_amount
+	data.u128(t, 179, "400000000500000000000000000") // liquidity.borrowed_amount_wads
+	data.u128(t, 195, "1020000000000000000")         // liquidity.cumulative_borrow_rate_wads
+	data.u128(t, 211, "1000100000000000000")         // liquidity.market_price
+	data.key(227, collateralMint)
+	data.u64(259, 900_000_000) // collateral.mint_total_supply
+	data.key(267, collateralSupply)
+	copy(data[299:306], []byte{80, 50, 5, 60, 0, 8, 20}) // config rates
+	data.u64(306, 1_000_000_000_000)                     // config.fees.borrow_fee_wad
+	data.u64(314, 3_000_000_000_000_000)                 // config.fees.flash_loan_fee_wad
+	data[322] = 20                                       // config.fees.host_fee_percentage
+
+	reserve, err := DecodeReserve(data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, LastUpdate{Slot: 123_456, Stale: true}, reserve.LastUpdate)
+	ag_require.Equal(t, market, reserve.LendingMarket)
+	ag_require.Equal(t, liquidityMint, reserve.Liquidity.MintPubkey)
+	ag_require.Equal(t, uint8(6), reserve.Liquidity.MintDecimals)
+	ag_require.Equal(t, supply, reserve.Liquidity.SupplyPubkey)
+	ag_require.Equal(t, feeReceiver, reserve.Liquidity.FeeReceiver)
+	ag_require.Equal(t, oracle, reserve.Liquidity.OraclePubkey)
+	ag_require.Equal(t, uint64(600_000_000), reserve.Liquidity.AvailableAmount)
+	ag_require.Equal(t, "400000000.5", reserve.Liquidity.BorrowedAmountWads.String())
+	ag_require.Equal(t, "1.02", reserve.Liquidity.CumulativeBorrowRateWads.String())
+	ag_require.Equal(t, "1.0001", reserve.Liquidity.MarketPrice.String())
+	ag_require.Equal(t, collateralMint, reserve.Collateral.MintPubkey)
+	ag_require.Equal(t, uint64(900_000_000), reserve.Collateral.MintTotalSupply)
+	ag_require.Equal(t, collateralSupply, reserve.Collateral.SupplyPubkey)
+	ag_require.Equal(t, ReserveConfig{
+		OptimalUtilizationRate: 80,
+		LoanToValueRatio:       50,
+		LiquidationBonus:       5,
+		LiquidationThreshold:   60,
+		OptimalBorrowRate:      8,
+		MaxBorrowRate:          20,
+		Fees: ReserveFees{
+			BorrowFeeWad:      1_000_000_000_000,
+			FlashLoanFeeWad:   3_000_000_000_000_000,
+			HostFeePercentage: 20,
+		},
+	}, reserve.Config)
+
+	// Encoding the decoded reserve gives back the layout.
+	ag_require.Equal(t, []byte(data), encode(t, reserve, RESERVE_LEN))
+}
+
+func TestDecodeObligation_Layout(t *testing.T) {
+	market := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	owner := ag_solanago.MPK("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+
+	data := make(fixture, OBLIGATION_LEN)
+	data[0] = PROGRAM_VERSION
+	data.u64(1, 123_456) // last_update.slot
+	data.key(10, market)
+	data.key(42, owner)
+	data.u128(t, 74, "200000000000000000000")  // deposited_value
+	data.u128(t, 90, "100250000000000000000")  // borrowed_value
+	data.u128(t, 106, "100000000000000000000") // allowed_borrow_value
+	data.u128(t, 122, "120000000000000000000") // unhealthy_borrow_value
+	data[138] = 1                              // deposits_len
+	data[139] = 1                              // borrows_len
+	// The deposit, then the borrow.
+	data.key(140, depositReserveAddress)
+	data.u64(172, 100_000_000)
+	data.u128(t, 180, "200000000000000000000")
+	data.key(196, borrowReserveAddress)
+	data.u128(t, 228, "1020000000000000000")
+	data.u128(t, 244, "100250000000000000000000000")
+	data.u128(t, 260, "100250000000000000000")
+
+	obligation, err := DecodeObligation(data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(123_456), obligation.LastUpdate.Slot)
+	ag_require.Equal(t, market, obligation.LendingMarket)
+	ag_require.Equal(t, owner, obligation.Owner)
+	ag_require.Equal(t, "200", obligation.DepositedValue.String())
+	ag_require.Equal(t, "100.25", obligation.BorrowedValue.String())
+	ag_require.Equal(t, "100", obligation.AllowedBorrowValue.String())
+	ag_require.Equal(t, "120", obligation.UnhealthyBorrowValue.String())
+	ag_require.Equal(t, []ObligationCollateral{{
+		DepositReserve:  depositReserveAddress,
+		DepositedAmount: 100_000_000,
+		MarketValue:     NewDecimal(200),
+	}}, obligation.Deposits)
+	ag_require.Len(t, obligation.Borrows, 1)
+	ag_require.Equal(t, borrowReserveAddress, obligation.Borrows[0].BorrowReserve)
+	ag_require.Equal(t, "1.02", obligation.Borrows[0].CumulativeBorrowRateWads.String())
+	ag_require.Equal(t, "100250000", obligation.Borrows[0].BorrowedAmountWads.String())
+	ag_require.Equal(t, "100.25", obligation.Borrows[0].MarketValue.String())
+
+	// Encoding the decoded obligation gives back the layout.
+	ag_require.Equal(t, []byte(data), encode(t, obligation, OBLIGATION_LEN))
+}
+
+func TestReserveRates(t *testing.T) {
+	reserve := depositReserve()
+	utilizationRate, err := reserve.UtilizationRate()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "0.4", utilizationRate.String())
+
+	// Half the optimal utilization rate: half the optimal borrow rate.
+	borrowRate, err := reserve.CurrentBorrowRate()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "0.04", borrowRate.String())
+
+	// 1 + 0.04 / SLOTS_PER_YEAR per slot.
+	ag_require.NoError(t, reserve.Refresh(1, NewDecimal(3)))
+	ag_require.Equal(t, "1.000000000634195839", reserve.Liquidity.CumulativeBorrowRateWads.String())
+	ag_require.Equal(t, "400000000.2536783356", reserve.Liquidity.BorrowedAmountWads.String())
+	ag_require.Equal(t, NewDecimal(3), reserve.Liquidity.MarketPrice)
+	ag_require.Equal(t, LastUpdate{Slot: 1}, reserve.LastUpdate)
+
+	// Above the optimal utilization rate.
+	reserve = depositReserve()
+	reserve.Liquidity.AvailableAmount = 100_000_000
+	reserve.Liquidity.BorrowedAmountWads = NewDecimal(900_000_000)
+	borrowRate, err = reserve.CurrentBorrowRate()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "0.14", borrowRate.String())
+
+	reserve.Collateral.MintTotalSupply = 500_000_000
+	collateral, err := reserve.LiquidityToCollateral(1000)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(500), collateral)
+	liquidity, err := reserve.CollateralToLiquidity(500)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(1000), liquidity)
+}
+
+func TestObligationHealth(t *testing.T) {
+	borrowReserve := &Reserve{
+		Version: PROGRAM_VERSION,
+		Liquidity: ReserveLiquidity{
+			MintDecimals:             6,
+			CumulativeBorrowRateWads: decimal(t, "1100000000000000000"),
+			MarketPrice:              NewDecimal(1),
+		},
+	}
+	reserves := map[ag_solanago.PublicKey]*Reserve{
+		depositReserveAddress: depositReserve(),
+		borrowReserveAddress:  borrowReserve,
+	}
+	obligation := &Obligation{
+		Version:  PROGRAM_VERSION,
+		Deposits: []ObligationCollateral{{DepositReserve: depositReserveAddress, DepositedAmount: 100_000_000}},
+		Borrows: []ObligationLiquidity{{
+			BorrowReserve:            borrowReserveAddress,
+			CumulativeBorrowRateWads: NewDecimal(1),
+			BorrowedAmountWads:       NewDecimal(100_000_000),
+		}},
+	}
+
+	// 100 collateral tokens worth 200, and 110 borrowed with the interest.
+	ag_require.NoError(t, obligation.Refresh(reserves))
+	ag_require.Equal(t, NewDecimal(200), obligation.DepositedValue)
+	ag_require.Equal(t, NewDecimal(100), obligation.AllowedBorrowValue)
+	ag_require.Equal(t, NewDecimal(120), obligation.UnhealthyBorrowValue)
+	ag_require.Equal(t, NewDecimal(110), obligation.BorrowedValue)
+	ag_require.Equal(t, NewDecimal(110_000_000), obligation.Borrows[0].BorrowedAmountWads)
+	ag_require.Equal(t, borrowReserve.Liquidity.CumulativeBorrowRateWads, obligation.Borrows[0].CumulativeBorrowRateWads)
+	ag_require.InDelta(t, 120.0/110.0, obligation.HealthFactor(), 1e-12)
+	ag_require.False(t, obligation.IsUnhealthy())
+	ag_require.Equal(t, Decimal{}, obligation.RemainingBorrowValue())
+
+	loanToValue, err := obligation.LoanToValue()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "0.55", loanToValue.String())
+
+	borrowReserve.Liquidity.MarketPrice = decimal(t, "1200000000000000000")
+	ag_require.NoError(t, obligation.Refresh(reserves))
+	ag_require.Equal(t, NewDecimal(132), obligation.BorrowedValue)
+	ag_require.True(t, obligation.IsUnhealthy())
+
+	amount, err := obligation.MaxLiquidationAmount(obligation.Borrows[0])
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, NewDecimal(55_000_000), amount)
+
+	delete(reserves, borrowReserveAddress)
+	ag_require.Error(t, obligation.Refresh(reserves))
+}
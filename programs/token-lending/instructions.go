@@ -0,0 +1,257 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenlending builds the instructions of the SPL Token Lending
+// program, decodes its lending market, reserve and obligation accounts, and
+// computes the values and health of obligations like the program does.
+package tokenlending
+
+import (
+	"bytes"
+	"fmt"
+
+	ag_spew "github.com/davecgh/go-spew/spew"
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_text "github.com/gagliardetto/solana-go/text"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+var ProgramID ag_solanago.PublicKey = ag_solanago.TokenLendingProgramID
+
+func SetProgramID(pubkey ag_solanago.PublicKey) {
+	ProgramID = pubkey
+	ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
+}
+
+const ProgramName = "TokenLending"
+
+func init() {
+	if !ProgramID.IsZero() {
+		ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+		registerAccountDecoders(ProgramID)
+	}
+}
+
+const (
+	// Initializes a lending market.
+	Instruction_InitLendingMarket uint8 = iota
+
+	// Sets the owner of a lending market.
+	Instruction_SetLendingMarketOwner
+
+	// Initializes a reserve of a token in a lending market.
+	Instruction_InitReserve
+
+	// Accrues the interest of a reserve and updates its market price.
+	Instruction_RefreshReserve
+
+	// Deposits liquidity into a reserve, for collateral tokens.
+	Instruction_DepositReserveLiquidity
+
+	// Redeems collateral tokens of a reserve, for liquidity.
+	Instruction_RedeemReserveCollateral
+
+	// Initializes an obligation.
+	Instruction_InitObligation
+
+	// Updates the values of the deposits and borrows of an obligation.
+	Instruction_RefreshObligation
+
+	// Deposits collateral tokens into an obligation.
+	Instruction_DepositObligationCollateral
+
+	// Withdraws collateral tokens from an obligation.
+	Instruction_WithdrawObligationCollateral
+
+	// Borrows liquidity against the deposits of an obligation.
+	Instruction_BorrowObligationLiquidity
+
+	// Repays liquidity borrowed by an obligation.
+	Instruction_RepayObligationLiquidity
+
+	// Repays liquidity borrowed by an unhealthy obligation, for its collateral.
+	Instruction_LiquidateObligation
+
+	// Lends liquidity for the duration of the instruction.
+	Instruction_FlashLoan
+)
+
+// InstructionIDToName returns the name of the instruction given its ID.
+func InstructionIDToName(id uint8) string {
+	switch id {
+	case Instruction_InitLendingMarket:
+		return "InitLendingMarket"
+	case Instruction_SetLendingMarketOwner:
+		return "SetLendingMarketOwner"
+	case Instruction_InitReserve:
+		return "InitReserve"
+	case Instruction_RefreshReserve:
+		return "RefreshReserve"
+	case Instruction_DepositReserveLiquidity:
+		return "DepositReserveLiquidity"
+	case Instruction_RedeemReserveCollateral:
+		return "RedeemReserveCollateral"
+	case Instruction_InitObligation:
+		return "InitObligation"
+	case Instruction_RefreshObligation:
+		return "RefreshObligation"
+	case Instruction_DepositObligationCollateral:
+		return "DepositObligationCollateral"
+	case Instruction_WithdrawObligationCollateral:
+		return "WithdrawObligationCollateral"
+	case Instruction_BorrowObligationLiquidity:
+		return "BorrowObligationLiquidity"
+	case Instruction_RepayObligationLiquidity:
+		return "RepayObligationLiquidity"
+	case Instruction_LiquidateObligation:
+		return "LiquidateObligation"
+	case Instruction_FlashLoan:
+		return "FlashLoan"
+	default:
+		return ""
+	}
+}
+
+type Instruction struct {
+	ag_binary.BaseVariant
+}
+
+func (inst *Instruction) EncodeToTree(parent ag_treeout.Branches) {
+	if enToTree, ok := inst.Impl.(ag_text.EncodableToTree); ok {
+		enToTree.EncodeToTree(parent)
+	} else {
+		parent.Child(ag_spew.Sdump(inst))
+	}
+}
+
+var InstructionImplDef = ag_binary.NewVariantDefinition(
+	ag_binary.Uint8TypeIDEncoding,
+	[]ag_binary.VariantType{
+		{
+			"InitLendingMarket", (*InitLendingMarket)(nil),
+		},
+		{
+			"SetLendingMarketOwner", (*SetLendingMarketOwner)(nil),
+		},
+		{
+			"InitReserve", (*InitReserve)(nil),
+		},
+		{
+			"RefreshReserve", (*RefreshReserve)(nil),
+		},
+		{
+			"DepositReserveLiquidity", (*DepositReserveLiquidity)(nil),
+		},
+		{
+			"RedeemReserveCollateral", (*RedeemReserveCollateral)(nil),
+		},
+		{
+			"InitObligation", (*InitObligation)(nil),
+		},
+		{
+			"RefreshObligation", (*RefreshObligation)(nil),
+		},
+		{
+			"DepositObligationCollateral", (*DepositObligationCollateral)(nil),
+		},
+		{
+			"WithdrawObligationCollateral", (*WithdrawObligationCollateral)(nil),
+		},
+		{
+			"BorrowObligationLiquidity", (*BorrowObligationLiquidity)(nil),
+		},
+		{
+			"RepayObligationLiquidity", (*RepayObligationLiquidity)(nil),
+		},
+		{
+			"LiquidateObligation", (*LiquidateObligation)(nil),
+		},
+		{
+			"FlashLoan", (*FlashLoan)(nil),
+		},
+	},
+)
+
+func (inst *Instruction) ProgramID() ag_solanago.PublicKey {
+	return ProgramID
+}
+
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
+func (inst *Instruction) Accounts() (out []*ag_solanago.AccountMeta) {
+	return inst.Impl.(ag_solanago.AccountsGettable).GetAccounts()
+}
+
+func (inst *Instruction) Data() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := ag_binary.NewBinEncoder(buf).Encode(inst); err != nil {
+		return nil, fmt.Errorf("unable to encode instruction: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (inst *Instruction) TextEncode(encoder *ag_text.Encoder, option *ag_text.Option) error {
+	return encoder.Encode(inst.Impl, option)
+}
+
+func (inst *Instruction) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	return inst.BaseVariant.UnmarshalBinaryVariant(decoder, InstructionImplDef)
+}
+
+func (inst Instruction) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	err := encoder.WriteUint8(inst.TypeID.Uint8())
+	if err != nil {
+		return fmt.Errorf("unable to write variant type: %w", err)
+	}
+	return encoder.Encode(inst.Impl)
+}
+
+func registryDecodeInstruction(accounts []*ag_solanago.AccountMeta, data []byte) (interface{}, error) {
+	inst, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func DecodeInstruction(accounts []*ag_solanago.AccountMeta, data []byte) (*Instruction, error) {
+	inst := new(Instruction)
+	if err := ag_binary.NewBinDecoder(data).Decode(inst); err != nil {
+		return nil, fmt.Errorf("unable to decode instruction: %w", err)
+	}
+	if v, ok := inst.Impl.(ag_solanago.AccountsSettable); ok {
+		err := v.SetAccounts(accounts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set accounts for instruction: %w", err)
+		}
+	}
+	return inst, nil
+}
+
+// appendAccountNames appends the names of the remaining accounts.
+func appendAccountNames(names []string, name string, accounts ag_solanago.AccountMetaSlice) []string {
+	for i := range accounts {
+		names = append(names, fmt.Sprintf("%s[%d]", name, i))
+	}
+	return names
+}
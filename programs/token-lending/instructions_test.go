@@ -0,0 +1,116 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func TestInitReserve(t *testing.T) {
+	config := ReserveConfig{
+		OptimalUtilizationRate: 80,
+		LoanToValueRatio:       50,
+		LiquidationBonus:       5,
+		LiquidationThreshold:   55,
+		MinBorrowRate:          0,
+		OptimalBorrowRate:      4,
+		MaxBorrowRate:          30,
+		Fees: ReserveFees{
+			BorrowFeeWad:      100_000_000_000_000,
+			FlashLoanFeeWad:   3_000_000_000_000_000,
+			HostFeePercentage: 20,
+		},
+	}
+	builder := NewInitReserveInstructionBuilder().
+		SetLiquidityAmount(1000).
+		SetConfig(config)
+	for i := range builder.AccountMetaSlice {
+		if builder.AccountMetaSlice[i] == nil {
+			builder.AccountMetaSlice[i] = ag_solanago.Meta(ag_solanago.SysVarClockPubkey)
+		}
+	}
+	inst, err := builder.ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := []byte{Instruction_InitReserve}
+	expected = binary.LittleEndian.AppendUint64(expected, 1000)
+	expected = append(expected, 80, 50, 5, 55, 0, 4, 30)
+	expected = binary.LittleEndian.AppendUint64(expected, 100_000_000_000_000)
+	expected = binary.LittleEndian.AppendUint64(expected, 3_000_000_000_000_000)
+	expected = append(expected, 20)
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+	ag_require.Len(t, inst.Accounts(), 17)
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "InitReserve", decoded.InstructionName())
+	ag_require.Equal(t, config, *decoded.Impl.(*InitReserve).Config)
+
+	config.LiquidationThreshold = 50
+	_, err = builder.SetConfig(config).ValidateAndBuild()
+	ag_require.Error(t, err)
+}
+
+func TestRefreshObligation(t *testing.T) {
+	obligation := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	reserves := []ag_solanago.PublicKey{ag_solanago.SysVarRentPubkey, ag_solanago.SysVarFeesPubkey}
+	inst, err := NewRefreshObligationInstruction(obligation, reserves).ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []byte{Instruction_RefreshObligation}, data)
+	ag_require.Equal(t, []*ag_solanago.AccountMeta{
+		ag_solanago.Meta(obligation).WRITE(),
+		ag_solanago.Meta(ag_solanago.SysVarClockPubkey),
+		ag_solanago.Meta(reserves[0]),
+		ag_solanago.Meta(reserves[1]),
+	}, inst.Accounts())
+	ag_require.Equal(t, []string{"obligation", "clock", "reserves[0]", "reserves[1]"}, inst.Impl.(RefreshObligation).AccountNames())
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, ag_solanago.AccountMetaSlice{ag_solanago.Meta(reserves[0]), ag_solanago.Meta(reserves[1])}, decoded.Impl.(*RefreshObligation).Reserves)
+}
+
+func TestBorrowObligationLiquidity(t *testing.T) {
+	owner := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	market := ag_solanago.SysVarRentPubkey
+	authority, _, err := FindLendingMarketAuthorityAddress(market)
+	ag_require.NoError(t, err)
+	a, b := ag_solanago.SysVarFeesPubkey, ag_solanago.SysVarEpochSchedulePubkey
+
+	builder := NewBorrowObligationLiquidityInstruction(500, a, owner, b, a, owner, market, authority, owner)
+	inst, err := builder.ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := binary.LittleEndian.AppendUint64([]byte{Instruction_BorrowObligationLiquidity}, 500)
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+	ag_require.Len(t, inst.Accounts(), 10)
+	ag_require.Equal(t, ag_solanago.Meta(owner).SIGNER(), inst.Accounts()[7])
+
+	inst, err = builder.SetHostFeeReceiverAccount(b).ValidateAndBuild()
+	ag_require.NoError(t, err)
+	ag_require.Len(t, inst.Accounts(), 11)
+	ag_require.Equal(t, ag_solanago.Meta(b).WRITE(), inst.Accounts()[10])
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrMathOverflow is returned when a calculation overflows or divides by
+// zero, which fails the instruction on chain.
+var ErrMathOverflow = errors.New("math operation overflow")
+
+// errAbort is panicked by the checked operations, and recovered by the
+// exported functions as the error of the program; it mirrors the `?` of the
+// checked arithmetic of the program.
+type errAbort struct{}
+
+func recoverAs(err *error, abortErr error) {
+	if r := recover(); r != nil {
+		if _, ok := r.(errAbort); !ok {
+			panic(r)
+		}
+		*err = abortErr
+	}
+}
+
+var (
+	wad     = big.NewInt(WAD)
+	halfWad = big.NewInt(WAD / 2)
+	maxU64  = new(big.Int).SetUint64(^uint64(0))
+	maxU128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+)
+
+func u128(v uint64) *big.Int {
+	return new(big.Int).SetUint64(v)
+}
+
+// checkBits aborts if the value doesn't fit an unsigned integer of the
+// bits: the program computes the decimals on 192 bits and the rates on 128.
+func checkBits(v *big.Int, bits int) *big.Int {
+	if v.Sign() < 0 || v.BitLen() > bits {
+		panic(errAbort{})
+	}
+	return v
+}
+
+func toU64(v *big.Int) (uint64, error) {
+	if v.Sign() < 0 || v.Cmp(maxU64) > 0 {
+		return 0, ErrMathOverflow
+	}
+	return v.Uint64(), nil
+}
+
+func percentRate(percent uint8) *big.Int {
+	return new(big.Int).Mul(big.NewInt(int64(percent)), big.NewInt(WAD/100))
+}
+
+// The operations on scaled values, with the bits of the type of the
+// program: 192 for decimals, 128 for rates.
+
+func scaledAdd(a, b *big.Int, bits int) *big.Int {
+	return checkBits(new(big.Int).Add(a, b), bits)
+}
+
+func scaledSub(a, b *big.Int, bits int) *big.Int {
+	return checkBits(new(big.Int).Sub(a, b), bits)
+}
+
+func scaledMul(a, b *big.Int, bits int) *big.Int {
+	return new(big.Int).Quo(checkBits(new(big.Int).Mul(a, b), bits), wad)
+}
+
+func scaledDiv(a, b *big.Int, bits int) *big.Int {
+	if b.Sign() == 0 {
+		panic(errAbort{})
+	}
+	return new(big.Int).Quo(checkBits(new(big.Int).Mul(a, wad), bits), b)
+}
+
+// ratePow raises a rate to the power, by squaring.
+func ratePow(base *big.Int, exp uint64) *big.Int {
+	ret := new(big.Int).Set(wad)
+	if exp%2 != 0 {
+		ret.Set(base)
+	}
+	for exp > 0 {
+		exp /= 2
+		base = scaledMul(base, base, 128)
+		if exp%2 != 0 {
+			ret = scaledMul(ret, base, 128)
+		}
+	}
+	return ret
+}
+
+func minBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return a
+	}
+	return b
+}
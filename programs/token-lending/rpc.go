@@ -0,0 +1,89 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"context"
+	"fmt"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func getAccountData(ctx context.Context, rpcCli *rpc.Client, address ag_solanago.PublicKey, name string) ([]byte, error) {
+	resp, err := rpcCli.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %s account: %w", name, err)
+	}
+	if !resp.Value.Owner.Equals(ProgramID) {
+		return nil, fmt.Errorf("account %s is not owned by the token lending program", address)
+	}
+	return resp.GetBinary(), nil
+}
+
+// FetchLendingMarket fetches the lending market account at the address.
+func FetchLendingMarket(ctx context.Context, rpcCli *rpc.Client, address ag_solanago.PublicKey) (*LendingMarket, error) {
+	data, err := getAccountData(ctx, rpcCli, address, "lending market")
+	if err != nil {
+		return nil, err
+	}
+	return DecodeLendingMarket(data)
+}
+
+// FetchReserve fetches the reserve account at the address.
+func FetchReserve(ctx context.Context, rpcCli *rpc.Client, address ag_solanago.PublicKey) (*Reserve, error) {
+	data, err := getAccountData(ctx, rpcCli, address, "reserve")
+	if err != nil {
+		return nil, err
+	}
+	return DecodeReserve(data)
+}
+
+// FetchObligation fetches the obligation account at the address.
+func FetchObligation(ctx context.Context, rpcCli *rpc.Client, address ag_solanago.PublicKey) (*Obligation, error) {
+	data, err := getAccountData(ctx, rpcCli, address, "obligation")
+	if err != nil {
+		return nil, err
+	}
+	return DecodeObligation(data)
+}
+
+// FetchObligationReserves fetches the reserves of the deposits and borrows
+// of the obligation, by address, for Obligation.Refresh.
+func FetchObligationReserves(ctx context.Context, rpcCli *rpc.Client, obligation *Obligation) (map[ag_solanago.PublicKey]*Reserve, error) {
+	addresses := obligation.Reserves()
+	reserves := make(map[ag_solanago.PublicKey]*Reserve, len(addresses))
+	if len(addresses) == 0 {
+		return reserves, nil
+	}
+	resp, err := rpcCli.GetMultipleAccounts(ctx, addresses...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get reserve accounts: %w", err)
+	}
+	for i, account := range resp.Value {
+		if account == nil {
+			return nil, fmt.Errorf("reserve %s not found", addresses[i])
+		}
+		if !account.Owner.Equals(ProgramID) {
+			return nil, fmt.Errorf("account %s is not owned by the token lending program", addresses[i])
+		}
+		reserve, err := DecodeReserve(account.Data.GetBinary())
+		if err != nil {
+			return nil, err
+		}
+		reserves[addresses[i]] = reserve
+	}
+	return reserves, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenlending
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ag_binary "github.com/gagliardetto/binary"
+)
+
+// WAD is the scale of the decimals and the rates: one is WAD.
+const WAD = 1_000_000_000_000_000_000
+
+// Decimal is a fixed point number with 18 decimals, stored as the u128 of
+// its value scaled by WAD; the program uses decimals for the amounts with
+// accrued interest, the cumulative borrow rates, the prices and the values.
+type Decimal struct {
+	Lo uint64
+	Hi uint64
+}
+
+// NewDecimal returns the decimal of an integer value.
+func NewDecimal(value uint64) Decimal {
+	return decimalFromScaled(new(big.Int).Mul(u128(value), wad))
+}
+
+// NewDecimalFromScaled returns the decimal of a value scaled by WAD.
+func NewDecimalFromScaled(scaled *big.Int) (Decimal, error) {
+	if scaled.Sign() < 0 || scaled.Cmp(maxU128) > 0 {
+		return Decimal{}, ErrMathOverflow
+	}
+	return decimalFromScaled(scaled), nil
+}
+
+// decimalFromScaled aborts if the scaled value doesn't fit an u128.
+func decimalFromScaled(scaled *big.Int) Decimal {
+	checkBits(scaled, 128)
+	return Decimal{
+		Lo: new(big.Int).And(scaled, maxU64).Uint64(),
+		Hi: new(big.Int).Rsh(scaled, 64).Uint64(),
+	}
+}
+
+// Scaled returns the value of the decimal scaled by WAD.
+func (d Decimal) Scaled() *big.Int {
+	return scaled(d.Lo, d.Hi)
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Lo == 0 && d.Hi == 0
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Scaled().Cmp(other.Scaled())
+}
+
+// Floor returns the value of the decimal rounded down.
+func (d Decimal) Floor() (uint64, error) {
+	return toU64(new(big.Int).Quo(d.Scaled(), wad))
+}
+
+// Ceil returns the value of the decimal rounded up.
+func (d Decimal) Ceil() (uint64, error) {
+	return toU64(new(big.Int).Quo(new(big.Int).Add(d.Scaled(), new(big.Int).Sub(wad, big.NewInt(1))), wad))
+}
+
+// Round returns the value of the decimal rounded half up.
+func (d Decimal) Round() (uint64, error) {
+	return toU64(new(big.Int).Quo(new(big.Int).Add(d.Scaled(), halfWad), wad))
+}
+
+// Float64 returns the nearest float of the decimal.
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.Scaled(), wad).Float64()
+	return f
+}
+
+func (d Decimal) String() string {
+	return formatScaled(d.Scaled())
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+func (d Decimal) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if err := encoder.WriteUint64(d.Lo, ag_binary.LE); err != nil {
+		return err
+	}
+	return encoder.WriteUint64(d.Hi, ag_binary.LE)
+}
+
+func (d *Decimal) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	if d.Lo, err = decoder.ReadUint64(ag_binary.LE); err != nil {
+		return err
+	}
+	d.Hi, err = decoder.ReadUint64(ag_binary.LE)
+	return err
+}
+
+// Rate is a fixed point number with 18 decimals, like Decimal, for the
+// ratios: the utilization and borrow rates, and the percentages of the
+// configuration of the reserves.
+type Rate struct {
+	Lo uint64
+	Hi uint64
+}
+
+// RateFromPercent returns the rate of a percentage.
+func RateFromPercent(percent uint8) Rate {
+	return rateFromScaled(percentRate(percent))
+}
+
+// rateFromScaled aborts if the scaled value doesn't fit an u128.
+func rateFromScaled(scaled *big.Int) Rate {
+	d := decimalFromScaled(scaled)
+	return Rate{Lo: d.Lo, Hi: d.Hi}
+}
+
+// Scaled returns the value of the rate scaled by WAD.
+func (r Rate) Scaled() *big.Int {
+	return scaled(r.Lo, r.Hi)
+}
+
+func (r Rate) Cmp(other Rate) int {
+	return r.Scaled().Cmp(other.Scaled())
+}
+
+// Float64 returns the nearest float of the rate.
+func (r Rate) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(r.Scaled(), wad).Float64()
+	return f
+}
+
+func (r Rate) String() string {
+	return formatScaled(r.Scaled())
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + r.String() + `"`), nil
+}
+
+func scaled(lo, hi uint64) *big.Int {
+	v := new(big.Int).Lsh(u128(hi), 64)
+	return v.Or(v, u128(lo))
+}
+
+// formatScaled formats a value scaled by WAD, without trailing zeros.
+func formatScaled(v *big.Int) string {
+	s := fmt.Sprintf("%019s", v.String())
+	integer, fraction := s[:len(s)-18], strings.TrimRight(s[len(s)-18:], "0")
+	if fraction == "" {
+		return integer
+	}
+	return integer + "." + fraction
+}
+
+// LastUpdate is the slot of the last refresh of a reserve or an obligation.
+type LastUpdate struct {
+	Slot uint64
+
+	// Whether an instruction changed the account since the refresh; most
+	// instructions require a refresh in the same slot.
+	Stale bool
+}
+
+// ReserveConfig is the configuration of a reserve; the rates are
+// percentages.
+type ReserveConfig struct {
+	// The utilization rate of the optimal borrow rate.
+	OptimalUtilizationRate uint8
+
+	// The share of the value of the collateral that can be borrowed.
+	LoanToValueRatio uint8
+
+	// The bonus of the liquidators, on the value of the collateral.
+	LiquidationBonus uint8
+
+	// The loan to value ratio over which obligations can be liquidated.
+	LiquidationThreshold uint8
+
+	// The borrow rates at no, optimal and full utilization.
+	MinBorrowRate     uint8
+	OptimalBorrowRate uint8
+	MaxBorrowRate     uint8
+
+	Fees ReserveFees
+}
+
+// ReserveFees are the fees of a reserve; the fees are scaled by WAD.
+type ReserveFees struct {
+	// The fee on the borrowed amounts; 0.01 * WAD is 1%.
+	BorrowFeeWad uint64
+
+	// The fee on the flash loans.
+	FlashLoanFeeWad uint64
+
+	// The share of the fees that goes to the host fee receiver.
+	HostFeePercentage uint8
+}
+
+func (config ReserveConfig) validate() error {
+	switch {
+	case config.OptimalUtilizationRate > 100:
+		return errors.New("optimal utilization rate must be in range [0, 100]")
+	case config.LoanToValueRatio >= 100:
+		return errors.New("loan to value ratio must be in range [0, 100)")
+	case config.LiquidationBonus > 100:
+		return errors.New("liquidation bonus must be in range [0, 100]")
+	case config.LiquidationThreshold <= config.LoanToValueRatio || config.LiquidationThreshold > 100:
+		return errors.New("liquidation threshold must be in range (LTV, 100]")
+	case config.OptimalBorrowRate < config.MinBorrowRate:
+		return errors.New("optimal borrow rate must be >= min borrow rate")
+	case config.OptimalBorrowRate > config.MaxBorrowRate:
+		return errors.New("optimal borrow rate must be <= max borrow rate")
+	case config.Fees.BorrowFeeWad >= WAD:
+		return errors.New("borrow fee must be in range [0, 1_000_000_000_000_000_000)")
+	case config.Fees.FlashLoanFeeWad >= WAD:
+		return errors.New("flash loan fee must be in range [0, 1_000_000_000_000_000_000)")
+	case config.Fees.HostFeePercentage > 100:
+		return errors.New("host fee percentage must be in range [0, 100]")
+	}
+	return nil
+}
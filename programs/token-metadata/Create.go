@@ -0,0 +1,437 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Creates the metadata and, for NFTs, the master edition of a mint,
+// initializing the mint if it doesn't exist.
+type Create struct {
+	AssetData *AssetData
+	// The decimals of the mint it initializes; zero for NFTs.
+	Decimals *uint8 `bin:"optional"`
+	// The print supply of the master edition of NFTs.
+	PrintSupply *PrintSupply `bin:"optional"`
+
+	// [0] = [WRITE] metadata
+	// ··········· The metadata account, at FindMetadataAddress(mint).
+	//
+	// [1] = [WRITE] masterEdition
+	// ··········· The master edition account, at FindMasterEditionAddress(mint), for NFTs.
+	//
+	// [2] = [WRITE] mint
+	// ··········· The mint; it signs when it doesn't exist yet.
+	//
+	// [3] = [SIGNER] authority
+	// ··········· The mint authority of the mint.
+	//
+	// [4] = [WRITE, SIGNER] payer
+	// ··········· The payer.
+	//
+	// [5] = [] updateAuthority
+	// ··········· The update authority of the metadata.
+	//
+	// [6] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [7] = [] sysvarInstructions
+	// ··········· The instructions sysvar.
+	//
+	// [8] = [] splTokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewCreateInstructionBuilder creates a new `Create` instruction builder.
+func NewCreateInstructionBuilder() *Create {
+	nd := &Create{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 9),
+	}
+	nd.AccountMetaSlice[6] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.SysVarInstructionsPubkey)
+	nd.AccountMetaSlice[8] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetAssetData sets the "assetData" parameter.
+func (inst *Create) SetAssetData(assetData AssetData) *Create {
+	inst.AssetData = &assetData
+	return inst
+}
+
+// SetDecimals sets the "decimals" parameter.
+// The decimals of the mint it initializes; zero for NFTs.
+func (inst *Create) SetDecimals(decimals uint8) *Create {
+	inst.Decimals = &decimals
+	return inst
+}
+
+// SetPrintSupply sets the "printSupply" parameter.
+// The print supply of the master edition of NFTs.
+func (inst *Create) SetPrintSupply(printSupply PrintSupply) *Create {
+	inst.PrintSupply = &printSupply
+	return inst
+}
+
+// SetMetadataAccount sets the "metadata" account.
+// The metadata account, at FindMetadataAddress(mint).
+func (inst *Create) SetMetadataAccount(metadata ag_solanago.PublicKey) *Create {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(metadata).WRITE()
+	return inst
+}
+
+// GetMetadataAccount gets the "metadata" account.
+// The metadata account, at FindMetadataAddress(mint).
+func (inst *Create) GetMetadataAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetMasterEditionAccount sets the "masterEdition" account.
+// The master edition account, at FindMasterEditionAddress(mint), for NFTs.
+func (inst *Create) SetMasterEditionAccount(masterEdition ag_solanago.PublicKey) *Create {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(masterEdition).WRITE()
+	return inst
+}
+
+// GetMasterEditionAccount gets the "masterEdition" account.
+// The master edition account, at FindMasterEditionAddress(mint), for NFTs.
+func (inst *Create) GetMasterEditionAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetMintAccount sets the "mint" account.
+// The mint; it signs when it doesn't exist yet.
+func (inst *Create) SetMintAccount(mint ag_solanago.PublicKey, isSigner bool) *Create {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(mint).WRITE()
+	if isSigner {
+		inst.AccountMetaSlice[2].SIGNER()
+	}
+	return inst
+}
+
+// GetMintAccount gets the "mint" account.
+// The mint; it signs when it doesn't exist yet.
+func (inst *Create) GetMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+// The mint authority of the mint.
+func (inst *Create) SetAuthorityAccount(authority ag_solanago.PublicKey) *Create {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(authority).SIGNER()
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+// The mint authority of the mint.
+func (inst *Create) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetPayerAccount sets the "payer" account.
+// The payer.
+func (inst *Create) SetPayerAccount(payer ag_solanago.PublicKey) *Create {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(payer).WRITE().SIGNER()
+	return inst
+}
+
+// GetPayerAccount gets the "payer" account.
+// The payer.
+func (inst *Create) GetPayerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetUpdateAuthorityAccount sets the "updateAuthority" account.
+// The update authority of the metadata.
+func (inst *Create) SetUpdateAuthorityAccount(updateAuthority ag_solanago.PublicKey, isSigner bool) *Create {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(updateAuthority)
+	if isSigner {
+		inst.AccountMetaSlice[5].SIGNER()
+	}
+	return inst
+}
+
+// GetUpdateAuthorityAccount gets the "updateAuthority" account.
+// The update authority of the metadata.
+func (inst *Create) GetUpdateAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *Create) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *Create {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *Create) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetSysvarInstructionsAccount sets the "sysvarInstructions" account.
+// The instructions sysvar.
+func (inst *Create) SetSysvarInstructionsAccount(sysvarInstructions ag_solanago.PublicKey) *Create {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(sysvarInstructions)
+	return inst
+}
+
+// GetSysvarInstructionsAccount gets the "sysvarInstructions" account.
+// The instructions sysvar.
+func (inst *Create) GetSysvarInstructionsAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetSplTokenProgramAccount sets the "splTokenProgram" account.
+// The token program.
+func (inst *Create) SetSplTokenProgramAccount(splTokenProgram ag_solanago.PublicKey) *Create {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(splTokenProgram)
+	return inst
+}
+
+// GetSplTokenProgramAccount gets the "splTokenProgram" account.
+// The token program.
+func (inst *Create) GetSplTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// GetAccounts returns the accounts, with the program ID in place of the
+// optional accounts that are not set.
+func (inst Create) GetAccounts() []*ag_solanago.AccountMeta {
+	accounts := make([]*ag_solanago.AccountMeta, len(inst.AccountMetaSlice))
+	for i, account := range inst.AccountMetaSlice {
+		accounts[i] = optionalAccount(account)
+	}
+	return accounts
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Create) AccountNames() []string {
+	return []string{"metadata", "masterEdition", "mint", "authority", "payer", "updateAuthority", "systemProgram", "sysvarInstructions", "splTokenProgram"}
+}
+
+func (inst Create) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_Create),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Create) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Create) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.AssetData == nil {
+			return errors.New("AssetData parameter is not set")
+		}
+		if err := inst.AssetData.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Metadata is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Mint is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Payer is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.UpdateAuthority is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.SysvarInstructions is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.SplTokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Create) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Create")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("  AssetData", inst.AssetData))
+						paramsBranch.Child(ag_format.Param("   Decimals", inst.Decimals))
+						paramsBranch.Child(ag_format.Param("PrintSupply", inst.PrintSupply))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("          metadata", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("     masterEdition", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("              mint", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("         authority", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("             payer", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("   updateAuthority", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("     systemProgram", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("sysvarInstructions", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("   splTokenProgram", inst.AccountMetaSlice.Get(8)))
+					})
+				})
+		})
+}
+
+func (obj Create) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize the version of the arguments, V1:
+	err = encoder.WriteUint8(0)
+	if err != nil {
+		return err
+	}
+	// Serialize `AssetData` param:
+	err = encoder.Encode(obj.AssetData)
+	if err != nil {
+		return err
+	}
+	// Serialize `Decimals` param (optional):
+	{
+		if obj.Decimals == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.Decimals)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Serialize `PrintSupply` param (optional):
+	{
+		if obj.PrintSupply == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.PrintSupply)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+func (obj *Create) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize the version of the arguments:
+	{
+		version, err := decoder.ReadUint8()
+		if err != nil {
+			return err
+		}
+		if version != 0 {
+			return fmt.Errorf("unsupported arguments version: %v", version)
+		}
+	}
+	// Deserialize `AssetData`:
+	err = decoder.Decode(&obj.AssetData)
+	if err != nil {
+		return err
+	}
+	// Deserialize `Decimals` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.Decimals)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Deserialize `PrintSupply` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.PrintSupply)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewCreateInstruction declares a new Create instruction with the provided parameters and accounts.
+func NewCreateInstruction(
+	// Parameters:
+	assetData AssetData,
+	// Accounts:
+	metadata ag_solanago.PublicKey,
+	mint ag_solanago.PublicKey,
+	isMintSigner bool,
+	authority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+	updateAuthority ag_solanago.PublicKey,
+	isUpdateAuthoritySigner bool,
+) *Create {
+	return NewCreateInstructionBuilder().
+		SetAssetData(assetData).
+		SetMetadataAccount(metadata).
+		SetMintAccount(mint, isMintSigner).
+		SetAuthorityAccount(authority).
+		SetPayerAccount(payer).
+		SetUpdateAuthorityAccount(updateAuthority, isUpdateAuthoritySigner)
+}
@@ -0,0 +1,315 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Creates the master edition of a NFT; the mint and freeze authorities of
+// the mint are transferred to the master edition.
+type CreateMasterEditionV3 struct {
+	// The maximum number of printed editions; unlimited if unset.
+	MaxSupply *uint64 `bin:"optional"`
+
+	// [0] = [WRITE] edition
+	// ··········· The master edition account, at FindMasterEditionAddress(mint).
+	//
+	// [1] = [WRITE] mint
+	// ··········· The mint, with a supply of one.
+	//
+	// [2] = [SIGNER] updateAuthority
+	// ··········· The update authority of the metadata.
+	//
+	// [3] = [SIGNER] mintAuthority
+	// ··········· The mint authority of the mint.
+	//
+	// [4] = [WRITE, SIGNER] payer
+	// ··········· The payer of the master edition account.
+	//
+	// [5] = [WRITE] metadata
+	// ··········· The metadata account.
+	//
+	// [6] = [] tokenProgram
+	// ··········· The token program.
+	//
+	// [7] = [] systemProgram
+	// ··········· The system program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewCreateMasterEditionV3InstructionBuilder creates a new `CreateMasterEditionV3` instruction builder.
+func NewCreateMasterEditionV3InstructionBuilder() *CreateMasterEditionV3 {
+	nd := &CreateMasterEditionV3{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 8),
+	}
+	nd.AccountMetaSlice[6] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	return nd
+}
+
+// SetMaxSupply sets the "maxSupply" parameter.
+// The maximum number of printed editions; unlimited if unset.
+func (inst *CreateMasterEditionV3) SetMaxSupply(maxSupply uint64) *CreateMasterEditionV3 {
+	inst.MaxSupply = &maxSupply
+	return inst
+}
+
+// SetEditionAccount sets the "edition" account.
+// The master edition account, at FindMasterEditionAddress(mint).
+func (inst *CreateMasterEditionV3) SetEditionAccount(edition ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(edition).WRITE()
+	return inst
+}
+
+// GetEditionAccount gets the "edition" account.
+// The master edition account, at FindMasterEditionAddress(mint).
+func (inst *CreateMasterEditionV3) GetEditionAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetMintAccount sets the "mint" account.
+// The mint, with a supply of one.
+func (inst *CreateMasterEditionV3) SetMintAccount(mint ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(mint).WRITE()
+	return inst
+}
+
+// GetMintAccount gets the "mint" account.
+// The mint, with a supply of one.
+func (inst *CreateMasterEditionV3) GetMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetUpdateAuthorityAccount sets the "updateAuthority" account.
+// The update authority of the metadata.
+func (inst *CreateMasterEditionV3) SetUpdateAuthorityAccount(updateAuthority ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(updateAuthority).SIGNER()
+	return inst
+}
+
+// GetUpdateAuthorityAccount gets the "updateAuthority" account.
+// The update authority of the metadata.
+func (inst *CreateMasterEditionV3) GetUpdateAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetMintAuthorityAccount sets the "mintAuthority" account.
+// The mint authority of the mint.
+func (inst *CreateMasterEditionV3) SetMintAuthorityAccount(mintAuthority ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(mintAuthority).SIGNER()
+	return inst
+}
+
+// GetMintAuthorityAccount gets the "mintAuthority" account.
+// The mint authority of the mint.
+func (inst *CreateMasterEditionV3) GetMintAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetPayerAccount sets the "payer" account.
+// The payer of the master edition account.
+func (inst *CreateMasterEditionV3) SetPayerAccount(payer ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(payer).WRITE().SIGNER()
+	return inst
+}
+
+// GetPayerAccount gets the "payer" account.
+// The payer of the master edition account.
+func (inst *CreateMasterEditionV3) GetPayerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetMetadataAccount sets the "metadata" account.
+// The metadata account.
+func (inst *CreateMasterEditionV3) SetMetadataAccount(metadata ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(metadata).WRITE()
+	return inst
+}
+
+// GetMetadataAccount gets the "metadata" account.
+// The metadata account.
+func (inst *CreateMasterEditionV3) GetMetadataAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *CreateMasterEditionV3) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *CreateMasterEditionV3) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *CreateMasterEditionV3) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *CreateMasterEditionV3 {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *CreateMasterEditionV3) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst CreateMasterEditionV3) AccountNames() []string {
+	return []string{"edition", "mint", "updateAuthority", "mintAuthority", "payer", "metadata", "tokenProgram", "systemProgram"}
+}
+
+func (inst CreateMasterEditionV3) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_CreateMasterEditionV3),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst CreateMasterEditionV3) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *CreateMasterEditionV3) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Edition is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Mint is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.UpdateAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.MintAuthority is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Payer is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.Metadata is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *CreateMasterEditionV3) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("CreateMasterEditionV3")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("MaxSupply", inst.MaxSupply))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        edition", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("           mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("updateAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("  mintAuthority", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("          payer", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("       metadata", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("   tokenProgram", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("  systemProgram", inst.AccountMetaSlice.Get(7)))
+					})
+				})
+		})
+}
+
+func (obj CreateMasterEditionV3) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `MaxSupply` param (optional):
+	{
+		if obj.MaxSupply == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.MaxSupply)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+func (obj *CreateMasterEditionV3) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `MaxSupply` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.MaxSupply)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewCreateMasterEditionV3Instruction declares a new CreateMasterEditionV3 instruction with the provided parameters and accounts.
+func NewCreateMasterEditionV3Instruction(
+	// Accounts:
+	edition ag_solanago.PublicKey,
+	mint ag_solanago.PublicKey,
+	updateAuthority ag_solanago.PublicKey,
+	mintAuthority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+	metadata ag_solanago.PublicKey,
+) *CreateMasterEditionV3 {
+	return NewCreateMasterEditionV3InstructionBuilder().
+		SetEditionAccount(edition).
+		SetMintAccount(mint).
+		SetUpdateAuthorityAccount(updateAuthority).
+		SetMintAuthorityAccount(mintAuthority).
+		SetPayerAccount(payer).
+		SetMetadataAccount(metadata)
+}
@@ -0,0 +1,329 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Creates the metadata of a mint.
+type CreateMetadataAccountV3 struct {
+	Data      *DataV2
+	IsMutable *bool
+	// Set to make the metadata the one of a collection NFT.
+	CollectionDetails *CollectionDetails `bin:"optional"`
+
+	// [0] = [WRITE] metadata
+	// ··········· The metadata account, at FindMetadataAddress(mint).
+	//
+	// [1] = [] mint
+	// ··········· The mint.
+	//
+	// [2] = [SIGNER] mintAuthority
+	// ··········· The mint authority of the mint.
+	//
+	// [3] = [WRITE, SIGNER] payer
+	// ··········· The payer of the metadata account.
+	//
+	// [4] = [] updateAuthority
+	// ··········· The update authority of the metadata; it signs to verify itself as creator.
+	//
+	// [5] = [] systemProgram
+	// ··········· The system program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewCreateMetadataAccountV3InstructionBuilder creates a new `CreateMetadataAccountV3` instruction builder.
+func NewCreateMetadataAccountV3InstructionBuilder() *CreateMetadataAccountV3 {
+	nd := &CreateMetadataAccountV3{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 6),
+	}
+	nd.AccountMetaSlice[5] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	return nd
+}
+
+// SetData sets the "data" parameter.
+func (inst *CreateMetadataAccountV3) SetData(data DataV2) *CreateMetadataAccountV3 {
+	inst.Data = &data
+	return inst
+}
+
+// SetIsMutable sets the "isMutable" parameter.
+func (inst *CreateMetadataAccountV3) SetIsMutable(isMutable bool) *CreateMetadataAccountV3 {
+	inst.IsMutable = &isMutable
+	return inst
+}
+
+// SetCollectionDetails sets the "collectionDetails" parameter.
+// Set to make the metadata the one of a collection NFT.
+func (inst *CreateMetadataAccountV3) SetCollectionDetails(collectionDetails CollectionDetails) *CreateMetadataAccountV3 {
+	inst.CollectionDetails = &collectionDetails
+	return inst
+}
+
+// SetMetadataAccount sets the "metadata" account.
+// The metadata account, at FindMetadataAddress(mint).
+func (inst *CreateMetadataAccountV3) SetMetadataAccount(metadata ag_solanago.PublicKey) *CreateMetadataAccountV3 {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(metadata).WRITE()
+	return inst
+}
+
+// GetMetadataAccount gets the "metadata" account.
+// The metadata account, at FindMetadataAddress(mint).
+func (inst *CreateMetadataAccountV3) GetMetadataAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetMintAccount sets the "mint" account.
+// The mint.
+func (inst *CreateMetadataAccountV3) SetMintAccount(mint ag_solanago.PublicKey) *CreateMetadataAccountV3 {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(mint)
+	return inst
+}
+
+// GetMintAccount gets the "mint" account.
+// The mint.
+func (inst *CreateMetadataAccountV3) GetMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetMintAuthorityAccount sets the "mintAuthority" account.
+// The mint authority of the mint.
+func (inst *CreateMetadataAccountV3) SetMintAuthorityAccount(mintAuthority ag_solanago.PublicKey) *CreateMetadataAccountV3 {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(mintAuthority).SIGNER()
+	return inst
+}
+
+// GetMintAuthorityAccount gets the "mintAuthority" account.
+// The mint authority of the mint.
+func (inst *CreateMetadataAccountV3) GetMintAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetPayerAccount sets the "payer" account.
+// The payer of the metadata account.
+func (inst *CreateMetadataAccountV3) SetPayerAccount(payer ag_solanago.PublicKey) *CreateMetadataAccountV3 {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(payer).WRITE().SIGNER()
+	return inst
+}
+
+// GetPayerAccount gets the "payer" account.
+// The payer of the metadata account.
+func (inst *CreateMetadataAccountV3) GetPayerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetUpdateAuthorityAccount sets the "updateAuthority" account.
+// The update authority of the metadata; it signs to verify itself as creator.
+func (inst *CreateMetadataAccountV3) SetUpdateAuthorityAccount(updateAuthority ag_solanago.PublicKey, isSigner bool) *CreateMetadataAccountV3 {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(updateAuthority)
+	if isSigner {
+		inst.AccountMetaSlice[4].SIGNER()
+	}
+	return inst
+}
+
+// GetUpdateAuthorityAccount gets the "updateAuthority" account.
+// The update authority of the metadata; it signs to verify itself as creator.
+func (inst *CreateMetadataAccountV3) GetUpdateAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *CreateMetadataAccountV3) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *CreateMetadataAccountV3 {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *CreateMetadataAccountV3) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst CreateMetadataAccountV3) AccountNames() []string {
+	return []string{"metadata", "mint", "mintAuthority", "payer", "updateAuthority", "systemProgram"}
+}
+
+func (inst CreateMetadataAccountV3) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_CreateMetadataAccountV3),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst CreateMetadataAccountV3) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *CreateMetadataAccountV3) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Data == nil {
+			return errors.New("Data parameter is not set")
+		}
+		if inst.IsMutable == nil {
+			return errors.New("IsMutable parameter is not set")
+		}
+		if err := inst.Data.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Metadata is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Mint is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.MintAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.Payer is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.UpdateAuthority is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *CreateMetadataAccountV3) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("CreateMetadataAccountV3")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("             Data", inst.Data))
+						paramsBranch.Child(ag_format.Param("        IsMutable", inst.IsMutable))
+						paramsBranch.Child(ag_format.Param("CollectionDetails", inst.CollectionDetails))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("       metadata", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("           mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("  mintAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("          payer", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("updateAuthority", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("  systemProgram", inst.AccountMetaSlice.Get(5)))
+					})
+				})
+		})
+}
+
+func (obj CreateMetadataAccountV3) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Data` param:
+	err = encoder.Encode(obj.Data)
+	if err != nil {
+		return err
+	}
+	// Serialize `IsMutable` param:
+	err = encoder.Encode(obj.IsMutable)
+	if err != nil {
+		return err
+	}
+	// Serialize `CollectionDetails` param (optional):
+	{
+		if obj.CollectionDetails == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.CollectionDetails)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+func (obj *CreateMetadataAccountV3) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Data`:
+	err = decoder.Decode(&obj.Data)
+	if err != nil {
+		return err
+	}
+	// Deserialize `IsMutable`:
+	err = decoder.Decode(&obj.IsMutable)
+	if err != nil {
+		return err
+	}
+	// Deserialize `CollectionDetails` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.CollectionDetails)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewCreateMetadataAccountV3Instruction declares a new CreateMetadataAccountV3 instruction with the provided parameters and accounts.
+func NewCreateMetadataAccountV3Instruction(
+	// Parameters:
+	data DataV2,
+	isMutable bool,
+	// Accounts:
+	metadata ag_solanago.PublicKey,
+	mint ag_solanago.PublicKey,
+	mintAuthority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+	updateAuthority ag_solanago.PublicKey,
+	isUpdateAuthoritySigner bool,
+) *CreateMetadataAccountV3 {
+	return NewCreateMetadataAccountV3InstructionBuilder().
+		SetData(data).
+		SetIsMutable(isMutable).
+		SetMetadataAccount(metadata).
+		SetMintAccount(mint).
+		SetMintAuthorityAccount(mintAuthority).
+		SetPayerAccount(payer).
+		SetUpdateAuthorityAccount(updateAuthority, isUpdateAuthoritySigner)
+}
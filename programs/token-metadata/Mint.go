@@ -0,0 +1,484 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Mints tokens of a mint with metadata, creating the token account if
+// needed; for programmable NFTs, it also creates the token record.
+type Mint struct {
+	Amount *uint64
+	// The borsh-encoded authorization data passed to the rule set, if any.
+	AuthorizationData []byte
+
+	// [0] = [WRITE] token
+	// ··········· The token account.
+	//
+	// [1] = [] tokenOwner
+	// ··········· The owner of the token account, to create it.
+	//
+	// [2] = [] metadata
+	// ··········· The metadata account.
+	//
+	// [3] = [] masterEdition
+	// ··········· The master edition account, for NFTs.
+	//
+	// [4] = [WRITE] tokenRecord
+	// ··········· The token record, at FindTokenRecordAddress(mint, token), for programmable NFTs.
+	//
+	// [5] = [WRITE] mint
+	// ··········· The mint.
+	//
+	// [6] = [SIGNER] authority
+	// ··········· The update authority of the metadata, for NFTs, or the mint authority.
+	//
+	// [7] = [] delegateRecord
+	// ··········· The metadata delegate record, if the authority is a delegate.
+	//
+	// [8] = [WRITE, SIGNER] payer
+	// ··········· The payer.
+	//
+	// [9] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [10] = [] sysvarInstructions
+	// ··········· The instructions sysvar.
+	//
+	// [11] = [] splTokenProgram
+	// ··········· The token program.
+	//
+	// [12] = [] splAtaProgram
+	// ··········· The associated token account program.
+	//
+	// [13] = [] authorizationRulesProgram
+	// ··········· The token authorization rules program, for programmable NFTs with a rule set.
+	//
+	// [14] = [] authorizationRules
+	// ··········· The rule set of the programmable NFT, if any.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewMintInstructionBuilder creates a new `Mint` instruction builder.
+func NewMintInstructionBuilder() *Mint {
+	nd := &Mint{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 15),
+	}
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	nd.AccountMetaSlice[10] = ag_solanago.Meta(ag_solanago.SysVarInstructionsPubkey)
+	nd.AccountMetaSlice[11] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	nd.AccountMetaSlice[12] = ag_solanago.Meta(ag_solanago.SPLAssociatedTokenAccountProgramID)
+	return nd
+}
+
+// SetAmount sets the "amount" parameter.
+func (inst *Mint) SetAmount(amount uint64) *Mint {
+	inst.Amount = &amount
+	return inst
+}
+
+// SetAuthorizationData sets the "authorizationData" parameter.
+// The authorization data is borsh-encoded.
+func (inst *Mint) SetAuthorizationData(authorizationData []byte) *Mint {
+	inst.AuthorizationData = authorizationData
+	return inst
+}
+
+// SetTokenAccount sets the "token" account.
+// The token account.
+func (inst *Mint) SetTokenAccount(token ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(token).WRITE()
+	return inst
+}
+
+// GetTokenAccount gets the "token" account.
+// The token account.
+func (inst *Mint) GetTokenAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetTokenOwnerAccount sets the "tokenOwner" account.
+// The owner of the token account, to create it.
+func (inst *Mint) SetTokenOwnerAccount(tokenOwner ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(tokenOwner)
+	return inst
+}
+
+// GetTokenOwnerAccount gets the "tokenOwner" account.
+// The owner of the token account, to create it.
+func (inst *Mint) GetTokenOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetMetadataAccount sets the "metadata" account.
+// The metadata account.
+func (inst *Mint) SetMetadataAccount(metadata ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(metadata)
+	return inst
+}
+
+// GetMetadataAccount gets the "metadata" account.
+// The metadata account.
+func (inst *Mint) GetMetadataAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetMasterEditionAccount sets the "masterEdition" account.
+// The master edition account, for NFTs.
+func (inst *Mint) SetMasterEditionAccount(masterEdition ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(masterEdition)
+	return inst
+}
+
+// GetMasterEditionAccount gets the "masterEdition" account.
+// The master edition account, for NFTs.
+func (inst *Mint) GetMasterEditionAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetTokenRecordAccount sets the "tokenRecord" account.
+// The token record, at FindTokenRecordAddress(mint, token), for programmable NFTs.
+func (inst *Mint) SetTokenRecordAccount(tokenRecord ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(tokenRecord).WRITE()
+	return inst
+}
+
+// GetTokenRecordAccount gets the "tokenRecord" account.
+// The token record, at FindTokenRecordAddress(mint, token), for programmable NFTs.
+func (inst *Mint) GetTokenRecordAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetMintAccount sets the "mint" account.
+// The mint.
+func (inst *Mint) SetMintAccount(mint ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(mint).WRITE()
+	return inst
+}
+
+// GetMintAccount gets the "mint" account.
+// The mint.
+func (inst *Mint) GetMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+// The update authority of the metadata, for NFTs, or the mint authority.
+func (inst *Mint) SetAuthorityAccount(authority ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(authority).SIGNER()
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+// The update authority of the metadata, for NFTs, or the mint authority.
+func (inst *Mint) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetDelegateRecordAccount sets the "delegateRecord" account.
+// The metadata delegate record, if the authority is a delegate.
+func (inst *Mint) SetDelegateRecordAccount(delegateRecord ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(delegateRecord)
+	return inst
+}
+
+// GetDelegateRecordAccount gets the "delegateRecord" account.
+// The metadata delegate record, if the authority is a delegate.
+func (inst *Mint) GetDelegateRecordAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetPayerAccount sets the "payer" account.
+// The payer.
+func (inst *Mint) SetPayerAccount(payer ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(payer).WRITE().SIGNER()
+	return inst
+}
+
+// GetPayerAccount gets the "payer" account.
+// The payer.
+func (inst *Mint) GetPayerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *Mint) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *Mint) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetSysvarInstructionsAccount sets the "sysvarInstructions" account.
+// The instructions sysvar.
+func (inst *Mint) SetSysvarInstructionsAccount(sysvarInstructions ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(sysvarInstructions)
+	return inst
+}
+
+// GetSysvarInstructionsAccount gets the "sysvarInstructions" account.
+// The instructions sysvar.
+func (inst *Mint) GetSysvarInstructionsAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// SetSplTokenProgramAccount sets the "splTokenProgram" account.
+// The token program.
+func (inst *Mint) SetSplTokenProgramAccount(splTokenProgram ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[11] = ag_solanago.Meta(splTokenProgram)
+	return inst
+}
+
+// GetSplTokenProgramAccount gets the "splTokenProgram" account.
+// The token program.
+func (inst *Mint) GetSplTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(11)
+}
+
+// SetSplAtaProgramAccount sets the "splAtaProgram" account.
+// The associated token account program.
+func (inst *Mint) SetSplAtaProgramAccount(splAtaProgram ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[12] = ag_solanago.Meta(splAtaProgram)
+	return inst
+}
+
+// GetSplAtaProgramAccount gets the "splAtaProgram" account.
+// The associated token account program.
+func (inst *Mint) GetSplAtaProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(12)
+}
+
+// SetAuthorizationRulesProgramAccount sets the "authorizationRulesProgram" account.
+// The token authorization rules program, for programmable NFTs with a rule set.
+func (inst *Mint) SetAuthorizationRulesProgramAccount(authorizationRulesProgram ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[13] = ag_solanago.Meta(authorizationRulesProgram)
+	return inst
+}
+
+// GetAuthorizationRulesProgramAccount gets the "authorizationRulesProgram" account.
+// The token authorization rules program, for programmable NFTs with a rule set.
+func (inst *Mint) GetAuthorizationRulesProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(13)
+}
+
+// SetAuthorizationRulesAccount sets the "authorizationRules" account.
+// The rule set of the programmable NFT, if any.
+func (inst *Mint) SetAuthorizationRulesAccount(authorizationRules ag_solanago.PublicKey) *Mint {
+	inst.AccountMetaSlice[14] = ag_solanago.Meta(authorizationRules)
+	return inst
+}
+
+// GetAuthorizationRulesAccount gets the "authorizationRules" account.
+// The rule set of the programmable NFT, if any.
+func (inst *Mint) GetAuthorizationRulesAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(14)
+}
+
+// GetAccounts returns the accounts, with the program ID in place of the
+// optional accounts that are not set.
+func (inst Mint) GetAccounts() []*ag_solanago.AccountMeta {
+	accounts := make([]*ag_solanago.AccountMeta, len(inst.AccountMetaSlice))
+	for i, account := range inst.AccountMetaSlice {
+		accounts[i] = optionalAccount(account)
+	}
+	return accounts
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Mint) AccountNames() []string {
+	return []string{"token", "tokenOwner", "metadata", "masterEdition", "tokenRecord", "mint", "authority", "delegateRecord", "payer", "systemProgram", "sysvarInstructions", "splTokenProgram", "splAtaProgram", "authorizationRulesProgram", "authorizationRules"}
+}
+
+func (inst Mint) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_Mint),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Mint) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Mint) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Amount == nil {
+			return errors.New("Amount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Token is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Metadata is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.Mint is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Payer is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.SysvarInstructions is not set")
+		}
+		if inst.AccountMetaSlice[11] == nil {
+			return errors.New("accounts.SplTokenProgram is not set")
+		}
+		if inst.AccountMetaSlice[12] == nil {
+			return errors.New("accounts.SplAtaProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Mint) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Mint")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("           Amount", inst.Amount))
+						paramsBranch.Child(ag_format.Param("AuthorizationData", inst.AuthorizationData))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("                    token", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("               tokenOwner", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("                 metadata", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("            masterEdition", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("              tokenRecord", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("                     mint", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("                authority", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("           delegateRecord", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("                    payer", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("            systemProgram", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("       sysvarInstructions", inst.AccountMetaSlice.Get(10)))
+						accountsBranch.Child(ag_format.Meta("          splTokenProgram", inst.AccountMetaSlice.Get(11)))
+						accountsBranch.Child(ag_format.Meta("            splAtaProgram", inst.AccountMetaSlice.Get(12)))
+						accountsBranch.Child(ag_format.Meta("authorizationRulesProgram", inst.AccountMetaSlice.Get(13)))
+						accountsBranch.Child(ag_format.Meta("       authorizationRules", inst.AccountMetaSlice.Get(14)))
+					})
+				})
+		})
+}
+
+func (obj Mint) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize the version of the arguments, V1:
+	err = encoder.WriteUint8(0)
+	if err != nil {
+		return err
+	}
+	// Serialize `Amount` param:
+	err = encoder.Encode(obj.Amount)
+	if err != nil {
+		return err
+	}
+	// Serialize `AuthorizationData` param (optional, already encoded):
+	{
+		err = encoder.WriteBool(obj.AuthorizationData != nil)
+		if err != nil {
+			return err
+		}
+		err = encoder.WriteBytes(obj.AuthorizationData, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (obj *Mint) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize the version of the arguments:
+	{
+		version, err := decoder.ReadUint8()
+		if err != nil {
+			return err
+		}
+		if version != 0 {
+			return fmt.Errorf("unsupported arguments version: %v", version)
+		}
+	}
+	// Deserialize `Amount`:
+	err = decoder.Decode(&obj.Amount)
+	if err != nil {
+		return err
+	}
+	// Deserialize `AuthorizationData` (optional, kept encoded):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			obj.AuthorizationData, err = decoder.ReadNBytes(decoder.Remaining())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewMintInstruction declares a new Mint instruction with the provided parameters and accounts.
+func NewMintInstruction(
+	// Parameters:
+	amount uint64,
+	// Accounts:
+	token ag_solanago.PublicKey,
+	metadata ag_solanago.PublicKey,
+	mint ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+) *Mint {
+	return NewMintInstructionBuilder().
+		SetAmount(amount).
+		SetTokenAccount(token).
+		SetMetadataAccount(metadata).
+		SetMintAccount(mint).
+		SetAuthorityAccount(authority).
+		SetPayerAccount(payer)
+}
@@ -0,0 +1,533 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Transfers tokens of a mint with metadata, creating the destination token
+// account if needed; for programmable NFTs, it also updates the token records.
+type Transfer struct {
+	Amount *uint64
+	// The borsh-encoded authorization data passed to the rule set, if any.
+	AuthorizationData []byte
+
+	// [0] = [WRITE] token
+	// ··········· The source token account.
+	//
+	// [1] = [] tokenOwner
+	// ··········· The owner of the source token account.
+	//
+	// [2] = [WRITE] destination
+	// ··········· The destination token account.
+	//
+	// [3] = [] destinationOwner
+	// ··········· The owner of the destination token account.
+	//
+	// [4] = [] mint
+	// ··········· The mint.
+	//
+	// [5] = [WRITE] metadata
+	// ··········· The metadata account.
+	//
+	// [6] = [] edition
+	// ··········· The master edition or edition account, for NFTs.
+	//
+	// [7] = [WRITE] ownerTokenRecord
+	// ··········· The token record of the source token account, for programmable NFTs.
+	//
+	// [8] = [WRITE] destinationTokenRecord
+	// ··········· The token record of the destination token account, for programmable NFTs.
+	//
+	// [9] = [SIGNER] authority
+	// ··········· The owner or the delegate of the source token account.
+	//
+	// [10] = [WRITE, SIGNER] payer
+	// ··········· The payer.
+	//
+	// [11] = [] systemProgram
+	// ··········· The system program.
+	//
+	// [12] = [] sysvarInstructions
+	// ··········· The instructions sysvar.
+	//
+	// [13] = [] splTokenProgram
+	// ··········· The token program.
+	//
+	// [14] = [] splAtaProgram
+	// ··········· The associated token account program.
+	//
+	// [15] = [] authorizationRulesProgram
+	// ··········· The token authorization rules program, for programmable NFTs with a rule set.
+	//
+	// [16] = [] authorizationRules
+	// ··········· The rule set of the programmable NFT, if any.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewTransferInstructionBuilder creates a new `Transfer` instruction builder.
+func NewTransferInstructionBuilder() *Transfer {
+	nd := &Transfer{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 17),
+	}
+	nd.AccountMetaSlice[11] = ag_solanago.Meta(ag_solanago.SystemProgramID)
+	nd.AccountMetaSlice[12] = ag_solanago.Meta(ag_solanago.SysVarInstructionsPubkey)
+	nd.AccountMetaSlice[13] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	nd.AccountMetaSlice[14] = ag_solanago.Meta(ag_solanago.SPLAssociatedTokenAccountProgramID)
+	return nd
+}
+
+// SetAmount sets the "amount" parameter.
+func (inst *Transfer) SetAmount(amount uint64) *Transfer {
+	inst.Amount = &amount
+	return inst
+}
+
+// SetAuthorizationData sets the "authorizationData" parameter.
+// The authorization data is borsh-encoded.
+func (inst *Transfer) SetAuthorizationData(authorizationData []byte) *Transfer {
+	inst.AuthorizationData = authorizationData
+	return inst
+}
+
+// SetTokenAccount sets the "token" account.
+// The source token account.
+func (inst *Transfer) SetTokenAccount(token ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(token).WRITE()
+	return inst
+}
+
+// GetTokenAccount gets the "token" account.
+// The source token account.
+func (inst *Transfer) GetTokenAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetTokenOwnerAccount sets the "tokenOwner" account.
+// The owner of the source token account.
+func (inst *Transfer) SetTokenOwnerAccount(tokenOwner ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(tokenOwner)
+	return inst
+}
+
+// GetTokenOwnerAccount gets the "tokenOwner" account.
+// The owner of the source token account.
+func (inst *Transfer) GetTokenOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetDestinationAccount sets the "destination" account.
+// The destination token account.
+func (inst *Transfer) SetDestinationAccount(destination ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(destination).WRITE()
+	return inst
+}
+
+// GetDestinationAccount gets the "destination" account.
+// The destination token account.
+func (inst *Transfer) GetDestinationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetDestinationOwnerAccount sets the "destinationOwner" account.
+// The owner of the destination token account.
+func (inst *Transfer) SetDestinationOwnerAccount(destinationOwner ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(destinationOwner)
+	return inst
+}
+
+// GetDestinationOwnerAccount gets the "destinationOwner" account.
+// The owner of the destination token account.
+func (inst *Transfer) GetDestinationOwnerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetMintAccount sets the "mint" account.
+// The mint.
+func (inst *Transfer) SetMintAccount(mint ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(mint)
+	return inst
+}
+
+// GetMintAccount gets the "mint" account.
+// The mint.
+func (inst *Transfer) GetMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetMetadataAccount sets the "metadata" account.
+// The metadata account.
+func (inst *Transfer) SetMetadataAccount(metadata ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(metadata).WRITE()
+	return inst
+}
+
+// GetMetadataAccount gets the "metadata" account.
+// The metadata account.
+func (inst *Transfer) GetMetadataAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetEditionAccount sets the "edition" account.
+// The master edition or edition account, for NFTs.
+func (inst *Transfer) SetEditionAccount(edition ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(edition)
+	return inst
+}
+
+// GetEditionAccount gets the "edition" account.
+// The master edition or edition account, for NFTs.
+func (inst *Transfer) GetEditionAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetOwnerTokenRecordAccount sets the "ownerTokenRecord" account.
+// The token record of the source token account, for programmable NFTs.
+func (inst *Transfer) SetOwnerTokenRecordAccount(ownerTokenRecord ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(ownerTokenRecord).WRITE()
+	return inst
+}
+
+// GetOwnerTokenRecordAccount gets the "ownerTokenRecord" account.
+// The token record of the source token account, for programmable NFTs.
+func (inst *Transfer) GetOwnerTokenRecordAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetDestinationTokenRecordAccount sets the "destinationTokenRecord" account.
+// The token record of the destination token account, for programmable NFTs.
+func (inst *Transfer) SetDestinationTokenRecordAccount(destinationTokenRecord ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(destinationTokenRecord).WRITE()
+	return inst
+}
+
+// GetDestinationTokenRecordAccount gets the "destinationTokenRecord" account.
+// The token record of the destination token account, for programmable NFTs.
+func (inst *Transfer) GetDestinationTokenRecordAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+// The owner or the delegate of the source token account.
+func (inst *Transfer) SetAuthorityAccount(authority ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(authority).SIGNER()
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+// The owner or the delegate of the source token account.
+func (inst *Transfer) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// SetPayerAccount sets the "payer" account.
+// The payer.
+func (inst *Transfer) SetPayerAccount(payer ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[10] = ag_solanago.Meta(payer).WRITE().SIGNER()
+	return inst
+}
+
+// GetPayerAccount gets the "payer" account.
+// The payer.
+func (inst *Transfer) GetPayerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(10)
+}
+
+// SetSystemProgramAccount sets the "systemProgram" account.
+// The system program.
+func (inst *Transfer) SetSystemProgramAccount(systemProgram ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[11] = ag_solanago.Meta(systemProgram)
+	return inst
+}
+
+// GetSystemProgramAccount gets the "systemProgram" account.
+// The system program.
+func (inst *Transfer) GetSystemProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(11)
+}
+
+// SetSysvarInstructionsAccount sets the "sysvarInstructions" account.
+// The instructions sysvar.
+func (inst *Transfer) SetSysvarInstructionsAccount(sysvarInstructions ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[12] = ag_solanago.Meta(sysvarInstructions)
+	return inst
+}
+
+// GetSysvarInstructionsAccount gets the "sysvarInstructions" account.
+// The instructions sysvar.
+func (inst *Transfer) GetSysvarInstructionsAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(12)
+}
+
+// SetSplTokenProgramAccount sets the "splTokenProgram" account.
+// The token program.
+func (inst *Transfer) SetSplTokenProgramAccount(splTokenProgram ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[13] = ag_solanago.Meta(splTokenProgram)
+	return inst
+}
+
+// GetSplTokenProgramAccount gets the "splTokenProgram" account.
+// The token program.
+func (inst *Transfer) GetSplTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(13)
+}
+
+// SetSplAtaProgramAccount sets the "splAtaProgram" account.
+// The associated token account program.
+func (inst *Transfer) SetSplAtaProgramAccount(splAtaProgram ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[14] = ag_solanago.Meta(splAtaProgram)
+	return inst
+}
+
+// GetSplAtaProgramAccount gets the "splAtaProgram" account.
+// The associated token account program.
+func (inst *Transfer) GetSplAtaProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(14)
+}
+
+// SetAuthorizationRulesProgramAccount sets the "authorizationRulesProgram" account.
+// The token authorization rules program, for programmable NFTs with a rule set.
+func (inst *Transfer) SetAuthorizationRulesProgramAccount(authorizationRulesProgram ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[15] = ag_solanago.Meta(authorizationRulesProgram)
+	return inst
+}
+
+// GetAuthorizationRulesProgramAccount gets the "authorizationRulesProgram" account.
+// The token authorization rules program, for programmable NFTs with a rule set.
+func (inst *Transfer) GetAuthorizationRulesProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(15)
+}
+
+// SetAuthorizationRulesAccount sets the "authorizationRules" account.
+// The rule set of the programmable NFT, if any.
+func (inst *Transfer) SetAuthorizationRulesAccount(authorizationRules ag_solanago.PublicKey) *Transfer {
+	inst.AccountMetaSlice[16] = ag_solanago.Meta(authorizationRules)
+	return inst
+}
+
+// GetAuthorizationRulesAccount gets the "authorizationRules" account.
+// The rule set of the programmable NFT, if any.
+func (inst *Transfer) GetAuthorizationRulesAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(16)
+}
+
+// GetAccounts returns the accounts, with the program ID in place of the
+// optional accounts that are not set.
+func (inst Transfer) GetAccounts() []*ag_solanago.AccountMeta {
+	accounts := make([]*ag_solanago.AccountMeta, len(inst.AccountMetaSlice))
+	for i, account := range inst.AccountMetaSlice {
+		accounts[i] = optionalAccount(account)
+	}
+	return accounts
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Transfer) AccountNames() []string {
+	return []string{"token", "tokenOwner", "destination", "destinationOwner", "mint", "metadata", "edition", "ownerTokenRecord", "destinationTokenRecord", "authority", "payer", "systemProgram", "sysvarInstructions", "splTokenProgram", "splAtaProgram", "authorizationRulesProgram", "authorizationRules"}
+}
+
+func (inst Transfer) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_Transfer),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Transfer) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Transfer) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Amount == nil {
+			return errors.New("Amount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Token is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.TokenOwner is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Destination is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.DestinationOwner is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Mint is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.Metadata is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[10] == nil {
+			return errors.New("accounts.Payer is not set")
+		}
+		if inst.AccountMetaSlice[11] == nil {
+			return errors.New("accounts.SystemProgram is not set")
+		}
+		if inst.AccountMetaSlice[12] == nil {
+			return errors.New("accounts.SysvarInstructions is not set")
+		}
+		if inst.AccountMetaSlice[13] == nil {
+			return errors.New("accounts.SplTokenProgram is not set")
+		}
+		if inst.AccountMetaSlice[14] == nil {
+			return errors.New("accounts.SplAtaProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Transfer) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Transfer")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("           Amount", inst.Amount))
+						paramsBranch.Child(ag_format.Param("AuthorizationData", inst.AuthorizationData))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("                    token", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("               tokenOwner", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("              destination", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("         destinationOwner", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("                     mint", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("                 metadata", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("                  edition", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("         ownerTokenRecord", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("   destinationTokenRecord", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("                authority", inst.AccountMetaSlice.Get(9)))
+						accountsBranch.Child(ag_format.Meta("                    payer", inst.AccountMetaSlice.Get(10)))
+						accountsBranch.Child(ag_format.Meta("            systemProgram", inst.AccountMetaSlice.Get(11)))
+						accountsBranch.Child(ag_format.Meta("       sysvarInstructions", inst.AccountMetaSlice.Get(12)))
+						accountsBranch.Child(ag_format.Meta("          splTokenProgram", inst.AccountMetaSlice.Get(13)))
+						accountsBranch.Child(ag_format.Meta("            splAtaProgram", inst.AccountMetaSlice.Get(14)))
+						accountsBranch.Child(ag_format.Meta("authorizationRulesProgram", inst.AccountMetaSlice.Get(15)))
+						accountsBranch.Child(ag_format.Meta("       authorizationRules", inst.AccountMetaSlice.Get(16)))
+					})
+				})
+		})
+}
+
+func (obj Transfer) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize the version of the arguments, V1:
+	err = encoder.WriteUint8(0)
+	if err != nil {
+		return err
+	}
+	// Serialize `Amount` param:
+	err = encoder.Encode(obj.Amount)
+	if err != nil {
+		return err
+	}
+	// Serialize `AuthorizationData` param (optional, already encoded):
+	{
+		err = encoder.WriteBool(obj.AuthorizationData != nil)
+		if err != nil {
+			return err
+		}
+		err = encoder.WriteBytes(obj.AuthorizationData, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (obj *Transfer) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize the version of the arguments:
+	{
+		version, err := decoder.ReadUint8()
+		if err != nil {
+			return err
+		}
+		if version != 0 {
+			return fmt.Errorf("unsupported arguments version: %v", version)
+		}
+	}
+	// Deserialize `Amount`:
+	err = decoder.Decode(&obj.Amount)
+	if err != nil {
+		return err
+	}
+	// Deserialize `AuthorizationData` (optional, kept encoded):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			obj.AuthorizationData, err = decoder.ReadNBytes(decoder.Remaining())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewTransferInstruction declares a new Transfer instruction with the provided parameters and accounts.
+func NewTransferInstruction(
+	// Parameters:
+	amount uint64,
+	// Accounts:
+	token ag_solanago.PublicKey,
+	tokenOwner ag_solanago.PublicKey,
+	destination ag_solanago.PublicKey,
+	destinationOwner ag_solanago.PublicKey,
+	mint ag_solanago.PublicKey,
+	metadata ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+) *Transfer {
+	return NewTransferInstructionBuilder().
+		SetAmount(amount).
+		SetTokenAccount(token).
+		SetTokenOwnerAccount(tokenOwner).
+		SetDestinationAccount(destination).
+		SetDestinationOwnerAccount(destinationOwner).
+		SetMintAccount(mint).
+		SetMetadataAccount(metadata).
+		SetAuthorityAccount(authority).
+		SetPayerAccount(payer)
+}
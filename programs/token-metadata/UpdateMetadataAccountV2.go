@@ -0,0 +1,308 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Updates the metadata of a mint; the unset parameters are left unchanged.
+type UpdateMetadataAccountV2 struct {
+	Data                *DataV2                `bin:"optional"`
+	NewUpdateAuthority  *ag_solanago.PublicKey `bin:"optional"`
+	PrimarySaleHappened *bool                  `bin:"optional"`
+	IsMutable           *bool                  `bin:"optional"`
+
+	// [0] = [WRITE] metadata
+	// ··········· The metadata account.
+	//
+	// [1] = [SIGNER] updateAuthority
+	// ··········· The update authority of the metadata.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewUpdateMetadataAccountV2InstructionBuilder creates a new `UpdateMetadataAccountV2` instruction builder.
+func NewUpdateMetadataAccountV2InstructionBuilder() *UpdateMetadataAccountV2 {
+	nd := &UpdateMetadataAccountV2{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 2),
+	}
+	return nd
+}
+
+// SetData sets the "data" parameter.
+func (inst *UpdateMetadataAccountV2) SetData(data DataV2) *UpdateMetadataAccountV2 {
+	inst.Data = &data
+	return inst
+}
+
+// SetNewUpdateAuthority sets the "newUpdateAuthority" parameter.
+func (inst *UpdateMetadataAccountV2) SetNewUpdateAuthority(newUpdateAuthority ag_solanago.PublicKey) *UpdateMetadataAccountV2 {
+	inst.NewUpdateAuthority = &newUpdateAuthority
+	return inst
+}
+
+// SetPrimarySaleHappened sets the "primarySaleHappened" parameter.
+func (inst *UpdateMetadataAccountV2) SetPrimarySaleHappened(primarySaleHappened bool) *UpdateMetadataAccountV2 {
+	inst.PrimarySaleHappened = &primarySaleHappened
+	return inst
+}
+
+// SetIsMutable sets the "isMutable" parameter.
+func (inst *UpdateMetadataAccountV2) SetIsMutable(isMutable bool) *UpdateMetadataAccountV2 {
+	inst.IsMutable = &isMutable
+	return inst
+}
+
+// SetMetadataAccount sets the "metadata" account.
+// The metadata account.
+func (inst *UpdateMetadataAccountV2) SetMetadataAccount(metadata ag_solanago.PublicKey) *UpdateMetadataAccountV2 {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(metadata).WRITE()
+	return inst
+}
+
+// GetMetadataAccount gets the "metadata" account.
+// The metadata account.
+func (inst *UpdateMetadataAccountV2) GetMetadataAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetUpdateAuthorityAccount sets the "updateAuthority" account.
+// The update authority of the metadata.
+func (inst *UpdateMetadataAccountV2) SetUpdateAuthorityAccount(updateAuthority ag_solanago.PublicKey) *UpdateMetadataAccountV2 {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(updateAuthority).SIGNER()
+	return inst
+}
+
+// GetUpdateAuthorityAccount gets the "updateAuthority" account.
+// The update authority of the metadata.
+func (inst *UpdateMetadataAccountV2) GetUpdateAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst UpdateMetadataAccountV2) AccountNames() []string {
+	return []string{"metadata", "updateAuthority"}
+}
+
+func (inst UpdateMetadataAccountV2) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_UpdateMetadataAccountV2),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst UpdateMetadataAccountV2) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *UpdateMetadataAccountV2) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Data != nil {
+			if err := inst.Data.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Metadata is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.UpdateAuthority is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *UpdateMetadataAccountV2) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("UpdateMetadataAccountV2")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("               Data", inst.Data))
+						paramsBranch.Child(ag_format.Param(" NewUpdateAuthority", inst.NewUpdateAuthority))
+						paramsBranch.Child(ag_format.Param("PrimarySaleHappened", inst.PrimarySaleHappened))
+						paramsBranch.Child(ag_format.Param("          IsMutable", inst.IsMutable))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("       metadata", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("updateAuthority", inst.AccountMetaSlice.Get(1)))
+					})
+				})
+		})
+}
+
+func (obj UpdateMetadataAccountV2) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Data` param (optional):
+	{
+		if obj.Data == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.Data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Serialize `NewUpdateAuthority` param (optional):
+	{
+		if obj.NewUpdateAuthority == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.NewUpdateAuthority)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Serialize `PrimarySaleHappened` param (optional):
+	{
+		if obj.PrimarySaleHappened == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.PrimarySaleHappened)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Serialize `IsMutable` param (optional):
+	{
+		if obj.IsMutable == nil {
+			err = encoder.WriteBool(false)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = encoder.WriteBool(true)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(obj.IsMutable)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+func (obj *UpdateMetadataAccountV2) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Data` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.Data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Deserialize `NewUpdateAuthority` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.NewUpdateAuthority)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Deserialize `PrimarySaleHappened` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.PrimarySaleHappened)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Deserialize `IsMutable` (optional):
+	{
+		ok, err := decoder.ReadBool()
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = decoder.Decode(&obj.IsMutable)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewUpdateMetadataAccountV2Instruction declares a new UpdateMetadataAccountV2 instruction with the provided parameters and accounts.
+func NewUpdateMetadataAccountV2Instruction(
+	// Accounts:
+	metadata ag_solanago.PublicKey,
+	updateAuthority ag_solanago.PublicKey,
+) *UpdateMetadataAccountV2 {
+	return NewUpdateMetadataAccountV2InstructionBuilder().
+		SetMetadataAccount(metadata).
+		SetUpdateAuthorityAccount(updateAuthority)
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Verifies the collection of a NFT, as the authority of the collection.
+type VerifyCollection struct {
+	// [0] = [WRITE] metadata
+	// ··········· The metadata of the NFT.
+	//
+	// [1] = [WRITE, SIGNER] collectionAuthority
+	// ··········· The update authority of the collection, or a delegated authority.
+	//
+	// [2] = [WRITE, SIGNER] payer
+	// ··········· The payer.
+	//
+	// [3] = [] collectionMint
+	// ··········· The mint of the collection NFT.
+	//
+	// [4] = [] collection
+	// ··········· The metadata of the collection NFT.
+	//
+	// [5] = [] collectionMasterEditionAccount
+	// ··········· The master edition of the collection NFT.
+	//
+	// [6] = [] collectionAuthorityRecord
+	// ··········· The collection authority record, for a delegated authority.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewVerifyCollectionInstructionBuilder creates a new `VerifyCollection` instruction builder.
+func NewVerifyCollectionInstructionBuilder() *VerifyCollection {
+	nd := &VerifyCollection{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 7),
+	}
+	return nd
+}
+
+// SetMetadataAccount sets the "metadata" account.
+// The metadata of the NFT.
+func (inst *VerifyCollection) SetMetadataAccount(metadata ag_solanago.PublicKey) *VerifyCollection {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(metadata).WRITE()
+	return inst
+}
+
+// GetMetadataAccount gets the "metadata" account.
+// The metadata of the NFT.
+func (inst *VerifyCollection) GetMetadataAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetCollectionAuthorityAccount sets the "collectionAuthority" account.
+// The update authority of the collection, or a delegated authority.
+func (inst *VerifyCollection) SetCollectionAuthorityAccount(collectionAuthority ag_solanago.PublicKey) *VerifyCollection {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(collectionAuthority).WRITE().SIGNER()
+	return inst
+}
+
+// GetCollectionAuthorityAccount gets the "collectionAuthority" account.
+// The update authority of the collection, or a delegated authority.
+func (inst *VerifyCollection) GetCollectionAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetPayerAccount sets the "payer" account.
+// The payer.
+func (inst *VerifyCollection) SetPayerAccount(payer ag_solanago.PublicKey) *VerifyCollection {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(payer).WRITE().SIGNER()
+	return inst
+}
+
+// GetPayerAccount gets the "payer" account.
+// The payer.
+func (inst *VerifyCollection) GetPayerAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetCollectionMintAccount sets the "collectionMint" account.
+// The mint of the collection NFT.
+func (inst *VerifyCollection) SetCollectionMintAccount(collectionMint ag_solanago.PublicKey) *VerifyCollection {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(collectionMint)
+	return inst
+}
+
+// GetCollectionMintAccount gets the "collectionMint" account.
+// The mint of the collection NFT.
+func (inst *VerifyCollection) GetCollectionMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetCollectionAccount sets the "collection" account.
+// The metadata of the collection NFT.
+func (inst *VerifyCollection) SetCollectionAccount(collection ag_solanago.PublicKey) *VerifyCollection {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(collection)
+	return inst
+}
+
+// GetCollectionAccount gets the "collection" account.
+// The metadata of the collection NFT.
+func (inst *VerifyCollection) GetCollectionAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetCollectionMasterEditionAccountAccount sets the "collectionMasterEditionAccount" account.
+// The master edition of the collection NFT.
+func (inst *VerifyCollection) SetCollectionMasterEditionAccountAccount(collectionMasterEditionAccount ag_solanago.PublicKey) *VerifyCollection {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(collectionMasterEditionAccount)
+	return inst
+}
+
+// GetCollectionMasterEditionAccountAccount gets the "collectionMasterEditionAccount" account.
+// The master edition of the collection NFT.
+func (inst *VerifyCollection) GetCollectionMasterEditionAccountAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetCollectionAuthorityRecordAccount sets the "collectionAuthorityRecord" account.
+// The collection authority record, for a delegated authority.
+func (inst *VerifyCollection) SetCollectionAuthorityRecordAccount(collectionAuthorityRecord ag_solanago.PublicKey) *VerifyCollection {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(collectionAuthorityRecord)
+	return inst
+}
+
+// GetCollectionAuthorityRecordAccount gets the "collectionAuthorityRecord" account.
+// The collection authority record, for a delegated authority.
+func (inst *VerifyCollection) GetCollectionAuthorityRecordAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst VerifyCollection) AccountNames() []string {
+	return []string{"metadata", "collectionAuthority", "payer", "collectionMint", "collection", "collectionMasterEditionAccount", "collectionAuthorityRecord"}
+}
+
+func (inst VerifyCollection) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_VerifyCollection),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst VerifyCollection) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *VerifyCollection) Validate() error {
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Metadata is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.CollectionAuthority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.Payer is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.CollectionMint is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Collection is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.CollectionMasterEditionAccount is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *VerifyCollection) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("VerifyCollection")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("                      metadata", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("           collectionAuthority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("                         payer", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("                collectionMint", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("                    collection", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("collectionMasterEditionAccount", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("     collectionAuthorityRecord", inst.AccountMetaSlice.Get(6)))
+					})
+				})
+		})
+}
+
+func (obj VerifyCollection) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	return nil
+}
+func (obj *VerifyCollection) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	return nil
+}
+
+// NewVerifyCollectionInstruction declares a new VerifyCollection instruction with the provided parameters and accounts.
+func NewVerifyCollectionInstruction(
+	// Accounts:
+	metadata ag_solanago.PublicKey,
+	collectionAuthority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+	collectionMint ag_solanago.PublicKey,
+	collection ag_solanago.PublicKey,
+	collectionMasterEditionAccount ag_solanago.PublicKey,
+) *VerifyCollection {
+	return NewVerifyCollectionInstructionBuilder().
+		SetMetadataAccount(metadata).
+		SetCollectionAuthorityAccount(collectionAuthority).
+		SetPayerAccount(payer).
+		SetCollectionMintAccount(collectionMint).
+		SetCollectionAccount(collection).
+		SetCollectionMasterEditionAccountAccount(collectionMasterEditionAccount)
+}
@@ -0,0 +1,321 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"fmt"
+	"strings"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+const (
+	// The size of the metadata accounts.
+	MAX_METADATA_LEN = 679
+	// The size of the master edition accounts.
+	MAX_MASTER_EDITION_LEN = 282
+	// The size of the edition accounts.
+	MAX_EDITION_LEN = 241
+	// The size of the token record accounts.
+	TOKEN_RECORD_SIZE = 80
+)
+
+// Metadata is the metadata of a mint, at the address of FindMetadataAddress.
+type Metadata struct {
+	Key             Key
+	UpdateAuthority ag_solanago.PublicKey
+	Mint            ag_solanago.PublicKey
+	// The name, symbol and uri are stored padded with zeros; the decoded
+	// ones are trimmed.
+	Data                Data
+	PrimarySaleHappened bool
+	IsMutable           bool
+	EditionNonce        *uint8
+	TokenStandard       *TokenStandard
+	Collection          *Collection
+	Uses                *Uses
+	CollectionDetails   *CollectionDetails
+	ProgrammableConfig  *ProgrammableConfig
+}
+
+func (meta Metadata) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	if err = encoder.WriteUint8(uint8(meta.Key)); err != nil {
+		return err
+	}
+	if err = encoder.Encode(meta.UpdateAuthority); err != nil {
+		return err
+	}
+	if err = encoder.Encode(meta.Mint); err != nil {
+		return err
+	}
+	if err = encoder.Encode(meta.Data); err != nil {
+		return err
+	}
+	if err = encoder.WriteBool(meta.PrimarySaleHappened); err != nil {
+		return err
+	}
+	if err = encoder.WriteBool(meta.IsMutable); err != nil {
+		return err
+	}
+	return encoder.Encode(metadataExtension{
+		EditionNonce:       meta.EditionNonce,
+		TokenStandard:      meta.TokenStandard,
+		Collection:         meta.Collection,
+		Uses:               meta.Uses,
+		CollectionDetails:  meta.CollectionDetails,
+		ProgrammableConfig: meta.ProgrammableConfig,
+	})
+}
+
+// metadataExtension are the fields added to the metadata over time,
+// missing from the older accounts.
+type metadataExtension struct {
+	EditionNonce       *uint8              `bin:"optional"`
+	TokenStandard      *TokenStandard      `bin:"optional"`
+	Collection         *Collection         `bin:"optional"`
+	Uses               *Uses               `bin:"optional"`
+	CollectionDetails  *CollectionDetails  `bin:"optional"`
+	ProgrammableConfig *ProgrammableConfig `bin:"optional"`
+}
+
+func (meta *Metadata) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	key, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	meta.Key = Key(key)
+	if meta.Key != KeyMetadataV1 {
+		return fmt.Errorf("invalid metadata key: %v", key)
+	}
+	if err = decoder.Decode(&meta.UpdateAuthority); err != nil {
+		return err
+	}
+	if err = decoder.Decode(&meta.Mint); err != nil {
+		return err
+	}
+	if err = decoder.Decode(&meta.Data); err != nil {
+		return err
+	}
+	meta.Data.Name = trimPadding(meta.Data.Name)
+	meta.Data.Symbol = trimPadding(meta.Data.Symbol)
+	meta.Data.Uri = trimPadding(meta.Data.Uri)
+	if meta.PrimarySaleHappened, err = decoder.ReadBool(); err != nil {
+		return err
+	}
+	if meta.IsMutable, err = decoder.ReadBool(); err != nil {
+		return err
+	}
+
+	// Like the program, leave the fields unset when they are missing or
+	// corrupted in the older accounts.
+	meta.EditionNonce = decodeTrailingOption[uint8](decoder)
+	meta.TokenStandard = decodeTrailingOption[TokenStandard](decoder)
+	meta.Collection = decodeTrailingOption[Collection](decoder)
+	meta.Uses = decodeTrailingOption[Uses](decoder)
+	meta.CollectionDetails = decodeTrailingOption[CollectionDetails](decoder)
+	meta.ProgrammableConfig = decodeTrailingOption[ProgrammableConfig](decoder)
+	return nil
+}
+
+// decodeTrailingOption decodes an optional field, returning nil when it's
+// missing or can't be decoded.
+func decodeTrailingOption[T any](decoder *ag_binary.Decoder) *T {
+	if decoder.Remaining() == 0 {
+		return nil
+	}
+	isSet, err := decoder.ReadOption()
+	if err != nil || !isSet {
+		return nil
+	}
+	value := new(T)
+	if err := decoder.Decode(value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func trimPadding(s string) string {
+	return strings.TrimRight(s, "\x00")
+}
+
+// MasterEdition is the master edition of a NFT, at the address of
+// FindMasterEditionAddress; it allows printing editions of the NFT.
+type MasterEdition struct {
+	Key Key
+	// Number of printed editions.
+	Supply uint64
+	// Maximum number of editions; unlimited if unset.
+	MaxSupply *uint64 `bin:"optional"`
+}
+
+// Edition is a printed edition of a master edition, at the address of
+// FindMasterEditionAddress with the mint of the edition.
+type Edition struct {
+	Key Key
+	// The master edition.
+	Parent ag_solanago.PublicKey
+	// The number of the edition.
+	Edition uint64
+}
+
+type TokenState ag_binary.BorshEnum
+
+const (
+	TokenStateUnlocked TokenState = iota
+	TokenStateLocked
+	TokenStateListed
+)
+
+type TokenDelegateRole ag_binary.BorshEnum
+
+const (
+	TokenDelegateRoleSale TokenDelegateRole = iota
+	TokenDelegateRoleTransfer
+	TokenDelegateRoleUtility
+	TokenDelegateRoleStaking
+	TokenDelegateRoleStandard
+	TokenDelegateRoleLockedTransfer
+	TokenDelegateRoleMigration
+)
+
+// TokenRecord is the state of a token account of a programmable NFT, at
+// the address of FindTokenRecordAddress.
+type TokenRecord struct {
+	Key             Key
+	Bump            uint8
+	State           TokenState
+	RuleSetRevision *uint64                `bin:"optional"`
+	Delegate        *ag_solanago.PublicKey `bin:"optional"`
+	DelegateRole    *TokenDelegateRole     `bin:"optional"`
+	LockedTransfer  *ag_solanago.PublicKey `bin:"optional"`
+}
+
+func DecodeMetadata(data []byte) (*Metadata, error) {
+	out := new(Metadata)
+	if err := ag_binary.NewBorshDecoder(data).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode metadata: %w", err)
+	}
+	return out, nil
+}
+
+func DecodeMasterEdition(data []byte) (*MasterEdition, error) {
+	out := new(MasterEdition)
+	if err := decodeKeyed(data, KeyMasterEditionV2, out); err != nil {
+		return nil, fmt.Errorf("unable to decode master edition: %w", err)
+	}
+	return out, nil
+}
+
+func DecodeEdition(data []byte) (*Edition, error) {
+	out := new(Edition)
+	if err := decodeKeyed(data, KeyEditionV1, out); err != nil {
+		return nil, fmt.Errorf("unable to decode edition: %w", err)
+	}
+	return out, nil
+}
+
+func DecodeTokenRecord(data []byte) (*TokenRecord, error) {
+	out := new(TokenRecord)
+	if err := decodeKeyed(data, KeyTokenRecord, out); err != nil {
+		return nil, fmt.Errorf("unable to decode token record: %w", err)
+	}
+	return out, nil
+}
+
+func decodeKeyed(data []byte, key Key, out interface{}) error {
+	if len(data) == 0 || Key(data[0]) != key {
+		return fmt.Errorf("account is not a %v", key)
+	}
+	return ag_binary.NewBorshDecoder(data).Decode(out)
+}
+
+func (key Key) String() string {
+	switch key {
+	case KeyUninitialized:
+		return "Uninitialized"
+	case KeyEditionV1:
+		return "EditionV1"
+	case KeyMasterEditionV1:
+		return "MasterEditionV1"
+	case KeyReservationListV1:
+		return "ReservationListV1"
+	case KeyMetadataV1:
+		return "MetadataV1"
+	case KeyReservationListV2:
+		return "ReservationListV2"
+	case KeyMasterEditionV2:
+		return "MasterEditionV2"
+	case KeyEditionMarker:
+		return "EditionMarker"
+	case KeyUseAuthorityRecord:
+		return "UseAuthorityRecord"
+	case KeyCollectionAuthorityRecord:
+		return "CollectionAuthorityRecord"
+	case KeyTokenOwnedEscrow:
+		return "TokenOwnedEscrow"
+	case KeyTokenRecord:
+		return "TokenRecord"
+	case KeyMetadataDelegate:
+		return "MetadataDelegate"
+	case KeyEditionMarkerV2:
+		return "EditionMarkerV2"
+	default:
+		return fmt.Sprintf("Key(%d)", uint8(key))
+	}
+}
+
+func registerAccountDecoders(programID ag_solanago.PublicKey) {
+	ag_solanago.RegisterAccountDecoder(programID, keyMatcher(KeyMetadataV1), decodeMetadataAccount)
+	ag_solanago.RegisterAccountDecoder(programID, keyMatcher(KeyMasterEditionV2), decodeMasterEditionAccount)
+	ag_solanago.RegisterAccountDecoder(programID, keyMatcher(KeyEditionV1), decodeEditionAccount)
+	ag_solanago.RegisterAccountDecoder(programID, keyMatcher(KeyTokenRecord), decodeTokenRecordAccount)
+}
+
+func keyMatcher(key Key) ag_solanago.AccountMatcher {
+	return ag_solanago.AccountDiscriminatorMatcher([]byte{byte(key)})
+}
+
+func decodeMetadataAccount(data []byte) (interface{}, error) {
+	out, err := DecodeMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeMasterEditionAccount(data []byte) (interface{}, error) {
+	out, err := DecodeMasterEdition(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeEditionAccount(data []byte) (interface{}, error) {
+	out, err := DecodeEdition(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeTokenRecordAccount(data []byte) (interface{}, error) {
+	out, err := DecodeTokenRecord(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func appendPaddedString(data []byte, s string, size int) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(size))
+	data = append(data, s...)
+	return append(data, make([]byte, size-len(s))...)
+}
+
+func TestDecodeMetadata(t *testing.T) {
+	updateAuthority := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	mint := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	creator := ag_solanago.MPK("3or4uF7ZyuQW5GGmcmdXDJasNiSZUURF2az1UrRPYQTg")
+	collection := ag_solanago.MPK("9WWfC3y4uCNofr2qEFHSVUXkCxW99JiYkMWmSZvVt8j3")
+	ruleSet := ag_solanago.MPK("eBJLFYPxJmMGKuFwpDWkzxZeUrad92kZRC5BJLpzyT9")
+
+	data := []byte{byte(KeyMetadataV1)}
+	data = append(data, updateAuthority[:]...)
+	data = append(data, mint[:]...)
+	data = appendPaddedString(data, "Programmable #1", MAX_NAME_LENGTH)
+	data = appendPaddedString(data, "PNFT", MAX_SYMBOL_LENGTH)
+	data = appendPaddedString(data, "https://example.com/1.json", MAX_URI_LENGTH)
+	data = binary.LittleEndian.AppendUint16(data, 500)
+	data = append(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = append(data, creator[:]...)
+	data = append(data, 1, 100)
+	// Primary sale happened, is mutable.
+	data = append(data, 0, 1)
+	// Edition nonce.
+	data = append(data, 1, 254)
+	// Token standard.
+	data = append(data, 1, byte(TokenStandardProgrammableNonFungible))
+	// Collection.
+	data = append(data, 1, 1)
+	data = append(data, collection[:]...)
+	// Uses, collection details.
+	data = append(data, 0, 0)
+	// Programmable config.
+	data = append(data, 1, 0, 1)
+	data = append(data, ruleSet[:]...)
+	data = append(data, make([]byte, MAX_METADATA_LEN-len(data))...)
+
+	decoded, err := ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+
+	nonce := uint8(254)
+	tokenStandard := TokenStandardProgrammableNonFungible
+	ag_require.Equal(t, &Metadata{
+		Key:             KeyMetadataV1,
+		UpdateAuthority: updateAuthority,
+		Mint:            mint,
+		Data: Data{
+			Name:                 "Programmable #1",
+			Symbol:               "PNFT",
+			Uri:                  "https://example.com/1.json",
+			SellerFeeBasisPoints: 500,
+			Creators:             &[]Creator{{Address: creator, Verified: true, Share: 100}},
+		},
+		IsMutable:          true,
+		EditionNonce:       &nonce,
+		TokenStandard:      &tokenStandard,
+		Collection:         &Collection{Verified: true, Key: collection},
+		ProgrammableConfig: &ProgrammableConfig{RuleSet: &ruleSet},
+	}, decoded)
+
+	// The older accounts lack the trailing fields.
+	meta, err := DecodeMetadata(data[:1+32+32+4+32+4+10+4+200+2+1+4+34+2])
+	ag_require.NoError(t, err)
+	ag_require.Nil(t, meta.EditionNonce)
+	ag_require.Nil(t, meta.TokenStandard)
+	ag_require.Len(t, *meta.Data.Creators, 1)
+
+	// Encoding without the padding.
+	encoded, err := ag_binary.MarshalBorsh(decoded)
+	ag_require.NoError(t, err)
+	reencoded, err := DecodeMetadata(encoded)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, decoded, reencoded)
+
+	_, err = DecodeMetadata(data[:40])
+	ag_require.Error(t, err)
+}
+
+func TestCollectionDetails(t *testing.T) {
+	for _, details := range []CollectionDetails{
+		{Kind: CollectionDetailsV1, Size: 42},
+		{Kind: CollectionDetailsV2},
+	} {
+		encoded, err := ag_binary.MarshalBorsh(details)
+		ag_require.NoError(t, err)
+		ag_require.Len(t, encoded, 9)
+		ag_require.Equal(t, byte(details.Kind), encoded[0])
+
+		var decoded CollectionDetails
+		ag_require.NoError(t, ag_binary.UnmarshalBorsh(&decoded, encoded))
+		ag_require.Equal(t, details, decoded)
+	}
+
+	var decoded CollectionDetails
+	ag_require.Error(t, ag_binary.UnmarshalBorsh(&decoded, []byte{2, 0, 0, 0, 0, 0, 0, 0, 0}))
+}
+
+func TestDecodeEditions(t *testing.T) {
+	data := []byte{byte(KeyMasterEditionV2)}
+	data = binary.LittleEndian.AppendUint64(data, 3)
+	data = append(data, 1)
+	data = binary.LittleEndian.AppendUint64(data, 10)
+	data = append(data, make([]byte, MAX_MASTER_EDITION_LEN-len(data))...)
+
+	decoded, err := ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	maxSupply := uint64(10)
+	ag_require.Equal(t, &MasterEdition{Key: KeyMasterEditionV2, Supply: 3, MaxSupply: &maxSupply}, decoded)
+
+	parent := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	data = []byte{byte(KeyEditionV1)}
+	data = append(data, parent[:]...)
+	data = binary.LittleEndian.AppendUint64(data, 7)
+	data = append(data, make([]byte, MAX_EDITION_LEN-len(data))...)
+
+	decoded, err = ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, &Edition{Key: KeyEditionV1, Parent: parent, Edition: 7}, decoded)
+
+	_, err = DecodeMasterEdition(data)
+	ag_require.Error(t, err)
+}
+
+func TestDecodeTokenRecord(t *testing.T) {
+	delegate := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	data := []byte{byte(KeyTokenRecord), 255, byte(TokenStateLocked), 0, 1}
+	data = append(data, delegate[:]...)
+	data = append(data, 1, byte(TokenDelegateRoleStaking), 0)
+	data = append(data, make([]byte, TOKEN_RECORD_SIZE-len(data))...)
+
+	decoded, err := ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	role := TokenDelegateRoleStaking
+	ag_require.Equal(t, &TokenRecord{
+		Key:          KeyTokenRecord,
+		Bump:         255,
+		State:        TokenStateLocked,
+		Delegate:     &delegate,
+		DelegateRole: &role,
+	}, decoded)
+}
+
+func TestFindAddresses(t *testing.T) {
+	mint := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+
+	metadata, _, err := FindMetadataAddress(mint)
+	ag_require.NoError(t, err)
+	expected, _, err := ag_solanago.FindTokenMetadataAddress(mint)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, metadata)
+
+	first, _, err := FindEditionMarkerAddress(mint, 1)
+	ag_require.NoError(t, err)
+	last, _, err := FindEditionMarkerAddress(mint, EDITION_MARKER_BIT_SIZE-1)
+	ag_require.NoError(t, err)
+	next, _, err := FindEditionMarkerAddress(mint, EDITION_MARKER_BIT_SIZE)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, first, last)
+	ag_require.NotEqual(t, first, next)
+}
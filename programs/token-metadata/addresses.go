@@ -0,0 +1,67 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"strconv"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+// The number of editions tracked by an edition marker.
+const EDITION_MARKER_BIT_SIZE = 248
+
+const (
+	PREFIX                    = "metadata"
+	EDITION                   = "edition"
+	TOKEN_RECORD_SEED         = "token_record"
+	COLLECTION_AUTHORITY_SEED = "collection_authority"
+)
+
+func findAddress(mint ag_solanago.PublicKey, seeds ...[]byte) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress(
+		append([][]byte{[]byte(PREFIX), ProgramID[:], mint[:]}, seeds...),
+		ProgramID,
+	)
+}
+
+// FindMetadataAddress returns the address of the metadata of the mint.
+func FindMetadataAddress(mint ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return findAddress(mint)
+}
+
+// FindMasterEditionAddress returns the address of the master edition of
+// the mint, or of its edition if it is a printed edition.
+func FindMasterEditionAddress(mint ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return findAddress(mint, []byte(EDITION))
+}
+
+// FindEditionMarkerAddress returns the address of the marker of the
+// printed editions of the master edition mint, for the edition number.
+func FindEditionMarkerAddress(masterMint ag_solanago.PublicKey, edition uint64) (ag_solanago.PublicKey, uint8, error) {
+	return findAddress(masterMint, []byte(EDITION), []byte(strconv.FormatUint(edition/EDITION_MARKER_BIT_SIZE, 10)))
+}
+
+// FindTokenRecordAddress returns the address of the token record of the
+// token account of a programmable NFT.
+func FindTokenRecordAddress(mint ag_solanago.PublicKey, token ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return findAddress(mint, []byte(TOKEN_RECORD_SEED), token[:])
+}
+
+// FindCollectionAuthorityRecordAddress returns the address of the record
+// delegating the authority of the collection of the mint.
+func FindCollectionAuthorityRecordAddress(mint ag_solanago.PublicKey, authority ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return findAddress(mint, []byte(COLLECTION_AUTHORITY_SEED), authority[:])
+}
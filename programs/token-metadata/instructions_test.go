@@ -0,0 +1,164 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func appendString(data []byte, s string) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(s)))
+	return append(data, s...)
+}
+
+func TestCreateMetadataAccountV3(t *testing.T) {
+	mint := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	authority := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	metadata, _, err := FindMetadataAddress(mint)
+	ag_require.NoError(t, err)
+
+	inst, err := NewCreateMetadataAccountV3Instruction(
+		DataV2{
+			Name:                 "Token",
+			Symbol:               "TKN",
+			Uri:                  "https://example.com",
+			SellerFeeBasisPoints: 100,
+			Creators:             &[]Creator{{Address: authority, Verified: true, Share: 100}},
+		},
+		true,
+		metadata,
+		mint,
+		authority,
+		authority,
+		authority,
+		true,
+	).SetCollectionDetails(CollectionDetails{Size: 0}).ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := []byte{Instruction_CreateMetadataAccountV3}
+	expected = appendString(expected, "Token")
+	expected = appendString(expected, "TKN")
+	expected = appendString(expected, "https://example.com")
+	expected = binary.LittleEndian.AppendUint16(expected, 100)
+	expected = append(expected, 1, 1, 0, 0, 0)
+	expected = append(expected, authority[:]...)
+	expected = append(expected, 1, 100)
+	// No collection nor uses; is mutable; collection details V1.
+	expected = append(expected, 0, 0, 1, 1, 0)
+	expected = binary.LittleEndian.AppendUint64(expected, 0)
+
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+	ag_require.Equal(t, []*ag_solanago.AccountMeta{
+		ag_solanago.Meta(metadata).WRITE(),
+		ag_solanago.Meta(mint),
+		ag_solanago.Meta(authority).SIGNER(),
+		ag_solanago.Meta(authority).WRITE().SIGNER(),
+		ag_solanago.Meta(authority).SIGNER(),
+		ag_solanago.Meta(ag_solanago.SystemProgramID),
+	}, inst.Accounts())
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	ag_require.NoError(t, err)
+	impl := inst.Impl.(CreateMetadataAccountV3)
+	ag_require.Equal(t, &impl, decoded.Impl)
+	ag_require.Equal(t, "CreateMetadataAccountV3", decoded.InstructionName())
+
+	_, err = NewCreateMetadataAccountV3InstructionBuilder().
+		SetData(DataV2{Name: "A name longer than thirty-two bytes"}).
+		SetIsMutable(false).
+		ValidateAndBuild()
+	ag_require.Error(t, err)
+}
+
+func TestTransferNFT(t *testing.T) {
+	mint := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	owner := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	destinationOwner := ag_solanago.MPK("3or4uF7ZyuQW5GGmcmdXDJasNiSZUURF2az1UrRPYQTg")
+
+	transfer, err := NewTransferNFTInstruction(TokenStandardNonFungible, nil, mint, owner, destinationOwner, owner)
+	ag_require.NoError(t, err)
+	inst, err := transfer.ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []byte{Instruction_Transfer, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, data)
+
+	// The unset token records and rules are replaced by the program ID.
+	accounts := inst.Accounts()
+	ag_require.Len(t, accounts, 17)
+	for _, index := range []int{7, 8, 15, 16} {
+		ag_require.Equal(t, ag_solanago.Meta(ProgramID), accounts[index])
+	}
+
+	ruleSet := ag_solanago.MPK("eBJLFYPxJmMGKuFwpDWkzxZeUrad92kZRC5BJLpzyT9")
+	_, err = NewTransferNFTInstruction(TokenStandardNonFungible, &ruleSet, mint, owner, destinationOwner, owner)
+	ag_require.Error(t, err)
+
+	transfer, err = NewTransferNFTInstruction(TokenStandardProgrammableNonFungible, &ruleSet, mint, owner, destinationOwner, owner)
+	ag_require.NoError(t, err)
+	token, _, err := ag_solanago.FindAssociatedTokenAddress(owner, mint)
+	ag_require.NoError(t, err)
+	tokenRecord, _, err := FindTokenRecordAddress(mint, token)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, ag_solanago.Meta(tokenRecord).WRITE(), transfer.GetOwnerTokenRecordAccount())
+	ag_require.Equal(t, ag_solanago.Meta(ruleSet), transfer.GetAuthorizationRulesAccount())
+
+	transfer.SetAuthorizationData([]byte{0, 0, 0, 0})
+	data, err = transfer.Build().Data()
+	ag_require.NoError(t, err)
+	decoded, err := DecodeInstruction(transfer.GetAccounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []byte{0, 0, 0, 0}, decoded.Impl.(*Transfer).AuthorizationData)
+	ag_require.Equal(t, uint64(1), *decoded.Impl.(*Transfer).Amount)
+}
+
+func TestCreateNFT(t *testing.T) {
+	mint := ag_solanago.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")
+	authority := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+
+	create, err := NewCreateNFTInstruction(AssetData{
+		Name:          "NFT",
+		Uri:           "https://example.com",
+		IsMutable:     true,
+		TokenStandard: TokenStandardProgrammableNonFungible,
+	}, mint, true, authority, authority, authority)
+	ag_require.NoError(t, err)
+	inst, err := create.ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := []byte{Instruction_Create, 0}
+	expected = appendString(expected, "NFT")
+	expected = appendString(expected, "")
+	expected = appendString(expected, "https://example.com")
+	// Fee, no creators, primary sale, mutable, standard, no collection,
+	// uses, details nor rule set, zero decimals, no prints.
+	expected = append(expected, 0, 0, 0, 0, 1, byte(TokenStandardProgrammableNonFungible), 0, 0, 0, 0, 1, 0, 1, byte(PrintSupplyZero))
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+
+	masterEdition, _, err := FindMasterEditionAddress(mint)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, ag_solanago.Meta(masterEdition).WRITE(), inst.Accounts()[1])
+	ag_require.Equal(t, ag_solanago.Meta(mint).WRITE().SIGNER(), inst.Accounts()[2])
+	ag_require.Len(t, inst.Accounts(), 9)
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"errors"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+// The program of the rule sets of programmable NFTs.
+var TokenAuthRulesProgramID = ag_solanago.MustPublicKeyFromBase58("auth9SigNpDKz4sJJ1DfCTuZrZNSAgh9sFD3rboVmgg")
+
+// NewCreateNFTInstruction declares a Create instruction of a NFT without
+// prints, with its metadata and master edition accounts derived from the mint;
+// the mint signs when it doesn't exist yet.
+func NewCreateNFTInstruction(
+	assetData AssetData,
+	mint ag_solanago.PublicKey,
+	isMintSigner bool,
+	authority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+	updateAuthority ag_solanago.PublicKey,
+) (*Create, error) {
+	metadata, _, err := FindMetadataAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	masterEdition, _, err := FindMasterEditionAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	return NewCreateInstruction(assetData, metadata, mint, isMintSigner, authority, payer, updateAuthority, updateAuthority.Equals(authority)).
+		SetDecimals(0).
+		SetPrintSupply(PrintSupply{Kind: PrintSupplyZero}).
+		SetMasterEditionAccount(masterEdition), nil
+}
+
+// NewMintNFTInstruction declares a Mint instruction of a NFT to the
+// associated token account of the owner; the token record of the token
+// account is set for programmable NFTs.
+func NewMintNFTInstruction(
+	tokenStandard TokenStandard,
+	mint ag_solanago.PublicKey,
+	owner ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+) (*Mint, error) {
+	token, _, err := ag_solanago.FindAssociatedTokenAddress(owner, mint)
+	if err != nil {
+		return nil, err
+	}
+	metadata, _, err := FindMetadataAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	masterEdition, _, err := FindMasterEditionAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	inst := NewMintInstruction(1, token, metadata, mint, authority, payer).
+		SetTokenOwnerAccount(owner).
+		SetMasterEditionAccount(masterEdition)
+	if tokenStandard.IsProgrammable() {
+		tokenRecord, _, err := FindTokenRecordAddress(mint, token)
+		if err != nil {
+			return nil, err
+		}
+		inst.SetTokenRecordAccount(tokenRecord)
+	}
+	return inst, nil
+}
+
+// NewTransferNFTInstruction declares a Transfer instruction of a NFT
+// between the associated token accounts of its owner and of the
+// destination owner. For programmable NFTs, the token records are set,
+// and the rule set if it isn't nil.
+func NewTransferNFTInstruction(
+	tokenStandard TokenStandard,
+	ruleSet *ag_solanago.PublicKey,
+	mint ag_solanago.PublicKey,
+	owner ag_solanago.PublicKey,
+	destinationOwner ag_solanago.PublicKey,
+	payer ag_solanago.PublicKey,
+) (*Transfer, error) {
+	token, _, err := ag_solanago.FindAssociatedTokenAddress(owner, mint)
+	if err != nil {
+		return nil, err
+	}
+	destination, _, err := ag_solanago.FindAssociatedTokenAddress(destinationOwner, mint)
+	if err != nil {
+		return nil, err
+	}
+	metadata, _, err := FindMetadataAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	edition, _, err := FindMasterEditionAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	inst := NewTransferInstruction(1, token, owner, destination, destinationOwner, mint, metadata, owner, payer).
+		SetEditionAccount(edition)
+	if !tokenStandard.IsProgrammable() {
+		if ruleSet != nil {
+			return nil, errors.New("rule set of a token that is not programmable")
+		}
+		return inst, nil
+	}
+	ownerTokenRecord, _, err := FindTokenRecordAddress(mint, token)
+	if err != nil {
+		return nil, err
+	}
+	destinationTokenRecord, _, err := FindTokenRecordAddress(mint, destination)
+	if err != nil {
+		return nil, err
+	}
+	inst.SetOwnerTokenRecordAccount(ownerTokenRecord).
+		SetDestinationTokenRecordAccount(destinationTokenRecord)
+	if ruleSet != nil {
+		inst.SetAuthorizationRulesProgramAccount(TokenAuthRulesProgramID).
+			SetAuthorizationRulesAccount(*ruleSet)
+	}
+	return inst, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"context"
+	"fmt"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// FetchMetadata fetches the metadata of the mint.
+func FetchMetadata(ctx context.Context, rpcCli *rpc.Client, mint ag_solanago.PublicKey) (*Metadata, error) {
+	address, _, err := FindMetadataAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rpcCli.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get metadata account: %w", err)
+	}
+	return DecodeMetadata(resp.GetBinary())
+}
+
+// FetchMasterEdition fetches the master edition of the mint.
+func FetchMasterEdition(ctx context.Context, rpcCli *rpc.Client, mint ag_solanago.PublicKey) (*MasterEdition, error) {
+	address, _, err := FindMasterEditionAddress(mint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rpcCli.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get master edition account: %w", err)
+	}
+	return DecodeMasterEdition(resp.GetBinary())
+}
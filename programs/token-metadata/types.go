@@ -0,0 +1,305 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenmetadata
+
+import (
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+const (
+	MAX_NAME_LENGTH   = 32
+	MAX_SYMBOL_LENGTH = 10
+	MAX_URI_LENGTH    = 200
+	MAX_CREATOR_LIMIT = 5
+)
+
+// Key is the type of an account of the program, its first byte.
+type Key ag_binary.BorshEnum
+
+const (
+	KeyUninitialized Key = iota
+	KeyEditionV1
+	KeyMasterEditionV1
+	KeyReservationListV1
+	KeyMetadataV1
+	KeyReservationListV2
+	KeyMasterEditionV2
+	KeyEditionMarker
+	KeyUseAuthorityRecord
+	KeyCollectionAuthorityRecord
+	KeyTokenOwnedEscrow
+	KeyTokenRecord
+	KeyMetadataDelegate
+	KeyEditionMarkerV2
+)
+
+type TokenStandard ag_binary.BorshEnum
+
+const (
+	// A non-fungible token with a master edition.
+	TokenStandardNonFungible TokenStandard = iota
+	// A fungible token with metadata that can also have attributes.
+	TokenStandardFungibleAsset
+	// A fungible token with simple metadata.
+	TokenStandardFungible
+	// A non-fungible token printed from a master edition.
+	TokenStandardNonFungibleEdition
+	// A non-fungible token with programmable configuration.
+	TokenStandardProgrammableNonFungible
+	// A programmable non-fungible token printed from a master edition.
+	TokenStandardProgrammableNonFungibleEdition
+)
+
+// IsProgrammable returns whether the transfers of the token are
+// enforced by the token metadata program, with token records.
+func (standard TokenStandard) IsProgrammable() bool {
+	return standard == TokenStandardProgrammableNonFungible ||
+		standard == TokenStandardProgrammableNonFungibleEdition
+}
+
+type UseMethod ag_binary.BorshEnum
+
+const (
+	UseMethodBurn UseMethod = iota
+	UseMethodMultiple
+	UseMethodSingle
+)
+
+type Creator struct {
+	Address  ag_solanago.PublicKey
+	Verified bool
+	// Share of the royalties, in percent; the shares of the creators
+	// add up to 100.
+	Share uint8
+}
+
+type Collection struct {
+	Verified bool
+	Key      ag_solanago.PublicKey
+}
+
+type Uses struct {
+	UseMethod UseMethod
+	Remaining uint64
+	Total     uint64
+}
+
+// Data is the data of the metadata of a token.
+type Data struct {
+	Name   string
+	Symbol string
+	Uri    string
+	// Royalties of the secondary sales, in basis points.
+	SellerFeeBasisPoints uint16
+	Creators             *[]Creator `bin:"optional"`
+}
+
+// DataV2 is the data of the instructions creating and updating metadata.
+type DataV2 struct {
+	Name                 string
+	Symbol               string
+	Uri                  string
+	SellerFeeBasisPoints uint16
+	Creators             *[]Creator  `bin:"optional"`
+	Collection           *Collection `bin:"optional"`
+	Uses                 *Uses       `bin:"optional"`
+}
+
+func (data DataV2) validate() error {
+	return validateData(data.Name, data.Symbol, data.Uri, data.Creators)
+}
+
+func validateData(name, symbol, uri string, creators *[]Creator) error {
+	if len(name) > MAX_NAME_LENGTH {
+		return fmt.Errorf("name is longer than %v bytes", MAX_NAME_LENGTH)
+	}
+	if len(symbol) > MAX_SYMBOL_LENGTH {
+		return fmt.Errorf("symbol is longer than %v bytes", MAX_SYMBOL_LENGTH)
+	}
+	if len(uri) > MAX_URI_LENGTH {
+		return fmt.Errorf("uri is longer than %v bytes", MAX_URI_LENGTH)
+	}
+	if creators != nil && len(*creators) > MAX_CREATOR_LIMIT {
+		return fmt.Errorf("more than %v creators", MAX_CREATOR_LIMIT)
+	}
+	return nil
+}
+
+type CollectionDetailsKind ag_binary.BorshEnum
+
+const (
+	// A sized collection, which counts its verified items.
+	CollectionDetailsV1 CollectionDetailsKind = iota
+	// A collection whose size is not tracked.
+	CollectionDetailsV2
+)
+
+// CollectionDetails is set on the metadata of the collection NFTs.
+type CollectionDetails struct {
+	Kind CollectionDetailsKind
+	// Number of verified items of the collection; set for CollectionDetailsV1.
+	Size uint64
+}
+
+func (details CollectionDetails) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if err := encoder.WriteUint8(uint8(details.Kind)); err != nil {
+		return err
+	}
+	switch details.Kind {
+	case CollectionDetailsV1:
+		return encoder.WriteUint64(details.Size, ag_binary.LE)
+	case CollectionDetailsV2:
+		// The padding that replaces the size.
+		return encoder.WriteBytes(make([]byte, 8), false)
+	default:
+		return fmt.Errorf("invalid collection details variant: %v", details.Kind)
+	}
+}
+
+func (details *CollectionDetails) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	kind, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	details.Kind = CollectionDetailsKind(kind)
+	switch details.Kind {
+	case CollectionDetailsV1:
+		details.Size, err = decoder.ReadUint64(ag_binary.LE)
+		return err
+	case CollectionDetailsV2:
+		_, err = decoder.ReadNBytes(8)
+		details.Size = 0
+		return err
+	default:
+		return fmt.Errorf("invalid collection details variant: %v", kind)
+	}
+}
+
+// ProgrammableConfig is the configuration of programmable NFTs.
+type ProgrammableConfig struct {
+	// The authorization rules applied to transfers, if any.
+	RuleSet *ag_solanago.PublicKey `bin:"optional"`
+}
+
+func (config ProgrammableConfig) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	// The V1 variant is the only one.
+	if err := encoder.WriteUint8(0); err != nil {
+		return err
+	}
+	return encodeOptionalPublicKey(encoder, config.RuleSet)
+}
+
+func (config *ProgrammableConfig) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	variant, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	if variant != 0 {
+		return fmt.Errorf("invalid programmable config variant: %v", variant)
+	}
+	config.RuleSet, err = decodeOptionalPublicKey(decoder)
+	return err
+}
+
+// AssetData is the data of the metadata created by the Create instruction.
+type AssetData struct {
+	Name                 string
+	Symbol               string
+	Uri                  string
+	SellerFeeBasisPoints uint16
+	Creators             *[]Creator `bin:"optional"`
+	PrimarySaleHappened  bool
+	IsMutable            bool
+	TokenStandard        TokenStandard
+	Collection           *Collection            `bin:"optional"`
+	Uses                 *Uses                  `bin:"optional"`
+	CollectionDetails    *CollectionDetails     `bin:"optional"`
+	RuleSet              *ag_solanago.PublicKey `bin:"optional"`
+}
+
+func (data AssetData) validate() error {
+	return validateData(data.Name, data.Symbol, data.Uri, data.Creators)
+}
+
+type PrintSupplyKind ag_binary.BorshEnum
+
+const (
+	// The master edition can't print editions.
+	PrintSupplyZero PrintSupplyKind = iota
+	// The master edition can print up to a number of editions.
+	PrintSupplyLimited
+	// The master edition can print any number of editions.
+	PrintSupplyUnlimited
+)
+
+// PrintSupply is the number of editions a master edition can print.
+type PrintSupply struct {
+	Kind PrintSupplyKind
+	// Set for PrintSupplyLimited.
+	Limit uint64
+}
+
+func (supply PrintSupply) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if err := encoder.WriteUint8(uint8(supply.Kind)); err != nil {
+		return err
+	}
+	if supply.Kind == PrintSupplyLimited {
+		return encoder.WriteUint64(supply.Limit, ag_binary.LE)
+	}
+	return nil
+}
+
+func (supply *PrintSupply) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	kind, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	supply.Kind = PrintSupplyKind(kind)
+	switch supply.Kind {
+	case PrintSupplyZero, PrintSupplyUnlimited:
+		supply.Limit = 0
+		return nil
+	case PrintSupplyLimited:
+		supply.Limit, err = decoder.ReadUint64(ag_binary.LE)
+		return err
+	default:
+		return fmt.Errorf("invalid print supply variant: %v", kind)
+	}
+}
+
+func encodeOptionalPublicKey(encoder *ag_binary.Encoder, key *ag_solanago.PublicKey) error {
+	if err := encoder.WriteOption(key != nil); err != nil {
+		return err
+	}
+	if key == nil {
+		return nil
+	}
+	return encoder.WriteBytes(key[:], false)
+}
+
+func decodeOptionalPublicKey(decoder *ag_binary.Decoder) (*ag_solanago.PublicKey, error) {
+	isSet, err := decoder.ReadOption()
+	if err != nil || !isSet {
+		return nil, err
+	}
+	var key ag_solanago.PublicKey
+	if err := decoder.Decode(&key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,398 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Deposits both tokens, in the proportion of the swap, for pool tokens.
+type DepositAllTokenTypes struct {
+	// The amount of pool tokens to mint.
+	PoolTokenAmount *uint64
+	// The maximum amount of token A to deposit, to prevent excessive slippage.
+	MaximumTokenAAmount *uint64
+	// The maximum amount of token B to deposit, to prevent excessive slippage.
+	MaximumTokenBAmount *uint64
+
+	// [0] = [] swap
+	// ··········· The swap.
+	//
+	// [1] = [] authority
+	// ··········· The authority of the swap, at FindSwapAuthorityAddress(swap).
+	//
+	// [2] = [SIGNER] userTransferAuthority
+	// ··········· The owner or the delegate of the user token accounts it debits.
+	//
+	// [3] = [WRITE] sourceA
+	// ··········· The user token account of token A.
+	//
+	// [4] = [WRITE] sourceB
+	// ··········· The user token account of token B.
+	//
+	// [5] = [WRITE] tokenA
+	// ··········· The token account of token A of the swap.
+	//
+	// [6] = [WRITE] tokenB
+	// ··········· The token account of token B of the swap.
+	//
+	// [7] = [WRITE] poolMint
+	// ··········· The mint of the pool tokens.
+	//
+	// [8] = [WRITE] destination
+	// ··········· The user pool token account receiving the pool tokens.
+	//
+	// [9] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewDepositAllTokenTypesInstructionBuilder creates a new `DepositAllTokenTypes` instruction builder.
+func NewDepositAllTokenTypesInstructionBuilder() *DepositAllTokenTypes {
+	nd := &DepositAllTokenTypes{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 10),
+	}
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetPoolTokenAmount sets the "poolTokenAmount" parameter.
+// The amount of pool tokens to mint.
+func (inst *DepositAllTokenTypes) SetPoolTokenAmount(poolTokenAmount uint64) *DepositAllTokenTypes {
+	inst.PoolTokenAmount = &poolTokenAmount
+	return inst
+}
+
+// SetMaximumTokenAAmount sets the "maximumTokenAAmount" parameter.
+// The maximum amount of token A to deposit, to prevent excessive slippage.
+func (inst *DepositAllTokenTypes) SetMaximumTokenAAmount(maximumTokenAAmount uint64) *DepositAllTokenTypes {
+	inst.MaximumTokenAAmount = &maximumTokenAAmount
+	return inst
+}
+
+// SetMaximumTokenBAmount sets the "maximumTokenBAmount" parameter.
+// The maximum amount of token B to deposit, to prevent excessive slippage.
+func (inst *DepositAllTokenTypes) SetMaximumTokenBAmount(maximumTokenBAmount uint64) *DepositAllTokenTypes {
+	inst.MaximumTokenBAmount = &maximumTokenBAmount
+	return inst
+}
+
+// SetSwapAccount sets the "swap" account.
+// The swap.
+func (inst *DepositAllTokenTypes) SetSwapAccount(swap ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(swap)
+	return inst
+}
+
+// GetSwapAccount gets the "swap" account.
+// The swap.
+func (inst *DepositAllTokenTypes) GetSwapAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+// The authority of the swap, at FindSwapAuthorityAddress(swap).
+func (inst *DepositAllTokenTypes) SetAuthorityAccount(authority ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(authority)
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+// The authority of the swap, at FindSwapAuthorityAddress(swap).
+func (inst *DepositAllTokenTypes) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetUserTransferAuthorityAccount sets the "userTransferAuthority" account.
+// The owner or the delegate of the user token accounts it debits.
+func (inst *DepositAllTokenTypes) SetUserTransferAuthorityAccount(userTransferAuthority ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(userTransferAuthority).SIGNER()
+	return inst
+}
+
+// GetUserTransferAuthorityAccount gets the "userTransferAuthority" account.
+// The owner or the delegate of the user token accounts it debits.
+func (inst *DepositAllTokenTypes) GetUserTransferAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetSourceAAccount sets the "sourceA" account.
+// The user token account of token A.
+func (inst *DepositAllTokenTypes) SetSourceAAccount(sourceA ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(sourceA).WRITE()
+	return inst
+}
+
+// GetSourceAAccount gets the "sourceA" account.
+// The user token account of token A.
+func (inst *DepositAllTokenTypes) GetSourceAAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetSourceBAccount sets the "sourceB" account.
+// The user token account of token B.
+func (inst *DepositAllTokenTypes) SetSourceBAccount(sourceB ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(sourceB).WRITE()
+	return inst
+}
+
+// GetSourceBAccount gets the "sourceB" account.
+// The user token account of token B.
+func (inst *DepositAllTokenTypes) GetSourceBAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetTokenAAccount sets the "tokenA" account.
+// The token account of token A of the swap.
+func (inst *DepositAllTokenTypes) SetTokenAAccount(tokenA ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(tokenA).WRITE()
+	return inst
+}
+
+// GetTokenAAccount gets the "tokenA" account.
+// The token account of token A of the swap.
+func (inst *DepositAllTokenTypes) GetTokenAAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetTokenBAccount sets the "tokenB" account.
+// The token account of token B of the swap.
+func (inst *DepositAllTokenTypes) SetTokenBAccount(tokenB ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(tokenB).WRITE()
+	return inst
+}
+
+// GetTokenBAccount gets the "tokenB" account.
+// The token account of token B of the swap.
+func (inst *DepositAllTokenTypes) GetTokenBAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The mint of the pool tokens.
+func (inst *DepositAllTokenTypes) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The mint of the pool tokens.
+func (inst *DepositAllTokenTypes) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetDestinationAccount sets the "destination" account.
+// The user pool token account receiving the pool tokens.
+func (inst *DepositAllTokenTypes) SetDestinationAccount(destination ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(destination).WRITE()
+	return inst
+}
+
+// GetDestinationAccount gets the "destination" account.
+// The user pool token account receiving the pool tokens.
+func (inst *DepositAllTokenTypes) GetDestinationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *DepositAllTokenTypes) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *DepositAllTokenTypes {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *DepositAllTokenTypes) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst DepositAllTokenTypes) AccountNames() []string {
+	return []string{"swap", "authority", "userTransferAuthority", "sourceA", "sourceB", "tokenA", "tokenB", "poolMint", "destination", "tokenProgram"}
+}
+
+func (inst DepositAllTokenTypes) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_DepositAllTokenTypes),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst DepositAllTokenTypes) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *DepositAllTokenTypes) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.PoolTokenAmount == nil {
+			return errors.New("PoolTokenAmount parameter is not set")
+		}
+		if inst.MaximumTokenAAmount == nil {
+			return errors.New("MaximumTokenAAmount parameter is not set")
+		}
+		if inst.MaximumTokenBAmount == nil {
+			return errors.New("MaximumTokenBAmount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Swap is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.UserTransferAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.SourceA is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.SourceB is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.TokenA is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.TokenB is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.Destination is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *DepositAllTokenTypes) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("DepositAllTokenTypes")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("    PoolTokenAmount", inst.PoolTokenAmount))
+						paramsBranch.Child(ag_format.Param("MaximumTokenAAmount", inst.MaximumTokenAAmount))
+						paramsBranch.Child(ag_format.Param("MaximumTokenBAmount", inst.MaximumTokenBAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("                 swap", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("            authority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("userTransferAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("              sourceA", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("              sourceB", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("               tokenA", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("               tokenB", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("             poolMint", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("          destination", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("         tokenProgram", inst.AccountMetaSlice.Get(9)))
+					})
+				})
+		})
+}
+
+func (obj DepositAllTokenTypes) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `PoolTokenAmount` param:
+	err = encoder.Encode(obj.PoolTokenAmount)
+	if err != nil {
+		return err
+	}
+	// Serialize `MaximumTokenAAmount` param:
+	err = encoder.Encode(obj.MaximumTokenAAmount)
+	if err != nil {
+		return err
+	}
+	// Serialize `MaximumTokenBAmount` param:
+	err = encoder.Encode(obj.MaximumTokenBAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *DepositAllTokenTypes) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `PoolTokenAmount`:
+	err = decoder.Decode(&obj.PoolTokenAmount)
+	if err != nil {
+		return err
+	}
+	// Deserialize `MaximumTokenAAmount`:
+	err = decoder.Decode(&obj.MaximumTokenAAmount)
+	if err != nil {
+		return err
+	}
+	// Deserialize `MaximumTokenBAmount`:
+	err = decoder.Decode(&obj.MaximumTokenBAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewDepositAllTokenTypesInstruction declares a new DepositAllTokenTypes instruction with the provided parameters and accounts.
+func NewDepositAllTokenTypesInstruction(
+	// Parameters:
+	poolTokenAmount uint64,
+	maximumTokenAAmount uint64,
+	maximumTokenBAmount uint64,
+	// Accounts:
+	swap ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	userTransferAuthority ag_solanago.PublicKey,
+	sourceA ag_solanago.PublicKey,
+	sourceB ag_solanago.PublicKey,
+	tokenA ag_solanago.PublicKey,
+	tokenB ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+	destination ag_solanago.PublicKey,
+) *DepositAllTokenTypes {
+	return NewDepositAllTokenTypesInstructionBuilder().
+		SetPoolTokenAmount(poolTokenAmount).
+		SetMaximumTokenAAmount(maximumTokenAAmount).
+		SetMaximumTokenBAmount(maximumTokenBAmount).
+		SetSwapAccount(swap).
+		SetAuthorityAccount(authority).
+		SetUserTransferAuthorityAccount(userTransferAuthority).
+		SetSourceAAccount(sourceA).
+		SetSourceBAccount(sourceB).
+		SetTokenAAccount(tokenA).
+		SetTokenBAccount(tokenB).
+		SetPoolMintAccount(poolMint).
+		SetDestinationAccount(destination)
+}
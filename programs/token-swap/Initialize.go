@@ -0,0 +1,332 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Initializes a swap between the tokens of its two token accounts, owned by
+// its authority, and mints the initial supply of pool tokens.
+type Initialize struct {
+	Fees      *Fees
+	SwapCurve *SwapCurve
+
+	// [0] = [WRITE, SIGNER] swap
+	// ··········· The new swap account, allocated with SWAP_LEN bytes and owned by the program.
+	//
+	// [1] = [] authority
+	// ··········· The authority of the swap, at FindSwapAuthorityAddress(swap).
+	//
+	// [2] = [] tokenA
+	// ··········· The token account of token A, owned by the authority of the swap.
+	//
+	// [3] = [] tokenB
+	// ··········· The token account of token B, owned by the authority of the swap.
+	//
+	// [4] = [WRITE] poolMint
+	// ··········· The mint of the pool tokens, with the authority of the swap as mint authority.
+	//
+	// [5] = [] poolFee
+	// ··········· The pool token account receiving the trading and withdrawal fees.
+	//
+	// [6] = [WRITE] destination
+	// ··········· The pool token account receiving the initial supply of pool tokens.
+	//
+	// [7] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeInstructionBuilder creates a new `Initialize` instruction builder.
+func NewInitializeInstructionBuilder() *Initialize {
+	nd := &Initialize{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 8),
+	}
+	nd.AccountMetaSlice[7] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetFees sets the "fees" parameter.
+func (inst *Initialize) SetFees(fees Fees) *Initialize {
+	inst.Fees = &fees
+	return inst
+}
+
+// SetSwapCurve sets the "swapCurve" parameter.
+func (inst *Initialize) SetSwapCurve(swapCurve SwapCurve) *Initialize {
+	inst.SwapCurve = &swapCurve
+	return inst
+}
+
+// SetSwapAccount sets the "swap" account.
+// The new swap account, allocated with SWAP_LEN bytes and owned by the program.
+func (inst *Initialize) SetSwapAccount(swap ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(swap).WRITE().SIGNER()
+	return inst
+}
+
+// GetSwapAccount gets the "swap" account.
+// The new swap account, allocated with SWAP_LEN bytes and owned by the program.
+func (inst *Initialize) GetSwapAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+// The authority of the swap, at FindSwapAuthorityAddress(swap).
+func (inst *Initialize) SetAuthorityAccount(authority ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(authority)
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+// The authority of the swap, at FindSwapAuthorityAddress(swap).
+func (inst *Initialize) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetTokenAAccount sets the "tokenA" account.
+// The token account of token A, owned by the authority of the swap.
+func (inst *Initialize) SetTokenAAccount(tokenA ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(tokenA)
+	return inst
+}
+
+// GetTokenAAccount gets the "tokenA" account.
+// The token account of token A, owned by the authority of the swap.
+func (inst *Initialize) GetTokenAAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetTokenBAccount sets the "tokenB" account.
+// The token account of token B, owned by the authority of the swap.
+func (inst *Initialize) SetTokenBAccount(tokenB ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(tokenB)
+	return inst
+}
+
+// GetTokenBAccount gets the "tokenB" account.
+// The token account of token B, owned by the authority of the swap.
+func (inst *Initialize) GetTokenBAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The mint of the pool tokens, with the authority of the swap as mint authority.
+func (inst *Initialize) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The mint of the pool tokens, with the authority of the swap as mint authority.
+func (inst *Initialize) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetPoolFeeAccount sets the "poolFee" account.
+// The pool token account receiving the trading and withdrawal fees.
+func (inst *Initialize) SetPoolFeeAccount(poolFee ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(poolFee)
+	return inst
+}
+
+// GetPoolFeeAccount gets the "poolFee" account.
+// The pool token account receiving the trading and withdrawal fees.
+func (inst *Initialize) GetPoolFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetDestinationAccount sets the "destination" account.
+// The pool token account receiving the initial supply of pool tokens.
+func (inst *Initialize) SetDestinationAccount(destination ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(destination).WRITE()
+	return inst
+}
+
+// GetDestinationAccount gets the "destination" account.
+// The pool token account receiving the initial supply of pool tokens.
+func (inst *Initialize) GetDestinationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *Initialize) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *Initialize {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *Initialize) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Initialize) AccountNames() []string {
+	return []string{"swap", "authority", "tokenA", "tokenB", "poolMint", "poolFee", "destination", "tokenProgram"}
+}
+
+func (inst Initialize) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_Initialize),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst Initialize) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Initialize) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.Fees == nil {
+			return errors.New("Fees parameter is not set")
+		}
+		if inst.SwapCurve == nil {
+			return errors.New("SwapCurve parameter is not set")
+		}
+		if err := inst.Fees.validate(); err != nil {
+			return err
+		}
+		if err := inst.SwapCurve.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Swap is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.TokenA is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.TokenB is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.PoolFee is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.Destination is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *Initialize) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("Initialize")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("     Fees", inst.Fees))
+						paramsBranch.Child(ag_format.Param("SwapCurve", inst.SwapCurve))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("        swap", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("   authority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("      tokenA", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("      tokenB", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("    poolMint", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("     poolFee", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta(" destination", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("tokenProgram", inst.AccountMetaSlice.Get(7)))
+					})
+				})
+		})
+}
+
+func (obj Initialize) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `Fees` param:
+	err = encoder.Encode(obj.Fees)
+	if err != nil {
+		return err
+	}
+	// Serialize `SwapCurve` param:
+	err = encoder.Encode(obj.SwapCurve)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *Initialize) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `Fees`:
+	err = decoder.Decode(&obj.Fees)
+	if err != nil {
+		return err
+	}
+	// Deserialize `SwapCurve`:
+	err = decoder.Decode(&obj.SwapCurve)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewInitializeInstruction declares a new Initialize instruction with the provided parameters and accounts.
+func NewInitializeInstruction(
+	// Parameters:
+	fees Fees,
+	swapCurve SwapCurve,
+	// Accounts:
+	swap ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	tokenA ag_solanago.PublicKey,
+	tokenB ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+	poolFee ag_solanago.PublicKey,
+	destination ag_solanago.PublicKey,
+) *Initialize {
+	return NewInitializeInstructionBuilder().
+		SetFees(fees).
+		SetSwapCurve(swapCurve).
+		SetSwapAccount(swap).
+		SetAuthorityAccount(authority).
+		SetTokenAAccount(tokenA).
+		SetTokenBAccount(tokenB).
+		SetPoolMintAccount(poolMint).
+		SetPoolFeeAccount(poolFee).
+		SetDestinationAccount(destination)
+}
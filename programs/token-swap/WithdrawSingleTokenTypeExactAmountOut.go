@@ -0,0 +1,375 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"errors"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_format "github.com/gagliardetto/solana-go/text/format"
+	ag_treeout "github.com/gagliardetto/treeout"
+)
+
+// Burns pool tokens for an exact amount of one of the tokens.
+type WithdrawSingleTokenTypeExactAmountOut struct {
+	// The amount of tokens to receive.
+	DestinationTokenAmount *uint64
+	// The maximum amount of pool tokens to burn, including the withdrawal fee,
+	// to prevent excessive slippage.
+	MaximumPoolTokenAmount *uint64
+
+	// [0] = [] swap
+	// ··········· The swap.
+	//
+	// [1] = [] authority
+	// ··········· The authority of the swap, at FindSwapAuthorityAddress(swap).
+	//
+	// [2] = [SIGNER] userTransferAuthority
+	// ··········· The owner or the delegate of the user token accounts it debits.
+	//
+	// [3] = [WRITE] poolMint
+	// ··········· The mint of the pool tokens.
+	//
+	// [4] = [WRITE] source
+	// ··········· The user pool token account of the pool tokens to burn.
+	//
+	// [5] = [WRITE] tokenA
+	// ··········· The token account of token A of the swap.
+	//
+	// [6] = [WRITE] tokenB
+	// ··········· The token account of token B of the swap.
+	//
+	// [7] = [WRITE] destination
+	// ··········· The user token account receiving the tokens.
+	//
+	// [8] = [WRITE] poolFee
+	// ··········· The pool token account receiving the withdrawal fee.
+	//
+	// [9] = [] tokenProgram
+	// ··········· The token program.
+	ag_solanago.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewWithdrawSingleTokenTypeExactAmountOutInstructionBuilder creates a new `WithdrawSingleTokenTypeExactAmountOut` instruction builder.
+func NewWithdrawSingleTokenTypeExactAmountOutInstructionBuilder() *WithdrawSingleTokenTypeExactAmountOut {
+	nd := &WithdrawSingleTokenTypeExactAmountOut{
+		AccountMetaSlice: make(ag_solanago.AccountMetaSlice, 10),
+	}
+	nd.AccountMetaSlice[9] = ag_solanago.Meta(ag_solanago.TokenProgramID)
+	return nd
+}
+
+// SetDestinationTokenAmount sets the "destinationTokenAmount" parameter.
+// The amount of tokens to receive.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetDestinationTokenAmount(destinationTokenAmount uint64) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.DestinationTokenAmount = &destinationTokenAmount
+	return inst
+}
+
+// SetMaximumPoolTokenAmount sets the "maximumPoolTokenAmount" parameter.
+// The maximum amount of pool tokens to burn, including the withdrawal fee,
+// to prevent excessive slippage.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetMaximumPoolTokenAmount(maximumPoolTokenAmount uint64) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.MaximumPoolTokenAmount = &maximumPoolTokenAmount
+	return inst
+}
+
+// SetSwapAccount sets the "swap" account.
+// The swap.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetSwapAccount(swap ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[0] = ag_solanago.Meta(swap)
+	return inst
+}
+
+// GetSwapAccount gets the "swap" account.
+// The swap.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetSwapAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(0)
+}
+
+// SetAuthorityAccount sets the "authority" account.
+// The authority of the swap, at FindSwapAuthorityAddress(swap).
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetAuthorityAccount(authority ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[1] = ag_solanago.Meta(authority)
+	return inst
+}
+
+// GetAuthorityAccount gets the "authority" account.
+// The authority of the swap, at FindSwapAuthorityAddress(swap).
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(1)
+}
+
+// SetUserTransferAuthorityAccount sets the "userTransferAuthority" account.
+// The owner or the delegate of the user token accounts it debits.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetUserTransferAuthorityAccount(userTransferAuthority ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[2] = ag_solanago.Meta(userTransferAuthority).SIGNER()
+	return inst
+}
+
+// GetUserTransferAuthorityAccount gets the "userTransferAuthority" account.
+// The owner or the delegate of the user token accounts it debits.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetUserTransferAuthorityAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(2)
+}
+
+// SetPoolMintAccount sets the "poolMint" account.
+// The mint of the pool tokens.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetPoolMintAccount(poolMint ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[3] = ag_solanago.Meta(poolMint).WRITE()
+	return inst
+}
+
+// GetPoolMintAccount gets the "poolMint" account.
+// The mint of the pool tokens.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetPoolMintAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(3)
+}
+
+// SetSourceAccount sets the "source" account.
+// The user pool token account of the pool tokens to burn.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetSourceAccount(source ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[4] = ag_solanago.Meta(source).WRITE()
+	return inst
+}
+
+// GetSourceAccount gets the "source" account.
+// The user pool token account of the pool tokens to burn.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetSourceAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(4)
+}
+
+// SetTokenAAccount sets the "tokenA" account.
+// The token account of token A of the swap.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetTokenAAccount(tokenA ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[5] = ag_solanago.Meta(tokenA).WRITE()
+	return inst
+}
+
+// GetTokenAAccount gets the "tokenA" account.
+// The token account of token A of the swap.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetTokenAAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(5)
+}
+
+// SetTokenBAccount sets the "tokenB" account.
+// The token account of token B of the swap.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetTokenBAccount(tokenB ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[6] = ag_solanago.Meta(tokenB).WRITE()
+	return inst
+}
+
+// GetTokenBAccount gets the "tokenB" account.
+// The token account of token B of the swap.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetTokenBAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(6)
+}
+
+// SetDestinationAccount sets the "destination" account.
+// The user token account receiving the tokens.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetDestinationAccount(destination ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[7] = ag_solanago.Meta(destination).WRITE()
+	return inst
+}
+
+// GetDestinationAccount gets the "destination" account.
+// The user token account receiving the tokens.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetDestinationAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(7)
+}
+
+// SetPoolFeeAccount sets the "poolFee" account.
+// The pool token account receiving the withdrawal fee.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetPoolFeeAccount(poolFee ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[8] = ag_solanago.Meta(poolFee).WRITE()
+	return inst
+}
+
+// GetPoolFeeAccount gets the "poolFee" account.
+// The pool token account receiving the withdrawal fee.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetPoolFeeAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(8)
+}
+
+// SetTokenProgramAccount sets the "tokenProgram" account.
+// The token program.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) SetTokenProgramAccount(tokenProgram ag_solanago.PublicKey) *WithdrawSingleTokenTypeExactAmountOut {
+	inst.AccountMetaSlice[9] = ag_solanago.Meta(tokenProgram)
+	return inst
+}
+
+// GetTokenProgramAccount gets the "tokenProgram" account.
+// The token program.
+func (inst *WithdrawSingleTokenTypeExactAmountOut) GetTokenProgramAccount() *ag_solanago.AccountMeta {
+	return inst.AccountMetaSlice.Get(9)
+}
+
+// AccountNames returns the names of the accounts, in order.
+func (inst WithdrawSingleTokenTypeExactAmountOut) AccountNames() []string {
+	return []string{"swap", "authority", "userTransferAuthority", "poolMint", "source", "tokenA", "tokenB", "destination", "poolFee", "tokenProgram"}
+}
+
+func (inst WithdrawSingleTokenTypeExactAmountOut) Build() *Instruction {
+	return &Instruction{BaseVariant: ag_binary.BaseVariant{
+		Impl:   inst,
+		TypeID: ag_binary.TypeIDFromUint8(Instruction_WithdrawSingleTokenTypeExactAmountOut),
+	}}
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts;
+// if there is a validation error, it returns the error.
+// Otherwise, it builds and returns the instruction.
+func (inst WithdrawSingleTokenTypeExactAmountOut) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *WithdrawSingleTokenTypeExactAmountOut) Validate() error {
+	// Check whether all (required) parameters are set:
+	{
+		if inst.DestinationTokenAmount == nil {
+			return errors.New("DestinationTokenAmount parameter is not set")
+		}
+		if inst.MaximumPoolTokenAmount == nil {
+			return errors.New("MaximumPoolTokenAmount parameter is not set")
+		}
+	}
+
+	// Check whether all (required) accounts are set:
+	{
+		if inst.AccountMetaSlice[0] == nil {
+			return errors.New("accounts.Swap is not set")
+		}
+		if inst.AccountMetaSlice[1] == nil {
+			return errors.New("accounts.Authority is not set")
+		}
+		if inst.AccountMetaSlice[2] == nil {
+			return errors.New("accounts.UserTransferAuthority is not set")
+		}
+		if inst.AccountMetaSlice[3] == nil {
+			return errors.New("accounts.PoolMint is not set")
+		}
+		if inst.AccountMetaSlice[4] == nil {
+			return errors.New("accounts.Source is not set")
+		}
+		if inst.AccountMetaSlice[5] == nil {
+			return errors.New("accounts.TokenA is not set")
+		}
+		if inst.AccountMetaSlice[6] == nil {
+			return errors.New("accounts.TokenB is not set")
+		}
+		if inst.AccountMetaSlice[7] == nil {
+			return errors.New("accounts.Destination is not set")
+		}
+		if inst.AccountMetaSlice[8] == nil {
+			return errors.New("accounts.PoolFee is not set")
+		}
+		if inst.AccountMetaSlice[9] == nil {
+			return errors.New("accounts.TokenProgram is not set")
+		}
+	}
+	return nil
+}
+
+func (inst *WithdrawSingleTokenTypeExactAmountOut) EncodeToTree(parent ag_treeout.Branches) {
+	parent.Child(ag_format.Program(ProgramName, ProgramID)).
+		//
+		ParentFunc(func(programBranch ag_treeout.Branches) {
+			programBranch.Child(ag_format.Instruction("WithdrawSingleTokenTypeExactAmountOut")).
+				//
+				ParentFunc(func(instructionBranch ag_treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params").ParentFunc(func(paramsBranch ag_treeout.Branches) {
+						paramsBranch.Child(ag_format.Param("DestinationTokenAmount", inst.DestinationTokenAmount))
+						paramsBranch.Child(ag_format.Param("MaximumPoolTokenAmount", inst.MaximumPoolTokenAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child("Accounts").ParentFunc(func(accountsBranch ag_treeout.Branches) {
+						accountsBranch.Child(ag_format.Meta("                 swap", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(ag_format.Meta("            authority", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(ag_format.Meta("userTransferAuthority", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(ag_format.Meta("             poolMint", inst.AccountMetaSlice.Get(3)))
+						accountsBranch.Child(ag_format.Meta("               source", inst.AccountMetaSlice.Get(4)))
+						accountsBranch.Child(ag_format.Meta("               tokenA", inst.AccountMetaSlice.Get(5)))
+						accountsBranch.Child(ag_format.Meta("               tokenB", inst.AccountMetaSlice.Get(6)))
+						accountsBranch.Child(ag_format.Meta("          destination", inst.AccountMetaSlice.Get(7)))
+						accountsBranch.Child(ag_format.Meta("              poolFee", inst.AccountMetaSlice.Get(8)))
+						accountsBranch.Child(ag_format.Meta("         tokenProgram", inst.AccountMetaSlice.Get(9)))
+					})
+				})
+		})
+}
+
+func (obj WithdrawSingleTokenTypeExactAmountOut) MarshalWithEncoder(encoder *ag_binary.Encoder) (err error) {
+	// Serialize `DestinationTokenAmount` param:
+	err = encoder.Encode(obj.DestinationTokenAmount)
+	if err != nil {
+		return err
+	}
+	// Serialize `MaximumPoolTokenAmount` param:
+	err = encoder.Encode(obj.MaximumPoolTokenAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (obj *WithdrawSingleTokenTypeExactAmountOut) UnmarshalWithDecoder(decoder *ag_binary.Decoder) (err error) {
+	// Deserialize `DestinationTokenAmount`:
+	err = decoder.Decode(&obj.DestinationTokenAmount)
+	if err != nil {
+		return err
+	}
+	// Deserialize `MaximumPoolTokenAmount`:
+	err = decoder.Decode(&obj.MaximumPoolTokenAmount)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewWithdrawSingleTokenTypeExactAmountOutInstruction declares a new WithdrawSingleTokenTypeExactAmountOut instruction with the provided parameters and accounts.
+func NewWithdrawSingleTokenTypeExactAmountOutInstruction(
+	// Parameters:
+	destinationTokenAmount uint64,
+	maximumPoolTokenAmount uint64,
+	// Accounts:
+	swap ag_solanago.PublicKey,
+	authority ag_solanago.PublicKey,
+	userTransferAuthority ag_solanago.PublicKey,
+	poolMint ag_solanago.PublicKey,
+	source ag_solanago.PublicKey,
+	tokenA ag_solanago.PublicKey,
+	tokenB ag_solanago.PublicKey,
+	destination ag_solanago.PublicKey,
+	poolFee ag_solanago.PublicKey,
+) *WithdrawSingleTokenTypeExactAmountOut {
+	return NewWithdrawSingleTokenTypeExactAmountOutInstructionBuilder().
+		SetDestinationTokenAmount(destinationTokenAmount).
+		SetMaximumPoolTokenAmount(maximumPoolTokenAmount).
+		SetSwapAccount(swap).
+		SetAuthorityAccount(authority).
+		SetUserTransferAuthorityAccount(userTransferAuthority).
+		SetPoolMintAccount(poolMint).
+		SetSourceAccount(source).
+		SetTokenAAccount(tokenA).
+		SetTokenBAccount(tokenB).
+		SetDestinationAccount(destination).
+		SetPoolFeeAccount(poolFee)
+}
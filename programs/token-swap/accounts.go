@@ -0,0 +1,102 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	ag_solanago "github.com/gagliardetto/solana-go"
+)
+
+// SWAP_LEN is the size of the data of a swap account: the version of its
+// state, and the state.
+const SWAP_LEN = 324
+
+// SWAP_VERSION_V1 is the version of the state of the swaps.
+const SWAP_VERSION_V1 = 1
+
+// FeeOwner is the owner that the deployed program requires for the pool fee
+// accounts of the new swaps.
+var FeeOwner = ag_solanago.TokenSwapFeeOwner
+
+// SwapV1 is the state of a swap account.
+type SwapV1 struct {
+	IsInitialized bool
+
+	// The bump seed of the authority of the swap.
+	BumpSeed uint8
+
+	// The token program of the tokens of the swap.
+	TokenProgramID ag_solanago.PublicKey
+
+	// The token accounts of the swap, owned by its authority.
+	TokenA ag_solanago.PublicKey
+	TokenB ag_solanago.PublicKey
+
+	PoolMint   ag_solanago.PublicKey
+	TokenAMint ag_solanago.PublicKey
+	TokenBMint ag_solanago.PublicKey
+
+	// The pool token account receiving the fees.
+	PoolFeeAccount ag_solanago.PublicKey
+
+	Fees      Fees
+	SwapCurve SwapCurve
+}
+
+// Authority returns the authority of the swap at the address.
+func (swap *SwapV1) Authority(address ag_solanago.PublicKey) (ag_solanago.PublicKey, error) {
+	return ag_solanago.CreateProgramAddress([][]byte{address[:], {swap.BumpSeed}}, ProgramID)
+}
+
+// FindSwapAuthorityAddress returns the authority of the swap at the address,
+// which owns its token accounts and mints its pool tokens.
+func FindSwapAuthorityAddress(swap ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindProgramAddress([][]byte{swap[:]}, ProgramID)
+}
+
+// FindPoolFeeAddress returns the associated token account of FeeOwner for
+// the pool mint, to use as the pool fee account of a new swap.
+func FindPoolFeeAddress(poolMint ag_solanago.PublicKey) (ag_solanago.PublicKey, uint8, error) {
+	return ag_solanago.FindAssociatedTokenAddress(FeeOwner, poolMint)
+}
+
+// DecodeSwap decodes the data of a swap account.
+func DecodeSwap(data []byte) (*SwapV1, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("unable to decode swap: no data")
+	}
+	if data[0] != SWAP_VERSION_V1 {
+		return nil, fmt.Errorf("unable to decode swap: unsupported version %d", data[0])
+	}
+	swap := new(SwapV1)
+	if err := ag_binary.NewBinDecoder(data[1:]).Decode(swap); err != nil {
+		return nil, fmt.Errorf("unable to decode swap: %w", err)
+	}
+	return swap, nil
+}
+
+func registerAccountDecoders(programID ag_solanago.PublicKey) {
+	ag_solanago.RegisterAccountDecoder(programID, ag_solanago.AccountSizeMatcher(SWAP_LEN), decodeSwapAccount)
+}
+
+func decodeSwapAccount(data []byte) (interface{}, error) {
+	out, err := DecodeSwap(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
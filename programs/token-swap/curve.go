@@ -0,0 +1,327 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The errors of the program that the quotes reproduce.
+var (
+	ErrZeroTradingTokens         = errors.New("given pool token amount results in zero trading tokens")
+	ErrUnsupportedCurveOperation = errors.New("the operation cannot be performed on the given curve")
+)
+
+// INITIAL_SWAP_POOL_AMOUNT is the supply of pool tokens minted to the first
+// depositor of a swap.
+const INITIAL_SWAP_POOL_AMOUNT = 1_000_000_000
+
+// TradeDirection is the direction of a swap, or the token of a single token
+// deposit or withdrawal: A for AtoB, B for BtoA.
+type TradeDirection uint8
+
+const (
+	TradeDirectionAtoB TradeDirection = iota
+	TradeDirectionBtoA
+)
+
+// calculateFee returns the fee on the amount; a non-zero fee is at least
+// one token.
+func calculateFee(amount *big.Int, numerator, denominator uint64) *big.Int {
+	if numerator == 0 || amount.Sign() == 0 {
+		return big.NewInt(0)
+	}
+	fee := div(mul(amount, u128(numerator)), u128(denominator))
+	if fee.Sign() == 0 {
+		return big.NewInt(1)
+	}
+	return fee
+}
+
+// preFeeAmount returns the amount that is postFeeAmount after the fee.
+func preFeeAmount(postFeeAmount *big.Int, numerator, denominator *big.Int) *big.Int {
+	switch {
+	case numerator.Sign() == 0 || denominator.Sign() == 0:
+		return postFeeAmount
+	case numerator.Cmp(denominator) == 0 || postFeeAmount.Sign() == 0:
+		return big.NewInt(0)
+	default:
+		difference := sub(denominator, numerator)
+		return div(sub(add(mul(postFeeAmount, denominator), difference), big.NewInt(1)), difference)
+	}
+}
+
+func (fees Fees) tradingFee(amount *big.Int) *big.Int {
+	return calculateFee(amount, fees.TradeFeeNumerator, fees.TradeFeeDenominator)
+}
+
+func (fees Fees) ownerTradingFee(amount *big.Int) *big.Int {
+	return calculateFee(amount, fees.OwnerTradeFeeNumerator, fees.OwnerTradeFeeDenominator)
+}
+
+// preTradingFeeAmount returns the amount that is postFeeAmount after the
+// trading fees.
+func (fees Fees) preTradingFeeAmount(postFeeAmount *big.Int) *big.Int {
+	tradeNumerator, tradeDenominator := u128(fees.TradeFeeNumerator), u128(fees.TradeFeeDenominator)
+	ownerNumerator, ownerDenominator := u128(fees.OwnerTradeFeeNumerator), u128(fees.OwnerTradeFeeDenominator)
+	switch {
+	case fees.TradeFeeNumerator == 0 || fees.TradeFeeDenominator == 0:
+		return preFeeAmount(postFeeAmount, ownerNumerator, ownerDenominator)
+	case fees.OwnerTradeFeeNumerator == 0 || fees.OwnerTradeFeeDenominator == 0:
+		return preFeeAmount(postFeeAmount, tradeNumerator, tradeDenominator)
+	default:
+		return preFeeAmount(
+			postFeeAmount,
+			add(mul(tradeNumerator, ownerDenominator), mul(ownerNumerator, tradeDenominator)),
+			mul(tradeDenominator, ownerDenominator),
+		)
+	}
+}
+
+// TradingFee returns the trading fee, kept by the pool, on an amount of
+// source tokens.
+func (fees Fees) TradingFee(amount uint64) (fee uint64, err error) {
+	defer recoverAs(&err, ErrCalculationFailure)
+	return toU64(fees.tradingFee(u128(amount))), nil
+}
+
+// OwnerTradingFee returns the owner trading fee on an amount of source tokens.
+func (fees Fees) OwnerTradingFee(amount uint64) (fee uint64, err error) {
+	defer recoverAs(&err, ErrCalculationFailure)
+	return toU64(fees.ownerTradingFee(u128(amount))), nil
+}
+
+// OwnerWithdrawFee returns the withdraw fee on an amount of pool tokens.
+func (fees Fees) OwnerWithdrawFee(poolTokens uint64) (fee uint64, err error) {
+	defer recoverAs(&err, ErrCalculationFailure)
+	return toU64(calculateFee(u128(poolTokens), fees.OwnerWithdrawFeeNumerator, fees.OwnerWithdrawFeeDenominator)), nil
+}
+
+// HostFee returns the share of the host of the owner fee, in pool tokens.
+func (fees Fees) HostFee(ownerFee uint64) (fee uint64, err error) {
+	defer recoverAs(&err, ErrCalculationFailure)
+	return toU64(calculateFee(u128(ownerFee), fees.HostFeeNumerator, fees.HostFeeDenominator)), nil
+}
+
+// calculator holds the math of a curve, on the amounts of the swap; it
+// aborts where the checked arithmetic of the program fails, or returns nil
+// where the program returns None from a successful calculation.
+type calculator interface {
+	swapWithoutFees(sourceAmount, swapSourceAmount, swapDestinationAmount *big.Int, direction TradeDirection) (sourceAmountSwapped, destinationAmountSwapped *big.Int)
+	poolTokensToTradingTokens(poolTokens, poolTokenSupply, swapTokenAAmount, swapTokenBAmount *big.Int, ceiling bool) (tokenAAmount, tokenBAmount *big.Int)
+	depositSingleTokenType(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int
+	withdrawSingleTokenTypeExactOut(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int
+	allowsDeposits() bool
+}
+
+func (curve SwapCurve) calculator() (calculator, error) {
+	switch curve.CurveType {
+	case CurveTypeConstantProduct:
+		return constantProduct{}, nil
+	case CurveTypeConstantPrice:
+		return constantPrice{tokenBPrice: u128(curve.TokenBPrice)}, nil
+	case CurveTypeOffset:
+		return offset{tokenBOffset: u128(curve.TokenBOffset)}, nil
+	default:
+		return nil, ErrUnsupportedCurveOperation
+	}
+}
+
+func swapAmounts(direction TradeDirection, swapTokenAAmount, swapTokenBAmount *big.Int) (source, destination *big.Int) {
+	if direction == TradeDirectionAtoB {
+		return swapTokenAAmount, swapTokenBAmount
+	}
+	return swapTokenBAmount, swapTokenAAmount
+}
+
+type constantProduct struct{}
+
+func constantProductSwap(sourceAmount, swapSourceAmount, swapDestinationAmount *big.Int) (*big.Int, *big.Int) {
+	invariant := mul(swapSourceAmount, swapDestinationAmount)
+	newSwapDestinationAmount, newSwapSourceAmount := ceilDiv(invariant, add(swapSourceAmount, sourceAmount))
+	sourceAmountSwapped := sub(newSwapSourceAmount, swapSourceAmount)
+	destinationAmountSwapped := sub(swapDestinationAmount, newSwapDestinationAmount)
+	if destinationAmountSwapped.Sign() == 0 {
+		return nil, nil
+	}
+	return sourceAmountSwapped, destinationAmountSwapped
+}
+
+func (constantProduct) swapWithoutFees(sourceAmount, swapSourceAmount, swapDestinationAmount *big.Int, _ TradeDirection) (*big.Int, *big.Int) {
+	return constantProductSwap(sourceAmount, swapSourceAmount, swapDestinationAmount)
+}
+
+func constantProductPoolTokensToTradingTokens(poolTokens, poolTokenSupply, swapTokenAAmount, swapTokenBAmount *big.Int, ceiling bool) (*big.Int, *big.Int) {
+	tokenAAmount := div(mul(poolTokens, swapTokenAAmount), poolTokenSupply)
+	tokenBAmount := div(mul(poolTokens, swapTokenBAmount), poolTokenSupply)
+	if ceiling {
+		// Rounding zero amounts up would take too much for tiny amounts of pool tokens.
+		if rem(mul(poolTokens, swapTokenAAmount), poolTokenSupply).Sign() > 0 && tokenAAmount.Sign() > 0 {
+			tokenAAmount = add(tokenAAmount, big.NewInt(1))
+		}
+		if rem(mul(poolTokens, swapTokenBAmount), poolTokenSupply).Sign() > 0 && tokenBAmount.Sign() > 0 {
+			tokenBAmount = add(tokenBAmount, big.NewInt(1))
+		}
+	}
+	return tokenAAmount, tokenBAmount
+}
+
+func (constantProduct) poolTokensToTradingTokens(poolTokens, poolTokenSupply, swapTokenAAmount, swapTokenBAmount *big.Int, ceiling bool) (*big.Int, *big.Int) {
+	return constantProductPoolTokensToTradingTokens(poolTokens, poolTokenSupply, swapTokenAAmount, swapTokenBAmount, ceiling)
+}
+
+func roundPrecise(p precise, ceiling bool) *big.Int {
+	if ceiling {
+		return checkU128(p.ceiling())
+	}
+	return checkU128(p.floor())
+}
+
+// The pool tokens of a single token deposit are the ones of the share of the
+// deposit in the pool after swapping half of it: supply * (sqrt(1 + ratio) - 1).
+func constantProductDepositSingleTokenType(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	if sourceAmount.Sign() == 0 {
+		return big.NewInt(0)
+	}
+	swapSourceAmount, _ := swapAmounts(direction, swapTokenAAmount, swapTokenBAmount)
+	one := newPrecise(big.NewInt(1))
+	ratio := newPrecise(sourceAmount).div(newPrecise(swapSourceAmount))
+	root := one.add(ratio).sqrt().sub(one)
+	return roundPrecise(newPrecise(poolSupply).mul(root), ceiling)
+}
+
+// The pool tokens of a single token withdrawal: supply * (1 - sqrt(1 - ratio)).
+func constantProductWithdrawSingleTokenTypeExactOut(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	if sourceAmount.Sign() == 0 {
+		return big.NewInt(0)
+	}
+	swapSourceAmount, _ := swapAmounts(direction, swapTokenAAmount, swapTokenBAmount)
+	one := newPrecise(big.NewInt(1))
+	ratio := newPrecise(sourceAmount).div(newPrecise(swapSourceAmount))
+	root := one.sub(one.sub(ratio).sqrt())
+	return roundPrecise(newPrecise(poolSupply).mul(root), ceiling)
+}
+
+func (constantProduct) depositSingleTokenType(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	return constantProductDepositSingleTokenType(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply, direction, ceiling)
+}
+
+func (constantProduct) withdrawSingleTokenTypeExactOut(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	return constantProductWithdrawSingleTokenTypeExactOut(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply, direction, ceiling)
+}
+
+func (constantProduct) allowsDeposits() bool {
+	return true
+}
+
+type constantPrice struct {
+	tokenBPrice *big.Int
+}
+
+func (curve constantPrice) swapWithoutFees(sourceAmount, _, _ *big.Int, direction TradeDirection) (*big.Int, *big.Int) {
+	var sourceAmountSwapped, destinationAmountSwapped *big.Int
+	if direction == TradeDirectionBtoA {
+		sourceAmountSwapped, destinationAmountSwapped = sourceAmount, mul(sourceAmount, curve.tokenBPrice)
+	} else {
+		// The remainder of the purchase of token B is not taken, but the
+		// fees on it are.
+		destinationAmountSwapped = div(sourceAmount, curve.tokenBPrice)
+		sourceAmountSwapped = sub(sourceAmount, rem(sourceAmount, curve.tokenBPrice))
+	}
+	if sourceAmountSwapped.Sign() == 0 || destinationAmountSwapped.Sign() == 0 {
+		return nil, nil
+	}
+	return sourceAmountSwapped, destinationAmountSwapped
+}
+
+// normalizedValue returns half the value of the pool, in token A.
+func (curve constantPrice) normalizedValue(swapTokenAAmount, swapTokenBAmount *big.Int) *big.Int {
+	return div(add(swapTokenAAmount, mul(swapTokenBAmount, curve.tokenBPrice)), big.NewInt(2))
+}
+
+func (curve constantPrice) poolTokensToTradingTokens(poolTokens, poolTokenSupply, swapTokenAAmount, swapTokenBAmount *big.Int, ceiling bool) (*big.Int, *big.Int) {
+	poolValue := mul(poolTokens, curve.normalizedValue(swapTokenAAmount, swapTokenBAmount))
+	if !ceiling {
+		return div(poolValue, poolTokenSupply), div(div(poolValue, curve.tokenBPrice), poolTokenSupply)
+	}
+	tokenAAmount, _ := ceilDiv(poolValue, poolTokenSupply)
+	poolValueAsTokenB, _ := ceilDiv(poolValue, curve.tokenBPrice)
+	tokenBAmount, _ := ceilDiv(poolValueAsTokenB, poolTokenSupply)
+	return tokenAAmount, tokenBAmount
+}
+
+// tradingTokensToPoolTokens returns the pool tokens worth the amount of
+// tokens, in proportion of the value of the pool.
+func (curve constantPrice) tradingTokensToPoolTokens(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	givenValue := sourceAmount
+	if direction == TradeDirectionBtoA {
+		givenValue = checkU256(new(big.Int).Mul(sourceAmount, curve.tokenBPrice))
+	}
+	totalValue := checkU256(new(big.Int).Add(new(big.Int).Mul(swapTokenBAmount, curve.tokenBPrice), swapTokenAAmount))
+	poolValue := checkU256(new(big.Int).Mul(poolSupply, givenValue))
+	if totalValue.Sign() == 0 {
+		panic(errAbort{})
+	}
+	if !ceiling {
+		return checkU128(new(big.Int).Quo(poolValue, totalValue))
+	}
+	poolTokens, _ := ceilDiv(poolValue, totalValue)
+	return poolTokens
+}
+
+func (curve constantPrice) depositSingleTokenType(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	return curve.tradingTokensToPoolTokens(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply, direction, ceiling)
+}
+
+func (curve constantPrice) withdrawSingleTokenTypeExactOut(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	return curve.tradingTokensToPoolTokens(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply, direction, ceiling)
+}
+
+func (constantPrice) allowsDeposits() bool {
+	return true
+}
+
+// offset is a constant product curve with tokenBOffset virtual tokens B.
+type offset struct {
+	tokenBOffset *big.Int
+}
+
+func (curve offset) swapWithoutFees(sourceAmount, swapSourceAmount, swapDestinationAmount *big.Int, direction TradeDirection) (*big.Int, *big.Int) {
+	if direction == TradeDirectionAtoB {
+		swapDestinationAmount = add(swapDestinationAmount, curve.tokenBOffset)
+	} else {
+		swapSourceAmount = add(swapSourceAmount, curve.tokenBOffset)
+	}
+	return constantProductSwap(sourceAmount, swapSourceAmount, swapDestinationAmount)
+}
+
+func (offset) poolTokensToTradingTokens(poolTokens, poolTokenSupply, swapTokenAAmount, swapTokenBAmount *big.Int, ceiling bool) (*big.Int, *big.Int) {
+	return constantProductPoolTokensToTradingTokens(poolTokens, poolTokenSupply, swapTokenAAmount, swapTokenBAmount, ceiling)
+}
+
+func (curve offset) depositSingleTokenType(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	return constantProductDepositSingleTokenType(sourceAmount, swapTokenAAmount, add(swapTokenBAmount, curve.tokenBOffset), poolSupply, direction, ceiling)
+}
+
+func (curve offset) withdrawSingleTokenTypeExactOut(sourceAmount, swapTokenAAmount, swapTokenBAmount, poolSupply *big.Int, direction TradeDirection, ceiling bool) *big.Int {
+	return constantProductWithdrawSingleTokenTypeExactOut(sourceAmount, swapTokenAAmount, add(swapTokenBAmount, curve.tokenBOffset), poolSupply, direction, ceiling)
+}
+
+// The program doesn't allow deposits to offset curves, which would make
+// the virtual tokens withdrawable.
+func (offset) allowsDeposits() bool {
+	return false
+}
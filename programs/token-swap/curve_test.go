@@ -0,0 +1,139 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"testing"
+
+	ag_require "github.com/stretchr/testify/require"
+)
+
+func TestConstantProductSwap(t *testing.T) {
+	result, err := ConstantProductCurve().Swap(100, 1000, 50000, TradeDirectionAtoB, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, &SwapResult{
+		NewSwapSourceAmount:      1100,
+		NewSwapDestinationAmount: 45455,
+		SourceAmountSwapped:      100,
+		DestinationAmountSwapped: 4545,
+	}, result)
+
+	// Both fees round up to one token.
+	result, err = ConstantProductCurve().Swap(100, 1000, 50000, TradeDirectionAtoB, testFees)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, &SwapResult{
+		NewSwapSourceAmount:      1100,
+		NewSwapDestinationAmount: 45538,
+		SourceAmountSwapped:      100,
+		DestinationAmountSwapped: 4462,
+		TradeFee:                 1,
+		OwnerFee:                 1,
+	}, result)
+
+	_, err = ConstantProductCurve().Swap(1, 50000, 1000, TradeDirectionBtoA, Fees{})
+	ag_require.ErrorIs(t, err, ErrZeroTradingTokens)
+}
+
+func TestConstantPriceSwap(t *testing.T) {
+	result, err := ConstantPriceCurve(10).Swap(105, 1000, 1000, TradeDirectionAtoB, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(100), result.SourceAmountSwapped)
+	ag_require.Equal(t, uint64(10), result.DestinationAmountSwapped)
+
+	result, err = ConstantPriceCurve(10).Swap(5, 1000, 1000, TradeDirectionBtoA, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(5), result.SourceAmountSwapped)
+	ag_require.Equal(t, uint64(50), result.DestinationAmountSwapped)
+
+	_, err = ConstantPriceCurve(10).Swap(9, 1000, 1000, TradeDirectionAtoB, Fees{})
+	ag_require.ErrorIs(t, err, ErrZeroTradingTokens)
+}
+
+func TestOffsetSwap(t *testing.T) {
+	result, err := OffsetCurve(1000).Swap(100, 1000, 500, TradeDirectionAtoB, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(100), result.SourceAmountSwapped)
+	ag_require.Equal(t, uint64(136), result.DestinationAmountSwapped)
+
+	_, _, err = OffsetCurve(1000).DepositAllTokenTypes(10, 1000, 1000, 500)
+	ag_require.ErrorIs(t, err, ErrUnsupportedCurveOperation)
+	_, err = StableCurve(100).Swap(100, 1000, 1000, TradeDirectionAtoB, Fees{})
+	ag_require.ErrorIs(t, err, ErrUnsupportedCurveOperation)
+}
+
+func TestDepositWithdrawAllTokenTypes(t *testing.T) {
+	curve := ConstantProductCurve()
+	a, b, err := curve.DepositAllTokenTypes(10, 1000, 1000, 50000)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []uint64{10, 500}, []uint64{a, b})
+
+	// Deposits round up.
+	a, b, err = curve.DepositAllTokenTypes(3, 7, 10, 70)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []uint64{5, 30}, []uint64{a, b})
+
+	// The first deposit takes all the tokens of the swap.
+	a, b, err = curve.DepositAllTokenTypes(1, 0, 1000, 50000)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []uint64{1000, 50000}, []uint64{a, b})
+
+	fees := Fees{OwnerWithdrawFeeNumerator: 1, OwnerWithdrawFeeDenominator: 100}
+	a, b, fee, err := curve.WithdrawAllTokenTypes(100, 1000, 1000, 50000, fees)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, []uint64{99, 4950, 1}, []uint64{a, b, fee})
+
+	_, _, _, err = curve.WithdrawAllTokenTypes(1, 10000, 1000, 50000, Fees{})
+	ag_require.ErrorIs(t, err, ErrZeroTradingTokens)
+}
+
+func TestSingleTokenType(t *testing.T) {
+	curve := ConstantProductCurve()
+	// 1000 * (sqrt(1.1) - 1) = 48.8
+	poolTokens, err := curve.DepositSingleTokenTypeExactAmountIn(100, 1000, 50000, 1000, TradeDirectionAtoB, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(48), poolTokens)
+
+	poolTokens, err = curve.DepositSingleTokenTypeExactAmountIn(100, 1000, 50000, 0, TradeDirectionAtoB, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(INITIAL_SWAP_POOL_AMOUNT), poolTokens)
+
+	// 1000 * (1 - sqrt(0.9)) = 51.3
+	poolTokens, err = curve.WithdrawSingleTokenTypeExactAmountOut(100, 1000, 50000, 1000, TradeDirectionAtoB, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(52), poolTokens)
+
+	// Constant price: 100 of the 1000 + 100 * 10 tokens A of value.
+	poolTokens, err = ConstantPriceCurve(10).DepositSingleTokenTypeExactAmountIn(100, 1000, 100, 1000, TradeDirectionAtoB, Fees{})
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(50), poolTokens)
+}
+
+func TestFees(t *testing.T) {
+	fee, err := testFees.TradingFee(100000)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(250), fee)
+
+	fee, err = testFees.OwnerTradingFee(100000)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(50), fee)
+
+	fee, err = testFees.HostFee(50)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(10), fee)
+
+	fee, err = testFees.OwnerWithdrawFee(100000)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, uint64(0), fee)
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"encoding/binary"
+	"testing"
+
+	ag_solanago "github.com/gagliardetto/solana-go"
+	ag_require "github.com/stretchr/testify/require"
+)
+
+var testFees = Fees{
+	TradeFeeNumerator:           25,
+	TradeFeeDenominator:         10000,
+	OwnerTradeFeeNumerator:      5,
+	OwnerTradeFeeDenominator:    10000,
+	OwnerWithdrawFeeNumerator:   0,
+	OwnerWithdrawFeeDenominator: 0,
+	HostFeeNumerator:            20,
+	HostFeeDenominator:          100,
+}
+
+func appendFees(data []byte, fees Fees) []byte {
+	for _, v := range []uint64{
+		fees.TradeFeeNumerator, fees.TradeFeeDenominator,
+		fees.OwnerTradeFeeNumerator, fees.OwnerTradeFeeDenominator,
+		fees.OwnerWithdrawFeeNumerator, fees.OwnerWithdrawFeeDenominator,
+		fees.HostFeeNumerator, fees.HostFeeDenominator,
+	} {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	return data
+}
+
+func TestInitialize(t *testing.T) {
+	swap := ag_solanago.MPK("SWaPpA9LAaLfeLi3a68M4DjnLqgtticKg6CnyNwgAC8")
+	authority, _, err := FindSwapAuthorityAddress(swap)
+	ag_require.NoError(t, err)
+	tokenA, tokenB := ag_solanago.SysVarClockPubkey, ag_solanago.SysVarRentPubkey
+	poolMint, destination := ag_solanago.SysVarEpochSchedulePubkey, ag_solanago.SysVarFeesPubkey
+	poolFee, _, err := FindPoolFeeAddress(poolMint)
+	ag_require.NoError(t, err)
+
+	inst, err := NewInitializeInstruction(testFees, ConstantPriceCurve(10), swap, authority, tokenA, tokenB, poolMint, poolFee, destination).ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := appendFees([]byte{0}, testFees)
+	expected = append(expected, 1)
+	expected = binary.LittleEndian.AppendUint64(expected, 10)
+	expected = append(expected, make([]byte, 24)...)
+
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+	ag_require.Equal(t, []*ag_solanago.AccountMeta{
+		ag_solanago.Meta(swap).WRITE().SIGNER(),
+		ag_solanago.Meta(authority),
+		ag_solanago.Meta(tokenA),
+		ag_solanago.Meta(tokenB),
+		ag_solanago.Meta(poolMint).WRITE(),
+		ag_solanago.Meta(poolFee),
+		ag_solanago.Meta(destination).WRITE(),
+		ag_solanago.Meta(ag_solanago.TokenProgramID),
+	}, inst.Accounts())
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "Initialize", decoded.InstructionName())
+	initialize := decoded.Impl.(*Initialize)
+	ag_require.Equal(t, testFees, *initialize.Fees)
+	ag_require.Equal(t, ConstantPriceCurve(10), *initialize.SwapCurve)
+
+	_, err = NewInitializeInstruction(testFees, ConstantPriceCurve(0), swap, authority, tokenA, tokenB, poolMint, poolFee, destination).ValidateAndBuild()
+	ag_require.Error(t, err)
+}
+
+func TestSwap(t *testing.T) {
+	swap := ag_solanago.MPK("SWaPpA9LAaLfeLi3a68M4DjnLqgtticKg6CnyNwgAC8")
+	user := ag_solanago.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")
+	authority, _, err := FindSwapAuthorityAddress(swap)
+	ag_require.NoError(t, err)
+	a, b := ag_solanago.SysVarClockPubkey, ag_solanago.SysVarRentPubkey
+
+	builder := NewSwapInstruction(100, 4000, swap, authority, user, a, a, b, b, b, b)
+	inst, err := builder.ValidateAndBuild()
+	ag_require.NoError(t, err)
+
+	expected := []byte{1}
+	expected = binary.LittleEndian.AppendUint64(expected, 100)
+	expected = binary.LittleEndian.AppendUint64(expected, 4000)
+	data, err := inst.Data()
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, expected, data)
+	ag_require.Len(t, inst.Accounts(), 10)
+
+	builder.SetHostFeeAccount(user)
+	inst, err = builder.ValidateAndBuild()
+	ag_require.NoError(t, err)
+	ag_require.Len(t, inst.Accounts(), 11)
+	ag_require.Equal(t, ag_solanago.Meta(user).WRITE(), inst.Accounts()[10])
+
+	decoded, err := DecodeInstruction(inst.Accounts(), data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, "Swap", decoded.InstructionName())
+	ag_require.Equal(t, uint64(4000), *decoded.Impl.(*Swap).MinimumAmountOut)
+}
+
+func TestDecodeSwap(t *testing.T) {
+	keys := []ag_solanago.PublicKey{
+		ag_solanago.TokenProgramID,
+		ag_solanago.SysVarClockPubkey,
+		ag_solanago.SysVarRentPubkey,
+		ag_solanago.SysVarEpochSchedulePubkey,
+		ag_solanago.SysVarFeesPubkey,
+		ag_solanago.SysVarRecentBlockHashesPubkey,
+		ag_solanago.SysVarRewardsPubkey,
+	}
+	data := []byte{SWAP_VERSION_V1, 1, 254}
+	for _, key := range keys {
+		data = append(data, key[:]...)
+	}
+	data = appendFees(data, testFees)
+	data = append(data, byte(CurveTypeOffset))
+	data = binary.LittleEndian.AppendUint64(data, 1000)
+	data = append(data, make([]byte, 24)...)
+	ag_require.Len(t, data, SWAP_LEN)
+
+	decoded, err := ag_solanago.DecodeAccount(ProgramID, data)
+	ag_require.NoError(t, err)
+	ag_require.Equal(t, &SwapV1{
+		IsInitialized:  true,
+		BumpSeed:       254,
+		TokenProgramID: keys[0],
+		TokenA:         keys[1],
+		TokenB:         keys[2],
+		PoolMint:       keys[3],
+		TokenAMint:     keys[4],
+		TokenBMint:     keys[5],
+		PoolFeeAccount: keys[6],
+		Fees:           testFees,
+		SwapCurve:      OffsetCurve(1000),
+	}, decoded)
+
+	data[0] = 2
+	_, err = DecodeSwap(data)
+	ag_require.Error(t, err)
+}
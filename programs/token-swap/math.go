@@ -0,0 +1,191 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrCalculationFailure is returned when a calculation overflows,
+// underflows or divides by zero, which fails the instruction on chain.
+var ErrCalculationFailure = errors.New("calculation failure")
+
+// errAbort is panicked by the checked operations, and recovered by the
+// exported functions as the error of the program; it mirrors the `?` of the
+// checked arithmetic of the program.
+type errAbort struct{}
+
+func recoverAs(err *error, abortErr error) {
+	if r := recover(); r != nil {
+		if _, ok := r.(errAbort); !ok {
+			panic(r)
+		}
+		*err = abortErr
+	}
+}
+
+var (
+	maxU128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxU256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	maxU64  = new(big.Int).SetUint64(^uint64(0))
+)
+
+func u128(v uint64) *big.Int {
+	return new(big.Int).SetUint64(v)
+}
+
+// checkU128 aborts if the value doesn't fit an u128.
+func checkU128(v *big.Int) *big.Int {
+	if v.Sign() < 0 || v.Cmp(maxU128) > 0 {
+		panic(errAbort{})
+	}
+	return v
+}
+
+func add(a, b *big.Int) *big.Int {
+	return checkU128(new(big.Int).Add(a, b))
+}
+
+func sub(a, b *big.Int) *big.Int {
+	return checkU128(new(big.Int).Sub(a, b))
+}
+
+func mul(a, b *big.Int) *big.Int {
+	return checkU128(new(big.Int).Mul(a, b))
+}
+
+func div(a, b *big.Int) *big.Int {
+	if b.Sign() == 0 {
+		panic(errAbort{})
+	}
+	return new(big.Int).Quo(a, b)
+}
+
+func rem(a, b *big.Int) *big.Int {
+	if b.Sign() == 0 {
+		panic(errAbort{})
+	}
+	return new(big.Int).Rem(a, b)
+}
+
+// toU64 aborts if the value doesn't fit an u64.
+func toU64(v *big.Int) uint64 {
+	if v.Cmp(maxU64) > 0 {
+		panic(errAbort{})
+	}
+	return v.Uint64()
+}
+
+// ceilDiv divides, rounding the quotient up, and returns the quotient and
+// the smallest divisor giving it; dividing a small number by a bigger one
+// gives one or zero.
+func ceilDiv(dividend, divisor *big.Int) (quotient *big.Int, newDivisor *big.Int) {
+	quotient = div(dividend, divisor)
+	if quotient.Sign() == 0 {
+		if mul(dividend, big.NewInt(2)).Cmp(divisor) >= 0 {
+			return big.NewInt(1), big.NewInt(0)
+		}
+		return big.NewInt(0), big.NewInt(0)
+	}
+	newDivisor = divisor
+	if rem(dividend, divisor).Sign() > 0 {
+		quotient = add(quotient, big.NewInt(1))
+		newDivisor = div(dividend, quotient)
+		if rem(dividend, quotient).Sign() > 0 {
+			newDivisor = add(newDivisor, big.NewInt(1))
+		}
+	}
+	return quotient, newDivisor
+}
+
+// preciseOne is one in the fixed point representation of the decimal
+// numbers of the program, with 12 decimals.
+var preciseOne = big.NewInt(1_000_000_000_000)
+
+// precise is a decimal number in fixed point, with the rounding of the
+// program.
+type precise struct {
+	value *big.Int
+}
+
+func checkU256(v *big.Int) *big.Int {
+	if v.Sign() < 0 || v.Cmp(maxU256) > 0 {
+		panic(errAbort{})
+	}
+	return v
+}
+
+func newPrecise(v *big.Int) precise {
+	return precise{checkU256(new(big.Int).Mul(v, preciseOne))}
+}
+
+func roundingCorrection() *big.Int {
+	return new(big.Int).Quo(preciseOne, big.NewInt(2))
+}
+
+func (p precise) add(q precise) precise {
+	return precise{checkU256(new(big.Int).Add(p.value, q.value))}
+}
+
+func (p precise) sub(q precise) precise {
+	return precise{checkU256(new(big.Int).Sub(p.value, q.value))}
+}
+
+func (p precise) mul(q precise) precise {
+	v := checkU256(new(big.Int).Mul(p.value, q.value))
+	v = checkU256(v.Add(v, roundingCorrection()))
+	return precise{v.Quo(v, preciseOne)}
+}
+
+func (p precise) div(q precise) precise {
+	if q.value.Sign() == 0 {
+		panic(errAbort{})
+	}
+	v := checkU256(new(big.Int).Mul(p.value, preciseOne))
+	v = checkU256(v.Add(v, roundingCorrection()))
+	return precise{v.Quo(v, q.value)}
+}
+
+func (p precise) floor() *big.Int {
+	return new(big.Int).Quo(p.value, preciseOne)
+}
+
+func (p precise) ceiling() *big.Int {
+	v := new(big.Int).Add(p.value, preciseOne)
+	v.Sub(v, big.NewInt(1))
+	return v.Quo(v, preciseOne)
+}
+
+// sqrt approximates the square root with the Newton's method, like the
+// program: from the middle of [1, p], until two guesses are within 1e-10.
+func (p precise) sqrt() precise {
+	if p.value.Sign() == 0 {
+		return p
+	}
+	one := newPrecise(big.NewInt(1))
+	two := newPrecise(big.NewInt(2))
+	guess := p.add(one).div(two)
+	last := guess
+	for i := 0; i < 100; i++ {
+		guess = guess.add(p.div(guess)).div(two)
+		difference := new(big.Int).Sub(last.value, guess.value)
+		if difference.Abs(difference).Cmp(big.NewInt(100)) < 0 {
+			break
+		}
+		last = guess
+	}
+	return guess
+}
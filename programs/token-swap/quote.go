@@ -0,0 +1,234 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"math/big"
+)
+
+// The quotes reproduce the calculations of the instructions of the program,
+// given the amounts of the token accounts of the swap and the supply of its
+// pool mint; they fail with the errors of the program. The stable curve is
+// not supported.
+
+// SwapResult is the quote of a swap.
+type SwapResult struct {
+	// The amounts of the token accounts of the swap after the swap.
+	NewSwapSourceAmount      uint64
+	NewSwapDestinationAmount uint64
+
+	// The source tokens taken from the user, fees included; it is less
+	// than the amount in when the curve can't use all of it.
+	SourceAmountSwapped uint64
+
+	// The destination tokens sent to the user.
+	DestinationAmountSwapped uint64
+
+	// The trading fee, kept by the pool, in source tokens.
+	TradeFee uint64
+
+	// The owner trading fee, in source tokens; the program mints the pool
+	// tokens worth it to the pool fee account.
+	OwnerFee uint64
+}
+
+// Swap quotes the Swap instruction of sourceAmount, given the amounts of the
+// swap source and destination token accounts.
+func (curve SwapCurve) Swap(
+	sourceAmount uint64,
+	swapSourceAmount uint64,
+	swapDestinationAmount uint64,
+	direction TradeDirection,
+	fees Fees,
+) (result *SwapResult, err error) {
+	calculator, err := curve.calculator()
+	if err != nil {
+		return nil, err
+	}
+	defer recoverAs(&err, ErrZeroTradingTokens)
+
+	source := u128(sourceAmount)
+	tradeFee := fees.tradingFee(source)
+	ownerFee := fees.ownerTradingFee(source)
+	totalFees := add(tradeFee, ownerFee)
+	sourceAmountSwapped, destinationAmountSwapped := calculator.swapWithoutFees(
+		sub(source, totalFees),
+		u128(swapSourceAmount),
+		u128(swapDestinationAmount),
+		direction,
+	)
+	if sourceAmountSwapped == nil {
+		return nil, ErrZeroTradingTokens
+	}
+	sourceAmountSwapped = add(sourceAmountSwapped, totalFees)
+	return &SwapResult{
+		NewSwapSourceAmount:      toU64(add(u128(swapSourceAmount), sourceAmountSwapped)),
+		NewSwapDestinationAmount: toU64(sub(u128(swapDestinationAmount), destinationAmountSwapped)),
+		SourceAmountSwapped:      toU64(sourceAmountSwapped),
+		DestinationAmountSwapped: toU64(destinationAmountSwapped),
+		TradeFee:                 toU64(tradeFee),
+		OwnerFee:                 toU64(ownerFee),
+	}, nil
+}
+
+// DepositAllTokenTypes quotes the amounts of tokens A and B that the
+// DepositAllTokenTypes instruction takes for poolTokenAmount pool tokens;
+// the first deposit, to a pool mint without supply, takes all the tokens of
+// the swap for INITIAL_SWAP_POOL_AMOUNT pool tokens.
+func (curve SwapCurve) DepositAllTokenTypes(
+	poolTokenAmount uint64,
+	poolSupply uint64,
+	swapTokenAAmount uint64,
+	swapTokenBAmount uint64,
+) (tokenAAmount uint64, tokenBAmount uint64, err error) {
+	calculator, err := curve.calculator()
+	if err != nil {
+		return 0, 0, err
+	}
+	if !calculator.allowsDeposits() {
+		return 0, 0, ErrUnsupportedCurveOperation
+	}
+	defer recoverAs(&err, ErrZeroTradingTokens)
+
+	poolTokens, supply := u128(poolTokenAmount), u128(poolSupply)
+	if poolSupply == 0 {
+		poolTokens, supply = u128(INITIAL_SWAP_POOL_AMOUNT), u128(INITIAL_SWAP_POOL_AMOUNT)
+	}
+	a, b := calculator.poolTokensToTradingTokens(poolTokens, supply, u128(swapTokenAAmount), u128(swapTokenBAmount), true)
+	tokenAAmount, tokenBAmount = toU64(a), toU64(b)
+	if tokenAAmount == 0 || tokenBAmount == 0 {
+		return 0, 0, ErrZeroTradingTokens
+	}
+	return tokenAAmount, tokenBAmount, nil
+}
+
+// WithdrawAllTokenTypes quotes the amounts of tokens A and B that the
+// WithdrawAllTokenTypes instruction gives for poolTokenAmount pool tokens,
+// and the withdraw fee taken from them; the program takes no fee from the
+// pool fee account.
+func (curve SwapCurve) WithdrawAllTokenTypes(
+	poolTokenAmount uint64,
+	poolSupply uint64,
+	swapTokenAAmount uint64,
+	swapTokenBAmount uint64,
+	fees Fees,
+) (tokenAAmount uint64, tokenBAmount uint64, withdrawFee uint64, err error) {
+	calculator, err := curve.calculator()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer recoverAs(&err, ErrZeroTradingTokens)
+
+	poolTokens := u128(poolTokenAmount)
+	fee := calculateFee(poolTokens, fees.OwnerWithdrawFeeNumerator, fees.OwnerWithdrawFeeDenominator)
+	a, b := calculator.poolTokensToTradingTokens(sub(poolTokens, fee), u128(poolSupply), u128(swapTokenAAmount), u128(swapTokenBAmount), false)
+	tokenAAmount, tokenBAmount = min64(toU64(a), swapTokenAAmount), min64(toU64(b), swapTokenBAmount)
+	if (tokenAAmount == 0 && swapTokenAAmount != 0) || (tokenBAmount == 0 && swapTokenBAmount != 0) {
+		return 0, 0, 0, ErrZeroTradingTokens
+	}
+	return tokenAAmount, tokenBAmount, toU64(fee), nil
+}
+
+// DepositSingleTokenTypeExactAmountIn quotes the pool tokens that the
+// DepositSingleTokenTypeExactAmountIn instruction gives for sourceAmount
+// tokens A (AtoB) or B (BtoA); the trading fees are taken on half of them.
+func (curve SwapCurve) DepositSingleTokenTypeExactAmountIn(
+	sourceAmount uint64,
+	swapTokenAAmount uint64,
+	swapTokenBAmount uint64,
+	poolSupply uint64,
+	direction TradeDirection,
+	fees Fees,
+) (poolTokenAmount uint64, err error) {
+	calculator, err := curve.calculator()
+	if err != nil {
+		return 0, err
+	}
+	if !calculator.allowsDeposits() {
+		return 0, ErrUnsupportedCurveOperation
+	}
+	defer recoverAs(&err, ErrZeroTradingTokens)
+
+	poolTokens := u128(INITIAL_SWAP_POOL_AMOUNT)
+	if poolSupply > 0 {
+		poolTokens = big.NewInt(0)
+		if sourceAmount > 0 {
+			half := u128(sourceAmount / 2)
+			if half.Sign() == 0 {
+				half = big.NewInt(1)
+			}
+			totalFees := add(fees.tradingFee(half), fees.ownerTradingFee(half))
+			poolTokens = calculator.depositSingleTokenType(
+				sub(u128(sourceAmount), totalFees),
+				u128(swapTokenAAmount),
+				u128(swapTokenBAmount),
+				u128(poolSupply),
+				direction,
+				false,
+			)
+		}
+	}
+	poolTokenAmount = toU64(poolTokens)
+	if poolTokenAmount == 0 {
+		return 0, ErrZeroTradingTokens
+	}
+	return poolTokenAmount, nil
+}
+
+// WithdrawSingleTokenTypeExactAmountOut quotes the pool tokens that the
+// WithdrawSingleTokenTypeExactAmountOut instruction burns for
+// destinationAmount tokens A (AtoB) or B (BtoA), withdraw fee included;
+// the trading fees are taken on half of them.
+func (curve SwapCurve) WithdrawSingleTokenTypeExactAmountOut(
+	destinationAmount uint64,
+	swapTokenAAmount uint64,
+	swapTokenBAmount uint64,
+	poolSupply uint64,
+	direction TradeDirection,
+	fees Fees,
+) (poolTokenAmount uint64, err error) {
+	calculator, err := curve.calculator()
+	if err != nil {
+		return 0, err
+	}
+	defer recoverAs(&err, ErrZeroTradingTokens)
+
+	burned := big.NewInt(0)
+	if destinationAmount > 0 {
+		destination := u128(destinationAmount)
+		half := div(add(destination, big.NewInt(1)), big.NewInt(2))
+		burned = calculator.withdrawSingleTokenTypeExactOut(
+			add(sub(destination, half), fees.preTradingFeeAmount(half)),
+			u128(swapTokenAAmount),
+			u128(swapTokenBAmount),
+			u128(poolSupply),
+			direction,
+			true,
+		)
+	}
+	withdrawFee := calculateFee(burned, fees.OwnerWithdrawFeeNumerator, fees.OwnerWithdrawFeeDenominator)
+	poolTokenAmount = toU64(add(burned, withdrawFee))
+	if poolTokenAmount == 0 {
+		return 0, ErrZeroTradingTokens
+	}
+	return poolTokenAmount, nil
+}
+
+func min64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenswap
+
+import (
+	"errors"
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+)
+
+// Fees are the fees of a swap, as fractions. The trading fees are paid in
+// the source tokens of the swaps; the owner trading fee is minted as pool
+// tokens to the pool fee account, which shares the host fee of it with the
+// host fee account of the swap, if any. The owner withdraw fee is paid in
+// pool tokens on the withdrawals.
+type Fees struct {
+	TradeFeeNumerator           uint64
+	TradeFeeDenominator         uint64
+	OwnerTradeFeeNumerator      uint64
+	OwnerTradeFeeDenominator    uint64
+	OwnerWithdrawFeeNumerator   uint64
+	OwnerWithdrawFeeDenominator uint64
+	HostFeeNumerator            uint64
+	HostFeeDenominator          uint64
+}
+
+func (fees Fees) validate() error {
+	fractions := [][2]uint64{
+		{fees.TradeFeeNumerator, fees.TradeFeeDenominator},
+		{fees.OwnerTradeFeeNumerator, fees.OwnerTradeFeeDenominator},
+		{fees.OwnerWithdrawFeeNumerator, fees.OwnerWithdrawFeeDenominator},
+		{fees.HostFeeNumerator, fees.HostFeeDenominator},
+	}
+	for _, fraction := range fractions {
+		if fraction[0] == 0 && fraction[1] == 0 {
+			continue
+		}
+		if fraction[0] >= fraction[1] {
+			return fmt.Errorf("invalid fee: %d/%d", fraction[0], fraction[1])
+		}
+	}
+	return nil
+}
+
+type CurveType uint8
+
+const (
+	// Uniswap-like curve: the product of the amounts of the two tokens is
+	// invariant.
+	CurveTypeConstantProduct CurveType = iota
+
+	// Flat curve: token B is always worth TokenBPrice of token A.
+	CurveTypeConstantPrice
+
+	// Stable curve, for tokens of the same value.
+	CurveTypeStable
+
+	// Constant product curve with a virtual amount of token B added to the
+	// pool, to sell token B without depositing token A.
+	CurveTypeOffset
+)
+
+func (typ CurveType) String() string {
+	switch typ {
+	case CurveTypeConstantProduct:
+		return "ConstantProduct"
+	case CurveTypeConstantPrice:
+		return "ConstantPrice"
+	case CurveTypeStable:
+		return "Stable"
+	case CurveTypeOffset:
+		return "Offset"
+	default:
+		return fmt.Sprintf("CurveType(%d)", uint8(typ))
+	}
+}
+
+// The bounds of the amplification coefficient of stable curves.
+const (
+	MIN_AMP = 1
+	MAX_AMP = 1_000_000
+)
+
+// SwapCurve is the curve of a swap and its parameter; only the parameter of
+// the type of the curve is encoded.
+type SwapCurve struct {
+	CurveType CurveType
+
+	// The price of token B in token A, for constant price curves.
+	TokenBPrice uint64
+
+	// The amplification coefficient, for stable curves.
+	Amp uint64
+
+	// The virtual amount of token B, for offset curves.
+	TokenBOffset uint64
+}
+
+// ConstantProductCurve returns a constant product curve.
+func ConstantProductCurve() SwapCurve {
+	return SwapCurve{CurveType: CurveTypeConstantProduct}
+}
+
+// ConstantPriceCurve returns a constant price curve, with token B worth
+// tokenBPrice of token A.
+func ConstantPriceCurve(tokenBPrice uint64) SwapCurve {
+	return SwapCurve{CurveType: CurveTypeConstantPrice, TokenBPrice: tokenBPrice}
+}
+
+// StableCurve returns a stable curve with the amplification coefficient.
+func StableCurve(amp uint64) SwapCurve {
+	return SwapCurve{CurveType: CurveTypeStable, Amp: amp}
+}
+
+// OffsetCurve returns an offset curve with the virtual amount of token B.
+func OffsetCurve(tokenBOffset uint64) SwapCurve {
+	return SwapCurve{CurveType: CurveTypeOffset, TokenBOffset: tokenBOffset}
+}
+
+// SWAP_CURVE_LEN is the size of an encoded swap curve: its type, and its
+// parameter padded to 32 bytes.
+const SWAP_CURVE_LEN = 33
+
+func (curve SwapCurve) validate() error {
+	switch curve.CurveType {
+	case CurveTypeConstantProduct:
+	case CurveTypeConstantPrice:
+		if curve.TokenBPrice == 0 {
+			return errors.New("the price of token B must not be zero")
+		}
+	case CurveTypeStable:
+		if curve.Amp < MIN_AMP || curve.Amp > MAX_AMP {
+			return fmt.Errorf("the amplification coefficient must be between %d and %d", MIN_AMP, MAX_AMP)
+		}
+	case CurveTypeOffset:
+		if curve.TokenBOffset == 0 {
+			return errors.New("the offset of token B must not be zero")
+		}
+	default:
+		return fmt.Errorf("unknown curve type: %s", curve.CurveType)
+	}
+	return nil
+}
+
+// parameter returns the parameter of the type of the curve.
+func (curve SwapCurve) parameter() *uint64 {
+	switch curve.CurveType {
+	case CurveTypeConstantPrice:
+		return &curve.TokenBPrice
+	case CurveTypeStable:
+		return &curve.Amp
+	case CurveTypeOffset:
+		return &curve.TokenBOffset
+	default:
+		return nil
+	}
+}
+
+func (curve SwapCurve) MarshalWithEncoder(encoder *ag_binary.Encoder) error {
+	if err := encoder.WriteUint8(uint8(curve.CurveType)); err != nil {
+		return err
+	}
+	padding := SWAP_CURVE_LEN - 1
+	if parameter := curve.parameter(); parameter != nil {
+		if err := encoder.WriteUint64(*parameter, ag_binary.LE); err != nil {
+			return err
+		}
+		padding -= 8
+	}
+	return encoder.WriteBytes(make([]byte, padding), false)
+}
+
+func (curve *SwapCurve) UnmarshalWithDecoder(decoder *ag_binary.Decoder) error {
+	curveType, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	*curve = SwapCurve{CurveType: CurveType(curveType)}
+	padding := SWAP_CURVE_LEN - 1
+	switch curve.CurveType {
+	case CurveTypeConstantProduct:
+	case CurveTypeConstantPrice:
+		curve.TokenBPrice, err = decoder.ReadUint64(ag_binary.LE)
+		padding -= 8
+	case CurveTypeStable:
+		curve.Amp, err = decoder.ReadUint64(ag_binary.LE)
+		padding -= 8
+	case CurveTypeOffset:
+		curve.TokenBOffset, err = decoder.ReadUint64(ag_binary.LE)
+		padding -= 8
+	default:
+		return fmt.Errorf("unknown curve type: %s", curve.CurveType)
+	}
+	if err != nil {
+		return err
+	}
+	return decoder.SkipBytes(uint(padding))
+}
@@ -117,6 +117,11 @@ func (inst *Approve) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Approve) AccountNames() []string {
+	return appendSignerNames([]string{"source", "delegate", "owner"}, inst.Signers)
+}
+
 func (inst Approve) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
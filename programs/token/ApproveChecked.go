@@ -147,6 +147,11 @@ func (inst *ApproveChecked) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[3]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst ApproveChecked) AccountNames() []string {
+	return appendSignerNames([]string{"source", "mint", "delegate", "owner"}, inst.Signers)
+}
+
 func (inst ApproveChecked) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -117,6 +117,11 @@ func (inst *Burn) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Burn) AccountNames() []string {
+	return appendSignerNames([]string{"source", "mint", "owner"}, inst.Signers)
+}
+
 func (inst Burn) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
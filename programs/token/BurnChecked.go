@@ -132,6 +132,11 @@ func (inst *BurnChecked) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst BurnChecked) AccountNames() []string {
+	return appendSignerNames([]string{"source", "mint", "owner"}, inst.Signers)
+}
+
 func (inst BurnChecked) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
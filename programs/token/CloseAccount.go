@@ -108,6 +108,11 @@ func (inst *CloseAccount) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst CloseAccount) AccountNames() []string {
+	return appendSignerNames([]string{"account", "destination", "owner"}, inst.Signers)
+}
+
 func (inst CloseAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -107,6 +107,11 @@ func (inst *FreezeAccount) GetAuthorityAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst FreezeAccount) AccountNames() []string {
+	return appendSignerNames([]string{"account", "mint", "authority"}, inst.Signers)
+}
+
 func (inst FreezeAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -111,6 +111,11 @@ func (inst *InitializeAccount) GetSysVarRentPubkeyAccount() *ag_solanago.Account
 	return inst.AccountMetaSlice[3]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeAccount) AccountNames() []string {
+	return []string{"account", "mint", "owner", "rentSysvar"}
+}
+
 func (inst InitializeAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -97,6 +97,11 @@ func (inst *InitializeAccount2) GetSysVarRentPubkeyAccount() *ag_solanago.Accoun
 	return inst.AccountMetaSlice[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeAccount2) AccountNames() []string {
+	return []string{"account", "mint", "rentSysvar"}
+}
+
 func (inst InitializeAccount2) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
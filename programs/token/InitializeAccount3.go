@@ -77,6 +77,11 @@ func (inst *InitializeAccount3) GetMintAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeAccount3) AccountNames() []string {
+	return []string{"account", "mint"}
+}
+
 func (inst InitializeAccount3) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
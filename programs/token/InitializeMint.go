@@ -105,6 +105,11 @@ func (inst *InitializeMint) GetSysVarRentPubkeyAccount() *ag_solanago.AccountMet
 	return inst.AccountMetaSlice[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeMint) AccountNames() []string {
+	return []string{"mint", "rentSysvar"}
+}
+
 func (inst InitializeMint) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -81,6 +81,11 @@ func (inst *InitializeMint2) GetMintAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[0]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeMint2) AccountNames() []string {
+	return []string{"mint"}
+}
+
 func (inst InitializeMint2) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
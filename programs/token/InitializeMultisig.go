@@ -117,6 +117,11 @@ func (inst *InitializeMultisig) AddSigners(signers ...ag_solanago.PublicKey) *In
 	return inst
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeMultisig) AccountNames() []string {
+	return appendSignerNames([]string{"account", "rentSysvar"}, inst.Signers)
+}
+
 func (inst InitializeMultisig) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -87,6 +87,11 @@ func (inst *InitializeMultisig2) AddSigners(signers ...ag_solanago.PublicKey) *I
 	return inst
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeMultisig2) AccountNames() []string {
+	return appendSignerNames([]string{"account"}, inst.Signers)
+}
+
 func (inst InitializeMultisig2) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -117,6 +117,11 @@ func (inst *MintTo) GetAuthorityAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst MintTo) AccountNames() []string {
+	return appendSignerNames([]string{"mint", "destination", "authority"}, inst.Signers)
+}
+
 func (inst MintTo) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -130,6 +130,11 @@ func (inst *MintToChecked) GetAuthorityAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst MintToChecked) AccountNames() []string {
+	return appendSignerNames([]string{"mint", "destination", "authority"}, inst.Signers)
+}
+
 func (inst MintToChecked) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -91,6 +91,11 @@ func (inst *Revoke) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Revoke) AccountNames() []string {
+	return appendSignerNames([]string{"source", "owner"}, inst.Signers)
+}
+
 func (inst Revoke) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -110,6 +110,11 @@ func (inst *SetAuthority) GetAuthorityAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[1]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst SetAuthority) AccountNames() []string {
+	return appendSignerNames([]string{"subject", "authority"}, inst.Signers)
+}
+
 func (inst SetAuthority) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
@@ -56,6 +56,11 @@ func (inst *SyncNative) GetTokenAccount() *ag_solanago.AccountMeta {
 	return inst.AccountMetaSlice[0]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst SyncNative) AccountNames() []string {
+	return []string{"tokenAccount"}
+}
+
 func (inst SyncNative) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
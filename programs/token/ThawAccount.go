@@ -107,6 +107,11 @@ func (inst *ThawAccount) GetAuthorityAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst ThawAccount) AccountNames() []string {
+	return appendSignerNames([]string{"account", "mint", "authority"}, inst.Signers)
+}
+
 func (inst ThawAccount) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
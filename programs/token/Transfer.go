@@ -119,6 +119,11 @@ func (inst *Transfer) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[2]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Transfer) AccountNames() []string {
+	return appendSignerNames([]string{"source", "destination", "owner"}, inst.Signers)
+}
+
 func (inst Transfer) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
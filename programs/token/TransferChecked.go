@@ -149,6 +149,11 @@ func (inst *TransferChecked) GetOwnerAccount() *ag_solanago.AccountMeta {
 	return inst.Accounts[3]
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst TransferChecked) AccountNames() []string {
+	return appendSignerNames([]string{"source", "mint", "destination", "owner"}, inst.Signers)
+}
+
 func (inst TransferChecked) Build() *Instruction {
 	return &Instruction{BaseVariant: ag_binary.BaseVariant{
 		Impl:   inst,
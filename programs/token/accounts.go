@@ -16,9 +16,11 @@ package token
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
 )
 
 type Mint struct {
@@ -163,6 +165,21 @@ func (mint Mint) MarshalWithEncoder(encoder *bin.Encoder) (err error) {
 	return nil
 }
 
+var mintFieldLayouts = map[string]rpc.FieldLayout{
+	"MintAuthority":   {Offset: 0, OptionFlagSize: 4},
+	"Supply":          {Offset: 36},
+	"Decimals":        {Offset: 44},
+	"IsInitialized":   {Offset: 45},
+	"FreezeAuthority": {Offset: 46, OptionFlagSize: 4},
+}
+
+// FieldLayout implements rpc.FieldLayouter: the COption fields
+// always take 4+32 bytes, whatever the encoding.
+func (mint Mint) FieldLayout(_ bin.Encoding, field string) (rpc.FieldLayout, bool) {
+	layout, ok := mintFieldLayouts[field]
+	return layout, ok
+}
+
 type Account struct {
 	// The mint associated with this account
 	Mint solana.PublicKey
@@ -385,6 +402,24 @@ func (mint Account) MarshalWithEncoder(encoder *bin.Encoder) (err error) {
 	return nil
 }
 
+var accountFieldLayouts = map[string]rpc.FieldLayout{
+	"Mint":            {Offset: 0},
+	"Owner":           {Offset: 32},
+	"Amount":          {Offset: 64},
+	"Delegate":        {Offset: 72, OptionFlagSize: 4},
+	"State":           {Offset: 108},
+	"IsNative":        {Offset: 109, OptionFlagSize: 4},
+	"DelegatedAmount": {Offset: 121},
+	"CloseAuthority":  {Offset: 129, OptionFlagSize: 4},
+}
+
+// FieldLayout implements rpc.FieldLayouter: the COption fields
+// always take 4 bytes plus the size of their value, whatever the encoding.
+func (mint Account) FieldLayout(_ bin.Encoding, field string) (rpc.FieldLayout, bool) {
+	layout, ok := accountFieldLayouts[field]
+	return layout, ok
+}
+
 type Multisig struct {
 	// Number of signers required
 	M uint8
@@ -395,3 +430,33 @@ type Multisig struct {
 	// Signer public keys
 	Signers [MAX_SIGNERS]solana.PublicKey
 }
+
+func registerAccountDecoders(programID solana.PublicKey) {
+	solana.RegisterAccountDecoder(programID, solana.AccountSizeMatcher(MINT_SIZE), decodeMintAccount)
+	solana.RegisterAccountDecoder(programID, solana.AccountSizeMatcher(ACCOUNT_SIZE), decodeTokenAccount)
+	solana.RegisterAccountDecoder(programID, solana.AccountSizeMatcher(MULTISIG_SIZE), decodeMultisigAccount)
+}
+
+func decodeMintAccount(data []byte) (interface{}, error) {
+	out := new(Mint)
+	if err := bin.NewBinDecoder(data).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode mint: %w", err)
+	}
+	return out, nil
+}
+
+func decodeTokenAccount(data []byte) (interface{}, error) {
+	out := new(Account)
+	if err := bin.NewBinDecoder(data).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode token account: %w", err)
+	}
+	return out, nil
+}
+
+func decodeMultisigAccount(data []byte) (interface{}, error) {
+	out := new(Multisig)
+	if err := bin.NewBinDecoder(data).Decode(out); err != nil {
+		return nil, fmt.Errorf("unable to decode multisig: %w", err)
+	}
+	return out, nil
+}
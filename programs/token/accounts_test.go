@@ -7,6 +7,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/stretchr/testify/require"
 )
 
@@ -94,3 +95,35 @@ func TestAccount(t *testing.T) {
 		}
 	}
 }
+
+func TestAccount_DecodeRegistered(t *testing.T) {
+	owner := solana.MustPublicKeyFromBase58("7HZaCWazgTuuFuajxaaxGYbGnyVKwxvsJKue1W4Nvyro")
+	account := Account{
+		Mint:   solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112"),
+		Owner:  owner,
+		Amount: 28320298,
+		State:  Initialized,
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewBinEncoder(buf).Encode(account))
+	require.Len(t, buf.Bytes(), ACCOUNT_SIZE)
+
+	keyed := &rpc.KeyedAccount{
+		Pubkey: solana.NewWallet().PublicKey(),
+		Account: &rpc.Account{
+			Owner: ProgramID,
+			Data:  rpc.DataBytesOrJSONFromBytes(buf.Bytes()),
+		},
+	}
+	decoded, err := keyed.Decode()
+	require.NoError(t, err)
+	require.Equal(t, &account, decoded)
+
+	mint := Mint{Decimals: 6, IsInitialized: true}
+	buf.Reset()
+	require.NoError(t, bin.NewBinEncoder(buf).Encode(mint))
+	decoded, err = solana.DecodeAccount(ProgramID, buf.Bytes())
+	require.NoError(t, err)
+	require.IsType(t, &Mint{}, decoded)
+	require.Equal(t, uint8(6), decoded.(*Mint).Decimals)
+}
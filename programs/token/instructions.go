@@ -36,6 +36,7 @@ var ProgramID ag_solanago.PublicKey = ag_solanago.TokenProgramID
 func SetProgramID(pubkey ag_solanago.PublicKey) {
 	ProgramID = pubkey
 	ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
 }
 
 const ProgramName = "Token"
@@ -43,6 +44,7 @@ const ProgramName = "Token"
 func init() {
 	if !ProgramID.IsZero() {
 		ag_solanago.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+		registerAccountDecoders(ProgramID)
 	}
 }
 
@@ -310,6 +312,15 @@ func (inst *Instruction) ProgramID() ag_solanago.PublicKey {
 	return ProgramID
 }
 
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
 func (inst *Instruction) Accounts() (out []*ag_solanago.AccountMeta) {
 	return inst.Impl.(ag_solanago.AccountsGettable).GetAccounts()
 }
@@ -359,3 +370,11 @@ func DecodeInstruction(accounts []*ag_solanago.AccountMeta, data []byte) (*Instr
 	}
 	return inst, nil
 }
+
+// appendSignerNames names the multisig signer accounts after the named ones.
+func appendSignerNames(names []string, signers ag_solanago.AccountMetaSlice) []string {
+	for i := range signers {
+		names = append(names, fmt.Sprintf("signers[%d]", i))
+	}
+	return names
+}
@@ -27,6 +27,10 @@ import (
 
 const MINT_SIZE = 82
 
+const ACCOUNT_SIZE = 165
+
+const MULTISIG_SIZE = 355
+
 func (mint *Mint) Decode(data []byte) error {
 	mint = new(Mint)
 	dec := bin.NewBinDecoder(data)
@@ -31,3 +31,8 @@ type Authorize struct {
 	// ··········· Vote or withdraw authority
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
+
+// AccountNames returns the names of the accounts, in order.
+func (inst Authorize) AccountNames() []string {
+	return []string{"voteAccount", "clockSysvar", "authority"}
+}
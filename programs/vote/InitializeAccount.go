@@ -34,3 +34,8 @@ type InitializeAccount struct {
 	// ··········· New validator identity (node_pubkey)
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
+
+// AccountNames returns the names of the accounts, in order.
+func (inst InitializeAccount) AccountNames() []string {
+	return []string{"voteAccount", "slotHashesSysvar", "clockSysvar", "voteAuthority"}
+}
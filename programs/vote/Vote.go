@@ -43,6 +43,11 @@ type Vote struct {
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Vote) AccountNames() []string {
+	return []string{"voteAccount", "slotHashesSysvar", "clockSysvar", "voteAuthority"}
+}
+
 func (v *Vote) UnmarshalWithDecoder(dec *bin.Decoder) error {
 	v.Slots = nil
 	var numSlots uint64
@@ -41,6 +41,11 @@ type Withdraw struct {
 	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
 }
 
+// AccountNames returns the names of the accounts, in order.
+func (inst Withdraw) AccountNames() []string {
+	return []string{"voteAccount", "toAccount", "authorizedWithdrawer"}
+}
+
 func (v *Withdraw) UnmarshalWithDecoder(dec *bin.Decoder) error {
 	// Deserialize `Lamports` param:
 	{
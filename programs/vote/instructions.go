@@ -31,12 +31,14 @@ var ProgramID solana.PublicKey = solana.VoteProgramID
 func SetProgramID(pubkey solana.PublicKey) {
 	ProgramID = pubkey
 	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
 }
 
 const ProgramName = "Vote"
 
 func init() {
 	solana.RegisterInstructionDecoder(ProgramID, registryDecodeInstruction)
+	registerAccountDecoders(ProgramID)
 }
 
 type Instruction struct {
@@ -73,6 +75,15 @@ func (inst *Instruction) ProgramID() solana.PublicKey {
 	return ProgramID
 }
 
+func (inst *Instruction) ProgramName() string {
+	return ProgramName
+}
+
+func (inst *Instruction) InstructionName() string {
+	_, name, _ := inst.Obtain(InstructionImplDef)
+	return name
+}
+
 func (inst *Instruction) Accounts() (out []*solana.AccountMeta) {
 	return inst.Impl.(solana.AccountsGettable).GetAccounts()
 }
@@ -0,0 +1,339 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vote
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// MAX_ITEMS is the number of entries of the prior voters circular buffer.
+const MAX_ITEMS = 32
+
+// VoteStateVersion is the tag of the serialized vote state.
+type VoteStateVersion uint32
+
+const (
+	VoteStateVersionV0_23_5 VoteStateVersion = iota
+	VoteStateVersionV1_14_11
+	VoteStateVersionCurrent
+)
+
+func (v VoteStateVersion) String() string {
+	switch v {
+	case VoteStateVersionV0_23_5:
+		return "V0_23_5"
+	case VoteStateVersionV1_14_11:
+		return "V1_14_11"
+	case VoteStateVersionCurrent:
+		return "Current"
+	default:
+		return fmt.Sprintf("VoteStateVersion(%d)", uint32(v))
+	}
+}
+
+// VoteState is the data of a vote account.
+// All the serialized versions are decoded into this normalized form.
+type VoteState struct {
+	Version VoteStateVersion
+
+	NodePubkey           solana.PublicKey
+	AuthorizedWithdrawer solana.PublicKey
+	Commission           uint8
+
+	// Latency is always zero for the versions preceding Current.
+	Votes    []LandedVote
+	RootSlot *uint64
+
+	AuthorizedVoters []AuthorizedVoter
+	// Prior voters, oldest first.
+	PriorVoters []PriorVoter
+
+	EpochCredits  []EpochCredits
+	LastTimestamp BlockTimestamp
+}
+
+type LandedVote struct {
+	Latency           uint8
+	Slot              uint64
+	ConfirmationCount uint32
+}
+
+type AuthorizedVoter struct {
+	Epoch  uint64
+	Pubkey solana.PublicKey
+}
+
+type PriorVoter struct {
+	Pubkey     solana.PublicKey
+	EpochStart uint64
+	EpochEnd   uint64
+}
+
+type EpochCredits struct {
+	Epoch       uint64
+	Credits     uint64
+	PrevCredits uint64
+}
+
+type BlockTimestamp struct {
+	Slot      uint64
+	Timestamp int64
+}
+
+func DecodeVoteState(data []byte) (*VoteState, error) {
+	state := new(VoteState)
+	if err := bin.NewBinDecoder(data).Decode(state); err != nil {
+		return nil, fmt.Errorf("unable to decode vote state: %w", err)
+	}
+	return state, nil
+}
+
+func (state *VoteState) UnmarshalWithDecoder(dec *bin.Decoder) (err error) {
+	version, err := dec.ReadUint32(bin.LE)
+	if err != nil {
+		return err
+	}
+	state.Version = VoteStateVersion(version)
+	switch state.Version {
+	case VoteStateVersionV0_23_5:
+		return state.decodeV0_23_5(dec)
+	case VoteStateVersionV1_14_11, VoteStateVersionCurrent:
+	default:
+		return fmt.Errorf("unknown vote state version: %d", version)
+	}
+
+	if err = dec.Decode(&state.NodePubkey); err != nil {
+		return err
+	}
+	if err = dec.Decode(&state.AuthorizedWithdrawer); err != nil {
+		return err
+	}
+	if state.Commission, err = dec.ReadUint8(); err != nil {
+		return err
+	}
+	if err = state.decodeVotes(dec, state.Version == VoteStateVersionCurrent); err != nil {
+		return err
+	}
+	if err = state.decodeRootSlot(dec); err != nil {
+		return err
+	}
+	{
+		count, err := readLength(dec, 8+32)
+		if err != nil {
+			return fmt.Errorf("unable to decode authorized voters: %w", err)
+		}
+		state.AuthorizedVoters = make([]AuthorizedVoter, count)
+		for i := range state.AuthorizedVoters {
+			if state.AuthorizedVoters[i].Epoch, err = dec.ReadUint64(bin.LE); err != nil {
+				return err
+			}
+			if err = dec.Decode(&state.AuthorizedVoters[i].Pubkey); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		var buf [MAX_ITEMS]PriorVoter
+		for i := range buf {
+			if err = dec.Decode(&buf[i].Pubkey); err != nil {
+				return err
+			}
+			if buf[i].EpochStart, err = dec.ReadUint64(bin.LE); err != nil {
+				return err
+			}
+			if buf[i].EpochEnd, err = dec.ReadUint64(bin.LE); err != nil {
+				return err
+			}
+		}
+		idx, err := dec.ReadUint64(bin.LE)
+		if err != nil {
+			return err
+		}
+		isEmpty, err := dec.ReadBool()
+		if err != nil {
+			return err
+		}
+		if !isEmpty {
+			state.PriorVoters = orderPriorVoters(buf, idx)
+		}
+	}
+	return state.decodeTail(dec)
+}
+
+// decodeV0_23_5 decodes the layout used before the introduction of
+// the authorized voters map.
+func (state *VoteState) decodeV0_23_5(dec *bin.Decoder) (err error) {
+	if err = dec.Decode(&state.NodePubkey); err != nil {
+		return err
+	}
+	voter := AuthorizedVoter{}
+	if err = dec.Decode(&voter.Pubkey); err != nil {
+		return err
+	}
+	if voter.Epoch, err = dec.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	state.AuthorizedVoters = []AuthorizedVoter{voter}
+	{
+		var buf [MAX_ITEMS]PriorVoter
+		for i := range buf {
+			if err = dec.Decode(&buf[i].Pubkey); err != nil {
+				return err
+			}
+			if buf[i].EpochStart, err = dec.ReadUint64(bin.LE); err != nil {
+				return err
+			}
+			if buf[i].EpochEnd, err = dec.ReadUint64(bin.LE); err != nil {
+				return err
+			}
+			// The slot at which the voter was replaced.
+			if err = dec.SkipBytes(8); err != nil {
+				return err
+			}
+		}
+		idx, err := dec.ReadUint64(bin.LE)
+		if err != nil {
+			return err
+		}
+		state.PriorVoters = orderPriorVoters(buf, idx)
+	}
+	if err = dec.Decode(&state.AuthorizedWithdrawer); err != nil {
+		return err
+	}
+	if state.Commission, err = dec.ReadUint8(); err != nil {
+		return err
+	}
+	if err = state.decodeVotes(dec, false); err != nil {
+		return err
+	}
+	if err = state.decodeRootSlot(dec); err != nil {
+		return err
+	}
+	return state.decodeTail(dec)
+}
+
+func (state *VoteState) decodeVotes(dec *bin.Decoder, withLatency bool) (err error) {
+	size := 8 + 4
+	if withLatency {
+		size++
+	}
+	count, err := readLength(dec, size)
+	if err != nil {
+		return fmt.Errorf("unable to decode votes: %w", err)
+	}
+	state.Votes = make([]LandedVote, count)
+	for i := range state.Votes {
+		if withLatency {
+			if state.Votes[i].Latency, err = dec.ReadUint8(); err != nil {
+				return err
+			}
+		}
+		if state.Votes[i].Slot, err = dec.ReadUint64(bin.LE); err != nil {
+			return err
+		}
+		if state.Votes[i].ConfirmationCount, err = dec.ReadUint32(bin.LE); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (state *VoteState) decodeRootSlot(dec *bin.Decoder) error {
+	has, err := dec.ReadOption()
+	if err != nil {
+		return err
+	}
+	if !has {
+		state.RootSlot = nil
+		return nil
+	}
+	slot, err := dec.ReadUint64(bin.LE)
+	if err != nil {
+		return err
+	}
+	state.RootSlot = &slot
+	return nil
+}
+
+// decodeTail decodes the epoch credits and the last timestamp,
+// which close all the versions.
+func (state *VoteState) decodeTail(dec *bin.Decoder) error {
+	count, err := readLength(dec, 8+8+8)
+	if err != nil {
+		return fmt.Errorf("unable to decode epoch credits: %w", err)
+	}
+	state.EpochCredits = make([]EpochCredits, count)
+	for i := range state.EpochCredits {
+		if state.EpochCredits[i].Epoch, err = dec.ReadUint64(bin.LE); err != nil {
+			return err
+		}
+		if state.EpochCredits[i].Credits, err = dec.ReadUint64(bin.LE); err != nil {
+			return err
+		}
+		if state.EpochCredits[i].PrevCredits, err = dec.ReadUint64(bin.LE); err != nil {
+			return err
+		}
+	}
+	if state.LastTimestamp.Slot, err = dec.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if state.LastTimestamp.Timestamp, err = dec.ReadInt64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readLength reads a u64 collection length, rejecting the lengths
+// that cannot fit in the remaining data.
+func readLength(dec *bin.Decoder, itemSize int) (int, error) {
+	count, err := dec.ReadUint64(bin.LE)
+	if err != nil {
+		return 0, err
+	}
+	if count > uint64(dec.Remaining()/itemSize) {
+		return 0, fmt.Errorf("length %d exceeds the remaining data", count)
+	}
+	return int(count), nil
+}
+
+// orderPriorVoters returns the used entries of the circular buffer, oldest first;
+// idx is the position of the most recent entry.
+func orderPriorVoters(buf [MAX_ITEMS]PriorVoter, idx uint64) []PriorVoter {
+	var out []PriorVoter
+	for i := uint64(1); i <= MAX_ITEMS; i++ {
+		entry := buf[(idx+i)%MAX_ITEMS]
+		if entry.Pubkey.IsZero() {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func registerAccountDecoders(programID solana.PublicKey) {
+	// The vote program owns only vote accounts.
+	solana.RegisterAccountDecoder(programID, nil, registryDecodeAccount)
+}
+
+func registryDecodeAccount(data []byte) (interface{}, error) {
+	state, err := DecodeVoteState(data)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vote
+
+import (
+	"bytes"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteState_Decode(t *testing.T) {
+	node := solana.NewWallet().PublicKey()
+	withdrawer := solana.NewWallet().PublicKey()
+	voter := solana.NewWallet().PublicKey()
+	prior := solana.NewWallet().PublicKey()
+
+	buf := new(bytes.Buffer)
+	enc := bin.NewBinEncoder(buf)
+	enc.WriteUint32(uint32(VoteStateVersionCurrent), bin.LE)
+	enc.WriteBytes(node[:], false)
+	enc.WriteBytes(withdrawer[:], false)
+	enc.WriteUint8(10)
+	// Votes.
+	enc.WriteUint64(2, bin.LE)
+	for i := uint64(0); i < 2; i++ {
+		enc.WriteUint8(1)
+		enc.WriteUint64(100+i, bin.LE)
+		enc.WriteUint32(uint32(2-i), bin.LE)
+	}
+	// Root slot.
+	enc.WriteOption(true)
+	enc.WriteUint64(99, bin.LE)
+	// Authorized voters.
+	enc.WriteUint64(1, bin.LE)
+	enc.WriteUint64(7, bin.LE)
+	enc.WriteBytes(voter[:], false)
+	// Prior voters: a single entry, at index 0.
+	for i := 0; i < MAX_ITEMS; i++ {
+		if i == 0 {
+			enc.WriteBytes(prior[:], false)
+			enc.WriteUint64(3, bin.LE)
+			enc.WriteUint64(7, bin.LE)
+		} else {
+			enc.WriteBytes(make([]byte, 32+8+8), false)
+		}
+	}
+	enc.WriteUint64(0, bin.LE)
+	enc.WriteBool(false)
+	// Epoch credits.
+	enc.WriteUint64(1, bin.LE)
+	enc.WriteUint64(7, bin.LE)
+	enc.WriteUint64(1500, bin.LE)
+	enc.WriteUint64(1000, bin.LE)
+	// Last timestamp.
+	enc.WriteUint64(101, bin.LE)
+	enc.WriteInt64(1700000000, bin.LE)
+	// Vote accounts are zero-padded.
+	enc.WriteBytes(make([]byte, 64), false)
+
+	decoded, err := solana.DecodeAccount(ProgramID, buf.Bytes())
+	require.NoError(t, err)
+	rootSlot := uint64(99)
+	require.Equal(t, &VoteState{
+		Version:              VoteStateVersionCurrent,
+		NodePubkey:           node,
+		AuthorizedWithdrawer: withdrawer,
+		Commission:           10,
+		Votes: []LandedVote{
+			{Latency: 1, Slot: 100, ConfirmationCount: 2},
+			{Latency: 1, Slot: 101, ConfirmationCount: 1},
+		},
+		RootSlot:         &rootSlot,
+		AuthorizedVoters: []AuthorizedVoter{{Epoch: 7, Pubkey: voter}},
+		PriorVoters:      []PriorVoter{{Pubkey: prior, EpochStart: 3, EpochEnd: 7}},
+		EpochCredits:     []EpochCredits{{Epoch: 7, Credits: 1500, PrevCredits: 1000}},
+		LastTimestamp:    BlockTimestamp{Slot: 101, Timestamp: 1700000000},
+	}, decoded)
+
+	// A corrupted votes length is rejected.
+	data := buf.Bytes()
+	data[4+32+32+1+7] = 0xff
+	_, err = DecodeVoteState(data)
+	require.Error(t, err)
+}
@@ -0,0 +1,293 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// BalanceChanges are the changes of the SOL and token balances made by a transaction.
+type BalanceChanges struct {
+	// The fee paid by the fee payer (the first account), included in its SOL change.
+	FeePayer solana.PublicKey
+	Fee      uint64
+
+	// The accounts whose SOL balance changed, and the fee payer, in account order.
+	SOL []*SOLBalanceChange
+	// The token accounts whose balance changed, in account order.
+	Tokens []*TokenBalanceChange
+}
+
+// SOLBalanceChange is the change of the lamports of an account.
+type SOLBalanceChange struct {
+	Account solana.PublicKey
+	Pre     uint64
+	Post    uint64
+	// Post - Pre: for the fee payer, the fee is included.
+	Change int64
+	// The fee paid by the account (i.e. if it is the fee payer).
+	Fee uint64
+	// Whether the account was funded, or drained (i.e. closed), by the transaction.
+	Created bool
+	Closed  bool
+}
+
+// ChangeExcludingFee returns the change of the balance, without the fee paid by the account.
+func (c *SOLBalanceChange) ChangeExcludingFee() int64 {
+	return c.Change + int64(c.Fee)
+}
+
+// TokenBalanceChange is the change of the balance of a token account.
+type TokenBalanceChange struct {
+	Account   solana.PublicKey
+	Owner     solana.PublicKey
+	Mint      solana.PublicKey
+	ProgramID solana.PublicKey // zero if not reported by the node
+	Decimals  uint8
+	Pre       *big.Int
+	Post      *big.Int
+	// Post - Pre.
+	Change *big.Int
+	// Whether the token account was initialized, or closed, by the transaction.
+	Created bool
+	Closed  bool
+}
+
+// UiChange returns the change as a decimal string (e.g. "-1.5").
+func (c *TokenBalanceChange) UiChange() string {
+	return formatTokenAmount(c.Change, c.Decimals)
+}
+
+// TokenOwnerChange is the change of the balance of a mint held by an owner,
+// over all the owner's token accounts of the mint.
+type TokenOwnerChange struct {
+	Owner    solana.PublicKey
+	Mint     solana.PublicKey
+	Decimals uint8
+	Change   *big.Int
+}
+
+// UiChange returns the change as a decimal string (e.g. "-1.5").
+func (c *TokenOwnerChange) UiChange() string {
+	return formatTokenAmount(c.Change, c.Decimals)
+}
+
+// BalanceChanges returns the changes of the SOL and token balances made by the transaction.
+func (res *GetTransactionResult) BalanceChanges() (*BalanceChanges, error) {
+	if res.Transaction == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+	tx, err := res.Transaction.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return NewBalanceChanges(tx, res.Meta)
+}
+
+// BalanceChanges returns the changes of the SOL and token balances made by the transaction.
+func (twm TransactionWithMeta) BalanceChanges() (*BalanceChanges, error) {
+	tx, err := twm.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return NewBalanceChanges(tx, twm.Meta)
+}
+
+// NewBalanceChanges zips the balances of the meta with the accounts of the
+// transaction: its static keys, followed by the writable and the read-only
+// loaded addresses.
+func NewBalanceChanges(tx *solana.Transaction, meta *TransactionMeta) (*BalanceChanges, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("meta is nil")
+	}
+	messageKeys, err := newMessageKeys(&tx.Message, meta)
+	if err != nil {
+		return nil, err
+	}
+	keys := messageKeys.keys
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("transaction has no accounts")
+	}
+	if len(meta.PreBalances) != len(keys) || len(meta.PostBalances) != len(keys) {
+		return nil, fmt.Errorf(
+			"got %d pre and %d post balances for %d accounts",
+			len(meta.PreBalances), len(meta.PostBalances), len(keys),
+		)
+	}
+
+	out := &BalanceChanges{
+		FeePayer: keys[0],
+		Fee:      meta.Fee,
+	}
+	for i, account := range keys {
+		pre, post := meta.PreBalances[i], meta.PostBalances[i]
+		if pre == post && i != 0 {
+			continue
+		}
+		change := &SOLBalanceChange{
+			Account: account,
+			Pre:     pre,
+			Post:    post,
+			Change:  int64(post) - int64(pre),
+			Created: pre == 0 && post > 0,
+			Closed:  pre > 0 && post == 0,
+		}
+		if i == 0 {
+			change.Fee = meta.Fee
+		}
+		out.SOL = append(out.SOL, change)
+	}
+
+	tokens := make(map[uint16]*TokenBalanceChange)
+	setToken := func(balance TokenBalance, pre bool) error {
+		if int(balance.AccountIndex) >= len(keys) {
+			return fmt.Errorf("token balance of account index %d out of range (%d accounts)", balance.AccountIndex, len(keys))
+		}
+		amount, err := parseTokenAmount(balance.UiTokenAmount)
+		if err != nil {
+			return fmt.Errorf("token balance of account %s: %w", keys[balance.AccountIndex], err)
+		}
+		change, ok := tokens[balance.AccountIndex]
+		if !ok {
+			change = &TokenBalanceChange{
+				Account: keys[balance.AccountIndex],
+				Pre:     new(big.Int),
+				Post:    new(big.Int),
+				Created: true,
+				Closed:  true,
+			}
+			tokens[balance.AccountIndex] = change
+		}
+		change.Mint = balance.Mint
+		if balance.Owner != nil {
+			change.Owner = *balance.Owner
+		}
+		if balance.ProgramId != nil {
+			change.ProgramID = *balance.ProgramId
+		}
+		if balance.UiTokenAmount != nil {
+			change.Decimals = balance.UiTokenAmount.Decimals
+		}
+		if pre {
+			change.Pre = amount
+			change.Created = false
+		} else {
+			change.Post = amount
+			change.Closed = false
+		}
+		return nil
+	}
+	for _, balance := range meta.PreTokenBalances {
+		if err := setToken(balance, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, balance := range meta.PostTokenBalances {
+		if err := setToken(balance, false); err != nil {
+			return nil, err
+		}
+	}
+
+	indices := make([]uint16, 0, len(tokens))
+	for index := range tokens {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	for _, index := range indices {
+		change := tokens[index]
+		change.Change = new(big.Int).Sub(change.Post, change.Pre)
+		if change.Change.Sign() == 0 && !change.Created && !change.Closed {
+			continue
+		}
+		out.Tokens = append(out.Tokens, change)
+	}
+	return out, nil
+}
+
+// SOLChange returns the change of the lamports of the account, or nil if its balance didn't change.
+func (c *BalanceChanges) SOLChange(account solana.PublicKey) *SOLBalanceChange {
+	for _, change := range c.SOL {
+		if change.Account.Equals(account) {
+			return change
+		}
+	}
+	return nil
+}
+
+// TokenChangesByOwner returns the changes of the token balances summed by
+// owner and mint, in the order of their first token account; the owners
+// whose balance of a mint didn't change overall are omitted.
+func (c *BalanceChanges) TokenChangesByOwner() []*TokenOwnerChange {
+	type ownerMint struct {
+		owner, mint solana.PublicKey
+	}
+	byOwner := make(map[ownerMint]*TokenOwnerChange)
+	var out []*TokenOwnerChange
+	for _, change := range c.Tokens {
+		key := ownerMint{change.Owner, change.Mint}
+		sum, ok := byOwner[key]
+		if !ok {
+			sum = &TokenOwnerChange{
+				Owner:    change.Owner,
+				Mint:     change.Mint,
+				Decimals: change.Decimals,
+				Change:   new(big.Int),
+			}
+			byOwner[key] = sum
+			out = append(out, sum)
+		}
+		sum.Change.Add(sum.Change, change.Change)
+	}
+	filtered := out[:0]
+	for _, sum := range out {
+		if sum.Change.Sign() != 0 {
+			filtered = append(filtered, sum)
+		}
+	}
+	return filtered
+}
+
+func parseTokenAmount(amount *UiTokenAmount) (*big.Int, error) {
+	if amount == nil {
+		return new(big.Int), nil
+	}
+	out, ok := new(big.Int).SetString(amount.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token amount %q", amount.Amount)
+	}
+	return out, nil
+}
+
+// formatTokenAmount renders a raw token amount with the provided decimals.
+func formatTokenAmount(amount *big.Int, decimals uint8) string {
+	str := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		if len(str) <= int(decimals) {
+			str = strings.Repeat("0", int(decimals)-len(str)+1) + str
+		}
+		point := len(str) - int(decimals)
+		str = strings.TrimRight(str[:point]+"."+str[point:], "0")
+		str = strings.TrimSuffix(str, ".")
+	}
+	if amount.Sign() < 0 {
+		str = "-" + str
+	}
+	return str
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBalanceChanges(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	recipient := solana.NewWallet().PublicKey()
+	closed := solana.NewWallet().PublicKey()
+	unchanged := solana.NewWallet().PublicKey()
+	loaded := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	owner := solana.NewWallet().PublicKey()
+
+	tx := &solana.Transaction{
+		Message: solana.Message{
+			Header:      solana.MessageHeader{NumRequiredSignatures: 1},
+			AccountKeys: solana.PublicKeySlice{payer, recipient, closed, unchanged},
+			AddressTableLookups: []solana.MessageAddressTableLookup{
+				{AccountKey: solana.NewWallet().PublicKey(), WritableIndexes: []uint8{0}},
+			},
+		},
+	}
+	tx.Message.SetVersion(solana.MessageVersionV0)
+
+	tokenBalance := func(index uint16, owner solana.PublicKey, amount string) TokenBalance {
+		return TokenBalance{
+			AccountIndex:  index,
+			Owner:         &owner,
+			ProgramId:     &solana.TokenProgramID,
+			Mint:          mint,
+			UiTokenAmount: &UiTokenAmount{Amount: amount, Decimals: 6},
+		}
+	}
+	meta := &TransactionMeta{
+		Fee:          5000,
+		PreBalances:  []uint64{10_000_000, 0, 2_039_280, 7, 1_000},
+		PostBalances: []uint64{7_957_720, 2_039_280, 0, 7, 3_000},
+		PreTokenBalances: []TokenBalance{
+			tokenBalance(2, payer, "1500000"),
+			tokenBalance(3, owner, "18446744073709551615"),
+		},
+		PostTokenBalances: []TokenBalance{
+			tokenBalance(1, owner, "1500000"),
+			tokenBalance(3, owner, "18446744073709551615"),
+		},
+		LoadedAddresses: LoadedAddresses{Writable: solana.PublicKeySlice{loaded}},
+	}
+
+	changes, err := NewBalanceChanges(tx, meta)
+	require.NoError(t, err)
+	require.Equal(t, payer, changes.FeePayer)
+	require.Equal(t, uint64(5000), changes.Fee)
+
+	require.Equal(t, []*SOLBalanceChange{
+		{Account: payer, Pre: 10_000_000, Post: 7_957_720, Change: -2_042_280, Fee: 5000},
+		{Account: recipient, Pre: 0, Post: 2_039_280, Change: 2_039_280, Created: true},
+		{Account: closed, Pre: 2_039_280, Post: 0, Change: -2_039_280, Closed: true},
+		{Account: loaded, Pre: 1_000, Post: 3_000, Change: 2_000},
+	}, changes.SOL)
+	require.Equal(t, int64(-2_037_280), changes.SOLChange(payer).ChangeExcludingFee())
+	require.Nil(t, changes.SOLChange(unchanged))
+
+	require.Len(t, changes.Tokens, 2)
+	created, closedToken := changes.Tokens[0], changes.Tokens[1]
+	require.Equal(t, recipient, created.Account)
+	require.True(t, created.Created)
+	require.Equal(t, owner, created.Owner)
+	require.Equal(t, big.NewInt(1_500_000), created.Change)
+	require.Equal(t, "1.5", created.UiChange())
+	require.Equal(t, closed, closedToken.Account)
+	require.True(t, closedToken.Closed)
+	require.Equal(t, payer, closedToken.Owner)
+	require.Equal(t, solana.TokenProgramID, closedToken.ProgramID)
+	require.Equal(t, "-1.5", closedToken.UiChange())
+
+	byOwner := changes.TokenChangesByOwner()
+	require.Len(t, byOwner, 2)
+	require.Equal(t, owner, byOwner[0].Owner)
+	require.Equal(t, mint, byOwner[0].Mint)
+	require.Equal(t, "1.5", byOwner[0].UiChange())
+	require.Equal(t, payer, byOwner[1].Owner)
+	require.Equal(t, "-1.5", byOwner[1].UiChange())
+
+	require.Equal(t, "-0.000001", formatTokenAmount(big.NewInt(-1), 6))
+	require.Equal(t, "120", formatTokenAmount(big.NewInt(120), 0))
+
+	meta.PostBalances = meta.PostBalances[:4]
+	_, err = NewBalanceChanges(tx, meta)
+	require.Error(t, err)
+}
@@ -0,0 +1,485 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// Interaction is a single recorded JSON-RPC request/response pair.
+// A cassette file contains one JSON-encoded Interaction per line.
+type Interaction struct {
+	Method string             `json:"method"`
+	Params stdjson.RawMessage `json:"params,omitempty"`
+
+	// Result is the raw `result` field of the response.
+	Result stdjson.RawMessage `json:"result,omitempty"`
+	// Error is the `error` field of the response, if the call failed.
+	Error *jsonrpc.RPCError `json:"error,omitempty"`
+}
+
+type CassetteMatchMode int
+
+const (
+	// CassetteMatchStrict requires the calls to be replayed
+	// in exactly the same order they were recorded in,
+	// with the same method and params.
+	CassetteMatchStrict CassetteMatchMode = iota
+	// CassetteMatchLenient matches calls by method and params
+	// regardless of order; once all the interactions matching
+	// a call have been used, the last one is replayed again
+	// (useful for polling calls like getSignatureStatuses).
+	CassetteMatchLenient
+)
+
+var ErrInteractionNotFound = errors.New("no recorded interaction matches the call")
+
+// ReadCassette reads all the interactions stored in the JSONL cassette file at path.
+func ReadCassette(path string) ([]*Interaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	out := make([]*Interaction, 0)
+	scanner := bufio.NewScanner(file)
+	// Account data and blocks can make for very long lines.
+	scanner.Buffer(make([]byte, 0, 64*1024), 512*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var interaction Interaction
+		if err := stdjson.Unmarshal(line, &interaction); err != nil {
+			return nil, fmt.Errorf("cassette %s line %d: %w", path, lineNum, err)
+		}
+		interaction.Params, err = normalizeParams(interaction.Params)
+		if err != nil {
+			return nil, fmt.Errorf("cassette %s line %d: %w", path, lineNum, err)
+		}
+		out = append(out, &interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// normalizeParams re-encodes the provided params
+// so that equal params always have the same representation
+// (e.g. object keys are sorted, whitespace is removed).
+func normalizeParams(params interface{}) (stdjson.RawMessage, error) {
+	var buf []byte
+	switch v := params.(type) {
+	case stdjson.RawMessage:
+		buf = v
+	case nil:
+	default:
+		var err error
+		buf, err = json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	var generic interface{}
+	decoder := stdjson.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	if generic == nil {
+		return nil, nil
+	}
+	return stdjson.Marshal(generic)
+}
+
+var _ JSONRPCClient = &CassetteRecorder{}
+
+// CassetteRecorder is a JSONRPCClient that records
+// the traffic of the JSONRPCClient it wraps to a cassette file.
+type CassetteRecorder struct {
+	rpcClient JSONRPCClient
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewWithRecorder wraps the provided JSONRPCClient and appends every
+// request/response pair that goes through it to the JSONL cassette file at cassettePath.
+// The cassette can then be replayed with NewWithReplayer.
+// Transport-level failures (e.g. network errors) are returned but not recorded.
+func NewWithRecorder(
+	rpcClient JSONRPCClient,
+	cassettePath string,
+) (*CassetteRecorder, error) {
+	file, err := os.OpenFile(cassettePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &CassetteRecorder{
+		rpcClient: rpcClient,
+		file:      file,
+	}, nil
+}
+
+func (rec *CassetteRecorder) record(method string, params interface{}, result stdjson.RawMessage, rpcErr *jsonrpc.RPCError) error {
+	normalized, err := normalizeParams(params)
+	if err != nil {
+		return fmt.Errorf("unable to normalize params of %s: %w", method, err)
+	}
+	buf, err := stdjson.Marshal(Interaction{
+		Method: method,
+		Params: normalized,
+		Result: result,
+		Error:  rpcErr,
+	})
+	if err != nil {
+		return err
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	_, err = rec.file.Write(append(buf, '\n'))
+	return err
+}
+
+func (rec *CassetteRecorder) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	var raw stdjson.RawMessage
+	err := rec.rpcClient.CallForInto(ctx, &raw, method, params)
+	if err != nil {
+		var rpcErr *jsonrpc.RPCError
+		if errors.As(err, &rpcErr) {
+			if recErr := rec.record(method, params, nil, rpcErr); recErr != nil {
+				return recErr
+			}
+		}
+		return err
+	}
+	if len(raw) == 0 {
+		raw = stdjson.RawMessage(`null`)
+	}
+	if err := rec.record(method, params, raw, nil); err != nil {
+		return err
+	}
+	return unmarshalResult(raw, out)
+}
+
+func (rec *CassetteRecorder) CallWithCallback(
+	ctx context.Context,
+	method string,
+	params []interface{},
+	callback func(*http.Request, *http.Response) error,
+) error {
+	return rec.rpcClient.CallWithCallback(
+		ctx,
+		method,
+		params,
+		func(httpRequest *http.Request, httpResponse *http.Response) error {
+			body, err := ioutil.ReadAll(httpResponse.Body)
+			if err != nil {
+				return err
+			}
+			httpResponse.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			var rpcResponse jsonrpc.RPCResponse
+			if err := json.Unmarshal(body, &rpcResponse); err == nil {
+				if err := rec.record(method, params, rpcResponse.Result, rpcResponse.Error); err != nil {
+					return err
+				}
+			}
+			return callback(httpRequest, httpResponse)
+		},
+	)
+}
+
+func (rec *CassetteRecorder) CallBatch(
+	ctx context.Context,
+	requests jsonrpc.RPCRequests,
+) (jsonrpc.RPCResponses, error) {
+	responses, err := rec.rpcClient.CallBatch(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		resp := findResponseByID(responses, req.ID)
+		if resp == nil {
+			continue
+		}
+		if err := rec.record(req.Method, req.Params, resp.Result, resp.Error); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// Close closes the cassette file and the wrapped client.
+func (rec *CassetteRecorder) Close() error {
+	rec.mu.Lock()
+	err := rec.file.Close()
+	rec.mu.Unlock()
+	if c, ok := rec.rpcClient.(io.Closer); ok {
+		if closeErr := c.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// findResponseByID returns the response with the provided ID;
+// IDs are compared by their textual representation
+// because decoded numeric IDs are json.Number values.
+func findResponseByID(responses jsonrpc.RPCResponses, id any) *jsonrpc.RPCResponse {
+	want := fmt.Sprint(id)
+	for _, resp := range responses {
+		if resp != nil && fmt.Sprint(resp.ID) == want {
+			return resp
+		}
+	}
+	return nil
+}
+
+func unmarshalResult(raw stdjson.RawMessage, out interface{}) error {
+	if out == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr {
+		return fmt.Errorf("expected a pointer, got a value: %s", reflect.TypeOf(out))
+	}
+	return json.Unmarshal(raw, out)
+}
+
+var _ JSONRPCClient = &CassetteReplayer{}
+
+// CassetteReplayer is a JSONRPCClient that replays
+// the interactions recorded by a CassetteRecorder.
+type CassetteReplayer struct {
+	mode CassetteMatchMode
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+	next         int
+}
+
+// NewWithReplayer creates a JSONRPCClient that never touches the network,
+// and instead serves the responses recorded in the JSONL cassette file at cassettePath.
+// Calls that have no matching recorded interaction fail with ErrInteractionNotFound.
+//
+// Use it with NewWithCustomRPCClient:
+//
+//	replayer, err := rpc.NewWithReplayer("testdata/mainnet.jsonl", rpc.CassetteMatchLenient)
+//	client := rpc.NewWithCustomRPCClient(replayer)
+func NewWithReplayer(
+	cassettePath string,
+	mode CassetteMatchMode,
+) (*CassetteReplayer, error) {
+	interactions, err := ReadCassette(cassettePath)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithInteractions(interactions, mode), nil
+}
+
+// NewWithInteractions is like NewWithReplayer, but uses the provided interactions
+// instead of reading them from a cassette file.
+func NewWithInteractions(
+	interactions []*Interaction,
+	mode CassetteMatchMode,
+) *CassetteReplayer {
+	return &CassetteReplayer{
+		mode:         mode,
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+func (rep *CassetteReplayer) find(method string, params interface{}) (*Interaction, error) {
+	normalized, err := normalizeParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to normalize params of %s: %w", method, err)
+	}
+	matches := func(interaction *Interaction) bool {
+		return interaction.Method == method && bytes.Equal(interaction.Params, normalized)
+	}
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	switch rep.mode {
+	case CassetteMatchStrict:
+		if rep.next >= len(rep.interactions) {
+			return nil, fmt.Errorf("%w: %s(%s): cassette exhausted", ErrInteractionNotFound, method, normalized)
+		}
+		interaction := rep.interactions[rep.next]
+		if !matches(interaction) {
+			return nil, fmt.Errorf(
+				"%w: %s(%s): expected %s(%s) at position %d",
+				ErrInteractionNotFound,
+				method, normalized,
+				interaction.Method, interaction.Params,
+				rep.next,
+			)
+		}
+		rep.used[rep.next] = true
+		rep.next++
+		return interaction, nil
+	case CassetteMatchLenient:
+		lastMatch := -1
+		for i, interaction := range rep.interactions {
+			if !matches(interaction) {
+				continue
+			}
+			if !rep.used[i] {
+				rep.used[i] = true
+				return interaction, nil
+			}
+			lastMatch = i
+		}
+		if lastMatch >= 0 {
+			return rep.interactions[lastMatch], nil
+		}
+		return nil, fmt.Errorf("%w: %s(%s)", ErrInteractionNotFound, method, normalized)
+	default:
+		return nil, fmt.Errorf("unknown cassette match mode: %v", rep.mode)
+	}
+}
+
+func (rep *CassetteReplayer) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	interaction, err := rep.find(method, params)
+	if err != nil {
+		return err
+	}
+	if interaction.Error != nil {
+		return interaction.Error
+	}
+	result := interaction.Result
+	if len(result) == 0 {
+		result = stdjson.RawMessage(`null`)
+	}
+	return unmarshalResult(result, out)
+}
+
+func (rep *CassetteReplayer) CallWithCallback(
+	ctx context.Context,
+	method string,
+	params []interface{},
+	callback func(*http.Request, *http.Response) error,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	interaction, err := rep.find(method, params)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(&jsonrpc.RPCResponse{
+		JSONRPC: "2.0",
+		Result:  interaction.Result,
+		Error:   interaction.Error,
+		ID:      1,
+	})
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://cassette.invalid", nil)
+	if err != nil {
+		return err
+	}
+	httpResponse := &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       httpRequest,
+	}
+	defer httpResponse.Body.Close()
+	return callback(httpRequest, httpResponse)
+}
+
+func (rep *CassetteReplayer) CallBatch(
+	ctx context.Context,
+	requests jsonrpc.RPCRequests,
+) (jsonrpc.RPCResponses, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("empty request list")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	responses := make(jsonrpc.RPCResponses, 0, len(requests))
+	for i, req := range requests {
+		interaction, err := rep.find(req.Method, req.Params)
+		if err != nil {
+			return nil, err
+		}
+		// Same ID assignment as jsonrpc.RPCClient.CallBatch.
+		req.ID = i
+		req.JSONRPC = "2.0"
+		responses = append(responses, &jsonrpc.RPCResponse{
+			JSONRPC: "2.0",
+			Result:  interaction.Result,
+			Error:   interaction.Error,
+			ID:      req.ID,
+		})
+	}
+	return responses, nil
+}
+
+// Unused returns the recorded interactions that have not been replayed yet.
+// Tests can use it to assert that all the recorded traffic was consumed.
+func (rep *CassetteReplayer) Unused() []*Interaction {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	out := make([]*Interaction, 0)
+	for i, interaction := range rep.interactions {
+		if !rep.used[i] {
+			out = append(out, interaction)
+		}
+	}
+	return out
+}
+
+// Close is a no-op.
+func (rep *CassetteReplayer) Close() error {
+	return nil
+}
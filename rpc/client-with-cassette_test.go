@@ -0,0 +1,146 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	responseBody := `{"context":{"slot":83986105},"value":123456}`
+	server, closer := mockJSONRPC(t, stdjson.RawMessage(wrapIntoRPC(responseBody)))
+	defer closer()
+
+	cassettePath := filepath.Join(t.TempDir(), "cassette.jsonl")
+	pubKey := solana.MustPublicKeyFromBase58("7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932")
+
+	{
+		recorder, err := NewWithRecorder(jsonrpc.NewClient(server.URL), cassettePath)
+		require.NoError(t, err)
+		client := NewWithCustomRPCClient(recorder)
+
+		out, err := client.GetBalance(context.Background(), pubKey, CommitmentFinalized)
+		require.NoError(t, err)
+		require.Equal(t, uint64(123456), out.Value)
+		require.NoError(t, client.Close())
+	}
+
+	interactions, err := ReadCassette(cassettePath)
+	require.NoError(t, err)
+	require.Len(t, interactions, 1)
+	require.Equal(t, "getBalance", interactions[0].Method)
+	require.JSONEq(t, `["7xLk17EQQ5KLDLDe44wCmupJKJjTGd8hs3eSVVhCx932",{"commitment":"finalized"}]`, string(interactions[0].Params))
+	require.JSONEq(t, responseBody, string(interactions[0].Result))
+
+	{
+		replayer, err := NewWithReplayer(cassettePath, CassetteMatchStrict)
+		require.NoError(t, err)
+		client := NewWithCustomRPCClient(replayer)
+
+		out, err := client.GetBalance(context.Background(), pubKey, CommitmentFinalized)
+		require.NoError(t, err)
+		require.Equal(t, uint64(83986105), out.Context.Slot)
+		require.Equal(t, uint64(123456), out.Value)
+		require.Empty(t, replayer.Unused())
+
+		// Strict mode: the cassette is exhausted.
+		_, err = client.GetBalance(context.Background(), pubKey, CommitmentFinalized)
+		require.True(t, errors.Is(err, ErrInteractionNotFound))
+	}
+}
+
+func TestCassette_Replay(t *testing.T) {
+	interactions := []*Interaction{
+		{
+			Method: "getSlot",
+			Params: stdjson.RawMessage(`[{"commitment":"finalized"}]`),
+			Result: stdjson.RawMessage(`1`),
+		},
+		{
+			Method: "getSlot",
+			Params: stdjson.RawMessage(`[{"commitment":"finalized"}]`),
+			Result: stdjson.RawMessage(`2`),
+		},
+		{
+			Method: "getBlockHeight",
+			Params: stdjson.RawMessage(`[{"commitment":"finalized"}]`),
+			Error:  &jsonrpc.RPCError{Code: -32004, Message: "Block not available"},
+		},
+	}
+	for _, interaction := range interactions {
+		var err error
+		interaction.Params, err = normalizeParams(interaction.Params)
+		require.NoError(t, err)
+	}
+	ctx := context.Background()
+
+	t.Run("strict", func(t *testing.T) {
+		client := NewWithCustomRPCClient(NewWithInteractions(interactions, CassetteMatchStrict))
+
+		_, err := client.GetBlockHeight(ctx, CommitmentFinalized)
+		require.True(t, errors.Is(err, ErrInteractionNotFound))
+
+		slot, err := client.GetSlot(ctx, CommitmentFinalized)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), slot)
+
+		slot, err = client.GetSlot(ctx, CommitmentFinalized)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), slot)
+
+		_, err = client.GetBlockHeight(ctx, CommitmentFinalized)
+		var rpcErr *jsonrpc.RPCError
+		require.True(t, errors.As(err, &rpcErr))
+		require.Equal(t, -32004, rpcErr.Code)
+	})
+	t.Run("lenient", func(t *testing.T) {
+		replayer := NewWithInteractions(interactions, CassetteMatchLenient)
+		client := NewWithCustomRPCClient(replayer)
+
+		_, err := client.GetBlockHeight(ctx, CommitmentFinalized)
+		require.Error(t, err)
+
+		for _, expected := range []uint64{1, 2, 2, 2} {
+			slot, err := client.GetSlot(ctx, CommitmentFinalized)
+			require.NoError(t, err)
+			require.Equal(t, expected, slot)
+		}
+		require.Empty(t, replayer.Unused())
+
+		_, err = client.GetSlot(ctx, CommitmentConfirmed)
+		require.True(t, errors.Is(err, ErrInteractionNotFound))
+	})
+	t.Run("batch", func(t *testing.T) {
+		client := NewWithCustomRPCClient(NewWithInteractions(interactions, CassetteMatchLenient))
+
+		responses, err := client.RPCCallBatch(ctx, jsonrpc.RPCRequests{
+			jsonrpc.NewRequest("getSlot", []interface{}{M{"commitment": "finalized"}}),
+			jsonrpc.NewRequest("getBlockHeight", []interface{}{M{"commitment": "finalized"}}),
+		})
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+		require.Equal(t, stdjson.RawMessage(`1`), responses[0].Result)
+		require.NotNil(t, responses[1].Error)
+	})
+}
@@ -24,6 +24,9 @@ import (
 	stdjson "encoding/json"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/AlekSi/pointer"
@@ -3123,3 +3126,292 @@ func TestClient_GetRecentPrioritizationFees(t *testing.T) {
 
 	assert.Equal(t, expected, got, "both deserialized values must be equal")
 }
+
+func TestClient_GetTransaction_resolveLookups(t *testing.T) {
+	const txV0Base64 = "Alkhq/BfGdBeok4oBP21xAwT4oO/R5PvkKqbCTq4sHHRsto+uDQCFcdp8hXh1g5D3mTh8GAJW8xE+EDD27f9IweTkH2Afiu4h5aM+Xbo0mklc0/Vi1xawd7SZVbstXDLtWdoJaf4Zt+20F/SasURzw/P4dkD+Q6BjgUNHT+vg5gOgAIBAQgaJV0Ch/DG6XwNcizWbI7STLgSbIOrg0Dl67Oo30WU1uA/NIbYLPRmuLarIJ4J0CcN3IWEm4Gf8675KhnXef2LaDXzjFgWVSbAO2yyTF6dK1oO3gTExie957LXDwu6oJMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAVKU1qZKSEGTSTocWDaOHx8NbXdvJK7geQfqEBBBUSN1LfoiB9oYLDSHJL9rjAlchZhn+fd/23ACfq0oIGla54pt5JT0MdBTJhQI+z7dnVsisw2xWwW+vFSTs97l0tJPxmv9kxpXbHYZFenDpT2s6CT75/9QNFVTkHFLMK+UG6VlyFnQmYh1aMkGtq3c6TIOsk32S6XMUnN9DQgFGQq4lwEAwIAAgwCAAAAgJaYAAAAAAADAgAFDAIAAACAlpgAAAAAAAMCAAYMAgAAAICWmAAAAAAABAAMSGVsbG8gRmFiaW8hAX5s37FH6IeB4QeMYxD4LtpXf1DaupH/ro7W+kEQnofaAgECAQA="
+	meta := `"meta":{"err":null,"fee":5000,"innerInstructions":[],"logMessages":[],"postBalances":[],"preBalances":[],"rewards":[],"loadedAddresses":{"writable":["FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j","3or4uF7ZyuQW5GGmcmdXDJasNiSZUURF2az1UrRPYQTg"],"readonly":["2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc"]}}`
+	jsonTransaction := `{"message":{"accountKeys":["2m4eNwBVqu6SgFk23HgE3W5MW89yT5z1vspz2WsiFBHF","G6NDx85GM481GPjT5kUBAvjLxzDMsgRMQ1EAxzGswEJn","81o7hHYN5a8fc5wdjjfznK9ziJ9wcuKXwbZnuYpanxMQ","11111111111111111111111111111111","MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr","FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j","3or4uF7ZyuQW5GGmcmdXDJasNiSZUURF2az1UrRPYQTg","2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc"],"header":{"numReadonlySignedAccounts":1,"numReadonlyUnsignedAccounts":1,"numRequiredSignatures":2},"instructions":[{"accounts":[0,8,9,10],"data":"3yZe7d","programIdIndex":3}],"recentBlockhash":"BAx74QRmMwhnTytrPoG5ogw2BQn4CdhB14jxJnbDMUS7","addressTableLookups":[{"accountKey":"9WWfC3y4uCNofr2qEFHSVUXkCxW99JiYkMWmSZvVt8j3","writableIndexes":[1,2],"readonlyIndexes":[0]}]},"signatures":[]}`
+
+	for name, transaction := range map[string]string{
+		"base64": `["` + txV0Base64 + `","base64"]`,
+		"json":   jsonTransaction,
+	} {
+		t.Run(name, func(t *testing.T) {
+			responseBody := `{"blockTime":1624821990,"slot":83311386,"version":0,` + meta + `,"transaction":` + transaction + `}`
+			server, closer := mockJSONRPC(t, stdjson.RawMessage(wrapIntoRPC(responseBody)))
+			defer closer()
+			client := New(server.URL)
+
+			out, err := client.GetTransaction(context.Background(), solana.Signature{}, nil)
+			require.NoError(t, err)
+
+			for _, get := range []func() (*solana.Transaction, error){
+				out.Transaction.GetTransaction,
+				TransactionWithMeta{Transaction: transactionData(t, transaction), Meta: out.Meta}.GetTransaction,
+			} {
+				tx, err := get()
+				require.NoError(t, err)
+				require.True(t, tx.Message.IsResolved())
+				require.True(t, tx.Message.IsVersioned())
+
+				metas, err := tx.Message.AccountMetaList()
+				require.NoError(t, err)
+				require.Len(t, metas, 11)
+				require.Equal(t, solana.Meta(solana.MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j")).WRITE(), metas[8])
+				require.Equal(t, solana.Meta(solana.MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc")), metas[10])
+			}
+		})
+	}
+}
+
+func transactionData(t *testing.T, transaction string) *DataBytesOrJSON {
+	var data DataBytesOrJSON
+	require.NoError(t, data.UnmarshalJSON([]byte(transaction)))
+	return &data
+}
+
+func TestClient_GetAsset(t *testing.T) {
+	responseBody := `{"interface":"ProgrammableNFT","id":"F9Lw3ki3hJ7PF9HQXsBzoY8GyE6sPoEZZdXJBsTTD2rk","content":{"$schema":"https://schema.metaplex.com/nft1.0.json","json_uri":"https://example.com/1.json","files":[{"uri":"https://example.com/1.png","mime":"image/png"}],"metadata":{"name":"NFT #1","symbol":"NFT","attributes":[{"trait_type":"level","value":3}]},"links":{"image":"https://example.com/1.png","external_url":null}},"authorities":[{"address":"2RtGg6fsFiiF1EQzHqbd66AhW7R5bWeQGpTbv2UMkCdW","scopes":["full"]}],"compression":{"eligible":false,"compressed":false,"data_hash":"","creator_hash":"","asset_hash":"","tree":"","seq":0,"leaf_id":0},"grouping":[{"group_key":"collection","group_value":"J1S9H3QjnRtBbbuD4HjPV6RpRhwuk4zKbxsnCHuTgh9w"}],"royalty":{"royalty_model":"creators","target":null,"percent":0.05,"basis_points":500,"primary_sale_happened":true,"locked":false},"creators":[{"address":"2RtGg6fsFiiF1EQzHqbd66AhW7R5bWeQGpTbv2UMkCdW","share":100,"verified":true}],"ownership":{"frozen":true,"delegated":false,"delegate":null,"ownership_model":"single","owner":"A4w7fG4kKj8jqrXrjUA4AbBCAf3w66mJr3M4vUhpx6Ek"},"supply":{"print_max_supply":0,"print_current_supply":0,"edition_nonce":254},"mutable":true,"burnt":false}`
+	server, closer := mockJSONRPC(t, stdjson.RawMessage(wrapIntoRPC(responseBody)))
+	defer closer()
+	client := New(server.URL)
+
+	id := solana.MustPublicKeyFromBase58("F9Lw3ki3hJ7PF9HQXsBzoY8GyE6sPoEZZdXJBsTTD2rk")
+	out, err := client.GetAssetWithOpts(context.Background(), id, &DASDisplayOptions{ShowCollectionMetadata: true})
+	require.NoError(t, err)
+
+	// the ID is random, so we can't assert it; let's check that it is set, and then remove it
+	reqBody := server.RequestBody(t)
+	assert.NotNil(t, reqBody["id"])
+	reqBody["id"] = any(nil)
+
+	assert.Equal(t,
+		map[string]interface{}{
+			"id":      any(nil),
+			"jsonrpc": "2.0",
+			"method":  "getAsset",
+			"params": map[string]interface{}{
+				"id":      id.String(),
+				"options": map[string]interface{}{"showCollectionMetadata": true},
+			},
+		},
+		reqBody,
+	)
+
+	assert.Equal(t, AssetInterfaceProgrammableNFT, out.Interface)
+	assert.Equal(t, id, out.ID)
+	assert.Equal(t, "NFT #1", out.Content.Metadata.Name)
+	assert.Equal(t, float64(3), out.Content.Metadata.Attributes[0].Value)
+	assert.Nil(t, out.Royalty.Target)
+	assert.Equal(t, uint64(500), out.Royalty.BasisPoints)
+	assert.Equal(t, "A4w7fG4kKj8jqrXrjUA4AbBCAf3w66mJr3M4vUhpx6Ek", out.Ownership.Owner)
+	assert.Equal(t, uint64(254), *out.Supply.EditionNonce)
+	collection, ok := out.Collection()
+	assert.True(t, ok)
+	assert.Equal(t, solana.MustPublicKeyFromBase58("J1S9H3QjnRtBbbuD4HjPV6RpRhwuk4zKbxsnCHuTgh9w"), collection)
+}
+
+func TestClient_GetAssetsByOwner(t *testing.T) {
+	responseBody := `{"total":1,"limit":10,"page":2,"items":[{"interface":"V1_NFT","id":"F9Lw3ki3hJ7PF9HQXsBzoY8GyE6sPoEZZdXJBsTTD2rk","mutable":false,"burnt":false}]}`
+	server, closer := mockJSONRPC(t, stdjson.RawMessage(wrapIntoRPC(responseBody)))
+	defer closer()
+	client := New(server.URL)
+
+	owner := solana.MustPublicKeyFromBase58("A4w7fG4kKj8jqrXrjUA4AbBCAf3w66mJr3M4vUhpx6Ek")
+	out, err := client.GetAssetsByOwner(context.Background(), owner, &DASListOpts{
+		DASPagination: DASPagination{Limit: 10, Page: 2},
+		SortBy:        &DASSorting{SortBy: DASSortByCreated, SortDirection: DASSortDesc},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		map[string]interface{}{
+			"ownerAddress": owner.String(),
+			"limit":        float64(10),
+			"page":         float64(2),
+			"sortBy":       map[string]interface{}{"sortBy": "created", "sortDirection": "desc"},
+		},
+		server.RequestBody(t)["params"],
+	)
+	assert.Equal(t, uint64(2), out.Page)
+	assert.Len(t, out.Items, 1)
+	assert.Equal(t, AssetInterfaceV1NFT, out.Items[0].Interface)
+
+	// Without options, only the owner.
+	_, err = client.GetAssetsByOwner(context.Background(), owner, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ownerAddress": owner.String()}, server.RequestBody(t)["params"])
+}
+
+func TestClient_SearchAssets(t *testing.T) {
+	server, closer := mockJSONRPC(t, stdjson.RawMessage(wrapIntoRPC(`{"total":0,"limit":1000,"cursor":"","items":[]}`)))
+	defer closer()
+	client := New(server.URL)
+
+	creator := solana.MustPublicKeyFromBase58("2RtGg6fsFiiF1EQzHqbd66AhW7R5bWeQGpTbv2UMkCdW")
+	_, err := client.SearchAssets(context.Background(), &SearchAssetsParams{
+		CreatorAddress:  &creator,
+		CreatorVerified: NewBoolean(true),
+		Grouping:        []string{"collection", "J1S9H3QjnRtBbbuD4HjPV6RpRhwuk4zKbxsnCHuTgh9w"},
+		DASListOpts:     DASListOpts{DASPagination: DASPagination{Cursor: "abc"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		map[string]interface{}{
+			"creatorAddress":  creator.String(),
+			"creatorVerified": true,
+			"grouping":        []interface{}{"collection", "J1S9H3QjnRtBbbuD4HjPV6RpRhwuk4zKbxsnCHuTgh9w"},
+			"cursor":          "abc",
+		},
+		server.RequestBody(t)["params"],
+	)
+}
+
+func TestClient_GetAssetProof(t *testing.T) {
+	responseBody := `{"root":"2o6Y6EiY3WXhoaEpei2pHmHLYnHDcEQVhgD89GrGHDBH","proof":["EmJXiXEAhEN3FfNQtBa5hwR8LC5kHvdLsaGCoERosZjK","7NEfhcNPAwbw3L87fjsPqTz2fQdd1CjoLE138SD58FDQ"],"node_index":16384,"leaf":"6YdZXw49M97mfFTwgQb6kxM2c6eqZkHSaW9XhhoZXtzv","tree_id":"2kuTFCcjbV22wvUmtmgsFR7cas7eZUzAu96jzJUvUcb7"}`
+	server, closer := mockJSONRPC(t, stdjson.RawMessage(wrapIntoRPC(responseBody)))
+	defer closer()
+	client := New(server.URL)
+
+	out, err := client.GetAssetProof(context.Background(), solana.MustPublicKeyFromBase58("F9Lw3ki3hJ7PF9HQXsBzoY8GyE6sPoEZZdXJBsTTD2rk"))
+	require.NoError(t, err)
+	assert.Equal(t, solana.MustHashFromBase58("2o6Y6EiY3WXhoaEpei2pHmHLYnHDcEQVhgD89GrGHDBH"), out.Root)
+	assert.Len(t, out.Proof, 2)
+	assert.Equal(t, uint64(16384), out.NodeIndex)
+	assert.Equal(t, solana.MustPublicKeyFromBase58("2kuTFCcjbV22wvUmtmgsFR7cas7eZUzAu96jzJUvUcb7"), out.TreeID)
+}
+
+func TestClient_GetSignaturesForAsset(t *testing.T) {
+	responseBody := `{"total":1,"limit":1000,"page":1,"items":[["5nLi8m72bU6PBcz4Xrk23P6KTGy9ufF92kZiQXjTv9ELgkUxrNaiCGhMF4vh6RAcisw9DEQWJt9ogM3G2uCuwwV7","Transfer"]]}`
+	server, closer := mockJSONRPC(t, stdjson.RawMessage(wrapIntoRPC(responseBody)))
+	defer closer()
+	client := New(server.URL)
+
+	out, err := client.GetSignaturesForAsset(context.Background(), solana.MustPublicKeyFromBase58("F9Lw3ki3hJ7PF9HQXsBzoY8GyE6sPoEZZdXJBsTTD2rk"), &DASPagination{Page: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []*AssetSignature{{
+		Signature: solana.MustSignatureFromBase58("5nLi8m72bU6PBcz4Xrk23P6KTGy9ufF92kZiQXjTv9ELgkUxrNaiCGhMF4vh6RAcisw9DEQWJt9ogM3G2uCuwwV7"),
+		Type:      "Transfer",
+	}}, out.Items)
+}
+
+func TestPaginate(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4}, {5}}
+
+	var got []int
+	var requested []DASPagination
+	err := Paginate(context.Background(), PaginateByPage, DASPagination{Limit: 2},
+		func(ctx context.Context, pagination DASPagination) (*DASList[int], error) {
+			requested = append(requested, pagination)
+			return &DASList[int]{Items: pages[pagination.Page-1]}, nil
+		},
+		func(items []int) error {
+			got = append(got, items...)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+	assert.Equal(t, []DASPagination{{Limit: 2, Page: 1}, {Limit: 2, Page: 2}, {Limit: 2, Page: 3}}, requested)
+
+	got, requested = nil, nil
+	cursors := map[string]*DASList[int]{
+		"":  {Items: []int{1, 2}, Cursor: "a"},
+		"a": {Items: []int{3, 4}, Cursor: "b"},
+		"b": {Items: []int{}, Cursor: ""},
+	}
+	err = Paginate(context.Background(), PaginateByCursor, DASPagination{Limit: 2},
+		func(ctx context.Context, pagination DASPagination) (*DASList[int], error) {
+			requested = append(requested, pagination)
+			return cursors[pagination.Cursor], nil
+		},
+		func(items []int) error {
+			got = append(got, items...)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, got)
+	assert.Len(t, requested, 3)
+
+	// Starting from the caller's cursor; a nil page ends the iteration.
+	got, requested = nil, nil
+	delete(cursors, "b")
+	err = Paginate(context.Background(), PaginateByCursor, DASPagination{Limit: 2, Cursor: "a"},
+		func(ctx context.Context, pagination DASPagination) (*DASList[int], error) {
+			requested = append(requested, pagination)
+			return cursors[pagination.Cursor], nil
+		},
+		func(items []int) error {
+			got = append(got, items...)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, got)
+	assert.Equal(t, []DASPagination{{Limit: 2, Cursor: "a"}, {Limit: 2, Cursor: "b"}}, requested)
+
+	// Starting from the caller's page.
+	got, requested = nil, nil
+	err = Paginate(context.Background(), PaginateByPage, DASPagination{Limit: 2, Page: 2},
+		func(ctx context.Context, pagination DASPagination) (*DASList[int], error) {
+			requested = append(requested, pagination)
+			return &DASList[int]{Items: pages[pagination.Page-1]}, nil
+		},
+		func(items []int) error {
+			got = append(got, items...)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, got)
+	assert.Equal(t, []DASPagination{{Limit: 2, Page: 2}, {Limit: 2, Page: 3}}, requested)
+}
+
+func TestClient_GetAllAssetsByOwner_CappedLimit(t *testing.T) {
+	ids := []string{
+		"F9Lw3ki3hJ7PF9HQXsBzoY8GyE6sPoEZZdXJBsTTD2rk",
+		"J1S9H3QjnRtBbbuD4HjPV6RpRhwuk4zKbxsnCHuTgh9w",
+		"A4w7fG4kKj8jqrXrjUA4AbBCAf3w66mJr3M4vUhpx6Ek",
+		"7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU",
+		"2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc",
+	}
+	// The provider serves at most 2 items per page, whatever the requested limit.
+	const capped = 2
+	var requested []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, stdjson.NewDecoder(req.Body).Decode(&body))
+		requested = append(requested, body.Params)
+
+		page := int(body.Params["page"].(float64))
+		start := (page - 1) * capped
+		if start > len(ids) {
+			start = len(ids)
+		}
+		end := start + capped
+		if end > len(ids) {
+			end = len(ids)
+		}
+		items := make([]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			items = append(items, `{"interface":"V1_NFT","id":"`+id+`"}`)
+		}
+		rw.Write([]byte(wrapIntoRPC(fmt.Sprintf(`{"total":%d,"limit":%d,"page":%d,"items":[%s]}`,
+			len(items), capped, page, strings.Join(items, ",")))))
+	}))
+	defer server.Close()
+	client := New(server.URL)
+
+	owner := solana.MustPublicKeyFromBase58("A4w7fG4kKj8jqrXrjUA4AbBCAf3w66mJr3M4vUhpx6Ek")
+	out, err := client.GetAllAssetsByOwner(context.Background(), owner, PaginateByPage, nil)
+	require.NoError(t, err)
+	require.Len(t, out, len(ids))
+	for i, asset := range out {
+		assert.Equal(t, solana.MustPublicKeyFromBase58(ids[i]), asset.ID)
+	}
+	require.Len(t, requested, 3)
+	assert.Equal(t, float64(DASMaxLimit), requested[0]["limit"])
+}
@@ -0,0 +1,353 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// The Digital Asset Standard (DAS) methods are served by the RPC providers
+// that index the NFTs, compressed NFTs and tokens; they take their params
+// by name.
+
+// The maximum number of items of a page of the DAS methods.
+const DASMaxLimit = 1000
+
+type AssetInterface string
+
+const (
+	AssetInterfaceV1NFT             AssetInterface = "V1_NFT"
+	AssetInterfaceV1Print           AssetInterface = "V1_PRINT"
+	AssetInterfaceLegacyNFT         AssetInterface = "LEGACY_NFT"
+	AssetInterfaceV2NFT             AssetInterface = "V2_NFT"
+	AssetInterfaceFungibleAsset     AssetInterface = "FungibleAsset"
+	AssetInterfaceFungibleToken     AssetInterface = "FungibleToken"
+	AssetInterfaceCustom            AssetInterface = "Custom"
+	AssetInterfaceIdentity          AssetInterface = "Identity"
+	AssetInterfaceExecutable        AssetInterface = "Executable"
+	AssetInterfaceProgrammableNFT   AssetInterface = "ProgrammableNFT"
+	AssetInterfaceMplCoreAsset      AssetInterface = "MplCoreAsset"
+	AssetInterfaceMplCoreCollection AssetInterface = "MplCoreCollection"
+)
+
+type Asset struct {
+	Interface   AssetInterface    `json:"interface"`
+	ID          solana.PublicKey  `json:"id"`
+	Content     *AssetContent     `json:"content,omitempty"`
+	Authorities []*AssetAuthority `json:"authorities,omitempty"`
+	Compression *AssetCompression `json:"compression,omitempty"`
+	Grouping    []*AssetGroup     `json:"grouping,omitempty"`
+	Royalty     *AssetRoyalty     `json:"royalty,omitempty"`
+	Creators    []*AssetCreator   `json:"creators,omitempty"`
+	Ownership   *AssetOwnership   `json:"ownership,omitempty"`
+	Supply      *AssetSupply      `json:"supply,omitempty"`
+	Mutable     bool              `json:"mutable"`
+	Burnt       bool              `json:"burnt"`
+}
+
+// Collection returns the collection of the asset, if it has one.
+func (asset *Asset) Collection() (solana.PublicKey, bool) {
+	for _, group := range asset.Grouping {
+		if group.GroupKey == "collection" {
+			collection, err := solana.PublicKeyFromBase58(group.GroupValue)
+			return collection, err == nil
+		}
+	}
+	return solana.PublicKey{}, false
+}
+
+type AssetContent struct {
+	Schema   string            `json:"$schema"`
+	JSONURI  string            `json:"json_uri"`
+	Files    []*AssetFile      `json:"files,omitempty"`
+	Metadata *AssetMetadata    `json:"metadata,omitempty"`
+	Links    map[string]string `json:"links,omitempty"`
+}
+
+type AssetFile struct {
+	URI    string `json:"uri"`
+	CDNURI string `json:"cdn_uri,omitempty"`
+	Mime   string `json:"mime,omitempty"`
+}
+
+type AssetMetadata struct {
+	Name          string            `json:"name"`
+	Symbol        string            `json:"symbol"`
+	Description   string            `json:"description,omitempty"`
+	TokenStandard string            `json:"token_standard,omitempty"`
+	Attributes    []*AssetAttribute `json:"attributes,omitempty"`
+}
+
+type AssetAttribute struct {
+	TraitType string `json:"trait_type"`
+	// A string or a number.
+	Value interface{} `json:"value"`
+}
+
+type AssetAuthority struct {
+	Address solana.PublicKey `json:"address"`
+	Scopes  []string         `json:"scopes"`
+}
+
+type AssetCompression struct {
+	Eligible   bool `json:"eligible"`
+	Compressed bool `json:"compressed"`
+	// The hashes and tree are empty for the assets that are not compressed.
+	DataHash    string `json:"data_hash"`
+	CreatorHash string `json:"creator_hash"`
+	AssetHash   string `json:"asset_hash"`
+	Tree        string `json:"tree"`
+	Seq         uint64 `json:"seq"`
+	LeafID      uint64 `json:"leaf_id"`
+}
+
+type AssetGroup struct {
+	GroupKey   string `json:"group_key"`
+	GroupValue string `json:"group_value"`
+}
+
+type AssetRoyalty struct {
+	RoyaltyModel        string            `json:"royalty_model"`
+	Target              *solana.PublicKey `json:"target"`
+	Percent             float64           `json:"percent"`
+	BasisPoints         uint64            `json:"basis_points"`
+	PrimarySaleHappened bool              `json:"primary_sale_happened"`
+	Locked              bool              `json:"locked"`
+}
+
+type AssetCreator struct {
+	Address  solana.PublicKey `json:"address"`
+	Share    uint64           `json:"share"`
+	Verified bool             `json:"verified"`
+}
+
+type AssetOwnership struct {
+	Frozen         bool              `json:"frozen"`
+	Delegated      bool              `json:"delegated"`
+	Delegate       *solana.PublicKey `json:"delegate"`
+	OwnershipModel string            `json:"ownership_model"`
+	Owner          string            `json:"owner"`
+}
+
+type AssetSupply struct {
+	PrintMaxSupply     *uint64 `json:"print_max_supply"`
+	PrintCurrentSupply uint64  `json:"print_current_supply"`
+	EditionNonce       *uint64 `json:"edition_nonce"`
+}
+
+// AssetProof is the merkle proof of a compressed asset.
+type AssetProof struct {
+	Root solana.Hash `json:"root"`
+	// The nodes of the proof, from the leaf to the root; they are passed
+	// as the remaining accounts of the instructions on the asset.
+	Proof     []solana.PublicKey `json:"proof"`
+	NodeIndex uint64             `json:"node_index"`
+	Leaf      solana.Hash        `json:"leaf"`
+	TreeID    solana.PublicKey   `json:"tree_id"`
+}
+
+// AssetSignature is a signature of a transaction on an asset, with the
+// type of the instruction on the asset.
+type AssetSignature struct {
+	Signature solana.Signature
+	Type      string
+}
+
+func (sig *AssetSignature) UnmarshalJSON(data []byte) error {
+	var tuple []string
+	if err := json.Unmarshal(data, &tuple); err != nil {
+		return err
+	}
+	if len(tuple) != 2 {
+		return fmt.Errorf("invalid asset signature: %s", data)
+	}
+	signature, err := solana.SignatureFromBase58(tuple[0])
+	if err != nil {
+		return err
+	}
+	sig.Signature, sig.Type = signature, tuple[1]
+	return nil
+}
+
+func (sig AssetSignature) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string{sig.Signature.String(), sig.Type})
+}
+
+// DASList is a page of the results of a DAS method.
+type DASList[T any] struct {
+	Total uint64 `json:"total"`
+	Limit uint64 `json:"limit"`
+	// Set for the pages selected by page number.
+	Page uint64 `json:"page,omitempty"`
+	// Set for the pages selected by cursor.
+	Cursor string `json:"cursor,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	Items  []T    `json:"items"`
+}
+
+type AssetList = DASList[*Asset]
+
+type AssetSignatureList = DASList[*AssetSignature]
+
+// DASPagination selects a page of the results of a DAS method, by page
+// number (from 1), by cursor, or by the before/after bounds.
+type DASPagination struct {
+	Limit  uint64 `json:"limit,omitempty"`
+	Page   uint64 `json:"page,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+type DASSortBy string
+
+const (
+	DASSortByCreated      DASSortBy = "created"
+	DASSortByUpdated      DASSortBy = "updated"
+	DASSortByRecentAction DASSortBy = "recent_action"
+	DASSortByID           DASSortBy = "id"
+	DASSortByNone         DASSortBy = "none"
+)
+
+type DASSortDirection string
+
+const (
+	DASSortAsc  DASSortDirection = "asc"
+	DASSortDesc DASSortDirection = "desc"
+)
+
+type DASSorting struct {
+	SortBy        DASSortBy        `json:"sortBy"`
+	SortDirection DASSortDirection `json:"sortDirection,omitempty"`
+}
+
+// DASDisplayOptions are the extra data of the assets to return; the
+// providers support different subsets of them.
+type DASDisplayOptions struct {
+	ShowUnverifiedCollections bool `json:"showUnverifiedCollections,omitempty"`
+	ShowCollectionMetadata    bool `json:"showCollectionMetadata,omitempty"`
+	ShowGrandTotal            bool `json:"showGrandTotal,omitempty"`
+	ShowFungible              bool `json:"showFungible,omitempty"`
+	ShowNativeBalance         bool `json:"showNativeBalance,omitempty"`
+	ShowZeroBalance           bool `json:"showZeroBalance,omitempty"`
+}
+
+// DASListOpts are the options of the DAS methods returning pages of assets.
+type DASListOpts struct {
+	DASPagination
+	SortBy  *DASSorting        `json:"sortBy,omitempty"`
+	Options *DASDisplayOptions `json:"options,omitempty"`
+}
+
+func (cl *Client) callDAS(ctx context.Context, out interface{}, method string, params interface{}) error {
+	return cl.RPCCallForInto(ctx, out, method, []interface{}{jsonrpc.NamedParams{Params: params}})
+}
+
+type PaginationMode int
+
+const (
+	// Select the pages by page number.
+	PaginateByPage PaginationMode = iota
+	// Select the pages by the cursor of the previous page.
+	PaginateByCursor
+)
+
+// Paginate fetches the successive pages of a DAS method, starting from the
+// provided pagination, and calling yield with the items of each page, until
+// the last page. A zero limit selects DASMaxLimit; in PaginateByPage mode, a
+// zero page selects the first page.
+// A page that is nil or shorter than the limit is the last one; the limit is
+// the one of the page if the provider sets it, since providers may serve
+// fewer items per page than requested.
+func Paginate[T any](
+	ctx context.Context,
+	mode PaginationMode,
+	start DASPagination,
+	fetch func(ctx context.Context, pagination DASPagination) (*DASList[T], error),
+	yield func(items []T) error,
+) error {
+	pagination := start
+	if pagination.Limit == 0 || pagination.Limit > DASMaxLimit {
+		pagination.Limit = DASMaxLimit
+	}
+	if mode == PaginateByPage && pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		list, err := fetch(ctx, pagination)
+		if err != nil {
+			return err
+		}
+		if list == nil {
+			return nil
+		}
+		if len(list.Items) > 0 {
+			if err := yield(list.Items); err != nil {
+				return err
+			}
+		}
+		limit := pagination.Limit
+		if list.Limit > 0 {
+			limit = list.Limit
+		}
+		if uint64(len(list.Items)) < limit {
+			return nil
+		}
+		switch mode {
+		case PaginateByPage:
+			pagination.Page++
+		case PaginateByCursor:
+			if list.Cursor == "" || list.Cursor == pagination.Cursor {
+				return nil
+			}
+			pagination.Cursor = list.Cursor
+		default:
+			return fmt.Errorf("unknown pagination mode: %v", mode)
+		}
+	}
+}
+
+// allAssets fetches all the pages of a DAS method returning assets,
+// starting from the pagination of opts.
+func allAssets(
+	ctx context.Context,
+	mode PaginationMode,
+	opts *DASListOpts,
+	fetch func(ctx context.Context, opts *DASListOpts) (*AssetList, error),
+) (out []*Asset, err error) {
+	pageOpts := DASListOpts{}
+	if opts != nil {
+		pageOpts = *opts
+	}
+	err = Paginate(ctx, mode, pageOpts.DASPagination,
+		func(ctx context.Context, pagination DASPagination) (*AssetList, error) {
+			pageOpts.DASPagination = pagination
+			return fetch(ctx, &pageOpts)
+		},
+		func(items []*Asset) error {
+			out = append(out, items...)
+			return nil
+		},
+	)
+	return out, err
+}
@@ -0,0 +1,217 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/mr-tron/base58"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// NewMemcmpFilter returns a filter that matches the accounts
+// whose data contains the provided bytes at the provided offset.
+// The bytes are base58-encoded on the wire; the RPC node rejects
+// base58 strings longer than 128 bytes, so use NewMemcmpFilterBase64 for long byte sequences.
+func NewMemcmpFilter(offset uint64, data []byte) RPCFilter {
+	return RPCFilter{
+		Memcmp: &RPCFilterMemcmp{
+			Offset: offset,
+			Bytes:  solana.Base58(data),
+		},
+	}
+}
+
+// NewMemcmpFilterBase58 is like NewMemcmpFilter, with the bytes provided as a base58 string.
+func NewMemcmpFilterBase58(offset uint64, data string) (RPCFilter, error) {
+	decoded, err := base58.Decode(data)
+	if err != nil {
+		return RPCFilter{}, fmt.Errorf("invalid base58 bytes: %w", err)
+	}
+	return NewMemcmpFilter(offset, decoded), nil
+}
+
+// NewMemcmpFilterBase64 is like NewMemcmpFilter, but the bytes are base64-encoded on the wire.
+func NewMemcmpFilterBase64(offset uint64, data []byte) RPCFilter {
+	filter := NewMemcmpFilter(offset, data)
+	filter.Memcmp.Encoding = solana.EncodingBase64
+	return filter
+}
+
+// NewMemcmpFilterPublicKey returns a filter that matches the accounts
+// whose data contains the provided public key at the provided offset.
+func NewMemcmpFilterPublicKey(offset uint64, pubkey solana.PublicKey) RPCFilter {
+	return NewMemcmpFilter(offset, pubkey[:])
+}
+
+// NewMemcmpFilterUint64 returns a filter that matches the accounts
+// whose data contains the provided little-endian u64 at the provided offset.
+func NewMemcmpFilterUint64(offset uint64, value uint64) RPCFilter {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, value)
+	return NewMemcmpFilter(offset, buf)
+}
+
+// NewDataSizeFilter returns a filter that matches the accounts
+// whose data is exactly size bytes long.
+func NewDataSizeFilter(size uint64) RPCFilter {
+	return RPCFilter{DataSize: size}
+}
+
+// NewTokenAccountStateFilter returns a filter that matches the valid,
+// initialized token accounts (of both the Token and the Token-2022 programs).
+func NewTokenAccountStateFilter() RPCFilter {
+	return RPCFilter{TokenAccountState: true}
+}
+
+const (
+	tokenAccountLen        = 165
+	tokenMultisigLen       = 355
+	tokenAccountStateIndex = 108
+	// Token-2022 accounts have the account type right after the base account.
+	tokenAccountTypeIndex = tokenAccountLen
+	tokenAccountTypeAcc   = 2
+)
+
+// Match reports whether the account data satisfies the filter,
+// with the same semantics as the RPC node.
+func (f RPCFilter) Match(data []byte) bool {
+	if f.DataSize != 0 && uint64(len(data)) != f.DataSize {
+		return false
+	}
+	if f.Memcmp != nil {
+		offset := f.Memcmp.Offset
+		want := []byte(f.Memcmp.Bytes)
+		if offset > uint64(len(data)) || uint64(len(want)) > uint64(len(data))-offset {
+			return false
+		}
+		if !bytes.Equal(data[offset:offset+uint64(len(want))], want) {
+			return false
+		}
+	}
+	if f.TokenAccountState && !isInitializedTokenAccount(data) {
+		return false
+	}
+	return true
+}
+
+func isInitializedTokenAccount(data []byte) bool {
+	switch {
+	case len(data) == tokenAccountLen:
+	case len(data) > tokenAccountLen && len(data) != tokenMultisigLen:
+		if data[tokenAccountTypeIndex] != tokenAccountTypeAcc {
+			return false
+		}
+	default:
+		return false
+	}
+	// The state is Uninitialized (0), Initialized (1) or Frozen (2).
+	return data[tokenAccountStateIndex] != 0
+}
+
+// RPCFilters is a set of filters; an account must match all of them.
+type RPCFilters []RPCFilter
+
+// Match reports whether the account data satisfies all the filters.
+func (filters RPCFilters) Match(data []byte) bool {
+	for _, filter := range filters {
+		if !filter.Match(data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f RPCFilter) MarshalJSON() ([]byte, error) {
+	if f.TokenAccountState {
+		return []byte(`"tokenAccountState"`), nil
+	}
+	type plain RPCFilter
+	return json.Marshal(plain(f))
+}
+
+func (f *RPCFilter) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		if name != "tokenAccountState" {
+			return fmt.Errorf("unknown filter %q", name)
+		}
+		*f = RPCFilter{TokenAccountState: true}
+		return nil
+	}
+	type plain RPCFilter
+	var out plain
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	*f = RPCFilter(out)
+	return nil
+}
+
+func (m RPCFilterMemcmp) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"offset": m.Offset,
+	}
+	switch m.Encoding {
+	case "", solana.EncodingBase58:
+		out["bytes"] = base58.Encode(m.Bytes)
+	case solana.EncodingBase64:
+		out["bytes"] = base64.StdEncoding.EncodeToString(m.Bytes)
+		out["encoding"] = m.Encoding
+	default:
+		return nil, fmt.Errorf("unsupported memcmp encoding %q", m.Encoding)
+	}
+	return json.Marshal(out)
+}
+
+func (m *RPCFilterMemcmp) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Offset   uint64              `json:"offset"`
+		Bytes    string              `json:"bytes"`
+		Encoding solana.EncodingType `json:"encoding"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	decoded := []byte{}
+	var err error
+	switch raw.Encoding {
+	case "", solana.EncodingBase58:
+		if raw.Bytes == "" {
+			break
+		}
+		decoded, err = base58.Decode(raw.Bytes)
+	case solana.EncodingBase64:
+		decoded, err = base64.StdEncoding.DecodeString(raw.Bytes)
+	default:
+		return fmt.Errorf("unsupported memcmp encoding %q", raw.Encoding)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid memcmp bytes: %w", err)
+	}
+	*m = RPCFilterMemcmp{
+		Offset:   raw.Offset,
+		Bytes:    decoded,
+		Encoding: raw.Encoding,
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+)
+
+// FieldLayout is the position of a field in the encoding of a struct.
+type FieldLayout struct {
+	// Offset of the field from the start of the struct.
+	Offset uint64
+	// Size of the presence flag that precedes the value of optional fields
+	// (1 byte for borsh options, 4 bytes for bin options and COptions), 0 for the other fields.
+	OptionFlagSize uint64
+}
+
+// FieldLayouter is implemented by the types whose encoding can't be derived
+// from their declaration (i.e. the ones with a custom MarshalWithEncoder/UnmarshalWithDecoder),
+// to declare the layout of their fields to FieldOffset and FilterOnField.
+type FieldLayouter interface {
+	// FieldLayout returns the layout of the named field in the provided encoding,
+	// or false if the field doesn't have a fixed offset.
+	FieldLayout(encoding bin.Encoding, field string) (FieldLayout, bool)
+}
+
+// FieldOffset returns the byte offset of the named field in the
+// encoding (bin or borsh) of the struct T, as declared by T if it implements FieldLayouter,
+// or else as derived from the declaration of T (fields tagged with `bin:"-"`
+// and unexported fields are skipped).
+// Nested fields are addressed with dots, e.g. "Header.Authority".
+//
+// An error is returned if the offset isn't fixed, i.e. if any of the
+// preceding fields has a variable length (optional values, strings, slices, etc.),
+// or if T has a custom encoding and doesn't declare its layout.
+func FieldOffset[T any](encoding bin.Encoding, field string) (uint64, error) {
+	layout, err := fieldLayoutOf(encoding, reflect.TypeOf((*T)(nil)).Elem(), field)
+	if err != nil {
+		return 0, err
+	}
+	return layout.Offset, nil
+}
+
+// FilterOnField returns a memcmp filter that matches the accounts
+// whose data, decoded as T with the provided encoding, has the provided value
+// in the named field; the offset is computed with FieldOffset.
+//
+//	filter, err := rpc.FilterOnField[token.Account](bin.EncodingBin, "Owner", owner)
+//
+// The value must have the type of the field (or of the pointed value, for pointer fields).
+// For optional fields, the filter matches the accounts where the field is set to value.
+func FilterOnField[T any](encoding bin.Encoding, field string, value interface{}) (RPCFilter, error) {
+	layout, err := fieldLayoutOf(encoding, reflect.TypeOf((*T)(nil)).Elem(), field)
+	if err != nil {
+		return RPCFilter{}, err
+	}
+	want := layout.field.Type
+	got := reflect.TypeOf(value)
+	if got != want && !(want.Kind() == reflect.Ptr && got == want.Elem()) {
+		return RPCFilter{}, fmt.Errorf("field %q is a %s, got a value of type %v", field, want, got)
+	}
+
+	buf := new(bytes.Buffer)
+	switch layout.OptionFlagSize {
+	case 0:
+	case 1:
+		// Some(value):
+		buf.WriteByte(1)
+	case 4:
+		if err := binary.Write(buf, binary.LittleEndian, uint32(1)); err != nil {
+			return RPCFilter{}, err
+		}
+	default:
+		return RPCFilter{}, fmt.Errorf("field %q has an unsupported option flag size: %d", field, layout.OptionFlagSize)
+	}
+	if err := bin.NewEncoderWithEncoding(buf, encoding).Encode(value); err != nil {
+		return RPCFilter{}, fmt.Errorf("unable to encode value of field %q: %w", field, err)
+	}
+	return NewMemcmpFilter(layout.Offset, buf.Bytes()), nil
+}
+
+type fieldLayout struct {
+	FieldLayout
+	field reflect.StructField
+}
+
+func fieldLayoutOf(encoding bin.Encoding, typ reflect.Type, path string) (*fieldLayout, error) {
+	if !encoding.IsBin() && !encoding.IsBorsh() {
+		return nil, fmt.Errorf("unsupported encoding: %v", encoding)
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", typ)
+	}
+	name, rest, nested := strings.Cut(path, ".")
+
+	layout, err := topFieldLayoutOf(encoding, typ, name)
+	if err != nil {
+		return nil, err
+	}
+	if !nested {
+		return layout, nil
+	}
+	if layout.OptionFlagSize > 0 {
+		return nil, fmt.Errorf("offset of field %q is not fixed: field %q of %v is optional", path, name, typ)
+	}
+	inner := layout.field.Type
+	if inner.Kind() == reflect.Ptr {
+		inner = inner.Elem()
+	}
+	innerLayout, err := fieldLayoutOf(encoding, inner, rest)
+	if err != nil {
+		return nil, err
+	}
+	innerLayout.Offset += layout.Offset
+	return innerLayout, nil
+}
+
+// topFieldLayoutOf returns the layout of the named field of the struct typ.
+func topFieldLayoutOf(encoding bin.Encoding, typ reflect.Type, name string) (*fieldLayout, error) {
+	if hasCustomEncoding(typ) {
+		layouter, ok := reflect.New(typ).Interface().(FieldLayouter)
+		if !ok {
+			return nil, fmt.Errorf("%v has a custom encoding and doesn't declare its layout", typ)
+		}
+		field, ok := typ.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%v has no field %q", typ, name)
+		}
+		layout, ok := layouter.FieldLayout(encoding, name)
+		if !ok {
+			return nil, fmt.Errorf("offset of field %q of %v is not fixed", name, typ)
+		}
+		return &fieldLayout{FieldLayout: layout, field: field}, nil
+	}
+
+	var offset uint64
+	// The first field of variable size, if any:
+	var variable string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := parseBinTag(field.Tag)
+		if tag.skip || !field.IsExported() {
+			if field.Name == name {
+				return nil, fmt.Errorf("field %q of %v is not encoded", name, typ)
+			}
+			continue
+		}
+		if field.Name == name {
+			if variable != "" {
+				return nil, fmt.Errorf("offset of field %q of %v is not fixed: field %q has a variable size", name, typ, variable)
+			}
+			return &fieldLayout{
+				FieldLayout: FieldLayout{Offset: offset, OptionFlagSize: tag.optionFlagSize(encoding)},
+				field:       field,
+			}, nil
+		}
+		if variable != "" {
+			continue
+		}
+		size, ok := fieldSize(encoding, field.Type, tag)
+		if !ok {
+			variable = field.Name
+			continue
+		}
+		offset += size
+	}
+	return nil, fmt.Errorf("%v has no field %q", typ, name)
+}
+
+type binTag struct {
+	skip    bool
+	option  bool
+	coption bool
+	enum    bool
+}
+
+// parseBinTag parses the tags that affect the layout, like gagliardetto/binary does.
+func parseBinTag(tag reflect.StructTag) binTag {
+	var out binTag
+	for _, s := range strings.Split(tag.Get("bin"), " ") {
+		switch s {
+		case "-", "skip":
+			out.skip = true
+		case "optional", "option":
+			out.option = true
+		case "coption":
+			out.coption = true
+		case "enum":
+			out.enum = true
+		}
+	}
+	if strings.TrimSpace(tag.Get("borsh_skip")) == "true" {
+		out.skip = true
+	}
+	if strings.TrimSpace(tag.Get("borsh_enum")) == "true" {
+		out.enum = true
+	}
+	return out
+}
+
+// optionFlagSize returns the size of the presence flag of the field.
+func (tag binTag) optionFlagSize(encoding bin.Encoding) uint64 {
+	switch {
+	case tag.coption:
+		return 4
+	case tag.option && encoding.IsBorsh():
+		return 1
+	case tag.option:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// fieldSize returns the encoded size of a field of type typ, if it doesn't depend on its value.
+func fieldSize(encoding bin.Encoding, typ reflect.Type, tag binTag) (uint64, bool) {
+	if tag.option || (tag.enum && encoding.IsBorsh()) {
+		return 0, false
+	}
+	size, ok := fixedSize(encoding, typ)
+	if !ok {
+		return 0, false
+	}
+	if tag.coption {
+		// Like the COption of the on-chain programs, the value is
+		// always written after the flag, zeroed when absent.
+		return 4 + size, true
+	}
+	return size, true
+}
+
+var (
+	uint128Type           = reflect.TypeOf(bin.Uint128{})
+	int128Type            = reflect.TypeOf(bin.Int128{})
+	float128Type          = reflect.TypeOf(bin.Float128{})
+	binaryMarshalerType   = reflect.TypeOf((*bin.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*bin.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// hasCustomEncoding tells whether the values of type typ are encoded or decoded with their own methods.
+func hasCustomEncoding(typ reflect.Type) bool {
+	ptr := reflect.PtrTo(typ)
+	return typ.Implements(binaryMarshalerType) || ptr.Implements(binaryMarshalerType) ||
+		typ.Implements(binaryUnmarshalerType) || ptr.Implements(binaryUnmarshalerType)
+}
+
+// fixedSize returns the encoded size of the values of type typ,
+// if it doesn't depend on the value.
+func fixedSize(encoding bin.Encoding, typ reflect.Type) (uint64, bool) {
+	switch typ {
+	case uint128Type, int128Type, float128Type:
+		return 16, true
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Uint8, reflect.Int8:
+		return 1, true
+	case reflect.Uint16, reflect.Int16:
+		return 2, true
+	case reflect.Uint32, reflect.Int32, reflect.Float32:
+		return 4, true
+	case reflect.Uint64, reflect.Int64, reflect.Float64:
+		return 8, true
+	case reflect.Array:
+		size, ok := fixedSize(encoding, typ.Elem())
+		return size * uint64(typ.Len()), ok
+	case reflect.Ptr:
+		// Non-optional pointers are encoded as the pointed value.
+		return fixedSize(encoding, typ.Elem())
+	case reflect.Struct:
+		if hasCustomEncoding(typ) {
+			// The size isn't declared.
+			return 0, false
+		}
+		var total uint64
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := parseBinTag(field.Tag)
+			if tag.skip || !field.IsExported() {
+				continue
+			}
+			size, ok := fieldSize(encoding, field.Type, tag)
+			if !ok {
+				return 0, false
+			}
+			total += size
+		}
+		return total, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc_test
+
+import (
+	"bytes"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+type layoutHeader struct {
+	Version   uint8
+	Authority solana.PublicKey
+}
+
+type layoutAccount struct {
+	Discriminator [8]byte
+	Header        layoutHeader
+	Cached        []byte `bin:"-"`
+	Amount        uint64
+	Flag          bool
+	Owner         *solana.PublicKey
+	Delegate      *solana.PublicKey `bin:"optional"`
+	Name          string
+	unexported    uint64
+	Tail          uint32
+}
+
+type layoutCOptionAccount struct {
+	Authority *solana.PublicKey `bin:"coption"`
+	Amount    uint64
+	Delegate  *solana.PublicKey `bin:"optional"`
+	Tail      uint32
+}
+
+type layoutCustomAccount struct {
+	Amount uint64
+}
+
+func (acc layoutCustomAccount) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint64(acc.Amount, bin.LE)
+}
+
+func TestFieldOffset(t *testing.T) {
+	for field, offset := range map[string]uint64{
+		"Discriminator":    0,
+		"Header":           8,
+		"Header.Version":   8,
+		"Header.Authority": 9,
+		"Amount":           41,
+		"Flag":             49,
+		"Owner":            50,
+		"Delegate":         82,
+	} {
+		for _, encoding := range []bin.Encoding{bin.EncodingBin, bin.EncodingBorsh} {
+			got, err := rpc.FieldOffset[layoutAccount](encoding, field)
+			require.NoError(t, err, field)
+			require.Equal(t, offset, got, field)
+		}
+	}
+
+	for _, field := range []string{"Name", "Tail", "Cached", "unexported", "Missing", "Amount.Foo"} {
+		_, err := rpc.FieldOffset[layoutAccount](bin.EncodingBin, field)
+		require.Error(t, err, field)
+	}
+
+	_, err := rpc.FieldOffset[layoutAccount](bin.EncodingCompactU16, "Amount")
+	require.Error(t, err)
+
+	// The size of a COption doesn't depend on its value.
+	for _, encoding := range []bin.Encoding{bin.EncodingBin, bin.EncodingBorsh} {
+		got, err := rpc.FieldOffset[layoutCOptionAccount](encoding, "Amount")
+		require.NoError(t, err)
+		require.Equal(t, uint64(36), got)
+		got, err = rpc.FieldOffset[layoutCOptionAccount](encoding, "Delegate")
+		require.NoError(t, err)
+		require.Equal(t, uint64(44), got)
+		_, err = rpc.FieldOffset[layoutCOptionAccount](encoding, "Tail")
+		require.Error(t, err)
+	}
+
+	// Custom encodings must be declared.
+	_, err = rpc.FieldOffset[layoutCustomAccount](bin.EncodingBin, "Amount")
+	require.Error(t, err)
+}
+
+func TestFilterOnField(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+	filter, err := rpc.FilterOnField[layoutAccount](bin.EncodingBin, "Header.Authority", authority)
+	require.NoError(t, err)
+	require.Equal(t, rpc.NewMemcmpFilterPublicKey(9, authority), filter)
+
+	filter, err = rpc.FilterOnField[layoutAccount](bin.EncodingBorsh, "Amount", uint64(7))
+	require.NoError(t, err)
+	require.Equal(t, rpc.NewMemcmpFilterUint64(41, 7), filter)
+
+	_, err = rpc.FilterOnField[layoutAccount](bin.EncodingBin, "Amount", uint32(7))
+	require.Error(t, err)
+}
+
+func TestFilterOnField_Optional(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+	account := layoutCOptionAccount{Amount: 7, Delegate: authority.ToPointer(), Tail: 1}
+
+	for _, test := range []struct {
+		encoding bin.Encoding
+		flag     []byte
+	}{
+		{encoding: bin.EncodingBin, flag: []byte{1, 0, 0, 0}},
+		{encoding: bin.EncodingBorsh, flag: []byte{1}},
+	} {
+		filter, err := rpc.FilterOnField[layoutAccount](test.encoding, "Delegate", authority)
+		require.NoError(t, err)
+		require.Equal(t, rpc.NewMemcmpFilter(82, append(test.flag, authority[:]...)), filter)
+
+		filter, err = rpc.FilterOnField[layoutCOptionAccount](test.encoding, "Delegate", authority)
+		require.NoError(t, err)
+		require.Equal(t, rpc.NewMemcmpFilter(44, append(test.flag, authority[:]...)), filter)
+	}
+
+	// With borsh, the COption is written like on chain:
+	account.Authority = authority.ToPointer()
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewBorshEncoder(buf).Encode(account))
+	filter, err := rpc.FilterOnField[layoutCOptionAccount](bin.EncodingBorsh, "Authority", authority)
+	require.NoError(t, err)
+	require.True(t, filter.Match(buf.Bytes()))
+	filter, err = rpc.FilterOnField[layoutCOptionAccount](bin.EncodingBorsh, "Delegate", authority)
+	require.NoError(t, err)
+	require.True(t, filter.Match(buf.Bytes()))
+}
+
+func TestFilterOnField_TokenAccount(t *testing.T) {
+	owner := solana.MustPublicKeyFromBase58("7HZaCWazgTuuFuajxaaxGYbGnyVKwxvsJKue1W4Nvyro")
+	account := token.Account{
+		Mint:           solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112"),
+		Owner:          owner,
+		Amount:         28320298,
+		Delegate:       owner.ToPointer(),
+		State:          token.Initialized,
+		CloseAuthority: owner.ToPointer(),
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, bin.NewBinEncoder(buf).Encode(account))
+
+	filter, err := rpc.FilterOnField[token.Account](bin.EncodingBin, "Owner", owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(32), filter.Memcmp.Offset)
+	require.True(t, filter.Match(buf.Bytes()))
+
+	filter, err = rpc.FilterOnField[token.Account](bin.EncodingBin, "Amount", uint64(28320298))
+	require.NoError(t, err)
+	require.True(t, filter.Match(buf.Bytes()))
+
+	filter, err = rpc.FilterOnField[token.Account](bin.EncodingBin, "Delegate", owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(72), filter.Memcmp.Offset)
+	require.True(t, filter.Match(buf.Bytes()))
+
+	// Delegate is a COption, which always takes 36 bytes.
+	offset, err := rpc.FieldOffset[token.Account](bin.EncodingBin, "State")
+	require.NoError(t, err)
+	require.Equal(t, uint64(108), offset)
+
+	filter, err = rpc.FilterOnField[token.Account](bin.EncodingBin, "State", token.Initialized)
+	require.NoError(t, err)
+	require.True(t, filter.Match(buf.Bytes()))
+
+	filter, err = rpc.FilterOnField[token.Account](bin.EncodingBin, "CloseAuthority", owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(129), filter.Memcmp.Offset)
+	require.True(t, filter.Match(buf.Bytes()))
+
+	_, err = rpc.FieldOffset[token.Account](bin.EncodingBin, "Missing")
+	require.Error(t, err)
+}
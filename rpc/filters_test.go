@@ -0,0 +1,78 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestRPCFilter_JSON(t *testing.T) {
+	pubkey := solana.MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	filters := RPCFilters{
+		NewDataSizeFilter(165),
+		NewMemcmpFilterPublicKey(32, pubkey),
+		NewMemcmpFilterBase64(0, []byte("hello")),
+		NewMemcmpFilterUint64(64, 1),
+		NewTokenAccountStateFilter(),
+	}
+	out, err := json.Marshal(filters)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"dataSize":165},
+		{"memcmp":{"offset":32,"bytes":"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"}},
+		{"memcmp":{"offset":0,"bytes":"aGVsbG8=","encoding":"base64"}},
+		{"memcmp":{"offset":64,"bytes":"Ahg1opVcGX"}},
+		"tokenAccountState"
+	]`, string(out))
+
+	var decoded RPCFilters
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	require.Equal(t, filters, decoded)
+
+	fromBase58, err := NewMemcmpFilterBase58(32, pubkey.String())
+	require.NoError(t, err)
+	require.Equal(t, filters[1], fromBase58)
+	_, err = NewMemcmpFilterBase58(0, "0OIl")
+	require.Error(t, err)
+}
+
+func TestRPCFilter_Match(t *testing.T) {
+	data := make([]byte, 165)
+	copy(data[32:], []byte{1, 2, 3})
+
+	require.True(t, NewDataSizeFilter(165).Match(data))
+	require.False(t, NewDataSizeFilter(82).Match(data))
+	require.True(t, NewMemcmpFilter(32, []byte{1, 2, 3}).Match(data))
+	require.False(t, NewMemcmpFilter(33, []byte{1, 2, 3}).Match(data))
+	require.False(t, NewMemcmpFilter(164, []byte{0, 0}).Match(data))
+	require.False(t, NewMemcmpFilter(1000, nil).Match(data))
+
+	// Uninitialized token account.
+	require.False(t, NewTokenAccountStateFilter().Match(data))
+	data[108] = 1
+	require.True(t, NewTokenAccountStateFilter().Match(data))
+	require.True(t, RPCFilters{NewDataSizeFilter(165), NewTokenAccountStateFilter()}.Match(data))
+	require.False(t, RPCFilters{NewDataSizeFilter(165), NewMemcmpFilter(0, []byte{9})}.Match(data))
+
+	// Token-2022 accounts with extensions need the account type.
+	extended := append(append([]byte(nil), data...), 0, 0, 0)
+	require.False(t, NewTokenAccountStateFilter().Match(extended))
+	extended[165] = 2
+	require.True(t, NewTokenAccountStateFilter().Match(extended))
+}
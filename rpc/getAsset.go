@@ -0,0 +1,53 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// GetAsset returns an asset (NFT, compressed NFT, token) by its ID.
+//
+// This is a DAS method, served by the providers that index the assets.
+func (cl *Client) GetAsset(
+	ctx context.Context,
+	id solana.PublicKey,
+) (out *Asset, err error) {
+	return cl.GetAssetWithOpts(ctx, id, nil)
+}
+
+// GetAssetWithOpts returns an asset by its ID, with the display options.
+//
+// This is a DAS method, served by the providers that index the assets.
+func (cl *Client) GetAssetWithOpts(
+	ctx context.Context,
+	id solana.PublicKey,
+	opts *DASDisplayOptions,
+) (out *Asset, err error) {
+	params := struct {
+		ID      solana.PublicKey   `json:"id"`
+		Options *DASDisplayOptions `json:"options,omitempty"`
+	}{id, opts}
+	err = cl.callDAS(ctx, &out, "getAsset", params)
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// GetAssetProof returns the merkle proof of a compressed asset, needed by
+// the instructions transferring or burning it.
+//
+// This is a DAS method, served by the providers that index the assets.
+func (cl *Client) GetAssetProof(
+	ctx context.Context,
+	id solana.PublicKey,
+) (out *AssetProof, err error) {
+	params := struct {
+		ID solana.PublicKey `json:"id"`
+	}{id}
+	err = cl.callDAS(ctx, &out, "getAssetProof", params)
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
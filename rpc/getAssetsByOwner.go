@@ -0,0 +1,51 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// GetAssetsByOwner returns a page of the assets owned by an address.
+//
+// This is a DAS method, served by the providers that index the assets.
+func (cl *Client) GetAssetsByOwner(
+	ctx context.Context,
+	owner solana.PublicKey,
+	opts *DASListOpts,
+) (out *AssetList, err error) {
+	params := struct {
+		OwnerAddress solana.PublicKey `json:"ownerAddress"`
+		*DASListOpts
+	}{owner, opts}
+	err = cl.callDAS(ctx, &out, "getAssetsByOwner", params)
+	return
+}
+
+// GetAllAssetsByOwner returns all the assets owned by an address, fetching
+// all the pages of opts.Limit items (or DASMaxLimit) by page or by cursor,
+// from opts.Page or opts.Cursor if set.
+func (cl *Client) GetAllAssetsByOwner(
+	ctx context.Context,
+	owner solana.PublicKey,
+	mode PaginationMode,
+	opts *DASListOpts,
+) (out []*Asset, err error) {
+	return allAssets(ctx, mode, opts, func(ctx context.Context, opts *DASListOpts) (*AssetList, error) {
+		return cl.GetAssetsByOwner(ctx, owner, opts)
+	})
+}
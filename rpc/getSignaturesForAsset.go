@@ -0,0 +1,38 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// GetSignaturesForAsset returns a page of the signatures of the
+// transactions on a compressed asset, most recent first.
+//
+// This is a DAS method, served by the providers that index the assets.
+func (cl *Client) GetSignaturesForAsset(
+	ctx context.Context,
+	id solana.PublicKey,
+	pagination *DASPagination,
+) (out *AssetSignatureList, err error) {
+	params := struct {
+		ID solana.PublicKey `json:"id"`
+		*DASPagination
+	}{id, pagination}
+	err = cl.callDAS(ctx, &out, "getSignaturesForAsset", params)
+	return
+}
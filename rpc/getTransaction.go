@@ -99,6 +99,8 @@ type GetTransactionResult struct {
 type TransactionResultEnvelope struct {
 	asDecodedBinary     solana.Data
 	asParsedTransaction *solana.Transaction
+	// The meta of the transaction, when it loaded addresses from lookup tables.
+	meta *TransactionMeta
 }
 
 func (wrap TransactionResultEnvelope) MarshalJSON() ([]byte, error) {
@@ -150,6 +152,9 @@ func (dt *TransactionResultEnvelope) GetData() solana.Data {
 
 // GetRawJSON returns a *solana.Transaction when the data
 // encoding is EncodingJSON.
+//
+// The address table lookups of a v0 transaction are resolved with
+// the LoadedAddresses of the meta of the GetTransactionResult.
 func (dt *TransactionResultEnvelope) GetTransaction() (*solana.Transaction, error) {
 	if dt.asDecodedBinary.Content != nil {
 		tx := new(solana.Transaction)
@@ -157,11 +162,38 @@ func (dt *TransactionResultEnvelope) GetTransaction() (*solana.Transaction, erro
 		if err != nil {
 			return nil, err
 		}
+		if err := resolveLookups(tx, dt.meta); err != nil {
+			return nil, err
+		}
 		return tx, nil
 	}
+	if dt.asParsedTransaction != nil {
+		if err := resolveLookups(dt.asParsedTransaction, dt.meta); err != nil {
+			return nil, err
+		}
+	}
 	return dt.asParsedTransaction, nil
 }
 
+func (obj *GetTransactionResult) UnmarshalJSON(data []byte) error {
+	type result GetTransactionResult
+	if err := json.Unmarshal(data, (*result)(obj)); err != nil {
+		return err
+	}
+	obj.setLookupsMeta()
+	return nil
+}
+
+// setLookupsMeta gives the transaction the meta to resolve its lookups with.
+func (obj *GetTransactionResult) setLookupsMeta() {
+	if obj.Transaction == nil || obj.Meta == nil {
+		return
+	}
+	if len(obj.Meta.LoadedAddresses.Writable)+len(obj.Meta.LoadedAddresses.ReadOnly) > 0 {
+		obj.Transaction.meta = obj.Meta
+	}
+}
+
 func (obj TransactionResultEnvelope) MarshalWithEncoder(encoder *bin.Encoder) (err error) {
 	return encoder.Encode(obj.asDecodedBinary)
 }
@@ -303,5 +335,6 @@ func (obj *GetTransactionResult) UnmarshalWithDecoder(decoder *bin.Decoder) (err
 			return err
 		}
 	}
+	obj.setLookupsMeta()
 	return nil
 }
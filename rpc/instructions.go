@@ -0,0 +1,213 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// InstructionNode is an instruction executed by a transaction, either a
+// top-level instruction or one invoked through a CPI, with its accounts
+// resolved against the keys of the message and the loaded addresses.
+type InstructionNode struct {
+	// Index of the top-level instruction this instruction belongs to.
+	Index int
+	// 1 for the top-level instructions, 2 for the instructions they invoke, etc.
+	StackHeight int
+
+	ProgramID solana.PublicKey
+	Accounts  solana.AccountMetaSlice
+	Data      []byte
+
+	// The instruction decoded by the decoder registered for the program
+	// (see solana.DecodeInstruction), or nil if DecodeError is set.
+	Decoded     interface{}
+	DecodeError error
+
+	Parent *InstructionNode
+	Inner  []*InstructionNode
+}
+
+// Walk calls fn for the instruction and its inner instructions in
+// execution order (depth-first), skipping the inner instructions
+// of the ones for which fn returns false.
+func (node *InstructionNode) Walk(fn func(node *InstructionNode) bool) {
+	if !fn(node) {
+		return
+	}
+	for _, inner := range node.Inner {
+		inner.Walk(fn)
+	}
+}
+
+// InstructionTree returns the instructions executed by the transaction,
+// decoded through the registry, with their inner instructions nested
+// under the instruction that invoked them.
+func (res *GetTransactionResult) InstructionTree() ([]*InstructionNode, error) {
+	if res.Transaction == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+	tx, err := res.Transaction.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return NewInstructionTree(tx, res.Meta)
+}
+
+// InstructionTree returns the instructions executed by the transaction,
+// decoded through the registry, with their inner instructions nested
+// under the instruction that invoked them.
+func (twm TransactionWithMeta) InstructionTree() ([]*InstructionNode, error) {
+	tx, err := twm.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return NewInstructionTree(tx, twm.Meta)
+}
+
+// NewInstructionTree returns the top-level instructions of the transaction,
+// with the inner instructions of the meta (if any) nested according to their
+// StackHeight. The inner instructions of the nodes that predate StackHeight
+// are all attached to their top-level instruction.
+//
+// The account indices are resolved against the static keys of the message
+// followed by the writable and the read-only loaded addresses, and the
+// account metas get the signer and writable flags of their position.
+func NewInstructionTree(tx *solana.Transaction, meta *TransactionMeta) ([]*InstructionNode, error) {
+	keys, err := newMessageKeys(&tx.Message, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*InstructionNode, len(tx.Message.Instructions))
+	for i, inst := range tx.Message.Instructions {
+		node, err := keys.node(inst.ProgramIDIndex, inst.Accounts, inst.Data)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %d: %w", i, err)
+		}
+		node.Index = i
+		node.StackHeight = 1
+		out[i] = node
+	}
+	if meta == nil {
+		return out, nil
+	}
+
+	for _, inner := range meta.InnerInstructions {
+		if int(inner.Index) >= len(out) {
+			return nil, fmt.Errorf("inner instructions of unknown instruction %d", inner.Index)
+		}
+		// The stack of the invocations, from the top-level instruction.
+		stack := []*InstructionNode{out[inner.Index]}
+		for j, inst := range inner.Instructions {
+			node, err := keys.node(inst.ProgramIDIndex, inst.Accounts, inst.Data)
+			if err != nil {
+				return nil, fmt.Errorf("inner instruction %d of instruction %d: %w", j, inner.Index, err)
+			}
+			node.Index = int(inner.Index)
+			node.StackHeight = int(inst.StackHeight)
+			if node.StackHeight == 0 {
+				node.StackHeight = 2
+			}
+			if node.StackHeight > len(stack)+1 {
+				return nil, fmt.Errorf("inner instruction %d of instruction %d: stack height %d after height %d", j, inner.Index, node.StackHeight, len(stack))
+			}
+			stack = stack[:node.StackHeight-1]
+			node.Parent = stack[len(stack)-1]
+			node.Parent.Inner = append(node.Parent.Inner, node)
+			stack = append(stack, node)
+		}
+	}
+	return out, nil
+}
+
+// messageKeys are the account keys of a message: the static ones,
+// followed by the writable and the read-only loaded addresses.
+type messageKeys struct {
+	header      solana.MessageHeader
+	keys        solana.PublicKeySlice
+	numStatic   int
+	numWritable int // writable loaded addresses
+}
+
+func newMessageKeys(message *solana.Message, meta *TransactionMeta) (*messageKeys, error) {
+	if message.IsResolved() {
+		numStatic := len(message.AccountKeys) - message.NumLookups()
+		return &messageKeys{
+			header:      message.Header,
+			keys:        message.AccountKeys,
+			numStatic:   numStatic,
+			numWritable: message.NumWritableLookups(),
+		}, nil
+	}
+	keys := append(solana.PublicKeySlice{}, message.AccountKeys...)
+	var numWritable int
+	if meta != nil {
+		keys = append(keys, meta.LoadedAddresses.Writable...)
+		keys = append(keys, meta.LoadedAddresses.ReadOnly...)
+		numWritable = len(meta.LoadedAddresses.Writable)
+	}
+	if message.NumLookups() > 0 && len(keys) == len(message.AccountKeys) {
+		return nil, fmt.Errorf("the meta has none of the %d addresses loaded from lookup tables", message.NumLookups())
+	}
+	return &messageKeys{
+		header:      message.Header,
+		keys:        keys,
+		numStatic:   len(message.AccountKeys),
+		numWritable: numWritable,
+	}, nil
+}
+
+func (k *messageKeys) meta(index uint16) (*solana.AccountMeta, error) {
+	if int(index) >= len(k.keys) {
+		return nil, fmt.Errorf("account index %d out of range (%d keys)", index, len(k.keys))
+	}
+	idx := int(index)
+	h := k.header
+	meta := &solana.AccountMeta{
+		PublicKey: k.keys[idx],
+		IsSigner:  idx < int(h.NumRequiredSignatures),
+	}
+	switch {
+	case idx >= k.numStatic:
+		meta.IsWritable = idx-k.numStatic < k.numWritable
+	case meta.IsSigner:
+		meta.IsWritable = idx < int(h.NumRequiredSignatures)-int(h.NumReadonlySignedAccounts)
+	default:
+		meta.IsWritable = idx < k.numStatic-int(h.NumReadonlyUnsignedAccounts)
+	}
+	return meta, nil
+}
+
+func (k *messageKeys) node(programIDIndex uint16, accounts []uint16, data []byte) (*InstructionNode, error) {
+	program, err := k.meta(programIDIndex)
+	if err != nil {
+		return nil, err
+	}
+	node := &InstructionNode{
+		ProgramID: program.PublicKey,
+		Accounts:  make(solana.AccountMetaSlice, len(accounts)),
+		Data:      data,
+	}
+	for i, index := range accounts {
+		if node.Accounts[i], err = k.meta(index); err != nil {
+			return nil, err
+		}
+	}
+	node.Decoded, node.DecodeError = solana.DecodeInstruction(node.ProgramID, node.Accounts, node.Data)
+	return node, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInstructionTree(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	account := solana.NewWallet().PublicKey()
+	program := solana.NewWallet().PublicKey()
+	loadedWritable := solana.NewWallet().PublicKey()
+	loadedReadOnly := solana.NewWallet().PublicKey()
+
+	type decoded struct {
+		accounts int
+		data     []byte
+	}
+	solana.RegisterInstructionDecoder(program, func(accounts []*solana.AccountMeta, data []byte) (interface{}, error) {
+		return &decoded{accounts: len(accounts), data: data}, nil
+	})
+
+	tx := &solana.Transaction{
+		Message: solana.Message{
+			Header: solana.MessageHeader{
+				NumRequiredSignatures:       1,
+				NumReadonlyUnsignedAccounts: 1,
+			},
+			AccountKeys: solana.PublicKeySlice{payer, account, program},
+			Instructions: []solana.CompiledInstruction{
+				{ProgramIDIndex: 2, Accounts: []uint16{0, 1, 3, 4}, Data: []byte{1}},
+				{ProgramIDIndex: 1, Data: []byte{2}},
+			},
+			AddressTableLookups: []solana.MessageAddressTableLookup{
+				{
+					AccountKey:      solana.NewWallet().PublicKey(),
+					WritableIndexes: []uint8{0},
+					ReadonlyIndexes: []uint8{1},
+				},
+			},
+		},
+	}
+	tx.Message.SetVersion(solana.MessageVersionV0)
+	meta := &TransactionMeta{
+		InnerInstructions: []InnerInstruction{
+			{
+				Index: 0,
+				Instructions: []CompiledInstruction{
+					{ProgramIDIndex: 2, Accounts: []uint16{3}, Data: []byte{3}, StackHeight: 2},
+					{ProgramIDIndex: 2, Accounts: []uint16{4}, Data: []byte{4}, StackHeight: 3},
+					{ProgramIDIndex: 2, Accounts: []uint16{0}, Data: []byte{5}, StackHeight: 2},
+				},
+			},
+		},
+		LoadedAddresses: LoadedAddresses{
+			Writable: solana.PublicKeySlice{loadedWritable},
+			ReadOnly: solana.PublicKeySlice{loadedReadOnly},
+		},
+	}
+
+	tree, err := NewInstructionTree(tx, meta)
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+
+	top := tree[0]
+	require.Equal(t, program, top.ProgramID)
+	require.Equal(t, 1, top.StackHeight)
+	require.Equal(t, solana.AccountMetaSlice{
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(account).WRITE(),
+		solana.Meta(loadedWritable).WRITE(),
+		solana.Meta(loadedReadOnly),
+	}, top.Accounts)
+	require.Equal(t, &decoded{accounts: 4, data: []byte{1}}, top.Decoded)
+	require.NoError(t, top.DecodeError)
+
+	require.Len(t, top.Inner, 2)
+	require.Equal(t, []byte{3}, top.Inner[0].Data)
+	require.Equal(t, top, top.Inner[0].Parent)
+	require.Len(t, top.Inner[0].Inner, 1)
+	require.Equal(t, []byte{4}, top.Inner[0].Inner[0].Data)
+	require.Equal(t, 3, top.Inner[0].Inner[0].StackHeight)
+	require.Equal(t, solana.AccountMetaSlice{solana.Meta(loadedReadOnly)}, top.Inner[0].Inner[0].Accounts)
+	require.Equal(t, []byte{5}, top.Inner[1].Data)
+	require.Empty(t, top.Inner[1].Inner)
+
+	var order []byte
+	top.Walk(func(node *InstructionNode) bool {
+		order = append(order, node.Data[0])
+		return true
+	})
+	require.Equal(t, []byte{1, 3, 4, 5}, order)
+
+	require.Equal(t, account, tree[1].ProgramID)
+	require.Nil(t, tree[1].Decoded)
+	require.ErrorIs(t, tree[1].DecodeError, solana.ErrInstructionDecoderNotFound)
+
+	// Without the loaded addresses, the lookups cannot be resolved.
+	_, err = NewInstructionTree(tx, &TransactionMeta{})
+	require.Error(t, err)
+
+	// Nor can a stack height skip a level.
+	meta.InnerInstructions[0].Instructions[0].StackHeight = 3
+	_, err = NewInstructionTree(tx, meta)
+	require.Error(t, err)
+}
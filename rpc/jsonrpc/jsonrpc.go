@@ -387,7 +387,7 @@ func (client *rpcClient) CallForInto(
 	}
 
 	if params != nil {
-		request.Params = params
+		request.Params = requestParams(params)
 	}
 
 	rpcResponse, err := client.doCall(ctx, request)
@@ -402,6 +402,25 @@ func (client *rpcClient) CallForInto(
 	return rpcResponse.GetObject(out)
 }
 
+// NamedParams wraps the params of a call to send them by name, as a JSON
+// object, instead of by position; pass it as the only param of the call.
+type NamedParams struct {
+	Params interface{}
+}
+
+func (params NamedParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(params.Params)
+}
+
+func requestParams(params []interface{}) interface{} {
+	if len(params) == 1 {
+		if named, ok := params[0].(NamedParams); ok {
+			return named
+		}
+	}
+	return params
+}
+
 func (client *rpcClient) CallWithCallback(
 	ctx context.Context,
 	method string,
@@ -414,7 +433,7 @@ func (client *rpcClient) CallWithCallback(
 	}
 
 	if params != nil {
-		request.Params = params
+		request.Params = requestParams(params)
 	}
 
 	return client.doCallWithCallbackOnHTTPResponse(
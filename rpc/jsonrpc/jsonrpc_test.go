@@ -933,6 +933,24 @@ func TestRpcBatchJsonResponseStruct(t *testing.T) {
 	*/
 }
 
+func TestRpcClient_CallForIntoNamedParams(t *testing.T) {
+	RegisterTestingT(t)
+	rpcClient := NewClient(httpServer.URL)
+	useFixedID = true
+
+	i := 0
+	responseBody = `{"result":3,"id":1,"jsonrpc":"2.0"}`
+	err := rpcClient.CallForInto(context.Background(), &i, "something", []interface{}{NamedParams{Params: map[string]interface{}{"id": 1}}})
+	Expect((<-requestChan).body).To(Equal(`{"method":"something","params":{"id":1},"id":1,"jsonrpc":"2.0"}`))
+	Expect(err).To(BeNil())
+	Expect(i).To(Equal(3))
+
+	// Only as the only param.
+	err = rpcClient.CallForInto(context.Background(), &i, "something", []interface{}{1, NamedParams{Params: map[string]interface{}{"id": 1}}})
+	Expect((<-requestChan).body).To(Equal(`{"method":"something","params":[1,{"id":1}],"id":1,"jsonrpc":"2.0"}`))
+	Expect(err).To(BeNil())
+}
+
 func TestRpcClient_CallFor(t *testing.T) {
 	RegisterTestingT(t)
 	rpcClient := NewClient(httpServer.URL)
@@ -0,0 +1,340 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpctest
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// JSON-RPC error codes used by the Solana validator.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+
+	codeSendTransactionPreflightFailure         = -32002
+	codeTransactionSignatureVerificationFailure = -32003
+	codeBlockNotAvailable                       = -32004
+	codeUnsupportedTransactionVersion           = -32015
+	codeMinContextSlotNotReached                = -32016
+)
+
+type handler func(srv *Server, p params) (interface{}, *jsonrpc.RPCError)
+
+// methods are the supported JSON-RPC methods;
+// any other method fails with "Method not found".
+var methods = map[string]handler{
+	// Accounts:
+	"getAccountInfo":                    getAccountInfo,
+	"getBalance":                        getBalance,
+	"getMultipleAccounts":               getMultipleAccounts,
+	"getProgramAccounts":                getProgramAccounts,
+	"getMinimumBalanceForRentExemption": getMinimumBalanceForRentExemption,
+	"getTokenAccountBalance":            getTokenAccountBalance,
+	"getTokenAccountsByOwner":           getTokenAccountsByOwner,
+	"getTokenAccountsByDelegate":        getTokenAccountsByDelegate,
+	"getTokenLargestAccounts":           getTokenLargestAccounts,
+	"getTokenSupply":                    getTokenSupply,
+	"getSupply":                         getSupply,
+	"getLargestAccounts":                getLargestAccounts,
+	"getStakeActivation":                getStakeActivation,
+	"getInflationReward":                getInflationReward,
+
+	// Transactions:
+	"sendTransaction":              sendTransaction,
+	"simulateTransaction":          simulateTransaction,
+	"requestAirdrop":               requestAirdrop,
+	"getSignatureStatuses":         getSignatureStatuses,
+	"getSignaturesForAddress":      getSignaturesForAddress,
+	"getTransaction":               getTransaction,
+	"getTransactionCount":          getTransactionCount,
+	"getFeeForMessage":             getFeeForMessage,
+	"getRecentPrioritizationFees":  getRecentPrioritizationFees,
+	"getFeeRateGovernor":           getFeeRateGovernor,
+	"getFees":                      getFees,
+	"getFeeCalculatorForBlockhash": getFeeCalculatorForBlockhash,
+
+	// Blocks and slots:
+	"getBlock":                    getBlock,
+	"getBlocks":                   getBlocks,
+	"getBlocksWithLimit":          getBlocksWithLimit,
+	"getBlockTime":                getBlockTime,
+	"getBlockHeight":              getBlockHeight,
+	"getSlot":                     getSlot,
+	"getLatestBlockhash":          getLatestBlockhash,
+	"getRecentBlockhash":          getRecentBlockhash,
+	"isBlockhashValid":            isBlockhashValid,
+	"getFirstAvailableBlock":      getFirstAvailableBlock,
+	"minimumLedgerSlot":           getFirstAvailableBlock,
+	"getMaxRetransmitSlot":        getMaxSlot,
+	"getMaxShredInsertSlot":       getMaxSlot,
+	"getEpochInfo":                getEpochInfo,
+	"getEpochSchedule":            getEpochSchedule,
+	"getBlockProduction":          getBlockProduction,
+	"getBlockCommitment":          getBlockCommitment,
+	"getLeaderSchedule":           getLeaderSchedule,
+	"getHighestSnapshotSlot":      getHighestSnapshotSlot,
+	"getSnapshotSlot":             getSnapshotSlot,
+	"getRecentPerformanceSamples": getRecentPerformanceSamples,
+
+	// Cluster:
+	"getHealth":            getHealth,
+	"getIdentity":          getIdentity,
+	"getVersion":           getVersion,
+	"getGenesisHash":       getGenesisHash,
+	"getSlotLeader":        getSlotLeader,
+	"getSlotLeaders":       getSlotLeaders,
+	"getClusterNodes":      getClusterNodes,
+	"getVoteAccounts":      getVoteAccounts,
+	"getInflationGovernor": getInflationGovernor,
+	"getInflationRate":     getInflationRate,
+
+	// Deprecated aliases:
+	"getConfirmedBlock":                 getBlock,
+	"getConfirmedBlocks":                getBlocks,
+	"getConfirmedBlocksWithLimit":       getBlocksWithLimit,
+	"getConfirmedTransaction":           getTransaction,
+	"getConfirmedSignaturesForAddress2": getSignaturesForAddress,
+}
+
+func errParse(err error) *jsonrpc.RPCError {
+	return &jsonrpc.RPCError{Code: codeParseError, Message: fmt.Sprintf("Parse error: %s", err)}
+}
+
+func errInvalidParams(format string, args ...interface{}) *jsonrpc.RPCError {
+	return &jsonrpc.RPCError{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+func errBlockNotAvailable(slot uint64) *jsonrpc.RPCError {
+	return &jsonrpc.RPCError{Code: codeBlockNotAvailable, Message: fmt.Sprintf("Block not available for slot %d", slot)}
+}
+
+func errUnsupportedTransactionVersion() *jsonrpc.RPCError {
+	return &jsonrpc.RPCError{
+		Code:    codeUnsupportedTransactionVersion,
+		Message: `Transaction version (0) is not supported by the requesting client. Please try the request again with the following configuration parameter: "maxSupportedTransactionVersion": 0`,
+	}
+}
+
+// transactionError is a transaction-level error
+// (i.e. one that is reported in the `err` field of a transaction status).
+type transactionError struct {
+	value interface{}
+}
+
+func (e *transactionError) Error() string {
+	return fmt.Sprint(e.value)
+}
+
+var (
+	errAccountNotFound         = &transactionError{"AccountNotFound"}
+	errInsufficientFundsForFee = &transactionError{"InsufficientFundsForFee"}
+	errBlockhashNotFound       = &transactionError{"BlockhashNotFound"}
+	errAlreadyProcessed        = &transactionError{"AlreadyProcessed"}
+)
+
+// params are the positional parameters of a request.
+type params []stdjson.RawMessage
+
+func (p params) has(i int) bool {
+	return i < len(p) && string(p[i]) != "null"
+}
+
+// decode decodes the i-th parameter into v, if present.
+func (p params) decode(i int, v interface{}) *jsonrpc.RPCError {
+	if !p.has(i) {
+		return nil
+	}
+	if err := json.Unmarshal(p[i], v); err != nil {
+		return errInvalidParams("Invalid params: %s", err)
+	}
+	return nil
+}
+
+// required is like decode, but fails if the parameter is missing.
+func (p params) required(i int, v interface{}) *jsonrpc.RPCError {
+	if !p.has(i) {
+		return errInvalidParams("Invalid params: missing parameter at position %d", i)
+	}
+	return p.decode(i, v)
+}
+
+func (p params) pubkey(i int) (solana.PublicKey, *jsonrpc.RPCError) {
+	var s string
+	if rpcErr := p.required(i, &s); rpcErr != nil {
+		return solana.PublicKey{}, rpcErr
+	}
+	pk, err := solana.PublicKeyFromBase58(s)
+	if err != nil {
+		return solana.PublicKey{}, errInvalidParams("Invalid param: %s", err)
+	}
+	return pk, nil
+}
+
+func (p params) config(i int) (*config, *jsonrpc.RPCError) {
+	cfg := new(config)
+	if rpcErr := p.decode(i, cfg); rpcErr != nil {
+		return nil, rpcErr
+	}
+	return cfg, nil
+}
+
+// config is the union of the configuration objects of all the methods.
+type config struct {
+	Commitment     rpc.CommitmentType  `json:"commitment"`
+	MinContextSlot *uint64             `json:"minContextSlot"`
+	Encoding       solana.EncodingType `json:"encoding"`
+	DataSlice      *struct {
+		Offset uint64 `json:"offset"`
+		Length uint64 `json:"length"`
+	} `json:"dataSlice"`
+	Filters     rpc.RPCFilters `json:"filters"`
+	WithContext bool           `json:"withContext"`
+
+	MaxSupportedTransactionVersion *uint64 `json:"maxSupportedTransactionVersion"`
+	TransactionDetails             string  `json:"transactionDetails"`
+	Rewards                        *bool   `json:"rewards"`
+
+	SkipPreflight          bool `json:"skipPreflight"`
+	SigVerify              bool `json:"sigVerify"`
+	ReplaceRecentBlockhash bool `json:"replaceRecentBlockhash"`
+	Accounts               *struct {
+		Encoding  solana.EncodingType `json:"encoding"`
+		Addresses []string            `json:"addresses"`
+	} `json:"accounts"`
+
+	Limit                    *uint64 `json:"limit"`
+	Before                   string  `json:"before"`
+	Until                    string  `json:"until"`
+	SearchTransactionHistory bool    `json:"searchTransactionHistory"`
+
+	Epoch    *uint64 `json:"epoch"`
+	Filter   string  `json:"filter"`
+	Identity string  `json:"identity"`
+	Range    *struct {
+		FirstSlot uint64  `json:"firstSlot"`
+		LastSlot  *uint64 `json:"lastSlot"`
+	} `json:"range"`
+	ExcludeNonCirculatingAccountsList bool `json:"excludeNonCirculatingAccountsList"`
+}
+
+// contextSlot returns the slot at the configured commitment,
+// checking the configured minContextSlot. Must be called with mu held.
+func (srv *Server) contextSlot(cfg *config) (uint64, *jsonrpc.RPCError) {
+	slot := srv.slotAt(cfg.Commitment)
+	if cfg.MinContextSlot != nil && *cfg.MinContextSlot > slot {
+		return 0, &jsonrpc.RPCError{
+			Code:    codeMinContextSlotNotReached,
+			Message: "Minimum context slot has not been reached",
+			Data:    M{"contextSlot": slot},
+		}
+	}
+	return slot, nil
+}
+
+// M is a shortcut for building JSON objects.
+type M map[string]interface{}
+
+func withContext(slot uint64, value interface{}) M {
+	return M{
+		"context": M{"slot": slot},
+		"value":   value,
+	}
+}
+
+// requireConfirmed rejects the "processed" commitment for the methods that don't support it.
+func requireConfirmed(cfg *config) *jsonrpc.RPCError {
+	switch cfg.Commitment {
+	case rpc.CommitmentProcessed, rpc.CommitmentRecent:
+		return errInvalidParams("Method does not support commitment below `confirmed`")
+	}
+	return nil
+}
+
+// encodeAccount renders an account in the format of the `value` of getAccountInfo.
+// A nil account is rendered as an empty, system-owned account.
+func encodeAccount(acc *Account, cfg *config) (interface{}, *jsonrpc.RPCError) {
+	if acc == nil {
+		acc = &Account{Owner: solana.SystemProgramID}
+	}
+	data := acc.Data
+	if cfg.DataSlice != nil {
+		start := cfg.DataSlice.Offset
+		if start > uint64(len(data)) {
+			start = uint64(len(data))
+		}
+		end := start + cfg.DataSlice.Length
+		if end > uint64(len(data)) {
+			end = uint64(len(data))
+		}
+		data = data[start:end]
+	}
+
+	encoding := cfg.Encoding
+	switch encoding {
+	case "", solana.EncodingJSON, solana.EncodingJSONParsed:
+		// Parsed account data is not supported; like the validator,
+		// fall back to base64 when no parser is available.
+		encoding = solana.EncodingBase64
+	case solana.EncodingBase58:
+		if len(data) > 128 {
+			return nil, &jsonrpc.RPCError{
+				Code:    codeInvalidRequest,
+				Message: "Encoded binary (base 58) data should be less than 128 bytes, please use Base64 encoding.",
+			}
+		}
+	case solana.EncodingBase64, solana.EncodingBase64Zstd:
+	default:
+		return nil, errInvalidParams("Invalid params: unknown encoding %q", encoding)
+	}
+
+	return M{
+		"lamports":   acc.Lamports,
+		"owner":      acc.Owner,
+		"data":       solana.Data{Content: data, Encoding: encoding},
+		"executable": acc.Executable,
+		"rentEpoch":  acc.RentEpoch,
+		"space":      len(acc.Data),
+	}, nil
+}
+
+// uiTokenAmount renders a raw token amount with the provided decimals.
+func uiTokenAmount(amount uint64, decimals uint8) *rpc.UiTokenAmount {
+	str := strconv.FormatUint(amount, 10)
+	if decimals > 0 {
+		if len(str) <= int(decimals) {
+			str = strings.Repeat("0", int(decimals)-len(str)+1) + str
+		}
+		point := len(str) - int(decimals)
+		str = strings.TrimRight(str[:point]+"."+str[point:], "0")
+		str = strings.TrimSuffix(str, ".")
+	}
+	ui, _ := strconv.ParseFloat(str, 64)
+	return &rpc.UiTokenAmount{
+		Amount:         strconv.FormatUint(amount, 10),
+		Decimals:       decimals,
+		UiAmount:       &ui,
+		UiAmountString: str,
+	}
+}
@@ -0,0 +1,522 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpctest
+
+import (
+	"bytes"
+	"math"
+	"sort"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/ledger"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+func getAccountInfo(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	acc, ok := srv.accounts[address]
+	if !ok {
+		return withContext(slot, nil), nil
+	}
+	value, rpcErr := encodeAccount(acc, cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return withContext(slot, value), nil
+}
+
+func getBalance(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var lamports uint64
+	if acc, ok := srv.accounts[address]; ok {
+		lamports = acc.Lamports
+	}
+	return withContext(slot, lamports), nil
+}
+
+func getMultipleAccounts(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var addresses []solana.PublicKey
+	if rpcErr := p.required(0, &addresses); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	values := make([]interface{}, len(addresses))
+	for i, address := range addresses {
+		acc, ok := srv.accounts[address]
+		if !ok {
+			continue
+		}
+		values[i], rpcErr = encodeAccount(acc, cfg)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+	}
+	return withContext(slot, values), nil
+}
+
+// sortedAddresses returns the addresses of all the accounts, sorted.
+// Must be called with mu held.
+func (srv *Server) sortedAddresses() []solana.PublicKey {
+	out := make([]solana.PublicKey, 0, len(srv.accounts))
+	for address := range srv.accounts {
+		out = append(out, address)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return bytes.Compare(out[i][:], out[j][:]) < 0
+	})
+	return out
+}
+
+func getProgramAccounts(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	programID, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	values := make([]interface{}, 0)
+	for _, address := range srv.sortedAddresses() {
+		acc := srv.accounts[address]
+		if !acc.Owner.Equals(programID) || !cfg.Filters.Match(acc.Data) {
+			continue
+		}
+		value, rpcErr := encodeAccount(acc, cfg)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		values = append(values, M{"pubkey": address, "account": value})
+	}
+	if cfg.WithContext {
+		return withContext(slot, values), nil
+	}
+	return values, nil
+}
+
+func getMinimumBalanceForRentExemption(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var size uint64
+	if rpcErr := p.required(0, &size); rpcErr != nil {
+		return nil, rpcErr
+	}
+	return MinimumBalanceForRentExemption(size), nil
+}
+
+// MinimumBalanceForRentExemption returns the lamports needed
+// for an account of the provided data size to be rent-exempt,
+// with the default rent parameters.
+func MinimumBalanceForRentExemption(size uint64) uint64 {
+	return ledger.MinimumBalanceForRentExemption(size)
+}
+
+func isTokenProgram(programID solana.PublicKey) bool {
+	return programID.Equals(solana.TokenProgramID) || programID.Equals(solana.Token2022ProgramID)
+}
+
+const tokenAccountSize = 165
+
+// Token-2022 accounts with extensions are padded to the size of a token account,
+// followed by a byte discriminating the account type.
+const (
+	tokenAccountTypeOffset  = tokenAccountSize
+	tokenAccountTypeMint    = 1
+	tokenAccountTypeAccount = 2
+)
+
+func decodeTokenAccount(acc *Account) (*token.Account, bool) {
+	if acc == nil || !isTokenProgram(acc.Owner) {
+		return nil, false
+	}
+	if len(acc.Data) != tokenAccountSize &&
+		!(len(acc.Data) > tokenAccountTypeOffset && acc.Data[tokenAccountTypeOffset] == tokenAccountTypeAccount) {
+		return nil, false
+	}
+	out := new(token.Account)
+	if err := bin.NewBinDecoder(acc.Data).Decode(out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func decodeMint(acc *Account) (*token.Mint, bool) {
+	if acc == nil || !isTokenProgram(acc.Owner) {
+		return nil, false
+	}
+	if len(acc.Data) != token.MINT_SIZE &&
+		!(len(acc.Data) > tokenAccountTypeOffset && acc.Data[tokenAccountTypeOffset] == tokenAccountTypeMint) {
+		return nil, false
+	}
+	out := new(token.Mint)
+	if err := bin.NewBinDecoder(acc.Data).Decode(out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func getTokenAccountBalance(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	tokenAccount, ok := decodeTokenAccount(srv.accounts[address])
+	if !ok {
+		return nil, errInvalidParams("Invalid param: not a Token account")
+	}
+	mint, ok := decodeMint(srv.accounts[tokenAccount.Mint])
+	if !ok {
+		return nil, errInvalidParams("Invalid param: mint could not be unpacked")
+	}
+	return withContext(slot, uiTokenAmount(tokenAccount.Amount, mint.Decimals)), nil
+}
+
+func getTokenSupply(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	mint, ok := decodeMint(srv.accounts[address])
+	if !ok {
+		return nil, errInvalidParams("Invalid param: not a Token mint")
+	}
+	return withContext(slot, uiTokenAmount(mint.Supply, mint.Decimals)), nil
+}
+
+func getTokenLargestAccounts(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	mint, ok := decodeMint(srv.accounts[address])
+	if !ok {
+		return nil, errInvalidParams("Invalid param: not a Token mint")
+	}
+
+	type holder struct {
+		address solana.PublicKey
+		amount  uint64
+	}
+	holders := make([]holder, 0)
+	for _, key := range srv.sortedAddresses() {
+		tokenAccount, ok := decodeTokenAccount(srv.accounts[key])
+		if ok && tokenAccount.Mint.Equals(address) {
+			holders = append(holders, holder{key, tokenAccount.Amount})
+		}
+	}
+	sort.SliceStable(holders, func(i, j int) bool {
+		return holders[i].amount > holders[j].amount
+	})
+	if len(holders) > 20 {
+		holders = holders[:20]
+	}
+	values := make([]interface{}, len(holders))
+	for i, h := range holders {
+		amount := uiTokenAmount(h.amount, mint.Decimals)
+		values[i] = M{
+			"address":        h.address,
+			"amount":         amount.Amount,
+			"decimals":       amount.Decimals,
+			"uiAmount":       amount.UiAmount,
+			"uiAmountString": amount.UiAmountString,
+		}
+	}
+	return withContext(slot, values), nil
+}
+
+func getTokenAccountsByOwner(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return getTokenAccountsBy(srv, p, func(acc *token.Account, key solana.PublicKey) bool {
+		return acc.Owner.Equals(key)
+	})
+}
+
+func getTokenAccountsByDelegate(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return getTokenAccountsBy(srv, p, func(acc *token.Account, key solana.PublicKey) bool {
+		return acc.Delegate != nil && acc.Delegate.Equals(key)
+	})
+}
+
+func getTokenAccountsBy(
+	srv *Server,
+	p params,
+	match func(acc *token.Account, key solana.PublicKey) bool,
+) (interface{}, *jsonrpc.RPCError) {
+	key, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var filter struct {
+		Mint      *solana.PublicKey `json:"mint"`
+		ProgramID *solana.PublicKey `json:"programId"`
+	}
+	if rpcErr := p.required(1, &filter); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if (filter.Mint == nil) == (filter.ProgramID == nil) {
+		return nil, errInvalidParams("Invalid params: exactly one of mint or programId must be provided")
+	}
+	if filter.ProgramID != nil && !isTokenProgram(*filter.ProgramID) {
+		return nil, errInvalidParams("Invalid param: unrecognized Token program id")
+	}
+	cfg, rpcErr := p.config(2)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	values := make([]interface{}, 0)
+	for _, address := range srv.sortedAddresses() {
+		acc := srv.accounts[address]
+		tokenAccount, ok := decodeTokenAccount(acc)
+		if !ok || !match(tokenAccount, key) {
+			continue
+		}
+		if filter.Mint != nil && !tokenAccount.Mint.Equals(*filter.Mint) {
+			continue
+		}
+		if filter.ProgramID != nil && !acc.Owner.Equals(*filter.ProgramID) {
+			continue
+		}
+		value, rpcErr := encodeAccount(acc, cfg)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		values = append(values, M{"pubkey": address, "account": value})
+	}
+	return withContext(slot, values), nil
+}
+
+func getSupply(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var total uint64
+	for _, acc := range srv.accounts {
+		total += acc.Lamports
+	}
+	// All the supply is circulating.
+	return withContext(slot, &rpc.SupplyResult{
+		Total:                  total,
+		Circulating:            total,
+		NonCirculatingAccounts: []solana.PublicKey{},
+	}), nil
+}
+
+func getLargestAccounts(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	switch rpc.LargestAccountsFilterType(cfg.Filter) {
+	case "", rpc.LargestAccountsFilterCirculating, rpc.LargestAccountsFilterNonCirculating:
+	default:
+		return nil, errInvalidParams("Invalid params: unknown filter %q", cfg.Filter)
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	out := make([]rpc.LargestAccountsResult, 0)
+	if rpc.LargestAccountsFilterType(cfg.Filter) == rpc.LargestAccountsFilterNonCirculating {
+		return withContext(slot, out), nil
+	}
+	for _, address := range srv.sortedAddresses() {
+		out = append(out, rpc.LargestAccountsResult{Address: address, Lamports: srv.accounts[address].Lamports})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Lamports > out[j].Lamports })
+	if len(out) > 20 {
+		out = out[:20]
+	}
+	return withContext(slot, out), nil
+}
+
+func getStakeActivation(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	epoch := slot / srv.opts.SlotsPerEpoch
+	if cfg.Epoch != nil {
+		if *cfg.Epoch > epoch {
+			return nil, errInvalidParams("Invalid param: epoch %d. Only the current epoch (%d) is supported", *cfg.Epoch, epoch)
+		}
+		epoch = *cfg.Epoch
+	}
+	acc, ok := srv.accounts[address]
+	if !ok || !acc.Owner.Equals(solana.StakeProgramID) {
+		return nil, errInvalidParams("Invalid param: not a stake account")
+	}
+	state, err := stake.DecodeStakeState(acc.Data)
+	if err != nil || state.Meta == nil {
+		return nil, errInvalidParams("Invalid param: stake account not initialized")
+	}
+	return stakeActivation(state, acc.Lamports, epoch), nil
+}
+
+// stakeActivation computes the activation of a stake at the provided epoch,
+// without warmup nor cooldown: the stake is activating (deactivating)
+// during its activation (deactivation) epoch only.
+func stakeActivation(state *stake.StakeState, lamports uint64, epoch uint64) *rpc.GetStakeActivationResult {
+	var delegated uint64
+	out := &rpc.GetStakeActivationResult{State: rpc.ActivationStateInactive}
+	if state.Stake != nil {
+		delegation := state.Stake.Delegation
+		delegated = delegation.Stake
+		switch {
+		case delegation.DeactivationEpoch < epoch, delegation.ActivationEpoch == delegation.DeactivationEpoch:
+		case delegation.DeactivationEpoch == epoch:
+			out.State = rpc.ActivationStateDeactivating
+			out.Active = delegated
+		case delegation.ActivationEpoch == epoch:
+			out.State = rpc.ActivationStateActivating
+		case delegation.ActivationEpoch < epoch || delegation.ActivationEpoch == math.MaxUint64:
+			// (Bootstrap stakes have the maximum activation epoch.)
+			out.State = rpc.ActivationStateActive
+			out.Active = delegated
+		}
+	}
+	if reserved := state.Meta.RentExemptReserve + out.Active; lamports > reserved {
+		out.Inactive = lamports - reserved
+	}
+	return out
+}
+
+func getInflationReward(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var addresses []solana.PublicKey
+	if rpcErr := p.required(0, &addresses); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	epoch := slot / srv.opts.SlotsPerEpoch
+	if cfg.Epoch != nil && *cfg.Epoch >= epoch {
+		return nil, errInvalidParams("Invalid param: epoch %d. Only past epochs are supported", *cfg.Epoch)
+	}
+	// No rewards are ever paid.
+	return make([]*rpc.GetInflationRewardResult, len(addresses)), nil
+}
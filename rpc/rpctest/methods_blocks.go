@@ -0,0 +1,502 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpctest
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// encodeBlock renders a block in the format of the result of getBlock.
+// If include is not nil, only the transactions for which it returns true are included.
+// Must be called with mu held.
+func (srv *Server) encodeBlock(blk *block, cfg *config, include func(tx *Transaction) bool) (M, *jsonrpc.RPCError) {
+	blockTime := blk.blockTime
+	blockHeight := blk.blockHeight
+	out := M{
+		"blockhash":         blk.blockhash,
+		"previousBlockhash": blk.previousBlockhash,
+		"parentSlot":        blk.parentSlot,
+		"blockTime":         &blockTime,
+		"blockHeight":       &blockHeight,
+	}
+	if cfg.Rewards == nil || *cfg.Rewards {
+		out["rewards"] = []rpc.BlockReward{}
+	}
+
+	switch rpc.TransactionDetailsType(cfg.TransactionDetails) {
+	case "", rpc.TransactionDetailsFull:
+		transactions := make([]M, 0, len(blk.signatures))
+		for _, signature := range blk.signatures {
+			tx := srv.transactions[signature]
+			if include != nil && !include(tx) {
+				continue
+			}
+			encoded, rpcErr := encodeTransactionWithMeta(tx, cfg)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			transactions = append(transactions, encoded)
+		}
+		out["transactions"] = transactions
+	case rpc.TransactionDetailsSignatures:
+		signatures := make([]solana.Signature, 0, len(blk.signatures))
+		for _, signature := range blk.signatures {
+			if include != nil && !include(srv.transactions[signature]) {
+				continue
+			}
+			signatures = append(signatures, signature)
+		}
+		out["signatures"] = signatures
+	case rpc.TransactionDetailsNone:
+	default:
+		return nil, errInvalidParams("Invalid params: unsupported transactionDetails %q", cfg.TransactionDetails)
+	}
+	return out, nil
+}
+
+func getBlock(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var slot uint64
+	if rpcErr := p.required(0, &slot); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if rpcErr := requireConfirmed(cfg); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	blk, ok := srv.blocks[slot]
+	if !ok || slot > srv.slotAt(cfg.Commitment) {
+		return nil, errBlockNotAvailable(slot)
+	}
+	return srv.encodeBlock(blk, cfg, nil)
+}
+
+// blockSlots returns the slots of the available blocks in [start, end], in ascending order.
+// Must be called with mu held.
+func (srv *Server) blockSlots(start, end uint64) []uint64 {
+	out := make([]uint64, 0)
+	for slot := range srv.blocks {
+		if slot >= start && slot <= end {
+			out = append(out, slot)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func getBlocks(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var start uint64
+	if rpcErr := p.required(0, &start); rpcErr != nil {
+		return nil, rpcErr
+	}
+	var end *uint64
+	cfgIndex := 1
+	if p.has(1) && len(p[1]) > 0 && p[1][0] != '{' {
+		if rpcErr := p.decode(1, &end); rpcErr != nil {
+			return nil, rpcErr
+		}
+		cfgIndex = 2
+	}
+	cfg, rpcErr := p.config(cfgIndex)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if rpcErr := requireConfirmed(cfg); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	last := srv.slotAt(cfg.Commitment)
+	if end != nil && *end < last {
+		last = *end
+	}
+	return srv.blockSlots(start, last), nil
+}
+
+func getBlocksWithLimit(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var start, limit uint64
+	if rpcErr := p.required(0, &start); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if rpcErr := p.required(1, &limit); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(2)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if rpcErr := requireConfirmed(cfg); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slots := srv.blockSlots(start, srv.slotAt(cfg.Commitment))
+	if uint64(len(slots)) > limit {
+		slots = slots[:limit]
+	}
+	return slots, nil
+}
+
+func getBlockTime(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var slot uint64
+	if rpcErr := p.required(0, &slot); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	blk, ok := srv.blocks[slot]
+	if !ok || slot > srv.slotAt(rpc.CommitmentConfirmed) {
+		return nil, errBlockNotAvailable(slot)
+	}
+	return blk.blockTime, nil
+}
+
+func getBlockHeight(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	if _, rpcErr := srv.contextSlot(cfg); rpcErr != nil {
+		return nil, rpcErr
+	}
+	return srv.blockHeightAt(cfg.Commitment), nil
+}
+
+func getSlot(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.contextSlot(cfg)
+}
+
+func getLatestBlockhash(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	blk := srv.blockAt(slot)
+	return withContext(slot, M{
+		"blockhash":            blk.blockhash,
+		"lastValidBlockHeight": blk.blockHeight + srv.opts.BlockhashValidity,
+	}), nil
+}
+
+func getRecentBlockhash(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return withContext(slot, M{
+		"blockhash": srv.blockAt(slot).blockhash,
+		"feeCalculator": M{
+			"lamportsPerSignature": srv.opts.LamportsPerSignature,
+		},
+	}), nil
+}
+
+func isBlockhashValid(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var hash solana.Hash
+	if rpcErr := p.required(0, &hash); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return withContext(slot, srv.isBlockhashValid(hash)), nil
+}
+
+func getFirstAvailableBlock(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return uint64(0), nil
+}
+
+func getMaxSlot(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return srv.Slot(), nil
+}
+
+func getEpochInfo(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	transactionCount := srv.transactionCount
+	return &rpc.GetEpochInfoResult{
+		AbsoluteSlot:     slot,
+		BlockHeight:      srv.blockHeightAt(cfg.Commitment),
+		Epoch:            slot / srv.opts.SlotsPerEpoch,
+		SlotIndex:        slot % srv.opts.SlotsPerEpoch,
+		SlotsInEpoch:     srv.opts.SlotsPerEpoch,
+		TransactionCount: &transactionCount,
+	}, nil
+}
+
+func getEpochSchedule(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return &rpc.GetEpochScheduleResult{
+		SlotsPerEpoch:            srv.opts.SlotsPerEpoch,
+		LeaderScheduleSlotOffset: srv.opts.SlotsPerEpoch,
+	}, nil
+}
+
+func getHealth(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return rpc.HealthOk, nil
+}
+
+func getIdentity(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return M{"identity": srv.opts.Identity}, nil
+}
+
+func getVersion(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return &rpc.GetVersionResult{SolanaCore: srv.opts.Version}, nil
+}
+
+func getGenesisHash(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return srv.genesisHash, nil
+}
+
+func getSlotLeader(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return srv.opts.Identity, nil
+}
+
+func getSlotLeaders(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var start, limit uint64
+	if rpcErr := p.required(0, &start); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if rpcErr := p.required(1, &limit); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if limit > 5000 {
+		return nil, errInvalidParams("Invalid limit; max 5000")
+	}
+	out := make([]solana.PublicKey, limit)
+	for i := range out {
+		out[i] = srv.opts.Identity
+	}
+	return out, nil
+}
+
+func getClusterNodes(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	host := srv.URL()
+	if u, err := url.Parse(host); err == nil {
+		host = u.Host
+	}
+	return []M{
+		{
+			"pubkey":       srv.opts.Identity,
+			"gossip":       nil,
+			"tpu":          nil,
+			"rpc":          host,
+			"version":      srv.opts.Version,
+			"featureSet":   nil,
+			"shredVersion": nil,
+		},
+	}, nil
+}
+
+func getVoteAccounts(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return M{
+		"current":    []interface{}{},
+		"delinquent": []interface{}{},
+	}, nil
+}
+
+func getBlockProduction(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	// Defaults to the current epoch.
+	first, last := slot-slot%srv.opts.SlotsPerEpoch, slot
+	if cfg.Range != nil {
+		first = cfg.Range.FirstSlot
+		if cfg.Range.LastSlot != nil {
+			last = *cfg.Range.LastSlot
+		}
+	}
+	if last > slot {
+		return nil, errInvalidParams("lastSlot, %d, cannot be greater than current slot, %d", last, slot)
+	}
+	if first > last {
+		return nil, errInvalidParams("lastSlot, %d, cannot be less than firstSlot, %d", last, first)
+	}
+	// The server is the leader of all the slots.
+	byIdentity := rpc.IdentityToSlotsBlocks{}
+	if cfg.Identity == "" || cfg.Identity == srv.opts.Identity.String() {
+		leaderSlots := int64(last - first + 1)
+		blocksProduced := int64(len(srv.blockSlots(first, last)))
+		byIdentity[srv.opts.Identity] = [2]int64{leaderSlots, blocksProduced}
+	}
+	return withContext(slot, &rpc.BlockProductionResult{
+		ByIdentity: byIdentity,
+		Range:      rpc.SlotRangeResponse{FirstSlot: first, LastSlot: last},
+	}), nil
+}
+
+func getBlockCommitment(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var slot uint64
+	if rpcErr := p.required(0, &slot); rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	// There is no stake: the commitment of the blocks that
+	// are not rooted yet is reported with zero votes.
+	out := &rpc.GetBlockCommitmentResult{}
+	if _, ok := srv.blocks[slot]; ok && slot > srv.slotAt(rpc.CommitmentFinalized) {
+		out.Commitment = make([]uint64, 32)
+	}
+	return out, nil
+}
+
+func getLeaderSchedule(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	// The first parameter is either a slot or the config.
+	var at *uint64
+	cfgIndex := 0
+	if p.has(0) && len(p[0]) > 0 && p[0][0] != '{' {
+		if rpcErr := p.decode(0, &at); rpcErr != nil {
+			return nil, rpcErr
+		}
+		cfgIndex = 1
+	}
+	cfg, rpcErr := p.config(cfgIndex)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	epoch := slot / srv.opts.SlotsPerEpoch
+	if at != nil && *at/srv.opts.SlotsPerEpoch != epoch && *at/srv.opts.SlotsPerEpoch != epoch+1 {
+		// Unknown schedule.
+		return nil, nil
+	}
+	// The server is the leader of all the slots.
+	out := rpc.GetLeaderScheduleResult{}
+	if cfg.Identity == "" || cfg.Identity == srv.opts.Identity.String() {
+		slots := make([]uint64, srv.opts.SlotsPerEpoch)
+		for i := range slots {
+			slots[i] = uint64(i)
+		}
+		out[srv.opts.Identity] = slots
+	}
+	return out, nil
+}
+
+func getHighestSnapshotSlot(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	// A full snapshot is taken at every root.
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return &rpc.GetHighestSnapshotSlotResult{Full: srv.slotAt(rpc.CommitmentFinalized)}, nil
+}
+
+func getSnapshotSlot(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.slotAt(rpc.CommitmentFinalized), nil
+}
+
+func getRecentPerformanceSamples(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var limit *uint64
+	if rpcErr := p.decode(0, &limit); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if limit != nil && *limit > 720 {
+		return nil, errInvalidParams("Invalid limit; max 720")
+	}
+	// No samples are taken.
+	return []*rpc.GetRecentPerformanceSamplesResult{}, nil
+}
+
+// inflationGovernor is the default inflation schedule of the cluster.
+var inflationGovernor = rpc.GetInflationGovernorResult{
+	Initial:        0.08,
+	Terminal:       0.015,
+	Taper:          0.15,
+	Foundation:     0.05,
+	FoundationTerm: 7,
+}
+
+func getInflationGovernor(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	if _, rpcErr := p.config(0); rpcErr != nil {
+		return nil, rpcErr
+	}
+	governor := inflationGovernor
+	return &governor, nil
+}
+
+func getInflationRate(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	// The rate stays at its initial value.
+	total := inflationGovernor.Initial
+	foundation := total * inflationGovernor.Foundation
+	return &rpc.GetInflationRateResult{
+		Total:      total,
+		Validator:  total - foundation,
+		Foundation: foundation,
+		Epoch:      float64(srv.slot / srv.opts.SlotsPerEpoch),
+	}, nil
+}
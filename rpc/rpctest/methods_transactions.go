@@ -0,0 +1,535 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpctest
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// decodeTransaction decodes the wire transaction in the first parameter.
+func decodeTransaction(p params, encoding solana.EncodingType) (*solana.Transaction, *jsonrpc.RPCError) {
+	var encoded string
+	if rpcErr := p.required(0, &encoded); rpcErr != nil {
+		return nil, rpcErr
+	}
+	var tx *solana.Transaction
+	var err error
+	switch encoding {
+	case "", solana.EncodingBase58:
+		tx, err = solana.TransactionFromBase58(encoded)
+	case solana.EncodingBase64:
+		tx, err = solana.TransactionFromBase64(encoded)
+	default:
+		return nil, errInvalidParams("Invalid params: unsupported encoding: %s", encoding)
+	}
+	if err != nil {
+		return nil, errInvalidParams("failed to deserialize solana_sdk::transaction::versioned::VersionedTransaction: %s", err)
+	}
+	if len(tx.Signatures) == 0 {
+		return nil, errInvalidParams("Invalid params: transaction has no signatures")
+	}
+	return tx, nil
+}
+
+func errPreflightFailure(txErr interface{}, logs []string) *jsonrpc.RPCError {
+	if logs == nil {
+		logs = []string{}
+	}
+	return &jsonrpc.RPCError{
+		Code:    codeSendTransactionPreflightFailure,
+		Message: fmt.Sprintf("Transaction simulation failed: %v", txErr),
+		Data: M{
+			"err":      txErr,
+			"logs":     logs,
+			"accounts": nil,
+		},
+	}
+}
+
+func sendTransaction(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	tx, rpcErr := decodeTransaction(p, cfg.Encoding)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		return nil, &jsonrpc.RPCError{
+			Code:    codeTransactionSignatureVerificationFailure,
+			Message: "Transaction signature verification failure",
+		}
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: codeInternalError, Message: err.Error()}
+	}
+	signature := tx.Signatures[0]
+
+	srv.mu.Lock()
+	exec, err := srv.checkAndExecute(tx)
+	if err != nil {
+		srv.mu.Unlock()
+		var txErr *transactionError
+		if !errors.As(err, &txErr) {
+			return nil, errInvalidParams("Invalid params: %s", err)
+		}
+		if cfg.SkipPreflight {
+			// The validator accepts the transaction, which is then dropped.
+			return signature.String(), nil
+		}
+		return nil, errPreflightFailure(txErr.value, nil)
+	}
+	if exec.meta.Err != nil && !cfg.SkipPreflight {
+		srv.mu.Unlock()
+		return nil, errPreflightFailure(exec.meta.Err, exec.meta.LogMessages)
+	}
+	changed := srv.commit(exec)
+	srv.storeTransaction(tx, raw, exec.meta)
+	srv.mu.Unlock()
+
+	srv.pubsub.notifyAccounts(changed)
+	srv.pubsub.notifyLogs(tx, exec.meta)
+	srv.pubsub.notifySignatures()
+	return signature.String(), nil
+}
+
+// checkAndExecute checks the blockhash and the signature of the transaction
+// and executes it, without committing. Must be called with mu held.
+func (srv *Server) checkAndExecute(tx *solana.Transaction) (*execution, error) {
+	if !srv.isBlockhashValid(tx.Message.RecentBlockhash) {
+		return nil, errBlockhashNotFound
+	}
+	if _, ok := srv.transactions[tx.Signatures[0]]; ok {
+		return nil, errAlreadyProcessed
+	}
+	return srv.execute(tx)
+}
+
+func simulateTransaction(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if cfg.SigVerify && cfg.ReplaceRecentBlockhash {
+		return nil, errInvalidParams("sigVerify may not be used with replaceRecentBlockhash")
+	}
+	tx, rpcErr := decodeTransaction(p, cfg.Encoding)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if cfg.SigVerify {
+		if err := tx.VerifySignatures(); err != nil {
+			return nil, &jsonrpc.RPCError{
+				Code:    codeTransactionSignatureVerificationFailure,
+				Message: "Transaction signature verification failure",
+			}
+		}
+	}
+	var requested []solana.PublicKey
+	if cfg.Accounts != nil {
+		for _, address := range cfg.Accounts.Addresses {
+			pk, err := solana.PublicKeyFromBase58(address)
+			if err != nil {
+				return nil, errInvalidParams("Invalid param: %s", err)
+			}
+			requested = append(requested, pk)
+		}
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if cfg.ReplaceRecentBlockhash {
+		tx.Message.RecentBlockhash = srv.blocks[srv.slot].blockhash
+	}
+
+	result := M{
+		"err":           nil,
+		"logs":          []string{},
+		"accounts":      nil,
+		"unitsConsumed": uint64(0),
+		"returnData":    nil,
+	}
+	var exec *execution
+	var err error
+	if !srv.isBlockhashValid(tx.Message.RecentBlockhash) {
+		err = errBlockhashNotFound
+	} else {
+		exec, err = srv.execute(tx)
+	}
+	if err != nil {
+		var txErr *transactionError
+		if !errors.As(err, &txErr) {
+			return nil, errInvalidParams("Invalid params: %s", err)
+		}
+		result["err"] = txErr.value
+		return withContext(slot, result), nil
+	}
+
+	result["err"] = exec.meta.Err
+	if exec.meta.LogMessages != nil {
+		result["logs"] = exec.meta.LogMessages
+	}
+	if exec.meta.ComputeUnitsConsumed != nil {
+		result["unitsConsumed"] = *exec.meta.ComputeUnitsConsumed
+	}
+	if cfg.Accounts != nil {
+		accountsCfg := &config{Encoding: cfg.Accounts.Encoding}
+		accounts := make([]interface{}, len(requested))
+		for i, address := range requested {
+			acc, ok := exec.accounts[address]
+			if !ok {
+				acc = srv.accounts[address]
+			}
+			if acc == nil {
+				continue
+			}
+			accounts[i], rpcErr = encodeAccount(acc, accountsCfg)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+		result["accounts"] = accounts
+	}
+	return withContext(slot, result), nil
+}
+
+func requestAirdrop(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var lamports uint64
+	if rpcErr := p.required(1, &lamports); rpcErr != nil {
+		return nil, rpcErr
+	}
+	signature, err := srv.Airdrop(address, lamports)
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: codeInternalError, Message: err.Error()}
+	}
+	return signature.String(), nil
+}
+
+// confirmationStatus returns the confirmation status of a transaction processed at the provided slot.
+// Must be called with mu held.
+func (srv *Server) confirmationStatus(slot uint64) rpc.ConfirmationStatusType {
+	switch {
+	case slot <= srv.slotAt(rpc.CommitmentFinalized):
+		return rpc.ConfirmationStatusFinalized
+	case slot <= srv.slotAt(rpc.CommitmentConfirmed):
+		return rpc.ConfirmationStatusConfirmed
+	default:
+		return rpc.ConfirmationStatusProcessed
+	}
+}
+
+// hasReached reports whether the provided status satisfies the provided commitment.
+func hasReached(status rpc.ConfirmationStatusType, commitment rpc.CommitmentType) bool {
+	switch commitment {
+	case rpc.CommitmentProcessed, rpc.CommitmentRecent:
+		return true
+	case rpc.CommitmentConfirmed, rpc.CommitmentSingle, rpc.CommitmentSingleGossip:
+		return status != rpc.ConfirmationStatusProcessed
+	default:
+		return status == rpc.ConfirmationStatusFinalized
+	}
+}
+
+func getSignatureStatuses(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var signatures []solana.Signature
+	if rpcErr := p.required(0, &signatures); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if len(signatures) > 256 {
+		return nil, errInvalidParams("Too many inputs provided; max 256")
+	}
+	if _, rpcErr := p.config(1); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	values := make([]interface{}, len(signatures))
+	for i, signature := range signatures {
+		tx, ok := srv.transactions[signature]
+		if !ok {
+			continue
+		}
+		status := srv.confirmationStatus(tx.Slot)
+		var confirmations *uint64
+		if status != rpc.ConfirmationStatusFinalized {
+			n := srv.slot - tx.Slot
+			confirmations = &n
+		}
+		values[i] = &rpc.SignatureStatusesResult{
+			Slot:               tx.Slot,
+			Confirmations:      confirmations,
+			Err:                tx.Meta.Err,
+			ConfirmationStatus: status,
+			Status:             tx.Meta.Status,
+		}
+	}
+	return withContext(srv.slot, values), nil
+}
+
+func getSignaturesForAddress(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	address, rpcErr := p.pubkey(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if rpcErr := requireConfirmed(cfg); rpcErr != nil {
+		return nil, rpcErr
+	}
+	limit := uint64(1000)
+	if cfg.Limit != nil {
+		if *cfg.Limit == 0 || *cfg.Limit > 1000 {
+			return nil, errInvalidParams("Invalid limit; max 1000")
+		}
+		limit = *cfg.Limit
+	}
+	var before, until solana.Signature
+	var err error
+	if cfg.Before != "" {
+		if before, err = solana.SignatureFromBase58(cfg.Before); err != nil {
+			return nil, errInvalidParams("Invalid param: %s", err)
+		}
+	}
+	if cfg.Until != "" {
+		if until, err = solana.SignatureFromBase58(cfg.Until); err != nil {
+			return nil, errInvalidParams("Invalid param: %s", err)
+		}
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	maxSlot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	signatures := srv.signaturesByAddress[address]
+	out := make([]*rpc.TransactionSignature, 0)
+	skipping := !before.IsZero()
+	// Newest first.
+	for i := len(signatures) - 1; i >= 0 && uint64(len(out)) < limit; i-- {
+		signature := signatures[i]
+		if skipping {
+			if signature.Equals(before) {
+				skipping = false
+			}
+			continue
+		}
+		if !until.IsZero() && signature.Equals(until) {
+			break
+		}
+		tx := srv.transactions[signature]
+		if tx.Slot > maxSlot {
+			continue
+		}
+		blockTime := tx.BlockTime
+		out = append(out, &rpc.TransactionSignature{
+			Err:                tx.Meta.Err,
+			Signature:          signature,
+			Slot:               tx.Slot,
+			BlockTime:          &blockTime,
+			ConfirmationStatus: srv.confirmationStatus(tx.Slot),
+		})
+	}
+	return out, nil
+}
+
+// encodeTransaction renders a transaction with the requested encoding.
+func encodeTransaction(tx *Transaction, encoding solana.EncodingType) (interface{}, *jsonrpc.RPCError) {
+	switch encoding {
+	case "", solana.EncodingJSON, solana.EncodingJSONParsed:
+		// Decode a fresh copy, so that the lookups are not resolved.
+		decoded, err := solana.TransactionFromBytes(tx.Raw)
+		if err != nil {
+			return nil, &jsonrpc.RPCError{Code: codeInternalError, Message: err.Error()}
+		}
+		return decoded, nil
+	case solana.EncodingBase58, solana.EncodingBase64, solana.EncodingBase64Zstd:
+		return solana.Data{Content: tx.Raw, Encoding: encoding}, nil
+	default:
+		return nil, errInvalidParams("Invalid params: unknown encoding %q", encoding)
+	}
+}
+
+// encodeTransactionWithMeta renders a transaction in the format
+// of the result of getTransaction (without the slot and blockTime).
+func encodeTransactionWithMeta(tx *Transaction, cfg *config) (M, *jsonrpc.RPCError) {
+	versioned := tx.Transaction.Message.IsVersioned()
+	if versioned && cfg.MaxSupportedTransactionVersion == nil {
+		return nil, errUnsupportedTransactionVersion()
+	}
+	encoded, rpcErr := encodeTransaction(tx, cfg.Encoding)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	out := M{
+		"transaction": encoded,
+		"meta":        tx.Meta,
+	}
+	if cfg.MaxSupportedTransactionVersion != nil {
+		if versioned {
+			out["version"] = 0
+		} else {
+			out["version"] = "legacy"
+		}
+	}
+	return out, nil
+}
+
+func getTransaction(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var signature solana.Signature
+	if rpcErr := p.required(0, &signature); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if rpcErr := requireConfirmed(cfg); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	tx, ok := srv.transactions[signature]
+	if !ok || tx.Slot > srv.slotAt(cfg.Commitment) {
+		return nil, nil
+	}
+	out, rpcErr := encodeTransactionWithMeta(tx, cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	out["slot"] = tx.Slot
+	out["blockTime"] = tx.BlockTime
+	return out, nil
+}
+
+func getTransactionCount(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	if _, rpcErr := p.config(0); rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.transactionCount, nil
+}
+
+func getFeeForMessage(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var encoded string
+	if rpcErr := p.required(0, &encoded); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var message solana.Message
+	if err := message.UnmarshalBase64(encoded); err != nil {
+		return nil, errInvalidParams("Invalid params: %s", err)
+	}
+
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if !srv.isBlockhashValid(message.RecentBlockhash) {
+		return withContext(slot, nil), nil
+	}
+	return withContext(slot, srv.opts.LamportsPerSignature*uint64(message.Header.NumRequiredSignatures)), nil
+}
+
+func getRecentPrioritizationFees(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	return []interface{}{}, nil
+}
+
+func getFeeRateGovernor(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	// The fee is fixed (targetSignaturesPerSlot is zero).
+	fee := srv.opts.LamportsPerSignature
+	return withContext(srv.slot, &rpc.FeeRateGovernorResult{
+		FeeRateGovernor: rpc.FeeRateGovernor{
+			BurnPercent:                50,
+			MaxLamportsPerSignature:    fee,
+			MinLamportsPerSignature:    fee,
+			TargetLamportsPerSignature: fee,
+		},
+	}), nil
+}
+
+func getFees(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	cfg, rpcErr := p.config(0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	blk := srv.blockAt(slot)
+	return withContext(slot, M{
+		"blockhash": blk.blockhash,
+		"feeCalculator": M{
+			"lamportsPerSignature": srv.opts.LamportsPerSignature,
+		},
+		"lastValidSlot":        slot + srv.opts.BlockhashValidity,
+		"lastValidBlockHeight": blk.blockHeight + srv.opts.BlockhashValidity,
+	}), nil
+}
+
+func getFeeCalculatorForBlockhash(srv *Server, p params) (interface{}, *jsonrpc.RPCError) {
+	var hash solana.Hash
+	if rpcErr := p.required(0, &hash); rpcErr != nil {
+		return nil, rpcErr
+	}
+	cfg, rpcErr := p.config(1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	slot, rpcErr := srv.contextSlot(cfg)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if !srv.isBlockhashValid(hash) {
+		return withContext(slot, nil), nil
+	}
+	return withContext(slot, M{
+		"feeCalculator": M{
+			"lamportsPerSignature": srv.opts.LamportsPerSignature,
+		},
+	}), nil
+}
@@ -0,0 +1,485 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpctest
+
+import (
+	stdjson "encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/gorilla/websocket"
+)
+
+// pubsub serves the subscriptions of the PubSub API.
+//
+// Account, program and logs notifications are sent as soon as a change is processed,
+// regardless of the requested commitment; signature and block notifications
+// honor the requested commitment. Vote subscriptions never produce notifications.
+type pubsub struct {
+	srv      *Server
+	upgrader websocket.Upgrader
+
+	mu     sync.Mutex
+	nextID uint64
+	conns  map[*wsConn]struct{}
+	subs   map[uint64]*subscription
+
+	// Serializes the block notifications.
+	blocksMu sync.Mutex
+}
+
+type wsConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (c *wsConn) write(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+type subscription struct {
+	id   uint64
+	conn *wsConn
+	kind string // e.g. "account" for accountSubscribe.
+
+	address   solana.PublicKey // account, program
+	signature solana.Signature
+	logs      string            // "all", "allWithVotes", or "mentions".
+	mentions  *solana.PublicKey // logs, block
+	config    *config
+	lastSlot  uint64 // block
+}
+
+func newPubSub(srv *Server) *pubsub {
+	return &pubsub{
+		srv:   srv,
+		conns: make(map[*wsConn]struct{}),
+		subs:  make(map[uint64]*subscription),
+	}
+}
+
+func (ps *pubsub) serveWS(rw http.ResponseWriter, req *http.Request) {
+	conn, err := ps.upgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		return
+	}
+	c := &wsConn{conn: conn}
+	ps.mu.Lock()
+	ps.conns[c] = struct{}{}
+	ps.mu.Unlock()
+
+	defer func() {
+		ps.mu.Lock()
+		delete(ps.conns, c)
+		for id, sub := range ps.subs {
+			if sub.conn == c {
+				delete(ps.subs, id)
+			}
+		}
+		ps.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var r request
+		if err := json.Unmarshal(message, &r); err != nil {
+			c.write(&response{JSONRPC: "2.0", Error: errParse(err), ID: stdjson.RawMessage(`null`)})
+			continue
+		}
+		c.write(ps.handle(c, &r))
+	}
+}
+
+func (ps *pubsub) handle(c *wsConn, req *request) *response {
+	resp := &response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+	}
+	if len(resp.ID) == 0 {
+		resp.ID = stdjson.RawMessage(`null`)
+	}
+	if rpcErr := ps.srv.takeFault(req.Method); rpcErr != nil {
+		resp.Error = rpcErr
+		return resp
+	}
+	var p params
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			resp.Error = errInvalidParams("params must be an array: %s", err)
+			return resp
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(req.Method, "Unsubscribe"):
+		var id uint64
+		if rpcErr := p.required(0, &id); rpcErr != nil {
+			resp.Error = rpcErr
+			return resp
+		}
+		kind := strings.TrimSuffix(req.Method, "Unsubscribe")
+		ps.mu.Lock()
+		sub, ok := ps.subs[id]
+		ok = ok && sub.conn == c && sub.kind == kind
+		if ok {
+			delete(ps.subs, id)
+		}
+		ps.mu.Unlock()
+		resp.Result = ok
+	case strings.HasSuffix(req.Method, "Subscribe"):
+		sub, rpcErr := newSubscription(strings.TrimSuffix(req.Method, "Subscribe"), p)
+		if rpcErr != nil {
+			resp.Error = rpcErr
+			return resp
+		}
+		sub.conn = c
+		ps.mu.Lock()
+		ps.nextID++
+		sub.id = ps.nextID
+		ps.mu.Unlock()
+		if sub.kind == "block" {
+			ps.blocksMu.Lock()
+			ps.srv.mu.RLock()
+			sub.lastSlot = ps.srv.slotAt(sub.config.Commitment)
+			ps.srv.mu.RUnlock()
+			ps.mu.Lock()
+			ps.subs[sub.id] = sub
+			ps.mu.Unlock()
+			ps.blocksMu.Unlock()
+		} else {
+			ps.mu.Lock()
+			ps.subs[sub.id] = sub
+			ps.mu.Unlock()
+		}
+		resp.Result = sub.id
+		if sub.kind == "signature" {
+			// The transaction might already have reached the requested commitment.
+			defer func() {
+				go ps.notifySignatures()
+			}()
+		}
+	default:
+		resp.Error = &jsonrpc.RPCError{Code: codeMethodNotFound, Message: "Method not found"}
+	}
+	return resp
+}
+
+func newSubscription(kind string, p params) (*subscription, *jsonrpc.RPCError) {
+	sub := &subscription{kind: kind}
+	var rpcErr *jsonrpc.RPCError
+	switch kind {
+	case "account", "program":
+		if sub.address, rpcErr = p.pubkey(0); rpcErr != nil {
+			return nil, rpcErr
+		}
+		sub.config, rpcErr = p.config(1)
+	case "signature":
+		if rpcErr = p.required(0, &sub.signature); rpcErr != nil {
+			return nil, rpcErr
+		}
+		sub.config, rpcErr = p.config(1)
+	case "logs":
+		var filter struct {
+			Mentions []solana.PublicKey `json:"mentions"`
+		}
+		if p.has(0) && len(p[0]) > 0 && p[0][0] == '"' {
+			if rpcErr = p.decode(0, &sub.logs); rpcErr != nil {
+				return nil, rpcErr
+			}
+			if sub.logs != "all" && sub.logs != "allWithVotes" {
+				return nil, errInvalidParams("Invalid params: unknown logs filter %q", sub.logs)
+			}
+		} else {
+			if rpcErr = p.required(0, &filter); rpcErr != nil {
+				return nil, rpcErr
+			}
+			if len(filter.Mentions) != 1 {
+				return nil, errInvalidParams("Invalid Request: Only 1 address supported")
+			}
+			sub.logs = "mentions"
+			sub.mentions = &filter.Mentions[0]
+		}
+		sub.config, rpcErr = p.config(1)
+	case "block":
+		var filter struct {
+			MentionsAccountOrProgram *solana.PublicKey `json:"mentionsAccountOrProgram"`
+		}
+		if !(p.has(0) && len(p[0]) > 0 && p[0][0] == '"') {
+			if rpcErr = p.required(0, &filter); rpcErr != nil {
+				return nil, rpcErr
+			}
+			sub.mentions = filter.MentionsAccountOrProgram
+		}
+		sub.config, rpcErr = p.config(1)
+	case "slot", "root", "slotsUpdates", "vote":
+		sub.config = new(config)
+	default:
+		return nil, &jsonrpc.RPCError{Code: codeMethodNotFound, Message: "Method not found"}
+	}
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return sub, nil
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  struct {
+		Result       interface{} `json:"result"`
+		Subscription uint64      `json:"subscription"`
+	} `json:"params"`
+}
+
+func (sub *subscription) notify(result interface{}) {
+	msg := &notification{
+		JSONRPC: "2.0",
+		Method:  sub.kind + "Notification",
+	}
+	msg.Params.Result = result
+	msg.Params.Subscription = sub.id
+	sub.conn.write(msg)
+}
+
+// subscriptions returns the active subscriptions of the provided kind.
+func (ps *pubsub) subscriptions(kind string) []*subscription {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	out := make([]*subscription, 0)
+	for _, sub := range ps.subs {
+		if sub.kind == kind {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+func (ps *pubsub) closeAll() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for c := range ps.conns {
+		c.writeMu.Lock()
+		c.conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second),
+		)
+		c.writeMu.Unlock()
+		c.conn.Close()
+	}
+}
+
+// notifyAccounts notifies the account and program subscribers
+// of the changes to the provided accounts.
+func (ps *pubsub) notifyAccounts(addresses []solana.PublicKey) {
+	accountSubs := ps.subscriptions("account")
+	programSubs := ps.subscriptions("program")
+	if len(accountSubs)+len(programSubs) == 0 {
+		return
+	}
+
+	type pending struct {
+		sub    *subscription
+		result interface{}
+	}
+	out := make([]pending, 0)
+
+	ps.srv.mu.RLock()
+	slot := ps.srv.slot
+	for _, address := range addresses {
+		acc := ps.srv.accounts[address]
+		for _, sub := range accountSubs {
+			if !sub.address.Equals(address) {
+				continue
+			}
+			value, rpcErr := encodeAccount(acc, sub.config)
+			if rpcErr != nil {
+				continue
+			}
+			out = append(out, pending{sub, withContext(slot, value)})
+		}
+		if acc == nil {
+			continue
+		}
+		for _, sub := range programSubs {
+			if !acc.Owner.Equals(sub.address) || !sub.config.Filters.Match(acc.Data) {
+				continue
+			}
+			value, rpcErr := encodeAccount(acc, sub.config)
+			if rpcErr != nil {
+				continue
+			}
+			out = append(out, pending{sub, withContext(slot, M{"pubkey": address, "account": value})})
+		}
+	}
+	ps.srv.mu.RUnlock()
+
+	for _, n := range out {
+		n.sub.notify(n.result)
+	}
+}
+
+// notifyLogs notifies the logs subscribers of a processed transaction.
+func (ps *pubsub) notifyLogs(tx *solana.Transaction, meta *rpc.TransactionMeta) {
+	subs := ps.subscriptions("logs")
+	if len(subs) == 0 {
+		return
+	}
+	keys, err := tx.Message.GetAllKeys()
+	if err != nil {
+		keys = tx.Message.AccountKeys
+	}
+	isVote := keys.Contains(solana.VoteProgramID)
+	logs := meta.LogMessages
+	if logs == nil {
+		logs = []string{}
+	}
+
+	ps.srv.mu.RLock()
+	slot := ps.srv.slot
+	ps.srv.mu.RUnlock()
+	result := withContext(slot, M{
+		"signature": tx.Signatures[0],
+		"err":       meta.Err,
+		"logs":      logs,
+	})
+	for _, sub := range subs {
+		switch sub.logs {
+		case "all":
+			if isVote {
+				continue
+			}
+		case "mentions":
+			if !keys.Contains(*sub.mentions) {
+				continue
+			}
+		}
+		sub.notify(result)
+	}
+}
+
+// notifySignatures notifies the signature subscribers whose transaction
+// has reached the requested commitment, and removes their subscription.
+func (ps *pubsub) notifySignatures() {
+	subs := ps.subscriptions("signature")
+	if len(subs) == 0 {
+		return
+	}
+	type pending struct {
+		sub    *subscription
+		result interface{}
+	}
+	out := make([]pending, 0)
+
+	ps.srv.mu.RLock()
+	for _, sub := range subs {
+		tx, ok := ps.srv.transactions[sub.signature]
+		if !ok || !hasReached(ps.srv.confirmationStatus(tx.Slot), sub.config.Commitment) {
+			continue
+		}
+		out = append(out, pending{sub, withContext(tx.Slot, M{"err": tx.Meta.Err})})
+	}
+	ps.srv.mu.RUnlock()
+
+	for _, n := range out {
+		ps.mu.Lock()
+		_, active := ps.subs[n.sub.id]
+		delete(ps.subs, n.sub.id)
+		ps.mu.Unlock()
+		if active {
+			n.sub.notify(n.result)
+		}
+	}
+}
+
+// notifySlot notifies the slot, root and slotsUpdates subscribers of a new slot.
+func (ps *pubsub) notifySlot(parent, slot, root uint64) {
+	for _, sub := range ps.subscriptions("slot") {
+		sub.notify(M{"parent": parent, "root": root, "slot": slot})
+	}
+	if root > 0 {
+		for _, sub := range ps.subscriptions("root") {
+			sub.notify(root)
+		}
+	}
+	now := solana.UnixTimeMilliseconds(time.Now().UnixNano() / int64(time.Millisecond))
+	for _, sub := range ps.subscriptions("slotsUpdates") {
+		sub.notify(M{"parent": parent, "slot": slot, "timestamp": now, "type": "createdBank"})
+	}
+}
+
+// notifyBlocks notifies the block subscribers of the blocks
+// that have reached the requested commitment since the last notification.
+func (ps *pubsub) notifyBlocks() {
+	ps.blocksMu.Lock()
+	defer ps.blocksMu.Unlock()
+	subs := ps.subscriptions("block")
+	if len(subs) == 0 {
+		return
+	}
+	type pending struct {
+		sub    *subscription
+		result interface{}
+	}
+	out := make([]pending, 0)
+
+	ps.srv.mu.RLock()
+	for _, sub := range subs {
+		slot := ps.srv.slotAt(sub.config.Commitment)
+		for _, blockSlot := range ps.srv.blockSlots(sub.lastSlot+1, slot) {
+			blk := ps.srv.blocks[blockSlot]
+			var include func(tx *Transaction) bool
+			if sub.mentions != nil {
+				mentions := *sub.mentions
+				include = func(tx *Transaction) bool {
+					keys, err := tx.Transaction.Message.GetAllKeys()
+					if err != nil {
+						keys = tx.Transaction.Message.AccountKeys
+					}
+					return keys.Contains(mentions)
+				}
+			}
+			encoded, rpcErr := ps.srv.encodeBlock(blk, sub.config, include)
+			value := M{"slot": blockSlot, "err": nil, "block": encoded}
+			if rpcErr != nil {
+				value = M{"slot": blockSlot, "err": rpcErr.Message, "block": nil}
+			}
+			out = append(out, pending{sub, withContext(blockSlot, value)})
+		}
+		if slot > sub.lastSlot {
+			sub.lastSlot = slot
+		}
+	}
+	ps.srv.mu.RUnlock()
+
+	for _, n := range out {
+		n.sub.notify(n.result)
+	}
+}
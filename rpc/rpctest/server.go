@@ -0,0 +1,350 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpctest provides an in-process fake Solana validator
+// that serves the JSON-RPC and PubSub (websocket) APIs from an in-memory store.
+//
+// It is meant to be used in unit tests of code that uses rpc.Client and ws.Client:
+//
+//	srv := rpctest.NewServer(nil)
+//	defer srv.Close()
+//
+//	srv.SetAccount(payer, &rpctest.Account{Lamports: solana.LAMPORTS_PER_SOL})
+//	client := rpc.New(srv.URL())
+//	wsClient, err := ws.Connect(ctx, srv.WSURL())
+package rpctest
+
+import (
+	"bytes"
+	"context"
+	stdjson "encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/gorilla/websocket"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Number of slots after which a slot is considered "confirmed".
+	// Defaults to 1.
+	ConfirmationDepth uint64
+	// Number of slots after which a slot is considered "finalized" (rooted).
+	// Defaults to 32.
+	FinalizationDepth uint64
+	// Number of blocks for which a blockhash stays valid.
+	// Defaults to 150.
+	BlockhashValidity uint64
+	// Fee charged per signature.
+	// Defaults to 5000.
+	LamportsPerSignature uint64
+	// Slots per epoch.
+	// Defaults to 432000.
+	SlotsPerEpoch uint64
+	// Slot the server starts at.
+	// Defaults to 1.
+	StartSlot uint64
+	// Identity of the fake validator.
+	// Defaults to a random key.
+	Identity solana.PublicKey
+	// Version reported by getVersion.
+	// Defaults to "1.18.0".
+	Version string
+}
+
+func (opts *Options) withDefaults() Options {
+	out := Options{}
+	if opts != nil {
+		out = *opts
+	}
+	if out.ConfirmationDepth == 0 {
+		out.ConfirmationDepth = 1
+	}
+	if out.FinalizationDepth == 0 {
+		out.FinalizationDepth = 32
+	}
+	if out.BlockhashValidity == 0 {
+		out.BlockhashValidity = 150
+	}
+	if out.LamportsPerSignature == 0 {
+		out.LamportsPerSignature = 5000
+	}
+	if out.SlotsPerEpoch == 0 {
+		out.SlotsPerEpoch = 432000
+	}
+	if out.StartSlot == 0 {
+		out.StartSlot = 1
+	}
+	if out.Identity.IsZero() {
+		out.Identity = solana.NewWallet().PublicKey()
+	}
+	if out.Version == "" {
+		out.Version = "1.18.0"
+	}
+	return out
+}
+
+// Server is a fake Solana validator serving the JSON-RPC API over HTTP
+// and the PubSub API over websocket, on the same address.
+// Server is safe for concurrent use.
+type Server struct {
+	opts       Options
+	httpServer *httptest.Server
+
+	mu           sync.RWMutex
+	accounts     map[solana.PublicKey]*Account
+	transactions map[solana.Signature]*Transaction
+	// Signatures mentioning an address, in the order they were processed.
+	signaturesByAddress map[solana.PublicKey][]solana.Signature
+	blocks              map[uint64]*block
+	blockhashes         map[solana.Hash]uint64 // blockhash -> last valid block height
+	slot                uint64
+	blockHeight         uint64
+	transactionCount    uint64
+	genesisHash         solana.Hash
+	faucet              solana.PrivateKey
+	processor           TransactionProcessor
+
+	faultsMu sync.Mutex
+	faults   map[string][]*fault
+	latency  map[string]time.Duration
+
+	pubsub *pubsub
+
+	autoAdvanceMu   sync.Mutex
+	autoAdvanceStop chan struct{}
+}
+
+// NewServer creates and starts a new Server.
+// The provided options can be nil.
+func NewServer(opts *Options) *Server {
+	srv := &Server{
+		opts:                opts.withDefaults(),
+		accounts:            make(map[solana.PublicKey]*Account),
+		transactions:        make(map[solana.Signature]*Transaction),
+		signaturesByAddress: make(map[solana.PublicKey][]solana.Signature),
+		blocks:              make(map[uint64]*block),
+		blockhashes:         make(map[solana.Hash]uint64),
+		faults:              make(map[string][]*fault),
+		latency:             make(map[string]time.Duration),
+		processor:           NoopProcessor,
+	}
+	srv.pubsub = newPubSub(srv)
+	srv.faucet = solana.NewWallet().PrivateKey
+	srv.genesisHash = solana.HashFromBytes(srv.faucet.PublicKey().Bytes())
+	// Genesis block.
+	srv.newBlock(nil)
+	if srv.opts.StartSlot > 0 {
+		genesis := srv.blocks[0]
+		srv.slot = srv.opts.StartSlot
+		srv.blockHeight = 1
+		srv.newBlock(genesis)
+	}
+
+	srv.httpServer = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+	return srv
+}
+
+// URL returns the http URL of the JSON-RPC endpoint.
+func (srv *Server) URL() string {
+	return srv.httpServer.URL
+}
+
+// WSURL returns the websocket URL of the PubSub endpoint.
+func (srv *Server) WSURL() string {
+	return "ws" + strings.TrimPrefix(srv.httpServer.URL, "http")
+}
+
+// Close stops the server and closes all the websocket connections.
+func (srv *Server) Close() {
+	srv.StopAutoAdvance()
+	srv.pubsub.closeAll()
+	srv.httpServer.Close()
+}
+
+type fault struct {
+	err       *jsonrpc.RPCError
+	remaining int // <= 0 means forever
+}
+
+// InjectError makes the next `times` calls to the provided method fail with the provided error.
+// If times is <= 0, all the calls fail until ClearErrors is called.
+// Use method "*" to make any method fail.
+func (srv *Server) InjectError(method string, err *jsonrpc.RPCError, times int) {
+	srv.faultsMu.Lock()
+	defer srv.faultsMu.Unlock()
+	srv.faults[method] = append(srv.faults[method], &fault{err: err, remaining: times})
+}
+
+// ClearErrors removes all the injected errors.
+func (srv *Server) ClearErrors() {
+	srv.faultsMu.Lock()
+	defer srv.faultsMu.Unlock()
+	srv.faults = make(map[string][]*fault)
+}
+
+// SetLatency delays the responses to the provided method by d.
+// Use method "*" to delay all the methods.
+// A zero duration removes the latency.
+func (srv *Server) SetLatency(method string, d time.Duration) {
+	srv.faultsMu.Lock()
+	defer srv.faultsMu.Unlock()
+	if d <= 0 {
+		delete(srv.latency, method)
+		return
+	}
+	srv.latency[method] = d
+}
+
+func (srv *Server) takeFault(method string) *jsonrpc.RPCError {
+	srv.faultsMu.Lock()
+	defer srv.faultsMu.Unlock()
+	for _, key := range []string{method, "*"} {
+		queue := srv.faults[key]
+		if len(queue) == 0 {
+			continue
+		}
+		f := queue[0]
+		if f.remaining > 0 {
+			f.remaining--
+			if f.remaining == 0 {
+				srv.faults[key] = queue[1:]
+			}
+		}
+		return f.err
+	}
+	return nil
+}
+
+func (srv *Server) getLatency(method string) time.Duration {
+	srv.faultsMu.Lock()
+	defer srv.faultsMu.Unlock()
+	if d, ok := srv.latency[method]; ok {
+		return d
+	}
+	return srv.latency["*"]
+}
+
+type request struct {
+	JSONRPC string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  stdjson.RawMessage `json:"params,omitempty"`
+	ID      stdjson.RawMessage `json:"id"`
+}
+
+type response struct {
+	JSONRPC string             `json:"jsonrpc"`
+	Result  interface{}        `json:"result,omitempty"`
+	Error   *jsonrpc.RPCError  `json:"error,omitempty"`
+	ID      stdjson.RawMessage `json:"id"`
+}
+
+func (srv *Server) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	if websocket.IsWebSocketUpgrade(req) {
+		srv.pubsub.serveWS(rw, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	var out interface{}
+	if len(body) > 0 && body[0] == '[' {
+		var requests []*request
+		if err := json.Unmarshal(body, &requests); err != nil {
+			out = &response{JSONRPC: "2.0", Error: errParse(err), ID: stdjson.RawMessage(`null`)}
+		} else {
+			responses := make([]*response, len(requests))
+			for i, r := range requests {
+				responses[i] = srv.handle(req.Context(), r)
+			}
+			out = responses
+		}
+	} else {
+		var r request
+		if err := json.Unmarshal(body, &r); err != nil {
+			out = &response{JSONRPC: "2.0", Error: errParse(err), ID: stdjson.RawMessage(`null`)}
+		} else {
+			out = srv.handle(req.Context(), &r)
+		}
+	}
+
+	buf, err := json.Marshal(out)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(buf)
+}
+
+func (srv *Server) handle(ctx context.Context, req *request) *response {
+	resp := &response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+	}
+	if len(resp.ID) == 0 {
+		resp.ID = stdjson.RawMessage(`null`)
+	}
+
+	if d := srv.getLatency(req.Method); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			resp.Error = &jsonrpc.RPCError{Code: codeInternalError, Message: ctx.Err().Error()}
+			return resp
+		}
+	}
+	if rpcErr := srv.takeFault(req.Method); rpcErr != nil {
+		resp.Error = rpcErr
+		return resp
+	}
+
+	handler, ok := methods[req.Method]
+	if !ok {
+		resp.Error = &jsonrpc.RPCError{Code: codeMethodNotFound, Message: "Method not found"}
+		return resp
+	}
+	var p params
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			resp.Error = errInvalidParams("params must be an array: %s", err)
+			return resp
+		}
+	}
+	result, rpcErr := handler(srv, p)
+	if rpcErr != nil {
+		resp.Error = rpcErr
+		return resp
+	}
+	if result == nil {
+		resp.Result = stdjson.RawMessage(`null`)
+	} else {
+		resp.Result = result
+	}
+	return resp
+}
@@ -0,0 +1,415 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpctest
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/ledger"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	confirm "github.com/gagliardetto/solana-go/rpc/sendAndConfirmTransaction"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+func newTransfer(t *testing.T, client *rpc.Client, from solana.PrivateKey, to solana.PublicKey, lamports uint64) *solana.Transaction {
+	recent, err := client.GetLatestBlockhash(context.Background(), rpc.CommitmentProcessed)
+	require.NoError(t, err)
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			system.NewTransferInstruction(lamports, from.PublicKey(), to).Build(),
+		},
+		recent.Value.Blockhash,
+		solana.TransactionPayer(from.PublicKey()),
+	)
+	require.NoError(t, err)
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(from.PublicKey()) {
+			return &from
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestServer_Accounts(t *testing.T) {
+	srv := NewServer(nil)
+	defer srv.Close()
+	client := rpc.New(srv.URL())
+	ctx := context.Background()
+
+	address := solana.NewWallet().PublicKey()
+	programID := solana.NewWallet().PublicKey()
+	srv.SetAccount(address, &Account{
+		Lamports: 1000,
+		Owner:    programID,
+		Data:     []byte{1, 2, 3, 4},
+	})
+
+	balance, err := client.GetBalance(ctx, address, rpc.CommitmentFinalized)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), balance.Value)
+
+	info, err := client.GetAccountInfo(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, programID, info.Value.Owner)
+	require.Equal(t, []byte{1, 2, 3, 4}, info.Value.Data.GetBinary())
+
+	_, err = client.GetAccountInfo(ctx, solana.NewWallet().PublicKey())
+	require.True(t, errors.Is(err, rpc.ErrNotFound))
+
+	accounts, err := client.GetProgramAccountsWithOpts(ctx, programID, &rpc.GetProgramAccountsOpts{
+		Filters: []rpc.RPCFilter{
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: 1, Bytes: solana.Base58{2, 3}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, address, accounts[0].Pubkey)
+
+	accounts, err = client.GetProgramAccountsWithOpts(ctx, programID, &rpc.GetProgramAccountsOpts{
+		Filters: []rpc.RPCFilter{{DataSize: 5}},
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 0)
+}
+
+func TestServer_SendAndConfirm(t *testing.T) {
+	srv := NewServer(&Options{FinalizationDepth: 3})
+	defer srv.Close()
+	client := rpc.New(srv.URL())
+	ctx := context.Background()
+
+	wsClient, err := ws.Connect(ctx, srv.WSURL())
+	require.NoError(t, err)
+	defer wsClient.Close()
+
+	payer := solana.NewWallet().PrivateKey
+	_, err = client.RequestAirdrop(ctx, payer.PublicKey(), solana.LAMPORTS_PER_SOL, rpc.CommitmentFinalized)
+	require.NoError(t, err)
+
+	srv.StartAutoAdvance(5 * time.Millisecond)
+	defer srv.StopAutoAdvance()
+
+	tx := newTransfer(t, client, payer, solana.NewWallet().PublicKey(), 1)
+	sig, err := confirm.SendAndConfirmTransactionWithTimeout(ctx, client, wsClient, tx, 10*time.Second)
+	require.NoError(t, err)
+
+	statuses, err := client.GetSignatureStatuses(ctx, false, sig)
+	require.NoError(t, err)
+	require.Equal(t, rpc.ConfirmationStatusFinalized, statuses.Value[0].ConfirmationStatus)
+
+	out, err := client.GetTransaction(ctx, sig, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5000), out.Meta.Fee)
+	decoded, err := out.Transaction.GetTransaction()
+	require.NoError(t, err)
+	require.Equal(t, sig, decoded.Signatures[0])
+
+	// The NoopProcessor only charges the fee.
+	balance, err := client.GetBalance(ctx, payer.PublicKey(), rpc.CommitmentProcessed)
+	require.NoError(t, err)
+	require.Equal(t, solana.LAMPORTS_PER_SOL-5000, balance.Value)
+
+	// Replaying the same transaction fails the preflight checks.
+	_, err = client.SendTransaction(ctx, tx)
+	var rpcErr *jsonrpc.RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, codeSendTransactionPreflightFailure, rpcErr.Code)
+}
+
+func TestServer_Processor(t *testing.T) {
+	srv := NewServer(nil)
+	defer srv.Close()
+	client := rpc.New(srv.URL())
+	ctx := context.Background()
+
+	payer := solana.NewWallet().PrivateKey
+	srv.SetBalance(payer.PublicKey(), solana.LAMPORTS_PER_SOL)
+	srv.SetTransactionProcessor(func(tx *solana.Transaction, accounts map[solana.PublicKey]*Account) ([]string, uint64, interface{}) {
+		return []string{"Program log: boom"}, 42, map[string]interface{}{
+			"InstructionError": []interface{}{0, map[string]interface{}{"Custom": 1}},
+		}
+	})
+
+	tx := newTransfer(t, client, payer, solana.NewWallet().PublicKey(), 1)
+	sim, err := client.SimulateTransaction(ctx, tx)
+	require.NoError(t, err)
+	require.NotNil(t, sim.Value.Err)
+	require.Equal(t, []string{"Program log: boom"}, sim.Value.Logs)
+
+	_, err = client.SendTransaction(ctx, tx)
+	var rpcErr *jsonrpc.RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, codeSendTransactionPreflightFailure, rpcErr.Code)
+
+	// Without preflight, the failed transaction lands and pays the fee.
+	sig, err := client.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{SkipPreflight: true})
+	require.NoError(t, err)
+	require.NotNil(t, srv.GetTransaction(sig).Meta.Err)
+	require.Equal(t, solana.LAMPORTS_PER_SOL-5000, srv.GetAccount(payer.PublicKey()).Lamports)
+
+	// Expired blockhash.
+	srv.ExpireBlockhashes()
+	_, err = client.SendTransaction(ctx, newTransferWithBlockhash(t, payer, tx.Message.RecentBlockhash))
+	require.True(t, errors.As(err, &rpcErr))
+	require.Contains(t, rpcErr.Message, "BlockhashNotFound")
+}
+
+func TestServer_LedgerProcessor(t *testing.T) {
+	srv := NewServer(nil)
+	defer srv.Close()
+	client := rpc.New(srv.URL())
+	ctx := context.Background()
+
+	payer := solana.NewWallet().PrivateKey
+	recipient := solana.NewWallet().PublicKey()
+	srv.SetBalance(payer.PublicKey(), solana.LAMPORTS_PER_SOL)
+	srv.SetTransactionProcessor(ledger.Processor)
+
+	sig, err := client.SendTransaction(ctx, newTransfer(t, client, payer, recipient, solana.LAMPORTS_PER_SOL/2))
+	require.NoError(t, err)
+	require.Nil(t, srv.GetTransaction(sig).Meta.Err)
+	require.Equal(t, solana.LAMPORTS_PER_SOL/2, srv.GetAccount(recipient).Lamports)
+
+	// The recipient would not be rent-exempt.
+	_, err = client.SendTransaction(ctx, newTransfer(t, client, payer, solana.NewWallet().PublicKey(), 1))
+	var rpcErr *jsonrpc.RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Contains(t, rpcErr.Message, "InsufficientFundsForRent")
+}
+
+func newTransferWithBlockhash(t *testing.T, from solana.PrivateKey, blockhash solana.Hash) *solana.Transaction {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			system.NewTransferInstruction(2, from.PublicKey(), solana.NewWallet().PublicKey()).Build(),
+		},
+		blockhash,
+		solana.TransactionPayer(from.PublicKey()),
+	)
+	require.NoError(t, err)
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		return &from
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestServer_Faults(t *testing.T) {
+	srv := NewServer(nil)
+	defer srv.Close()
+	client := rpc.New(srv.URL())
+	ctx := context.Background()
+
+	srv.InjectError("getSlot", &jsonrpc.RPCError{Code: -32005, Message: "Node is unhealthy"}, 1)
+	_, err := client.GetSlot(ctx, rpc.CommitmentFinalized)
+	var rpcErr *jsonrpc.RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, -32005, rpcErr.Code)
+
+	_, err = client.GetSlot(ctx, rpc.CommitmentFinalized)
+	require.NoError(t, err)
+
+	srv.SetLatency("*", 50*time.Millisecond)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.GetSlot(timeoutCtx, rpc.CommitmentFinalized)
+	require.Error(t, err)
+}
+
+func TestServer_SlotSubscribe(t *testing.T) {
+	srv := NewServer(nil)
+	defer srv.Close()
+	ctx := context.Background()
+
+	wsClient, err := ws.Connect(ctx, srv.WSURL())
+	require.NoError(t, err)
+	defer wsClient.Close()
+
+	sub, err := wsClient.SlotSubscribe()
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	// Wait for the subscription to be registered.
+	require.Eventually(t, func() bool {
+		srv.AdvanceSlot()
+		recvCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		got, err := sub.Recv(recvCtx)
+		return err == nil && got.Slot == srv.Slot()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestServer_Methods checks that all the JSON-RPC methods called by rpc.Client are served.
+func TestServer_Methods(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "..", nil, 0)
+	require.NoError(t, err)
+
+	called := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || len(call.Args) < 3 {
+					return true
+				}
+				// Only cl.rpcClient.CallForInto(ctx, &out, "method", params):
+				// the DAS methods are served by a separate API.
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "CallForInto" {
+					return true
+				}
+				if lit, ok := call.Args[2].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					method, err := strconv.Unquote(lit.Value)
+					require.NoError(t, err)
+					called[method] = true
+				}
+				return true
+			})
+		}
+	}
+	require.NotEmpty(t, called)
+	for method := range called {
+		require.Contains(t, methods, method, "method %q of rpc.Client is not served", method)
+	}
+}
+
+func TestServer_Cluster(t *testing.T) {
+	srv := NewServer(&Options{SlotsPerEpoch: 100})
+	defer srv.Close()
+	client := rpc.New(srv.URL())
+	ctx := context.Background()
+
+	address := solana.NewWallet().PublicKey()
+	srv.SetBalance(address, 1000)
+	srv.SetBalance(solana.NewWallet().PublicKey(), 10)
+	srv.AdvanceSlots(40)
+
+	supply, err := client.GetSupply(ctx, rpc.CommitmentFinalized)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1010), supply.Value.Total)
+	require.Equal(t, uint64(1010), supply.Value.Circulating)
+
+	largest, err := client.GetLargestAccounts(ctx, rpc.CommitmentFinalized, rpc.LargestAccountsFilterCirculating)
+	require.NoError(t, err)
+	require.Len(t, largest.Value, 2)
+	require.Equal(t, address, largest.Value[0].Address)
+
+	production, err := client.GetBlockProduction(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), production.Value.Range.FirstSlot)
+	require.Equal(t, production.Value.Range.LastSlot, production.Context.Slot)
+	require.Contains(t, production.Value.ByIdentity, srv.opts.Identity)
+
+	commitment, err := client.GetBlockCommitment(ctx, srv.Slot())
+	require.NoError(t, err)
+	require.Len(t, commitment.Commitment, 32)
+	commitment, err = client.GetBlockCommitment(ctx, 1)
+	require.NoError(t, err)
+	require.Nil(t, commitment.Commitment)
+
+	schedule, err := client.GetLeaderSchedule(ctx)
+	require.NoError(t, err)
+	require.Len(t, schedule[srv.opts.Identity], 100)
+
+	snapshot, err := client.GetHighestSnapshotSlot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, srv.Slot()-32, snapshot.Full)
+
+	governor, err := client.GetInflationGovernor(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 0.08, governor.Initial)
+	_, err = client.GetInflationRate(ctx)
+	require.NoError(t, err)
+	rewards, err := client.GetInflationReward(ctx, []solana.PublicKey{address}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*rpc.GetInflationRewardResult{nil}, rewards)
+
+	samples, err := client.GetRecentPerformanceSamples(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, samples)
+
+	feeGovernor, err := client.GetFeeRateGovernor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5000), feeGovernor.Value.FeeRateGovernor.TargetLamportsPerSignature)
+	fees, err := client.GetFees(ctx, rpc.CommitmentProcessed)
+	require.NoError(t, err)
+	require.Equal(t, srv.LatestBlockhash(), fees.Value.Blockhash)
+	calculator, err := client.GetFeeCalculatorForBlockhash(ctx, fees.Value.Blockhash, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5000), calculator.Value.FeeCalculator.LamportsPerSignature)
+}
+
+func stakeAccountData(t *testing.T, activationEpoch uint64) []byte {
+	authority := solana.NewWallet().PublicKey()
+	custodian := solana.PublicKey{}
+	unixTimestamp := int64(0)
+	epoch := uint64(0)
+	state := stake.StakeState{
+		Type: stake.StakeStateStake,
+		Meta: &stake.Meta{
+			RentExemptReserve: 100,
+			Authorized:        stake.Authorized{Staker: &authority, Withdrawer: &authority},
+			Lockup:            stake.Lockup{UnixTimestamp: &unixTimestamp, Epoch: &epoch, Custodian: &custodian},
+		},
+		Stake: &stake.Stake{
+			Delegation: stake.Delegation{
+				Stake:             1000,
+				ActivationEpoch:   activationEpoch,
+				DeactivationEpoch: math.MaxUint64,
+			},
+		},
+	}
+	data, err := bin.MarshalBin(state)
+	require.NoError(t, err)
+	return append(data, 0, 0, 0)
+}
+
+func TestServer_StakeActivation(t *testing.T) {
+	srv := NewServer(&Options{SlotsPerEpoch: 10, StartSlot: 25})
+	defer srv.Close()
+	client := rpc.New(srv.URL())
+	ctx := context.Background()
+
+	address := solana.NewWallet().PublicKey()
+	srv.SetAccount(address, &Account{Lamports: 1150, Owner: solana.StakeProgramID, Data: stakeAccountData(t, 1)})
+	activation, err := client.GetStakeActivation(ctx, address, rpc.CommitmentProcessed, nil)
+	require.NoError(t, err)
+	require.Equal(t, &rpc.GetStakeActivationResult{State: rpc.ActivationStateActive, Active: 1000, Inactive: 50}, activation)
+
+	srv.SetAccount(address, &Account{Lamports: 1150, Owner: solana.StakeProgramID, Data: stakeAccountData(t, 2)})
+	activation, err = client.GetStakeActivation(ctx, address, rpc.CommitmentProcessed, nil)
+	require.NoError(t, err)
+	require.Equal(t, &rpc.GetStakeActivationResult{State: rpc.ActivationStateActivating, Inactive: 1050}, activation)
+
+	_, err = client.GetStakeActivation(ctx, solana.NewWallet().PublicKey(), rpc.CommitmentProcessed, nil)
+	require.Error(t, err)
+}
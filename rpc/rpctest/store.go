@@ -0,0 +1,553 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpctest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/ledger"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Account is an account held by the Server.
+type Account = ledger.Account
+
+// Transaction is a transaction processed by the Server.
+type Transaction struct {
+	Slot        uint64
+	BlockTime   solana.UnixTimeSeconds
+	Transaction *solana.Transaction
+	// Raw is the wire encoding of the transaction,
+	// with its address table lookups unresolved.
+	Raw  []byte
+	Meta *rpc.TransactionMeta
+}
+
+type block struct {
+	slot              uint64
+	parentSlot        uint64
+	blockHeight       uint64
+	blockhash         solana.Hash
+	previousBlockhash solana.Hash
+	blockTime         solana.UnixTimeSeconds
+	signatures        []solana.Signature
+}
+
+// TransactionProcessor executes the instructions of a transaction
+// against the provided accounts; accounts holds a copy of all the accounts
+// referenced by the transaction (missing accounts are nil),
+// and the processor can modify, add or remove (set to nil) entries in it.
+// The fee has already been charged to the fee payer.
+//
+// If txErr is not nil the transaction is considered failed
+// and the account changes are discarded (except for the fee);
+// txErr is reported as-is in the transaction status (e.g. map[string]interface{}{"InstructionError": ...}).
+//
+// ledger.Processor executes the System, SPL Token, Associated Token Account,
+// Compute Budget and Memo instructions natively.
+type TransactionProcessor func(
+	tx *solana.Transaction,
+	accounts map[solana.PublicKey]*Account,
+) (logs []string, unitsConsumed uint64, txErr interface{})
+
+// NoopProcessor is the default TransactionProcessor:
+// it succeeds without changing any account, logging the invocation of each instruction.
+func NoopProcessor(
+	tx *solana.Transaction,
+	accounts map[solana.PublicKey]*Account,
+) (logs []string, unitsConsumed uint64, txErr interface{}) {
+	for _, inst := range tx.Message.Instructions {
+		programID, err := tx.Message.Program(inst.ProgramIDIndex)
+		if err != nil {
+			return logs, 0, err.Error()
+		}
+		logs = append(logs,
+			fmt.Sprintf("Program %s invoke [1]", programID),
+			fmt.Sprintf("Program %s success", programID),
+		)
+	}
+	return logs, 0, nil
+}
+
+// SetTransactionProcessor sets the function used to execute
+// the transactions sent, simulated or airdropped to the server.
+func (srv *Server) SetTransactionProcessor(processor TransactionProcessor) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if processor == nil {
+		processor = NoopProcessor
+	}
+	srv.processor = processor
+}
+
+// SetAccount creates or replaces the account at the provided address.
+// Account and program subscribers are notified.
+func (srv *Server) SetAccount(address solana.PublicKey, account *Account) {
+	srv.mu.Lock()
+	if account == nil {
+		delete(srv.accounts, address)
+	} else {
+		srv.accounts[address] = account.Clone()
+	}
+	srv.mu.Unlock()
+	srv.pubsub.notifyAccounts([]solana.PublicKey{address})
+}
+
+// SetBalance sets the lamports of the account at the provided address,
+// creating a system-owned account if it doesn't exist.
+func (srv *Server) SetBalance(address solana.PublicKey, lamports uint64) {
+	srv.mu.Lock()
+	acc, ok := srv.accounts[address]
+	if !ok {
+		acc = &Account{Owner: solana.SystemProgramID}
+		srv.accounts[address] = acc
+	}
+	acc.Lamports = lamports
+	srv.mu.Unlock()
+	srv.pubsub.notifyAccounts([]solana.PublicKey{address})
+}
+
+// DeleteAccount removes the account at the provided address.
+func (srv *Server) DeleteAccount(address solana.PublicKey) {
+	srv.SetAccount(address, nil)
+}
+
+// GetAccount returns a copy of the account at the provided address, or nil.
+func (srv *Server) GetAccount(address solana.PublicKey) *Account {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.accounts[address].Clone()
+}
+
+// GetTransaction returns the transaction with the provided signature, or nil.
+func (srv *Server) GetTransaction(signature solana.Signature) *Transaction {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.transactions[signature]
+}
+
+// AddTransaction stores an already executed transaction with the provided meta
+// in the block of the current slot, without executing it or touching any account.
+// Use it to seed the transaction history.
+func (srv *Server) AddTransaction(tx *solana.Transaction, meta *rpc.TransactionMeta) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if len(tx.Signatures) == 0 {
+		return fmt.Errorf("transaction has no signatures")
+	}
+	if meta == nil {
+		meta = &rpc.TransactionMeta{}
+	}
+	srv.mu.Lock()
+	srv.storeTransaction(tx, raw, meta)
+	srv.mu.Unlock()
+	srv.pubsub.notifyLogs(tx, meta)
+	srv.pubsub.notifySignatures()
+	return nil
+}
+
+// Slot returns the current (processed) slot.
+func (srv *Server) Slot() uint64 {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.slot
+}
+
+// LatestBlockhash returns the blockhash of the current slot.
+func (srv *Server) LatestBlockhash() solana.Hash {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.blocks[srv.slot].blockhash
+}
+
+// ExpireBlockhashes invalidates all the blockhashes issued so far,
+// as if BlockhashValidity blocks had elapsed.
+func (srv *Server) ExpireBlockhashes() {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.blockhashes = make(map[solana.Hash]uint64)
+}
+
+// AdvanceSlot moves the server to the next slot,
+// producing a new block and blockhash and notifying the subscribers.
+func (srv *Server) AdvanceSlot() {
+	srv.AdvanceSlots(1)
+}
+
+// AdvanceSlots calls AdvanceSlot n times.
+func (srv *Server) AdvanceSlots(n int) {
+	for i := 0; i < n; i++ {
+		srv.mu.Lock()
+		parent := srv.blocks[srv.slot]
+		srv.slot++
+		srv.blockHeight++
+		srv.newBlock(parent)
+		slot, root := srv.slot, srv.slotAt(rpc.CommitmentFinalized)
+		srv.mu.Unlock()
+
+		srv.pubsub.notifySlot(parent.slot, slot, root)
+		srv.pubsub.notifyBlocks()
+		srv.pubsub.notifySignatures()
+	}
+}
+
+// StartAutoAdvance advances the slot every interval until StopAutoAdvance is called.
+func (srv *Server) StartAutoAdvance(interval time.Duration) {
+	srv.StopAutoAdvance()
+
+	srv.autoAdvanceMu.Lock()
+	defer srv.autoAdvanceMu.Unlock()
+	stop := make(chan struct{})
+	srv.autoAdvanceStop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				srv.AdvanceSlot()
+			}
+		}
+	}()
+}
+
+// StopAutoAdvance stops the goroutine started by StartAutoAdvance.
+func (srv *Server) StopAutoAdvance() {
+	srv.autoAdvanceMu.Lock()
+	defer srv.autoAdvanceMu.Unlock()
+	if srv.autoAdvanceStop != nil {
+		close(srv.autoAdvanceStop)
+		srv.autoAdvanceStop = nil
+	}
+}
+
+// newBlock creates the block for the current slot, as a child of parent.
+// Must be called with mu held.
+func (srv *Server) newBlock(parent *block) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, srv.slot)
+	sum := sha256.Sum256(append(srv.genesisHash[:], buf...))
+
+	blk := &block{
+		slot:        srv.slot,
+		blockHeight: srv.blockHeight,
+		blockhash:   solana.HashFromBytes(sum[:]),
+		blockTime:   solana.UnixTimeSeconds(time.Now().Unix()),
+	}
+	if parent != nil {
+		blk.parentSlot = parent.slot
+		blk.previousBlockhash = parent.blockhash
+	}
+	srv.blocks[blk.slot] = blk
+	srv.blockhashes[blk.blockhash] = blk.blockHeight + srv.opts.BlockhashValidity
+}
+
+// blockAt returns the highest block at or below the provided slot.
+// Must be called with mu held.
+func (srv *Server) blockAt(slot uint64) *block {
+	for {
+		if blk, ok := srv.blocks[slot]; ok {
+			return blk
+		}
+		if slot == 0 {
+			return nil
+		}
+		slot--
+	}
+}
+
+// slotAt returns the highest slot that has reached the provided commitment.
+// Must be called with mu held.
+func (srv *Server) slotAt(commitment rpc.CommitmentType) uint64 {
+	var depth uint64
+	switch commitment {
+	case rpc.CommitmentProcessed, rpc.CommitmentRecent:
+		depth = 0
+	case rpc.CommitmentConfirmed, rpc.CommitmentSingle, rpc.CommitmentSingleGossip:
+		depth = srv.opts.ConfirmationDepth
+	default:
+		depth = srv.opts.FinalizationDepth
+	}
+	if depth >= srv.slot {
+		return 0
+	}
+	return srv.slot - depth
+}
+
+// blockHeightAt is like slotAt, but for block heights.
+// Must be called with mu held.
+func (srv *Server) blockHeightAt(commitment rpc.CommitmentType) uint64 {
+	if blk := srv.blockAt(srv.slotAt(commitment)); blk != nil {
+		return blk.blockHeight
+	}
+	return 0
+}
+
+func (srv *Server) isBlockhashValid(hash solana.Hash) bool {
+	lastValid, ok := srv.blockhashes[hash]
+	return ok && lastValid >= srv.blockHeight
+}
+
+// resolveLookups loads the addresses referenced by the address table lookups
+// of a v0 transaction from the lookup table accounts held by the server.
+// Must be called with mu held.
+func (srv *Server) resolveLookups(tx *solana.Transaction) (*rpc.LoadedAddresses, error) {
+	loaded := &rpc.LoadedAddresses{
+		Writable: solana.PublicKeySlice{},
+		ReadOnly: solana.PublicKeySlice{},
+	}
+	if !tx.Message.IsVersioned() || tx.Message.AddressTableLookups.NumLookups() == 0 {
+		return loaded, nil
+	}
+	tables := make(map[solana.PublicKey]solana.PublicKeySlice)
+	for _, lookup := range tx.Message.AddressTableLookups {
+		acc, ok := srv.accounts[lookup.AccountKey]
+		if !ok {
+			return nil, fmt.Errorf("address lookup table %s not found", lookup.AccountKey)
+		}
+		state, err := addresslookuptable.DecodeAddressLookupTableState(acc.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address lookup table %s: %w", lookup.AccountKey, err)
+		}
+		tables[lookup.AccountKey] = state.Addresses
+		for _, idx := range lookup.WritableIndexes {
+			if int(idx) >= len(state.Addresses) {
+				return nil, fmt.Errorf("invalid index %d for address lookup table %s", idx, lookup.AccountKey)
+			}
+			loaded.Writable = append(loaded.Writable, state.Addresses[idx])
+		}
+		for _, idx := range lookup.ReadonlyIndexes {
+			if int(idx) >= len(state.Addresses) {
+				return nil, fmt.Errorf("invalid index %d for address lookup table %s", idx, lookup.AccountKey)
+			}
+			loaded.ReadOnly = append(loaded.ReadOnly, state.Addresses[idx])
+		}
+	}
+	if err := tx.Message.SetAddressTables(tables); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
+
+// execution is the outcome of running a transaction against the accounts.
+type execution struct {
+	meta     *rpc.TransactionMeta
+	accounts map[solana.PublicKey]*Account // post-execution state of the tx accounts
+	keys     solana.PublicKeySlice
+}
+
+// execute runs the transaction against a copy of its accounts.
+// Nothing is committed. Must be called with mu held.
+func (srv *Server) execute(tx *solana.Transaction) (*execution, error) {
+	loaded, err := srv.resolveLookups(tx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := tx.Message.GetAllKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("transaction has no accounts")
+	}
+
+	accounts := make(map[solana.PublicKey]*Account, len(keys))
+	pre := make([]uint64, len(keys))
+	for i, key := range keys {
+		acc := srv.accounts[key].Clone()
+		accounts[key] = acc
+		if acc != nil {
+			pre[i] = acc.Lamports
+		}
+	}
+
+	fee := srv.opts.LamportsPerSignature * uint64(tx.Message.Header.NumRequiredSignatures)
+	payer := accounts[keys[0]]
+	if payer == nil {
+		return nil, errAccountNotFound
+	}
+	if payer.Lamports < fee {
+		return nil, errInsufficientFundsForFee
+	}
+	payer.Lamports -= fee
+
+	working := make(map[solana.PublicKey]*Account, len(accounts))
+	for key, acc := range accounts {
+		working[key] = acc.Clone()
+	}
+	logs, units, txErr := srv.processor(tx, working)
+	if txErr == nil {
+		accounts = working
+	}
+
+	post := make([]uint64, len(keys))
+	for i, key := range keys {
+		if acc := accounts[key]; acc != nil {
+			post[i] = acc.Lamports
+		}
+	}
+
+	meta := &rpc.TransactionMeta{
+		Err:               txErr,
+		Fee:               fee,
+		PreBalances:       pre,
+		PostBalances:      post,
+		InnerInstructions: []rpc.InnerInstruction{},
+		PreTokenBalances:  []rpc.TokenBalance{},
+		PostTokenBalances: []rpc.TokenBalance{},
+		LogMessages:       logs,
+		Rewards:           []rpc.BlockReward{},
+		LoadedAddresses:   *loaded,
+		ComputeUnitsConsumed: func() *uint64 {
+			v := units
+			return &v
+		}(),
+	}
+	if txErr == nil {
+		meta.Status = rpc.DeprecatedTransactionMetaStatus{"Ok": nil}
+	} else {
+		meta.Status = rpc.DeprecatedTransactionMetaStatus{"Err": txErr}
+	}
+	return &execution{
+		meta:     meta,
+		accounts: accounts,
+		keys:     keys,
+	}, nil
+}
+
+// commit applies the result of an execution. Must be called with mu held.
+func (srv *Server) commit(exec *execution) []solana.PublicKey {
+	changed := make([]solana.PublicKey, 0)
+	for i, key := range exec.keys {
+		acc := exec.accounts[key]
+		if acc == nil || (acc.Lamports == 0 && i > 0) {
+			// Accounts left without lamports are garbage-collected.
+			if _, ok := srv.accounts[key]; ok {
+				delete(srv.accounts, key)
+				changed = append(changed, key)
+			}
+			continue
+		}
+		srv.accounts[key] = acc
+		changed = append(changed, key)
+	}
+	return changed
+}
+
+// storeTransaction adds the transaction to the current block. Must be called with mu held.
+func (srv *Server) storeTransaction(tx *solana.Transaction, raw []byte, meta *rpc.TransactionMeta) {
+	sig := tx.Signatures[0]
+	blk := srv.blocks[srv.slot]
+	srv.transactions[sig] = &Transaction{
+		Slot:        srv.slot,
+		BlockTime:   blk.blockTime,
+		Transaction: tx,
+		Raw:         raw,
+		Meta:        meta,
+	}
+	blk.signatures = append(blk.signatures, sig)
+	srv.transactionCount++
+
+	mentioned := make(solana.PublicKeySlice, 0)
+	keys, err := tx.Message.GetAllKeys()
+	if err != nil {
+		keys = tx.Message.AccountKeys
+	}
+	for _, key := range keys {
+		mentioned.UniqueAppend(key)
+	}
+	for _, key := range mentioned {
+		srv.signaturesByAddress[key] = append(srv.signaturesByAddress[key], sig)
+	}
+}
+
+// Airdrop credits lamports to the provided address
+// and records a transfer transaction from the faucet.
+func (srv *Server) Airdrop(address solana.PublicKey, lamports uint64) (solana.Signature, error) {
+	faucet := srv.faucet.PublicKey()
+
+	srv.mu.Lock()
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			system.NewTransferInstruction(lamports, faucet, address).Build(),
+		},
+		srv.blocks[srv.slot].blockhash,
+		solana.TransactionPayer(faucet),
+	)
+	if err != nil {
+		srv.mu.Unlock()
+		return solana.Signature{}, err
+	}
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(faucet) {
+			return &srv.faucet
+		}
+		return nil
+	})
+	if err != nil {
+		srv.mu.Unlock()
+		return solana.Signature{}, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		srv.mu.Unlock()
+		return solana.Signature{}, err
+	}
+
+	acc, ok := srv.accounts[address]
+	if !ok {
+		acc = &Account{Owner: solana.SystemProgramID}
+		srv.accounts[address] = acc
+	}
+	pre := acc.Lamports
+	acc.Lamports += lamports
+
+	unitsConsumed := uint64(150)
+	meta := &rpc.TransactionMeta{
+		PreBalances:       []uint64{lamports, pre, 1},
+		PostBalances:      []uint64{0, acc.Lamports, 1},
+		InnerInstructions: []rpc.InnerInstruction{},
+		PreTokenBalances:  []rpc.TokenBalance{},
+		PostTokenBalances: []rpc.TokenBalance{},
+		LogMessages: []string{
+			fmt.Sprintf("Program %s invoke [1]", solana.SystemProgramID),
+			fmt.Sprintf("Program %s success", solana.SystemProgramID),
+		},
+		Status:  rpc.DeprecatedTransactionMetaStatus{"Ok": nil},
+		Rewards: []rpc.BlockReward{},
+		LoadedAddresses: rpc.LoadedAddresses{
+			Writable: solana.PublicKeySlice{},
+			ReadOnly: solana.PublicKeySlice{},
+		},
+		ComputeUnitsConsumed: &unitsConsumed,
+	}
+	srv.storeTransaction(tx, raw, meta)
+	srv.mu.Unlock()
+
+	srv.pubsub.notifyAccounts([]solana.PublicKey{address})
+	srv.pubsub.notifyLogs(tx, meta)
+	srv.pubsub.notifySignatures()
+	return tx.Signatures[0], nil
+}
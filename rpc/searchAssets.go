@@ -0,0 +1,84 @@
+// Copyright 2021 github.com/gagliardetto
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// SearchAssetsParams are the criteria of SearchAssets; the unset ones are
+// ignored.
+type SearchAssetsParams struct {
+	// "all" (the default) to match all the criteria, "any" to match any.
+	ConditionType string `json:"conditionType,omitempty"`
+	// Return the assets that don't match the criteria.
+	Negate bool `json:"negate,omitempty"`
+
+	Interface        AssetInterface    `json:"interface,omitempty"`
+	OwnerAddress     *solana.PublicKey `json:"ownerAddress,omitempty"`
+	OwnerType        string            `json:"ownerType,omitempty"`
+	CreatorAddress   *solana.PublicKey `json:"creatorAddress,omitempty"`
+	CreatorVerified  *bool             `json:"creatorVerified,omitempty"`
+	AuthorityAddress *solana.PublicKey `json:"authorityAddress,omitempty"`
+	// The group key and value, like ["collection", collection].
+	Grouping          []string          `json:"grouping,omitempty"`
+	Delegate          *solana.PublicKey `json:"delegate,omitempty"`
+	Frozen            *bool             `json:"frozen,omitempty"`
+	Supply            *uint64           `json:"supply,omitempty"`
+	SupplyMint        *solana.PublicKey `json:"supplyMint,omitempty"`
+	Compressed        *bool             `json:"compressed,omitempty"`
+	Compressible      *bool             `json:"compressible,omitempty"`
+	RoyaltyTargetType string            `json:"royaltyTargetType,omitempty"`
+	RoyaltyTarget     *solana.PublicKey `json:"royaltyTarget,omitempty"`
+	RoyaltyAmount     *uint64           `json:"royaltyAmount,omitempty"`
+	Burnt             *bool             `json:"burnt,omitempty"`
+	JSONURI           string            `json:"jsonUri,omitempty"`
+
+	DASListOpts
+}
+
+// SearchAssets returns a page of the assets matching the criteria.
+//
+// This is a DAS method, served by the providers that index the assets.
+func (cl *Client) SearchAssets(
+	ctx context.Context,
+	params *SearchAssetsParams,
+) (out *AssetList, err error) {
+	if params == nil {
+		params = &SearchAssetsParams{}
+	}
+	err = cl.callDAS(ctx, &out, "searchAssets", params)
+	return
+}
+
+// SearchAllAssets returns all the assets matching the criteria, fetching
+// all the pages of params.Limit items (or DASMaxLimit) by page or by cursor,
+// from params.Page or params.Cursor if set.
+func (cl *Client) SearchAllAssets(
+	ctx context.Context,
+	mode PaginationMode,
+	params *SearchAssetsParams,
+) (out []*Asset, err error) {
+	pageParams := SearchAssetsParams{}
+	if params != nil {
+		pageParams = *params
+	}
+	return allAssets(ctx, mode, &pageParams.DASListOpts, func(ctx context.Context, opts *DASListOpts) (*AssetList, error) {
+		pageParams.DASListOpts = *opts
+		return cl.SearchAssets(ctx, &pageParams)
+	})
+}
@@ -133,15 +133,54 @@ func (twm TransactionWithMeta) MustGetTransaction() *solana.Transaction {
 	return tx
 }
 
+// GetTransaction decodes the transaction (in a binary or the "json" encoding);
+// the address table lookups of a v0 transaction are resolved with the
+// LoadedAddresses of the meta.
 func (twm TransactionWithMeta) GetTransaction() (*solana.Transaction, error) {
 	tx := new(solana.Transaction)
-	err := tx.UnmarshalWithDecoder(bin.NewBinDecoder(twm.Transaction.GetBinary()))
-	if err != nil {
+	if twm.Transaction != nil && twm.Transaction.asDecodedBinary.Content == nil && twm.Transaction.asJSON != nil {
+		if err := json.Unmarshal(twm.Transaction.asJSON, tx); err != nil {
+			return nil, err
+		}
+	} else {
+		err := tx.UnmarshalWithDecoder(bin.NewBinDecoder(twm.Transaction.GetBinary()))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := resolveLookups(tx, twm.Meta); err != nil {
 		return nil, err
 	}
 	return tx, nil
 }
 
+// resolveLookups resolves the address table lookups of the transaction with
+// the addresses loaded during its execution, as reported by the meta.
+func resolveLookups(tx *solana.Transaction, meta *TransactionMeta) error {
+	message := &tx.Message
+	if meta == nil || message.NumLookups() == 0 || message.IsResolved() {
+		return nil
+	}
+	// The "json" encoding of a message doesn't include its version,
+	// but only v0 messages have lookups.
+	if !message.IsVersioned() {
+		message.SetVersion(solana.MessageVersionV0)
+	}
+	loaded := meta.LoadedAddresses
+	if len(loaded.Writable) != message.NumWritableLookups() ||
+		len(loaded.Writable)+len(loaded.ReadOnly) != message.NumLookups() {
+		return fmt.Errorf(
+			"loaded addresses (%d writable, %d read-only) don't match the lookups (%d writable, %d read-only)",
+			len(loaded.Writable), len(loaded.ReadOnly),
+			message.NumWritableLookups(), message.NumLookups()-message.NumWritableLookups(),
+		)
+	}
+	return message.ResolveLookupsWith(
+		append(solana.PublicKeySlice{}, loaded.Writable...),
+		append(solana.PublicKeySlice{}, loaded.ReadOnly...),
+	)
+}
+
 type TransactionParsed struct {
 	Meta        *TransactionMeta    `json:"meta,omitempty"`
 	Transaction *solana.Transaction `json:"transaction"`
@@ -327,6 +366,24 @@ type Account struct {
 	Space uint64 `json:"space"`
 }
 
+// Decode decodes the account data with the decoder registered
+// for the account owner (see solana.RegisterAccountDecoder).
+// The decoders of the programs under programs/ are registered
+// when their package is imported.
+// The account data must have been requested with a binary encoding.
+func (a *Account) Decode() (interface{}, error) {
+	if a == nil {
+		return nil, fmt.Errorf("account is nil")
+	}
+	if a.Data == nil {
+		return nil, fmt.Errorf("account data is nil")
+	}
+	if a.Data.rawDataEncoding == solana.EncodingJSONParsed || a.Data.rawDataEncoding == solana.EncodingJSON {
+		return nil, fmt.Errorf("account data is not binary: %s", a.Data.rawDataEncoding)
+	}
+	return solana.DecodeAccount(a.Owner, a.Data.GetBinary())
+}
+
 type DataBytesOrJSON struct {
 	rawDataEncoding solana.EncodingType
 	asDecodedBinary solana.Data
@@ -433,6 +490,11 @@ type KeyedAccount struct {
 	Account *Account         `json:"account"`
 }
 
+// Decode decodes the account data; see Account.Decode.
+func (a *KeyedAccount) Decode() (interface{}, error) {
+	return a.Account.Decode()
+}
+
 type GetConfirmedSignaturesForAddress2Opts struct {
 	Limit      *uint64          `json:"limit,omitempty"`
 	Before     solana.Signature `json:"before,omitempty"`
@@ -442,14 +504,21 @@ type GetConfirmedSignaturesForAddress2Opts struct {
 
 type GetConfirmedSignaturesForAddress2Result []*TransactionSignature
 
+// RPCFilter is a filter of getProgramAccounts and programSubscribe;
+// see filters.go for the constructors.
 type RPCFilter struct {
 	Memcmp   *RPCFilterMemcmp `json:"memcmp,omitempty"`
 	DataSize uint64           `json:"dataSize,omitempty"`
+	// If true, only the valid and initialized token accounts match
+	// (the filter is serialized as "tokenAccountState").
+	TokenAccountState bool `json:"-"`
 }
 
 type RPCFilterMemcmp struct {
 	Offset uint64        `json:"offset"`
 	Bytes  solana.Base58 `json:"bytes"`
+	// Encoding of Bytes on the wire: base58 (the default) or base64.
+	Encoding solana.EncodingType `json:"encoding,omitempty"`
 }
 
 type CommitmentType string
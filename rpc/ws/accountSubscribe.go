@@ -28,6 +28,11 @@ type AccountResult struct {
 	Value *rpc.Account `json:"value"`
 }
 
+// Decode decodes the account data; see rpc.Account.Decode.
+func (r *AccountResult) Decode() (interface{}, error) {
+	return r.Value.Decode()
+}
+
 // AccountSubscribe subscribes to an account to receive notifications
 // when the lamports or data for a given account public key changes.
 func (cl *Client) AccountSubscribe(
@@ -28,6 +28,11 @@ type ProgramResult struct {
 	Value rpc.KeyedAccount `json:"value"`
 }
 
+// Decode decodes the account data; see rpc.Account.Decode.
+func (r *ProgramResult) Decode() (interface{}, error) {
+	return r.Value.Decode()
+}
+
 // ProgramSubscribe subscribes to a program to receive notifications
 // when the lamports or data for a given account owned by the program changes.
 func (cl *Client) ProgramSubscribe(
@@ -80,28 +85,52 @@ func (cl *Client) ProgramSubscribeWithOpts(
 		return nil, err
 	}
 	return &ProgramSubscription{
-		sub: genSub,
+		sub:     genSub,
+		filters: filters,
 	}, nil
 }
 
 type ProgramSubscription struct {
-	sub *Subscription
+	sub     *Subscription
+	filters rpc.RPCFilters
 }
 
+// Recv waits for the next notification.
+// The notifications whose account data doesn't match the filters
+// of the subscription are skipped.
 func (sw *ProgramSubscription) Recv(ctx context.Context) (*ProgramResult, error) {
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case d, ok := <-sw.sub.stream:
-		if !ok {
-			return nil, ErrSubscriptionClosed
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case d, ok := <-sw.sub.stream:
+			if !ok {
+				return nil, ErrSubscriptionClosed
+			}
+			res := d.(*ProgramResult)
+			if !sw.match(res) {
+				continue
+			}
+			return res, nil
+		case err := <-sw.sub.err:
+			return nil, err
 		}
-		return d.(*ProgramResult), nil
-	case err := <-sw.sub.err:
-		return nil, err
 	}
 }
 
+// match applies the filters to the account data of a notification;
+// notifications with jsonParsed data can't be filtered client-side, and always match.
+func (sw *ProgramSubscription) match(res *ProgramResult) bool {
+	if len(sw.filters) == 0 || res.Value.Account == nil || res.Value.Account.Data == nil {
+		return true
+	}
+	data := res.Value.Account.Data.GetBinary()
+	if data == nil {
+		return true
+	}
+	return sw.filters.Match(data)
+}
+
 func (sw *ProgramSubscription) Err() <-chan error {
 	return sw.sub.err
 }
@@ -110,11 +139,16 @@ func (sw *ProgramSubscription) Response() <-chan *ProgramResult {
 	typedChan := make(chan *ProgramResult, 1)
 	go func(ch chan *ProgramResult) {
 		// TODO: will this subscription yield more than one result?
-		d, ok := <-sw.sub.stream
-		if !ok {
-			return
+		for {
+			d, ok := <-sw.sub.stream
+			if !ok {
+				return
+			}
+			if res := d.(*ProgramResult); sw.match(res) {
+				ch <- res
+				return
+			}
 		}
-		ch <- d.(*ProgramResult)
 	}(typedChan)
 	return typedChan
 }
@@ -7,11 +7,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestTransactionV0(t *testing.T) {
-	txB64 := "Alkhq/BfGdBeok4oBP21xAwT4oO/R5PvkKqbCTq4sHHRsto+uDQCFcdp8hXh1g5D3mTh8GAJW8xE+EDD27f9IweTkH2Afiu4h5aM+Xbo0mklc0/Vi1xawd7SZVbstXDLtWdoJaf4Zt+20F/SasURzw/P4dkD+Q6BjgUNHT+vg5gOgAIBAQgaJV0Ch/DG6XwNcizWbI7STLgSbIOrg0Dl67Oo30WU1uA/NIbYLPRmuLarIJ4J0CcN3IWEm4Gf8675KhnXef2LaDXzjFgWVSbAO2yyTF6dK1oO3gTExie957LXDwu6oJMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAVKU1qZKSEGTSTocWDaOHx8NbXdvJK7geQfqEBBBUSN1LfoiB9oYLDSHJL9rjAlchZhn+fd/23ACfq0oIGla54pt5JT0MdBTJhQI+z7dnVsisw2xWwW+vFSTs97l0tJPxmv9kxpXbHYZFenDpT2s6CT75/9QNFVTkHFLMK+UG6VlyFnQmYh1aMkGtq3c6TIOsk32S6XMUnN9DQgFGQq4lwEAwIAAgwCAAAAgJaYAAAAAAADAgAFDAIAAACAlpgAAAAAAAMCAAYMAgAAAICWmAAAAAAABAAMSGVsbG8gRmFiaW8hAX5s37FH6IeB4QeMYxD4LtpXf1DaupH/ro7W+kEQnofaAgECAQA="
+// resolveLookupsTxB64 is a v0 transaction with address table lookups.
+const resolveLookupsTxB64 = "Alkhq/BfGdBeok4oBP21xAwT4oO/R5PvkKqbCTq4sHHRsto+uDQCFcdp8hXh1g5D3mTh8GAJW8xE+EDD27f9IweTkH2Afiu4h5aM+Xbo0mklc0/Vi1xawd7SZVbstXDLtWdoJaf4Zt+20F/SasURzw/P4dkD+Q6BjgUNHT+vg5gOgAIBAQgaJV0Ch/DG6XwNcizWbI7STLgSbIOrg0Dl67Oo30WU1uA/NIbYLPRmuLarIJ4J0CcN3IWEm4Gf8675KhnXef2LaDXzjFgWVSbAO2yyTF6dK1oO3gTExie957LXDwu6oJMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAVKU1qZKSEGTSTocWDaOHx8NbXdvJK7geQfqEBBBUSN1LfoiB9oYLDSHJL9rjAlchZhn+fd/23ACfq0oIGla54pt5JT0MdBTJhQI+z7dnVsisw2xWwW+vFSTs97l0tJPxmv9kxpXbHYZFenDpT2s6CT75/9QNFVTkHFLMK+UG6VlyFnQmYh1aMkGtq3c6TIOsk32S6XMUnN9DQgFGQq4lwEAwIAAgwCAAAAgJaYAAAAAAADAgAFDAIAAACAlpgAAAAAAAMCAAYMAgAAAICWmAAAAAAABAAMSGVsbG8gRmFiaW8hAX5s37FH6IeB4QeMYxD4LtpXf1DaupH/ro7W+kEQnofaAgECAQA="
 
+func TestTransactionV0(t *testing.T) {
 	tx := new(Transaction)
-	err := tx.UnmarshalBase64(txB64)
+	err := tx.UnmarshalBase64(resolveLookupsTxB64)
 	require.NoError(t, err)
 
 	require.NotPanics(t, func() {
@@ -115,6 +116,36 @@ func TestTransactionV0(t *testing.T) {
 
 	{
 		encoded := tx.MustToBase64()
-		require.Equal(t, txB64, encoded)
+		require.Equal(t, resolveLookupsTxB64, encoded)
 	}
 }
+
+func TestMessage_ResolveLookupsWith(t *testing.T) {
+	tx := new(Transaction)
+	require.NoError(t, tx.UnmarshalBase64(resolveLookupsTxB64))
+
+	// Without the tables, the lookups cannot be resolved:
+	_, err := tx.Message.AccountMetaList()
+	require.Error(t, err)
+
+	// The addresses loaded by the lookups, as reported by the transaction meta:
+	writable := PublicKeySlice{
+		MPK("FKN5imdi7yadX4axe4hxaqBET4n6DBDRF5LKo5aBF53j"),
+		MPK("3or4uF7ZyuQW5GGmcmdXDJasNiSZUURF2az1UrRPYQTg"),
+	}
+	readonly := PublicKeySlice{
+		MPK("2jGpE3ADYRoJPMjyGC4tvqqDfobvdvwGr3vhd66zA1rc"),
+	}
+	require.NoError(t, tx.Message.ResolveLookupsWith(writable, readonly))
+	require.ErrorIs(t, tx.Message.ResolveLookupsWith(writable, readonly), ErrAlreadyResolved)
+
+	metas, err := tx.Message.AccountMetaList()
+	require.NoError(t, err)
+	require.Len(t, metas, 11)
+	require.Equal(t, Meta(writable[0]).WRITE(), metas[8])
+	require.Equal(t, Meta(readonly[0]), metas[10])
+
+	accounts, err := tx.Message.Instructions[0].ResolveInstructionAccounts(&tx.Message)
+	require.NoError(t, err)
+	require.NotEmpty(t, accounts)
+}